@@ -15,16 +15,22 @@ import (
 	"github.com/link-rift/link-rift/internal/database"
 	"github.com/link-rift/link-rift/internal/handler"
 	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/linkimport"
 	"github.com/link-rift/link-rift/internal/middleware"
 	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/ogimage"
 	"github.com/link-rift/link-rift/internal/qrcode"
 	"github.com/link-rift/link-rift/internal/realtime"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/safety"
 	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
 	"github.com/link-rift/link-rift/pkg/paseto"
 	"github.com/link-rift/link-rift/pkg/storage"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func main() {
@@ -34,6 +40,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 
 	// 2. Init logger
 	var logger *zap.Logger
@@ -48,6 +58,14 @@ func main() {
 	}
 	defer logger.Sync()
 
+	if err := crypto.SetParams(crypto.Params{
+		Memory:      cfg.Auth.PasswordHashMemory,
+		Iterations:  cfg.Auth.PasswordHashIterations,
+		Parallelism: cfg.Auth.PasswordHashParallelism,
+	}); err != nil {
+		logger.Fatal("invalid password hash parameters", zap.Error(err))
+	}
+
 	// 3. Connect PostgreSQL
 	pgDB, err := database.NewPostgres(cfg.Database, logger)
 	if err != nil {
@@ -66,7 +84,7 @@ func main() {
 	queries := sqlc.New(pgDB.Pool())
 
 	// 6. Create PASETO token maker
-	tokenMaker, err := paseto.NewPasetoMaker(cfg.Auth.TokenSecret)
+	tokenMaker, err := paseto.NewPasetoMakerWithKeys(cfg.Auth.TokenSecret, cfg.Auth.PreviousTokenSecrets)
 	if err != nil {
 		logger.Fatal("failed to create token maker", zap.Error(err))
 	}
@@ -87,26 +105,29 @@ func main() {
 			)
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
-			licManager.StartPeriodicCheck(ctx, cfg.License.CheckInterval)
+			licManager.StartPeriodicCheck(ctx, cfg.License.CheckInterval, cfg.License.CheckJitter)
 		}
 	} else {
 		logger.Info("no license key configured, running as community edition")
 	}
 
-	// 8. Connect ClickHouse (optional — analytics)
-	var analyticsRepo repository.AnalyticsRepository
+	// 8. Connect ClickHouse (optional — analytics). PostgreSQL always holds
+	// the clicks too, so each workspace can read from either store.
+	analyticsBackends := service.AnalyticsBackends{
+		Postgres:          repository.NewPGAnalyticsRepository(pgDB.Pool(), logger),
+		ClickHouseDefault: cfg.ClickHouse.WorkspaceDefault,
+	}
 	if cfg.ClickHouse.URL != "" {
 		chDB, err := database.NewClickHouse(cfg.ClickHouse, logger)
 		if err != nil {
 			logger.Warn("ClickHouse unavailable, using PostgreSQL for analytics", zap.Error(err))
-			analyticsRepo = repository.NewPGAnalyticsRepository(pgDB.Pool(), logger)
 		} else {
 			defer chDB.Close()
-			analyticsRepo = repository.NewClickHouseAnalyticsRepository(chDB.Conn(), logger)
+			analyticsBackends.ClickHouse = repository.NewClickHouseAnalyticsRepository(chDB.Conn(), logger)
+			analyticsBackends.Circuit = service.NewAnalyticsCircuit(cfg.ClickHouse.CircuitFailures, cfg.ClickHouse.CircuitCooldown, logger)
 		}
-	} else {
-		analyticsRepo = repository.NewPGAnalyticsRepository(pgDB.Pool(), logger)
 	}
+	analyticsRepo := analyticsBackends.Default(logger)
 
 	// 9. Create repositories
 	userRepo := repository.NewUserRepository(queries, logger)
@@ -120,48 +141,100 @@ func main() {
 	qrCodeRepo := repository.NewQRCodeRepository(queries, logger)
 	bioPageRepo := repository.NewBioPageRepository(queries, logger)
 	apiKeyRepo := repository.NewAPIKeyRepository(queries, logger)
+	analyticsShareRepo := repository.NewAnalyticsShareRepository(queries, logger)
 	webhookRepo := repository.NewWebhookRepository(queries, logger)
+	linkRuleRepo := repository.NewLinkRuleRepository(queries, logger)
+	linkFlagRepo := repository.NewLinkFlagRepository(queries, logger)
+	linkCommentRepo := repository.NewLinkCommentRepository(queries, logger)
+	linkReportRepo := repository.NewLinkReportRepository(queries, logger)
+	usageRepo := repository.NewUsageRepository(queries, logger)
+	scheduledReportRepo := repository.NewScheduledReportRepository(queries, logger)
+	linkTemplateRepo := repository.NewLinkTemplateRepository(queries, logger)
 
 	// 9b. Create storage client (local fallback for development)
 	var objectStore storage.ObjectStorage
+	storageOpts := storage.Options{
+		KeyPrefix:         cfg.Storage.KeyPrefix,
+		WorkspacePrefixes: cfg.Storage.WorkspacePrefixes,
+	}
 	if cfg.S3.Endpoint != "" && cfg.S3.AccessKey != "" {
-		s3Store, err := storage.NewS3Storage(cfg.S3)
+		s3Store, err := storage.NewS3Storage(cfg.S3, storageOpts)
 		if err != nil {
 			logger.Warn("S3 storage unavailable, falling back to local storage", zap.Error(err))
-			objectStore = storage.NewLocalStorage("./data/uploads/", cfg.App.BaseURL+"/uploads/")
+			objectStore = storage.NewLocalStorage("./data/uploads/", cfg.App.BaseURL+"/uploads/", storageOpts)
 		} else {
 			objectStore = s3Store
 		}
 	} else {
-		objectStore = storage.NewLocalStorage("./data/uploads/", cfg.App.BaseURL+"/uploads/")
+		objectStore = storage.NewLocalStorage("./data/uploads/", cfg.App.BaseURL+"/uploads/", storageOpts)
 	}
 
 	// 9c. Create QR code generator
 	qrGenerator := qrcode.NewGenerator(objectStore)
-	qrBatchGenerator := qrcode.NewBatchGenerator(qrGenerator, 4)
+
+	// Server-side fetches of user-supplied URLs (QR logos, link metadata) go through the
+	// SSRF-safe client, which only reaches public addresses.
+	fetchPolicy, _ := httputil.NewHostPolicy(false, nil)
+	fetchConfig := httputil.DefaultSafeClientConfig()
+	fetchConfig.MaxRedirects = cfg.Fetch.MaxRedirects
+	fetchConfig.HopTimeout = cfg.Fetch.HopTimeout
+	safeFetcher := httputil.NewSafeClient(fetchPolicy, fetchConfig)
+	qrGenerator.SetLogoFetcher(safeFetcher)
+	qrBatchGenerator := qrcode.NewBatchGenerator(qrGenerator, cfg.QR.BatchWorkers)
+
+	// Destination screening against malware and phishing sources (nil when off)
+	urlChecker, err := safety.FromConfig(cfg.Safety, logger)
+	if err != nil {
+		logger.Fatal("failed to load destination blocklist", zap.Error(err))
+	}
 
 	// 10. Create event publisher for webhooks
 	eventPublisher := service.NewEventPublisher(redisDB.Client(), logger)
 
 	// Create services
 	authService := service.NewAuthService(
-		userRepo, sessionRepo, resetRepo,
+		userRepo, sessionRepo, resetRepo, workspaceRepo,
 		tokenMaker, pgDB.Pool(), redisDB.Client(),
 		cfg, logger,
 	)
-	linkService := service.NewLinkService(linkRepo, clickRepo, pgDB.Pool(), redisDB.Client(), cfg, eventPublisher, logger)
-	workspaceService := service.NewWorkspaceService(workspaceRepo, memberRepo, userRepo, licManager, eventPublisher, pgDB.Pool(), logger)
-	analyticsService := service.NewAnalyticsService(analyticsRepo, clickRepo, licManager, logger)
+	sessionValidator := service.NewSessionValidator(sessionRepo, redisDB.Client(), logger)
+	qrService := service.NewQRCodeService(qrCodeRepo, linkRepo, workspaceRepo, domainRepo, qrGenerator, qrBatchGenerator, objectStore, licManager, cfg, logger)
+	linkService := service.NewLinkService(linkRepo, clickRepo, analyticsRepo, memberRepo, workspaceRepo, domainRepo, qrService, safeFetcher, safeFetcher, urlChecker, linkFlagRepo, linkCommentRepo, linkTemplateRepo, pgDB.Pool(), redisDB.Client(), cfg, licManager, eventPublisher, logger)
+	webhookHostPolicy, err := httputil.NewHostPolicy(cfg.Webhooks.AllowPrivateTargets, cfg.Webhooks.AllowedHosts)
+	if err != nil {
+		logger.Fatal("invalid webhook allowed hosts", zap.Error(err))
+	}
+	linkImporters := map[string]linkimport.Importer{
+		models.LinkImportSourceBitly: linkimport.NewBitly(&http.Client{Timeout: 30 * time.Second}),
+	}
+	linkImportService := service.NewLinkImportService(linkImporters, linkService, linkRepo, workspaceRepo, redisDB.Client(), cfg, logger)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, memberRepo, userRepo, linkRepo, domainRepo, urlChecker, licManager, eventPublisher, pgDB.Pool(), redisDB.Client(), objectStore, webhookHostPolicy, cfg, logger)
+	analyticsService := service.NewAnalyticsService(analyticsBackends, workspaceRepo, clickRepo, linkRepo, analyticsShareRepo, cfg.App.SecretKey, licManager, logger)
 	sslProvider := service.NewMockSSLProvider()
 	domainService := service.NewDomainService(domainRepo, licManager, sslProvider, cfg, eventPublisher, logger)
-	qrService := service.NewQRCodeService(qrCodeRepo, linkRepo, qrGenerator, qrBatchGenerator, objectStore, licManager, cfg, logger)
-	bioPageService := service.NewBioPageService(bioPageRepo, licManager, eventPublisher, logger)
+	bioPageService := service.NewBioPageService(bioPageRepo, licManager, eventPublisher, ogimage.NewRenderer(safeFetcher, cfg.App.Name), objectStore, redisDB.Client(), cfg.App.SecretKey, logger)
 	apiKeyService := service.NewAPIKeyService(apiKeyRepo, licManager, redisDB.Client(), logger)
-	webhookService := service.NewWebhookService(webhookRepo, licManager, logger)
+	webhookService := service.NewWebhookService(webhookRepo, redisDB.Client(), licManager, webhookHostPolicy, cfg.Webhooks.SecretRotationWindow, logger)
+	ruleService := service.NewRuleService(linkRuleRepo, linkRepo, licManager, logger)
+	moderationService := service.NewLinkModerationService(linkFlagRepo, linkReportRepo, linkRepo, eventPublisher, logger)
+	linkCommentService := service.NewLinkCommentService(linkCommentRepo, linkRepo, redisDB.Client(), eventPublisher, logger)
+	licenseService := service.NewLicenseService(licManager, workspaceRepo, memberRepo, linkRepo, domainRepo, eventPublisher, logger)
+	usageService := service.NewUsageService(usageRepo, licManager, logger)
+	scheduledReportService := service.NewScheduledReportService(scheduledReportRepo, licManager, logger)
+	linkTemplateService := service.NewLinkTemplateService(linkTemplateRepo, domainRepo, licManager, logger)
+	reconcileLicenseUsage := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := licenseService.ReconcileUsage(ctx); err != nil {
+			logger.Warn("failed to reconcile license usage", zap.Error(err))
+		}
+	}
+	licManager.OnChange(reconcileLicenseUsage)
+	reconcileLicenseUsage()
 
 	// 11. Create handlers
 	authHandler := handler.NewAuthHandler(authService, logger)
-	licenseHandler := handler.NewLicenseHandler(licManager, logger)
+	licenseHandler := handler.NewLicenseHandler(licManager, licenseService, logger)
 	linkHandler := handler.NewLinkHandler(linkService, logger)
 	workspaceHandler := handler.NewWorkspaceHandler(workspaceService, logger)
 	analyticsHandler := handler.NewAnalyticsHandler(analyticsService, linkService, logger)
@@ -170,29 +243,48 @@ func main() {
 	bioPageHandler := handler.NewBioPageHandler(bioPageService, logger)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, logger)
 	webhookHandler := handler.NewWebhookHandler(webhookService, logger)
+	ruleHandler := handler.NewRuleHandler(ruleService, logger)
+	linkCommentHandler := handler.NewLinkCommentHandler(linkCommentService, logger)
+	linkImportHandler := handler.NewLinkImportHandler(linkImportService, logger)
+	adminHandler := handler.NewAdminHandler(moderationService, logger)
+	usageHandler := handler.NewUsageHandler(usageService, logger)
+	scheduledReportHandler := handler.NewScheduledReportHandler(scheduledReportService, logger)
+	linkTemplateHandler := handler.NewLinkTemplateHandler(linkTemplateService, logger)
 
 	// WebSocket real-time hub
 	wsHub := realtime.NewHub(logger)
+	wsHub.SetIdleTimeout(cfg.Realtime.IdleTimeout)
 	go wsHub.Run()
-	wsHandler := handler.NewWebSocketHandler(wsHub, tokenMaker, memberRepo, logger)
+	wsLimiter := realtime.NewConnLimiter(realtime.ConnLimits{
+		Max:     cfg.Realtime.MaxConnections,
+		PerIP:   cfg.Realtime.MaxConnectionsPerIP,
+		PerUser: cfg.Realtime.MaxConnectionsPerUser,
+	})
+	wsHandler := handler.NewWebSocketHandler(wsHub, wsLimiter, tokenMaker, sessionValidator, memberRepo, logger)
 
 	// Start Redis subscriber for real-time click notifications
 	realtimeCtx, realtimeCancel := context.WithCancel(context.Background())
 	defer realtimeCancel()
-	realtime.StartRedisSubscriber(realtimeCtx, redisDB.Client(), wsHub, logger)
+	realtime.StartRedisSubscriber(realtimeCtx, redisDB.Client(), wsHub, cfg.Realtime.SnapshotInterval, logger)
 
 	// 12. Create Gin router
 	if cfg.App.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	accessLevel, _ := zapcore.ParseLevel(cfg.Log.AccessLevel)
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog(logger, accessLevel, "/health", "/ready"))
+	if cfg.Security.ForceHTTPS {
+		router.Use(middleware.RequireHTTPS(cfg.Security.HSTSMaxAge, "/health", "/ready"))
+	}
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{cfg.App.FrontendURL},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Reset-After"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Reset-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -205,38 +297,79 @@ func main() {
 		})
 	})
 
+	// Readiness: PostgreSQL and Redis must answer. Analytics only reports
+	// whether ClickHouse is in use, since queries fall back to PostgreSQL.
+	router.GET("/ready", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+
+		status, ready := http.StatusOK, "ready"
+		checks := gin.H{"postgres": "ok", "redis": "ok"}
+		if err := pgDB.HealthCheck(ctx); err != nil {
+			checks["postgres"] = err.Error()
+			status, ready = http.StatusServiceUnavailable, "not_ready"
+		}
+		if err := redisDB.HealthCheck(ctx); err != nil {
+			checks["redis"] = err.Error()
+			status, ready = http.StatusServiceUnavailable, "not_ready"
+		}
+
+		analytics := gin.H{"backend": "postgres"}
+		if analyticsBackends.ClickHouse != nil {
+			analytics = gin.H{"backend": "clickhouse", "circuit": analyticsBackends.Circuit.Status()}
+		}
+		c.JSON(status, gin.H{
+			"status":    ready,
+			"service":   "linkrift-api",
+			"checks":    checks,
+			"analytics": analytics,
+		})
+	})
+
 	// 14. API v1 routes
 	v1 := router.Group("/api/v1")
-	authMw := middleware.RequireAuth(tokenMaker, userRepo)
+	authMw := middleware.RequireAuth(tokenMaker, userRepo, sessionValidator)
 	authHandler.RegisterRoutes(v1, authMw)
 	licenseHandler.RegisterRoutes(v1, authMw)
 
+	// Instance admin routes, for users listed in APP_ADMIN_EMAILS
+	adminHandler.RegisterRoutes(v1, authMw, middleware.RequireInstanceAdmin(cfg.App.AdminEmails))
+
 	// Workspace routes
 	wsAccessMw := middleware.RequireWorkspaceAccess(workspaceRepo, memberRepo)
 	workspaceHandler.RegisterRoutes(v1, authMw, wsAccessMw)
 
-	// API key auth middleware (processes X-API-Key header before session auth)
+	// API key auth middleware (authenticates the X-API-Key header)
 	apiKeyAuthMw := middleware.APIKeyAuth(apiKeyService, userRepo, workspaceRepo, memberRepo)
 
-	// Link routes now live under /api/v1/workspaces/:workspaceId/links
+	// Workspace routes that require a session
 	wsScoped := v1.Group("/workspaces/:workspaceId", authMw, wsAccessMw)
 	editorMw := middleware.RequireWorkspaceRole(models.RoleEditor)
 	adminMw := middleware.RequireWorkspaceRole(models.RoleAdmin)
-	linkHandler.RegisterRoutes(wsScoped, editorMw)
+	ruleHandler.RegisterRoutes(wsScoped, editorMw)
+	linkCommentHandler.RegisterRoutes(wsScoped)
+	linkImportHandler.RegisterRoutes(wsScoped, editorMw)
 	domainHandler.RegisterRoutes(wsScoped, editorMw)
-	qrHandler.RegisterRoutes(wsScoped, editorMw)
 	bioPageHandler.RegisterRoutes(wsScoped, editorMw)
-	analyticsHandler.RegisterRoutes(wsScoped)
 	apiKeyHandler.RegisterRoutes(wsScoped, adminMw)
 	webhookHandler.RegisterRoutes(wsScoped, adminMw)
+	usageHandler.RegisterRoutes(wsScoped, adminMw)
+	scheduledReportHandler.RegisterRoutes(wsScoped, adminMw)
 
-	// API key authenticated routes (alternative auth for programmatic access)
-	apiScoped := v1.Group("/workspaces/:workspaceId", apiKeyAuthMw, wsAccessMw)
+	// Routes that also accept an API key instead of a session, for
+	// programmatic access. Handlers check the key's scopes per route.
+	apiScoped := v1.Group("/workspaces/:workspaceId", middleware.RequireAuthOrAPIKey(authMw, apiKeyAuthMw), wsAccessMw)
 	linkHandler.RegisterRoutes(apiScoped, editorMw)
+	linkTemplateHandler.RegisterRoutes(apiScoped, editorMw)
+	qrHandler.RegisterRoutes(apiScoped, editorMw)
+	analyticsHandler.RegisterRoutes(apiScoped, editorMw)
 
 	// Public bio page routes (no auth)
 	bioPageHandler.RegisterPublicRoutes(router)
 
+	// Public analytics share links (no auth, token in the path)
+	analyticsHandler.RegisterPublicRoutes(v1)
+
 	// WebSocket endpoint (outside API group, no auth middleware — auth via query param)
 	wsHandler.RegisterRoutes(router)
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,12 +14,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/link-rift/link-rift/internal/config"
 	"github.com/link-rift/link-rift/internal/database"
+	"github.com/link-rift/link-rift/internal/middleware"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/redirect"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/service"
 	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var passwordPageTmpl = template.Must(template.New("password").Parse(`<!DOCTYPE html>
@@ -35,18 +40,23 @@ var passwordPageTmpl = template.Must(template.New("password").Parse(`<!DOCTYPE h
     p { font-size: 0.875rem; color: #6b7280; margin-bottom: 1.5rem; }
     .error { color: #dc2626; font-size: 0.875rem; margin-bottom: 1rem; }
     input { width: 100%; padding: 0.625rem 0.75rem; border: 1px solid #d1d5db; border-radius: 6px; font-size: 0.875rem; margin-bottom: 1rem; outline: none; }
-    input:focus { border-color: #2563eb; box-shadow: 0 0 0 2px rgba(37,99,235,0.15); }
-    button { width: 100%; padding: 0.625rem; background: #2563eb; color: white; border: none; border-radius: 6px; font-size: 0.875rem; font-weight: 500; cursor: pointer; }
-    button:hover { background: #1d4ed8; }
+    input:focus { border-color: {{.Accent}}; box-shadow: 0 0 0 1px {{.Accent}}; }
+    button { width: 100%; padding: 0.625rem; background: {{.Accent}}; color: white; border: none; border-radius: 6px; font-size: 0.875rem; font-weight: 500; cursor: pointer; }
+    button:hover { filter: brightness(0.9); }
+    .logo { display: block; max-height: 40px; max-width: 160px; margin-bottom: 1.25rem; }
+    .hint { color: #374151; font-size: 0.8125rem; margin: -0.5rem 0 1rem; }
   </style>
 </head>
 <body>
   <div class="card">
+    {{if .Logo}}<img class="logo" src="{{.Logo}}" alt="">{{end}}
     <h1>Password Required</h1>
     <p>This link is password protected. Enter the password to continue.</p>
     {{if .Error}}<div class="error">{{.Error}}</div>{{end}}
     <form method="POST" action="/{{.ShortCode}}/verify">
       <input type="password" name="password" placeholder="Enter password" required autofocus>
+      {{if .Hint}}<p class="hint">Hint: {{.Hint}}</p>{{end}}
+      {{if .Source}}<input type="hidden" name="lr_src" value="{{.Source}}">{{end}}
       <button type="submit">Continue</button>
     </form>
   </div>
@@ -65,10 +75,13 @@ var errorPageTmpl = template.Must(template.New("error").Parse(`<!DOCTYPE html>
     .card { background: white; border-radius: 12px; box-shadow: 0 1px 3px rgba(0,0,0,0.1); padding: 2rem; max-width: 400px; width: 90%; text-align: center; }
     h1 { font-size: 1.5rem; margin-bottom: 0.5rem; color: #111827; }
     p { font-size: 0.875rem; color: #6b7280; }
+    .card { border-top: 4px solid {{.Accent}}; }
+    .logo { display: block; max-height: 40px; max-width: 160px; margin: 0 auto 1.25rem; }
   </style>
 </head>
 <body>
   <div class="card">
+    {{if .Logo}}<img class="logo" src="{{.Logo}}" alt="">{{end}}
     <h1>{{.Title}}</h1>
     <p>{{.Message}}</p>
   </div>
@@ -82,6 +95,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 
 	// 2. Init logger
 	var logger *zap.Logger
@@ -96,13 +113,23 @@ func main() {
 	}
 	defer logger.Sync()
 
-	// 3. Connect PostgreSQL (read pool)
+	// 3. Connect PostgreSQL. Link lookups read from the replica when one is
+	// configured; abuse reports write to the primary.
 	pgDB, err := database.NewPostgres(cfg.Database, logger)
 	if err != nil {
 		logger.Fatal("failed to connect to PostgreSQL", zap.Error(err))
 	}
 	defer pgDB.Close()
 
+	readDB := pgDB
+	if cfg.Database.ReplicaURL != "" {
+		readDB, err = database.NewPostgresReplica(cfg.Database, logger)
+		if err != nil {
+			logger.Fatal("failed to connect to PostgreSQL read replica", zap.Error(err))
+		}
+		defer readDB.Close()
+	}
+
 	// 4. Connect Redis
 	redisDB, err := database.NewRedis(cfg.Redis, logger)
 	if err != nil {
@@ -113,6 +140,8 @@ func main() {
 	// 5. Create dependencies
 	queries := sqlc.New(pgDB.Pool())
 	linkRepo := repository.NewLinkRepository(queries, logger)
+	readQueries := sqlc.New(readDB.Pool())
+	readLinkRepo := repository.NewLinkRepository(readQueries, logger)
 
 	cache := redirect.NewCache(
 		redisDB.Client(),
@@ -120,20 +149,229 @@ func main() {
 		cfg.Redirect.RedisCacheTTL,
 		logger,
 	)
-	resolver := redirect.NewResolver(cache, linkRepo, logger)
+	resolver := redirect.NewResolver(cache, readLinkRepo, logger)
+	resolver.SetCaseInsensitive(cfg.Links.CaseInsensitiveShortCodes)
+	if cfg.Redirect.WarmCache {
+		warmCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		warmed, err := resolver.Warm(warmCtx, cfg.Redirect.WarmCacheLinks)
+		cancel()
+		if err != nil {
+			// A cold cache only costs latency; serve anyway.
+			logger.Warn("failed to warm redirect cache", zap.Error(err))
+		} else {
+			logger.Info("warmed redirect cache", zap.Int("links", warmed))
+		}
+	}
 	tracker := redirect.NewClickTracker(
 		redisDB.Client(),
 		cfg.Redirect.TrackerBuffer,
 		cfg.Redirect.TrackerFlush,
 		logger,
 	)
+	readWorkspaceRepo := repository.NewWorkspaceRepository(readQueries, logger)
+	sampler := redirect.NewClickSampler(readWorkspaceRepo, redisDB.Client(), logger)
 	botDetector := redirect.NewBotDetector()
-	ruleEngine := redirect.NewRuleEngine(queries, logger)
+	optOut := redirect.NewOptOutPolicy(cfg.Privacy.HonorOptOut, cfg.Privacy.OptOutCookie)
+	ruleEngine := redirect.NewRuleEngine(readQueries, logger)
+
+	// Cloaked links probe their destination's framing headers, and links
+	// that force HTTPS probe whether the destination host serves HTTPS,
+	// through the SSRF-safe client, which only reaches public addresses.
+	fetchPolicy, _ := httputil.NewHostPolicy(false, nil)
+	fetchConfig := httputil.DefaultSafeClientConfig()
+	fetchConfig.MaxRedirects = cfg.Fetch.MaxRedirects
+	fetchConfig.HopTimeout = cfg.Fetch.HopTimeout
+	safeClient := httputil.NewSafeClient(fetchPolicy, fetchConfig)
+	frameChecker := redirect.NewFrameChecker(safeClient, cfg.Redirect.FrameCheckTTL, logger)
+	httpsUpgrader := redirect.NewHTTPSUpgrader(safeClient, cfg.Redirect.HTTPSCheckTTL, logger)
+	// Previews trace destination redirects, reusing results for as long as
+	// HTTPS probes are reused.
+	chainTracer := redirect.NewChainTracer(safeClient, cfg.Redirect.HTTPSCheckTTL, logger)
+
+	// Links with failover URLs skip a destination the worker's health checks
+	// found down
+	failover := redirect.NewFailover(redisDB.Client(), logger)
+
+	// Once-per-visitor links remember their visitors in Redis
+	visitorLimiter := redirect.NewVisitorLimiter(redisDB.Client(), cfg.Redirect.VisitorIdentity, cfg.App.SecretKey, cfg.Security.SecureCookies, logger)
+
+	// Abuse reports can disable a link and notify its workspace via webhooks
+	reportService := service.NewAbuseReportService(
+		linkRepo,
+		repository.NewLinkReportRepository(queries, logger),
+		repository.NewLinkFlagRepository(queries, logger),
+		redisDB.Client(),
+		cfg,
+		service.NewEventPublisher(redisDB.Client(), logger),
+		logger,
+	)
+
+	robotsTxt, err := redirect.LoadRobotsTxt(cfg.Redirect.RobotsTxtPath)
+	if err != nil {
+		logger.Fatal("failed to load robots.txt", zap.Error(err))
+	}
+	site := redirect.NewSiteRoutes(
+		cfg.Redirect.RootURL,
+		cfg.Redirect.FaviconURL,
+		robotsTxt,
+	)
+
+	// Unknown short codes are answered per host: custom domains can choose
+	// their own not-found policy over the instance default.
+	notFoundPolicies := redirect.NewNotFoundPolicies(
+		repository.NewDomainRepository(readQueries, logger),
+		redirect.DefaultNotFoundPolicy(cfg.Redirect.NotFoundMode, cfg.Redirect.NotFoundStatus, cfg.Redirect.NotFoundURL),
+		logger,
+	)
+
+	interstitialTmpl, err := redirect.LoadInterstitialTemplate(cfg.Redirect.InterstitialTemplatePath)
+	if err != nil {
+		logger.Fatal("failed to load interstitial template", zap.Error(err))
+	}
+	interstitials := redirect.NewInterstitials(
+		readWorkspaceRepo,
+		redisDB.Client(),
+		interstitialTmpl,
+		cfg.Redirect.InterstitialDelay,
+		cfg.Redirect.InterstitialConsentTTL,
+		cfg.Security.SecureCookies,
+		logger,
+	)
+
+	// Password and error pages carry the workspace's branding, cached with
+	// its interstitial settings
+	resolver.SetBranding(interstitials)
+
+	botDetector.SetAllowlist(cfg.Redirect.BotAllowlist)
+
+	// 5b. Apply cache TTLs, the bot allowlist, opt-out settings and the
+	// not-found default again on SIGHUP, without restarting
+	live := config.NewLive(cfg)
+	live.OnReload(func(c *config.Config) {
+		cache.SetTTLs(c.Redirect.LocalCacheTTL, c.Redirect.RedisCacheTTL)
+		frameChecker.SetTTL(c.Redirect.FrameCheckTTL)
+		httpsUpgrader.SetTTL(c.Redirect.HTTPSCheckTTL)
+		chainTracer.SetTTL(c.Redirect.HTTPSCheckTTL)
+		botDetector.SetAllowlist(c.Redirect.BotAllowlist)
+		optOut.Update(c.Privacy.HonorOptOut, c.Privacy.OptOutCookie)
+		notFoundPolicies.SetFallback(redirect.DefaultNotFoundPolicy(c.Redirect.NotFoundMode, c.Redirect.NotFoundStatus, c.Redirect.NotFoundURL))
+	})
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	live.ReloadOnSIGHUP(reloadCtx, func(err error) {
+		if err != nil {
+			logger.Error("config reload failed, keeping current settings", zap.Error(err))
+			return
+		}
+		logger.Info("config reloaded")
+	})
+
+	// notFound answers with the host's not-found policy: the branded page,
+	// a redirect or a bare status. JSON clients always get an error body.
+	notFound := func(c *gin.Context, title, message string) {
+		policy := notFoundPolicies.For(c.Request.Context(), c.Request.Host)
+		redirect.WriteNotFound(c.Writer, c.Request, policy, func(status int) {
+			renderError(c, nil, status, title, message)
+		})
+	}
+
+	// Destinations on the redirect service's own host are other short links;
+	// they carry a hop count so chains that loop are stopped.
+	var redirectHost string
+	if u, err := url.Parse(cfg.App.RedirectURL); err == nil {
+		redirectHost = u.Host
+	}
+
+	// sendToDestination upgrades the destination to HTTPS for links that
+	// force it. Mobile visitors of links with an app URI for their platform
+	// get a page that tries the app and falls back to the destination. Cloaked
+	// links whose destination allows framing get a cloak page, links with
+	// retargeting pixels an interstitial that fires them (except for bots and
+	// opted-out visitors), and everything else a 302.
+	sendToDestination := func(c *gin.Context, result *redirect.ResolveResult, destinationURL string) {
+		destinationURL = redirect.MarkHop(destinationURL, redirect.Hops(c.Request)+1, c.Request.Host, redirectHost)
+		if result.ForceHTTPS {
+			destinationURL = httpsUpgrader.Upgrade(c.Request.Context(), destinationURL)
+		}
+		if appURL := result.AppURL(c.Request.UserAgent()); appURL != "" {
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			c.Header("X-Robots-Tag", "noindex, nofollow")
+			c.Status(http.StatusOK)
+			redirect.WriteDeepLinkPage(c.Writer, result.Title, appURL, destinationURL)
+			return
+		}
+		if result.Cloak && frameChecker.CanFrame(c.Request.Context(), destinationURL) {
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			c.Header("X-Robots-Tag", "noindex, nofollow")
+			c.Status(http.StatusOK)
+			redirect.WriteCloakPage(c.Writer, result.Title, destinationURL)
+			return
+		}
+		if result.HasPixels() && !botDetector.IsBot(c.Request.UserAgent()) && !optOut.OptedOut(c.Request) {
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			c.Header("Cache-Control", "no-store")
+			c.Header("X-Robots-Tag", "noindex, nofollow")
+			c.Status(http.StatusOK)
+			redirect.WritePixelPage(c.Writer, result, destinationURL)
+			return
+		}
+		c.Redirect(http.StatusFound, destinationURL)
+	}
+
+	// Clicks skip HEAD requests, bots and opted-out visitors
+	clicks := redirect.NewClickRecorder(tracker, sampler, botDetector, optOut)
+
+	// showInterstitial shows the link's "you are leaving" page to visitors
+	// who haven't continued past it yet and reports whether it did. Bots
+	// skip it.
+	showInterstitial := func(c *gin.Context, result *redirect.ResolveResult) bool {
+		if botDetector.IsBot(c.Request.UserAgent()) || interstitials.Consented(c.Request, result.ShortCode) {
+			return false
+		}
+		page := interstitials.Page(c.Request.Context(), result, c.Request.URL.RawQuery)
+		if page == nil {
+			return false
+		}
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Header("Cache-Control", "no-store")
+		c.Header("X-Robots-Tag", "noindex, nofollow")
+		c.Status(http.StatusOK)
+		interstitials.Write(c.Writer, page)
+		return true
+	}
+
+	// repeatVisit handles a return visit to a once-per-visitor link by
+	// redirecting to the link's repeat-visit URL or showing an "already used"
+	// page. It records first visits and reports false for them.
+	repeatVisit := func(c *gin.Context, result *redirect.ResolveResult) bool {
+		if !result.OncePerVisitor {
+			return false
+		}
+		visitor := visitorLimiter.Visitor(c.Writer, c.Request, c.ClientIP())
+		if visitorLimiter.FirstVisit(c.Request.Context(), result.LinkID, visitor, result.ExpiresAt) {
+			return false
+		}
+		if result.RepeatVisitURL != "" {
+			c.Redirect(http.StatusFound, result.RepeatVisitURL)
+			return true
+		}
+		renderError(c, result.Branding, http.StatusGone, "Link Already Used", "This link can only be used once per visitor.")
+		return true
+	}
 
 	// 6. Create Gin router in release mode
 	gin.SetMode(gin.ReleaseMode)
+	accessLevel, _ := zapcore.ParseLevel(cfg.Log.AccessLevel)
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog(logger, accessLevel, "/health"))
+	if cfg.Security.ForceHTTPS {
+		router.Use(middleware.RequireHTTPS(cfg.Security.HSTSMaxAge, "/health"))
+	}
+	// Short links answer HEAD like GET, for monitors that check a link
+	// without following it
+	router.Use(middleware.HeadWithoutBody())
 
 	// 7. Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -143,6 +381,21 @@ func main() {
 		})
 	})
 
+	// Site routes that aren't short links
+	handleRoot := func(c *gin.Context) {
+		if site.RedirectRoot(c.Writer, c.Request) {
+			return
+		}
+		notFound(c, "Page Not Found", "There's nothing here. Check the link you followed.")
+	}
+	router.GET("/", handleRoot)
+	router.HEAD("/", handleRoot)
+	router.GET("/robots.txt", gin.WrapF(site.ServeRobots))
+	router.GET("/favicon.ico", gin.WrapF(site.ServeFavicon))
+	router.NoRoute(func(c *gin.Context) {
+		notFound(c, "Page Not Found", "There's nothing here. Check the link you followed.")
+	})
+
 	// 8. Password verification endpoint
 	router.POST("/:shortCode/verify", func(c *gin.Context) {
 		shortCode := c.Param("shortCode")
@@ -150,38 +403,80 @@ func main() {
 
 		result, err := resolver.Resolve(c.Request.Context(), shortCode)
 		if err != nil {
-			renderError(c, http.StatusNotFound, "Link Not Found", "The link you're looking for doesn't exist.")
+			renderError(c, nil, http.StatusNotFound, "Link Not Found", "The link you're looking for doesn't exist.")
+			return
+		}
+
+		// A correct password doesn't get past a disabled, expired or
+		// unscheduled link
+		if linkUnavailable(c, result) {
+			return
+		}
+
+		if showInterstitial(c, result) {
 			return
 		}
 
 		if !result.HasPassword {
-			c.Redirect(http.StatusFound, result.DestinationURL)
+			if repeatVisit(c, result) {
+				return
+			}
+			c.Redirect(http.StatusFound, failover.Destination(c.Request.Context(), result))
 			return
 		}
 
 		match, err := crypto.VerifyPassword(password, result.PasswordHash)
 		if err != nil || !match {
-			passwordPageTmpl.Execute(c.Writer, map[string]interface{}{
-				"ShortCode": shortCode,
-				"Error":     "Incorrect password. Please try again.",
-			})
+			renderPasswordPage(c, result, "Incorrect password. Please try again.")
+			return
+		}
+
+		redirect.SetLinkAuth(c.Writer, result, cfg.Security.SecureCookies)
+
+		if repeatVisit(c, result) {
+			return
+		}
+
+		// Track click (non-blocking)
+		clicks.Record(c.Request, result, c.ClientIP())
+
+		sendToDestination(c, result, failover.Destination(c.Request.Context(), result))
+	})
+
+	// 8a. Interstitial continue endpoint: remember the visitor's consent and
+	// send them back through the short link
+	router.GET("/:shortCode/continue", func(c *gin.Context) {
+		result, err := resolver.Resolve(c.Request.Context(), c.Param("shortCode"))
+		if err != nil {
+			notFound(c, "Link Not Found", "The link you're looking for doesn't exist or has been removed.")
 			return
 		}
 
-		// Track click
-		if !botDetector.IsBot(c.Request.UserAgent()) {
-			tracker.Track(&models.ClickEvent{
-				LinkID:      result.LinkID,
-				WorkspaceID: result.WorkspaceID,
-				ShortCode:   result.ShortCode,
-				IP:          c.ClientIP(),
-				UserAgent:   c.Request.UserAgent(),
-				Referer:     c.Request.Referer(),
-				Timestamp:   time.Now(),
-			})
+		interstitials.Consent(c.Writer, result.ShortCode)
+		back := url.URL{Path: "/" + result.ShortCode, RawQuery: c.Request.URL.RawQuery}
+		c.Redirect(http.StatusFound, back.String())
+	})
+
+	// 8b. Abuse report endpoint
+	router.POST("/:shortCode/report", func(c *gin.Context) {
+		var input models.ReportLinkInput
+		if err := c.ShouldBind(&input); err != nil {
+			httputil.RespondError(c, httputil.BindingError("body", err))
+			return
 		}
 
-		c.Redirect(http.StatusFound, result.DestinationURL)
+		result, err := reportService.ReportLink(c.Request.Context(), c.Param("shortCode"), input, c.ClientIP())
+		if err != nil {
+			httputil.RespondError(c, err)
+			return
+		}
+
+		// Stop serving a link disabled by reports right away
+		if result.Disabled {
+			resolver.InvalidateCache(c.Request.Context(), result.ShortCode)
+		}
+
+		httputil.RespondSuccess(c, http.StatusAccepted, gin.H{"received": true})
 	})
 
 	// 9. Preview handler (shortCode+)
@@ -200,79 +495,67 @@ func main() {
 			"is_active":       result.IsActive,
 			"has_password":    result.HasPassword,
 			"is_expired":      result.IsExpired,
+			"redirect_chain":  chainTracer.Trace(c.Request.Context(), result.DestinationURL),
 		})
 	})
 
-	// 10. Main redirect handler
-	router.GET("/:shortCode", func(c *gin.Context) {
+	// 10. Main redirect handler. HEAD gets the same status and headers as
+	// GET but is never counted as a click or a visit.
+	handleRedirect := func(c *gin.Context) {
 		shortCode := c.Param("shortCode")
-
-		// Skip common favicon/robot requests
-		if shortCode == "favicon.ico" || shortCode == "robots.txt" {
-			c.Status(http.StatusNotFound)
-			return
-		}
+		head := c.Request.Method == http.MethodHead
 
 		result, err := resolver.Resolve(c.Request.Context(), shortCode)
 		if err != nil {
-			renderError(c, http.StatusNotFound, "Link Not Found", "The link you're looking for doesn't exist or has been removed.")
-			return
-		}
-
-		// Check if active
-		if !result.IsActive {
-			renderError(c, http.StatusGone, "Link Disabled", "This link has been disabled by its owner.")
+			notFound(c, "Link Not Found", "The link you're looking for doesn't exist or has been removed.")
 			return
 		}
 
-		// Check if expired
-		if result.IsExpired {
-			renderError(c, http.StatusGone, "Link Expired", "This link has expired and is no longer available.")
+		if linkUnavailable(c, result) {
 			return
 		}
 
-		// Check click limit
-		if result.IsOverLimit {
-			renderError(c, http.StatusGone, "Link Limit Reached", "This link has reached its maximum number of clicks.")
+		// Show the "you are leaving" page until the visitor continues
+		if showInterstitial(c, result) {
 			return
 		}
 
 		// Password protected — show form
 		if result.HasPassword {
 			// Check for auth cookie
-			cookie, err := c.Cookie("lr_auth_" + shortCode)
-			if err != nil || cookie != "1" {
-				c.Header("Content-Type", "text/html; charset=utf-8")
-				c.Status(http.StatusOK)
-				passwordPageTmpl.Execute(c.Writer, map[string]interface{}{
-					"ShortCode": shortCode,
-				})
+			if !redirect.HasLinkAuth(c.Request, result) {
+				renderPasswordPage(c, result, "")
 				return
 			}
 		}
 
-		// Evaluate conditional redirect rules
-		destinationURL := result.DestinationURL
+		// Send returning visitors of once-per-visitor links elsewhere
+		if !head && repeatVisit(c, result) {
+			return
+		}
+
+		// Evaluate conditional redirect rules. Without a match the link's
+		// destination is used, or a failover URL while it is down.
+		var destinationURL string
 		if ruleURL, matched := ruleEngine.Evaluate(c.Request.Context(), result.LinkID, c.Request); matched {
 			destinationURL = ruleURL
+		} else {
+			destinationURL = failover.Destination(c.Request.Context(), result)
 		}
 
-		// Track click (non-blocking, skip bots)
-		if !botDetector.IsBot(c.Request.UserAgent()) {
-			tracker.Track(&models.ClickEvent{
-				LinkID:      result.LinkID,
-				WorkspaceID: result.WorkspaceID,
-				ShortCode:   result.ShortCode,
-				IP:          c.ClientIP(),
-				UserAgent:   c.Request.UserAgent(),
-				Referer:     c.Request.Referer(),
-				Timestamp:   time.Now(),
-			})
+		// Forward the short link's query parameters onto the destination
+		if result.ForwardParams {
+			destinationURL = redirect.ForwardQuery(destinationURL, redirect.ForwardableQuery(c.Request), result.ParamPrecedence)
 		}
 
+		// Track click (non-blocking)
+		clicks.Record(c.Request, result, c.ClientIP())
+
 		// Append UTM params if the destination doesn't already have them
-		c.Redirect(http.StatusFound, destinationURL)
-	})
+		sendToDestination(c, result, destinationURL)
+	}
+	router.GET("/:shortCode", handleRedirect)
+	router.HEAD("/:shortCode", handleRedirect)
 
 	// 11. Start server with graceful shutdown
 	srv := &http.Server{
@@ -312,12 +595,82 @@ func main() {
 	logger.Info("redirect server stopped")
 }
 
-func renderError(c *gin.Context, status int, title, message string) {
+// linkUnavailable writes the response for a link that can't be followed
+// right now: a short link loop, a link outside its schedule, or one that is
+// disabled, expired or over its click limit. It reports whether it did, so
+// every handler that leads to the destination checks the same conditions.
+func linkUnavailable(c *gin.Context, result *redirect.ResolveResult) bool {
+	// Stop short links that redirect to each other
+	if redirect.Hops(c.Request) >= redirect.MaxShortLinkHops {
+		renderError(c, result.Branding, http.StatusLoopDetected, "Redirect Loop", "This link redirects through too many short links.")
+		return true
+	}
+
+	// Outside its schedule the link goes to the schedule's fallback, or
+	// is unavailable until the next window opens
+	if result.OutsideSchedule {
+		c.Header("Cache-Control", "no-store")
+		if result.ScheduleFallbackURL != "" {
+			c.Redirect(http.StatusFound, result.ScheduleFallbackURL)
+			return true
+		}
+		renderError(c, result.Branding, http.StatusServiceUnavailable, "Link Unavailable", "This link isn't available right now. Please try again later.")
+		return true
+	}
+
+	// Check if active
+	if !result.IsActive {
+		renderError(c, result.Branding, http.StatusGone, "Link Disabled", "This link has been disabled by its owner.")
+		return true
+	}
+
+	// Check if expired
+	if result.IsExpired {
+		renderError(c, result.Branding, http.StatusGone, "Link Expired", "This link has expired and is no longer available.")
+		return true
+	}
+
+	// Check click limit
+	if result.IsOverLimit {
+		renderError(c, result.Branding, http.StatusGone, "Link Limit Reached", "This link has reached its maximum number of clicks.")
+		return true
+	}
+
+	return false
+}
+
+// renderPasswordPage writes the password form for result's link, with the
+// link's hint and its workspace's branding. errMsg is shown above the form
+// when not empty.
+func renderPasswordPage(c *gin.Context, result *redirect.ResolveResult, errMsg string) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	passwordPageTmpl.Execute(c.Writer, map[string]interface{}{
+		"ShortCode": result.ShortCode,
+		"Source":    redirect.ClickSource(c.Request),
+		"Error":     errMsg,
+		"Hint":      result.PasswordHint,
+		"Logo":      result.Branding.Logo(),
+		"Accent":    result.Branding.Accent(),
+	})
+}
+
+// renderError writes the error page, styled with branding when it isn't nil,
+// or a JSON error body to clients that ask for one with Accept:
+// application/json.
+func renderError(c *gin.Context, branding *models.WorkspaceBranding, status int, title, message string) {
+	c.Header("Vary", "Accept")
+	if redirect.WantsJSON(c.Request) {
+		c.JSON(status, redirect.ErrorResponse(title, message))
+		return
+	}
 	c.Header("Content-Type", "text/html; charset=utf-8")
 	c.Status(status)
 	errorPageTmpl.Execute(c.Writer, map[string]string{
 		"Title":   title,
 		"Message": message,
+		"Logo":    branding.Logo(),
+		"Accent":  branding.Accent(),
 	})
 }
 
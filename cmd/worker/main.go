@@ -9,11 +9,17 @@ import (
 
 	"github.com/link-rift/link-rift/internal/config"
 	"github.com/link-rift/link-rift/internal/database"
+	"github.com/link-rift/link-rift/internal/license"
 	"github.com/link-rift/link-rift/internal/redirect"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/safety"
 	"github.com/link-rift/link-rift/internal/service"
 	"github.com/link-rift/link-rift/internal/worker"
+	"github.com/link-rift/link-rift/pkg/email"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/lock"
+	"github.com/link-rift/link-rift/pkg/storage"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +30,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 
 	// 2. Init logger
 	var logger *zap.Logger
@@ -52,16 +62,88 @@ func main() {
 	}
 	defer redisDB.Close()
 
-	// 5. Create dependencies
+	// 5. Initialize license system, for the tier recorded with usage
+	// snapshots, the analytics included in scheduled reports and the link
+	// limit of bulk link jobs
+	licVerifier, err := license.NewVerifier()
+	if err != nil {
+		logger.Fatal("failed to create license verifier", zap.Error(err))
+	}
+	licManager := license.NewManager(licVerifier, logger)
+	if cfg.License.Key != "" {
+		if err := licManager.LoadLicense(cfg.License.Key); err != nil {
+			logger.Warn("failed to load license key, running as community edition", zap.Error(err))
+		} else {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			licManager.StartPeriodicCheck(ctx, cfg.License.CheckInterval, cfg.License.CheckJitter)
+		}
+	}
+
+	// 5b. Create dependencies
 	queries := sqlc.New(pgDB.Pool())
 	clickRepo := repository.NewClickRepository(queries, logger)
 	linkRepo := repository.NewLinkRepository(queries, logger)
 	webhookRepo := repository.NewWebhookRepository(queries, logger)
+	workspaceRepo := repository.NewWorkspaceRepository(queries, logger)
+	bioPageRepo := repository.NewBioPageRepository(queries, logger)
+	domainRepo := repository.NewDomainRepository(queries, logger)
+	qrRepo := repository.NewQRCodeRepository(queries, logger)
+	apiKeyRepo := repository.NewAPIKeyRepository(queries, logger)
+	usageRepo := repository.NewUsageRepository(queries, logger)
+	scheduledReportRepo := repository.NewScheduledReportRepository(queries, logger)
+	linkFlagRepo := repository.NewLinkFlagRepository(queries, logger)
+	linkTemplateRepo := repository.NewLinkTemplateRepository(queries, logger)
 	botDetector := redirect.NewBotDetector()
+	botDetector.SetAllowlist(cfg.Redirect.BotAllowlist)
+
+	// Analytics backends are chosen as in the API. Workspace exports read
+	// from the default store; scheduled reports follow each workspace's
+	// setting. Processed clicks are forwarded to ClickHouse when it's
+	// available.
+	var clickSinks []worker.ClickSink
+	analyticsBackends := service.AnalyticsBackends{
+		Postgres:          repository.NewPGAnalyticsRepository(pgDB.Pool(), logger),
+		ClickHouseDefault: cfg.ClickHouse.WorkspaceDefault,
+	}
+	if cfg.ClickHouse.URL != "" {
+		chDB, err := database.NewClickHouse(cfg.ClickHouse, logger)
+		if err != nil {
+			logger.Warn("ClickHouse unavailable, using PostgreSQL for analytics", zap.Error(err))
+		} else {
+			defer chDB.Close()
+			analyticsBackends.ClickHouse = repository.NewClickHouseAnalyticsRepository(chDB.Conn(), logger)
+			clickSinks = append(clickSinks, worker.NewClickHouseForwarder(chDB.Conn(), logger))
+		}
+	}
+	analyticsRepo := analyticsBackends.Default(logger)
+
+	// Export archives, and deleted workspaces' files, live in the same object
+	// storage the API uses.
+	var objectStore storage.ObjectStorage
+	storageOpts := storage.Options{
+		KeyPrefix:         cfg.Storage.KeyPrefix,
+		WorkspacePrefixes: cfg.Storage.WorkspacePrefixes,
+	}
+	if cfg.S3.Endpoint != "" && cfg.S3.AccessKey != "" {
+		s3Store, err := storage.NewS3Storage(cfg.S3, storageOpts)
+		if err != nil {
+			logger.Warn("S3 storage unavailable, falling back to local storage", zap.Error(err))
+			objectStore = storage.NewLocalStorage("./data/uploads/", cfg.App.BaseURL+"/uploads/", storageOpts)
+		} else {
+			objectStore = s3Store
+		}
+	} else {
+		objectStore = storage.NewLocalStorage("./data/uploads/", cfg.App.BaseURL+"/uploads/", storageOpts)
+	}
 
-	// 5b. Create event publisher for webhook events
+	// 5c. Create event publisher for webhook events
 	eventPublisher := service.NewEventPublisher(redisDB.Client(), logger)
 
+	// Periodic jobs take a Redis lock, so with several worker replicas each
+	// job runs once per interval
+	locker := lock.New(redisDB.Client())
+
 	// 6. Create and start click processor
 	processor := worker.NewClickProcessor(
 		redisDB.Client(),
@@ -71,21 +153,208 @@ func main() {
 		logger,
 	)
 	processor.SetEventPublisher(eventPublisher)
+	processor.SetIPAnonymization(cfg.Privacy.AnonymizeIP)
+	processor.SetUniqueClickWindow(cfg.Links.UniqueClickWindow)
+	processor.SetDuplicateClickWindow(cfg.Links.DuplicateClickWindow)
+	processor.SetLocker(locker)
+
+	// 6a. Publish processed clicks to an event bus, if one is configured
+	var (
+		busPublisher worker.MessagePublisher
+		busErr       error
+	)
+	switch cfg.EventBus.Driver {
+	case config.EventBusDriverNATS:
+		busPublisher, busErr = worker.NewNATSPublisher(cfg.EventBus.URL, cfg.EventBus.Topic, cfg.EventBus.Timeout, logger)
+	case config.EventBusDriverKafka:
+		busPublisher, busErr = worker.NewKafkaRESTPublisher(cfg.EventBus.URL, cfg.EventBus.Topic, cfg.EventBus.Timeout)
+	}
+	if busErr != nil {
+		logger.Fatal("invalid event bus configuration", zap.Error(busErr))
+	}
+	if busPublisher != nil {
+		defer busPublisher.Close()
+		clickSinks = append(clickSinks, worker.NewEventBusSink(busPublisher, logger))
+		logger.Info("publishing clicks to event bus",
+			zap.String("driver", cfg.EventBus.Driver),
+			zap.String("topic", cfg.EventBus.Topic),
+		)
+	}
+	for _, sink := range clickSinks {
+		processor.AddClickSink(sink)
+	}
 
 	// 6b. Create and start webhook delivery processor
+	webhookHostPolicy, err := httputil.NewHostPolicy(cfg.Webhooks.AllowPrivateTargets, cfg.Webhooks.AllowedHosts)
+	if err != nil {
+		logger.Fatal("invalid webhook allowed hosts", zap.Error(err))
+	}
 	webhookProcessor := worker.NewWebhookDeliveryProcessor(
 		redisDB.Client(),
 		webhookRepo,
+		webhookHostPolicy,
+		logger,
+	)
+	webhookProcessor.SetLocker(locker)
+
+	// 6c. Create workspace export processor
+	exportProcessor := worker.NewWorkspaceExportProcessor(
+		redisDB.Client(),
+		service.NewWorkspaceExporter(
+			redisDB.Client(),
+			workspaceRepo,
+			linkRepo,
+			bioPageRepo,
+			domainRepo,
+			webhookRepo,
+			analyticsRepo,
+			objectStore,
+			cfg,
+			logger,
+		),
+		logger,
+	)
+
+	// 6d. Create bulk link job processor. Queued links are screened as in
+	// the API, so it needs the same destination screening providers.
+	urlChecker, err := safety.FromConfig(cfg.Safety, logger)
+	if err != nil {
+		logger.Fatal("failed to load destination blocklist", zap.Error(err))
+	}
+	bulkLinkProcessor := worker.NewBulkLinkJobProcessor(
+		redisDB.Client(),
+		service.NewBulkLinkJobRunner(
+			linkRepo,
+			workspaceRepo,
+			domainRepo,
+			linkTemplateRepo,
+			linkFlagRepo,
+			urlChecker,
+			pgDB.Pool(),
+			redisDB.Client(),
+			licManager,
+			cfg,
+			logger,
+		),
 		logger,
 	)
 
+	// 6e. Create the cleanup of deleted workspaces, which releases their
+	// domains through the same SSL provider as the API
+	cleanupProcessor := worker.NewWorkspaceCleanupProcessor(
+		redisDB.Client(),
+		service.NewWorkspaceCleaner(
+			workspaceRepo,
+			linkRepo,
+			qrRepo,
+			bioPageRepo,
+			domainRepo,
+			apiKeyRepo,
+			webhookRepo,
+			service.NewMockSSLProvider(),
+			objectStore,
+			logger,
+		),
+		logger,
+	)
+	cleanupProcessor.SetLocker(locker)
+
+	// 6f. Create usage snapshotter
+	usageSnapshotter := worker.NewUsageSnapshotter(
+		service.NewUsageService(usageRepo, licManager, logger),
+		cfg.License.UsageSnapshotInterval,
+		logger,
+	)
+	usageSnapshotter.SetLocker(locker)
+
+	// 6g. Create short code reservation releaser
+	reservationReleaser := worker.NewReservationReleaser(linkRepo, logger)
+	reservationReleaser.SetLocker(locker)
+
+	// 6h. Create the health checks behind link failover URLs. Destinations
+	// are probed through the SSRF-safe client, as in the API's destination
+	// check, and the results are read by the redirect service.
+	var failoverChecker *worker.FailoverHealthChecker
+	if cfg.Links.FailoverCheckInterval > 0 {
+		fetchPolicy, _ := httputil.NewHostPolicy(false, nil)
+		fetchConfig := httputil.DefaultSafeClientConfig()
+		fetchConfig.MaxRedirects = cfg.Fetch.MaxRedirects
+		fetchConfig.HopTimeout = cfg.Fetch.HopTimeout
+		failoverChecker = worker.NewFailoverHealthChecker(
+			linkRepo,
+			httputil.NewSafeClient(fetchPolicy, fetchConfig),
+			redirect.NewFailover(redisDB.Client(), logger),
+			cfg.Links.FailoverCheckInterval,
+			logger,
+		)
+		failoverChecker.SetLocker(locker)
+	}
+
+	// 6i. Create scheduled report runner, which needs SMTP to send reports
+	var reportRunner *worker.ScheduledReportRunner
+	if mailer, err := email.NewSMTPSender(cfg.SMTP); err != nil {
+		logger.Warn("SMTP not configured, scheduled reports will not be sent", zap.Error(err))
+	} else {
+		analyticsService := service.NewAnalyticsService(
+			analyticsBackends,
+			workspaceRepo,
+			clickRepo,
+			linkRepo,
+			repository.NewAnalyticsShareRepository(queries, logger),
+			cfg.App.SecretKey,
+			licManager,
+			logger,
+		)
+		reportRunner = worker.NewScheduledReportRunner(
+			service.NewScheduledReportSender(
+				scheduledReportRepo,
+				workspaceRepo,
+				analyticsService,
+				mailer,
+				licManager,
+				cfg.App.FrontendURL,
+				logger,
+			),
+			logger,
+		)
+		reportRunner.SetLocker(locker)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// 6j. Apply privacy, click counting and bot allowlist settings again on
+	// SIGHUP, without restarting
+	live := config.NewLive(cfg)
+	live.OnReload(func(c *config.Config) {
+		processor.SetIPAnonymization(c.Privacy.AnonymizeIP)
+		processor.SetUniqueClickWindow(c.Links.UniqueClickWindow)
+		processor.SetDuplicateClickWindow(c.Links.DuplicateClickWindow)
+		botDetector.SetAllowlist(c.Redirect.BotAllowlist)
+	})
+	live.ReloadOnSIGHUP(ctx, func(err error) {
+		if err != nil {
+			logger.Error("config reload failed, keeping current settings", zap.Error(err))
+			return
+		}
+		logger.Info("config reloaded")
+	})
+
 	go processor.Start(ctx)
 	go webhookProcessor.Start(ctx)
+	go exportProcessor.Start(ctx)
+	go bulkLinkProcessor.Start(ctx)
+	go cleanupProcessor.Start(ctx)
+	go usageSnapshotter.Start(ctx)
+	go reservationReleaser.Start(ctx)
+	if failoverChecker != nil {
+		go failoverChecker.Start(ctx)
+	}
+	if reportRunner != nil {
+		go reportRunner.Start(ctx)
+	}
 
-	logger.Info("worker started, processing click events and webhook deliveries")
+	logger.Info("worker started, processing click events, webhook deliveries, bulk link jobs, workspace exports and cleanups, usage snapshots, expired short code reservations, failover health checks and scheduled reports")
 
 	// 7. Wait for shutdown signal
 	quit := make(chan os.Signal, 1)
@@ -95,6 +364,17 @@ func main() {
 	logger.Info("shutting down worker...")
 	processor.Stop()
 	webhookProcessor.Stop()
+	exportProcessor.Stop()
+	bulkLinkProcessor.Stop()
+	cleanupProcessor.Stop()
+	usageSnapshotter.Stop()
+	reservationReleaser.Stop()
+	if failoverChecker != nil {
+		failoverChecker.Stop()
+	}
+	if reportRunner != nil {
+		reportRunner.Stop()
+	}
 	cancel()
 
 	logger.Info("worker stopped")
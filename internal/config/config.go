@@ -16,10 +16,20 @@ type Config struct {
 	Meilisearch MeilisearchConfig
 	Auth        AuthConfig
 	License     LicenseConfig
+	Links       LinksConfig
 	Redirect    RedirectConfig
+	QR          QRConfig
 	GeoIP       GeoIPConfig
+	Privacy     PrivacyConfig
+	Webhooks    WebhooksConfig
+	Realtime    RealtimeConfig
+	Safety      SafetyConfig
+	Fetch       FetchConfig
+	EventBus    EventBusConfig
+	Security    SecurityConfig
 	SMTP        SMTPConfig
 	S3          S3Config
+	Storage     StorageConfig
 	Log         LogConfig
 	RateLimit   RateLimitConfig
 }
@@ -32,13 +42,34 @@ type AppConfig struct {
 	RedirectURL string `mapstructure:"redirect_url"`
 	FrontendURL string `mapstructure:"frontend_url"`
 	SecretKey   string `mapstructure:"secret_key"`
+	// AdminEmails lists the users, by email, who administer this instance,
+	// such as reviewing flagged links.
+	AdminEmails []string `mapstructure:"admin_emails"`
 }
 
 type DatabaseConfig struct {
 	URL             string        `mapstructure:"url"`
+	// ReplicaURL is an optional read replica the redirect service resolves
+	// links from. Empty means reads go to the primary.
+	ReplicaURL      string        `mapstructure:"replica_url"`
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	// MinConns is how many connections the pool keeps open even when idle.
+	// When unset, MaxIdleConns is used, which older configs set for this.
+	MinConns        int           `mapstructure:"min_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+	// PoolStatsInterval is how often pool statistics are logged; 0 disables
+	// them.
+	PoolStatsInterval time.Duration `mapstructure:"pool_stats_interval"`
+}
+
+// PoolMinConns returns MinConns, or MaxIdleConns when MinConns is unset.
+func (c DatabaseConfig) PoolMinConns() int {
+	if c.MinConns > 0 {
+		return c.MinConns
+	}
+	return c.MaxIdleConns
 }
 
 type RedisConfig struct {
@@ -52,6 +83,14 @@ type ClickHouseConfig struct {
 	Database string `mapstructure:"database"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
+	// WorkspaceDefault routes workspaces that don't choose an analytics
+	// backend to ClickHouse. When false they read from PostgreSQL unless
+	// they opt in.
+	WorkspaceDefault bool `mapstructure:"workspace_default"`
+	// CircuitFailures consecutive failed queries make the API read analytics
+	// from PostgreSQL for CircuitCooldown before trying ClickHouse again.
+	CircuitFailures int           `mapstructure:"circuit_failures"`
+	CircuitCooldown time.Duration `mapstructure:"circuit_cooldown"`
 }
 
 type MeilisearchConfig struct {
@@ -63,12 +102,65 @@ type AuthConfig struct {
 	TokenSecret       string        `mapstructure:"token_secret"`
 	AccessTokenExpiry time.Duration `mapstructure:"access_token_expiry"`
 	RefreshTokenExpiry time.Duration `mapstructure:"refresh_token_expiry"`
+	// PreviousTokenSecrets still verify tokens signed before TokenSecret was
+	// rotated. New tokens are always signed with TokenSecret.
+	PreviousTokenSecrets []string `mapstructure:"previous_token_secrets"`
+
+	// Argon2id cost parameters for new password hashes. Memory is in KiB.
+	PasswordHashMemory      uint32 `mapstructure:"password_hash_memory"`
+	PasswordHashIterations  uint32 `mapstructure:"password_hash_iterations"`
+	PasswordHashParallelism uint8  `mapstructure:"password_hash_parallelism"`
 }
 
 type LicenseConfig struct {
 	Key           string        `mapstructure:"key"`
 	PublicKeyPath string        `mapstructure:"public_key_path"`
 	CheckInterval time.Duration `mapstructure:"check_interval"`
+	// CheckJitter adds a random delay of up to this much to each check
+	// interval, so that instances started together don't check at once.
+	CheckJitter time.Duration `mapstructure:"check_jitter"`
+	// UsageSnapshotInterval is how often the worker records each workspace's
+	// monthly usage for billing integrations.
+	UsageSnapshotInterval time.Duration `mapstructure:"usage_snapshot_interval"`
+}
+
+type LinksConfig struct {
+	ShortCodeMaxRetries    int `mapstructure:"short_code_max_retries"`
+	ShortCodeEscalateAfter int `mapstructure:"short_code_escalate_after"`
+	// MetadataRefreshCooldown is the minimum time between manual metadata
+	// refreshes of the same link.
+	MetadataRefreshCooldown time.Duration `mapstructure:"metadata_refresh_cooldown"`
+	// UniqueClickWindow is how long a visitor's repeat clicks on a link stop
+	// counting as unique. Each click restarts the window.
+	UniqueClickWindow time.Duration `mapstructure:"unique_click_window"`
+	// DuplicateClickWindow is how soon after a visitor's click on a link
+	// another click by them counts as the same one, e.g. a double tap. Each
+	// click restarts the window; 0 counts every click.
+	DuplicateClickWindow time.Duration `mapstructure:"duplicate_click_window"`
+	// CaseInsensitiveShortCodes lowercases short codes when links are created
+	// and looked up, and rejects codes that only differ in case from an
+	// existing one. Existing mixed-case codes must be lowercased first.
+	CaseInsensitiveShortCodes bool `mapstructure:"case_insensitive_short_codes"`
+	// BulkMaxLinks caps the number of links in one bulk create request;
+	// 0 means no cap.
+	BulkMaxLinks int `mapstructure:"bulk_max_links"`
+	// BulkAsyncThreshold is the largest bulk create handled within the
+	// request. Larger ones run as a background job, this many links at a
+	// time; 0 handles every bulk create within the request.
+	BulkAsyncThreshold int `mapstructure:"bulk_async_threshold"`
+	// FailoverCheckInterval is how often the worker checks the destinations
+	// of links with failover URLs. Redirects serve a failover URL only while
+	// the last check found the destination down; 0 turns the checks, and so
+	// failover, off.
+	FailoverCheckInterval time.Duration `mapstructure:"failover_check_interval"`
+}
+
+// NormalizeShortCode returns the form of code that is stored and looked up.
+func (c LinksConfig) NormalizeShortCode(code string) string {
+	if c.CaseInsensitiveShortCodes {
+		return strings.ToLower(code)
+	}
+	return code
 }
 
 type RedirectConfig struct {
@@ -77,12 +169,170 @@ type RedirectConfig struct {
 	RedisCacheTTL time.Duration `mapstructure:"redis_cache_ttl"`
 	TrackerBuffer int           `mapstructure:"tracker_buffer"`
 	TrackerFlush  time.Duration `mapstructure:"tracker_flush"`
+	// WarmCache preloads the WarmCacheLinks most clicked links into the
+	// cache at startup, so a deploy doesn't send every hot short code to the
+	// database at once.
+	WarmCache      bool `mapstructure:"warm_cache"`
+	WarmCacheLinks int  `mapstructure:"warm_cache_links"`
+	// FrameCheckTTL is how long a cloaked destination's framing probe result
+	// is reused before the headers are checked again.
+	FrameCheckTTL time.Duration `mapstructure:"frame_check_ttl"`
+	// HTTPSCheckTTL is how long a host's HTTPS probe result is reused for
+	// links that force HTTPS.
+	HTTPSCheckTTL time.Duration `mapstructure:"https_check_ttl"`
+	// RootURL, NotFoundURL and FaviconURL are redirect targets for the bare
+	// root, unknown paths and favicon.ico; empty serves a 404 instead.
+	RootURL     string `mapstructure:"root_url"`
+	NotFoundURL string `mapstructure:"not_found_url"`
+	FaviconURL  string `mapstructure:"favicon_url"`
+	// NotFoundMode is how unknown short codes are answered on hosts whose
+	// domain doesn't choose: NotFoundModePage, NotFoundModeRedirect (to
+	// NotFoundURL) or NotFoundModeStatus. Empty redirects when NotFoundURL
+	// is set and renders the page otherwise. NotFoundStatus overrides the
+	// mode's status code, 302 for redirects and 404 otherwise.
+	NotFoundMode   string `mapstructure:"not_found_mode"`
+	NotFoundStatus int    `mapstructure:"not_found_status"`
+	// RobotsTxtPath overrides the default robots.txt, which disallows all
+	// crawlers.
+	RobotsTxtPath string `mapstructure:"robots_txt_path"`
+	// BotAllowlist holds User-Agent substrings, matched case-insensitively,
+	// that are never treated as bots, e.g. an internal monitor whose clicks
+	// should be counted.
+	BotAllowlist []string `mapstructure:"bot_allowlist"`
+	// VisitorIdentity is how once-per-visitor links recognize a returning
+	// visitor: VisitorIdentityCookie or VisitorIdentityIP.
+	VisitorIdentity string `mapstructure:"visitor_identity"`
+	// InterstitialTemplatePath overrides the built-in interstitial page
+	// with an html/template file. InterstitialDelay is the countdown before
+	// continuing when the workspace doesn't set one, and
+	// InterstitialConsentTTL how long a visitor who continued skips the
+	// page for that link.
+	InterstitialTemplatePath string        `mapstructure:"interstitial_template_path"`
+	InterstitialDelay        time.Duration `mapstructure:"interstitial_delay"`
+	InterstitialConsentTTL   time.Duration `mapstructure:"interstitial_consent_ttl"`
+}
+
+// How unknown short codes are answered.
+const (
+	NotFoundModePage     = "page"
+	NotFoundModeRedirect = "redirect"
+	NotFoundModeStatus   = "status"
+)
+
+// How once-per-visitor links identify visitors.
+const (
+	VisitorIdentityCookie = "cookie"
+	VisitorIdentityIP     = "ip"
+)
+
+type QRConfig struct {
+	// BatchWorkers is how many QR codes a bulk request generates in parallel.
+	BatchWorkers int `mapstructure:"batch_workers"`
+	// BatchMaxItems caps the number of links in one bulk request.
+	BatchMaxItems int `mapstructure:"batch_max_items"`
 }
 
 type GeoIPConfig struct {
 	DatabasePath string `mapstructure:"database_path"`
 }
 
+type PrivacyConfig struct {
+	AnonymizeIP  bool   `mapstructure:"anonymize_ip"`
+	HonorOptOut  bool   `mapstructure:"honor_opt_out"`
+	OptOutCookie string `mapstructure:"opt_out_cookie"`
+}
+
+// WebhooksConfig controls which targets webhooks may be delivered to. By
+// default private, loopback and link-local addresses are rejected.
+// SecretRotationWindow is how long deliveries stay signed with the old secret
+// as well after a webhook's secret is rotated; zero retires it immediately.
+type WebhooksConfig struct {
+	AllowPrivateTargets  bool          `mapstructure:"allow_private_targets"`
+	AllowedHosts         []string      `mapstructure:"allowed_hosts"`
+	SecretRotationWindow time.Duration `mapstructure:"secret_rotation_window"`
+}
+
+// RealtimeConfig bounds the live analytics WebSocket connections of the API.
+// MaxConnections caps them across the server, and MaxConnectionsPerIP and
+// MaxConnectionsPerUser cap what one address or user can hold open; zero
+// means no cap. IdleTimeout is how long a connection may go without
+// answering a ping before it is closed. SnapshotInterval is how often
+// connected workspaces get their rolling click totals; zero turns the
+// snapshots off.
+type RealtimeConfig struct {
+	MaxConnections        int           `mapstructure:"max_connections"`
+	MaxConnectionsPerIP   int           `mapstructure:"max_connections_per_ip"`
+	MaxConnectionsPerUser int           `mapstructure:"max_connections_per_user"`
+	IdleTimeout           time.Duration `mapstructure:"idle_timeout"`
+	SnapshotInterval      time.Duration `mapstructure:"snapshot_interval"`
+}
+
+// Link destination screening modes.
+const (
+	SafetyModeOff   = "off"
+	SafetyModeFlag  = "flag"
+	SafetyModeBlock = "block"
+)
+
+// SafetyConfig controls screening of link destinations against malware and
+// phishing sources. In flag mode a matching link is created inactive and
+// queued for admin review; in block mode it is rejected.
+type SafetyConfig struct {
+	Mode string `mapstructure:"mode"`
+	// BlocklistPath is a file of blocked domains, one per line.
+	BlocklistPath string `mapstructure:"blocklist_path"`
+	// SafeBrowsingAPIKey turns on Google Safe Browsing lookups.
+	SafeBrowsingAPIKey string        `mapstructure:"safe_browsing_api_key"`
+	Timeout            time.Duration `mapstructure:"timeout"`
+	// ReportThreshold is the number of abuse reports, from different
+	// visitors, that disables a link until an admin reviews it.
+	ReportThreshold int `mapstructure:"report_threshold"`
+}
+
+// FetchConfig bounds the server-side requests made to user-supplied URLs:
+// destination checks, link previews, page metadata and QR logos. Zero
+// values use the safe client's defaults.
+type FetchConfig struct {
+	// MaxRedirects is how many redirects are followed before giving up.
+	MaxRedirects int `mapstructure:"max_redirects"`
+	// HopTimeout bounds each request while a redirect chain is followed.
+	HopTimeout time.Duration `mapstructure:"hop_timeout"`
+}
+
+// SecurityConfig hardens cookies and transport for the API and redirect
+// services.
+type SecurityConfig struct {
+	// SecureCookies marks cookies Secure, so browsers only send them over
+	// HTTPS. Turn it off for local development over plain HTTP.
+	SecureCookies bool `mapstructure:"secure_cookies"`
+	// ForceHTTPS redirects plain HTTP requests to HTTPS. A request counts as
+	// HTTPS when served over TLS or when a proxy sets X-Forwarded-Proto.
+	ForceHTTPS bool `mapstructure:"force_https"`
+	// HSTSMaxAge is the Strict-Transport-Security max-age sent on HTTPS
+	// responses when ForceHTTPS is on; zero sends no header.
+	HSTSMaxAge time.Duration `mapstructure:"hsts_max_age"`
+}
+
+// Event bus drivers for publishing clicks.
+const (
+	EventBusDriverNone  = ""
+	EventBusDriverNATS  = "nats"
+	EventBusDriverKafka = "kafka"
+)
+
+// EventBusConfig publishes every processed click as a JSON event to an
+// existing event bus, alongside ClickHouse. Kafka is reached through a REST
+// proxy.
+type EventBusConfig struct {
+	Driver string `mapstructure:"driver"`
+	// URL is the NATS server (nats://host:4222) or the Kafka REST proxy
+	// (http://host:8082).
+	URL string `mapstructure:"url"`
+	// Topic is the NATS subject or Kafka topic.
+	Topic   string        `mapstructure:"topic"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
 type SMTPConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
@@ -99,9 +349,23 @@ type S3Config struct {
 	Region    string `mapstructure:"region"`
 }
 
+// StorageConfig shapes object storage keys, for S3 and local storage alike.
+type StorageConfig struct {
+	// KeyPrefix is put in front of every key, so deployments can share a
+	// bucket.
+	KeyPrefix string `mapstructure:"key_prefix"`
+	// WorkspacePrefixes stores each workspace's QR codes, images and
+	// exports under workspaces/<id>/, so they can be lifecycle-managed and
+	// purged together.
+	WorkspacePrefixes bool `mapstructure:"workspace_prefixes"`
+}
+
 type LogConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
+	// AccessLevel is the level of the per-request access log entries, so
+	// they can be hidden without raising the level of everything else.
+	AccessLevel string `mapstructure:"access_level"`
 }
 
 type RateLimitConfig struct {
@@ -153,10 +417,15 @@ func bindEnvVars(v *viper.Viper) {
 	_ = v.BindEnv("app.redirect_url", "APP_REDIRECT_URL")
 	_ = v.BindEnv("app.frontend_url", "APP_FRONTEND_URL")
 	_ = v.BindEnv("app.secret_key", "APP_SECRET_KEY")
+	_ = v.BindEnv("app.admin_emails", "APP_ADMIN_EMAILS")
 	_ = v.BindEnv("database.url", "DATABASE_URL")
+	_ = v.BindEnv("database.replica_url", "DATABASE_REPLICA_URL")
 	_ = v.BindEnv("database.max_open_conns", "DATABASE_MAX_OPEN_CONNS")
+	_ = v.BindEnv("database.min_conns", "DATABASE_MIN_CONNS")
 	_ = v.BindEnv("database.max_idle_conns", "DATABASE_MAX_IDLE_CONNS")
 	_ = v.BindEnv("database.conn_max_lifetime", "DATABASE_CONN_MAX_LIFETIME")
+	_ = v.BindEnv("database.conn_max_idle_time", "DATABASE_CONN_MAX_IDLE_TIME")
+	_ = v.BindEnv("database.pool_stats_interval", "DATABASE_POOL_STATS_INTERVAL")
 	_ = v.BindEnv("redis.url", "REDIS_URL")
 	_ = v.BindEnv("redis.password", "REDIS_PASSWORD")
 	_ = v.BindEnv("redis.db", "REDIS_DB")
@@ -164,20 +433,80 @@ func bindEnvVars(v *viper.Viper) {
 	_ = v.BindEnv("clickhouse.database", "CLICKHOUSE_DATABASE")
 	_ = v.BindEnv("clickhouse.user", "CLICKHOUSE_USER")
 	_ = v.BindEnv("clickhouse.password", "CLICKHOUSE_PASSWORD")
+	_ = v.BindEnv("clickhouse.workspace_default", "CLICKHOUSE_WORKSPACE_DEFAULT")
+	_ = v.BindEnv("clickhouse.circuit_failures", "CLICKHOUSE_CIRCUIT_FAILURES")
+	_ = v.BindEnv("clickhouse.circuit_cooldown", "CLICKHOUSE_CIRCUIT_COOLDOWN")
 	_ = v.BindEnv("meilisearch.url", "MEILISEARCH_URL")
 	_ = v.BindEnv("meilisearch.api_key", "MEILISEARCH_API_KEY")
 	_ = v.BindEnv("auth.token_secret", "AUTH_TOKEN_SECRET")
+	_ = v.BindEnv("auth.previous_token_secrets", "AUTH_PREVIOUS_TOKEN_SECRETS")
 	_ = v.BindEnv("auth.access_token_expiry", "AUTH_ACCESS_TOKEN_EXPIRY")
 	_ = v.BindEnv("auth.refresh_token_expiry", "AUTH_REFRESH_TOKEN_EXPIRY")
+	_ = v.BindEnv("auth.password_hash_memory", "AUTH_PASSWORD_HASH_MEMORY")
+	_ = v.BindEnv("auth.password_hash_iterations", "AUTH_PASSWORD_HASH_ITERATIONS")
+	_ = v.BindEnv("auth.password_hash_parallelism", "AUTH_PASSWORD_HASH_PARALLELISM")
 	_ = v.BindEnv("license.key", "LICENSE_KEY")
 	_ = v.BindEnv("license.public_key_path", "LICENSE_PUBLIC_KEY_PATH")
 	_ = v.BindEnv("license.check_interval", "LICENSE_CHECK_INTERVAL")
+	_ = v.BindEnv("license.check_jitter", "LICENSE_CHECK_JITTER")
+	_ = v.BindEnv("license.usage_snapshot_interval", "LICENSE_USAGE_SNAPSHOT_INTERVAL")
+	_ = v.BindEnv("links.short_code_max_retries", "LINKS_SHORT_CODE_MAX_RETRIES")
+	_ = v.BindEnv("links.short_code_escalate_after", "LINKS_SHORT_CODE_ESCALATE_AFTER")
+	_ = v.BindEnv("links.metadata_refresh_cooldown", "LINKS_METADATA_REFRESH_COOLDOWN")
+	_ = v.BindEnv("links.unique_click_window", "LINKS_UNIQUE_CLICK_WINDOW")
+	_ = v.BindEnv("links.duplicate_click_window", "LINKS_DUPLICATE_CLICK_WINDOW")
+	_ = v.BindEnv("links.case_insensitive_short_codes", "LINKS_CASE_INSENSITIVE_SHORT_CODES")
+	_ = v.BindEnv("links.bulk_max_links", "LINKS_BULK_MAX_LINKS")
+	_ = v.BindEnv("links.bulk_async_threshold", "LINKS_BULK_ASYNC_THRESHOLD")
+	_ = v.BindEnv("links.failover_check_interval", "LINKS_FAILOVER_CHECK_INTERVAL")
+	_ = v.BindEnv("qr.batch_workers", "QR_BATCH_WORKERS")
+	_ = v.BindEnv("qr.batch_max_items", "QR_BATCH_MAX_ITEMS")
 	_ = v.BindEnv("redirect.port", "REDIRECT_PORT")
 	_ = v.BindEnv("redirect.local_cache_ttl", "REDIRECT_LOCAL_CACHE_TTL")
 	_ = v.BindEnv("redirect.redis_cache_ttl", "REDIRECT_REDIS_CACHE_TTL")
 	_ = v.BindEnv("redirect.tracker_buffer", "REDIRECT_TRACKER_BUFFER")
 	_ = v.BindEnv("redirect.tracker_flush", "REDIRECT_TRACKER_FLUSH")
+	_ = v.BindEnv("redirect.warm_cache", "REDIRECT_WARM_CACHE")
+	_ = v.BindEnv("redirect.warm_cache_links", "REDIRECT_WARM_CACHE_LINKS")
+	_ = v.BindEnv("redirect.frame_check_ttl", "REDIRECT_FRAME_CHECK_TTL")
+	_ = v.BindEnv("redirect.https_check_ttl", "REDIRECT_HTTPS_CHECK_TTL")
+	_ = v.BindEnv("redirect.root_url", "REDIRECT_ROOT_URL")
+	_ = v.BindEnv("redirect.not_found_url", "REDIRECT_NOT_FOUND_URL")
+	_ = v.BindEnv("redirect.not_found_mode", "REDIRECT_NOT_FOUND_MODE")
+	_ = v.BindEnv("redirect.not_found_status", "REDIRECT_NOT_FOUND_STATUS")
+	_ = v.BindEnv("redirect.favicon_url", "REDIRECT_FAVICON_URL")
+	_ = v.BindEnv("redirect.robots_txt_path", "REDIRECT_ROBOTS_TXT_PATH")
+	_ = v.BindEnv("redirect.bot_allowlist", "REDIRECT_BOT_ALLOWLIST")
+	_ = v.BindEnv("redirect.visitor_identity", "REDIRECT_VISITOR_IDENTITY")
+	_ = v.BindEnv("redirect.interstitial_template_path", "REDIRECT_INTERSTITIAL_TEMPLATE_PATH")
+	_ = v.BindEnv("redirect.interstitial_delay", "REDIRECT_INTERSTITIAL_DELAY")
+	_ = v.BindEnv("redirect.interstitial_consent_ttl", "REDIRECT_INTERSTITIAL_CONSENT_TTL")
 	_ = v.BindEnv("geoip.database_path", "GEOIP_DATABASE_PATH")
+	_ = v.BindEnv("privacy.anonymize_ip", "PRIVACY_ANONYMIZE_IP")
+	_ = v.BindEnv("privacy.honor_opt_out", "PRIVACY_HONOR_OPT_OUT")
+	_ = v.BindEnv("privacy.opt_out_cookie", "PRIVACY_OPT_OUT_COOKIE")
+	_ = v.BindEnv("webhooks.allow_private_targets", "WEBHOOKS_ALLOW_PRIVATE_TARGETS")
+	_ = v.BindEnv("webhooks.allowed_hosts", "WEBHOOKS_ALLOWED_HOSTS")
+	_ = v.BindEnv("webhooks.secret_rotation_window", "WEBHOOKS_SECRET_ROTATION_WINDOW")
+	_ = v.BindEnv("realtime.max_connections", "REALTIME_MAX_CONNECTIONS")
+	_ = v.BindEnv("realtime.max_connections_per_ip", "REALTIME_MAX_CONNECTIONS_PER_IP")
+	_ = v.BindEnv("realtime.max_connections_per_user", "REALTIME_MAX_CONNECTIONS_PER_USER")
+	_ = v.BindEnv("realtime.idle_timeout", "REALTIME_IDLE_TIMEOUT")
+	_ = v.BindEnv("realtime.snapshot_interval", "REALTIME_SNAPSHOT_INTERVAL")
+	_ = v.BindEnv("safety.mode", "SAFETY_MODE")
+	_ = v.BindEnv("safety.blocklist_path", "SAFETY_BLOCKLIST_PATH")
+	_ = v.BindEnv("safety.safe_browsing_api_key", "SAFETY_SAFE_BROWSING_API_KEY")
+	_ = v.BindEnv("safety.timeout", "SAFETY_TIMEOUT")
+	_ = v.BindEnv("safety.report_threshold", "SAFETY_REPORT_THRESHOLD")
+	_ = v.BindEnv("fetch.max_redirects", "FETCH_MAX_REDIRECTS")
+	_ = v.BindEnv("fetch.hop_timeout", "FETCH_HOP_TIMEOUT")
+	_ = v.BindEnv("security.secure_cookies", "SECURE_COOKIES")
+	_ = v.BindEnv("security.force_https", "FORCE_HTTPS")
+	_ = v.BindEnv("security.hsts_max_age", "HSTS_MAX_AGE")
+	_ = v.BindEnv("eventbus.driver", "EVENT_BUS_DRIVER")
+	_ = v.BindEnv("eventbus.url", "EVENT_BUS_URL")
+	_ = v.BindEnv("eventbus.topic", "EVENT_BUS_TOPIC")
+	_ = v.BindEnv("eventbus.timeout", "EVENT_BUS_TIMEOUT")
 	_ = v.BindEnv("smtp.host", "SMTP_HOST")
 	_ = v.BindEnv("smtp.port", "SMTP_PORT")
 	_ = v.BindEnv("smtp.user", "SMTP_USER")
@@ -188,8 +517,11 @@ func bindEnvVars(v *viper.Viper) {
 	_ = v.BindEnv("s3.access_key", "S3_ACCESS_KEY")
 	_ = v.BindEnv("s3.secret_key", "S3_SECRET_KEY")
 	_ = v.BindEnv("s3.region", "S3_REGION")
+	_ = v.BindEnv("storage.key_prefix", "STORAGE_KEY_PREFIX")
+	_ = v.BindEnv("storage.workspace_prefixes", "STORAGE_WORKSPACE_PREFIXES")
 	_ = v.BindEnv("log.level", "LOG_LEVEL")
 	_ = v.BindEnv("log.format", "LOG_FORMAT")
+	_ = v.BindEnv("log.access_level", "LOG_ACCESS_LEVEL")
 	_ = v.BindEnv("ratelimit.requests", "RATE_LIMIT_REQUESTS")
 	_ = v.BindEnv("ratelimit.window", "RATE_LIMIT_WINDOW")
 }
@@ -204,23 +536,81 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_open_conns", 25)
 	v.SetDefault("database.max_idle_conns", 10)
 	v.SetDefault("database.conn_max_lifetime", "5m")
+	v.SetDefault("database.conn_max_idle_time", "5m")
+	v.SetDefault("database.pool_stats_interval", "1m")
 	v.SetDefault("redis.db", 0)
 	v.SetDefault("clickhouse.database", "linkrift_analytics")
+	v.SetDefault("clickhouse.workspace_default", true)
+	v.SetDefault("clickhouse.circuit_failures", 5)
+	v.SetDefault("clickhouse.circuit_cooldown", "30s")
 	v.SetDefault("auth.access_token_expiry", "15m")
 	v.SetDefault("auth.refresh_token_expiry", "168h")
+	v.SetDefault("auth.password_hash_memory", 65536)
+	v.SetDefault("auth.password_hash_iterations", 3)
+	v.SetDefault("auth.password_hash_parallelism", 2)
 	v.SetDefault("license.check_interval", "1h")
+	v.SetDefault("license.check_jitter", "5m")
+	v.SetDefault("license.usage_snapshot_interval", "1h")
+	v.SetDefault("links.short_code_max_retries", 10)
+	v.SetDefault("links.short_code_escalate_after", 3)
+	v.SetDefault("links.metadata_refresh_cooldown", "1m")
+	v.SetDefault("links.unique_click_window", "24h")
+	v.SetDefault("links.duplicate_click_window", "2s")
+	v.SetDefault("links.case_insensitive_short_codes", false)
+	v.SetDefault("links.bulk_max_links", 10000)
+	v.SetDefault("links.bulk_async_threshold", 100)
+	v.SetDefault("links.failover_check_interval", "1m")
+	v.SetDefault("qr.batch_workers", 4)
+	v.SetDefault("qr.batch_max_items", 500)
 	v.SetDefault("redirect.port", 8081)
 	v.SetDefault("redirect.local_cache_ttl", "5m")
 	v.SetDefault("redirect.redis_cache_ttl", "1h")
 	v.SetDefault("redirect.tracker_buffer", 10000)
 	v.SetDefault("redirect.tracker_flush", "100ms")
+	v.SetDefault("redirect.warm_cache", false)
+	v.SetDefault("redirect.warm_cache_links", 1000)
+	v.SetDefault("redirect.frame_check_ttl", "1h")
+	v.SetDefault("redirect.https_check_ttl", "1h")
+	v.SetDefault("redirect.visitor_identity", "cookie")
+	v.SetDefault("redirect.root_url", "")
+	v.SetDefault("redirect.not_found_url", "")
+	v.SetDefault("redirect.not_found_mode", "")
+	v.SetDefault("redirect.not_found_status", 0)
+	v.SetDefault("redirect.favicon_url", "")
+	v.SetDefault("redirect.robots_txt_path", "")
+	v.SetDefault("redirect.interstitial_template_path", "")
+	v.SetDefault("redirect.interstitial_delay", "5s")
+	v.SetDefault("redirect.interstitial_consent_ttl", "1h")
+	v.SetDefault("privacy.anonymize_ip", false)
+	v.SetDefault("privacy.honor_opt_out", false)
+	v.SetDefault("privacy.opt_out_cookie", "lr_optout")
+	v.SetDefault("webhooks.allow_private_targets", false)
+	v.SetDefault("webhooks.secret_rotation_window", "24h")
+	v.SetDefault("realtime.max_connections", 10000)
+	v.SetDefault("realtime.max_connections_per_ip", 20)
+	v.SetDefault("realtime.max_connections_per_user", 10)
+	v.SetDefault("realtime.idle_timeout", "60s")
+	v.SetDefault("realtime.snapshot_interval", "5s")
+	v.SetDefault("safety.mode", "off")
+	v.SetDefault("safety.timeout", "3s")
+	v.SetDefault("safety.report_threshold", 5)
+	v.SetDefault("fetch.max_redirects", 5)
+	v.SetDefault("fetch.hop_timeout", "3s")
+	v.SetDefault("security.secure_cookies", true)
+	v.SetDefault("security.force_https", false)
+	v.SetDefault("security.hsts_max_age", "8760h")
+	v.SetDefault("eventbus.topic", "linkrift.clicks")
+	v.SetDefault("eventbus.timeout", "5s")
 	v.SetDefault("smtp.host", "localhost")
 	v.SetDefault("smtp.port", 1025)
 	v.SetDefault("smtp.from", "noreply@linkrift.io")
 	v.SetDefault("s3.region", "us-east-1")
 	v.SetDefault("s3.bucket", "linkrift")
+	v.SetDefault("storage.key_prefix", "")
+	v.SetDefault("storage.workspace_prefixes", false)
 	v.SetDefault("log.level", "debug")
 	v.SetDefault("log.format", "console")
+	v.SetDefault("log.access_level", "info")
 	v.SetDefault("ratelimit.requests", 100)
 	v.SetDefault("ratelimit.window", "1m")
 }
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Live holds the running configuration and swaps in reloaded settings
+// atomically, so readers never see a half-applied reload. Only the settings
+// copied by applyHotReload change; connection, listener and worker pool
+// settings keep their startup values until a restart.
+type Live struct {
+	cfg atomic.Pointer[Config]
+
+	mu       sync.Mutex // serializes reloads and guards onReload
+	onReload []func(*Config)
+}
+
+// NewLive wraps a loaded and validated configuration.
+func NewLive(cfg *Config) *Live {
+	l := &Live{}
+	l.cfg.Store(cfg)
+	return l
+}
+
+// Get returns the current configuration. Callers must not modify it.
+func (l *Live) Get() *Config {
+	return l.cfg.Load()
+}
+
+// OnReload registers fn to run with the new configuration after each
+// successful reload, for components that copy settings at construction.
+func (l *Live) OnReload(fn func(*Config)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onReload = append(l.onReload, fn)
+}
+
+// Reload reads the configuration again and applies its hot-reloadable
+// settings. If loading or validation fails the current configuration is kept.
+func (l *Live) Reload() error {
+	next, err := Load()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	merged := *l.cfg.Load()
+	merged.applyHotReload(next)
+	if err := merged.Validate(); err != nil {
+		return err
+	}
+	l.cfg.Store(&merged)
+
+	for _, fn := range l.onReload {
+		fn(&merged)
+	}
+	return nil
+}
+
+// ReloadOnSIGHUP reloads the configuration each time the process receives
+// SIGHUP until ctx is done. report is called with the result of every reload.
+func (l *Live) ReloadOnSIGHUP(ctx context.Context, report func(error)) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sig:
+				report(l.Reload())
+			}
+		}
+	}()
+}
+
+// applyHotReload copies the settings that can change without a restart from
+// next. Keep the list in docs/operations/MAINTENANCE.md in sync.
+func (c *Config) applyHotReload(next *Config) {
+	c.Redirect.LocalCacheTTL = next.Redirect.LocalCacheTTL
+	c.Redirect.RedisCacheTTL = next.Redirect.RedisCacheTTL
+	c.Redirect.FrameCheckTTL = next.Redirect.FrameCheckTTL
+	c.Redirect.HTTPSCheckTTL = next.Redirect.HTTPSCheckTTL
+	c.Redirect.BotAllowlist = next.Redirect.BotAllowlist
+	c.Privacy = next.Privacy
+	c.Links.UniqueClickWindow = next.Links.UniqueClickWindow
+	c.Links.DuplicateClickWindow = next.Links.DuplicateClickWindow
+}
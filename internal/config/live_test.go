@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLiveReload(t *testing.T) {
+	cfg := validConfig()
+	live := NewLive(cfg)
+
+	var reloaded *Config
+	live.OnReload(func(c *Config) { reloaded = c })
+
+	t.Setenv("REDIRECT_LOCAL_CACHE_TTL", "2m")
+	t.Setenv("REDIRECT_BOT_ALLOWLIST", "uptimerobot,statuscake")
+	t.Setenv("DATABASE_URL", "postgres://elsewhere/linkrift")
+	if err := live.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	got := live.Get()
+	if got.Redirect.LocalCacheTTL != 2*time.Minute {
+		t.Errorf("LocalCacheTTL = %v, want 2m", got.Redirect.LocalCacheTTL)
+	}
+	if len(got.Redirect.BotAllowlist) != 2 {
+		t.Errorf("BotAllowlist = %q, want 2 entries", got.Redirect.BotAllowlist)
+	}
+	if got.Database.URL != cfg.Database.URL {
+		t.Errorf("Database.URL = %q, restart-only settings must not change", got.Database.URL)
+	}
+	if reloaded != got {
+		t.Error("OnReload callback did not get the new configuration")
+	}
+	if cfg.Redirect.LocalCacheTTL != time.Minute {
+		t.Error("the previous configuration must not be modified")
+	}
+}
+
+func TestLiveReloadKeepsConfigOnError(t *testing.T) {
+	live := NewLive(validConfig())
+	before := live.Get()
+
+	t.Setenv("REDIRECT_LOCAL_CACHE_TTL", "-1s")
+	if err := live.Reload(); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if live.Get() != before {
+		t.Error("a failed reload must keep the current configuration")
+	}
+}
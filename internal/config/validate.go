@@ -0,0 +1,312 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// minTokenSecretLength is the PASETO v4 symmetric key size; shorter secrets
+// are rejected when the token maker is created.
+const minTokenSecretLength = 32
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks that required settings are present and well-formed,
+// including the settings of optional services that are turned on. It returns
+// a *ValidationError listing all problems, or nil.
+func (c *Config) Validate() error {
+	var v validator
+
+	switch c.App.Env {
+	case "development", "staging", "production":
+	default:
+		v.addf("APP_ENV must be development, staging or production, got %q", c.App.Env)
+	}
+	v.port("APP_PORT", c.App.Port)
+	v.httpURL("APP_BASE_URL", c.App.BaseURL)
+	v.httpURL("APP_REDIRECT_URL", c.App.RedirectURL)
+	v.httpURL("APP_FRONTEND_URL", c.App.FrontendURL)
+	if c.App.Env == "production" && c.App.SecretKey == "" {
+		v.add("APP_SECRET_KEY is required in production")
+	}
+
+	v.required("DATABASE_URL", c.Database.URL)
+	if c.Database.MaxOpenConns <= 0 {
+		v.add("DATABASE_MAX_OPEN_CONNS must be positive")
+	} else if minConns := c.Database.PoolMinConns(); minConns > c.Database.MaxOpenConns {
+		v.addf("DATABASE_MIN_CONNS (%d) must not exceed DATABASE_MAX_OPEN_CONNS (%d)", minConns, c.Database.MaxOpenConns)
+	}
+	if c.Database.PoolStatsInterval < 0 {
+		v.add("DATABASE_POOL_STATS_INTERVAL must not be negative")
+	}
+	v.required("REDIS_URL", c.Redis.URL)
+
+	switch {
+	case c.Auth.TokenSecret == "":
+		v.add("AUTH_TOKEN_SECRET is required")
+	case len(c.Auth.TokenSecret) < minTokenSecretLength:
+		v.addf("AUTH_TOKEN_SECRET must be at least %d characters, got %d", minTokenSecretLength, len(c.Auth.TokenSecret))
+	}
+	for i, secret := range c.Auth.PreviousTokenSecrets {
+		if len(secret) < minTokenSecretLength {
+			v.addf("AUTH_PREVIOUS_TOKEN_SECRETS entry %d must be at least %d characters, got %d", i+1, minTokenSecretLength, len(secret))
+		}
+	}
+	if c.Auth.AccessTokenExpiry <= 0 {
+		v.add("AUTH_ACCESS_TOKEN_EXPIRY must be positive")
+	}
+	if c.Auth.RefreshTokenExpiry <= 0 {
+		v.add("AUTH_REFRESH_TOKEN_EXPIRY must be positive")
+	}
+
+	if c.ClickHouse.URL != "" {
+		v.parsedURL("CLICKHOUSE_URL", c.ClickHouse.URL)
+		v.required("CLICKHOUSE_DATABASE", c.ClickHouse.Database)
+		v.required("CLICKHOUSE_USER", c.ClickHouse.User)
+		v.required("CLICKHOUSE_PASSWORD", c.ClickHouse.Password)
+		if c.ClickHouse.CircuitFailures <= 0 {
+			v.add("CLICKHOUSE_CIRCUIT_FAILURES must be positive")
+		}
+		if c.ClickHouse.CircuitCooldown <= 0 {
+			v.add("CLICKHOUSE_CIRCUIT_COOLDOWN must be positive")
+		}
+	}
+
+	if c.Meilisearch.URL != "" {
+		v.httpURL("MEILISEARCH_URL", c.Meilisearch.URL)
+	}
+
+	if c.License.Key != "" && c.License.CheckInterval <= 0 {
+		v.add("LICENSE_CHECK_INTERVAL must be positive when LICENSE_KEY is set")
+	}
+	if c.License.CheckJitter < 0 {
+		v.add("LICENSE_CHECK_JITTER must not be negative")
+	}
+	if c.License.UsageSnapshotInterval <= 0 {
+		v.add("LICENSE_USAGE_SNAPSHOT_INTERVAL must be positive")
+	}
+
+	if c.Links.DuplicateClickWindow < 0 {
+		v.add("LINKS_DUPLICATE_CLICK_WINDOW must not be negative")
+	}
+	if c.Links.BulkMaxLinks < 0 {
+		v.add("LINKS_BULK_MAX_LINKS must not be negative")
+	}
+	if c.Links.BulkAsyncThreshold < 0 {
+		v.add("LINKS_BULK_ASYNC_THRESHOLD must not be negative")
+	}
+	if c.Links.FailoverCheckInterval < 0 {
+		v.add("LINKS_FAILOVER_CHECK_INTERVAL must not be negative")
+	}
+
+	v.port("REDIRECT_PORT", c.Redirect.Port)
+	if c.Redirect.LocalCacheTTL <= 0 {
+		v.add("REDIRECT_LOCAL_CACHE_TTL must be positive")
+	}
+	if c.Redirect.RedisCacheTTL <= 0 {
+		v.add("REDIRECT_REDIS_CACHE_TTL must be positive")
+	}
+	if c.Redirect.FrameCheckTTL <= 0 {
+		v.add("REDIRECT_FRAME_CHECK_TTL must be positive")
+	}
+	if c.Redirect.HTTPSCheckTTL <= 0 {
+		v.add("REDIRECT_HTTPS_CHECK_TTL must be positive")
+	}
+	if c.Redirect.TrackerBuffer <= 0 {
+		v.add("REDIRECT_TRACKER_BUFFER must be positive")
+	}
+	if c.Redirect.TrackerFlush <= 0 {
+		v.add("REDIRECT_TRACKER_FLUSH must be positive")
+	}
+	if c.Redirect.WarmCache && c.Redirect.WarmCacheLinks <= 0 {
+		v.add("REDIRECT_WARM_CACHE_LINKS must be positive when REDIRECT_WARM_CACHE is on")
+	}
+	switch c.Redirect.NotFoundMode {
+	case "":
+	case NotFoundModePage, NotFoundModeStatus:
+		if s := c.Redirect.NotFoundStatus; s != 0 && s != 404 && s != 410 {
+			v.addf("REDIRECT_NOT_FOUND_STATUS must be 404 or 410 for the %s mode, got %d", c.Redirect.NotFoundMode, s)
+		}
+	case NotFoundModeRedirect:
+		if c.Redirect.NotFoundURL == "" {
+			v.add("REDIRECT_NOT_FOUND_URL is required when REDIRECT_NOT_FOUND_MODE is redirect")
+		}
+		if s := c.Redirect.NotFoundStatus; s != 0 && s != 301 && s != 302 && s != 307 && s != 308 {
+			v.addf("REDIRECT_NOT_FOUND_STATUS must be 301, 302, 307 or 308 for the redirect mode, got %d", s)
+		}
+	default:
+		v.addf("REDIRECT_NOT_FOUND_MODE must be page, redirect or status, got %q", c.Redirect.NotFoundMode)
+	}
+	switch c.Redirect.VisitorIdentity {
+	case VisitorIdentityCookie, VisitorIdentityIP:
+	default:
+		v.addf("REDIRECT_VISITOR_IDENTITY must be cookie or ip, got %q", c.Redirect.VisitorIdentity)
+	}
+
+	switch c.Safety.Mode {
+	case SafetyModeOff:
+	case SafetyModeFlag, SafetyModeBlock:
+		if c.Safety.BlocklistPath == "" && c.Safety.SafeBrowsingAPIKey == "" {
+			v.addf("SAFETY_MODE %s needs SAFETY_BLOCKLIST_PATH or SAFETY_SAFE_BROWSING_API_KEY", c.Safety.Mode)
+		}
+		if c.Safety.Timeout <= 0 {
+			v.add("SAFETY_TIMEOUT must be positive")
+		}
+	default:
+		v.addf("SAFETY_MODE must be off, flag or block, got %q", c.Safety.Mode)
+	}
+	if c.Safety.ReportThreshold <= 0 {
+		v.add("SAFETY_REPORT_THRESHOLD must be positive")
+	}
+	if c.Webhooks.SecretRotationWindow < 0 {
+		v.add("WEBHOOKS_SECRET_ROTATION_WINDOW must not be negative")
+	}
+
+	if c.Realtime.MaxConnections < 0 {
+		v.add("REALTIME_MAX_CONNECTIONS must not be negative")
+	}
+	if c.Realtime.MaxConnectionsPerIP < 0 {
+		v.add("REALTIME_MAX_CONNECTIONS_PER_IP must not be negative")
+	}
+	if c.Realtime.MaxConnectionsPerUser < 0 {
+		v.add("REALTIME_MAX_CONNECTIONS_PER_USER must not be negative")
+	}
+	if c.Realtime.IdleTimeout <= 0 {
+		v.add("REALTIME_IDLE_TIMEOUT must be positive")
+	}
+	if c.Realtime.SnapshotInterval < 0 {
+		v.add("REALTIME_SNAPSHOT_INTERVAL must not be negative")
+	}
+
+	switch c.EventBus.Driver {
+	case EventBusDriverNone:
+	case EventBusDriverNATS, EventBusDriverKafka:
+		if u := v.parsedURL("EVENT_BUS_URL", c.EventBus.URL); u != nil {
+			switch {
+			case c.EventBus.Driver == EventBusDriverNATS && u.Scheme != "nats":
+				v.addf("EVENT_BUS_URL must be a nats:// URL for the nats driver, got %q", c.EventBus.URL)
+			case c.EventBus.Driver == EventBusDriverKafka && u.Scheme != "http" && u.Scheme != "https":
+				v.addf("EVENT_BUS_URL must be the http(s) URL of a Kafka REST proxy for the kafka driver, got %q", c.EventBus.URL)
+			}
+		}
+		v.required("EVENT_BUS_TOPIC", c.EventBus.Topic)
+		if c.EventBus.Timeout <= 0 {
+			v.add("EVENT_BUS_TIMEOUT must be positive")
+		}
+	default:
+		v.addf("EVENT_BUS_DRIVER must be empty, nats or kafka, got %q", c.EventBus.Driver)
+	}
+
+	if c.Security.HSTSMaxAge < 0 {
+		v.add("HSTS_MAX_AGE must not be negative")
+	}
+
+	if c.SMTP.Host != "" {
+		v.port("SMTP_PORT", c.SMTP.Port)
+		v.required("SMTP_FROM", c.SMTP.From)
+	}
+
+	if c.S3.Endpoint != "" {
+		v.httpURL("S3_ENDPOINT", c.S3.Endpoint)
+		v.required("S3_BUCKET", c.S3.Bucket)
+		v.required("S3_REGION", c.S3.Region)
+		v.required("S3_ACCESS_KEY", c.S3.AccessKey)
+		v.required("S3_SECRET_KEY", c.S3.SecretKey)
+	}
+	if !validKeyPrefix(c.Storage.KeyPrefix) {
+		v.addf("STORAGE_KEY_PREFIX must be path segments of letters, digits, '.', '_' and '-', got %q", c.Storage.KeyPrefix)
+	}
+
+	if _, err := zapcore.ParseLevel(c.Log.AccessLevel); err != nil {
+		v.addf("LOG_ACCESS_LEVEL must be debug, info, warn or error, got %q", c.Log.AccessLevel)
+	}
+
+	if c.RateLimit.Requests <= 0 {
+		v.add("RATE_LIMIT_REQUESTS must be positive")
+	}
+	if c.RateLimit.Window <= 0 {
+		v.add("RATE_LIMIT_WINDOW must be positive")
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validator collects problems so that all of them are reported at once.
+type validator struct {
+	problems []string
+}
+
+func (v *validator) add(msg string) {
+	v.problems = append(v.problems, msg)
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.add(fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(name, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s is required", name)
+	}
+}
+
+func (v *validator) port(name string, port int) {
+	if port < 1 || port > 65535 {
+		v.addf("%s must be between 1 and 65535, got %d", name, port)
+	}
+}
+
+// parsedURL checks that value is an absolute URL with a host.
+func (v *validator) parsedURL(name, value string) *url.URL {
+	if value == "" {
+		v.addf("%s is required", name)
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		v.addf("%s must be an absolute URL, got %q", name, value)
+		return nil
+	}
+	return u
+}
+
+// httpURL checks that value is an absolute http or https URL.
+func (v *validator) httpURL(name, value string) {
+	u := v.parsedURL(name, value)
+	if u != nil && u.Scheme != "http" && u.Scheme != "https" {
+		v.addf("%s must use http or https, got %q", name, value)
+	}
+}
+
+// validKeyPrefix reports whether prefix is empty or slash-separated path
+// segments that are safe both as object keys and as local directories.
+func validKeyPrefix(prefix string) bool {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return true
+	}
+	for _, segment := range strings.Split(prefix, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() *Config {
+	return &Config{
+		App: AppConfig{
+			Env:         "development",
+			Port:        8080,
+			BaseURL:     "http://localhost:8080",
+			RedirectURL: "http://localhost:8081",
+			FrontendURL: "http://localhost:3000",
+		},
+		Database: DatabaseConfig{URL: "postgres://localhost/linkrift", MaxOpenConns: 25, MaxIdleConns: 10},
+		Redis:    RedisConfig{URL: "redis://localhost:6379"},
+		Auth:     AuthConfig{TokenSecret: strings.Repeat("s", 32), AccessTokenExpiry: time.Minute, RefreshTokenExpiry: time.Hour},
+		License:  LicenseConfig{UsageSnapshotInterval: time.Hour},
+		Redirect: RedirectConfig{
+			Port: 8081, LocalCacheTTL: time.Minute, RedisCacheTTL: time.Hour, FrameCheckTTL: time.Hour, HTTPSCheckTTL: time.Hour,
+			TrackerBuffer: 100, TrackerFlush: time.Second, VisitorIdentity: VisitorIdentityCookie,
+		},
+		Realtime:  RealtimeConfig{IdleTimeout: time.Minute},
+		RateLimit: RateLimitConfig{Requests: 100, Window: time.Minute},
+		Safety:    SafetyConfig{Mode: SafetyModeOff, ReportThreshold: 5},
+		Log:       LogConfig{AccessLevel: "info"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string
+	}{
+		{"missing token secret", func(c *Config) { c.Auth.TokenSecret = "" }, "AUTH_TOKEN_SECRET is required"},
+		{"short token secret", func(c *Config) { c.Auth.TokenSecret = "short" }, "at least 32 characters"},
+		{"short previous token secret", func(c *Config) {
+			c.Auth.PreviousTokenSecrets = []string{strings.Repeat("p", 32), "short"}
+		}, "AUTH_PREVIOUS_TOKEN_SECRETS entry 2"},
+		{"bad env", func(c *Config) { c.App.Env = "prod" }, "APP_ENV"},
+		{"production secret key", func(c *Config) { c.App.Env = "production" }, "APP_SECRET_KEY"},
+		{"clickhouse credentials", func(c *Config) { c.ClickHouse.URL = "http://localhost:8123" }, "CLICKHOUSE_USER is required"},
+		{"clickhouse circuit", func(c *Config) {
+			c.ClickHouse = ClickHouseConfig{URL: "http://localhost:8123", Database: "a", User: "u", Password: "p", CircuitCooldown: time.Second}
+		}, "CLICKHOUSE_CIRCUIT_FAILURES must be positive"},
+		{"malformed s3 endpoint", func(c *Config) {
+			c.S3 = S3Config{Endpoint: "minio:9000", Bucket: "b", Region: "r", AccessKey: "a", SecretKey: "s"}
+		}, "S3_ENDPOINT"},
+		{"storage key prefix", func(c *Config) { c.Storage.KeyPrefix = "prod/../other" }, "STORAGE_KEY_PREFIX"},
+		{"s3 keys", func(c *Config) { c.S3 = S3Config{Endpoint: "http://minio:9000", Bucket: "b", Region: "r"} }, "S3_ACCESS_KEY"},
+		{"base url", func(c *Config) { c.App.BaseURL = "localhost:8080" }, "APP_BASE_URL must be an absolute URL"},
+		{"port", func(c *Config) { c.App.Port = 0 }, "APP_PORT"},
+		{"max open conns", func(c *Config) { c.Database.MaxOpenConns = 0 }, "DATABASE_MAX_OPEN_CONNS must be positive"},
+		{"min conns", func(c *Config) { c.Database.MinConns = 30 }, "DATABASE_MIN_CONNS (30) must not exceed"},
+		{"legacy min conns", func(c *Config) { c.Database.MaxIdleConns = 30 }, "DATABASE_MIN_CONNS (30) must not exceed"},
+		{"usage snapshot interval", func(c *Config) { c.License.UsageSnapshotInterval = 0 }, "LICENSE_USAGE_SNAPSHOT_INTERVAL"},
+		{"license check jitter", func(c *Config) { c.License.CheckJitter = -time.Minute }, "LICENSE_CHECK_JITTER"},
+		{"bulk async threshold", func(c *Config) { c.Links.BulkAsyncThreshold = -1 }, "LINKS_BULK_ASYNC_THRESHOLD"},
+		{"duplicate click window", func(c *Config) { c.Links.DuplicateClickWindow = -time.Second }, "LINKS_DUPLICATE_CLICK_WINDOW"},
+		{"failover check interval", func(c *Config) { c.Links.FailoverCheckInterval = -time.Minute }, "LINKS_FAILOVER_CHECK_INTERVAL"},
+		{"visitor identity", func(c *Config) { c.Redirect.VisitorIdentity = "fingerprint" }, "REDIRECT_VISITOR_IDENTITY"},
+		{"not found mode", func(c *Config) { c.Redirect.NotFoundMode = "silent" }, "REDIRECT_NOT_FOUND_MODE"},
+		{"not found redirect url", func(c *Config) { c.Redirect.NotFoundMode = NotFoundModeRedirect }, "REDIRECT_NOT_FOUND_URL is required"},
+		{"not found status", func(c *Config) {
+			c.Redirect.NotFoundMode = NotFoundModeStatus
+			c.Redirect.NotFoundStatus = 301
+		}, "REDIRECT_NOT_FOUND_STATUS"},
+		{"warm cache links", func(c *Config) { c.Redirect.WarmCache = true; c.Redirect.WarmCacheLinks = 0 }, "REDIRECT_WARM_CACHE_LINKS"},
+		{"safety mode", func(c *Config) { c.Safety.Mode = "reject" }, "SAFETY_MODE must be"},
+		{"webhook secret rotation window", func(c *Config) { c.Webhooks.SecretRotationWindow = -time.Hour }, "WEBHOOKS_SECRET_ROTATION_WINDOW"},
+		{"realtime per-IP limit", func(c *Config) { c.Realtime.MaxConnectionsPerIP = -1 }, "REALTIME_MAX_CONNECTIONS_PER_IP"},
+		{"realtime idle timeout", func(c *Config) { c.Realtime.IdleTimeout = 0 }, "REALTIME_IDLE_TIMEOUT"},
+		{"safety provider", func(c *Config) {
+			c.Safety = SafetyConfig{Mode: SafetyModeFlag, Timeout: time.Second, ReportThreshold: 5}
+		}, "SAFETY_BLOCKLIST_PATH"},
+		{"report threshold", func(c *Config) { c.Safety.ReportThreshold = 0 }, "SAFETY_REPORT_THRESHOLD"},
+		{"event bus driver", func(c *Config) { c.EventBus.Driver = "kinesis" }, "EVENT_BUS_DRIVER must be"},
+		{"event bus url scheme", func(c *Config) {
+			c.EventBus = EventBusConfig{Driver: EventBusDriverKafka, URL: "kafka://broker:9092", Topic: "clicks", Timeout: time.Second}
+		}, "Kafka REST proxy"},
+		{"event bus url", func(c *Config) {
+			c.EventBus = EventBusConfig{Driver: EventBusDriverNATS, Topic: "clicks", Timeout: time.Second}
+		}, "EVENT_BUS_URL is required"},
+		{"hsts max age", func(c *Config) { c.Security.HSTSMaxAge = -time.Second }, "HSTS_MAX_AGE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.URL = ""
+	cfg.Redis.URL = ""
+	cfg.Auth.TokenSecret = ""
+
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if len(verr.Problems) != 3 {
+		t.Errorf("problems = %q, want 3", verr.Problems)
+	}
+}
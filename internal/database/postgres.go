@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,20 +12,39 @@ import (
 )
 
 type PostgresDB struct {
-	pool   *pgxpool.Pool
-	logger *zap.Logger
+	pool      *pgxpool.Pool
+	name      string
+	logger    *zap.Logger
+	stopStats chan struct{}
+	closeOnce sync.Once
 }
 
 func NewPostgres(cfg config.DatabaseConfig, logger *zap.Logger) (*PostgresDB, error) {
-	poolCfg, err := pgxpool.ParseConfig(cfg.URL)
+	return connectPostgres(cfg.URL, cfg, "PostgreSQL", logger)
+}
+
+// NewPostgresReplica connects to the read replica at cfg.ReplicaURL, with
+// the same pool settings as the primary. Replicas lag behind the primary, so
+// only use it for reads that tolerate slightly stale data.
+func NewPostgresReplica(cfg config.DatabaseConfig, logger *zap.Logger) (*PostgresDB, error) {
+	if cfg.ReplicaURL == "" {
+		return nil, fmt.Errorf("no read replica URL configured")
+	}
+	return connectPostgres(cfg.ReplicaURL, cfg, "PostgreSQL read replica", logger)
+}
+
+func connectPostgres(url string, cfg config.DatabaseConfig, name string, logger *zap.Logger) (*PostgresDB, error) {
+	poolCfg, err := pgxpool.ParseConfig(url)
 	if err != nil {
 		return nil, fmt.Errorf("parsing database URL: %w", err)
 	}
 
 	poolCfg.MaxConns = int32(cfg.MaxOpenConns)
-	poolCfg.MinConns = int32(cfg.MaxIdleConns)
+	poolCfg.MinConns = int32(cfg.PoolMinConns())
 	poolCfg.MaxConnLifetime = cfg.ConnMaxLifetime
-	poolCfg.MaxConnIdleTime = 5 * time.Minute
+	if cfg.ConnMaxIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.ConnMaxIdleTime
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -39,12 +59,19 @@ func NewPostgres(cfg config.DatabaseConfig, logger *zap.Logger) (*PostgresDB, er
 		return nil, fmt.Errorf("pinging database: %w", err)
 	}
 
-	logger.Info("connected to PostgreSQL",
+	logger.Info("connected to "+name,
 		zap.String("host", poolCfg.ConnConfig.Host),
 		zap.Int32("max_conns", poolCfg.MaxConns),
+		zap.Int32("min_conns", poolCfg.MinConns),
+		zap.Duration("max_conn_lifetime", poolCfg.MaxConnLifetime),
+		zap.Duration("max_conn_idle_time", poolCfg.MaxConnIdleTime),
 	)
 
-	return &PostgresDB{pool: pool, logger: logger}, nil
+	db := &PostgresDB{pool: pool, name: name, logger: logger, stopStats: make(chan struct{})}
+	if cfg.PoolStatsInterval > 0 {
+		go db.reportStats(cfg.PoolStatsInterval)
+	}
+	return db, nil
 }
 
 func (db *PostgresDB) Pool() *pgxpool.Pool {
@@ -56,6 +83,81 @@ func (db *PostgresDB) HealthCheck(ctx context.Context) error {
 }
 
 func (db *PostgresDB) Close() {
-	db.pool.Close()
-	db.logger.Info("PostgreSQL connection closed")
+	db.closeOnce.Do(func() {
+		close(db.stopStats)
+		db.pool.Close()
+		db.logger.Info(db.name + " connection closed")
+	})
+}
+
+// poolStats is a snapshot of the pool's gauges and cumulative counters.
+type poolStats struct {
+	total, acquired, idle, constructing, max int32
+	acquires, waitedAcquires, canceled       int64
+	emptyAcquireWait                         time.Duration
+}
+
+func snapshotPool(s *pgxpool.Stat) poolStats {
+	return poolStats{
+		total:            s.TotalConns(),
+		acquired:         s.AcquiredConns(),
+		idle:             s.IdleConns(),
+		constructing:     s.ConstructingConns(),
+		max:              s.MaxConns(),
+		acquires:         s.AcquireCount(),
+		waitedAcquires:   s.EmptyAcquireCount(),
+		canceled:         s.CanceledAcquireCount(),
+		emptyAcquireWait: s.EmptyAcquireWaitTime(),
+	}
+}
+
+// statsFields describes the pool's state at cur and the acquires since prev.
+// It reports whether any of those acquires found no idle connection and had
+// to wait for one to be released or opened, a sign the pool is too small.
+func statsFields(prev, cur poolStats) ([]zap.Field, bool) {
+	acquires := cur.acquires - prev.acquires
+	waited := cur.waitedAcquires - prev.waitedAcquires
+
+	var avgWait time.Duration
+	if waited > 0 {
+		avgWait = (cur.emptyAcquireWait - prev.emptyAcquireWait) / time.Duration(waited)
+	}
+
+	return []zap.Field{
+		zap.Int32("total_conns", cur.total),
+		zap.Int32("acquired_conns", cur.acquired),
+		zap.Int32("idle_conns", cur.idle),
+		zap.Int32("constructing_conns", cur.constructing),
+		zap.Int32("max_conns", cur.max),
+		zap.Int64("acquires", acquires),
+		zap.Int64("waited_acquires", waited),
+		zap.Int64("canceled_acquires", cur.canceled-prev.canceled),
+		zap.Duration("avg_wait", avgWait),
+	}, waited > 0
+}
+
+// reportStats logs pool statistics every interval until the pool is closed,
+// as a warning when acquires had to wait for a connection.
+func (db *PostgresDB) reportStats(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prev := snapshotPool(db.pool.Stat())
+	for {
+		select {
+		case <-db.stopStats:
+			return
+		case <-ticker.C:
+		}
+
+		cur := snapshotPool(db.pool.Stat())
+		fields, waited := statsFields(prev, cur)
+		fields = append(fields, zap.String("database", db.name))
+		if waited {
+			db.logger.Warn("database pool acquires waited for a connection", fields...)
+		} else {
+			db.logger.Info("database pool stats", fields...)
+		}
+		prev = cur
+	}
 }
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func fieldMap(fields []zap.Field) map[string]zap.Field {
+	m := make(map[string]zap.Field, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f
+	}
+	return m
+}
+
+func TestStatsFields(t *testing.T) {
+	prev := poolStats{acquires: 100, waitedAcquires: 4, canceled: 1, emptyAcquireWait: time.Second}
+	cur := poolStats{
+		total: 25, acquired: 25, max: 25,
+		acquires: 160, waitedAcquires: 8, canceled: 2, emptyAcquireWait: 3 * time.Second,
+	}
+
+	fields, waited := statsFields(prev, cur)
+	if !waited {
+		t.Error("expected waited acquires to be reported")
+	}
+	m := fieldMap(fields)
+	if got := m["acquires"].Integer; got != 60 {
+		t.Errorf("acquires = %d, want 60", got)
+	}
+	if got := m["waited_acquires"].Integer; got != 4 {
+		t.Errorf("waited_acquires = %d, want 4", got)
+	}
+	if got := m["canceled_acquires"].Integer; got != 1 {
+		t.Errorf("canceled_acquires = %d, want 1", got)
+	}
+	if got := time.Duration(m["avg_wait"].Integer); got != 500*time.Millisecond {
+		t.Errorf("avg_wait = %v, want 500ms", got)
+	}
+}
+
+func TestStatsFieldsIdlePool(t *testing.T) {
+	s := poolStats{total: 10, idle: 10, max: 25, acquires: 50, waitedAcquires: 2}
+
+	fields, waited := statsFields(s, s)
+	if waited {
+		t.Error("expected no waited acquires")
+	}
+	if got := fieldMap(fields)["avg_wait"].Integer; got != 0 {
+		t.Errorf("avg_wait = %d, want 0", got)
+	}
+}
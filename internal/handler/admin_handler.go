@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// AdminHandler serves instance administration endpoints.
+type AdminHandler struct {
+	moderationService service.LinkModerationService
+	logger            *zap.Logger
+}
+
+func NewAdminHandler(moderationService service.LinkModerationService, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{moderationService: moderationService, logger: logger}
+}
+
+func (h *AdminHandler) RegisterRoutes(rg *gin.RouterGroup, authMw, adminMw gin.HandlerFunc) {
+	admin := rg.Group("/admin", authMw, adminMw)
+	{
+		admin.GET("/flagged-links", h.ListFlaggedLinks)
+		admin.POST("/flagged-links/:linkId/approve", h.ApproveLink)
+		admin.POST("/flagged-links/:linkId/reject", h.RejectLink)
+		admin.GET("/flagged-links/:linkId/reports", h.ListReports)
+	}
+}
+
+func (h *AdminHandler) ListFlaggedLinks(c *gin.Context) {
+	var pagination models.Pagination
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		httputil.RespondError(c, httputil.BindingError("query", err))
+		return
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = 20
+	}
+
+	result, err := h.moderationService.ListFlaggedLinks(c.Request.Context(), c.Query("status"), pagination)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondList(c, result.Flags, result.Total, pagination.Limit, pagination.Offset)
+}
+
+func (h *AdminHandler) ApproveLink(c *gin.Context) {
+	h.review(c, h.moderationService.ApproveLink)
+}
+
+func (h *AdminHandler) RejectLink(c *gin.Context) {
+	h.review(c, h.moderationService.RejectLink)
+}
+
+func (h *AdminHandler) ListReports(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	reports, err := h.moderationService.ListReports(c.Request.Context(), linkID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, reports)
+}
+
+func (h *AdminHandler) review(c *gin.Context, decide func(ctx context.Context, linkID, reviewerID uuid.UUID) (*models.LinkFlag, error)) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		httputil.RespondError(c, httputil.Unauthorized("not authenticated"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	flag, err := decide(c.Request.Context(), linkID, user.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, flag)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -29,20 +31,44 @@ func NewAnalyticsHandler(analyticsService service.AnalyticsService, linkService
 }
 
 // RegisterRoutes registers analytics routes under a workspace-scoped group.
-func (h *AnalyticsHandler) RegisterRoutes(wsScoped *gin.RouterGroup) {
-	analytics := wsScoped.Group("/analytics")
+// editorMw enforces editor+ role for managing share links. API keys need the
+// analytics:read scope, and links:write to manage share links since those
+// publish a link's stats.
+func (h *AnalyticsHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.HandlerFunc) {
+	read := middleware.RequireAPIKeyScope(models.ScopeAnalyticsRead)
+	share := middleware.RequireAPIKeyScope(models.ScopeLinksWrite)
+
+	analytics := wsScoped.Group("/analytics", read)
 	{
+		analytics.GET("/presets", h.ListDateRangePresets)
 		analytics.GET("/links/:id", h.GetLinkStats)
 		analytics.GET("/links/:id/timeseries", h.GetTimeSeries)
 		analytics.GET("/links/:id/referrers", h.GetReferrers)
 		analytics.GET("/links/:id/countries", h.GetCountries)
 		analytics.GET("/links/:id/devices", h.GetDevices)
 		analytics.GET("/links/:id/browsers", h.GetBrowsers)
+		analytics.GET("/links/:id/heatmap", h.GetHeatmap)
 		analytics.GET("/workspace", h.GetWorkspaceStats)
+		analytics.GET("/workspace/referrers", h.GetWorkspaceReferrers)
+		analytics.GET("/workspace/countries", h.GetWorkspaceCountries)
+		analytics.GET("/workspace/devices", h.GetWorkspaceDevices)
 		analytics.GET("/export", h.ExportData)
+		analytics.GET("/links/:id/shares", h.ListShares)
+		analytics.POST("/links/:id/shares", share, editorMw, h.CreateShare)
+		analytics.DELETE("/shares/:shareId", share, editorMw, h.RevokeShare)
 	}
 }
 
+// RegisterPublicRoutes registers the unauthenticated share-link endpoint.
+func (h *AnalyticsHandler) RegisterPublicRoutes(rg *gin.RouterGroup) {
+	rg.GET("/shared/analytics/:token", h.GetSharedAnalytics)
+}
+
+// ListDateRangePresets lists the presets accepted by the "range" parameter.
+func (h *AnalyticsHandler) ListDateRangePresets(c *gin.Context) {
+	httputil.RespondSuccess(c, http.StatusOK, models.DateRangePresets)
+}
+
 func (h *AnalyticsHandler) GetLinkStats(c *gin.Context) {
 	ws := middleware.GetWorkspaceFromContext(c)
 	if ws == nil {
@@ -61,8 +87,12 @@ func (h *AnalyticsHandler) GetLinkStats(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
-	stats, err := h.analyticsService.GetLinkStats(c.Request.Context(), linkID, dr)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	stats, err := h.analyticsService.GetLinkStats(c.Request.Context(), linkID, ws.ID, dr)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -89,10 +119,14 @@ func (h *AnalyticsHandler) GetTimeSeries(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
 	interval := h.parseInterval(c)
 
-	points, err := h.analyticsService.GetTimeSeries(c.Request.Context(), linkID, interval, dr)
+	points, err := h.analyticsService.GetTimeSeries(c.Request.Context(), linkID, ws.ID, interval, dr)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -119,10 +153,14 @@ func (h *AnalyticsHandler) GetReferrers(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
 	limit := h.parseLimit(c)
 
-	stats, err := h.analyticsService.GetTopReferrers(c.Request.Context(), linkID, dr, limit)
+	stats, err := h.analyticsService.GetTopReferrers(c.Request.Context(), linkID, ws.ID, dr, limit)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -149,10 +187,14 @@ func (h *AnalyticsHandler) GetCountries(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
 	limit := h.parseLimit(c)
 
-	stats, err := h.analyticsService.GetTopCountries(c.Request.Context(), linkID, dr, limit)
+	stats, err := h.analyticsService.GetTopCountries(c.Request.Context(), linkID, ws.ID, dr, limit)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -179,9 +221,13 @@ func (h *AnalyticsHandler) GetDevices(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
 
-	breakdown, err := h.analyticsService.GetDeviceBreakdown(c.Request.Context(), linkID, dr)
+	breakdown, err := h.analyticsService.GetDeviceBreakdown(c.Request.Context(), linkID, ws.ID, dr)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -208,10 +254,14 @@ func (h *AnalyticsHandler) GetBrowsers(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
 	limit := h.parseLimit(c)
 
-	stats, err := h.analyticsService.GetBrowserBreakdown(c.Request.Context(), linkID, dr, limit)
+	stats, err := h.analyticsService.GetBrowserBreakdown(c.Request.Context(), linkID, ws.ID, dr, limit)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -220,6 +270,39 @@ func (h *AnalyticsHandler) GetBrowsers(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, stats)
 }
 
+func (h *AnalyticsHandler) GetHeatmap(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	if err := h.verifyLinkOwnership(c, linkID, ws.ID); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	heatmap, err := h.analyticsService.GetClickHeatmap(c.Request.Context(), linkID, ws.ID, dr)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, heatmap)
+}
+
 func (h *AnalyticsHandler) GetWorkspaceStats(c *gin.Context) {
 	ws := middleware.GetWorkspaceFromContext(c)
 	if ws == nil {
@@ -227,7 +310,11 @@ func (h *AnalyticsHandler) GetWorkspaceStats(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
 
 	stats, err := h.analyticsService.GetWorkspaceStats(c.Request.Context(), ws.ID, dr)
 	if err != nil {
@@ -238,6 +325,92 @@ func (h *AnalyticsHandler) GetWorkspaceStats(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, stats)
 }
 
+func (h *AnalyticsHandler) GetWorkspaceReferrers(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	tagID, err := parseTagFilter(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	limit := h.parseLimit(c)
+
+	stats, err := h.analyticsService.GetWorkspaceReferrers(c.Request.Context(), ws.ID, tagID, dr, limit)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, stats)
+}
+
+func (h *AnalyticsHandler) GetWorkspaceCountries(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	tagID, err := parseTagFilter(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	limit := h.parseLimit(c)
+
+	stats, err := h.analyticsService.GetWorkspaceCountries(c.Request.Context(), ws.ID, tagID, dr, limit)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, stats)
+}
+
+func (h *AnalyticsHandler) GetWorkspaceDevices(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	tagID, err := parseTagFilter(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	breakdown, err := h.analyticsService.GetWorkspaceDevices(c.Request.Context(), ws.ID, tagID, dr)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, breakdown)
+}
+
 func (h *AnalyticsHandler) ExportData(c *gin.Context) {
 	ws := middleware.GetWorkspaceFromContext(c)
 	if ws == nil {
@@ -262,10 +435,14 @@ func (h *AnalyticsHandler) ExportData(c *gin.Context) {
 		return
 	}
 
-	dr := h.parseDateRange(c)
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
 	format := models.AnalyticsExportFormat(c.DefaultQuery("format", "csv"))
 
-	data, contentType, err := h.analyticsService.ExportLinkData(c.Request.Context(), linkID, dr, format)
+	data, contentType, err := h.analyticsService.ExportLinkData(c.Request.Context(), linkID, ws.ID, dr, format)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -282,6 +459,98 @@ func (h *AnalyticsHandler) ExportData(c *gin.Context) {
 	c.Data(http.StatusOK, contentType, data)
 }
 
+func (h *AnalyticsHandler) CreateShare(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	var input models.CreateAnalyticsShareInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	dr, err := shareDateRange(input, ws.ParsedSettings().Location())
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	ttl := time.Duration(input.TTLHours) * time.Hour
+	result, err := h.analyticsService.CreateShareToken(c.Request.Context(), linkID, ws.ID, ttl, dr)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusCreated, result)
+}
+
+func (h *AnalyticsHandler) ListShares(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	shares, err := h.analyticsService.ListShareTokens(c.Request.Context(), linkID, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, shares)
+}
+
+func (h *AnalyticsHandler) RevokeShare(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	shareID, err := uuid.Parse(c.Param("shareId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("shareId", "invalid share ID"))
+		return
+	}
+
+	if err := h.analyticsService.RevokeShareToken(c.Request.Context(), shareID, ws.ID); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "share link revoked successfully"})
+}
+
+// Public
+
+func (h *AnalyticsHandler) GetSharedAnalytics(c *gin.Context) {
+	result, err := h.analyticsService.GetSharedLinkAnalytics(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	httputil.RespondSuccess(c, http.StatusOK, result)
+}
+
 // verifyLinkOwnership checks that the link belongs to the workspace.
 func (h *AnalyticsHandler) verifyLinkOwnership(c *gin.Context, linkID, workspaceID uuid.UUID) error {
 	link, err := h.linkService.GetLink(c.Request.Context(), linkID)
@@ -294,9 +563,13 @@ func (h *AnalyticsHandler) verifyLinkOwnership(c *gin.Context, linkID, workspace
 	return nil
 }
 
-func (h *AnalyticsHandler) parseDateRange(c *gin.Context) models.DateRange {
+// parseDateRange reads the date range from the "range" preset or the
+// "start"/"end" RFC3339 query parameters, defaulting to the last 7 days.
+// Presets and time-series buckets use the workspace's time zone.
+func parseDateRange(c *gin.Context) (models.DateRange, error) {
+	loc := workspaceLocation(c)
 	if preset := c.Query("range"); preset != "" {
-		return models.DateRangeFromPreset(preset)
+		return dateRangeFromPreset(preset, loc)
 	}
 
 	startStr := c.Query("start")
@@ -304,8 +577,9 @@ func (h *AnalyticsHandler) parseDateRange(c *gin.Context) models.DateRange {
 
 	now := time.Now().UTC()
 	dr := models.DateRange{
-		Start: now.Add(-7 * 24 * time.Hour),
-		End:   now,
+		Start:    now.Add(-7 * 24 * time.Hour),
+		End:      now,
+		Location: loc,
 	}
 
 	if startStr != "" {
@@ -319,7 +593,55 @@ func (h *AnalyticsHandler) parseDateRange(c *gin.Context) models.DateRange {
 		}
 	}
 
-	return dr
+	return dr, nil
+}
+
+// shareDateRange resolves the date range a share link exposes from the
+// "range" preset or RFC3339 "start"/"end", defaulting to the last 30 days.
+func shareDateRange(input models.CreateAnalyticsShareInput, loc *time.Location) (models.DateRange, error) {
+	if input.Range != "" {
+		return dateRangeFromPreset(input.Range, loc)
+	}
+
+	dr, _ := models.DateRangeFromPreset("30d", loc)
+	if input.Start != "" {
+		t, err := time.Parse(time.RFC3339, input.Start)
+		if err != nil {
+			return dr, httputil.Validation("start", "invalid date format, use RFC3339")
+		}
+		dr.Start = t
+	}
+	if input.End != "" {
+		t, err := time.Parse(time.RFC3339, input.End)
+		if err != nil {
+			return dr, httputil.Validation("end", "invalid date format, use RFC3339")
+		}
+		dr.End = t
+	}
+	return dr, nil
+}
+
+// dateRangeFromPreset resolves a "range" preset, rejecting unknown ones
+// with a validation error that lists the supported presets.
+func dateRangeFromPreset(preset string, loc *time.Location) (models.DateRange, error) {
+	dr, err := models.DateRangeFromPreset(preset, loc)
+	if err != nil {
+		names := make([]string, len(models.DateRangePresets))
+		for i, p := range models.DateRangePresets {
+			names[i] = p.Name
+		}
+		return dr, httputil.Validation("range", fmt.Sprintf("unknown preset %q, use one of: %s", preset, strings.Join(names, ", ")))
+	}
+	return dr, nil
+}
+
+// workspaceLocation returns the analytics time zone of the request's
+// workspace, UTC outside one.
+func workspaceLocation(c *gin.Context) *time.Location {
+	if ws := middleware.GetWorkspaceFromContext(c); ws != nil {
+		return ws.ParsedSettings().Location()
+	}
+	return time.UTC
 }
 
 func (h *AnalyticsHandler) parseInterval(c *gin.Context) models.TimeSeriesInterval {
@@ -345,3 +667,16 @@ func (h *AnalyticsHandler) parseLimit(c *gin.Context) int {
 	}
 	return limit
 }
+
+// parseTagFilter reads the optional tag_id query parameter.
+func parseTagFilter(c *gin.Context) (*uuid.UUID, error) {
+	raw := c.Query("tag_id")
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, httputil.Validation("tag_id", "invalid tag ID")
+	}
+	return &id, nil
+}
@@ -45,7 +45,7 @@ func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
 
 	var input models.CreateAPIKeyInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -41,7 +41,7 @@ func (h *AuthHandler) RegisterRoutes(rg *gin.RouterGroup, authMw gin.HandlerFunc
 func (h *AuthHandler) Register(c *gin.Context) {
 	var input models.RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -57,7 +57,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var input models.LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -87,7 +87,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var input models.RefreshInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -106,7 +106,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 	var input models.ForgotPasswordInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -121,7 +121,7 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var input models.ResetPasswordInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -136,7 +136,7 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 	var input models.VerifyEmailInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
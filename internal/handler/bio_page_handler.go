@@ -27,12 +27,15 @@ func (h *BioPageHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.
 		bioPages.GET("", h.ListBioPages)
 		bioPages.GET("/:id", h.GetBioPage)
 		bioPages.GET("/:id/links", h.ListLinks)
+		bioPages.GET("/:id/submissions", editorMw, h.ListSubmissions)
+		bioPages.GET("/:id/submissions/export", editorMw, h.ExportSubmissions)
 
 		bioPages.POST("", editorMw, h.CreateBioPage)
 		bioPages.PUT("/:id", editorMw, h.UpdateBioPage)
 		bioPages.DELETE("/:id", editorMw, h.DeleteBioPage)
 		bioPages.POST("/:id/publish", editorMw, h.PublishBioPage)
 		bioPages.POST("/:id/unpublish", editorMw, h.UnpublishBioPage)
+		bioPages.POST("/:id/og-image", editorMw, h.GenerateOGImage)
 		bioPages.POST("/:id/links", editorMw, h.AddLink)
 		bioPages.PUT("/:id/links/:linkId", editorMw, h.UpdateLink)
 		bioPages.DELETE("/:id/links/:linkId", editorMw, h.DeleteLink)
@@ -43,12 +46,17 @@ func (h *BioPageHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.
 	{
 		themes.GET("", h.ListThemes)
 		themes.GET("/:themeId", h.GetTheme)
+
+		themes.POST("", editorMw, h.CreateTheme)
+		themes.PUT("/:themeId", editorMw, h.UpdateTheme)
+		themes.DELETE("/:themeId", editorMw, h.DeleteTheme)
 	}
 }
 
 func (h *BioPageHandler) RegisterPublicRoutes(router *gin.Engine) {
 	router.GET("/b/:slug", h.GetPublicPage)
 	router.POST("/b/:slug/click/:linkId", h.TrackLinkClick)
+	router.POST("/b/:slug/subscribe", h.SubmitEmail)
 }
 
 // Bio Page CRUD
@@ -62,7 +70,7 @@ func (h *BioPageHandler) CreateBioPage(c *gin.Context) {
 
 	var input models.CreateBioPageInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -122,7 +130,7 @@ func (h *BioPageHandler) UpdateBioPage(c *gin.Context) {
 
 	var input models.UpdateBioPageInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -202,6 +210,32 @@ func (h *BioPageHandler) UnpublishBioPage(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, page)
 }
 
+// Open Graph image
+
+// GenerateOGImage renders the page's OG card now and sets it as the page's
+// OG image, replacing any custom one.
+func (h *BioPageHandler) GenerateOGImage(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid bio page ID"))
+		return
+	}
+
+	page, err := h.bioPageService.GenerateOGImage(c.Request.Context(), id, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, page)
+}
+
 // Links
 
 func (h *BioPageHandler) ListLinks(c *gin.Context) {
@@ -235,7 +269,7 @@ func (h *BioPageHandler) AddLink(c *gin.Context) {
 
 	var input models.CreateBioPageLinkInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -269,7 +303,7 @@ func (h *BioPageHandler) UpdateLink(c *gin.Context) {
 
 	var input models.UpdateBioPageLinkInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -324,7 +358,7 @@ func (h *BioPageHandler) ReorderLinks(c *gin.Context) {
 
 	var input models.ReorderBioLinksInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -339,13 +373,29 @@ func (h *BioPageHandler) ReorderLinks(c *gin.Context) {
 // Themes
 
 func (h *BioPageHandler) ListThemes(c *gin.Context) {
-	themes := h.bioPageService.ListThemes()
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	themes, err := h.bioPageService.ListThemes(c.Request.Context(), ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
 	httputil.RespondSuccess(c, http.StatusOK, themes)
 }
 
 func (h *BioPageHandler) GetTheme(c *gin.Context) {
-	themeID := c.Param("themeId")
-	theme, err := h.bioPageService.GetTheme(themeID)
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	theme, err := h.bioPageService.GetTheme(c.Request.Context(), ws.ID, c.Param("themeId"))
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -354,6 +404,133 @@ func (h *BioPageHandler) GetTheme(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, theme)
 }
 
+func (h *BioPageHandler) CreateTheme(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.CreateBioThemeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	theme, err := h.bioPageService.CreateTheme(c.Request.Context(), ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusCreated, theme)
+}
+
+func (h *BioPageHandler) UpdateTheme(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	themeID, err := uuid.Parse(c.Param("themeId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("themeId", "invalid theme ID"))
+		return
+	}
+
+	var input models.UpdateBioThemeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	theme, err := h.bioPageService.UpdateTheme(c.Request.Context(), themeID, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, theme)
+}
+
+func (h *BioPageHandler) DeleteTheme(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	themeID, err := uuid.Parse(c.Param("themeId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("themeId", "invalid theme ID"))
+		return
+	}
+
+	if err := h.bioPageService.DeleteTheme(c.Request.Context(), themeID, ws.ID); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "theme deleted successfully"})
+}
+
+// Email capture
+
+func (h *BioPageHandler) ListSubmissions(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid bio page ID"))
+		return
+	}
+
+	var pagination models.Pagination
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		httputil.RespondError(c, httputil.BindingError("query", err))
+		return
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = 20
+	}
+
+	subs, total, err := h.bioPageService.ListSubmissions(c.Request.Context(), id, ws.ID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondList(c, subs, total, pagination.Limit, pagination.Offset)
+}
+
+func (h *BioPageHandler) ExportSubmissions(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid bio page ID"))
+		return
+	}
+
+	ew := &exportWriter{c: c, filename: "bio-submissions.csv", contentType: "text/csv"}
+	if err := h.bioPageService.ExportSubmissions(c.Request.Context(), id, ws.ID, ew); err != nil {
+		if !ew.started {
+			httputil.RespondError(c, err)
+			return
+		}
+		h.logger.Error("bio submission export aborted", zap.Error(err))
+	}
+}
+
 // Public
 
 func (h *BioPageHandler) GetPublicPage(c *gin.Context) {
@@ -382,3 +559,18 @@ func (h *BioPageHandler) TrackLinkClick(c *gin.Context) {
 
 	httputil.RespondSuccess(c, http.StatusOK, gin.H{"tracked": true})
 }
+
+func (h *BioPageHandler) SubmitEmail(c *gin.Context) {
+	var input models.BioEmailSubmissionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	if err := h.bioPageService.SubmitEmail(c.Request.Context(), c.Param("slug"), input, c.ClientIP()); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, gin.H{"subscribed": true})
+}
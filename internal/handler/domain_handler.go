@@ -30,6 +30,7 @@ func (h *DomainHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.H
 
 		domains.POST("", editorMw, h.AddDomain)
 		domains.POST("/:id/verify", editorMw, h.VerifyDomain)
+		domains.PUT("/:id", editorMw, h.UpdateDomain)
 		domains.DELETE("/:id", editorMw, h.RemoveDomain)
 	}
 }
@@ -43,7 +44,7 @@ func (h *DomainHandler) AddDomain(c *gin.Context) {
 
 	var input models.CreateDomainInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -110,6 +111,34 @@ func (h *DomainHandler) VerifyDomain(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, domain)
 }
 
+func (h *DomainHandler) UpdateDomain(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid domain ID"))
+		return
+	}
+
+	var input models.UpdateDomainInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	domain, err := h.domainService.UpdateDomain(c.Request.Context(), id, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, domain)
+}
+
 func (h *DomainHandler) RemoveDomain(c *gin.Context) {
 	ws := middleware.GetWorkspaceFromContext(c)
 	if ws == nil {
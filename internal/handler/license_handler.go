@@ -4,26 +4,32 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/service"
 	"github.com/link-rift/link-rift/pkg/httputil"
 	"go.uber.org/zap"
 )
 
 type LicenseHandler struct {
-	manager *license.Manager
-	logger  *zap.Logger
+	manager        *license.Manager
+	licenseService service.LicenseService
+	logger         *zap.Logger
 }
 
-func NewLicenseHandler(manager *license.Manager, logger *zap.Logger) *LicenseHandler {
-	return &LicenseHandler{manager: manager, logger: logger}
+func NewLicenseHandler(manager *license.Manager, licenseService service.LicenseService, logger *zap.Logger) *LicenseHandler {
+	return &LicenseHandler{manager: manager, licenseService: licenseService, logger: logger}
 }
 
 func (h *LicenseHandler) RegisterRoutes(rg *gin.RouterGroup, authMw gin.HandlerFunc) {
 	lic := rg.Group("/license", authMw)
 	{
 		lic.GET("", h.GetLicense)
+		lic.GET("/features", h.GetFeatures)
 		lic.POST("", h.ActivateLicense)
 		lic.DELETE("", h.DeactivateLicense)
+		lic.POST("/revalidate", h.RevalidateLicense)
 	}
 }
 
@@ -36,10 +42,38 @@ func (h *LicenseHandler) GetLicense(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, resp)
 }
 
+// GetFeatures returns the feature matrix and limits. Usage counts are
+// included for the workspace given in the workspace_id query parameter.
+func (h *LicenseHandler) GetFeatures(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		httputil.RespondError(c, httputil.Unauthorized("not authenticated"))
+		return
+	}
+
+	var workspaceID *uuid.UUID
+	if raw := c.Query("workspace_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			httputil.RespondError(c, httputil.Validation("workspace_id", "invalid workspace ID"))
+			return
+		}
+		workspaceID = &id
+	}
+
+	features, err := h.licenseService.GetFeatures(c.Request.Context(), user.ID, workspaceID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, features)
+}
+
 func (h *LicenseHandler) ActivateLicense(c *gin.Context) {
 	var input activateLicenseInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("license_key", "license_key is required"))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -65,3 +99,15 @@ func (h *LicenseHandler) DeactivateLicense(c *gin.Context) {
 	resp := h.manager.GetLicenseResponse()
 	httputil.RespondSuccess(c, http.StatusOK, resp)
 }
+
+// RevalidateLicense verifies the active license right away instead of
+// waiting for the periodic check. A license that is no longer valid is
+// removed, and the response shows the community edition.
+func (h *LicenseHandler) RevalidateLicense(c *gin.Context) {
+	if err := h.manager.RevalidateNow(); err != nil {
+		h.logger.Warn("license revalidation failed, reverted to community edition", zap.Error(err))
+	}
+
+	resp := h.manager.GetLicenseResponse()
+	httputil.RespondSuccess(c, http.StatusOK, resp)
+}
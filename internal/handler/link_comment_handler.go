@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkCommentHandler struct {
+	commentService service.LinkCommentService
+	logger         *zap.Logger
+}
+
+func NewLinkCommentHandler(commentService service.LinkCommentService, logger *zap.Logger) *LinkCommentHandler {
+	return &LinkCommentHandler{commentService: commentService, logger: logger}
+}
+
+// RegisterRoutes registers the comment thread routes. Every workspace member
+// can read and comment; the service decides who can delete.
+func (h *LinkCommentHandler) RegisterRoutes(wsScoped *gin.RouterGroup) {
+	comments := wsScoped.Group("/links/:id/comments")
+	{
+		comments.GET("", h.ListComments)
+		comments.POST("", h.CreateComment)
+		comments.DELETE("/:commentId", h.DeleteComment)
+	}
+}
+
+func (h *LinkCommentHandler) CreateComment(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	ws := middleware.GetWorkspaceFromContext(c)
+	if user == nil || ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	var input models.CreateLinkCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(c.Request.Context(), linkID, ws.ID, user.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusCreated, comment)
+}
+
+func (h *LinkCommentHandler) ListComments(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	var pagination models.Pagination
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		httputil.RespondError(c, httputil.BindingError("query", err))
+		return
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = 20
+	}
+
+	result, err := h.commentService.ListComments(c.Request.Context(), linkID, ws.ID, pagination)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondList(c, result.Comments, result.Total, pagination.Limit, pagination.Offset)
+}
+
+func (h *LinkCommentHandler) DeleteComment(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	member := middleware.GetWorkspaceMemberFromContext(c)
+	if ws == nil || member == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	commentID, err := uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("commentId", "invalid comment ID"))
+		return
+	}
+
+	if err := h.commentService.DeleteComment(c.Request.Context(), commentID, linkID, ws.ID, member); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "comment deleted successfully"})
+}
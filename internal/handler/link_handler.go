@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,18 +24,33 @@ func NewLinkHandler(linkService service.LinkService, logger *zap.Logger) *LinkHa
 }
 
 // RegisterRoutes registers link routes under a workspace-scoped router group.
-// editorMw enforces editor+ role for write operations.
+// editorMw enforces editor+ role for write operations. API keys need the
+// links:read or links:write scope.
 func (h *LinkHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.HandlerFunc) {
+	read := middleware.RequireAPIKeyScope(models.ScopeLinksRead)
+	write := middleware.RequireAPIKeyScope(models.ScopeLinksWrite)
+
 	links := wsScoped.Group("/links")
 	{
-		links.GET("", h.ListLinks)
-		links.GET("/:id", h.GetLink)
-		links.GET("/:id/stats", h.GetQuickStats)
-
-		links.POST("", editorMw, h.CreateLink)
-		links.PUT("/:id", editorMw, h.UpdateLink)
-		links.DELETE("/:id", editorMw, h.DeleteLink)
-		links.POST("/bulk", editorMw, h.BulkCreateLinks)
+		links.GET("", read, h.ListLinks)
+		links.GET("/export", read, h.ExportLinks)
+		links.GET("/domains", read, h.ListLinkDomains)
+		links.GET("/:id", read, h.GetLink)
+		links.GET("/:id/stats", read, h.GetQuickStats)
+
+		links.POST("", write, editorMw, h.CreateLink)
+		links.PUT("/:id", write, editorMw, h.UpdateLink)
+		links.DELETE("/:id", write, editorMw, h.DeleteLink)
+		links.POST("/reserve", write, editorMw, h.ReserveShortCode)
+		links.POST("/validate", write, editorMw, h.ValidateDestination)
+		links.POST("/bulk", write, editorMw, h.BulkCreateLinks)
+		links.GET("/bulk/:jobId", read, h.GetBulkLinkJob)
+		links.PATCH("/bulk", write, editorMw, h.BulkUpdateLinks)
+		links.DELETE("/bulk", write, editorMw, h.BulkDeleteLinks)
+		links.POST("/:id/transfer", write, editorMw, h.TransferLink)
+		links.POST("/:id/refresh-metadata", write, editorMw, h.RefreshMetadata)
+		links.POST("/:id/archive", write, editorMw, h.ArchiveLink)
+		links.POST("/:id/unarchive", write, editorMw, h.UnarchiveLink)
 	}
 }
 
@@ -52,7 +69,7 @@ func (h *LinkHandler) CreateLink(c *gin.Context) {
 
 	var input models.CreateLinkInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -65,6 +82,36 @@ func (h *LinkHandler) CreateLink(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusCreated, link)
 }
 
+// ReserveShortCode holds a custom short code until a link is created with
+// it or the reservation expires.
+func (h *LinkHandler) ReserveShortCode(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		httputil.RespondError(c, httputil.Unauthorized("not authenticated"))
+		return
+	}
+
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.ReserveLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	link, err := h.linkService.ReserveShortCode(c.Request.Context(), user.ID, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusCreated, link)
+}
+
 func (h *LinkHandler) ListLinks(c *gin.Context) {
 	ws := middleware.GetWorkspaceFromContext(c)
 	if ws == nil {
@@ -74,13 +121,13 @@ func (h *LinkHandler) ListLinks(c *gin.Context) {
 
 	var filter models.LinkFilter
 	if err := c.ShouldBindQuery(&filter); err != nil {
-		httputil.RespondError(c, httputil.Validation("query", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("query", err))
 		return
 	}
 
 	var pagination models.Pagination
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		httputil.RespondError(c, httputil.Validation("query", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("query", err))
 		return
 	}
 	if pagination.Limit == 0 {
@@ -96,6 +143,63 @@ func (h *LinkHandler) ListLinks(c *gin.Context) {
 	httputil.RespondList(c, result.Links, result.Total, pagination.Limit, pagination.Offset)
 }
 
+func (h *LinkHandler) ExportLinks(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	dr, err := parseDateRange(c)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	format := models.AnalyticsExportFormat(c.DefaultQuery("format", "csv"))
+
+	filename := "links-export"
+	contentType := "text/csv"
+	switch format {
+	case models.ExportCSV:
+		filename += ".csv"
+	case models.ExportJSON:
+		filename += ".json"
+		contentType = "application/json"
+	default:
+		httputil.RespondError(c, httputil.Validation("format", "unsupported export format, use csv or json"))
+		return
+	}
+
+	// Headers are sent on the first write, so errors raised before any
+	// output (license, first page lookup) are still returned as JSON.
+	ew := &exportWriter{c: c, filename: filename, contentType: contentType}
+	if err := h.linkService.ExportWorkspaceLinks(c.Request.Context(), ws.ID, dr, format, ew); err != nil {
+		if !ew.started {
+			httputil.RespondError(c, err)
+			return
+		}
+		h.logger.Error("workspace link export aborted", zap.Error(err))
+	}
+}
+
+// exportWriter writes export headers lazily on the first write.
+type exportWriter struct {
+	c           *gin.Context
+	filename    string
+	contentType string
+	started     bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Disposition", "attachment; filename="+w.filename)
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
 func (h *LinkHandler) GetLink(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -127,7 +231,7 @@ func (h *LinkHandler) UpdateLink(c *gin.Context) {
 
 	var input models.UpdateLinkInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -161,6 +265,120 @@ func (h *LinkHandler) DeleteLink(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "link deleted successfully"})
 }
 
+func (h *LinkHandler) ArchiveLink(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	// The body is optional; an empty one archives without deactivating.
+	var input models.ArchiveLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	link, err := h.linkService.ArchiveLink(c.Request.Context(), id, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, link)
+}
+
+func (h *LinkHandler) UnarchiveLink(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	link, err := h.linkService.UnarchiveLink(c.Request.Context(), id, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, link)
+}
+
+func (h *LinkHandler) RefreshMetadata(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	meta, err := h.linkService.RefreshLinkMetadata(c.Request.Context(), id, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, meta)
+}
+
+// ValidateDestination checks that a destination URL responds before a link
+// is created for it.
+func (h *LinkHandler) ValidateDestination(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.ValidateLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	check, err := h.linkService.CheckDestination(c.Request.Context(), ws.ID, input.URL)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, check)
+}
+
+// ListLinkDomains returns the verified custom domains new links can use.
+func (h *LinkHandler) ListLinkDomains(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	domains, err := h.linkService.ListLinkDomains(c.Request.Context(), ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, domains)
+}
+
 func (h *LinkHandler) BulkCreateLinks(c *gin.Context) {
 	user := middleware.GetUserFromContext(c)
 	if user == nil {
@@ -176,19 +394,127 @@ func (h *LinkHandler) BulkCreateLinks(c *gin.Context) {
 
 	var input models.BulkCreateLinkInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
-	links, err := h.linkService.BulkCreateLinks(c.Request.Context(), user.ID, ws.ID, input)
+	links, job, err := h.linkService.SubmitBulkCreateLinks(c.Request.Context(), user.ID, ws.ID, input)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
 	}
 
+	// Large batches are created in the background
+	if job != nil {
+		httputil.RespondSuccess(c, http.StatusAccepted, job)
+		return
+	}
+
 	httputil.RespondSuccess(c, http.StatusCreated, links)
 }
 
+func (h *LinkHandler) GetBulkLinkJob(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("jobId", "invalid job ID"))
+		return
+	}
+
+	job, err := h.linkService.GetBulkLinkJob(c.Request.Context(), ws.ID, jobID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, job)
+}
+
+func (h *LinkHandler) BulkUpdateLinks(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.BulkUpdateLinksInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	result, err := h.linkService.BulkUpdateLinks(c.Request.Context(), ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, result)
+}
+
+func (h *LinkHandler) BulkDeleteLinks(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.BulkDeleteLinksInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	result, err := h.linkService.BulkDeleteLinks(c.Request.Context(), ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, result)
+}
+
+// TransferLink moves a link from the current workspace into another workspace
+// the caller is an editor of.
+func (h *LinkHandler) TransferLink(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	if user == nil {
+		httputil.RespondError(c, httputil.Unauthorized("not authenticated"))
+		return
+	}
+
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	var input models.TransferLinkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	link, err := h.linkService.TransferLink(c.Request.Context(), id, ws.ID, input.WorkspaceID, user.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, link)
+}
+
 func (h *LinkHandler) GetQuickStats(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,12 +23,19 @@ type mockLinkService struct {
 	createLinkFn         func(ctx context.Context, userID, workspaceID uuid.UUID, input models.CreateLinkInput) (*models.Link, error)
 	updateLinkFn         func(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateLinkInput) (*models.Link, error)
 	deleteLinkFn         func(ctx context.Context, id, workspaceID uuid.UUID) error
+	archiveLinkFn        func(ctx context.Context, id, workspaceID uuid.UUID, input models.ArchiveLinkInput) (*models.Link, error)
+	unarchiveLinkFn      func(ctx context.Context, id, workspaceID uuid.UUID) (*models.Link, error)
 	getLinkFn            func(ctx context.Context, id uuid.UUID) (*models.Link, error)
 	listLinksFn          func(ctx context.Context, workspaceID uuid.UUID, filter models.LinkFilter, pagination models.Pagination) (*models.LinkListResult, error)
 	bulkCreateLinksFn    func(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, error)
+	bulkLinkJobFn        func(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) (*models.BulkLinkJob, error)
+	bulkUpdateLinksFn    func(ctx context.Context, workspaceID uuid.UUID, input models.BulkUpdateLinksInput) (*models.BulkLinkResult, error)
+	bulkDeleteLinksFn    func(ctx context.Context, workspaceID uuid.UUID, input models.BulkDeleteLinksInput) (*models.BulkLinkResult, error)
 	getQuickStatsFn      func(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error)
 	checkShortCodeFn     func(ctx context.Context, code string) (bool, error)
 	verifyLinkPasswordFn func(ctx context.Context, shortCode, password string) (bool, error)
+	exportLinksFn        func(ctx context.Context, workspaceID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat, w io.Writer) error
+	transferLinkFn       func(ctx context.Context, linkID, fromWorkspaceID, toWorkspaceID, actorID uuid.UUID) (*models.Link, error)
 }
 
 func (m *mockLinkService) CreateLink(ctx context.Context, userID, workspaceID uuid.UUID, input models.CreateLinkInput) (*models.Link, error) {
@@ -36,6 +45,10 @@ func (m *mockLinkService) CreateLink(ctx context.Context, userID, workspaceID uu
 	return nil, nil
 }
 
+func (m *mockLinkService) ListLinkDomains(_ context.Context, _ uuid.UUID) ([]*models.Domain, error) {
+	return nil, nil
+}
+
 func (m *mockLinkService) UpdateLink(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateLinkInput) (*models.Link, error) {
 	if m.updateLinkFn != nil {
 		return m.updateLinkFn(ctx, id, workspaceID, input)
@@ -50,6 +63,20 @@ func (m *mockLinkService) DeleteLink(ctx context.Context, id, workspaceID uuid.U
 	return nil
 }
 
+func (m *mockLinkService) ArchiveLink(ctx context.Context, id, workspaceID uuid.UUID, input models.ArchiveLinkInput) (*models.Link, error) {
+	if m.archiveLinkFn != nil {
+		return m.archiveLinkFn(ctx, id, workspaceID, input)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkService) UnarchiveLink(ctx context.Context, id, workspaceID uuid.UUID) (*models.Link, error) {
+	if m.unarchiveLinkFn != nil {
+		return m.unarchiveLinkFn(ctx, id, workspaceID)
+	}
+	return nil, nil
+}
+
 func (m *mockLinkService) GetLink(ctx context.Context, id uuid.UUID) (*models.Link, error) {
 	if m.getLinkFn != nil {
 		return m.getLinkFn(ctx, id)
@@ -71,6 +98,21 @@ func (m *mockLinkService) BulkCreateLinks(ctx context.Context, userID, workspace
 	return nil, nil
 }
 
+// SubmitBulkCreateLinks queues a job when bulkLinkJobFn is set and creates
+// the links through bulkCreateLinksFn otherwise.
+func (m *mockLinkService) SubmitBulkCreateLinks(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, *models.BulkLinkJob, error) {
+	if m.bulkLinkJobFn != nil {
+		job, err := m.bulkLinkJobFn(ctx, userID, workspaceID, input)
+		return nil, job, err
+	}
+	links, err := m.BulkCreateLinks(ctx, userID, workspaceID, input)
+	return links, nil, err
+}
+
+func (m *mockLinkService) GetBulkLinkJob(_ context.Context, _, _ uuid.UUID) (*models.BulkLinkJob, error) {
+	return nil, nil
+}
+
 func (m *mockLinkService) GetQuickStats(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error) {
 	if m.getQuickStatsFn != nil {
 		return m.getQuickStatsFn(ctx, id)
@@ -92,6 +134,46 @@ func (m *mockLinkService) VerifyLinkPassword(ctx context.Context, shortCode, pas
 	return false, nil
 }
 
+func (m *mockLinkService) ExportWorkspaceLinks(ctx context.Context, workspaceID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat, w io.Writer) error {
+	if m.exportLinksFn != nil {
+		return m.exportLinksFn(ctx, workspaceID, dr, format, w)
+	}
+	return nil
+}
+
+func (m *mockLinkService) TransferLink(ctx context.Context, linkID, fromWorkspaceID, toWorkspaceID, actorID uuid.UUID) (*models.Link, error) {
+	if m.transferLinkFn != nil {
+		return m.transferLinkFn(ctx, linkID, fromWorkspaceID, toWorkspaceID, actorID)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkService) RefreshLinkMetadata(_ context.Context, _, _ uuid.UUID) (*models.LinkMetadata, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) CheckDestination(_ context.Context, _ uuid.UUID, rawURL string) (*models.LinkDestinationCheck, error) {
+	return &models.LinkDestinationCheck{URL: rawURL, Redirects: []models.LinkRedirectHop{}}, nil
+}
+
+func (m *mockLinkService) ReserveShortCode(_ context.Context, _, _ uuid.UUID, _ models.ReserveLinkInput) (*models.Link, error) {
+	return nil, nil
+}
+
+func (m *mockLinkService) BulkUpdateLinks(ctx context.Context, workspaceID uuid.UUID, input models.BulkUpdateLinksInput) (*models.BulkLinkResult, error) {
+	if m.bulkUpdateLinksFn != nil {
+		return m.bulkUpdateLinksFn(ctx, workspaceID, input)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkService) BulkDeleteLinks(ctx context.Context, workspaceID uuid.UUID, input models.BulkDeleteLinksInput) (*models.BulkLinkResult, error) {
+	if m.bulkDeleteLinksFn != nil {
+		return m.bulkDeleteLinksFn(ctx, workspaceID, input)
+	}
+	return nil, nil
+}
+
 // --- Test Router Setup ---
 
 var testWorkspaceID = uuid.MustParse("22222222-2222-2222-2222-222222222222")
@@ -347,6 +429,46 @@ func TestDeleteLink_Success(t *testing.T) {
 	}
 }
 
+func TestArchiveLink(t *testing.T) {
+	linkID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		wantDeactivate bool
+	}{
+		{"empty body", "", false},
+		{"deactivate", `{"deactivate":true}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *models.ArchiveLinkInput
+			svc := &mockLinkService{
+				archiveLinkFn: func(_ context.Context, id, _ uuid.UUID, input models.ArchiveLinkInput) (*models.Link, error) {
+					got = &input
+					return &models.Link{ID: id}, nil
+				},
+			}
+
+			r := setupTestRouter(svc, true)
+
+			req := httptest.NewRequest("POST", linkURL("/"+linkID.String()+"/archive"), bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+			}
+			if got == nil || got.Deactivate != tt.wantDeactivate {
+				t.Errorf("archive input = %+v, want deactivate %v", got, tt.wantDeactivate)
+			}
+		})
+	}
+}
+
 func TestBulkCreateLinks_Success(t *testing.T) {
 	svc := &mockLinkService{
 		bulkCreateLinksFn: func(_ context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, error) {
@@ -376,6 +498,33 @@ func TestBulkCreateLinks_Success(t *testing.T) {
 	}
 }
 
+func TestBulkCreateLinks_Async(t *testing.T) {
+	svc := &mockLinkService{
+		bulkLinkJobFn: func(_ context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) (*models.BulkLinkJob, error) {
+			return &models.BulkLinkJob{
+				ID:          uuid.New(),
+				WorkspaceID: workspaceID,
+				RequestedBy: userID,
+				Status:      models.BulkLinkJobPending,
+				Requested:   len(input.Links),
+			}, nil
+		},
+	}
+
+	r := setupTestRouter(svc, true)
+
+	body := `{"links":[{"url":"https://example.com"},{"url":"https://example.org"}]}`
+	req := httptest.NewRequest("POST", linkURL("/bulk"), bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status %d, got %d (body: %s)", http.StatusAccepted, w.Code, w.Body.String())
+	}
+}
+
 func TestGetQuickStats_Success(t *testing.T) {
 	linkID := uuid.New()
 
@@ -435,3 +584,141 @@ func TestGetQuickStats_NotFound(t *testing.T) {
 		t.Errorf("expected status %d, got %d (body: %s)", http.StatusNotFound, w.Code, w.Body.String())
 	}
 }
+
+func TestExportLinks_CSV(t *testing.T) {
+	svc := &mockLinkService{
+		exportLinksFn: func(_ context.Context, workspaceID uuid.UUID, _ models.DateRange, format models.AnalyticsExportFormat, w io.Writer) error {
+			if workspaceID != testWorkspaceID {
+				t.Errorf("expected workspace_id %s, got %s", testWorkspaceID, workspaceID)
+			}
+			if format != models.ExportCSV {
+				t.Errorf("expected csv format, got %s", format)
+			}
+			_, err := w.Write([]byte("id,short_code\n"))
+			return err
+		},
+	}
+
+	r := setupTestRouter(svc, true)
+
+	req := httptest.NewRequest("GET", linkURL("/export?format=csv&range=30d"), nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("expected Content-Type text/csv, got %s", ct)
+	}
+	if w.Body.String() != "id,short_code\n" {
+		t.Errorf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestExportLinks_ErrorBeforeOutput(t *testing.T) {
+	svc := &mockLinkService{
+		exportLinksFn: func(_ context.Context, _ uuid.UUID, _ models.DateRange, _ models.AnalyticsExportFormat, _ io.Writer) error {
+			return httputil.PaymentRequiredWithDetails("export_data", "pro")
+		},
+	}
+
+	r := setupTestRouter(svc, true)
+
+	req := httptest.NewRequest("GET", linkURL("/export?format=json"), nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPaymentRequired {
+		t.Errorf("expected status %d, got %d", http.StatusPaymentRequired, w.Code)
+	}
+}
+
+func TestExportLinks_UnknownRangePreset(t *testing.T) {
+	svc := &mockLinkService{
+		exportLinksFn: func(_ context.Context, _ uuid.UUID, _ models.DateRange, _ models.AnalyticsExportFormat, _ io.Writer) error {
+			t.Error("export should not run for an unknown preset")
+			return nil
+		},
+	}
+
+	r := setupTestRouter(svc, true)
+
+	req := httptest.NewRequest("GET", linkURL("/export?format=csv&range=2w"), nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestParseDateRange_CalendarPresets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(query string) models.DateRange {
+		t.Helper()
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+		dr, err := parseDateRange(c)
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return dr
+	}
+
+	dr := parse("range=yesterday")
+	if dr.End.Sub(dr.Start) != 24*time.Hour || dr.End.Hour() != 0 || dr.End.Minute() != 0 {
+		t.Errorf("yesterday = %v – %v, want midnight to midnight", dr.Start, dr.End)
+	}
+
+	dr = parse("range=mtd")
+	if dr.Start.Day() != 1 || dr.Start.Hour() != 0 || dr.End.Before(dr.Start) {
+		t.Errorf("mtd = %v – %v, want from the first of the month", dr.Start, dr.End)
+	}
+}
+
+func TestBulkDeleteLinks_RoutesToBulkHandler(t *testing.T) {
+	linkID := uuid.New()
+	svc := &mockLinkService{
+		deleteLinkFn: func(_ context.Context, _, _ uuid.UUID) error {
+			t.Error("single delete should not be called")
+			return nil
+		},
+		bulkDeleteLinksFn: func(_ context.Context, workspaceID uuid.UUID, input models.BulkDeleteLinksInput) (*models.BulkLinkResult, error) {
+			if workspaceID != testWorkspaceID {
+				t.Errorf("expected workspace %s, got %s", testWorkspaceID, workspaceID)
+			}
+			return &models.BulkLinkResult{Requested: 1, Succeeded: 1, LinkIDs: input.LinkIDs}, nil
+		},
+	}
+
+	r := setupTestRouter(svc, true)
+
+	body := `{"link_ids":["` + linkID.String() + `"]}`
+	req := httptest.NewRequest("DELETE", linkURL("/bulk"), bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d (body: %s)", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestBulkUpdateLinks_RequiresIDs(t *testing.T) {
+	r := setupTestRouter(&mockLinkService{}, true)
+
+	req := httptest.NewRequest("PATCH", linkURL("/bulk"), bytes.NewBufferString(`{"is_active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
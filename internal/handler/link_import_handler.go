@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkImportHandler struct {
+	importService service.LinkImportService
+	logger        *zap.Logger
+}
+
+func NewLinkImportHandler(importService service.LinkImportService, logger *zap.Logger) *LinkImportHandler {
+	return &LinkImportHandler{importService: importService, logger: logger}
+}
+
+func (h *LinkImportHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.HandlerFunc) {
+	imports := wsScoped.Group("/link-imports", editorMw)
+	{
+		imports.POST("", h.StartImport)
+		imports.GET("/:importId", h.GetImport)
+	}
+}
+
+func (h *LinkImportHandler) StartImport(c *gin.Context) {
+	user := middleware.GetUserFromContext(c)
+	ws := middleware.GetWorkspaceFromContext(c)
+	if user == nil || ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.StartLinkImportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	job, err := h.importService.StartImport(c.Request.Context(), ws.ID, user.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusAccepted, job)
+}
+
+func (h *LinkImportHandler) GetImport(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	importID, err := uuid.Parse(c.Param("importId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("importId", "invalid import ID"))
+		return
+	}
+
+	job, err := h.importService.GetImport(c.Request.Context(), ws.ID, importID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, job)
+}
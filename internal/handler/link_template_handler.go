@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkTemplateHandler struct {
+	templateService service.LinkTemplateService
+	logger          *zap.Logger
+}
+
+func NewLinkTemplateHandler(templateService service.LinkTemplateService, logger *zap.Logger) *LinkTemplateHandler {
+	return &LinkTemplateHandler{templateService: templateService, logger: logger}
+}
+
+func (h *LinkTemplateHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.HandlerFunc) {
+	read := middleware.RequireAPIKeyScope(models.ScopeLinksRead)
+	write := middleware.RequireAPIKeyScope(models.ScopeLinksWrite)
+
+	templates := wsScoped.Group("/link-templates")
+	{
+		templates.GET("", read, h.ListTemplates)
+		templates.GET("/:id", read, h.GetTemplate)
+
+		templates.POST("", write, editorMw, h.CreateTemplate)
+		templates.PUT("/:id", write, editorMw, h.UpdateTemplate)
+		templates.DELETE("/:id", write, editorMw, h.DeleteTemplate)
+	}
+}
+
+func (h *LinkTemplateHandler) CreateTemplate(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	user := middleware.GetUserFromContext(c)
+	if ws == nil || user == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.CreateLinkTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	tmpl, err := h.templateService.CreateTemplate(c.Request.Context(), ws.ID, user.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusCreated, tmpl)
+}
+
+func (h *LinkTemplateHandler) ListTemplates(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	templates, err := h.templateService.ListTemplates(c.Request.Context(), ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, templates)
+}
+
+func (h *LinkTemplateHandler) GetTemplate(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid template ID"))
+		return
+	}
+
+	tmpl, err := h.templateService.GetTemplate(c.Request.Context(), id, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, tmpl)
+}
+
+func (h *LinkTemplateHandler) UpdateTemplate(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid template ID"))
+		return
+	}
+
+	var input models.UpdateLinkTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	tmpl, err := h.templateService.UpdateTemplate(c.Request.Context(), id, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, tmpl)
+}
+
+func (h *LinkTemplateHandler) DeleteTemplate(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid template ID"))
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), id, ws.ID); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "link template deleted successfully"})
+}
@@ -2,11 +2,14 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/link-rift/link-rift/internal/middleware"
 	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/qrcode"
 	"github.com/link-rift/link-rift/internal/service"
 	"github.com/link-rift/link-rift/pkg/httputil"
 	"go.uber.org/zap"
@@ -21,18 +24,24 @@ func NewQRHandler(qrService service.QRCodeService, logger *zap.Logger) *QRHandle
 	return &QRHandler{qrService: qrService, logger: logger}
 }
 
+// RegisterRoutes registers QR code routes under a workspace-scoped group.
+// API keys need the qr:read or qr:write scope.
 func (h *QRHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.HandlerFunc) {
+	read := middleware.RequireAPIKeyScope(models.ScopeQRRead)
+	write := middleware.RequireAPIKeyScope(models.ScopeQRWrite)
+
 	links := wsScoped.Group("/links")
 	{
-		links.POST("/:id/qr", editorMw, h.CreateQRCode)
-		links.GET("/:id/qr", h.GetQRCodeForLink)
-		links.GET("/:id/qr/download", h.DownloadQRCode)
+		links.POST("/:id/qr", write, editorMw, h.CreateQRCode)
+		links.GET("/:id/qr", read, h.GetQRCodeForLink)
+		links.GET("/:id/qr/download", read, h.DownloadQRCode)
 	}
 
 	qr := wsScoped.Group("/qr")
 	{
-		qr.POST("/bulk", editorMw, h.BulkGenerateQRCodes)
-		qr.GET("/templates", h.GetStyleTemplates)
+		qr.POST("/bulk", write, editorMw, h.BulkGenerateQRCodes)
+		qr.GET("/templates", read, h.GetStyleTemplates)
+		qr.PUT("/:id", write, editorMw, h.UpdateQRCode)
 	}
 }
 
@@ -51,7 +60,7 @@ func (h *QRHandler) CreateQRCode(c *gin.Context) {
 
 	var input models.CreateQRCodeInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -64,6 +73,34 @@ func (h *QRHandler) CreateQRCode(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusCreated, qr.ToResponse())
 }
 
+func (h *QRHandler) UpdateQRCode(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	qrID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid QR code ID"))
+		return
+	}
+
+	var input models.UpdateQRCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	qr, err := h.qrService.UpdateQRCode(c.Request.Context(), qrID, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, qr.ToResponse())
+}
+
 func (h *QRHandler) GetQRCodeForLink(c *gin.Context) {
 	linkID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -88,8 +125,31 @@ func (h *QRHandler) DownloadQRCode(c *gin.Context) {
 	}
 
 	format := c.DefaultQuery("format", "png")
+	switch format {
+	case "png", "svg", "pdf", "eps":
+	default:
+		httputil.RespondError(c, httputil.Validation("format", "format must be png, svg, pdf or eps"))
+		return
+	}
 
-	data, contentType, err := h.qrService.DownloadQRCode(c.Request.Context(), linkID, format)
+	var query models.QRPrintQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.RespondError(c, httputil.BindingError("query", err))
+		return
+	}
+	var print *qrcode.PrintProfile
+	if query.WidthMM > 0 {
+		print = &qrcode.PrintProfile{
+			WidthMM:   query.WidthMM,
+			DPI:       query.DPI,
+			QuietZone: query.QuietZone,
+		}
+		if print.DPI == 0 {
+			print.DPI = 300
+		}
+	}
+
+	data, contentType, err := h.qrService.DownloadQRCode(c.Request.Context(), linkID, format, print)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -100,6 +160,9 @@ func (h *QRHandler) DownloadQRCode(c *gin.Context) {
 	c.Data(http.StatusOK, contentType, data)
 }
 
+// BulkGenerateQRCodes returns a ZIP of the generated QR codes. Clients that
+// accept text/event-stream instead receive "progress" events as items finish
+// and a final "complete" event with a download URL for the ZIP.
 func (h *QRHandler) BulkGenerateQRCodes(c *gin.Context) {
 	ws := middleware.GetWorkspaceFromContext(c)
 	if ws == nil {
@@ -109,11 +172,16 @@ func (h *QRHandler) BulkGenerateQRCodes(c *gin.Context) {
 
 	var input models.BulkQRCodeInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
-	result, err := h.qrService.BulkGenerateQRCodes(c.Request.Context(), ws.ID, input)
+	if strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		h.streamBulkGenerate(c, ws.ID, input)
+		return
+	}
+
+	result, err := h.qrService.BulkGenerateQRCodes(c.Request.Context(), ws.ID, input, nil)
 	if err != nil {
 		httputil.RespondError(c, err)
 		return
@@ -121,9 +189,71 @@ func (h *QRHandler) BulkGenerateQRCodes(c *gin.Context) {
 
 	// Return ZIP file
 	c.Header("Content-Disposition", "attachment; filename=qr_codes.zip")
+	c.Header("X-QR-Failed-Count", strconv.Itoa(result.Failed()))
 	c.Data(http.StatusOK, "application/zip", result.ZipData)
 }
 
+// streamBulkGenerate runs a bulk generation as server-sent events. The stream
+// starts with the first progress event, so validation errors are still
+// returned as regular JSON errors.
+func (h *QRHandler) streamBulkGenerate(c *gin.Context, workspaceID uuid.UUID, input models.BulkQRCodeInput) {
+	started := false
+	progress := func(p qrcode.BatchProgress) {
+		if !started {
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("X-Accel-Buffering", "no")
+			started = true
+		}
+		event := gin.H{
+			"link_id":   p.LinkID,
+			"completed": p.Completed,
+			"failed":    p.Failed,
+			"total":     p.Total,
+		}
+		if p.Error != nil {
+			event["error"] = p.Error.Error()
+		}
+		c.SSEvent("progress", event)
+		c.Writer.Flush()
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.qrService.BulkGenerateQRCodes(ctx, workspaceID, input, progress)
+	if err == nil {
+		var url string
+		url, err = h.qrService.StoreBatchArchive(ctx, workspaceID, result.ZipData)
+		if err == nil {
+			failures := make([]gin.H, 0, result.Failed())
+			for _, item := range result.Results {
+				if item.Error != nil {
+					failures = append(failures, gin.H{"link_id": item.LinkID, "error": item.Error.Error()})
+				}
+			}
+			c.SSEvent("complete", gin.H{
+				"total":        len(result.Results),
+				"failed":       len(failures),
+				"failures":     failures,
+				"download_url": url,
+			})
+			c.Writer.Flush()
+			return
+		}
+	}
+
+	if !started {
+		httputil.RespondError(c, err)
+		return
+	}
+	if ctx.Err() != nil {
+		// The client went away; there's no one to tell.
+		return
+	}
+	h.logger.Warn("bulk QR generation failed mid-stream", zap.Error(err))
+	c.SSEvent("error", gin.H{"message": "bulk QR generation failed"})
+	c.Writer.Flush()
+}
+
 func (h *QRHandler) GetStyleTemplates(c *gin.Context) {
 	templates := h.qrService.GetStyleTemplates()
 	httputil.RespondSuccess(c, http.StatusOK, templates)
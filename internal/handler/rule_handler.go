@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type RuleHandler struct {
+	ruleService service.RuleService
+	logger      *zap.Logger
+}
+
+func NewRuleHandler(ruleService service.RuleService, logger *zap.Logger) *RuleHandler {
+	return &RuleHandler{ruleService: ruleService, logger: logger}
+}
+
+func (h *RuleHandler) RegisterRoutes(wsScoped *gin.RouterGroup, editorMw gin.HandlerFunc) {
+	rules := wsScoped.Group("/links/:id/rules")
+	{
+		rules.GET("", h.ListRules)
+
+		rules.POST("", editorMw, h.CreateRule)
+		rules.PUT("/:ruleId", editorMw, h.UpdateRule)
+		rules.DELETE("/:ruleId", editorMw, h.DeleteRule)
+	}
+
+	// Any member can preview where a visitor would be sent
+	wsScoped.POST("/links/:id/simulate", h.SimulateRedirect)
+}
+
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	var input models.CreateLinkRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	rule, err := h.ruleService.CreateRule(c.Request.Context(), linkID, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusCreated, rule)
+}
+
+func (h *RuleHandler) ListRules(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	rules, err := h.ruleService.ListRules(c.Request.Context(), linkID, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, rules)
+}
+
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("ruleId", "invalid rule ID"))
+		return
+	}
+
+	var input models.UpdateLinkRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	rule, err := h.ruleService.UpdateRule(c.Request.Context(), ruleID, linkID, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, rule)
+}
+
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("ruleId", "invalid rule ID"))
+		return
+	}
+
+	if err := h.ruleService.DeleteRule(c.Request.Context(), ruleID, linkID, ws.ID); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "rule deleted successfully"})
+}
+
+func (h *RuleHandler) SimulateRedirect(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid link ID"))
+		return
+	}
+
+	var input models.SimulateRedirectInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	simulation, err := h.ruleService.SimulateRedirect(c.Request.Context(), linkID, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, simulation)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type ScheduledReportHandler struct {
+	reportService service.ScheduledReportService
+	logger        *zap.Logger
+}
+
+func NewScheduledReportHandler(reportService service.ScheduledReportService, logger *zap.Logger) *ScheduledReportHandler {
+	return &ScheduledReportHandler{reportService: reportService, logger: logger}
+}
+
+func (h *ScheduledReportHandler) RegisterRoutes(wsScoped *gin.RouterGroup, adminMw gin.HandlerFunc) {
+	reports := wsScoped.Group("/reports", adminMw)
+	{
+		reports.GET("", h.ListReports)
+		reports.POST("", h.CreateReport)
+		reports.GET("/:id", h.GetReport)
+		reports.PUT("/:id", h.UpdateReport)
+		reports.DELETE("/:id", h.DeleteReport)
+	}
+}
+
+func (h *ScheduledReportHandler) CreateReport(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	user := middleware.GetUserFromContext(c)
+	if ws == nil || user == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var input models.CreateScheduledReportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	report, err := h.reportService.CreateReport(c.Request.Context(), ws.ID, user.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusCreated, report)
+}
+
+func (h *ScheduledReportHandler) ListReports(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	reports, err := h.reportService.ListReports(c.Request.Context(), ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, reports)
+}
+
+func (h *ScheduledReportHandler) GetReport(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid report ID"))
+		return
+	}
+
+	report, err := h.reportService.GetReport(c.Request.Context(), id, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, report)
+}
+
+func (h *ScheduledReportHandler) UpdateReport(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid report ID"))
+		return
+	}
+
+	var input models.UpdateScheduledReportInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.RespondError(c, httputil.BindingError("body", err))
+		return
+	}
+
+	report, err := h.reportService.UpdateReport(c.Request.Context(), id, ws.ID, input)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, report)
+}
+
+func (h *ScheduledReportHandler) DeleteReport(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid report ID"))
+		return
+	}
+
+	if err := h.reportService.DeleteReport(c.Request.Context(), id, ws.ID); err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "scheduled report deleted successfully"})
+}
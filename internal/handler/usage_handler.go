@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/link-rift/link-rift/internal/middleware"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type UsageHandler struct {
+	usageService service.UsageService
+	logger       *zap.Logger
+}
+
+func NewUsageHandler(usageService service.UsageService, logger *zap.Logger) *UsageHandler {
+	return &UsageHandler{usageService: usageService, logger: logger}
+}
+
+func (h *UsageHandler) RegisterRoutes(wsScoped *gin.RouterGroup, adminMw gin.HandlerFunc) {
+	usage := wsScoped.Group("/usage", adminMw)
+	{
+		usage.GET("", h.GetUsage)
+		usage.GET("/snapshots", h.ListSnapshots)
+	}
+}
+
+// GetUsage returns the workspace's live usage for ?month=YYYY-MM, or the
+// current month.
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	month := time.Now()
+	if m := c.Query("month"); m != "" {
+		parsed, err := time.Parse("2006-01", m)
+		if err != nil {
+			httputil.RespondError(c, httputil.Validation("month", "must be formatted as YYYY-MM"))
+			return
+		}
+		month = parsed
+	}
+
+	usage, err := h.usageService.GetUsage(c.Request.Context(), ws.ID, month)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, usage)
+}
+
+func (h *UsageHandler) ListSnapshots(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	snapshots, err := h.usageService.ListSnapshots(c.Request.Context(), ws.ID, limit)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, snapshots)
+}
@@ -27,7 +27,10 @@ func (h *WebhookHandler) RegisterRoutes(wsScoped *gin.RouterGroup, adminMw gin.H
 		webhooks.GET("", h.ListWebhooks)
 		webhooks.POST("", adminMw, h.CreateWebhook)
 		webhooks.DELETE("/:id", adminMw, h.DeleteWebhook)
+		webhooks.POST("/:id/rotate-secret", adminMw, h.RotateSecret)
 		webhooks.GET("/:id/deliveries", h.ListDeliveries)
+		webhooks.GET("/dlq", adminMw, h.ListDeadLetters)
+		webhooks.POST("/dlq/reprocess", adminMw, h.ReprocessDeadLetters)
 	}
 }
 
@@ -40,7 +43,7 @@ func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
 
 	var input models.CreateWebhookInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -90,6 +93,28 @@ func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
 	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "webhook deleted successfully"})
 }
 
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("id", "invalid webhook ID"))
+		return
+	}
+
+	result, err := h.webhookService.RotateSecret(c.Request.Context(), id, ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, result)
+}
+
 func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
 	ws := middleware.GetWorkspaceFromContext(c)
 	if ws == nil {
@@ -105,7 +130,7 @@ func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
 
 	var pagination models.Pagination
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		httputil.RespondError(c, httputil.Validation("query", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("query", err))
 		return
 	}
 	if pagination.Limit == 0 {
@@ -123,3 +148,44 @@ func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
 
 	httputil.RespondList(c, deliveries, total, pagination.Limit, pagination.Offset)
 }
+
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var pagination models.Pagination
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		httputil.RespondError(c, httputil.BindingError("query", err))
+		return
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = 20
+	}
+
+	entries, total, err := h.webhookService.ListDeadLetters(c.Request.Context(), ws.ID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondList(c, entries, total, pagination.Limit, pagination.Offset)
+}
+
+func (h *WebhookHandler) ReprocessDeadLetters(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	if ws == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	result, err := h.webhookService.ReprocessDeadLetters(c.Request.Context(), ws.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, result)
+}
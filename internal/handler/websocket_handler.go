@@ -2,12 +2,14 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/link-rift/link-rift/internal/realtime"
 	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/service"
 	"github.com/link-rift/link-rift/pkg/httputil"
 	"github.com/link-rift/link-rift/pkg/paseto"
 	"go.uber.org/zap"
@@ -23,20 +25,26 @@ var upgrader = websocket.Upgrader{
 
 type WebSocketHandler struct {
 	hub        *realtime.Hub
+	limiter    *realtime.ConnLimiter
 	tokenMaker paseto.Maker
+	sessions   service.SessionValidator
 	memberRepo repository.WorkspaceMemberRepository
 	logger     *zap.Logger
 }
 
 func NewWebSocketHandler(
 	hub *realtime.Hub,
+	limiter *realtime.ConnLimiter,
 	tokenMaker paseto.Maker,
+	sessions service.SessionValidator,
 	memberRepo repository.WorkspaceMemberRepository,
 	logger *zap.Logger,
 ) *WebSocketHandler {
 	return &WebSocketHandler{
 		hub:        hub,
+		limiter:    limiter,
 		tokenMaker: tokenMaker,
+		sessions:   sessions,
 		memberRepo: memberRepo,
 		logger:     logger,
 	}
@@ -61,6 +69,16 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	active, err := h.sessions.IsSessionActive(c.Request.Context(), claims.SessionID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+	if !active {
+		httputil.RespondError(c, httputil.Unauthorized("session has been revoked"))
+		return
+	}
+
 	// Parse workspace ID
 	wsIDStr := c.Query("workspace_id")
 	if wsIDStr == "" {
@@ -88,9 +106,28 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Refuse connections over the limits before they reach the hub. The
+	// close frame carries the reason, which browsers can't get from a
+	// refused handshake.
+	ip := c.ClientIP()
+	if err := h.limiter.Acquire(ip, claims.UserID); err != nil {
+		h.logger.Warn("websocket connection refused",
+			zap.String("ip", ip),
+			zap.String("user_id", claims.UserID.String()),
+			zap.Error(err),
+		)
+		msg := websocket.FormatCloseMessage(realtime.CloseCode(err), err.Error())
+		_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+		conn.Close()
+		return
+	}
+
 	client := realtime.NewClient(h.hub, conn, workspaceID)
 	h.hub.Register(client)
 
 	go client.WritePump()
-	go client.ReadPump()
+	go func() {
+		client.ReadPump()
+		h.limiter.Release(ip, claims.UserID)
+	}()
 }
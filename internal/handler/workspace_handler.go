@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -45,6 +46,11 @@ func (h *WorkspaceHandler) RegisterRoutes(v1 *gin.RouterGroup, authMw gin.Handle
 		ws.PUT("/members/:userId", adminMw, h.UpdateMemberRole)
 		ws.DELETE("/members/:userId", adminMw, h.RemoveMember)
 		ws.POST("/transfer", ownerMw, h.TransferOwnership)
+
+		ws.POST("/exports", ownerMw, h.ExportWorkspace)
+		ws.GET("/exports/:exportId", ownerMw, h.GetExport)
+		ws.GET("/exports/:exportId/download", ownerMw, h.DownloadExport)
+		ws.POST("/imports", ownerMw, h.ImportWorkspace)
 	}
 }
 
@@ -57,7 +63,7 @@ func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
 
 	var input models.CreateWorkspaceInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -119,7 +125,7 @@ func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
 
 	var input models.UpdateWorkspaceInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -181,7 +187,7 @@ func (h *WorkspaceHandler) InviteMember(c *gin.Context) {
 
 	var input models.InviteMemberInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -210,7 +216,7 @@ func (h *WorkspaceHandler) UpdateMemberRole(c *gin.Context) {
 
 	var input models.UpdateMemberRoleInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -255,7 +261,7 @@ func (h *WorkspaceHandler) TransferOwnership(c *gin.Context) {
 
 	var input models.TransferOwnershipInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		httputil.RespondError(c, httputil.Validation("body", err.Error()))
+		httputil.RespondError(c, httputil.BindingError("body", err))
 		return
 	}
 
@@ -266,3 +272,112 @@ func (h *WorkspaceHandler) TransferOwnership(c *gin.Context) {
 
 	httputil.RespondSuccess(c, http.StatusOK, gin.H{"message": "ownership transferred successfully"})
 }
+
+func (h *WorkspaceHandler) ExportWorkspace(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	user := middleware.GetUserFromContext(c)
+	if ws == nil || user == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	export, err := h.wsService.ExportWorkspace(c.Request.Context(), ws.ID, user.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	httputil.RespondSuccess(c, http.StatusAccepted, export)
+}
+
+func (h *WorkspaceHandler) GetExport(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	user := middleware.GetUserFromContext(c)
+	if ws == nil || user == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("exportId", "invalid export ID"))
+		return
+	}
+
+	export, err := h.wsService.GetExport(c.Request.Context(), ws.ID, exportID, user.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	if export.Status == models.WorkspaceExportCompleted {
+		export.DownloadURL = c.Request.URL.Path + "/download"
+	}
+
+	httputil.RespondSuccess(c, http.StatusOK, export)
+}
+
+func (h *WorkspaceHandler) DownloadExport(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	user := middleware.GetUserFromContext(c)
+	if ws == nil || user == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("exportId", "invalid export ID"))
+		return
+	}
+
+	data, err := h.wsService.DownloadExport(c.Request.Context(), ws.ID, exportID, user.ID)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=workspace-"+ws.Slug+"-export.zip")
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// maxImportArchiveSize caps the size of an uploaded export archive.
+const maxImportArchiveSize = 50 << 20
+
+// ImportWorkspace applies an export archive sent as the request body.
+func (h *WorkspaceHandler) ImportWorkspace(c *gin.Context) {
+	ws := middleware.GetWorkspaceFromContext(c)
+	user := middleware.GetUserFromContext(c)
+	if ws == nil || user == nil {
+		httputil.RespondError(c, httputil.Forbidden("workspace access required"))
+		return
+	}
+
+	var opts models.WorkspaceImportOptions
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		httputil.RespondError(c, httputil.BindingError("query", err))
+		return
+	}
+
+	archive, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportArchiveSize))
+	if err != nil {
+		httputil.RespondError(c, httputil.Validation("archive", "archive must be at most 50 MB"))
+		return
+	}
+	if len(archive) == 0 {
+		httputil.RespondError(c, httputil.Validation("archive", "request body must contain the export archive"))
+		return
+	}
+
+	result, err := h.wsService.ImportWorkspace(c.Request.Context(), ws.ID, user.ID, archive, opts)
+	if err != nil {
+		httputil.RespondError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if result.DryRun {
+		status = http.StatusOK
+	}
+	httputil.RespondSuccess(c, status, result)
+}
@@ -16,10 +16,15 @@ const (
 	FeatureWebhooks          Feature = "webhooks"
 	FeatureQRCustomization   Feature = "qr_customization"
 	FeatureBioPages          Feature = "bio_pages"
+	FeatureCustomBioThemes   Feature = "custom_bio_themes"
+	FeatureBioEmailCapture   Feature = "bio_email_capture"
 	FeatureConditionalRouting Feature = "conditional_routing"
+	FeatureLinkCloaking      Feature = "link_cloaking"
+	FeatureRetargetingPixels Feature = "retargeting_pixels"
 	FeatureSAML              Feature = "saml"
 	FeatureSCIM              Feature = "scim"
 	FeatureAuditLogs         Feature = "audit_logs"
+	FeatureSessionPolicies   Feature = "session_policies"
 	FeatureWhiteLabel        Feature = "white_label"
 	FeatureCustomCSS         Feature = "custom_css"
 	FeaturePrioritySupport   Feature = "priority_support"
@@ -107,12 +112,36 @@ var featureRegistry = map[Feature]FeatureDefinition{
 		MinTier:     TierPro,
 		Category:    "pages",
 	},
+	FeatureCustomBioThemes: {
+		Name:        "Custom Bio Themes",
+		Description: "Save your own colors, fonts, and gradients as bio page themes",
+		MinTier:     TierPro,
+		Category:    "pages",
+	},
+	FeatureBioEmailCapture: {
+		Name:        "Bio Email Capture",
+		Description: "Collect visitor emails with a form block on bio pages",
+		MinTier:     TierPro,
+		Category:    "pages",
+	},
 	FeatureConditionalRouting: {
 		Name:        "Conditional Routing",
 		Description: "Route clicks based on device, location, or time rules",
 		MinTier:     TierBusiness,
 		Category:    "links",
 	},
+	FeatureLinkCloaking: {
+		Name:        "Link Cloaking",
+		Description: "Keep the short URL in the address bar by framing the destination",
+		MinTier:     TierPro,
+		Category:    "links",
+	},
+	FeatureRetargetingPixels: {
+		Name:        "Retargeting Pixels",
+		Description: "Fire Facebook and Google retargeting pixels before redirecting",
+		MinTier:     TierBusiness,
+		Category:    "links",
+	},
 	FeatureSAML: {
 		Name:        "SAML SSO",
 		Description: "Enterprise single sign-on via SAML 2.0",
@@ -131,6 +160,12 @@ var featureRegistry = map[Feature]FeatureDefinition{
 		MinTier:     TierEnterprise,
 		Category:    "security",
 	},
+	FeatureSessionPolicies: {
+		Name:        "Session Policies",
+		Description: "Enforce shorter session and token lifetimes per workspace",
+		MinTier:     TierEnterprise,
+		Category:    "security",
+	},
 	FeatureWhiteLabel: {
 		Name:        "White Label",
 		Description: "Remove Linkrift branding and add your own",
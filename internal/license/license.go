@@ -77,6 +77,7 @@ type LicenseResponse struct {
 	Features     []Feature         `json:"features"`
 	Limits       Limits            `json:"limits"`
 	IsCommunity  bool              `json:"is_community"`
+	Warnings     []LimitWarning    `json:"warnings,omitempty"`
 }
 
 // ToResponse converts a License to a safe API response.
@@ -472,7 +472,7 @@ func TestManagerPeriodicCheck(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	mgr.StartPeriodicCheck(ctx, 100*time.Millisecond)
+	mgr.StartPeriodicCheck(ctx, 100*time.Millisecond, 0)
 
 	// Wait for license to expire and periodic check to fire
 	time.Sleep(300 * time.Millisecond)
@@ -482,6 +482,77 @@ func TestManagerPeriodicCheck(t *testing.T) {
 	}
 }
 
+func TestManagerPeriodicCheckRunsAtStart(t *testing.T) {
+	signer := GenerateKeyPair(t)
+	verifier, err := NewVerifierWithKey(signer.PublicKeyPEM())
+	if err != nil {
+		t.Fatalf("create verifier: %v", err)
+	}
+
+	mgr := NewManager(verifier, zap.NewNop())
+	lic := newTestLicense()
+	lic.ExpiresAt = time.Now().Add(50 * time.Millisecond)
+	if err := mgr.LoadLicense(signer.SignToString(t, lic)); err != nil {
+		t.Fatalf("LoadLicense: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first check doesn't wait for the interval
+	mgr.StartPeriodicCheck(ctx, time.Hour, time.Minute)
+
+	deadline := time.Now().Add(time.Second)
+	for !mgr.IsCommunity() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !mgr.IsCommunity() {
+		t.Error("expired license not reverted by the check at start")
+	}
+}
+
+func TestManagerRevalidateNow(t *testing.T) {
+	signer := GenerateKeyPair(t)
+	verifier, err := NewVerifierWithKey(signer.PublicKeyPEM())
+	if err != nil {
+		t.Fatalf("create verifier: %v", err)
+	}
+
+	mgr := NewManager(verifier, zap.NewNop())
+	if err := mgr.RevalidateNow(); err != nil {
+		t.Errorf("community edition: RevalidateNow() = %v", err)
+	}
+
+	lic := newTestLicense()
+	lic.ExpiresAt = time.Now().Add(50 * time.Millisecond)
+	if err := mgr.LoadLicense(signer.SignToString(t, lic)); err != nil {
+		t.Fatalf("LoadLicense: %v", err)
+	}
+	if err := mgr.RevalidateNow(); err != nil || mgr.IsCommunity() {
+		t.Fatalf("valid license: RevalidateNow() = %v, community = %v", err, mgr.IsCommunity())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if err := mgr.RevalidateNow(); err == nil {
+		t.Error("expected an error for the expired license")
+	}
+	if !mgr.IsCommunity() {
+		t.Error("should revert to community after revalidating an expired license")
+	}
+}
+
+func TestCheckDelay(t *testing.T) {
+	if got := checkDelay(time.Hour, 0); got != time.Hour {
+		t.Errorf("checkDelay without jitter = %v, want 1h", got)
+	}
+	for i := 0; i < 100; i++ {
+		if got := checkDelay(time.Hour, time.Minute); got < time.Hour || got >= time.Hour+time.Minute {
+			t.Fatalf("checkDelay = %v, want within a minute after 1h", got)
+		}
+	}
+}
+
 func TestManagerGetLicenseResponse(t *testing.T) {
 	signer := GenerateKeyPair(t)
 	verifier, err := NewVerifierWithKey(signer.PublicKeyPEM())
@@ -529,3 +600,56 @@ func TestManagerLoadLicenseDefaultLimits(t *testing.T) {
 		t.Errorf("MaxUsers = %d, want %d (tier default)", mgr.GetLimits().MaxUsers, expected.MaxUsers)
 	}
 }
+
+func TestManagerLoadLicenseBackfillsBioLinkLimit(t *testing.T) {
+	signer := GenerateKeyPair(t)
+	verifier, err := NewVerifierWithKey(signer.PublicKeyPEM())
+	if err != nil {
+		t.Fatalf("create verifier: %v", err)
+	}
+
+	mgr := NewManager(verifier, zap.NewNop())
+
+	// Custom limits that predate max_bio_links
+	lic := newTestLicense()
+	key := signer.SignToString(t, lic)
+	if err := mgr.LoadLicense(key); err != nil {
+		t.Fatalf("LoadLicense: %v", err)
+	}
+
+	limits := mgr.GetLimits()
+	if limits.MaxUsers != lic.Limits.MaxUsers {
+		t.Errorf("MaxUsers = %d, want %d (from license)", limits.MaxUsers, lic.Limits.MaxUsers)
+	}
+	if want := DefaultLimits(TierPro).MaxBioLinks; limits.MaxBioLinks != want {
+		t.Errorf("MaxBioLinks = %d, want %d (tier default)", limits.MaxBioLinks, want)
+	}
+}
+
+func TestManagerOnChange(t *testing.T) {
+	signer := GenerateKeyPair(t)
+	verifier, err := NewVerifierWithKey(signer.PublicKeyPEM())
+	if err != nil {
+		t.Fatalf("create verifier: %v", err)
+	}
+
+	mgr := NewManager(verifier, zap.NewNop())
+	var tiers []Tier
+	mgr.OnChange(func() {
+		tiers = append(tiers, mgr.GetTier())
+		mgr.SetLimitWarnings([]LimitWarning{{Limit: LimitMaxUsers, Current: 2, Max: 1}})
+	})
+
+	if err := mgr.LoadLicense(signer.SignToString(t, newTestLicense())); err != nil {
+		t.Fatalf("LoadLicense: %v", err)
+	}
+	// Warnings set by a callback are in the very next response.
+	if got := mgr.GetLicenseResponse().Warnings; len(got) != 1 {
+		t.Errorf("warnings = %+v, want 1", got)
+	}
+
+	mgr.RemoveLicense()
+	if len(tiers) != 2 || tiers[0] != TierPro || tiers[1] != TierFree {
+		t.Errorf("callbacks saw tiers %v, want [pro free]", tiers)
+	}
+}
@@ -11,6 +11,7 @@ const (
 	LimitMaxWorkspaces         LimitType = "max_workspaces"
 	LimitMaxAPIRequestsPerMin  LimitType = "max_api_requests_per_min"
 	LimitAnalyticsRetentionDays LimitType = "analytics_retention_days"
+	LimitMaxBioLinks           LimitType = "max_bio_links"
 )
 
 // Limits holds usage limits for a license tier.
@@ -22,6 +23,7 @@ type Limits struct {
 	MaxWorkspaces           int64 `json:"max_workspaces"`
 	MaxAPIRequestsPerMin    int64 `json:"max_api_requests_per_min"`
 	AnalyticsRetentionDays  int64 `json:"analytics_retention_days"`
+	MaxBioLinks             int64 `json:"max_bio_links"` // per bio page
 }
 
 var defaultLimits = map[Tier]Limits{
@@ -33,6 +35,7 @@ var defaultLimits = map[Tier]Limits{
 		MaxWorkspaces:          1,
 		MaxAPIRequestsPerMin:   10,
 		AnalyticsRetentionDays: 30,
+		MaxBioLinks:            10,
 	},
 	TierPro: {
 		MaxUsers:               5,
@@ -42,6 +45,7 @@ var defaultLimits = map[Tier]Limits{
 		MaxWorkspaces:          3,
 		MaxAPIRequestsPerMin:   60,
 		AnalyticsRetentionDays: 365,
+		MaxBioLinks:            50,
 	},
 	TierBusiness: {
 		MaxUsers:               25,
@@ -51,6 +55,7 @@ var defaultLimits = map[Tier]Limits{
 		MaxWorkspaces:          10,
 		MaxAPIRequestsPerMin:   300,
 		AnalyticsRetentionDays: 730,
+		MaxBioLinks:            200,
 	},
 	TierEnterprise: {
 		MaxUsers:               -1, // unlimited
@@ -60,6 +65,7 @@ var defaultLimits = map[Tier]Limits{
 		MaxWorkspaces:          -1,
 		MaxAPIRequestsPerMin:   1000,
 		AnalyticsRetentionDays: -1, // unlimited
+		MaxBioLinks:            -1,
 	},
 }
 
@@ -88,6 +94,8 @@ func (l Limits) GetLimit(lt LimitType) int64 {
 		return l.MaxAPIRequestsPerMin
 	case LimitAnalyticsRetentionDays:
 		return l.AnalyticsRetentionDays
+	case LimitMaxBioLinks:
+		return l.MaxBioLinks
 	default:
 		return 0
 	}
@@ -102,3 +110,13 @@ func (l Limits) CheckLimit(lt LimitType, current int64) bool {
 	}
 	return current < limit
 }
+
+// LimitWarning reports usage above a limit of the current license, usually
+// after a downgrade. Existing resources keep working, but no more can be
+// added until usage is back under the limit.
+type LimitWarning struct {
+	Limit       LimitType `json:"limit"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	Current     int64     `json:"current"`
+	Max         int64     `json:"max"`
+}
@@ -2,6 +2,8 @@ package license
 
 import (
 	"context"
+	"math/rand/v2"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,6 +17,8 @@ type Manager struct {
 	license     *License
 	licenseKey  string
 	isCommunity bool
+	warnings    []LimitWarning
+	onChange    []func()
 	logger      *zap.Logger
 }
 
@@ -36,8 +40,6 @@ func (m *Manager) LoadLicense(key string) error {
 	}
 
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	m.license = lic
 	m.licenseKey = key
 	m.isCommunity = false
@@ -46,15 +48,19 @@ func (m *Manager) LoadLicense(key string) error {
 	if m.license.Limits == (Limits{}) {
 		m.license.Limits = DefaultLimits(m.license.Tier)
 	}
+	// Licenses issued before the bio link limit existed don't carry it.
+	if m.license.Limits.MaxBioLinks == 0 {
+		m.license.Limits.MaxBioLinks = DefaultLimits(m.license.Tier).MaxBioLinks
+	}
+	m.mu.Unlock()
 
+	m.notifyChange()
 	return nil
 }
 
 // SetCommunityEdition resets to the free community edition.
 func (m *Manager) SetCommunityEdition() {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	m.license = &License{
 		Type:     LicenseTypeSubscription,
 		Tier:     TierFree,
@@ -63,6 +69,42 @@ func (m *Manager) SetCommunityEdition() {
 	}
 	m.licenseKey = ""
 	m.isCommunity = true
+	m.mu.Unlock()
+
+	m.notifyChange()
+}
+
+// OnChange registers fn to run after the active license changes. Callbacks
+// run synchronously, so a license response built right after a change
+// reflects anything they set.
+func (m *Manager) OnChange(fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = append(m.onChange, fn)
+}
+
+func (m *Manager) notifyChange() {
+	m.mu.RLock()
+	callbacks := append([]func(){}, m.onChange...)
+	m.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn()
+	}
+}
+
+// SetLimitWarnings replaces the current over-limit warnings.
+func (m *Manager) SetLimitWarnings(warnings []LimitWarning) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.warnings = append([]LimitWarning(nil), warnings...)
+}
+
+// LimitWarnings returns the current over-limit warnings.
+func (m *Manager) LimitWarnings() []LimitWarning {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]LimitWarning(nil), m.warnings...)
 }
 
 // RemoveLicense removes the current license and reverts to CE.
@@ -108,6 +150,38 @@ func (m *Manager) HasFeature(f Feature) bool {
 	return m.license.HasFeature(f)
 }
 
+// FeatureStatus is a registered feature and whether the current license
+// grants it.
+type FeatureStatus struct {
+	Feature Feature `json:"feature"`
+	FeatureDefinition
+	Available bool `json:"available"`
+}
+
+// FeatureMatrix returns every registered feature with its availability,
+// sorted by category and then feature.
+func (m *Manager) FeatureMatrix() []FeatureStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	all := AllFeatures()
+	matrix := make([]FeatureStatus, 0, len(all))
+	for f, def := range all {
+		matrix = append(matrix, FeatureStatus{
+			Feature:           f,
+			FeatureDefinition: def,
+			Available:         m.license.HasFeature(f),
+		})
+	}
+	sort.Slice(matrix, func(i, j int) bool {
+		if matrix[i].Category != matrix[j].Category {
+			return matrix[i].Category < matrix[j].Category
+		}
+		return matrix[i].Feature < matrix[j].Feature
+	})
+	return matrix
+}
+
 // CheckLimit returns true if the current usage is within the license limit.
 func (m *Manager) CheckLimit(lt LimitType, current int64) bool {
 	m.mu.RLock()
@@ -115,41 +189,69 @@ func (m *Manager) CheckLimit(lt LimitType, current int64) bool {
 	return m.license.Limits.CheckLimit(lt, current)
 }
 
-// StartPeriodicCheck re-verifies the license at the given interval.
-// If the license becomes invalid or expired, it falls back to CE.
-func (m *Manager) StartPeriodicCheck(ctx context.Context, interval time.Duration) {
+// StartPeriodicCheck re-verifies the license right away and then every
+// interval plus a random delay of up to jitter, so that instances started
+// together don't all check at once. If the license becomes invalid or
+// expired, it falls back to CE.
+func (m *Manager) StartPeriodicCheck(ctx context.Context, interval, jitter time.Duration) {
 	go func() {
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
 		for {
+			m.RevalidateNow()
+
+			timer := time.NewTimer(checkDelay(interval, jitter))
 			select {
 			case <-ctx.Done():
+				timer.Stop()
 				return
-			case <-ticker.C:
-				m.mu.RLock()
-				key := m.licenseKey
-				community := m.isCommunity
-				m.mu.RUnlock()
-
-				if community || key == "" {
-					continue
-				}
-
-				if _, err := m.verifier.VerifyString(key); err != nil {
-					m.logger.Warn("license verification failed, reverting to community edition",
-						zap.Error(err),
-					)
-					m.SetCommunityEdition()
-				}
+			case <-timer.C:
 			}
 		}
 	}()
 }
 
+// checkDelay returns interval plus a random delay of up to jitter.
+func checkDelay(interval, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + rand.N(jitter)
+}
+
+// RevalidateNow verifies the loaded license key again, reverting to CE if
+// it is no longer valid, e.g. because it has expired, and returning why.
+// There is nothing to verify in CE.
+func (m *Manager) RevalidateNow() error {
+	m.mu.RLock()
+	key := m.licenseKey
+	community := m.isCommunity
+	m.mu.RUnlock()
+
+	if community || key == "" {
+		return nil
+	}
+
+	if _, err := m.verifier.VerifyString(key); err != nil {
+		m.logger.Warn("license verification failed, reverting to community edition",
+			zap.Error(err),
+		)
+		// A different license loaded meanwhile was verified when it was
+		// loaded.
+		m.mu.RLock()
+		replaced := m.licenseKey != key
+		m.mu.RUnlock()
+		if !replaced {
+			m.SetCommunityEdition()
+		}
+		return err
+	}
+	return nil
+}
+
 // GetLicenseResponse returns a safe API response for the current license.
 func (m *Manager) GetLicenseResponse() *LicenseResponse {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.license.ToResponse(m.isCommunity)
+	resp := m.license.ToResponse(m.isCommunity)
+	resp.Warnings = append([]LimitWarning(nil), m.warnings...)
+	return resp
 }
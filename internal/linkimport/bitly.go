@@ -0,0 +1,173 @@
+package linkimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	bitlyEndpoint = "https://api-ssl.bitly.com/v4"
+	bitlyPageSize = 100
+
+	// bitlyMaxResponseSize bounds how much of one API response is read.
+	bitlyMaxResponseSize = 10 << 20
+)
+
+// Bitly reads links through the Bitly v4 API. Links come from the token
+// owner's default group, with lifetime click totals from the click summary
+// endpoint.
+type Bitly struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewBitly creates a Bitly importer. The client's timeout bounds each API
+// call.
+func NewBitly(client *http.Client) *Bitly {
+	return &Bitly{client: client, endpoint: bitlyEndpoint}
+}
+
+type bitlyUser struct {
+	DefaultGroupGUID string `json:"default_group_guid"`
+}
+
+type bitlyLinksPage struct {
+	Links []struct {
+		ID             string   `json:"id"`
+		LongURL        string   `json:"long_url"`
+		Title          string   `json:"title"`
+		Tags           []string `json:"tags"`
+		CustomBitlinks []string `json:"custom_bitlinks"`
+		CreatedAt      string   `json:"created_at"`
+	} `json:"links"`
+	Pagination struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+type bitlyClickSummary struct {
+	TotalClicks int64 `json:"total_clicks"`
+}
+
+func (b *Bitly) Verify(ctx context.Context, token string) error {
+	_, err := b.defaultGroup(ctx, token)
+	return err
+}
+
+func (b *Bitly) Links(ctx context.Context, token string, fn func([]Link) error) error {
+	group, err := b.defaultGroup(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	next := fmt.Sprintf("%s/groups/%s/bitlinks?size=%d", b.endpoint, url.PathEscape(group), bitlyPageSize)
+	for next != "" {
+		var page bitlyLinksPage
+		if err := b.get(ctx, token, next, &page); err != nil {
+			return err
+		}
+
+		links := make([]Link, 0, len(page.Links))
+		for _, bl := range page.Links {
+			link := Link{
+				URL:       bl.LongURL,
+				ShortCode: bitlyBackHalf(bl.ID),
+				Title:     bl.Title,
+				Tags:      bl.Tags,
+			}
+			// A custom back-half is the code people have been sharing.
+			if len(bl.CustomBitlinks) > 0 {
+				if code := bitlyBackHalf(bl.CustomBitlinks[0]); code != "" {
+					link.ShortCode = code
+				}
+			}
+			if t, err := time.Parse("2006-01-02T15:04:05-0700", bl.CreatedAt); err == nil {
+				link.CreatedAt = t
+			}
+
+			var summary bitlyClickSummary
+			if err := b.get(ctx, token, b.endpoint+"/bitlinks/"+bl.ID+"/clicks/summary?unit=day&units=-1", &summary); err == nil {
+				link.Clicks = &summary.TotalClicks
+			} else if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			links = append(links, link)
+		}
+
+		if len(links) > 0 {
+			if err := fn(links); err != nil {
+				return err
+			}
+		}
+		next = page.Pagination.Next
+		if next != "" && !b.sameHost(next) {
+			// The token is only ever sent to the API host.
+			return fmt.Errorf("bitly: unexpected pagination URL %q", next)
+		}
+	}
+	return nil
+}
+
+func (b *Bitly) sameHost(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	base, err := url.Parse(b.endpoint)
+	return err == nil && u.Scheme == base.Scheme && u.Host == base.Host
+}
+
+func (b *Bitly) defaultGroup(ctx context.Context, token string) (string, error) {
+	var user bitlyUser
+	if err := b.get(ctx, token, b.endpoint+"/user", &user); err != nil {
+		return "", err
+	}
+	if user.DefaultGroupGUID == "" {
+		return "", fmt.Errorf("bitly: account has no default group")
+	}
+	return user.DefaultGroupGUID, nil
+}
+
+func (b *Bitly) get(ctx context.Context, token, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("bitly: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bitly: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("bitly: unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, bitlyMaxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("bitly: decoding response: %w", err)
+	}
+	return nil
+}
+
+// bitlyBackHalf returns the path of a bitlink such as "bit.ly/3xYz" or
+// "https://bit.ly/my-link".
+func bitlyBackHalf(bitlink string) string {
+	bitlink = strings.TrimPrefix(strings.TrimPrefix(bitlink, "https://"), "http://")
+	_, path, ok := strings.Cut(bitlink, "/")
+	if !ok {
+		return ""
+	}
+	return strings.Trim(path, "/")
+}
@@ -0,0 +1,107 @@
+package linkimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newBitlyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.URL.Path == "/user":
+			fmt.Fprint(w, `{"default_group_guid": "Bg1"}`)
+		case r.URL.Path == "/groups/Bg1/bitlinks" && r.URL.Query().Get("page") == "":
+			fmt.Fprintf(w, `{"links": [
+				{"id": "bit.ly/3abc", "long_url": "https://example.com/a", "title": "A", "tags": ["promo"], "created_at": "2024-05-01T10:00:00+0000"},
+				{"id": "bit.ly/3def", "long_url": "https://example.com/b", "custom_bitlinks": ["https://bit.ly/spring-sale"]}
+			], "pagination": {"next": "%s/groups/Bg1/bitlinks?page=2&size=100"}}`, srv.URL)
+		case r.URL.Path == "/groups/Bg1/bitlinks":
+			fmt.Fprint(w, `{"links": [{"id": "bit.ly/3ghi", "long_url": "https://example.com/c"}], "pagination": {"next": ""}}`)
+		case strings.HasSuffix(r.URL.Path, "/clicks/summary"):
+			if strings.Contains(r.URL.Path, "3ghi") {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `{"total_clicks": 42}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBitly_Links(t *testing.T) {
+	srv := newBitlyServer(t)
+	b := NewBitly(srv.Client())
+	b.endpoint = srv.URL
+
+	var links []Link
+	pages := 0
+	err := b.Links(context.Background(), "good", func(page []Link) error {
+		pages++
+		links = append(links, page...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Links() error = %v", err)
+	}
+	if pages != 2 || len(links) != 3 {
+		t.Fatalf("got %d links in %d pages, want 3 in 2", len(links), pages)
+	}
+
+	first := links[0]
+	if first.URL != "https://example.com/a" || first.ShortCode != "3abc" || first.Title != "A" || len(first.Tags) != 1 {
+		t.Errorf("first link = %+v", first)
+	}
+	if first.Clicks == nil || *first.Clicks != 42 {
+		t.Errorf("first link clicks = %v, want 42", first.Clicks)
+	}
+	if first.CreatedAt.IsZero() {
+		t.Error("first link created_at was not parsed")
+	}
+	if links[1].ShortCode != "spring-sale" {
+		t.Errorf("custom back-half = %q, want spring-sale", links[1].ShortCode)
+	}
+	if links[2].Clicks != nil {
+		t.Errorf("clicks = %v, want nil when the summary fails", *links[2].Clicks)
+	}
+}
+
+func TestBitly_RejectedToken(t *testing.T) {
+	srv := newBitlyServer(t)
+	b := NewBitly(srv.Client())
+	b.endpoint = srv.URL
+
+	if err := b.Verify(context.Background(), "bad"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Verify() error = %v, want ErrUnauthorized", err)
+	}
+	if err := b.Verify(context.Background(), "good"); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestBitlyBackHalf(t *testing.T) {
+	tests := map[string]string{
+		"bit.ly/3abc":            "3abc",
+		"https://bit.ly/my-link": "my-link",
+		"example.co/x/":          "x",
+		"bit.ly":                 "",
+	}
+	for in, want := range tests {
+		if got := bitlyBackHalf(in); got != want {
+			t.Errorf("bitlyBackHalf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
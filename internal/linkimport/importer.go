@@ -0,0 +1,34 @@
+// Package linkimport pulls links from other link shorteners' APIs so
+// workspaces can migrate to Linkrift.
+package linkimport
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnauthorized is returned when the source rejects the API token.
+var ErrUnauthorized = errors.New("linkimport: API token was rejected")
+
+// Link is a link read from another shortener, mapped to the fields Linkrift
+// imports.
+type Link struct {
+	URL       string
+	ShortCode string
+	Title     string
+	Tags      []string
+	// Clicks is the link's lifetime click count, nil when the source could
+	// not report it.
+	Clicks    *int64
+	CreatedAt time.Time
+}
+
+// Importer reads the links of an account on another shortener.
+type Importer interface {
+	// Verify checks that token is accepted by the source.
+	Verify(ctx context.Context, token string) error
+	// Links calls fn with each page of the account's links, stopping at the
+	// first error fn returns.
+	Links(ctx context.Context, token string, fn func([]Link) error) error
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// RequireInstanceAdmin allows only users whose verified email is in emails.
+// Emails are compared case-insensitively. It must run after RequireAuth.
+func RequireInstanceAdmin(emails []string) gin.HandlerFunc {
+	admins := make(map[string]bool, len(emails))
+	for _, e := range emails {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			admins[e] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		user := GetUserFromContext(c)
+		if user == nil || user.EmailVerifiedAt == nil || !admins[strings.ToLower(user.Email)] {
+			c.AbortWithStatusJSON(http.StatusForbidden, httputil.Response{
+				Success: false,
+				Error: &httputil.ErrorBody{
+					Code:    "FORBIDDEN",
+					Message: "instance admin access required",
+				},
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -131,6 +131,18 @@ func APIKeyAuth(
 	}
 }
 
+// RequireAuthOrAPIKey authenticates with apiKeyAuth when the request has an
+// X-API-Key header and with sessionAuth otherwise, for routes open to both.
+func RequireAuthOrAPIKey(sessionAuth, apiKeyAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "" {
+			apiKeyAuth(c)
+			return
+		}
+		sessionAuth(c)
+	}
+}
+
 // RequireAPIKeyScope checks that the API key in context has the required scope.
 func RequireAPIKeyScope(scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve runs handlers for one request and returns the response code.
+func serve(req *http.Request, handlers ...gin.HandlerFunc) int {
+	router := gin.New()
+	router.GET("/workspaces/:workspaceId/links", handlers...)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func withAPIKey(key *models.APIKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(contextKeyUser, &models.User{ID: key.UserID})
+		c.Set(contextKeyAPIKey, key)
+		c.Next()
+	}
+}
+
+func ok(c *gin.Context) { c.Status(http.StatusOK) }
+
+func TestRequireAuthOrAPIKey(t *testing.T) {
+	var used string
+	session := func(c *gin.Context) { used = "session"; c.Next() }
+	apiKey := func(c *gin.Context) { used = "api_key"; c.Next() }
+
+	req := httptest.NewRequest(http.MethodGet, "/workspaces/x/links", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	if code := serve(req, RequireAuthOrAPIKey(session, apiKey), ok); code != http.StatusOK || used != "session" {
+		t.Errorf("bearer request: code %d, used %q; want 200 via session", code, used)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/workspaces/x/links", nil)
+	req.Header.Set("X-API-Key", "lr_test")
+	if code := serve(req, RequireAuthOrAPIKey(session, apiKey), ok); code != http.StatusOK || used != "api_key" {
+		t.Errorf("API key request: code %d, used %q; want 200 via API key", code, used)
+	}
+}
+
+func TestRequireAPIKeyScope(t *testing.T) {
+	key := &models.APIKey{UserID: uuid.New(), Scopes: []string{models.ScopeLinksRead}}
+
+	req := httptest.NewRequest(http.MethodGet, "/workspaces/x/links", nil)
+	if code := serve(req, withAPIKey(key), RequireAPIKeyScope(models.ScopeLinksRead), ok); code != http.StatusOK {
+		t.Errorf("granted scope: got %d, want 200", code)
+	}
+	if code := serve(req, withAPIKey(key), RequireAPIKeyScope(models.ScopeQRWrite), ok); code != http.StatusForbidden {
+		t.Errorf("missing scope: got %d, want 403", code)
+	}
+	if code := serve(req, RequireAPIKeyScope(models.ScopeQRWrite), ok); code != http.StatusOK {
+		t.Errorf("session request: got %d, want scope check skipped", code)
+	}
+}
+
+func TestRequireWorkspaceAccess_APIKeyOtherWorkspace(t *testing.T) {
+	key := &models.APIKey{UserID: uuid.New(), WorkspaceID: uuid.New()}
+
+	// The key is rejected before any repository lookup.
+	req := httptest.NewRequest(http.MethodGet, "/workspaces/"+uuid.NewString()+"/links", nil)
+	if code := serve(req, withAPIKey(key), RequireWorkspaceAccess(nil, nil), ok); code != http.StatusForbidden {
+		t.Errorf("got %d, want 403 for a key used outside its workspace", code)
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -17,7 +18,14 @@ const (
 	contextKeySessionID = "session_id"
 )
 
-func RequireAuth(tokenMaker paseto.Maker, userRepo repository.UserRepository) gin.HandlerFunc {
+// SessionChecker reports whether the session an access token was issued for
+// is still active. It lets logout and password resets take effect before the
+// token itself expires.
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
+
+func RequireAuth(tokenMaker paseto.Maker, userRepo repository.UserRepository, sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := extractBearerToken(c)
 		if token == "" {
@@ -43,6 +51,28 @@ func RequireAuth(tokenMaker paseto.Maker, userRepo repository.UserRepository) gi
 			return
 		}
 
+		active, err := sessions.IsSessionActive(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, httputil.Response{
+				Success: false,
+				Error: &httputil.ErrorBody{
+					Code:    "INTERNAL_ERROR",
+					Message: "failed to validate session",
+				},
+			})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, httputil.Response{
+				Success: false,
+				Error: &httputil.ErrorBody{
+					Code:    "UNAUTHORIZED",
+					Message: "session has been revoked",
+				},
+			})
+			return
+		}
+
 		user, err := userRepo.GetByID(c.Request.Context(), claims.UserID)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, httputil.Response{
@@ -61,7 +91,7 @@ func RequireAuth(tokenMaker paseto.Maker, userRepo repository.UserRepository) gi
 	}
 }
 
-func OptionalAuth(tokenMaker paseto.Maker, userRepo repository.UserRepository) gin.HandlerFunc {
+func OptionalAuth(tokenMaker paseto.Maker, userRepo repository.UserRepository, sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := extractBearerToken(c)
 		if token == "" {
@@ -75,6 +105,11 @@ func OptionalAuth(tokenMaker paseto.Maker, userRepo repository.UserRepository) g
 			return
 		}
 
+		if active, err := sessions.IsSessionActive(c.Request.Context(), claims.SessionID); err != nil || !active {
+			c.Next()
+			return
+		}
+
 		user, err := userRepo.GetByID(c.Request.Context(), claims.UserID)
 		if err != nil {
 			c.Next()
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeadWithoutBody drops the response body of HEAD requests, so a handler
+// registered for both GET and HEAD can write pages and redirects as usual.
+// HEAD responses keep the status and headers, including Location.
+func HeadWithoutBody() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead {
+			c.Writer = &headWriter{ResponseWriter: c.Writer}
+		}
+		c.Next()
+	}
+}
+
+// headWriter sends the status and headers but discards the body.
+type headWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *headWriter) Write(b []byte) (int, error) {
+	w.WriteHeaderNow()
+	return len(b), nil
+}
+
+func (w *headWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	return len(s), nil
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func headRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(HeadWithoutBody())
+	redirect := func(c *gin.Context) {
+		c.Redirect(http.StatusFound, "https://example.com/landing")
+	}
+	router.GET("/abc123", redirect)
+	router.HEAD("/abc123", redirect)
+	page := func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusGone)
+		c.Writer.WriteString("<h1>Link Expired</h1>")
+	}
+	router.GET("/expired", page)
+	router.HEAD("/expired", page)
+	return router
+}
+
+func TestHeadWithoutBody_Redirect(t *testing.T) {
+	w := httptest.NewRecorder()
+	headRouter().ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/abc123", nil))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("got %d, want 302", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "https://example.com/landing" {
+		t.Errorf("Location = %q", loc)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("HEAD response has a body: %q", w.Body.String())
+	}
+
+	// GET still gets the body http.Redirect writes.
+	w = httptest.NewRecorder()
+	headRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/abc123", nil))
+	if w.Code != http.StatusFound || w.Body.Len() == 0 {
+		t.Errorf("GET got %d with %d body bytes", w.Code, w.Body.Len())
+	}
+}
+
+func TestHeadWithoutBody_Page(t *testing.T) {
+	w := httptest.NewRecorder()
+	headRouter().ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/expired", nil))
+
+	if w.Code != http.StatusGone {
+		t.Fatalf("got %d, want 410", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("HEAD response has a body: %q", w.Body.String())
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func httpsRouter(hstsMaxAge time.Duration) *gin.Engine {
+	router := gin.New()
+	router.Use(RequireHTTPS(hstsMaxAge, "/health"))
+	router.GET("/health", ok)
+	router.GET("/abc123", ok)
+	return router
+}
+
+func TestRequireHTTPS_RedirectsPlainHTTP(t *testing.T) {
+	w := httptest.NewRecorder()
+	httpsRouter(time.Hour).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://lrift.co/abc123?utm_source=x", nil))
+
+	if w.Code != http.StatusPermanentRedirect {
+		t.Fatalf("got %d, want 308", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "https://lrift.co/abc123?utm_source=x" {
+		t.Errorf("Location = %q", loc)
+	}
+	if w.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("HSTS must only be sent over HTTPS")
+	}
+}
+
+func TestRequireHTTPS_HSTS(t *testing.T) {
+	tests := []struct {
+		name string
+		req  func() *http.Request
+	}{
+		{"tls", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "https://lrift.co/abc123", nil)
+			r.TLS = &tls.ConnectionState{}
+			return r
+		}},
+		{"forwarded", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "http://lrift.co/abc123", nil)
+			r.Header.Set("X-Forwarded-Proto", "https")
+			return r
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			httpsRouter(365*24*time.Hour).ServeHTTP(w, tt.req())
+			if w.Code != http.StatusOK {
+				t.Fatalf("got %d, want 200", w.Code)
+			}
+			if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000" {
+				t.Errorf("Strict-Transport-Security = %q", got)
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "http://lrift.co/abc123", nil)
+	r.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+	httpsRouter(0).ServeHTTP(w, r)
+	if w.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("a zero max-age must not send HSTS")
+	}
+}
+
+func TestRequireHTTPS_SkipPaths(t *testing.T) {
+	w := httptest.NewRecorder()
+	httpsRouter(time.Hour).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://10.0.0.5/health", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("health check got %d, want 200 over plain HTTP", w.Code)
+	}
+}
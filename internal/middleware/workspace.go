@@ -44,6 +44,18 @@ func RequireWorkspaceAccess(wsRepo repository.WorkspaceRepository, memberRepo re
 			return
 		}
 
+		// An API key only grants access to the workspace it was created in
+		if apiKey := GetAPIKeyFromContext(c); apiKey != nil && apiKey.WorkspaceID != wsID {
+			c.AbortWithStatusJSON(http.StatusForbidden, httputil.Response{
+				Success: false,
+				Error: &httputil.ErrorBody{
+					Code:    "FORBIDDEN",
+					Message: "API key is not valid for this workspace",
+				},
+			})
+			return
+		}
+
 		ws, err := wsRepo.GetByID(c.Request.Context(), wsID)
 		if err != nil {
 			status := httputil.MapToHTTPStatus(err)
@@ -1,33 +1,79 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// DateRange represents a time window for analytics queries.
+// DateRange represents a time window for analytics queries. Location is the
+// time zone time-series buckets are cut in; nil means UTC.
 type DateRange struct {
-	Start time.Time
-	End   time.Time
+	Start    time.Time
+	End      time.Time
+	Location *time.Location
 }
 
-// DateRangeFromPreset creates a DateRange from a named preset.
-// Supported presets: "24h", "7d", "30d", "90d".
-func DateRangeFromPreset(preset string) DateRange {
-	now := time.Now().UTC()
+// TimeZone returns the name of the range's time zone for use in queries.
+func (dr DateRange) TimeZone() string {
+	if dr.Location == nil {
+		return "UTC"
+	}
+	return dr.Location.String()
+}
+
+// DateRangePreset is a named analytics date range.
+type DateRangePreset struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// DateRangePresets lists the presets DateRangeFromPreset accepts.
+var DateRangePresets = []DateRangePreset{
+	{Name: "today", Description: "Since midnight today"},
+	{Name: "yesterday", Description: "The whole of yesterday"},
+	{Name: "24h", Description: "The last 24 hours"},
+	{Name: "7d", Description: "The last 7 days"},
+	{Name: "30d", Description: "The last 30 days"},
+	{Name: "90d", Description: "The last 90 days"},
+	{Name: "mtd", Description: "Month to date"},
+	{Name: "ytd", Description: "Year to date"},
+	{Name: "all", Description: "All time, subject to the plan's retention"},
+}
+
+// ErrUnknownDateRangePreset is returned for a preset not in DateRangePresets.
+var ErrUnknownDateRangePreset = errors.New("unknown date range preset")
+
+// DateRangeFromPreset creates a DateRange ending now from a named preset.
+// Calendar presets (today, yesterday, mtd, ytd) start at midnight in loc.
+func DateRangeFromPreset(preset string, loc *time.Location) (DateRange, error) {
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var start time.Time
 	switch preset {
+	case "today":
+		start = midnight
+	case "yesterday":
+		return DateRange{Start: midnight.AddDate(0, 0, -1).UTC(), End: midnight.UTC(), Location: loc}, nil
 	case "24h":
-		return DateRange{Start: now.Add(-24 * time.Hour), End: now}
+		start = now.Add(-24 * time.Hour)
 	case "7d":
-		return DateRange{Start: now.Add(-7 * 24 * time.Hour), End: now}
+		start = now.Add(-7 * 24 * time.Hour)
 	case "30d":
-		return DateRange{Start: now.Add(-30 * 24 * time.Hour), End: now}
+		start = now.Add(-30 * 24 * time.Hour)
 	case "90d":
-		return DateRange{Start: now.Add(-90 * 24 * time.Hour), End: now}
+		start = now.Add(-90 * 24 * time.Hour)
+	case "mtd":
+		start = midnight.AddDate(0, 0, 1-now.Day())
+	case "ytd":
+		start = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	case "all":
+		start = time.Unix(0, 0)
 	default:
-		return DateRange{Start: now.Add(-7 * 24 * time.Hour), End: now}
+		return DateRange{}, ErrUnknownDateRangePreset
 	}
+	return DateRange{Start: start.UTC(), End: now.UTC(), Location: loc}, nil
 }
 
 // ClampToRetention clamps the date range start so it doesn't exceed the retention limit.
@@ -60,6 +106,8 @@ type LinkAnalytics struct {
 	Clicks24h    int64 `json:"clicks_24h"`
 	Clicks7d     int64 `json:"clicks_7d"`
 	Clicks30d    int64 `json:"clicks_30d"`
+	// QRScans is how many of TotalClicks came through a dynamic QR code.
+	QRScans int64 `json:"qr_scans"`
 }
 
 // WorkspaceAnalytics holds aggregated stats for a workspace.
@@ -80,6 +128,13 @@ type TopLink struct {
 	TotalClicks int64    `json:"total_clicks"`
 }
 
+// LinkClickSummary holds per-link click aggregates within a date range.
+type LinkClickSummary struct {
+	Clicks        int64      `json:"clicks"`
+	UniqueClicks  int64      `json:"unique_clicks"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty"`
+}
+
 // TimeSeriesPoint is a single data point in a time-series chart.
 type TimeSeriesPoint struct {
 	Timestamp time.Time `json:"timestamp"`
@@ -110,6 +165,29 @@ type DeviceBreakdown struct {
 	Other   int64   `json:"other"`
 }
 
+// WorkspaceAnalyticsFilter narrows workspace-level breakdowns. An empty
+// LinkIDs means every link in the workspace.
+type WorkspaceAnalyticsFilter struct {
+	LinkIDs []uuid.UUID
+}
+
+// ClickHeatmap holds click counts by hour of week in UTC. Days are indexed
+// Monday (0) through Sunday (6); hours 0-23.
+type ClickHeatmap struct {
+	Days  [7][24]int64 `json:"days"`
+	Total int64        `json:"total"`
+}
+
+// Add records clicks for an ISO day of week (1 = Monday, 7 = Sunday) and
+// hour. Out-of-range values are ignored.
+func (h *ClickHeatmap) Add(isoDay, hour int, clicks int64) {
+	if isoDay < 1 || isoDay > 7 || hour < 0 || hour > 23 {
+		return
+	}
+	h.Days[isoDay-1][hour] += clicks
+	h.Total += clicks
+}
+
 // BrowserStats holds click counts grouped by browser.
 type BrowserStats struct {
 	Browser string  `json:"browser"`
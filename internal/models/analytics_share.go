@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// MaxAnalyticsShareTTL caps how long a public analytics share link stays valid.
+const MaxAnalyticsShareTTL = 90 * 24 * time.Hour
+
+// AnalyticsShare scopes a public share token to one link and date range.
+type AnalyticsShare struct {
+	ID          uuid.UUID  `json:"id"`
+	LinkID      uuid.UUID  `json:"link_id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	RangeStart  time.Time  `json:"range_start"`
+	RangeEnd    time.Time  `json:"range_end"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func AnalyticsShareFromSqlc(s sqlc.AnalyticsShareToken) *AnalyticsShare {
+	share := &AnalyticsShare{
+		ID:          s.ID,
+		LinkID:      s.LinkID,
+		WorkspaceID: s.WorkspaceID,
+		RangeStart:  s.RangeStart.Time,
+		RangeEnd:    s.RangeEnd.Time,
+		ExpiresAt:   s.ExpiresAt.Time,
+	}
+	if s.RevokedAt.Valid {
+		t := s.RevokedAt.Time
+		share.RevokedAt = &t
+	}
+	if s.CreatedAt.Valid {
+		share.CreatedAt = s.CreatedAt.Time
+	}
+	return share
+}
+
+// DateRange returns the range of analytics the share exposes.
+func (s *AnalyticsShare) DateRange() DateRange {
+	return DateRange{Start: s.RangeStart, End: s.RangeEnd}
+}
+
+// IsUsable reports whether the share is neither revoked nor expired.
+func (s *AnalyticsShare) IsUsable(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// CreateAnalyticsShareInput is the request body for creating a share link.
+// The date range comes from Range, or Start/End (RFC3339), defaulting to the
+// last 30 days.
+type CreateAnalyticsShareInput struct {
+	TTLHours int    `json:"ttl_hours" binding:"required,min=1"`
+	Range    string `json:"range,omitempty"`
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+}
+
+// CreateAnalyticsShareResponse carries the signed token, which is only
+// returned once.
+type CreateAnalyticsShareResponse struct {
+	Share *AnalyticsShare `json:"share"`
+	Token string          `json:"token"`
+}
+
+// SharedLinkAnalytics is the read-only view served to share-token holders.
+type SharedLinkAnalytics struct {
+	ShortCode  string            `json:"short_code"`
+	Title      *string           `json:"title,omitempty"`
+	RangeStart time.Time         `json:"range_start"`
+	RangeEnd   time.Time         `json:"range_end"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	Stats      *LinkAnalytics    `json:"stats"`
+	TimeSeries []TimeSeriesPoint `json:"time_series"`
+}
@@ -7,19 +7,34 @@ import (
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
 )
 
+// API key scopes.
+const (
+	ScopeLinksRead     = "links:read"
+	ScopeLinksWrite    = "links:write"
+	ScopeDomainsRead   = "domains:read"
+	ScopeDomainsWrite  = "domains:write"
+	ScopeAnalyticsRead = "analytics:read"
+	ScopeBioPagesRead  = "bio_pages:read"
+	ScopeBioPagesWrite = "bio_pages:write"
+	ScopeQRRead        = "qr:read"
+	ScopeQRWrite       = "qr:write"
+	ScopeWebhooksRead  = "webhooks:read"
+	ScopeWebhooksWrite = "webhooks:write"
+)
+
 // ValidAPIKeyScopes defines all valid API key scopes.
 var ValidAPIKeyScopes = []string{
-	"links:read",
-	"links:write",
-	"domains:read",
-	"domains:write",
-	"analytics:read",
-	"bio_pages:read",
-	"bio_pages:write",
-	"qr:read",
-	"qr:write",
-	"webhooks:read",
-	"webhooks:write",
+	ScopeLinksRead,
+	ScopeLinksWrite,
+	ScopeDomainsRead,
+	ScopeDomainsWrite,
+	ScopeAnalyticsRead,
+	ScopeBioPagesRead,
+	ScopeBioPagesWrite,
+	ScopeQRRead,
+	ScopeQRWrite,
+	ScopeWebhooksRead,
+	ScopeWebhooksWrite,
 }
 
 type APIKey struct {
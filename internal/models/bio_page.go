@@ -2,6 +2,8 @@ package models
 
 import (
 	"crypto/sha256"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,6 +42,8 @@ type BioPageLink struct {
 	VisibleFrom  *time.Time `json:"visible_from,omitempty"`
 	VisibleUntil *time.Time `json:"visible_until,omitempty"`
 	ClickCount   int64      `json:"click_count"`
+	LinkType     string     `json:"link_type"`
+	Platform     string     `json:"platform,omitempty"` // social links only
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
 }
@@ -71,7 +75,8 @@ type UpdateBioPageInput struct {
 
 type CreateBioPageLinkInput struct {
 	Title        string  `json:"title" binding:"required"`
-	URL          string  `json:"url" binding:"required"`
+	URL          string  `json:"url"` // required unless link_type is email_capture
+	LinkType     *string `json:"link_type,omitempty"`
 	Icon         *string `json:"icon,omitempty"`
 	IsVisible    *bool   `json:"is_visible,omitempty"`
 	VisibleFrom  *string `json:"visible_from,omitempty"`
@@ -81,6 +86,7 @@ type CreateBioPageLinkInput struct {
 type UpdateBioPageLinkInput struct {
 	Title        *string `json:"title,omitempty"`
 	URL          *string `json:"url,omitempty"`
+	LinkType     *string `json:"link_type,omitempty"`
 	Icon         *string `json:"icon,omitempty"`
 	IsVisible    *bool   `json:"is_visible,omitempty"`
 	VisibleFrom  *string `json:"visible_from,omitempty"`
@@ -93,12 +99,16 @@ type ReorderBioLinksInput struct {
 
 // Theme types
 
+// BioPageTheme is either a built-in theme, keyed by its string ID, or a
+// workspace's custom theme, keyed by its UUID.
 type BioPageTheme struct {
 	ID          string      `json:"id"`
 	Name        string      `json:"name"`
 	Description string      `json:"description"`
 	IsPremium   bool        `json:"is_premium"`
+	IsCustom    bool        `json:"is_custom"`
 	Styles      ThemeStyles `json:"styles"`
+	WorkspaceID uuid.UUID   `json:"-"` // custom themes only
 }
 
 type ThemeStyles struct {
@@ -117,6 +127,18 @@ type GradientConfig struct {
 	Direction string `json:"direction"`
 }
 
+type CreateBioThemeInput struct {
+	Name        string      `json:"name" binding:"required,max=100"`
+	Description *string     `json:"description,omitempty"`
+	Styles      ThemeStyles `json:"styles" binding:"required"`
+}
+
+type UpdateBioThemeInput struct {
+	Name        *string      `json:"name,omitempty" binding:"omitempty,max=100"`
+	Description *string      `json:"description,omitempty"`
+	Styles      *ThemeStyles `json:"styles,omitempty"`
+}
+
 // ThemeIDToUUID generates a deterministic UUID from a theme string ID.
 func ThemeIDToUUID(themeID string) uuid.UUID {
 	hash := sha256.Sum256([]byte("linkrift-theme:" + themeID))
@@ -126,7 +148,8 @@ func ThemeIDToUUID(themeID string) uuid.UUID {
 	return u
 }
 
-// ThemeUUIDToID looks up a theme string ID from a UUID.
+// ThemeUUIDToID looks up a built-in theme string ID from a UUID. It returns
+// "" for anything else, including custom theme UUIDs.
 func ThemeUUIDToID(themeUUID uuid.UUID) string {
 	for id := range PredefinedThemes {
 		if ThemeIDToUUID(id) == themeUUID {
@@ -230,6 +253,22 @@ func BioPageFromSqlc(b sqlc.BioPage) *BioPage {
 	return page
 }
 
+// BioThemeFromSqlc converts a custom theme row. Its ID is the row's UUID,
+// which is also what bio pages store in theme_id.
+func BioThemeFromSqlc(t sqlc.BioTheme) *BioPageTheme {
+	theme := &BioPageTheme{
+		ID:          t.ID.String(),
+		Name:        t.Name,
+		IsCustom:    true,
+		WorkspaceID: t.WorkspaceID,
+	}
+	if t.Description.Valid {
+		theme.Description = t.Description.String
+	}
+	_ = json.Unmarshal(t.Styles, &theme.Styles)
+	return theme
+}
+
 func BioPageLinkFromSqlc(l sqlc.BioPageLink) *BioPageLink {
 	link := &BioPageLink{
 		ID:         l.ID,
@@ -239,6 +278,11 @@ func BioPageLinkFromSqlc(l sqlc.BioPageLink) *BioPageLink {
 		Position:   l.Position,
 		IsVisible:  l.IsVisible,
 		ClickCount: l.ClickCount,
+		LinkType:   l.LinkType,
+	}
+
+	if l.LinkType == BioLinkTypeSocial {
+		link.Platform = DetectSocialPlatform(l.Url)
 	}
 
 	if l.Icon.Valid {
@@ -277,9 +321,77 @@ type PublicBioPageResponse struct {
 }
 
 type PublicBioLink struct {
-	ID    uuid.UUID `json:"id"`
-	Title string    `json:"title"`
-	URL   string    `json:"url"`
-	Icon  *string   `json:"icon,omitempty"`
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	URL      string    `json:"url"`
+	Icon     *string   `json:"icon,omitempty"`
+	LinkType string    `json:"link_type"`
+	Platform string    `json:"platform,omitempty"`
+}
+
+// BioPageSubmission is an email collected by an email capture block.
+type BioPageSubmission struct {
+	ID        uuid.UUID  `json:"id"`
+	BioPageID uuid.UUID  `json:"bio_page_id"`
+	BlockID   *uuid.UUID `json:"block_id,omitempty"`
+	Email     string     `json:"email"`
+	Name      *string    `json:"name,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// BioEmailSubmissionInput is posted by visitors to an email capture block.
+// Website is a honeypot field that is hidden from people, so only bots fill
+// it in.
+type BioEmailSubmissionInput struct {
+	BlockID string  `json:"block_id" binding:"required"`
+	Email   string  `json:"email" binding:"required"`
+	Name    *string `json:"name,omitempty"`
+	Website string  `json:"website,omitempty"`
+}
+
+// BioSubmissionCSVHeader is the header row matching CSVRecord.
+var BioSubmissionCSVHeader = []string{"email", "name", "submitted_at"}
+
+// CSVRecord returns the submission's fields in export column order. Visitor
+// input that a spreadsheet would evaluate as a formula is prefixed with a
+// quote.
+func (s *BioPageSubmission) CSVRecord() []string {
+	var name string
+	if s.Name != nil {
+		name = *s.Name
+	}
+	return []string{
+		CSVText(s.Email),
+		CSVText(name),
+		s.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// CSVText prefixes values a spreadsheet would read as a formula with a quote,
+// so they are shown as text.
+func CSVText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func BioPageSubmissionFromSqlc(s sqlc.BioPageSubmission) *BioPageSubmission {
+	sub := &BioPageSubmission{
+		ID:        s.ID,
+		BioPageID: s.BioPageID,
+		Email:     s.Email,
+	}
+	if s.BlockID.Valid {
+		id := uuid.UUID(s.BlockID.Bytes)
+		sub.BlockID = &id
+	}
+	if s.Name.Valid {
+		sub.Name = &s.Name.String
+	}
+	if s.CreatedAt.Valid {
+		sub.CreatedAt = s.CreatedAt.Time
+	}
+	return sub
 }
 
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Bio page link types.
+const (
+	BioLinkTypeRegular      = "regular"
+	BioLinkTypeSocial       = "social"
+	BioLinkTypeEmailCapture = "email_capture"
+)
+
+// SocialPlatform is a platform a social bio link can point at. Its ID is
+// also the canonical icon ID the frontend renders.
+type SocialPlatform struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Hosts []string `json:"-"`
+}
+
+// SocialPlatforms lists the platforms social bio links are recognised for.
+// Hosts match exactly or as a parent domain, so "m.youtube.com" is YouTube.
+var SocialPlatforms = []SocialPlatform{
+	{ID: "instagram", Name: "Instagram", Hosts: []string{"instagram.com", "instagr.am"}},
+	{ID: "tiktok", Name: "TikTok", Hosts: []string{"tiktok.com"}},
+	{ID: "youtube", Name: "YouTube", Hosts: []string{"youtube.com", "youtu.be"}},
+	{ID: "x", Name: "X", Hosts: []string{"x.com", "twitter.com"}},
+	{ID: "facebook", Name: "Facebook", Hosts: []string{"facebook.com", "fb.com"}},
+	{ID: "linkedin", Name: "LinkedIn", Hosts: []string{"linkedin.com"}},
+	{ID: "github", Name: "GitHub", Hosts: []string{"github.com"}},
+	{ID: "twitch", Name: "Twitch", Hosts: []string{"twitch.tv"}},
+	{ID: "threads", Name: "Threads", Hosts: []string{"threads.net", "threads.com"}},
+	{ID: "snapchat", Name: "Snapchat", Hosts: []string{"snapchat.com"}},
+	{ID: "pinterest", Name: "Pinterest", Hosts: []string{"pinterest.com", "pin.it"}},
+	{ID: "spotify", Name: "Spotify", Hosts: []string{"spotify.com"}},
+	{ID: "discord", Name: "Discord", Hosts: []string{"discord.com", "discord.gg"}},
+	{ID: "reddit", Name: "Reddit", Hosts: []string{"reddit.com"}},
+	{ID: "bluesky", Name: "Bluesky", Hosts: []string{"bsky.app"}},
+	{ID: "whatsapp", Name: "WhatsApp", Hosts: []string{"whatsapp.com", "wa.me"}},
+	{ID: "telegram", Name: "Telegram", Hosts: []string{"telegram.org", "t.me"}},
+}
+
+// IsValidBioLinkType reports whether t is a known bio link type.
+func IsValidBioLinkType(t string) bool {
+	return t == BioLinkTypeRegular || t == BioLinkTypeSocial || t == BioLinkTypeEmailCapture
+}
+
+// DetectSocialPlatform returns the ID of the platform rawURL points at, or ""
+// if the URL doesn't belong to a known platform.
+func DetectSocialPlatform(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return ""
+	}
+
+	for _, p := range SocialPlatforms {
+		for _, h := range p.Hosts {
+			if host == h || strings.HasSuffix(host, "."+h) {
+				return p.ID
+			}
+		}
+	}
+	return ""
+}
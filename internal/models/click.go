@@ -27,8 +27,17 @@ type Click struct {
 	UTMSource      *string    `json:"utm_source,omitempty"`
 	UTMMedium      *string    `json:"utm_medium,omitempty"`
 	UTMCampaign    *string    `json:"utm_campaign,omitempty"`
+	Source         *string    `json:"source,omitempty"`
 }
 
+// ClickSourceParam is the query parameter that marks which entry point a
+// visitor reached a short link through. The redirect service strips it before
+// forwarding parameters to the destination.
+const ClickSourceParam = "lr_src"
+
+// ClickSourceQR marks clicks from scanning a dynamic QR code.
+const ClickSourceQR = "qr"
+
 // ClickEvent is a lightweight struct for the async tracking pipeline.
 type ClickEvent struct {
 	LinkID      uuid.UUID `json:"link_id"`
@@ -38,6 +47,11 @@ type ClickEvent struct {
 	UserAgent   string    `json:"user_agent"`
 	Referer     string    `json:"referer"`
 	Timestamp   time.Time `json:"timestamp"`
+	// SampleRate is set when the event is one of every SampleRate clicks
+	// sampled on the link; the skipped clicks are counted separately.
+	SampleRate int `json:"sample_rate,omitempty"`
+	// Source is ClickSourceQR for QR code scans and empty otherwise.
+	Source string `json:"source,omitempty"`
 }
 
 // ClickNotification is published to Redis Pub/Sub for real-time WebSocket updates.
@@ -50,6 +64,53 @@ type ClickNotification struct {
 	DeviceType  string    `json:"device_type,omitempty"`
 	Browser     string    `json:"browser,omitempty"`
 	Referer     string    `json:"referer,omitempty"`
+	Source      string    `json:"source,omitempty"`
+}
+
+// RealtimeSnapshot is sent periodically to a workspace's WebSocket clients
+// with headline numbers for a rolling window ending now: its clicks, and
+// the link with the most of them. TopLink is nil when there were none.
+type RealtimeSnapshot struct {
+	WorkspaceID uuid.UUID        `json:"workspace_id"`
+	WindowStart time.Time        `json:"window_start"`
+	Clicks      int64            `json:"clicks"`
+	TopLink     *RealtimeTopLink `json:"top_link,omitempty"`
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
+// RealtimeTopLink is the most clicked link in a RealtimeSnapshot's window.
+type RealtimeTopLink struct {
+	LinkID    uuid.UUID `json:"link_id"`
+	ShortCode string    `json:"short_code,omitempty"`
+	Clicks    int64     `json:"clicks"`
+}
+
+// ClickStreamVersion is the version of the ClickStreamEvent schema. It is
+// raised when fields change meaning or are removed; new fields don't raise it.
+const ClickStreamVersion = 1
+
+// ClickStreamEvent is published to external event buses for each stored
+// click. IPAddress is anonymized when PRIVACY_ANONYMIZE_IP is set.
+type ClickStreamEvent struct {
+	Type           string    `json:"type"`
+	Version        int       `json:"version"`
+	LinkID         uuid.UUID `json:"link_id"`
+	WorkspaceID    uuid.UUID `json:"workspace_id"`
+	ShortCode      string    `json:"short_code"`
+	ClickedAt      time.Time `json:"clicked_at"`
+	IPAddress      string    `json:"ip_address"`
+	UserAgent      string    `json:"user_agent,omitempty"`
+	Referer        string    `json:"referer,omitempty"`
+	CountryCode    string    `json:"country_code,omitempty"`
+	Region         string    `json:"region,omitempty"`
+	City           string    `json:"city,omitempty"`
+	Browser        string    `json:"browser,omitempty"`
+	BrowserVersion string    `json:"browser_version,omitempty"`
+	OS             string    `json:"os,omitempty"`
+	OSVersion      string    `json:"os_version,omitempty"`
+	DeviceType     string    `json:"device_type,omitempty"`
+	IsBot          bool      `json:"is_bot"`
+	Source         string    `json:"source,omitempty"`
 }
 
 func ClickFromSqlc(c sqlc.Click) *Click {
@@ -105,6 +166,9 @@ func ClickFromSqlc(c sqlc.Click) *Click {
 	if c.UtmCampaign.Valid {
 		click.UTMCampaign = &c.UtmCampaign.String
 	}
+	if c.Source.Valid {
+		click.Source = &c.Source.String
+	}
 
 	return click
 }
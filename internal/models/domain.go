@@ -2,6 +2,9 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +17,16 @@ const (
 	SSLFailed  = "failed"
 )
 
+// How the redirect service answers unknown short codes and paths.
+const (
+	// NotFoundModePage renders the branded not-found page.
+	NotFoundModePage = "page"
+	// NotFoundModeRedirect redirects to the not-found URL.
+	NotFoundModeRedirect = "redirect"
+	// NotFoundModeStatus answers with the status code and an empty body.
+	NotFoundModeStatus = "status"
+)
+
 type Domain struct {
 	ID                 uuid.UUID  `json:"id"`
 	WorkspaceID        uuid.UUID  `json:"workspace_id"`
@@ -26,6 +39,8 @@ type Domain struct {
 	LastDNSCheckAt     *time.Time `json:"last_dns_check_at,omitempty"`
 	DefaultRedirectURL *string    `json:"default_redirect_url,omitempty"`
 	Custom404URL       *string    `json:"custom_404_url,omitempty"`
+	NotFoundMode       *string    `json:"not_found_mode,omitempty"`
+	NotFoundStatus     *int       `json:"not_found_status,omitempty"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
@@ -37,6 +52,70 @@ type CreateDomainInput struct {
 type UpdateDomainInput struct {
 	DefaultRedirectURL *string `json:"default_redirect_url,omitempty"`
 	Custom404URL       *string `json:"custom_404_url,omitempty"`
+	// NotFoundMode is one of the NotFoundMode constants, or "" to follow the
+	// instance default again. NotFoundStatus 0 uses the mode's default.
+	NotFoundMode   *string `json:"not_found_mode,omitempty"`
+	NotFoundStatus *int    `json:"not_found_status,omitempty"`
+}
+
+// NotFoundPolicy is how unknown short codes and paths are answered. Status
+// 0 means the mode's default: 302 for redirects and 404 otherwise.
+type NotFoundPolicy struct {
+	Mode   string `json:"mode"`
+	Status int    `json:"status,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// Validate checks that the mode is known, that the status fits the mode and
+// that redirects have somewhere to go.
+func (p NotFoundPolicy) Validate() error {
+	switch p.Mode {
+	case NotFoundModeRedirect:
+		if p.URL == "" {
+			return errors.New("redirect mode needs a not-found URL")
+		}
+		switch p.Status {
+		case 0, http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return fmt.Errorf("status must be 301, 302, 307 or 308 for redirects, got %d", p.Status)
+		}
+	case NotFoundModePage, NotFoundModeStatus:
+		switch p.Status {
+		case 0, http.StatusNotFound, http.StatusGone:
+		default:
+			return fmt.Errorf("status must be 404 or 410, got %d", p.Status)
+		}
+	default:
+		return fmt.Errorf("mode must be page, redirect or status, got %q", p.Mode)
+	}
+	return nil
+}
+
+// StatusCode returns the status the policy answers with.
+func (p NotFoundPolicy) StatusCode() int {
+	if p.Status != 0 {
+		return p.Status
+	}
+	if p.Mode == NotFoundModeRedirect {
+		return http.StatusFound
+	}
+	return http.StatusNotFound
+}
+
+// NotFoundPolicy returns the domain's not-found policy, or fallback when the
+// domain doesn't set a mode.
+func (d *Domain) NotFoundPolicy(fallback NotFoundPolicy) NotFoundPolicy {
+	if d.NotFoundMode == nil || *d.NotFoundMode == "" {
+		return fallback
+	}
+	p := NotFoundPolicy{Mode: *d.NotFoundMode}
+	if d.NotFoundStatus != nil {
+		p.Status = *d.NotFoundStatus
+	}
+	if d.Custom404URL != nil {
+		p.URL = *d.Custom404URL
+	}
+	return p
 }
 
 type DNSRecordsData struct {
@@ -81,6 +160,13 @@ func DomainFromSqlc(d sqlc.Domain) *Domain {
 	if d.Custom404Url.Valid {
 		domain.Custom404URL = &d.Custom404Url.String
 	}
+	if d.NotFoundMode.Valid {
+		domain.NotFoundMode = &d.NotFoundMode.String
+	}
+	if d.NotFoundStatus.Valid {
+		status := int(d.NotFoundStatus.Int32)
+		domain.NotFoundStatus = &status
+	}
 	if d.CreatedAt.Valid {
 		domain.CreatedAt = d.CreatedAt.Time
 	}
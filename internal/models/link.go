@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,57 +12,150 @@ import (
 )
 
 type Link struct {
-	ID           uuid.UUID  `json:"id"`
-	UserID       uuid.UUID  `json:"user_id"`
-	WorkspaceID  uuid.UUID  `json:"workspace_id"`
-	DomainID     *uuid.UUID `json:"domain_id,omitempty"`
-	URL          string     `json:"url"`
-	ShortCode    string     `json:"short_code"`
-	Title        *string    `json:"title,omitempty"`
-	Description  *string    `json:"description,omitempty"`
-	FaviconURL   *string    `json:"favicon_url,omitempty"`
-	OgImageURL   *string    `json:"og_image_url,omitempty"`
-	IsActive     bool       `json:"is_active"`
-	PasswordHash *string    `json:"-"`
-	HasPassword  bool       `json:"has_password"`
-	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
-	MaxClicks    *int32     `json:"max_clicks,omitempty"`
-	UTMSource    *string    `json:"utm_source,omitempty"`
-	UTMMedium    *string    `json:"utm_medium,omitempty"`
-	UTMCampaign  *string    `json:"utm_campaign,omitempty"`
-	UTMTerm      *string    `json:"utm_term,omitempty"`
-	UTMContent   *string    `json:"utm_content,omitempty"`
-	TotalClicks  int64      `json:"total_clicks"`
-	UniqueClicks int64      `json:"unique_clicks"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID              uuid.UUID  `json:"id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	WorkspaceID     uuid.UUID  `json:"workspace_id"`
+	DomainID        *uuid.UUID `json:"domain_id,omitempty"`
+	URL             string     `json:"url"`
+	ShortCode       string     `json:"short_code"`
+	Title           *string    `json:"title,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	FaviconURL      *string    `json:"favicon_url,omitempty"`
+	OgImageURL      *string    `json:"og_image_url,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	PasswordHash    *string    `json:"-"`
+	HasPassword     bool       `json:"has_password"`
+	PasswordHint    *string    `json:"password_hint,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	MaxClicks       *int32     `json:"max_clicks,omitempty"`
+	UTMSource       *string    `json:"utm_source,omitempty"`
+	UTMMedium       *string    `json:"utm_medium,omitempty"`
+	UTMCampaign     *string    `json:"utm_campaign,omitempty"`
+	UTMTerm         *string    `json:"utm_term,omitempty"`
+	UTMContent      *string    `json:"utm_content,omitempty"`
+	TotalClicks     int64      `json:"total_clicks"`
+	UniqueClicks    int64      `json:"unique_clicks"`
+	QRCodeURL       *string    `json:"qr_code_url,omitempty"`
+	Cloak           bool       `json:"cloak"`
+	ForwardParams   bool       `json:"forward_params"`
+	ParamPrecedence string     `json:"param_precedence"`
+	ForceHTTPS      bool       `json:"force_https"`
+	OncePerVisitor  bool       `json:"once_per_visitor"`
+	RepeatVisitURL  *string    `json:"repeat_visit_url,omitempty"`
+	IOSURL          *string    `json:"ios_url,omitempty"`
+	AndroidURL      *string    `json:"android_url,omitempty"`
+	FallbackURL     *string    `json:"fallback_url,omitempty"`
+	FacebookPixelID *string    `json:"facebook_pixel_id,omitempty"`
+	GoogleTagID     *string    `json:"google_tag_id,omitempty"`
+	Interstitial    bool       `json:"interstitial"`
+	InternalNote    *string    `json:"internal_note,omitempty"`
+	ArchivedAt      *time.Time `json:"archived_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Schedule limits the link to recurring weekly windows.
+	Schedule *LinkSchedule `json:"schedule,omitempty"`
+	// FailoverURLs are backup destinations, in order, served while health
+	// checks find the destination down.
+	FailoverURLs []string `json:"failover_urls,omitempty"`
+	// ReservedUntil is set while the link only holds its short code; see
+	// ReserveLinkInput.
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"`
+	// LastClickedAt and Active24h are set in link lists, as in
+	// LinkQuickStats.
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty"`
+	Active24h     *bool      `json:"active_24h,omitempty"`
+	// DestinationCheck is set on create when a destination check was asked for.
+	DestinationCheck *LinkDestinationCheck `json:"destination_check,omitempty"`
+	// Flag is set on create or update when the destination was flagged for review.
+	Flag *LinkFlag `json:"flag,omitempty"`
+	// CommentCount is set on the link detail.
+	CommentCount *int64 `json:"comment_count,omitempty"`
+}
+
+// Query parameter precedence for links that forward incoming parameters.
+const (
+	ParamPrecedenceDestination = "destination"
+	ParamPrecedenceIncoming    = "incoming"
+)
+
+// IsValidParamPrecedence reports whether p is a known precedence.
+func IsValidParamPrecedence(p string) bool {
+	return p == ParamPrecedenceDestination || p == ParamPrecedenceIncoming
 }
 
+// CloakNotice is returned with cloaked links: search engines index the short
+// URL with no content of its own and don't credit the destination, and
+// destinations that refuse framing are redirected normally instead.
+const CloakNotice = "Cloaked links are not indexed by search engines and pass no ranking to the destination. Destinations that block framing fall back to a normal redirect."
+
+// MaxPasswordHintLength is the longest password hint accepted, in
+// characters.
+const MaxPasswordHintLength = 100
+
+// MaxFailoverURLs is the most backup destinations a link can have.
+const MaxFailoverURLs = 3
+
 type LinkResponse struct {
-	ID           uuid.UUID  `json:"id"`
-	UserID       uuid.UUID  `json:"user_id"`
-	WorkspaceID  uuid.UUID  `json:"workspace_id"`
-	DomainID     *uuid.UUID `json:"domain_id,omitempty"`
-	URL          string     `json:"url"`
-	ShortCode    string     `json:"short_code"`
-	ShortURL     string     `json:"short_url"`
-	Title        *string    `json:"title,omitempty"`
-	Description  *string    `json:"description,omitempty"`
-	FaviconURL   *string    `json:"favicon_url,omitempty"`
-	OgImageURL   *string    `json:"og_image_url,omitempty"`
-	IsActive     bool       `json:"is_active"`
-	HasPassword  bool       `json:"has_password"`
-	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
-	MaxClicks    *int32     `json:"max_clicks,omitempty"`
-	UTMSource    *string    `json:"utm_source,omitempty"`
-	UTMMedium    *string    `json:"utm_medium,omitempty"`
-	UTMCampaign  *string    `json:"utm_campaign,omitempty"`
-	UTMTerm      *string    `json:"utm_term,omitempty"`
-	UTMContent   *string    `json:"utm_content,omitempty"`
-	TotalClicks  int64      `json:"total_clicks"`
-	UniqueClicks int64      `json:"unique_clicks"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID              uuid.UUID  `json:"id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	WorkspaceID     uuid.UUID  `json:"workspace_id"`
+	DomainID        *uuid.UUID `json:"domain_id,omitempty"`
+	URL             string     `json:"url"`
+	ShortCode       string     `json:"short_code"`
+	ShortURL        string     `json:"short_url"`
+	Title           *string    `json:"title,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	FaviconURL      *string    `json:"favicon_url,omitempty"`
+	OgImageURL      *string    `json:"og_image_url,omitempty"`
+	IsActive        bool       `json:"is_active"`
+	HasPassword     bool       `json:"has_password"`
+	PasswordHint    *string    `json:"password_hint,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	MaxClicks       *int32     `json:"max_clicks,omitempty"`
+	UTMSource       *string    `json:"utm_source,omitempty"`
+	UTMMedium       *string    `json:"utm_medium,omitempty"`
+	UTMCampaign     *string    `json:"utm_campaign,omitempty"`
+	UTMTerm         *string    `json:"utm_term,omitempty"`
+	UTMContent      *string    `json:"utm_content,omitempty"`
+	TotalClicks     int64      `json:"total_clicks"`
+	UniqueClicks    int64      `json:"unique_clicks"`
+	QRCodeURL       *string    `json:"qr_code_url,omitempty"`
+	QRCodeDataURI   *string    `json:"qr_code_data_uri,omitempty"`
+	Cloak           bool       `json:"cloak"`
+	CloakNotice     string     `json:"cloak_notice,omitempty"`
+	ForwardParams   bool       `json:"forward_params"`
+	ParamPrecedence string     `json:"param_precedence"`
+	ForceHTTPS      bool       `json:"force_https"`
+	OncePerVisitor  bool       `json:"once_per_visitor"`
+	RepeatVisitURL  *string    `json:"repeat_visit_url,omitempty"`
+	IOSURL          *string    `json:"ios_url,omitempty"`
+	AndroidURL      *string    `json:"android_url,omitempty"`
+	FallbackURL     *string    `json:"fallback_url,omitempty"`
+	FacebookPixelID *string    `json:"facebook_pixel_id,omitempty"`
+	GoogleTagID     *string    `json:"google_tag_id,omitempty"`
+	Interstitial    bool       `json:"interstitial"`
+	InternalNote    *string    `json:"internal_note,omitempty"`
+	ArchivedAt      *time.Time `json:"archived_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Schedule limits the link to recurring weekly windows.
+	Schedule *LinkSchedule `json:"schedule,omitempty"`
+	// FailoverURLs are backup destinations, in order, served while health
+	// checks find the destination down.
+	FailoverURLs []string `json:"failover_urls,omitempty"`
+	// ReservedUntil is set while the link only holds its short code; see
+	// ReserveLinkInput.
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"`
+	// LastClickedAt and Active24h are set in link lists, as in
+	// LinkQuickStats.
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty"`
+	Active24h     *bool      `json:"active_24h,omitempty"`
+	// DestinationCheck is set on create when a destination check was asked for.
+	DestinationCheck *LinkDestinationCheck `json:"destination_check,omitempty"`
+	// Flag is set on create or update when the destination was flagged for review.
+	Flag *LinkFlag `json:"flag,omitempty"`
 }
 
 type CreateLinkInput struct {
@@ -75,6 +171,122 @@ type CreateLinkInput struct {
 	UTMCampaign *string `json:"utm_campaign,omitempty"`
 	UTMTerm     *string `json:"utm_term,omitempty"`
 	UTMContent  *string `json:"utm_content,omitempty"`
+
+	// PasswordHint is shown on the link's password form. It is plain text and
+	// may not contain the password.
+	PasswordHint *string `json:"password_hint,omitempty"`
+
+	// DomainID serves the link from one of the workspace's verified custom
+	// domains instead of the default redirect host.
+	DomainID *uuid.UUID `json:"domain_id,omitempty"`
+
+	// Cloak serves the destination in a full-page iframe so the short URL
+	// stays in the address bar.
+	Cloak bool `json:"cloak,omitempty"`
+
+	// ForwardParams appends the short link's incoming query parameters to the
+	// destination. ParamPrecedence is "destination" (default) or "incoming".
+	ForwardParams   bool    `json:"forward_params,omitempty"`
+	ParamPrecedence *string `json:"param_precedence,omitempty"`
+
+	// ForceHTTPS redirects an http:// destination over https:// when the
+	// destination host answers on HTTPS, and over plain HTTP otherwise.
+	ForceHTTPS bool `json:"force_https,omitempty"`
+
+	// OncePerVisitor lets each visitor follow the link once. Repeat visits go
+	// to RepeatVisitURL, or an "already used" page when it is empty.
+	OncePerVisitor bool    `json:"once_per_visitor,omitempty"`
+	RepeatVisitURL *string `json:"repeat_visit_url,omitempty"`
+
+	// IOSURL and AndroidURL are app URIs, e.g. myapp://product/123, that
+	// visitors on those platforms are sent to first. FallbackURL is used when
+	// the app doesn't open and for everyone else; it defaults to URL.
+	IOSURL      *string `json:"ios_url,omitempty"`
+	AndroidURL  *string `json:"android_url,omitempty"`
+	FallbackURL *string `json:"fallback_url,omitempty"`
+
+	// FacebookPixelID (e.g. 1234567890123456) and GoogleTagID (e.g.
+	// AW-123456789) fire retargeting pixels on an interstitial page before
+	// visitors are redirected.
+	FacebookPixelID *string `json:"facebook_pixel_id,omitempty"`
+	GoogleTagID     *string `json:"google_tag_id,omitempty"`
+
+	// Interstitial shows a "you are leaving" page with a countdown before
+	// redirecting, configured in the workspace's interstitial settings.
+	Interstitial bool `json:"interstitial,omitempty"`
+
+	// Schedule limits the link to recurring weekly windows, e.g. business
+	// hours. Outside them it acts as disabled or sends visitors to the
+	// schedule's fallback URL.
+	Schedule *LinkSchedule `json:"schedule,omitempty"`
+
+	// FailoverURLs are backup destinations, in order. While the worker's
+	// health checks find the destination down, visitors go to the first one
+	// found up instead. At most MaxFailoverURLs.
+	FailoverURLs []string `json:"failover_urls,omitempty"`
+
+	// InternalNote is shown to workspace members only, unlike Title and
+	// Description which may surface in link previews.
+	InternalNote *string `json:"internal_note,omitempty"`
+
+	// GenerateQR creates a QR code for the new link in the same request.
+	// QROptions is optional; defaults are used when omitted.
+	GenerateQR bool               `json:"generate_qr,omitempty"`
+	QROptions  *CreateQRCodeInput `json:"qr_options,omitempty"`
+
+	// CheckDestination checks the destination after the link is created and
+	// returns the result with it. The link is created either way.
+	CheckDestination bool `json:"check_destination,omitempty"`
+
+	// TemplateID starts the link from one of the workspace's link templates.
+	// Fields sent with the request override the template's settings.
+	TemplateID *uuid.UUID `json:"template_id,omitempty"`
+
+	// sent records the JSON fields the request included, so that an explicit
+	// false or null can override a template.
+	sent map[string]bool
+}
+
+func (in *CreateLinkInput) UnmarshalJSON(data []byte) error {
+	type plain CreateLinkInput
+	if err := json.Unmarshal(data, (*plain)(in)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	in.sent = make(map[string]bool, len(fields))
+	for name := range fields {
+		in.sent[strings.ToLower(name)] = true
+	}
+	return nil
+}
+
+// MarshalJSON writes fields the input was decoded with even when they are
+// empty, so that decoding the result again reports the same fields as sent.
+func (in CreateLinkInput) MarshalJSON() ([]byte, error) {
+	type plain CreateLinkInput
+	data, err := json.Marshal(plain(in))
+	if err != nil || len(in.sent) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name := range in.sent {
+		if _, ok := fields[name]; !ok {
+			fields[name] = json.RawMessage("null")
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// Sent reports whether the request body included the JSON field name. It
+// is false for inputs built in code rather than decoded from JSON.
+func (in *CreateLinkInput) Sent(name string) bool {
+	return in.sent[name]
 }
 
 type UpdateLinkInput struct {
@@ -85,15 +297,198 @@ type UpdateLinkInput struct {
 	Password    *string `json:"password,omitempty"`
 	ExpiresAt   *string `json:"expires_at,omitempty"`
 	MaxClicks   *int32  `json:"max_clicks,omitempty"`
+	Cloak       *bool   `json:"cloak,omitempty"`
+
+	// PasswordHint replaces the hint shown on the password form; an empty
+	// one removes it. Removing the password removes the hint too.
+	PasswordHint *string `json:"password_hint,omitempty"`
+
+	ForwardParams   *bool   `json:"forward_params,omitempty"`
+	ParamPrecedence *string `json:"param_precedence,omitempty"`
+	ForceHTTPS      *bool   `json:"force_https,omitempty"`
+	OncePerVisitor  *bool   `json:"once_per_visitor,omitempty"`
+	RepeatVisitURL  *string `json:"repeat_visit_url,omitempty"`
+	IOSURL          *string `json:"ios_url,omitempty"`
+	AndroidURL      *string `json:"android_url,omitempty"`
+	FallbackURL     *string `json:"fallback_url,omitempty"`
+	FacebookPixelID *string `json:"facebook_pixel_id,omitempty"`
+	GoogleTagID     *string `json:"google_tag_id,omitempty"`
+	Interstitial    *bool   `json:"interstitial,omitempty"`
+	InternalNote    *string `json:"internal_note,omitempty"`
+
+	// Schedule replaces the link's schedule; one without windows removes it.
+	Schedule *LinkSchedule `json:"schedule,omitempty"`
+
+	// FailoverURLs replaces the link's backup destinations; an empty list
+	// removes them.
+	FailoverURLs []string `json:"failover_urls,omitempty"`
+}
+
+// LinkMetadata is the destination page metadata stored on a link.
+type LinkMetadata struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	FaviconURL  *string `json:"favicon_url,omitempty"`
+	OgImageURL  *string `json:"og_image_url,omitempty"`
+}
+
+// ValidateLinkInput is a destination URL to check before creating a link.
+type ValidateLinkInput struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// LinkDestinationCheck reports whether a destination responds and where it
+// ends up. A destination is reachable when the final response is below 400.
+// Error explains why no response was received, e.g. a DNS failure or a
+// blocked address. Warning is set when the destination redirects several
+// times before landing.
+type LinkDestinationCheck struct {
+	URL        string            `json:"url"`
+	Reachable  bool              `json:"reachable"`
+	StatusCode int               `json:"status_code,omitempty"`
+	FinalURL   string            `json:"final_url,omitempty"`
+	Redirects  []LinkRedirectHop `json:"redirects"`
+	Warning    string            `json:"warning,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// LongRedirectChain is the number of redirects at which a destination is
+// reported as redirecting several times. Chains this long are common in
+// cloaked and malicious links.
+const LongRedirectChain = 3
+
+// RedirectChainWarning returns the warning for a destination that redirects
+// redirects times, or "" for shorter chains.
+func RedirectChainWarning(redirects int) string {
+	if redirects < LongRedirectChain {
+		return ""
+	}
+	return "destination redirects " + strconv.Itoa(redirects) + " times before landing"
+}
+
+// LinkRedirectHop is one redirect on the way to a destination.
+type LinkRedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
 }
 
+type TransferLinkInput struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" binding:"required"`
+}
+
+// BulkCreateLinkInput creates several links at once. The number of links
+// is capped by configuration rather than here.
 type BulkCreateLinkInput struct {
-	Links []CreateLinkInput `json:"links" binding:"required,min=1,max=100,dive"`
+	Links []CreateLinkInput `json:"links" binding:"required,min=1,dive"`
+}
+
+// BulkUpdateLinksInput applies the same changes to a set of links. At least
+// one change must be given.
+type BulkUpdateLinksInput struct {
+	LinkIDs   []uuid.UUID `json:"link_ids" binding:"required,min=1,max=100"`
+	IsActive  *bool       `json:"is_active,omitempty"`
+	ExpiresAt *string     `json:"expires_at,omitempty"`
+	AddTagIDs []uuid.UUID `json:"add_tag_ids,omitempty"`
+}
+
+type BulkDeleteLinksInput struct {
+	LinkIDs []uuid.UUID `json:"link_ids" binding:"required,min=1,max=100"`
+}
+
+// BulkLinkSkip is a requested link a bulk operation did not touch.
+type BulkLinkSkip struct {
+	LinkID uuid.UUID `json:"link_id"`
+	Reason string    `json:"reason"`
+}
+
+// BulkLinkResult summarizes a bulk update or delete. LinkIDs are the links
+// that were changed; everything else requested is listed in Skipped.
+type BulkLinkResult struct {
+	Requested int            `json:"requested"`
+	Succeeded int            `json:"succeeded"`
+	LinkIDs   []uuid.UUID    `json:"link_ids"`
+	Skipped   []BulkLinkSkip `json:"skipped"`
+}
+
+// BulkLinkJobStatus is the state of a background bulk create.
+type BulkLinkJobStatus string
+
+const (
+	BulkLinkJobPending    BulkLinkJobStatus = "pending"
+	BulkLinkJobProcessing BulkLinkJobStatus = "processing"
+	BulkLinkJobCompleted  BulkLinkJobStatus = "completed"
+	BulkLinkJobFailed     BulkLinkJobStatus = "failed"
+)
+
+// BulkLinkJob creates the links of a large bulk request in the background,
+// one batch at a time. Batches created before a failure are kept; LinkIDs
+// lists the created links in request order.
+type BulkLinkJob struct {
+	ID          uuid.UUID         `json:"id"`
+	WorkspaceID uuid.UUID         `json:"workspace_id"`
+	RequestedBy uuid.UUID         `json:"requested_by"`
+	Status      BulkLinkJobStatus `json:"status"`
+	Error       string            `json:"error,omitempty"`
+	Requested   int               `json:"requested"`
+	Created     int               `json:"created"`
+	LinkIDs     []uuid.UUID       `json:"link_ids"`
+	CreatedAt   time.Time         `json:"created_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+}
+
+// IsFinished reports whether the job completed or failed.
+func (j *BulkLinkJob) IsFinished() bool {
+	return j.Status == BulkLinkJobCompleted || j.Status == BulkLinkJobFailed
 }
 
 type LinkFilter struct {
 	Search   *string `form:"search"`
 	IsActive *bool   `form:"is_active"`
+	// Status is "active" (default) for unarchived links, "archived", or "all".
+	Status string `form:"status"`
+	// IncludeQR embeds a small QR code of each link's short URL.
+	IncludeQR bool `form:"include_qr"`
+}
+
+// Link list statuses.
+const (
+	LinkStatusActive   = "active"
+	LinkStatusArchived = "archived"
+	LinkStatusAll      = "all"
+)
+
+// ArchiveLinkInput controls whether an archived link keeps redirecting.
+type ArchiveLinkInput struct {
+	// Deactivate also stops the link from redirecting.
+	Deactivate bool `json:"deactivate"`
+}
+
+// IsArchived reports whether the link has been archived.
+func (l *Link) IsArchived() bool {
+	return l.ArchivedAt != nil
+}
+
+// IsReserved reports whether the link is a short code reservation that
+// hasn't been claimed yet.
+func (l *Link) IsReserved() bool {
+	return l.ReservedUntil != nil
+}
+
+// Short code reservation lifetimes.
+const (
+	DefaultLinkReservationTTL = 7 * 24 * time.Hour
+	MaxLinkReservationTTL     = 90 * 24 * time.Hour
+)
+
+// ReserveLinkInput holds a short code for the workspace without creating a
+// link. Creating a link with the same short code before TTLHours have passed
+// claims the reservation; after that the code is free again.
+type ReserveLinkInput struct {
+	ShortCode string `json:"short_code" binding:"required"`
+	// TTLHours is how long the code is held, DefaultLinkReservationTTL when
+	// not set.
+	TTLHours int `json:"ttl_hours,omitempty"`
 }
 
 type Pagination struct {
@@ -112,18 +507,100 @@ type LinkQuickStats struct {
 	Clicks24h    int64     `json:"clicks_24h"`
 	Clicks7d     int64     `json:"clicks_7d"`
 	CreatedAt    time.Time `json:"created_at"`
+
+	// LastClickedAt is the latest non-bot click; Active24h is true when it
+	// falls within LinkActiveWindow.
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty"`
+	Active24h     bool       `json:"active_24h"`
+}
+
+// LinkActiveWindow is how recently a link must have been clicked to count
+// as active.
+const LinkActiveWindow = 24 * time.Hour
+
+// LinkActiveAt reports whether a link last clicked at lastClickedAt is
+// active at now.
+func LinkActiveAt(lastClickedAt *time.Time, now time.Time) bool {
+	return lastClickedAt != nil && now.Sub(*lastClickedAt) < LinkActiveWindow
+}
+
+// LinkExportRow is a single link with its click aggregates, as written by
+// workspace link exports.
+type LinkExportRow struct {
+	ID            uuid.UUID  `json:"id"`
+	ShortCode     string     `json:"short_code"`
+	ShortURL      string     `json:"short_url"`
+	URL           string     `json:"url"`
+	Title         *string    `json:"title,omitempty"`
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Clicks        int64      `json:"clicks"`
+	UniqueClicks  int64      `json:"unique_clicks"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty"`
+}
+
+func NewLinkExportRow(l *Link, summary LinkClickSummary, redirectBaseURL string) *LinkExportRow {
+	return &LinkExportRow{
+		ID:            l.ID,
+		ShortCode:     l.ShortCode,
+		ShortURL:      redirectBaseURL + "/" + l.ShortCode,
+		URL:           l.URL,
+		Title:         l.Title,
+		IsActive:      l.IsActive,
+		CreatedAt:     l.CreatedAt,
+		Clicks:        summary.Clicks,
+		UniqueClicks:  summary.UniqueClicks,
+		LastClickedAt: summary.LastClickedAt,
+	}
+}
+
+// LinkExportCSVHeader is the header row matching CSVRecord.
+var LinkExportCSVHeader = []string{
+	"id", "short_code", "short_url", "url", "title", "is_active",
+	"created_at", "clicks", "unique_clicks", "last_clicked_at",
+}
+
+// CSVRecord returns the row's fields in export column order, with the short
+// code, destination and title escaped by CSVText.
+func (r *LinkExportRow) CSVRecord() []string {
+	var title, lastClickedAt string
+	if r.Title != nil {
+		title = *r.Title
+	}
+	if r.LastClickedAt != nil {
+		lastClickedAt = r.LastClickedAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		r.ID.String(),
+		CSVText(r.ShortCode),
+		r.ShortURL,
+		CSVText(r.URL),
+		CSVText(title),
+		strconv.FormatBool(r.IsActive),
+		r.CreatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatInt(r.Clicks, 10),
+		strconv.FormatInt(r.UniqueClicks, 10),
+		lastClickedAt,
+	}
 }
 
 func LinkFromSqlc(l sqlc.Link) *Link {
 	link := &Link{
-		ID:           l.ID,
-		UserID:       l.UserID,
-		WorkspaceID:  l.WorkspaceID,
-		URL:          l.Url,
-		ShortCode:    l.ShortCode,
-		IsActive:     l.IsActive,
-		TotalClicks:  l.TotalClicks,
-		UniqueClicks: l.UniqueClicks,
+		ID:              l.ID,
+		UserID:          l.UserID,
+		WorkspaceID:     l.WorkspaceID,
+		URL:             l.Url,
+		ShortCode:       l.ShortCode,
+		IsActive:        l.IsActive,
+		TotalClicks:     l.TotalClicks,
+		UniqueClicks:    l.UniqueClicks,
+		Cloak:           l.Cloak,
+		ForwardParams:   l.ForwardParams,
+		ParamPrecedence: l.ParamPrecedence,
+		ForceHTTPS:      l.ForceHttps,
+		OncePerVisitor:  l.OncePerVisitor,
+		Interstitial:    l.Interstitial,
+		Schedule:        ParseLinkSchedule(l.Schedule),
 	}
 
 	if l.DomainID.Valid {
@@ -136,6 +613,27 @@ func LinkFromSqlc(l sqlc.Link) *Link {
 	if l.Description.Valid {
 		link.Description = &l.Description.String
 	}
+	if l.InternalNote.Valid {
+		link.InternalNote = &l.InternalNote.String
+	}
+	if l.RepeatVisitUrl.Valid && l.RepeatVisitUrl.String != "" {
+		link.RepeatVisitURL = &l.RepeatVisitUrl.String
+	}
+	if l.IosUrl.Valid && l.IosUrl.String != "" {
+		link.IOSURL = &l.IosUrl.String
+	}
+	if l.AndroidUrl.Valid && l.AndroidUrl.String != "" {
+		link.AndroidURL = &l.AndroidUrl.String
+	}
+	if l.FallbackUrl.Valid && l.FallbackUrl.String != "" {
+		link.FallbackURL = &l.FallbackUrl.String
+	}
+	if l.FacebookPixelID.Valid && l.FacebookPixelID.String != "" {
+		link.FacebookPixelID = &l.FacebookPixelID.String
+	}
+	if l.GoogleTagID.Valid && l.GoogleTagID.String != "" {
+		link.GoogleTagID = &l.GoogleTagID.String
+	}
 	if l.FaviconUrl.Valid {
 		link.FaviconURL = &l.FaviconUrl.String
 	}
@@ -146,10 +644,20 @@ func LinkFromSqlc(l sqlc.Link) *Link {
 		link.PasswordHash = &l.PasswordHash.String
 		link.HasPassword = true
 	}
+	if l.PasswordHint.Valid && l.PasswordHint.String != "" {
+		link.PasswordHint = &l.PasswordHint.String
+	}
+	if len(l.FailoverUrls) > 0 {
+		link.FailoverURLs = l.FailoverUrls
+	}
 	if l.ExpiresAt.Valid {
 		t := l.ExpiresAt.Time
 		link.ExpiresAt = &t
 	}
+	if l.ArchivedAt.Valid {
+		t := l.ArchivedAt.Time
+		link.ArchivedAt = &t
+	}
 	if l.MaxClicks.Valid {
 		v := l.MaxClicks.Int32
 		link.MaxClicks = &v
@@ -175,20 +683,31 @@ func LinkFromSqlc(l sqlc.Link) *Link {
 	if l.UpdatedAt.Valid {
 		link.UpdatedAt = l.UpdatedAt.Time
 	}
+	if l.ReservedUntil.Valid {
+		t := l.ReservedUntil.Time
+		link.ReservedUntil = &t
+	}
 
 	return link
 }
 
 func LinkFromSqlcRow(r sqlc.ListLinksForWorkspaceRow) *Link {
 	l := &Link{
-		ID:           r.ID,
-		UserID:       r.UserID,
-		WorkspaceID:  r.WorkspaceID,
-		URL:          r.Url,
-		ShortCode:    r.ShortCode,
-		IsActive:     r.IsActive,
-		TotalClicks:  r.TotalClicks,
-		UniqueClicks: r.UniqueClicks,
+		ID:              r.ID,
+		UserID:          r.UserID,
+		WorkspaceID:     r.WorkspaceID,
+		URL:             r.Url,
+		ShortCode:       r.ShortCode,
+		IsActive:        r.IsActive,
+		TotalClicks:     r.TotalClicks,
+		UniqueClicks:    r.UniqueClicks,
+		Cloak:           r.Cloak,
+		ForwardParams:   r.ForwardParams,
+		ParamPrecedence: r.ParamPrecedence,
+		ForceHTTPS:      r.ForceHttps,
+		OncePerVisitor:  r.OncePerVisitor,
+		Interstitial:    r.Interstitial,
+		Schedule:        ParseLinkSchedule(r.Schedule),
 	}
 
 	if r.DomainID.Valid {
@@ -201,6 +720,27 @@ func LinkFromSqlcRow(r sqlc.ListLinksForWorkspaceRow) *Link {
 	if r.Description.Valid {
 		l.Description = &r.Description.String
 	}
+	if r.InternalNote.Valid {
+		l.InternalNote = &r.InternalNote.String
+	}
+	if r.RepeatVisitUrl.Valid && r.RepeatVisitUrl.String != "" {
+		l.RepeatVisitURL = &r.RepeatVisitUrl.String
+	}
+	if r.IosUrl.Valid && r.IosUrl.String != "" {
+		l.IOSURL = &r.IosUrl.String
+	}
+	if r.AndroidUrl.Valid && r.AndroidUrl.String != "" {
+		l.AndroidURL = &r.AndroidUrl.String
+	}
+	if r.FallbackUrl.Valid && r.FallbackUrl.String != "" {
+		l.FallbackURL = &r.FallbackUrl.String
+	}
+	if r.FacebookPixelID.Valid && r.FacebookPixelID.String != "" {
+		l.FacebookPixelID = &r.FacebookPixelID.String
+	}
+	if r.GoogleTagID.Valid && r.GoogleTagID.String != "" {
+		l.GoogleTagID = &r.GoogleTagID.String
+	}
 	if r.FaviconUrl.Valid {
 		l.FaviconURL = &r.FaviconUrl.String
 	}
@@ -211,10 +751,20 @@ func LinkFromSqlcRow(r sqlc.ListLinksForWorkspaceRow) *Link {
 		l.PasswordHash = &r.PasswordHash.String
 		l.HasPassword = true
 	}
+	if r.PasswordHint.Valid && r.PasswordHint.String != "" {
+		l.PasswordHint = &r.PasswordHint.String
+	}
+	if len(r.FailoverUrls) > 0 {
+		l.FailoverURLs = r.FailoverUrls
+	}
 	if r.ExpiresAt.Valid {
 		t := r.ExpiresAt.Time
 		l.ExpiresAt = &t
 	}
+	if r.ArchivedAt.Valid {
+		t := r.ArchivedAt.Time
+		l.ArchivedAt = &t
+	}
 	if r.MaxClicks.Valid {
 		v := r.MaxClicks.Int32
 		l.MaxClicks = &v
@@ -240,37 +790,67 @@ func LinkFromSqlcRow(r sqlc.ListLinksForWorkspaceRow) *Link {
 	if r.UpdatedAt.Valid {
 		l.UpdatedAt = r.UpdatedAt.Time
 	}
+	if r.ReservedUntil.Valid {
+		t := r.ReservedUntil.Time
+		l.ReservedUntil = &t
+	}
 
 	return l
 }
 
 func (l *Link) ToResponse(redirectBaseURL string) *LinkResponse {
-	return &LinkResponse{
-		ID:           l.ID,
-		UserID:       l.UserID,
-		WorkspaceID:  l.WorkspaceID,
-		DomainID:     l.DomainID,
-		URL:          l.URL,
-		ShortCode:    l.ShortCode,
-		ShortURL:     redirectBaseURL + "/" + l.ShortCode,
-		Title:        l.Title,
-		Description:  l.Description,
-		FaviconURL:   l.FaviconURL,
-		OgImageURL:   l.OgImageURL,
-		IsActive:     l.IsActive,
-		HasPassword:  l.HasPassword,
-		ExpiresAt:    l.ExpiresAt,
-		MaxClicks:    l.MaxClicks,
-		UTMSource:    l.UTMSource,
-		UTMMedium:    l.UTMMedium,
-		UTMCampaign:  l.UTMCampaign,
-		UTMTerm:      l.UTMTerm,
-		UTMContent:   l.UTMContent,
-		TotalClicks:  l.TotalClicks,
-		UniqueClicks: l.UniqueClicks,
-		CreatedAt:    l.CreatedAt,
-		UpdatedAt:    l.UpdatedAt,
+	resp := &LinkResponse{
+		ID:              l.ID,
+		UserID:          l.UserID,
+		WorkspaceID:     l.WorkspaceID,
+		DomainID:        l.DomainID,
+		URL:             l.URL,
+		ShortCode:       l.ShortCode,
+		ShortURL:        redirectBaseURL + "/" + l.ShortCode,
+		Title:           l.Title,
+		Description:     l.Description,
+		FaviconURL:      l.FaviconURL,
+		OgImageURL:      l.OgImageURL,
+		IsActive:        l.IsActive,
+		HasPassword:     l.HasPassword,
+		PasswordHint:    l.PasswordHint,
+		ExpiresAt:       l.ExpiresAt,
+		MaxClicks:       l.MaxClicks,
+		UTMSource:       l.UTMSource,
+		UTMMedium:       l.UTMMedium,
+		UTMCampaign:     l.UTMCampaign,
+		UTMTerm:         l.UTMTerm,
+		UTMContent:      l.UTMContent,
+		TotalClicks:     l.TotalClicks,
+		UniqueClicks:    l.UniqueClicks,
+		QRCodeURL:       l.QRCodeURL,
+		Cloak:           l.Cloak,
+		ForwardParams:   l.ForwardParams,
+		ParamPrecedence: l.ParamPrecedence,
+		ForceHTTPS:      l.ForceHTTPS,
+		OncePerVisitor:  l.OncePerVisitor,
+		Interstitial:    l.Interstitial,
+		RepeatVisitURL:  l.RepeatVisitURL,
+		IOSURL:          l.IOSURL,
+		AndroidURL:      l.AndroidURL,
+		FallbackURL:     l.FallbackURL,
+		FacebookPixelID: l.FacebookPixelID,
+		GoogleTagID:     l.GoogleTagID,
+		InternalNote:    l.InternalNote,
+		Schedule:        l.Schedule,
+		FailoverURLs:    l.FailoverURLs,
+		ReservedUntil:   l.ReservedUntil,
+		ArchivedAt:      l.ArchivedAt,
+		CreatedAt:       l.CreatedAt,
+		UpdatedAt:       l.UpdatedAt,
+
+		DestinationCheck: l.DestinationCheck,
+		Flag:             l.Flag,
+	}
+	if l.Cloak {
+		resp.CloakNotice = CloakNotice
 	}
+	return resp
 }
 
 func (l *Link) IsExpired() bool {
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// MaxLinkCommentLength caps a comment's body, before Markdown escaping.
+const MaxLinkCommentLength = 2000
+
+// LinkComment is a comment in a link's flat discussion thread. Body is
+// Markdown-escaped, so it renders as the literal text that was entered.
+// UserID and the author fields are empty once the author's account is gone.
+type LinkComment struct {
+	ID              uuid.UUID  `json:"id"`
+	LinkID          uuid.UUID  `json:"link_id"`
+	WorkspaceID     uuid.UUID  `json:"workspace_id"`
+	UserID          *uuid.UUID `json:"user_id,omitempty"`
+	AuthorName      string     `json:"author_name,omitempty"`
+	AuthorAvatarURL *string    `json:"author_avatar_url,omitempty"`
+	Body            string     `json:"body"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type CreateLinkCommentInput struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type LinkCommentListResult struct {
+	Comments []*LinkComment `json:"comments"`
+	Total    int64          `json:"total"`
+}
+
+func LinkCommentFromSqlc(c sqlc.LinkComment) *LinkComment {
+	comment := &LinkComment{
+		ID:          c.ID,
+		LinkID:      c.LinkID,
+		WorkspaceID: c.WorkspaceID,
+		Body:        c.Body,
+		CreatedAt:   c.CreatedAt.Time,
+	}
+	if c.UserID.Valid {
+		id := uuid.UUID(c.UserID.Bytes)
+		comment.UserID = &id
+	}
+	return comment
+}
+
+// LinkCommentFromSqlcRow converts a comment joined with its author.
+func LinkCommentFromSqlcRow(r sqlc.ListLinkCommentsRow) *LinkComment {
+	comment := LinkCommentFromSqlc(sqlc.LinkComment{
+		ID:          r.ID,
+		LinkID:      r.LinkID,
+		WorkspaceID: r.WorkspaceID,
+		UserID:      r.UserID,
+		Body:        r.Body,
+		CreatedAt:   r.CreatedAt,
+	})
+	comment.AuthorName = r.AuthorName.String
+	if r.AuthorAvatarUrl.Valid {
+		comment.AuthorAvatarURL = &r.AuthorAvatarUrl.String
+	}
+	return comment
+}
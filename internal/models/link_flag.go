@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// Review states of a link flag.
+const (
+	LinkFlagPending  = "pending"
+	LinkFlagApproved = "approved"
+	LinkFlagRejected = "rejected"
+)
+
+// LinkFlag records that a link's destination matched a malware or phishing
+// source, or that visitors reported the link, and that it is waiting for, or
+// has had, an instance admin's review.
+type LinkFlag struct {
+	LinkID      uuid.UUID  `json:"link_id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	URL         string     `json:"url"`
+	Source      string     `json:"source"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	ReviewedBy  *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsPending reports whether the flag still awaits review.
+func (f *LinkFlag) IsPending() bool {
+	return f.Status == LinkFlagPending
+}
+
+type LinkFlagListResult struct {
+	Flags []*LinkFlag `json:"flags"`
+	Total int64       `json:"total"`
+}
+
+func LinkFlagFromSqlc(f sqlc.LinkFlag) *LinkFlag {
+	flag := &LinkFlag{
+		LinkID:      f.LinkID,
+		WorkspaceID: f.WorkspaceID,
+		URL:         f.Url,
+		Source:      f.Source,
+		Reason:      f.Reason,
+		Status:      f.Status,
+	}
+	if f.ReviewedBy.Valid {
+		id := uuid.UUID(f.ReviewedBy.Bytes)
+		flag.ReviewedBy = &id
+	}
+	if f.ReviewedAt.Valid {
+		flag.ReviewedAt = &f.ReviewedAt.Time
+	}
+	if f.CreatedAt.Valid {
+		flag.CreatedAt = f.CreatedAt.Time
+	}
+	return flag
+}
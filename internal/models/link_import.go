@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Link shorteners links can be imported from.
+const (
+	LinkImportSourceBitly = "bitly"
+)
+
+// LinkImportStatus is the state of a link import job.
+type LinkImportStatus string
+
+const (
+	LinkImportPending    LinkImportStatus = "pending"
+	LinkImportProcessing LinkImportStatus = "processing"
+	LinkImportCompleted  LinkImportStatus = "completed"
+	LinkImportFailed     LinkImportStatus = "failed"
+)
+
+// MaxLinkImportIssues caps the conflicts and warnings kept on a job. Counts
+// stay exact past the cap.
+const MaxLinkImportIssues = 200
+
+// StartLinkImportInput starts importing links from another shortener. Token
+// is an API token for the source account; it is used for the import only
+// and never stored.
+type StartLinkImportInput struct {
+	Source string `json:"source" binding:"required"`
+	Token  string `json:"token" binding:"required"`
+	// OnConflict is "skip" (default) to leave out links whose short code is
+	// taken, or "suffix" to import them under a suffixed one.
+	OnConflict string `json:"on_conflict,omitempty"`
+}
+
+// LinkImport is a background job that recreates links from another
+// shortener in a workspace.
+type LinkImport struct {
+	ID          uuid.UUID        `json:"id"`
+	WorkspaceID uuid.UUID        `json:"workspace_id"`
+	RequestedBy uuid.UUID        `json:"requested_by"`
+	Source      string           `json:"source"`
+	OnConflict  string           `json:"on_conflict"`
+	Status      LinkImportStatus `json:"status"`
+	Error       string           `json:"error,omitempty"`
+	// Fetched counts links read from the source so far; Links counts those
+	// created or skipped.
+	Fetched     int              `json:"fetched"`
+	Links       ImportCounts     `json:"links"`
+	Conflicts   []ImportConflict `json:"conflicts"`
+	Warnings    []ImportWarning  `json:"warnings"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// IsFinished reports whether the job completed or failed.
+func (j *LinkImport) IsFinished() bool {
+	return j.Status == LinkImportCompleted || j.Status == LinkImportFailed
+}
+
+// AddConflict records a conflict unless the cap is reached.
+func (j *LinkImport) AddConflict(c ImportConflict) {
+	if len(j.Conflicts) < MaxLinkImportIssues {
+		j.Conflicts = append(j.Conflicts, c)
+	}
+}
+
+// AddWarning records a warning unless the cap is reached.
+func (j *LinkImport) AddWarning(w ImportWarning) {
+	if len(j.Warnings) < MaxLinkImportIssues {
+		j.Warnings = append(j.Warnings, w)
+	}
+}
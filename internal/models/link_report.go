@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// Reasons a visitor can give when reporting a link.
+const (
+	LinkReportPhishing = "phishing"
+	LinkReportMalware  = "malware"
+	LinkReportSpam     = "spam"
+	LinkReportIllegal  = "illegal"
+	LinkReportOther    = "other"
+)
+
+// IsValidLinkReportReason reports whether reason is a known report reason.
+func IsValidLinkReportReason(reason string) bool {
+	switch reason {
+	case LinkReportPhishing, LinkReportMalware, LinkReportSpam, LinkReportIllegal, LinkReportOther:
+		return true
+	}
+	return false
+}
+
+// LinkReport is an abuse report filed against a link. The reporter is only
+// identified by the email they chose to give.
+type LinkReport struct {
+	ID            uuid.UUID `json:"id"`
+	LinkID        uuid.UUID `json:"link_id"`
+	WorkspaceID   uuid.UUID `json:"workspace_id"`
+	Reason        string    `json:"reason"`
+	Details       *string   `json:"details,omitempty"`
+	ReporterEmail *string   `json:"reporter_email,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ReportLinkInput is posted by visitors to report a link. Website is a
+// honeypot field that is hidden from people, so only bots fill it in.
+type ReportLinkInput struct {
+	Reason  string  `json:"reason" form:"reason" binding:"required"`
+	Details *string `json:"details,omitempty" form:"details"`
+	Email   *string `json:"email,omitempty" form:"email"`
+	Website string  `json:"website,omitempty" form:"website"`
+}
+
+// LinkReportedEvent is the payload of the link.reported webhook event. It
+// leaves out the reporter's email.
+type LinkReportedEvent struct {
+	LinkID      uuid.UUID `json:"link_id"`
+	ShortCode   string    `json:"short_code"`
+	Reason      string    `json:"reason"`
+	ReportCount int64     `json:"report_count"`
+	Disabled    bool      `json:"disabled"`
+}
+
+func LinkReportFromSqlc(r sqlc.LinkReport) *LinkReport {
+	report := &LinkReport{
+		ID:          r.ID,
+		LinkID:      r.LinkID,
+		WorkspaceID: r.WorkspaceID,
+		Reason:      r.Reason,
+	}
+	if r.Details.Valid {
+		report.Details = &r.Details.String
+	}
+	if r.ReporterEmail.Valid {
+		report.ReporterEmail = &r.ReporterEmail.String
+	}
+	if r.CreatedAt.Valid {
+		report.CreatedAt = r.CreatedAt.Time
+	}
+	return report
+}
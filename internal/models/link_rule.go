@@ -0,0 +1,120 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// LinkRuleConditions maps each rule type understood by the redirect rule
+// engine to the condition values it can match.
+var LinkRuleConditions = map[string][]string{
+	"device":  {"mobile", "tablet", "desktop"},
+	"browser": {"chrome", "firefox", "safari", "edge"},
+	"os":      {"windows", "macos", "mac", "linux", "ios", "android"},
+}
+
+// IsValidLinkRuleCondition reports whether value is a known condition for
+// ruleType. Values are matched case-insensitively.
+func IsValidLinkRuleCondition(ruleType, value string) bool {
+	for _, v := range LinkRuleConditions[ruleType] {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+type LinkRule struct {
+	ID             uuid.UUID `json:"id"`
+	LinkID         uuid.UUID `json:"link_id"`
+	RuleType       string    `json:"rule_type"`
+	Value          string    `json:"value"`
+	DestinationURL string    `json:"destination_url"`
+	Priority       int32     `json:"priority"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type CreateLinkRuleInput struct {
+	RuleType       string `json:"rule_type" binding:"required"`
+	Value          string `json:"value" binding:"required"`
+	DestinationURL string `json:"destination_url" binding:"required"`
+	Priority       int32  `json:"priority"`
+	IsActive       *bool  `json:"is_active,omitempty"`
+}
+
+type UpdateLinkRuleInput struct {
+	RuleType       *string `json:"rule_type,omitempty"`
+	Value          *string `json:"value,omitempty"`
+	DestinationURL *string `json:"destination_url,omitempty"`
+	Priority       *int32  `json:"priority,omitempty"`
+	IsActive       *bool   `json:"is_active,omitempty"`
+}
+
+// LinkRuleCondition is the JSON stored in link_rules.conditions.
+type LinkRuleCondition struct {
+	Value string `json:"value"`
+}
+
+func LinkRuleFromSqlc(r sqlc.LinkRule) *LinkRule {
+	rule := &LinkRule{
+		ID:             r.ID,
+		LinkID:         r.LinkID,
+		RuleType:       r.RuleType,
+		DestinationURL: r.DestinationUrl,
+		Priority:       r.Priority,
+		IsActive:       r.IsActive,
+	}
+	var cond LinkRuleCondition
+	if err := json.Unmarshal(r.Conditions, &cond); err == nil {
+		rule.Value = cond.Value
+	}
+	if r.CreatedAt.Valid {
+		rule.CreatedAt = r.CreatedAt.Time
+	}
+	if r.UpdatedAt.Valid {
+		rule.UpdatedAt = r.UpdatedAt.Time
+	}
+	return rule
+}
+
+// Outcomes of a simulated visit to a link, matching how the redirect service
+// answers it.
+const (
+	SimulationRedirect         = "redirect"
+	SimulationScheduleFallback = "schedule_fallback"
+	SimulationUnavailable      = "unavailable"
+	SimulationDisabled         = "disabled"
+	SimulationExpired          = "expired"
+	SimulationLimitReached     = "limit_reached"
+)
+
+// SimulateRedirectInput describes a simulated visitor. Device, OS and
+// browser take the rule condition values and build a representative
+// User-Agent; UserAgent replaces them with an exact one. Query is the short
+// link's query string, such as "utm_source=newsletter&ref=42".
+type SimulateRedirectInput struct {
+	Device    string `json:"device"`
+	OS        string `json:"os"`
+	Browser   string `json:"browser"`
+	UserAgent string `json:"user_agent"`
+	Query     string `json:"query"`
+}
+
+// RedirectSimulation is where the redirect service would send a simulated
+// visitor. DestinationURL is empty when the link doesn't redirect them, and
+// AppURL is set when their platform first tries the link's app.
+type RedirectSimulation struct {
+	Outcome          string     `json:"outcome"`
+	DestinationURL   string     `json:"destination_url,omitempty"`
+	AppURL           string     `json:"app_url,omitempty"`
+	MatchedRuleID    *uuid.UUID `json:"matched_rule_id,omitempty"`
+	PasswordRequired bool       `json:"password_required"`
+	Interstitial     bool       `json:"interstitial"`
+	UserAgent        string     `json:"user_agent"`
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxLinkScheduleWindows caps the windows on one link's schedule.
+const MaxLinkScheduleWindows = 28
+
+// scheduleDays maps the day names accepted in a schedule window to weekdays.
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// LinkSchedule is a recurring weekly schedule a link is live in, e.g. a
+// store link that only redirects during business hours. Outside every
+// window the link behaves as disabled, or sends visitors to FallbackURL
+// when one is set. Times are wall-clock times in Timezone, UTC when empty.
+//
+// A schedule is separate from IsActive and ExpiresAt: a disabled or
+// expired link stays off whatever its schedule says.
+type LinkSchedule struct {
+	Timezone    string               `json:"timezone,omitempty"`
+	Windows     []LinkScheduleWindow `json:"windows"`
+	FallbackURL string               `json:"fallback_url,omitempty"`
+}
+
+// LinkScheduleWindow is live from Start to End, both "HH:MM", on Days
+// ("mon" through "sun"; every day when empty). An End before Start runs
+// past midnight into the next day, so "22:00"-"02:00" on fri covers
+// Friday night until 2am Saturday. "00:00"-"00:00" is the whole day.
+type LinkScheduleWindow struct {
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// ParseLinkSchedule decodes a stored schedule. Empty or null JSON, and a
+// schedule without windows, mean the link has no schedule.
+func ParseLinkSchedule(data []byte) *LinkSchedule {
+	if len(data) == 0 {
+		return nil
+	}
+	var s LinkSchedule
+	if err := json.Unmarshal(data, &s); err != nil || len(s.Windows) == 0 {
+		return nil
+	}
+	return &s
+}
+
+// Validate checks the time zone, days and times of every window.
+func (s *LinkSchedule) Validate() error {
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if len(s.Windows) > MaxLinkScheduleWindows {
+		return fmt.Errorf("at most %d windows are allowed", MaxLinkScheduleWindows)
+	}
+	for i, w := range s.Windows {
+		for _, d := range w.Days {
+			if _, ok := scheduleDays[strings.ToLower(d)]; !ok {
+				return fmt.Errorf("window %d: unknown day %q, use mon, tue, wed, thu, fri, sat or sun", i+1, d)
+			}
+		}
+		if _, err := parseClock(w.Start); err != nil {
+			return fmt.Errorf("window %d: start %v", i+1, err)
+		}
+		if _, err := parseClock(w.End); err != nil {
+			return fmt.Errorf("window %d: end %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ActiveAt reports whether t falls inside any of the schedule's windows.
+// A nil schedule, or one without windows, is always active.
+func (s *LinkSchedule) ActiveAt(t time.Time) bool {
+	if s == nil || len(s.Windows) == 0 {
+		return true
+	}
+	loc, err := s.location()
+	if err != nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+
+	for _, w := range s.Windows {
+		start, err := parseClock(w.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			continue
+		}
+		switch {
+		case start < end:
+			if w.onDay(today) && minute >= start && minute < end {
+				return true
+			}
+		case start == end:
+			if w.onDay(today) {
+				return true
+			}
+		default:
+			// Overnight: the evening part belongs to the window's day and
+			// the early-morning part to the day after.
+			if w.onDay(today) && minute >= start {
+				return true
+			}
+			if w.onDay(yesterday) && minute < end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *LinkSchedule) location() (*time.Location, error) {
+	if s.Timezone == "" || s.Timezone == "UTC" {
+		return time.UTC, nil
+	}
+	if s.Timezone == "Local" {
+		return nil, errors.New("local time zone is not allowed")
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+func (w LinkScheduleWindow) onDay(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, name := range w.Days {
+		if day, ok := scheduleDays[strings.ToLower(name)]; ok && day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock returns the minutes since midnight of an "HH:MM" time.
+func parseClock(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an HH:MM time", v)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
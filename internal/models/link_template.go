@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// LinkTemplateSettings are the link settings a template pre-fills. They
+// mirror CreateLinkInput; fields that only make sense per link, such as the
+// destination, short code and title, are not part of a template.
+type LinkTemplateSettings struct {
+	DomainID *uuid.UUID `json:"domain_id,omitempty"`
+
+	// Password is write-only: it is hashed when the template is saved and
+	// never returned. A template with a password reports HasPassword.
+	Password *string `json:"password,omitempty"`
+
+	// ExpiresInDays sets a new link's expiry this many days after it is
+	// created.
+	ExpiresInDays *int32 `json:"expires_in_days,omitempty"`
+	MaxClicks     *int32 `json:"max_clicks,omitempty"`
+
+	UTMSource   *string `json:"utm_source,omitempty"`
+	UTMMedium   *string `json:"utm_medium,omitempty"`
+	UTMCampaign *string `json:"utm_campaign,omitempty"`
+	UTMTerm     *string `json:"utm_term,omitempty"`
+	UTMContent  *string `json:"utm_content,omitempty"`
+
+	Cloak           bool    `json:"cloak,omitempty"`
+	ForwardParams   bool    `json:"forward_params,omitempty"`
+	ParamPrecedence *string `json:"param_precedence,omitempty"`
+	ForceHTTPS      bool    `json:"force_https,omitempty"`
+	OncePerVisitor  bool    `json:"once_per_visitor,omitempty"`
+	RepeatVisitURL  *string `json:"repeat_visit_url,omitempty"`
+	FacebookPixelID *string `json:"facebook_pixel_id,omitempty"`
+	GoogleTagID     *string `json:"google_tag_id,omitempty"`
+	Interstitial    bool    `json:"interstitial,omitempty"`
+	GenerateQR      bool    `json:"generate_qr,omitempty"`
+
+	Schedule *LinkSchedule `json:"schedule,omitempty"`
+}
+
+// LinkTemplate is a named set of link settings that new links in the
+// workspace can start from.
+type LinkTemplate struct {
+	ID          uuid.UUID            `json:"id"`
+	WorkspaceID uuid.UUID            `json:"workspace_id"`
+	CreatedBy   *uuid.UUID           `json:"created_by,omitempty"`
+	Name        string               `json:"name"`
+	Description *string              `json:"description,omitempty"`
+	Settings    LinkTemplateSettings `json:"settings"`
+	HasPassword bool                 `json:"has_password"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+
+	PasswordHash string `json:"-"`
+}
+
+type CreateLinkTemplateInput struct {
+	Name        string               `json:"name" binding:"required,min=1,max=100"`
+	Description *string              `json:"description,omitempty" binding:"omitempty,max=500"`
+	Settings    LinkTemplateSettings `json:"settings"`
+}
+
+// UpdateLinkTemplateInput changes a template. Settings, when given, replace
+// the template's settings as a whole, except that a nil Password keeps the
+// current password and an empty one removes it.
+type UpdateLinkTemplateInput struct {
+	Name        *string               `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Description *string               `json:"description,omitempty" binding:"omitempty,max=500"`
+	Settings    *LinkTemplateSettings `json:"settings,omitempty"`
+}
+
+func LinkTemplateFromSqlc(t sqlc.LinkTemplate) *LinkTemplate {
+	tmpl := &LinkTemplate{
+		ID:          t.ID,
+		WorkspaceID: t.WorkspaceID,
+		Name:        t.Name,
+		HasPassword: t.PasswordHash.Valid && t.PasswordHash.String != "",
+		CreatedAt:   t.CreatedAt.Time,
+		UpdatedAt:   t.UpdatedAt.Time,
+	}
+	_ = json.Unmarshal(t.Settings, &tmpl.Settings)
+	// Stored settings never hold a password, but don't trust that here
+	tmpl.Settings.Password = nil
+	if t.CreatedBy.Valid {
+		id := uuid.UUID(t.CreatedBy.Bytes)
+		tmpl.CreatedBy = &id
+	}
+	if t.Description.Valid {
+		d := t.Description.String
+		tmpl.Description = &d
+	}
+	if t.PasswordHash.Valid {
+		tmpl.PasswordHash = t.PasswordHash.String
+	}
+	return tmpl
+}
@@ -57,8 +57,40 @@ type CreateQRCodeInput struct {
 	Margin          *int32  `json:"margin,omitempty"`
 }
 
+// UpdateQRCodeInput changes a QR code's style. Unset fields keep their
+// current values. The code's type and target can't be changed.
+type UpdateQRCodeInput struct {
+	ErrorCorrection *string `json:"error_correction,omitempty"`
+	ForegroundColor *string `json:"foreground_color,omitempty"`
+	BackgroundColor *string `json:"background_color,omitempty"`
+	LogoURL         *string `json:"logo_url,omitempty"`
+	DotStyle        *string `json:"dot_style,omitempty"`
+	CornerStyle     *string `json:"corner_style,omitempty"`
+	Size            *int32  `json:"size,omitempty"`
+	Margin          *int32  `json:"margin,omitempty"`
+}
+
+// IsValidErrorCorrection reports whether level is a QR error-correction
+// level (L, M, Q or H).
+func IsValidErrorCorrection(level string) bool {
+	switch level {
+	case "L", "M", "Q", "H":
+		return true
+	default:
+		return false
+	}
+}
+
+// QRPrintQuery requests print output at a physical size. WidthMM enables
+// print mode; DPI defaults to 300.
+type QRPrintQuery struct {
+	WidthMM   float64 `form:"width_mm"`
+	DPI       int     `form:"dpi"`
+	QuietZone bool    `form:"quiet_zone"`
+}
+
 type BulkQRCodeInput struct {
-	LinkIDs []uuid.UUID       `json:"link_ids" binding:"required,min=1,max=50"`
+	LinkIDs []uuid.UUID       `json:"link_ids" binding:"required,min=1"`
 	Options CreateQRCodeInput `json:"options"`
 }
 
@@ -77,7 +109,7 @@ func QRCodeFromSqlc(q sqlc.QrCode) *QRCode {
 		ScanCount:       q.ScanCount,
 	}
 
-	if q.LogoUrl.Valid {
+	if q.LogoUrl.Valid && q.LogoUrl.String != "" {
 		qr.LogoURL = &q.LogoUrl.String
 	}
 	if q.PngUrl.Valid {
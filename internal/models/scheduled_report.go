@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// scheduledReportPeriods are the analytics windows a scheduled report can
+// cover, ending when the report runs.
+var scheduledReportPeriods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// DefaultScheduledReportPeriod is used when a report's scope has no period.
+const DefaultScheduledReportPeriod = "7d"
+
+// IsValidScheduledReportPeriod reports whether period is 24h, 7d, 30d or 90d.
+func IsValidScheduledReportPeriod(period string) bool {
+	_, ok := scheduledReportPeriods[period]
+	return ok
+}
+
+// ScheduledReportScope selects what a scheduled report covers.
+type ScheduledReportScope struct {
+	Period string `json:"period"`
+}
+
+// DateRange returns the scope's period ending at end.
+func (s ScheduledReportScope) DateRange(end time.Time) DateRange {
+	d, ok := scheduledReportPeriods[s.Period]
+	if !ok {
+		d = scheduledReportPeriods[DefaultScheduledReportPeriod]
+	}
+	end = end.UTC()
+	return DateRange{Start: end.Add(-d), End: end}
+}
+
+// ScheduledReport emails a summary of workspace analytics to its recipients
+// on a cron schedule, evaluated in Timezone.
+type ScheduledReport struct {
+	ID          uuid.UUID            `json:"id"`
+	WorkspaceID uuid.UUID            `json:"workspace_id"`
+	CreatedBy   *uuid.UUID           `json:"created_by,omitempty"`
+	Name        string               `json:"name"`
+	Schedule    string               `json:"schedule"`
+	Timezone    string               `json:"timezone"`
+	Recipients  []string             `json:"recipients"`
+	Scope       ScheduledReportScope `json:"scope"`
+	IsActive    bool                 `json:"is_active"`
+	NextRunAt   time.Time            `json:"next_run_at"`
+	LastRunAt   *time.Time           `json:"last_run_at,omitempty"`
+	LastError   *string              `json:"last_error,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+type CreateScheduledReportInput struct {
+	Name       string                `json:"name" binding:"required,min=1,max=255"`
+	Schedule   string                `json:"schedule" binding:"required,max=100"`
+	Timezone   string                `json:"timezone,omitempty" binding:"max=64"`
+	Recipients []string              `json:"recipients" binding:"required,min=1,dive,email"`
+	Scope      *ScheduledReportScope `json:"scope,omitempty"`
+}
+
+type UpdateScheduledReportInput struct {
+	Name       *string               `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Schedule   *string               `json:"schedule,omitempty" binding:"omitempty,max=100"`
+	Timezone   *string               `json:"timezone,omitempty" binding:"omitempty,max=64"`
+	Recipients []string              `json:"recipients,omitempty" binding:"omitempty,min=1,dive,email"`
+	Scope      *ScheduledReportScope `json:"scope,omitempty"`
+	IsActive   *bool                 `json:"is_active,omitempty"`
+}
+
+func ScheduledReportFromSqlc(r sqlc.ScheduledReport) *ScheduledReport {
+	report := &ScheduledReport{
+		ID:          r.ID,
+		WorkspaceID: r.WorkspaceID,
+		Name:        r.Name,
+		Schedule:    r.Schedule,
+		Timezone:    r.Timezone,
+		Recipients:  r.Recipients,
+		IsActive:    r.IsActive,
+		NextRunAt:   r.NextRunAt.Time,
+		CreatedAt:   r.CreatedAt.Time,
+		UpdatedAt:   r.UpdatedAt.Time,
+	}
+	_ = json.Unmarshal(r.Scope, &report.Scope)
+	if report.Scope.Period == "" {
+		report.Scope.Period = DefaultScheduledReportPeriod
+	}
+	if r.CreatedBy.Valid {
+		id := uuid.UUID(r.CreatedBy.Bytes)
+		report.CreatedBy = &id
+	}
+	if r.LastRunAt.Valid {
+		t := r.LastRunAt.Time
+		report.LastRunAt = &t
+	}
+	if r.LastError.Valid {
+		e := r.LastError.String
+		report.LastError = &e
+	}
+	return report
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+)
+
+// UsageCounts are a workspace's metered counts for one billing period.
+type UsageCounts struct {
+	LinksCreated      int64 `json:"links_created"`
+	ClicksTracked     int64 `json:"clicks_tracked"`
+	QRCodesGenerated  int64 `json:"qr_codes_generated"`
+	WebhookDeliveries int64 `json:"webhook_deliveries"`
+}
+
+// UsageLimits are the monthly limits of the current tier for the metered
+// counts that have one; -1 means unlimited.
+type UsageLimits struct {
+	LinksCreated  int64 `json:"links_created"`
+	ClicksTracked int64 `json:"clicks_tracked"`
+}
+
+// WorkspaceUsage is a workspace's usage for a calendar month (UTC), counted
+// live. PeriodEnd is exclusive.
+type WorkspaceUsage struct {
+	WorkspaceID uuid.UUID   `json:"workspace_id"`
+	PeriodStart time.Time   `json:"period_start"`
+	PeriodEnd   time.Time   `json:"period_end"`
+	Tier        string      `json:"tier"`
+	Usage       UsageCounts `json:"usage"`
+	Limits      UsageLimits `json:"limits"`
+}
+
+// UsageSnapshot is a workspace's usage for a month as last recorded by the
+// snapshot job. Snapshots of the current month are refreshed until it ends.
+type UsageSnapshot struct {
+	WorkspaceID uuid.UUID   `json:"workspace_id"`
+	PeriodStart time.Time   `json:"period_start"`
+	Tier        string      `json:"tier"`
+	Usage       UsageCounts `json:"usage"`
+	CapturedAt  time.Time   `json:"captured_at"`
+}
+
+func UsageSnapshotFromSqlc(s sqlc.UsageSnapshot) *UsageSnapshot {
+	return &UsageSnapshot{
+		WorkspaceID: s.WorkspaceID,
+		PeriodStart: s.PeriodStart.Time,
+		Tier:        s.Tier,
+		Usage: UsageCounts{
+			LinksCreated:      s.LinksCreated,
+			ClicksTracked:     s.ClicksTracked,
+			QRCodesGenerated:  s.QrCodesGenerated,
+			WebhookDeliveries: s.WebhookDeliveries,
+		},
+		CapturedAt: s.CapturedAt.Time,
+	}
+}
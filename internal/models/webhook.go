@@ -15,6 +15,9 @@ var ValidWebhookEvents = []string{
 	"link.deleted",
 	"link.clicked",
 	"link.expired",
+	"link.transferred",
+	"link.reported",
+	"link.commented",
 	"qr.created",
 	"qr.scanned",
 	"biopage.created",
@@ -25,20 +28,57 @@ var ValidWebhookEvents = []string{
 	"team.member_invited",
 	"team.member_joined",
 	"team.member_removed",
+	"license.limit_exceeded",
+}
+
+// Webhook payload schema versions. Webhooks without a pinned version always
+// receive CurrentWebhookPayloadVersion; older versions are produced by
+// downgrading the current envelope.
+const (
+	MinWebhookPayloadVersion     = 1
+	CurrentWebhookPayloadVersion = 2
+)
+
+// IsValidWebhookPayloadVersion reports whether v is a supported payload version.
+func IsValidWebhookPayloadVersion(v int32) bool {
+	return v >= MinWebhookPayloadVersion && v <= CurrentWebhookPayloadVersion
 }
 
 type Webhook struct {
-	ID              uuid.UUID  `json:"id"`
-	WorkspaceID     uuid.UUID  `json:"workspace_id"`
-	URL             string     `json:"url"`
-	Secret          string     `json:"-"`
-	Events          []string   `json:"events"`
-	IsActive        bool       `json:"is_active"`
-	FailureCount    int32      `json:"failure_count"`
-	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
-	LastSuccessAt   *time.Time `json:"last_success_at,omitempty"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
+	ID                      uuid.UUID  `json:"id"`
+	WorkspaceID             uuid.UUID  `json:"workspace_id"`
+	URL                     string     `json:"url"`
+	Secret                  string     `json:"-"`
+	Events                  []string   `json:"events"`
+	IsActive                bool       `json:"is_active"`
+	FailureCount            int32      `json:"failure_count"`
+	LastTriggeredAt         *time.Time `json:"last_triggered_at,omitempty"`
+	LastSuccessAt           *time.Time `json:"last_success_at,omitempty"`
+	PayloadVersion          *int32     `json:"payload_version,omitempty"`
+	PreviousSecret          string     `json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+// SigningSecrets returns the secrets a delivery made at now is signed with:
+// the current secret, followed by the previous one while its dual-sign
+// window is open.
+func (w *Webhook) SigningSecrets(now time.Time) []string {
+	secrets := []string{w.Secret}
+	if w.PreviousSecret != "" && w.PreviousSecretExpiresAt != nil && now.Before(*w.PreviousSecretExpiresAt) {
+		secrets = append(secrets, w.PreviousSecret)
+	}
+	return secrets
+}
+
+// EffectivePayloadVersion returns the pinned payload version, or the current
+// version if none is pinned.
+func (w *Webhook) EffectivePayloadVersion() int32 {
+	if w.PayloadVersion != nil {
+		return *w.PayloadVersion
+	}
+	return CurrentWebhookPayloadVersion
 }
 
 type WebhookDelivery struct {
@@ -52,9 +92,41 @@ type WebhookDelivery struct {
 	MaxAttempts    int32           `json:"max_attempts"`
 	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
 	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	NextRetryAt    *time.Time      `json:"next_retry_at,omitempty"`
 	CreatedAt      time.Time       `json:"created_at"`
 }
 
+// Reasons a delivery ends up in the dead-letter queue.
+const (
+	WebhookDeadLetterAttemptsExhausted = "attempts_exhausted"
+	WebhookDeadLetterWebhookDisabled   = "webhook_disabled"
+)
+
+// WebhookDeadLetter is a delivery that failed permanently, kept in its
+// workspace's dead-letter queue with everything needed to act on it.
+type WebhookDeadLetter struct {
+	DeliveryID     uuid.UUID       `json:"delivery_id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	WorkspaceID    uuid.UUID       `json:"workspace_id"`
+	URL            string          `json:"url"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int32           `json:"attempts"`
+	ResponseStatus *int32          `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	Reason         string          `json:"reason"`
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+// ReprocessDeadLettersResult summarizes a dead-letter queue reprocess.
+// Deliveries of disabled webhooks are skipped and stay queued; those of
+// deleted webhooks are dropped.
+type ReprocessDeadLettersResult struct {
+	Requeued int `json:"requeued"`
+	Skipped  int `json:"skipped"`
+	Dropped  int `json:"dropped"`
+}
+
 type WebhookEvent struct {
 	Event       string          `json:"event"`
 	WorkspaceID uuid.UUID       `json:"workspace_id"`
@@ -63,8 +135,9 @@ type WebhookEvent struct {
 }
 
 type CreateWebhookInput struct {
-	URL    string   `json:"url" binding:"required,url"`
-	Events []string `json:"events" binding:"required,min=1"`
+	URL            string   `json:"url" binding:"required,url"`
+	Events         []string `json:"events" binding:"required,min=1"`
+	PayloadVersion *int32   `json:"payload_version,omitempty"`
 }
 
 type CreateWebhookResponse struct {
@@ -72,6 +145,13 @@ type CreateWebhookResponse struct {
 	Secret  string   `json:"secret"`
 }
 
+// RotateWebhookSecretResponse carries a rotated webhook's new secret, the
+// only time it is shown.
+type RotateWebhookSecretResponse struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
 func WebhookFromSqlc(w sqlc.Webhook) *Webhook {
 	wh := &Webhook{
 		ID:           w.ID,
@@ -90,6 +170,15 @@ func WebhookFromSqlc(w sqlc.Webhook) *Webhook {
 		t := w.LastSuccessAt.Time
 		wh.LastSuccessAt = &t
 	}
+	if w.PayloadVersion.Valid {
+		v := w.PayloadVersion.Int32
+		wh.PayloadVersion = &v
+	}
+	if w.PreviousSecret.Valid && w.PreviousSecretExpiresAt.Valid {
+		t := w.PreviousSecretExpiresAt.Time
+		wh.PreviousSecret = w.PreviousSecret.String
+		wh.PreviousSecretExpiresAt = &t
+	}
 	if w.CreatedAt.Valid {
 		wh.CreatedAt = w.CreatedAt.Time
 	}
@@ -123,6 +212,10 @@ func WebhookDeliveryFromSqlc(d sqlc.WebhookDelivery) *WebhookDelivery {
 		t := d.CompletedAt.Time
 		wd.CompletedAt = &t
 	}
+	if d.NextRetryAt.Valid {
+		t := d.NextRetryAt.Time
+		wd.NextRetryAt = &t
+	}
 	if d.CreatedAt.Valid {
 		wd.CreatedAt = d.CreatedAt.Time
 	}
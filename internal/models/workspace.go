@@ -2,6 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -109,12 +113,274 @@ type WorkspaceResponse struct {
 
 type CreateWorkspaceInput struct {
 	Name string `json:"name" binding:"required,min=1,max=100"`
-	Slug string `json:"slug" binding:"required,min=1,max=100,alphanumunicode"`
+	Slug string `json:"slug" binding:"required,min=1,max=100"`
+}
+
+// MaxWorkspaceSlugLength is the longest workspace slug accepted.
+const MaxWorkspaceSlugLength = 100
+
+var workspaceSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// IsValidWorkspaceSlug reports whether slug is lowercase letters and digits,
+// optionally separated by single hyphens, and at most MaxWorkspaceSlugLength
+// characters long.
+func IsValidWorkspaceSlug(slug string) bool {
+	return len(slug) <= MaxWorkspaceSlugLength && workspaceSlugPattern.MatchString(slug)
 }
 
 type UpdateWorkspaceInput struct {
-	Name *string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
-	Slug *string `json:"slug,omitempty" binding:"omitempty,min=1,max=100,alphanumunicode"`
+	Name             *string                   `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Slug             *string                   `json:"slug,omitempty" binding:"omitempty,min=1,max=100"`
+	SecurityPolicy   *WorkspaceSecurityPolicy  `json:"security_policy,omitempty"`
+	QRDefaults       *WorkspaceQRDefaults      `json:"qr_defaults,omitempty"`
+	ClickSampling    *WorkspaceClickSampling   `json:"click_sampling,omitempty"`
+	Interstitial     *WorkspaceInterstitial    `json:"interstitial,omitempty"`
+	Branding         *WorkspaceBranding        `json:"branding,omitempty"`
+	ShortCodeLength  *WorkspaceShortCodeLength `json:"short_code_length,omitempty"`
+	AnalyticsBackend *string                   `json:"analytics_backend,omitempty"`
+	Timezone         *string                   `json:"timezone,omitempty"`
+}
+
+// WorkspaceSettings is the typed form of the workspaces.settings JSON column.
+type WorkspaceSettings struct {
+	SecurityPolicy   *WorkspaceSecurityPolicy  `json:"security_policy,omitempty"`
+	QRDefaults       *WorkspaceQRDefaults      `json:"qr_defaults,omitempty"`
+	ClickSampling    *WorkspaceClickSampling   `json:"click_sampling,omitempty"`
+	Interstitial     *WorkspaceInterstitial    `json:"interstitial,omitempty"`
+	Branding         *WorkspaceBranding        `json:"branding,omitempty"`
+	ShortCodeLength  *WorkspaceShortCodeLength `json:"short_code_length,omitempty"`
+	AnalyticsBackend string                    `json:"analytics_backend,omitempty"`
+	// Timezone is the IANA time zone analytics days are counted in. Empty
+	// means UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Location returns the workspace's analytics time zone, UTC when unset or
+// unknown.
+func (s WorkspaceSettings) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Analytics stores a workspace can read its analytics from. Leaving the
+// setting empty uses the deployment's default.
+const (
+	AnalyticsBackendPostgres   = "postgres"
+	AnalyticsBackendClickHouse = "clickhouse"
+)
+
+// IsValidAnalyticsBackend reports whether b names an analytics store.
+func IsValidAnalyticsBackend(b string) bool {
+	return b == AnalyticsBackendPostgres || b == AnalyticsBackendClickHouse
+}
+
+// MaxClickSampleRate is the sparsest click sampling a workspace can choose.
+const MaxClickSampleRate = 1000
+
+// WorkspaceClickSampling has the redirect service record only one in Rate
+// clicks on the workspace's links as click events. Total click counts stay
+// exact. A Rate of 0 or 1 records every click.
+type WorkspaceClickSampling struct {
+	Rate int `json:"rate"`
+}
+
+// ClickSampleRate returns the workspace's click sample rate, 1 when every
+// click is recorded.
+func (s WorkspaceSettings) ClickSampleRate() int {
+	if s.ClickSampling == nil || s.ClickSampling.Rate < 1 {
+		return 1
+	}
+	return s.ClickSampling.Rate
+}
+
+// MaxInterstitialDelaySeconds caps the interstitial countdown.
+const MaxInterstitialDelaySeconds = 30
+
+// WorkspaceInterstitial configures the "you are leaving" page the redirect
+// service shows before redirecting. Enabled shows it for every link in the
+// workspace; links can also turn it on individually. Title and Message
+// replace the default wording. DelaySeconds is the countdown before the
+// visitor continues automatically, where 0 waits for them to click; unset,
+// the redirect service's default applies.
+type WorkspaceInterstitial struct {
+	Enabled      bool   `json:"enabled"`
+	Title        string `json:"title,omitempty"`
+	Message      string `json:"message,omitempty"`
+	DelaySeconds *int   `json:"delay_seconds,omitempty"`
+}
+
+// IsEmpty reports whether the interstitial settings change nothing.
+func (i *WorkspaceInterstitial) IsEmpty() bool {
+	return !i.Enabled && i.Title == "" && i.Message == "" && i.DelaySeconds == nil
+}
+
+// DefaultAccentColor is the accent of redirect pages for workspaces without
+// branding.
+const DefaultAccentColor = "#2563eb"
+
+var accentColorPattern = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// WorkspaceBranding styles the password and error pages the redirect service
+// shows for the workspace's links. LogoURL is an https image shown at the top
+// of the page and AccentColor a hex color such as #0f766e for its buttons.
+type WorkspaceBranding struct {
+	LogoURL     string `json:"logo_url,omitempty"`
+	AccentColor string `json:"accent_color,omitempty"`
+}
+
+// Validate checks the logo URL and accent color. It returns the offending
+// field name with the error.
+func (b *WorkspaceBranding) Validate() (string, error) {
+	if b.LogoURL != "" {
+		u, err := url.Parse(b.LogoURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil || len(b.LogoURL) > 2048 {
+			return "logo_url", errors.New("must be an https URL")
+		}
+	}
+	if b.AccentColor != "" && !accentColorPattern.MatchString(b.AccentColor) {
+		return "accent_color", errors.New("must be a hex color such as #0f766e")
+	}
+	return "", nil
+}
+
+// IsEmpty reports whether the branding changes nothing.
+func (b *WorkspaceBranding) IsEmpty() bool {
+	return b.LogoURL == "" && b.AccentColor == ""
+}
+
+// Logo returns the logo URL, "" when there is none.
+func (b *WorkspaceBranding) Logo() string {
+	if b == nil {
+		return ""
+	}
+	return b.LogoURL
+}
+
+// Accent returns the accent color, DefaultAccentColor when unset.
+func (b *WorkspaceBranding) Accent() string {
+	if b == nil || b.AccentColor == "" {
+		return DefaultAccentColor
+	}
+	return b.AccentColor
+}
+
+// Short code length limits. Custom short codes must be DefaultShortCodeMinLength
+// to MaxShortCodeLength characters unless the workspace configures its own
+// bounds, which may not go outside MinShortCodeLength–MaxShortCodeLength.
+const (
+	MinShortCodeLength        = 2
+	MaxShortCodeLength        = 50
+	DefaultShortCodeMinLength = 3
+)
+
+// WorkspaceShortCodeLength sets the length range for custom short codes in
+// the workspace. A zero Min or Max keeps the default for that bound.
+type WorkspaceShortCodeLength struct {
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// Bounds returns the effective minimum and maximum lengths.
+func (l *WorkspaceShortCodeLength) Bounds() (minLen, maxLen int) {
+	minLen, maxLen = DefaultShortCodeMinLength, MaxShortCodeLength
+	if l == nil {
+		return minLen, maxLen
+	}
+	if l.Min > 0 {
+		minLen = l.Min
+	}
+	if l.Max > 0 {
+		maxLen = l.Max
+	}
+	return minLen, maxLen
+}
+
+// Validate checks the bounds against the hard limits and each other. It
+// returns the offending field name with the error.
+func (l *WorkspaceShortCodeLength) Validate() (string, error) {
+	if l.Min != 0 && (l.Min < MinShortCodeLength || l.Min > MaxShortCodeLength) {
+		return "min", fmt.Errorf("must be between %d and %d", MinShortCodeLength, MaxShortCodeLength)
+	}
+	if l.Max != 0 && (l.Max < MinShortCodeLength || l.Max > MaxShortCodeLength) {
+		return "max", fmt.Errorf("must be between %d and %d", MinShortCodeLength, MaxShortCodeLength)
+	}
+	if minLen, maxLen := l.Bounds(); minLen > maxLen {
+		return "max", fmt.Errorf("must be at least the minimum length (%d)", minLen)
+	}
+	return "", nil
+}
+
+// IsEmpty reports whether both bounds use the defaults.
+func (l *WorkspaceShortCodeLength) IsEmpty() bool {
+	return l.Min == 0 && l.Max == 0
+}
+
+// WorkspaceQRDefaults are applied to new QR codes whose input leaves the
+// corresponding option unset.
+type WorkspaceQRDefaults struct {
+	ErrorCorrection string `json:"error_correction,omitempty"`
+}
+
+// WorkspaceSecurityPolicy lets a workspace enforce shorter session lifetimes
+// than the global auth config. Durations use Go syntax ("15m", "8h"); empty
+// values fall back to the global setting.
+type WorkspaceSecurityPolicy struct {
+	AccessTokenExpiry  string `json:"access_token_expiry,omitempty"`
+	RefreshTokenExpiry string `json:"refresh_token_expiry,omitempty"`
+}
+
+// Validate checks that any configured durations parse and are positive. It
+// returns the offending field name with the error.
+func (p *WorkspaceSecurityPolicy) Validate() (string, error) {
+	if _, err := parsePolicyDuration(p.AccessTokenExpiry); err != nil {
+		return "access_token_expiry", err
+	}
+	if _, err := parsePolicyDuration(p.RefreshTokenExpiry); err != nil {
+		return "refresh_token_expiry", err
+	}
+	return "", nil
+}
+
+// IsEmpty reports whether the policy sets no overrides.
+func (p *WorkspaceSecurityPolicy) IsEmpty() bool {
+	return p.AccessTokenExpiry == "" && p.RefreshTokenExpiry == ""
+}
+
+// Expiries returns the configured token lifetimes, zero where unset.
+func (p *WorkspaceSecurityPolicy) Expiries() (access, refresh time.Duration) {
+	access, _ = parsePolicyDuration(p.AccessTokenExpiry)
+	refresh, _ = parsePolicyDuration(p.RefreshTokenExpiry)
+	return access, refresh
+}
+
+func parsePolicyDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("duration must be positive")
+	}
+	return d, nil
+}
+
+// ParsedSettings decodes the workspace settings. Malformed settings are
+// treated as empty.
+func (w *Workspace) ParsedSettings() WorkspaceSettings {
+	var settings WorkspaceSettings
+	if len(w.Settings) > 0 {
+		_ = json.Unmarshal(w.Settings, &settings)
+	}
+	return settings
 }
 
 func WorkspaceFromSqlc(w sqlc.Workspace) *Workspace {
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceExportFormatVersion is written to each archive's manifest.json.
+// Bump it when the archive layout changes in a way imports must handle.
+const WorkspaceExportFormatVersion = 1
+
+// WorkspaceExportManifest describes an export archive.
+type WorkspaceExportManifest struct {
+	FormatVersion int       `json:"format_version"`
+	WorkspaceID   uuid.UUID `json:"workspace_id"`
+	ExportedAt    time.Time `json:"exported_at"`
+}
+
+// WorkspaceExportStatus is the state of a workspace export job.
+type WorkspaceExportStatus string
+
+const (
+	WorkspaceExportPending    WorkspaceExportStatus = "pending"
+	WorkspaceExportProcessing WorkspaceExportStatus = "processing"
+	WorkspaceExportCompleted  WorkspaceExportStatus = "completed"
+	WorkspaceExportFailed     WorkspaceExportStatus = "failed"
+)
+
+// WorkspaceExport is a background job that packages a workspace's data into
+// a ZIP archive for portability requests.
+type WorkspaceExport struct {
+	ID          uuid.UUID             `json:"id"`
+	WorkspaceID uuid.UUID             `json:"workspace_id"`
+	RequestedBy uuid.UUID             `json:"requested_by"`
+	Status      WorkspaceExportStatus `json:"status"`
+	Error       string                `json:"error,omitempty"`
+	SizeBytes   int64                 `json:"size_bytes,omitempty"`
+	DownloadURL string                `json:"download_url,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time             `json:"expires_at"`
+}
+
+// IsFinished reports whether the job has stopped, successfully or not.
+func (e *WorkspaceExport) IsFinished() bool {
+	return e.Status == WorkspaceExportCompleted || e.Status == WorkspaceExportFailed
+}
+
+// StorageKey is where the export's archive is kept in object storage.
+func (e *WorkspaceExport) StorageKey() string {
+	return WorkspaceExportPrefix(e.WorkspaceID) + e.ID.String() + ".zip"
+}
+
+// WorkspaceExportPrefix is the storage prefix holding a workspace's export
+// archives.
+func WorkspaceExportPrefix(workspaceID uuid.UUID) string {
+	return "exports/" + workspaceID.String() + "/"
+}
@@ -0,0 +1,65 @@
+package models
+
+import "github.com/google/uuid"
+
+// Short code handling when importing links.
+const (
+	ImportShortCodesPreserve   = "preserve"
+	ImportShortCodesRegenerate = "regenerate"
+)
+
+// Conflict handling for short codes and bio page slugs already in use.
+const (
+	ImportConflictSkip   = "skip"
+	ImportConflictSuffix = "suffix"
+)
+
+// WorkspaceImportOptions controls how an export archive is applied.
+type WorkspaceImportOptions struct {
+	// DryRun runs the import without saving anything, to report conflicts.
+	DryRun bool `form:"dry_run"`
+	// ShortCodes is "preserve" (default) to keep exported short codes, or
+	// "regenerate" to give every link a new one.
+	ShortCodes string `form:"short_codes"`
+	// OnConflict is "skip" (default) to leave out items whose short code or
+	// slug is taken, or "suffix" to import them under a suffixed one.
+	OnConflict string `form:"on_conflict"`
+}
+
+// ImportConflict is an item whose short code or slug was already taken.
+type ImportConflict struct {
+	Type       string `json:"type"`
+	Key        string `json:"key"`
+	Resolution string `json:"resolution"`
+	NewKey     string `json:"new_key,omitempty"`
+}
+
+// ImportWarning is an item imported with changes, or left out for a reason
+// other than a conflict.
+type ImportWarning struct {
+	Type    string `json:"type"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// ImportCounts tallies one resource type in an import.
+type ImportCounts struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
+
+// WorkspaceImportResult summarises an import or dry run.
+type WorkspaceImportResult struct {
+	DryRun    bool             `json:"dry_run"`
+	Links     ImportCounts     `json:"links"`
+	BioPages  ImportCounts     `json:"bio_pages"`
+	Webhooks  ImportCounts     `json:"webhooks"`
+	Conflicts []ImportConflict `json:"conflicts"`
+	Warnings  []ImportWarning  `json:"warnings"`
+	// ShortCodes maps exported short codes to the ones they were imported
+	// under, when they differ.
+	ShortCodes map[string]string `json:"short_codes,omitempty"`
+	// WebhookSecrets holds the signing secret of each imported webhook.
+	// Exports don't include secrets, so every imported webhook gets a new one.
+	WebhookSecrets map[uuid.UUID]string `json:"webhook_secrets,omitempty"`
+}
@@ -0,0 +1,278 @@
+// Package ogimage renders Open Graph preview cards as PNG images.
+package ogimage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF decoder for avatars
+	_ "image/jpeg" // register JPEG decoder for avatars
+	"image/png"
+	"time"
+
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// Width and Height are the card dimensions recommended by the Open Graph
+// consumers (Facebook, LinkedIn, Slack).
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	margin         = 80
+	avatarDiameter = 200
+	avatarCenterY  = 170
+	titleTop       = 310
+	titleScale     = 8
+	titleMaxLines  = 2
+	brandScale     = 4
+	initialScale   = 16
+
+	avatarFetchTimeout = 5 * time.Second
+)
+
+var (
+	backgroundColor = color.RGBA{0x11, 0x18, 0x27, 0xff}
+	accentColor     = color.RGBA{0x63, 0x66, 0xf1, 0xff}
+	titleColor      = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	brandColor      = color.RGBA{0x9c, 0xa3, 0xaf, 0xff}
+)
+
+// Card is the content of one OG image.
+type Card struct {
+	Title     string
+	AvatarURL string
+}
+
+// Renderer draws OG cards: the avatar in a circle, the title below it and the
+// brand name along the bottom edge.
+type Renderer struct {
+	fetcher *httputil.SafeClient
+	brand   string
+}
+
+// NewRenderer creates a Renderer. Avatars are only fetched through the
+// SSRF-safe client; with a nil fetcher every card uses the initial-letter
+// placeholder.
+func NewRenderer(fetcher *httputil.SafeClient, brand string) *Renderer {
+	return &Renderer{fetcher: fetcher, brand: brand}
+}
+
+// Render draws card and returns it PNG-encoded. An avatar that cannot be
+// fetched or decoded falls back to a placeholder instead of failing.
+func (r *Renderer) Render(ctx context.Context, card Card) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fillRect(img, img.Bounds(), backgroundColor)
+
+	title := normalizeText(card.Title)
+
+	var avatar image.Image
+	if card.AvatarURL != "" && r.fetcher != nil {
+		avatar, _ = r.loadAvatar(ctx, card.AvatarURL)
+	}
+	if avatar != nil {
+		drawAvatar(img, avatar)
+	} else {
+		initial := title
+		if len(initial) == 0 {
+			initial = normalizeText(r.brand)
+		}
+		drawPlaceholder(img, initial)
+	}
+
+	lineHeight := (glyphHeight + 3) * titleScale
+	maxChars := (Width - 2*margin) / ((glyphWidth + 1) * titleScale)
+	for i, line := range wrapText(title, maxChars, titleMaxLines) {
+		drawCentered(img, line, titleTop+i*lineHeight, titleScale, titleColor)
+	}
+
+	brand := normalizeText(r.brand)
+	drawCentered(img, brand, Height-margin+10-glyphHeight*brandScale, brandScale, brandColor)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode OG image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// loadAvatar fetches and decodes the avatar image at url.
+func (r *Renderer) loadAvatar(ctx context.Context, url string) (image.Image, error) {
+	ctx, cancel := context.WithTimeout(ctx, avatarFetchTimeout)
+	defer cancel()
+
+	res, err := r.fetcher.Get(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch avatar: %w", err)
+	}
+
+	avatar, _, err := image.Decode(bytes.NewReader(res.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode avatar: %w", err)
+	}
+	return avatar, nil
+}
+
+// wrapText breaks text into at most maxLines lines of maxChars runes on word
+// boundaries, ending the last line with "..." when text does not fit.
+func wrapText(text []rune, maxChars, maxLines int) [][]rune {
+	var lines [][]rune
+	var line []rune
+	truncated := false
+
+	words := splitWords(text)
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if len(word) > maxChars {
+			// Hard-break words longer than a whole line.
+			words = append(words[:i+1], append([][]rune{word[maxChars:]}, words[i+1:]...)...)
+			word = word[:maxChars]
+		}
+		switch {
+		case len(line) == 0:
+			line = append(line, word...)
+		case len(line)+1+len(word) <= maxChars:
+			line = append(append(line, ' '), word...)
+		default:
+			lines = append(lines, line)
+			line = append([]rune(nil), word...)
+		}
+		if len(lines) == maxLines {
+			truncated = true
+			break
+		}
+	}
+	if !truncated && len(line) > 0 {
+		lines = append(lines, line)
+	}
+
+	if truncated {
+		last := lines[maxLines-1]
+		if len(last)+3 > maxChars {
+			last = last[:maxChars-3]
+			for i := len(last) - 1; i > 0; i-- {
+				if last[i] == ' ' {
+					last = last[:i]
+					break
+				}
+			}
+		}
+		lines[maxLines-1] = append(last, '.', '.', '.')
+	}
+	return lines
+}
+
+func splitWords(text []rune) [][]rune {
+	var words [][]rune
+	start := 0
+	for i := 0; i <= len(text); i++ {
+		if i == len(text) || text[i] == ' ' {
+			if i > start {
+				words = append(words, text[start:i:i])
+			}
+			start = i + 1
+		}
+	}
+	return words
+}
+
+// drawAvatar paints avatar into the avatar circle, center-cropped to a square
+// and scaled with nearest-neighbour sampling.
+func drawAvatar(img *image.RGBA, avatar image.Image) {
+	b := avatar.Bounds()
+	side := min(b.Dx(), b.Dy())
+	if side == 0 {
+		drawPlaceholder(img, nil)
+		return
+	}
+	sx := b.Min.X + (b.Dx()-side)/2
+	sy := b.Min.Y + (b.Dy()-side)/2
+
+	x0, y0 := avatarOrigin()
+	for y := 0; y < avatarDiameter; y++ {
+		for x := 0; x < avatarDiameter; x++ {
+			if !inCircle(x, y) {
+				continue
+			}
+			src := avatar.At(sx+x*side/avatarDiameter, sy+y*side/avatarDiameter)
+			img.Set(x0+x, y0+y, blend(src, backgroundColor))
+		}
+	}
+}
+
+// drawPlaceholder paints an accent-colored circle with the first letter of
+// text in place of a missing avatar.
+func drawPlaceholder(img *image.RGBA, text []rune) {
+	x0, y0 := avatarOrigin()
+	for y := 0; y < avatarDiameter; y++ {
+		for x := 0; x < avatarDiameter; x++ {
+			if inCircle(x, y) {
+				img.Set(x0+x, y0+y, accentColor)
+			}
+		}
+	}
+	if len(text) == 0 {
+		return
+	}
+	drawCentered(img, text[:1], avatarCenterY-glyphHeight*initialScale/2, initialScale, titleColor)
+}
+
+func avatarOrigin() (int, int) {
+	return Width/2 - avatarDiameter/2, avatarCenterY - avatarDiameter/2
+}
+
+func inCircle(x, y int) bool {
+	r := avatarDiameter / 2
+	dx, dy := x-r, y-r
+	return dx*dx+dy*dy <= r*r
+}
+
+// blend composites c over the opaque background bg.
+func blend(c color.Color, bg color.RGBA) color.RGBA {
+	r, g, b, a := c.RGBA()
+	if a == 0xffff {
+		return color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xff}
+	}
+	mix := func(fg uint32, bg uint8) uint8 {
+		// fg is alpha-premultiplied
+		return uint8((fg + uint32(bg)*0x101*(0xffff-a)/0xffff) >> 8)
+	}
+	return color.RGBA{mix(r, bg.R), mix(g, bg.G), mix(b, bg.B), 0xff}
+}
+
+// drawCentered draws text horizontally centered with its top edge at y.
+func drawCentered(img *image.RGBA, text []rune, y, scale int, c color.RGBA) {
+	advance := (glyphWidth + 1) * scale
+	width := len(text)*advance - scale
+	x := (Width - width) / 2
+	for _, r := range text {
+		drawGlyph(img, glyphs[r], x, y, scale, c)
+		x += advance
+	}
+}
+
+func drawGlyph(img *image.RGBA, glyph [glyphHeight]uint8, x, y, scale int, c color.RGBA) {
+	for row, bits := range glyph {
+		for col := 0; col < glyphWidth; col++ {
+			if bits&(1<<(glyphWidth-1-col)) == 0 {
+				continue
+			}
+			px := x + col*scale
+			py := y + row*scale
+			fillRect(img, image.Rect(px, py, px+scale, py+scale), c)
+		}
+	}
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
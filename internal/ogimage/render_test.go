@@ -0,0 +1,76 @@
+package ogimage
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	r := NewRenderer(nil, "Linkrift")
+
+	data, err := r.Render(context.Background(), Card{Title: "Jane's Links", AvatarURL: "https://example.com/a.png"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+		t.Errorf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), Width, Height)
+	}
+
+	// Without a fetcher the avatar circle holds the placeholder.
+	if got := img.At(Width/2-avatarDiameter/2+10, avatarCenterY); got != accentColor {
+		t.Errorf("avatar placeholder pixel = %v, want %v", got, accentColor)
+	}
+	if got := img.At(5, 5); got != backgroundColor {
+		t.Errorf("background pixel = %v, want %v", got, backgroundColor)
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello, world!", "HELLO, WORLD!"},
+		{"  spaced \t\n out  ", "SPACED OUT"},
+		{"café ☕ 2024", "CAF 2024"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := string(normalizeText(tt.in)); got != tt.want {
+			t.Errorf("normalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"fits", "SHORT ONE", []string{"SHORT ONE"}},
+		{"wraps", "ONE TWO THREE", []string{"ONE TWO", "THREE"}},
+		{"truncates", "ONE TWO THREE FOUR FIVE", []string{"ONE TWO", "THREE..."}},
+		{"hard break", "ABCDEFGHIJKL", []string{"ABCDEFGHIJ", "KL"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText([]rune(tt.in), 10, 2)
+			if len(got) != len(tt.want) {
+				t.Fatalf("wrapText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if string(got[i]) != tt.want[i] {
+					t.Errorf("line %d = %q, want %q", i, string(got[i]), tt.want[i])
+				}
+			}
+		})
+	}
+}
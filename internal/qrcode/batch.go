@@ -4,16 +4,44 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"sync"
 
 	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
 )
 
-// BatchItem represents a single link to generate a QR code for.
+// DefaultBatchWorkers is the worker count used when none is configured.
+const DefaultBatchWorkers = 4
+
+// BatchItem represents a single link to generate a QR code for. URL is
+// what the code encodes; the link's short code, short URL, destination and
+// title are only written to the archive's manifest.
 type BatchItem struct {
-	LinkID uuid.UUID
-	URL    string
+	LinkID         uuid.UUID
+	URL            string
+	ShortCode      string
+	ShortURL       string
+	DestinationURL string
+	Title          string
+	// Err marks an item that can't be generated, e.g. a link that wasn't
+	// found. It is reported as failed without generating anything.
+	Err error
+}
+
+// BatchManifestEntry is one row of a batch archive's manifest, matching a
+// PNG in the archive to its link. File is empty for failed items.
+type BatchManifestEntry struct {
+	LinkID         uuid.UUID `json:"link_id"`
+	ShortCode      string    `json:"short_code,omitempty"`
+	ShortURL       string    `json:"short_url,omitempty"`
+	DestinationURL string    `json:"destination_url,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	File           string    `json:"file,omitempty"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error,omitempty"`
 }
 
 // BatchResult contains the results of a batch QR generation.
@@ -22,6 +50,17 @@ type BatchResult struct {
 	ZipData []byte
 }
 
+// Failed returns the number of items that failed.
+func (r *BatchResult) Failed() int {
+	n := 0
+	for _, item := range r.Results {
+		if item.Error != nil {
+			n++
+		}
+	}
+	return n
+}
+
 // BatchResultItem is the result of generating a single QR code in a batch.
 type BatchResultItem struct {
 	LinkID uuid.UUID
@@ -29,6 +68,19 @@ type BatchResultItem struct {
 	Error  error
 }
 
+// BatchProgress reports a batch item that just finished.
+type BatchProgress struct {
+	LinkID    uuid.UUID
+	Error     error
+	Completed int // items finished so far, including failures
+	Failed    int
+	Total     int
+}
+
+// ProgressFunc receives progress as batch items finish. Calls are serialized,
+// so implementations don't need their own locking.
+type ProgressFunc func(BatchProgress)
+
 // BatchGenerator generates QR codes in parallel.
 type BatchGenerator struct {
 	generator  *Generator
@@ -36,66 +88,154 @@ type BatchGenerator struct {
 }
 
 // NewBatchGenerator creates a batch generator with the specified worker count.
+// Non-positive counts fall back to DefaultBatchWorkers.
 func NewBatchGenerator(gen *Generator, numWorkers int) *BatchGenerator {
 	if numWorkers <= 0 {
-		numWorkers = 4
+		numWorkers = DefaultBatchWorkers
 	}
 	return &BatchGenerator{generator: gen, numWorkers: numWorkers}
 }
 
 // GenerateBatch generates QR codes for multiple links and returns individual PNGs plus a ZIP archive.
 func (bg *BatchGenerator) GenerateBatch(ctx context.Context, items []BatchItem, opts Options) (*BatchResult, error) {
+	return bg.GenerateBatchWithProgress(ctx, items, opts, nil)
+}
+
+// GenerateBatchWithProgress is GenerateBatch with an optional progress
+// callback. A failed item is recorded in its result and the batch carries on;
+// only cancelling ctx stops the batch, in which case ctx's error is returned.
+func (bg *BatchGenerator) GenerateBatchWithProgress(ctx context.Context, items []BatchItem, opts Options, progress ProgressFunc) (*BatchResult, error) {
 	results := make([]BatchResultItem, len(items))
 
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, bg.numWorkers)
+	var mu sync.Mutex
+	completed, failed := 0, 0
+	finish := func(idx int, result BatchResultItem) {
+		results[idx] = result
+
+		mu.Lock()
+		defer mu.Unlock()
+		completed++
+		if result.Error != nil {
+			failed++
+		}
+		if progress != nil {
+			progress(BatchProgress{
+				LinkID:    result.LinkID,
+				Error:     result.Error,
+				Completed: completed,
+				Failed:    failed,
+				Total:     len(items),
+			})
+		}
+	}
 
-	for i, item := range items {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(bg.numWorkers, len(items)); w++ {
 		wg.Add(1)
-		go func(idx int, it BatchItem) {
+		go func() {
 			defer wg.Done()
-
-			select {
-			case <-ctx.Done():
-				results[idx] = BatchResultItem{LinkID: it.LinkID, Error: ctx.Err()}
-				return
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			}
-
-			data, err := bg.generator.Generate(it.URL, opts)
-			results[idx] = BatchResultItem{
-				LinkID: it.LinkID,
-				Data:   data,
-				Error:  err,
+			for idx := range jobs {
+				it := items[idx]
+				if it.Err != nil {
+					finish(idx, BatchResultItem{LinkID: it.LinkID, Error: it.Err})
+					continue
+				}
+				data, err := bg.generator.Generate(it.URL, opts)
+				finish(idx, BatchResultItem{LinkID: it.LinkID, Data: data, Error: err})
 			}
-		}(i, item)
+		}()
 	}
 
+dispatch:
+	for i := range items {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- i:
+		}
+	}
+	close(jobs)
 	wg.Wait()
 
-	// Create ZIP archive
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	zipData, err := buildBatchArchive(items, results)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BatchResult{
+		Results: results,
+		ZipData: zipData,
+	}, nil
+}
+
+// buildBatchArchive zips the generated PNGs with a manifest listing every
+// item's link, file or error, as both manifest.csv and manifest.json.
+func buildBatchArchive(items []BatchItem, results []BatchResultItem) ([]byte, error) {
 	var zipBuf bytes.Buffer
 	zipWriter := zip.NewWriter(&zipBuf)
 
+	entries := make([]BatchManifestEntry, len(results))
 	for i, r := range results {
+		it := items[i]
+		entry := BatchManifestEntry{
+			LinkID:         r.LinkID,
+			ShortCode:      it.ShortCode,
+			ShortURL:       it.ShortURL,
+			DestinationURL: it.DestinationURL,
+			Title:          it.Title,
+			Status:         "ok",
+		}
 		if r.Error != nil || r.Data == nil {
+			entry.Status = "failed"
+			entry.Error = "no image generated"
+			if r.Error != nil {
+				entry.Error = r.Error.Error()
+			}
+			entries[i] = entry
 			continue
 		}
-		filename := fmt.Sprintf("qr_%d_%s.png", i+1, r.LinkID.String()[:8])
-		w, err := zipWriter.Create(filename)
+		entry.File = fmt.Sprintf("qr_%d_%s.png", i+1, r.LinkID.String()[:8])
+		w, err := zipWriter.Create(entry.File)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("failed to create ZIP archive: %w", err)
 		}
 		_, _ = w.Write(r.Data)
+		entries[i] = entry
 	}
 
-	if err := zipWriter.Close(); err != nil {
+	// The CSV keeps its original first four columns so existing readers
+	// still work. Values users chose are escaped so spreadsheets don't run
+	// them as formulas; manifest.json keeps them as entered.
+	var manifest bytes.Buffer
+	mw := csv.NewWriter(&manifest)
+	_ = mw.Write([]string{"link_id", "file", "status", "error", "short_code", "short_url", "destination_url", "title"})
+	for _, e := range entries {
+		_ = mw.Write([]string{e.LinkID.String(), e.File, e.Status, e.Error, models.CSVText(e.ShortCode), e.ShortURL, models.CSVText(e.DestinationURL), models.CSVText(e.Title)})
+	}
+	mw.Flush()
+	w, err := zipWriter.Create("manifest.csv")
+	if err != nil {
 		return nil, fmt.Errorf("failed to create ZIP archive: %w", err)
 	}
+	_, _ = w.Write(manifest.Bytes())
 
-	return &BatchResult{
-		Results: results,
-		ZipData: zipBuf.Bytes(),
-	}, nil
+	manifestJSON, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	w, err = zipWriter.Create("manifest.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ZIP archive: %w", err)
+	}
+	_, _ = w.Write(manifestJSON)
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create ZIP archive: %w", err)
+	}
+	return zipBuf.Bytes(), nil
 }
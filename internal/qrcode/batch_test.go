@@ -0,0 +1,145 @@
+package qrcode
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateBatch_ProgressAndPartialFailure(t *testing.T) {
+	bg := NewBatchGenerator(NewGenerator(nil), 2)
+	missing := errors.New("link not found")
+	items := []BatchItem{
+		{LinkID: uuid.New(), URL: "https://lrift.co/a?src=qr", ShortCode: "a", ShortURL: "https://lrift.co/a", DestinationURL: "https://example.com/a", Title: "Spring menu"},
+		{LinkID: uuid.New(), Err: missing},
+		{LinkID: uuid.New(), URL: "https://example.com/c"},
+	}
+
+	var updates []BatchProgress
+	result, err := bg.GenerateBatchWithProgress(context.Background(), items, DefaultOptions(), func(p BatchProgress) {
+		updates = append(updates, p)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(updates) != 3 {
+		t.Fatalf("got %d progress updates, want 3", len(updates))
+	}
+	last := updates[len(updates)-1]
+	if last.Completed != 3 || last.Failed != 1 || last.Total != 3 {
+		t.Errorf("final progress = %+v", last)
+	}
+
+	if result.Failed() != 1 || !errors.Is(result.Results[1].Error, missing) {
+		t.Errorf("results = %+v", result.Results)
+	}
+	if result.Results[0].Data == nil || result.Results[2].Data == nil {
+		t.Error("expected images for the valid items")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(result.ZipData), int64(len(result.ZipData)))
+	if err != nil {
+		t.Fatalf("reading ZIP: %v", err)
+	}
+	if len(zr.File) != 4 {
+		t.Errorf("ZIP has %d files, want 2 PNGs and two manifests", len(zr.File))
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	csvManifest := string(files["manifest.csv"])
+	if !strings.Contains(csvManifest, items[1].LinkID.String()+",,failed,link not found") {
+		t.Errorf("manifest.csv missing failure row:\n%s", csvManifest)
+	}
+	wantRow := items[0].LinkID.String() + ",qr_1_" + items[0].LinkID.String()[:8] + ".png,ok,,a,https://lrift.co/a,https://example.com/a,Spring menu"
+	if !strings.Contains(csvManifest, wantRow) {
+		t.Errorf("manifest.csv missing link row %q:\n%s", wantRow, csvManifest)
+	}
+
+	var entries []BatchManifestEntry
+	if err := json.Unmarshal(files["manifest.json"], &entries); err != nil {
+		t.Fatalf("decoding manifest.json: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("manifest.json has %d entries, want 3", len(entries))
+	}
+	if e := entries[0]; e.ShortCode != "a" || e.Title != "Spring menu" || e.DestinationURL != "https://example.com/a" {
+		t.Errorf("manifest.json entry = %+v", e)
+	}
+	if _, ok := files[entries[0].File]; !ok {
+		t.Errorf("manifest.json names %q, which isn't in the archive", entries[0].File)
+	}
+	if e := entries[1]; e.Status != "failed" || e.File != "" || e.Error != "link not found" {
+		t.Errorf("manifest.json failure entry = %+v", e)
+	}
+}
+
+func TestGenerateBatch_EscapesManifestFormulas(t *testing.T) {
+	bg := NewBatchGenerator(NewGenerator(nil), 1)
+	item := BatchItem{
+		LinkID:         uuid.New(),
+		URL:            "https://lrift.co/a",
+		ShortCode:      "a",
+		ShortURL:       "https://lrift.co/a",
+		DestinationURL: "@SUM(1+1)",
+		Title:          `=HYPERLINK("https://evil.test")`,
+	}
+	result, err := bg.GenerateBatch(context.Background(), []BatchItem{item}, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(result.ZipData), int64(len(result.ZipData)))
+	if err != nil {
+		t.Fatalf("reading ZIP: %v", err)
+	}
+	f, err := zr.Open("manifest.csv")
+	if err != nil {
+		t.Fatalf("opening manifest.csv: %v", err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("reading manifest.csv: %v", err)
+	}
+	row := rows[1]
+	if row[6] != "'@SUM(1+1)" || row[7] != `'=HYPERLINK("https://evil.test")` {
+		t.Errorf("destination_url, title = %q, %q; want them escaped", row[6], row[7])
+	}
+}
+
+func TestGenerateBatch_Cancelled(t *testing.T) {
+	bg := NewBatchGenerator(NewGenerator(nil), 1)
+	items := make([]BatchItem, 20)
+	for i := range items {
+		items[i] = BatchItem{LinkID: uuid.New(), URL: "https://example.com"}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	completed := 0
+	_, err := bg.GenerateBatchWithProgress(ctx, items, DefaultOptions(), func(p BatchProgress) {
+		completed = p.Completed
+		if p.Completed == 2 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if completed >= len(items) {
+		t.Errorf("batch ran to completion after cancellation")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/link-rift/link-rift/pkg/httputil"
 	"github.com/link-rift/link-rift/pkg/storage"
 )
 
@@ -24,6 +25,7 @@ type Options struct {
 	DotStyle        string
 	CornerStyle     string
 	Margin          int
+	Print           *PrintProfile // optional; overrides Size for print output
 }
 
 // DefaultOptions returns sensible defaults.
@@ -41,7 +43,8 @@ func DefaultOptions() Options {
 
 // Generator generates QR code images.
 type Generator struct {
-	storage storage.ObjectStorage
+	storage     storage.ObjectStorage
+	logoFetcher *httputil.SafeClient
 }
 
 // NewGenerator creates a new QR code generator.
@@ -54,8 +57,8 @@ func (g *Generator) Generate(url string, opts Options) ([]byte, error) {
 	if opts.Size <= 0 {
 		opts.Size = 512
 	}
-	if opts.Size > 2048 {
-		opts.Size = 2048
+	if err := applyPrintProfile(&opts); err != nil {
+		return nil, err
 	}
 
 	fg := parseHexColorWithDefault(opts.ForegroundColor, color.Black)
@@ -82,6 +85,14 @@ func (g *Generator) Generate(url string, opts Options) ([]byte, error) {
 	}
 	imgSize := totalModules * moduleSize
 
+	// Print output must match the requested pixel size exactly, so the
+	// rounding remainder is added to the quiet zone.
+	offset := 0
+	if opts.Print != nil && opts.Size > imgSize {
+		offset = (opts.Size - imgSize) / 2
+		imgSize = opts.Size
+	}
+
 	img := image.NewRGBA(image.Rect(0, 0, imgSize, imgSize))
 
 	// Fill background
@@ -95,8 +106,8 @@ func (g *Generator) Generate(url string, opts Options) ([]byte, error) {
 	for row := 0; row < moduleCount; row++ {
 		for col := 0; col < moduleCount; col++ {
 			if matrix[row][col] {
-				px := (col + margin) * moduleSize
-				py := (row + margin) * moduleSize
+				px := offset + (col+margin)*moduleSize
+				py := offset + (row+margin)*moduleSize
 				for dy := 0; dy < moduleSize; dy++ {
 					for dx := 0; dx < moduleSize; dx++ {
 						img.Set(px+dx, py+dy, fg)
@@ -106,11 +117,22 @@ func (g *Generator) Generate(url string, opts Options) ([]byte, error) {
 		}
 	}
 
+	// Embed logo (best-effort: an unreachable or blocked logo URL still
+	// produces a plain QR code)
+	if opts.LogoURL != "" && g.logoFetcher != nil {
+		if logo, err := g.loadLogo(opts.LogoURL); err == nil {
+			drawLogo(img, logo, bg, moduleSize)
+		}
+	}
+
 	var buf bytes.Buffer
 	if err := png.Encode(&buf, img); err != nil {
 		return nil, fmt.Errorf("failed to encode PNG: %w", err)
 	}
 
+	if opts.Print != nil {
+		return setPNGDensity(buf.Bytes(), opts.Print.DPI), nil
+	}
 	return buf.Bytes(), nil
 }
 
@@ -119,6 +141,9 @@ func (g *Generator) GenerateSVG(url string, opts Options) ([]byte, error) {
 	if opts.Size <= 0 {
 		opts.Size = 512
 	}
+	if err := applyPrintProfile(&opts); err != nil {
+		return nil, err
+	}
 
 	matrix, err := encodeQR(url, opts.ErrorCorrection)
 	if err != nil {
@@ -147,8 +172,14 @@ func (g *Generator) GenerateSVG(url string, opts Options) ([]byte, error) {
 	}
 	totalSize := totalModules * moduleSize
 
+	// Print output carries physical dimensions so it imports at scale
+	width := strconv.Itoa(opts.Size)
+	if opts.Print != nil {
+		width = strconv.FormatFloat(opts.Print.WidthMM, 'f', -1, 64) + "mm"
+	}
+
 	var buf bytes.Buffer
-	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`, totalSize, totalSize, opts.Size, opts.Size)
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%s" height="%s">`, totalSize, totalSize, width, width)
 	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, totalSize, totalSize, bgHex)
 
 	for row := 0; row < moduleCount; row++ {
@@ -0,0 +1,37 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeLogo(t *testing.T) {
+	logo, err := decodeLogo(encodePNG(t, 64, 32))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b := logo.Bounds(); b.Dx() != 64 || b.Dy() != 32 {
+		t.Errorf("logo is %dx%d, want 64x32", b.Dx(), b.Dy())
+	}
+
+	for _, size := range [][2]int{{maxLogoDimension + 1, 1}, {1, maxLogoDimension + 1}} {
+		if _, err := decodeLogo(encodePNG(t, size[0], size[1])); err == nil {
+			t.Errorf("%dx%d logo: expected an error", size[0], size[1])
+		}
+	}
+
+	if _, err := decodeLogo([]byte("not an image")); err == nil {
+		t.Error("expected an error for data that isn't an image")
+	}
+}
@@ -0,0 +1,106 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+)
+
+const (
+	// MaxScreenSize caps the pixel size of QR codes generated without a print
+	// profile.
+	MaxScreenSize = 2048
+
+	// MaxPrintPixels caps the pixel size of print output (e.g. 1000 mm at
+	// 300 DPI) so a large profile can't exhaust memory.
+	MaxPrintPixels = 12000
+
+	MinPrintDPI = 72
+	MaxPrintDPI = 1200
+
+	// SpecQuietZone is the minimum quiet zone, in modules, required by
+	// ISO/IEC 18004.
+	SpecQuietZone = 4
+
+	mmPerInch = 25.4
+)
+
+var ErrInvalidPrintProfile = errors.New("invalid print profile")
+
+// PrintProfile describes QR output for print at a physical size. The pixel
+// size of PNG output is derived from WidthMM and DPI, and SVG output carries
+// the physical dimensions so design tools import it at the right scale.
+type PrintProfile struct {
+	WidthMM float64
+	DPI     int
+	// QuietZone enforces the spec minimum quiet zone, overriding a smaller
+	// Options.Margin.
+	QuietZone bool
+}
+
+// Validate checks the profile against sane bounds.
+func (p PrintProfile) Validate() error {
+	if p.WidthMM <= 0 || math.IsNaN(p.WidthMM) || math.IsInf(p.WidthMM, 0) {
+		return fmt.Errorf("%w: width must be positive", ErrInvalidPrintProfile)
+	}
+	if p.DPI < MinPrintDPI || p.DPI > MaxPrintDPI {
+		return fmt.Errorf("%w: dpi must be between %d and %d", ErrInvalidPrintProfile, MinPrintDPI, MaxPrintDPI)
+	}
+	if p.WidthMM/mmPerInch*float64(p.DPI) > MaxPrintPixels {
+		return fmt.Errorf("%w: output exceeds %d pixels", ErrInvalidPrintProfile, MaxPrintPixels)
+	}
+	return nil
+}
+
+// PixelSize returns the output width in pixels for the profile.
+func (p PrintProfile) PixelSize() int {
+	return int(math.Round(p.WidthMM / mmPerInch * float64(p.DPI)))
+}
+
+// applyPrintProfile validates opts.Print and rewrites size and margin from it.
+// Without a profile the screen size cap applies.
+func applyPrintProfile(opts *Options) error {
+	if opts.Print == nil {
+		if opts.Size > MaxScreenSize {
+			opts.Size = MaxScreenSize
+		}
+		return nil
+	}
+	if err := opts.Print.Validate(); err != nil {
+		return err
+	}
+	opts.Size = opts.Print.PixelSize()
+	if opts.Print.QuietZone && opts.Margin < SpecQuietZone {
+		opts.Margin = SpecQuietZone
+	}
+	return nil
+}
+
+// setPNGDensity inserts a pHYs chunk recording dpi into an encoded PNG so
+// print software renders it at the intended physical size.
+func setPNGDensity(data []byte, dpi int) []byte {
+	// 8-byte signature + IHDR chunk (4 length + 4 type + 13 data + 4 CRC)
+	const ihdrEnd = 8 + 25
+	if len(data) < ihdrEnd {
+		return data
+	}
+
+	ppm := uint32(math.Round(float64(dpi) / 0.0254))
+	chunk := make([]byte, 0, 21)
+	chunk = binary.BigEndian.AppendUint32(chunk, 9)
+	chunk = append(chunk, "pHYs"...)
+	chunk = binary.BigEndian.AppendUint32(chunk, ppm)
+	chunk = binary.BigEndian.AppendUint32(chunk, ppm)
+	chunk = append(chunk, 1) // unit: metre
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	var buf bytes.Buffer
+	buf.Grow(len(data) + len(chunk))
+	buf.Write(data[:ihdrEnd])
+	buf.Write(chunk)
+	buf.Write(data[ihdrEnd:])
+	return buf.Bytes()
+}
@@ -0,0 +1,86 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestPrintProfile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile PrintProfile
+		wantErr bool
+	}{
+		{"valid", PrintProfile{WidthMM: 50, DPI: 300}, false},
+		{"zero width", PrintProfile{WidthMM: 0, DPI: 300}, true},
+		{"dpi too low", PrintProfile{WidthMM: 50, DPI: 10}, true},
+		{"dpi too high", PrintProfile{WidthMM: 50, DPI: 4800}, true},
+		{"too many pixels", PrintProfile{WidthMM: 2000, DPI: 600}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPrintProfile) {
+				t.Errorf("expected ErrInvalidPrintProfile, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerate_PrintProfileExactSize(t *testing.T) {
+	g := NewGenerator(nil)
+	opts := DefaultOptions()
+	opts.Margin = 1
+	opts.Print = &PrintProfile{WidthMM: 25.4, DPI: 300, QuietZone: true}
+
+	data, err := g.Generate("https://example.com", opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	if got := img.Bounds().Dx(); got != 300 {
+		t.Errorf("width = %d, want 300", got)
+	}
+	if !bytes.Contains(data, []byte("pHYs")) {
+		t.Error("expected pHYs density chunk")
+	}
+}
+
+func TestGenerateSVG_PrintDimensions(t *testing.T) {
+	g := NewGenerator(nil)
+	opts := DefaultOptions()
+	opts.Print = &PrintProfile{WidthMM: 30, DPI: 300}
+
+	data, err := g.GenerateSVG("https://example.com", opts)
+	if err != nil {
+		t.Fatalf("GenerateSVG: %v", err)
+	}
+	if !strings.Contains(string(data), `width="30mm" height="30mm"`) {
+		t.Errorf("expected physical dimensions, got %s", data[:120])
+	}
+}
+
+func TestGenerateSVG_ScreenSizeCapped(t *testing.T) {
+	g := NewGenerator(nil)
+	opts := DefaultOptions()
+	opts.Size = 100000
+
+	data, err := g.GenerateSVG("https://example.com", opts)
+	if err != nil {
+		t.Fatalf("GenerateSVG: %v", err)
+	}
+	if !strings.Contains(string(data), `width="2048"`) {
+		t.Errorf("expected size capped at %d", MaxScreenSize)
+	}
+}
@@ -0,0 +1,161 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"strconv"
+)
+
+const pointsPerInch = 72.0
+
+// vectorLayout is the geometry shared by the PDF and EPS serializers. All
+// drawing happens in module units and is scaled to sizePt on output.
+type vectorLayout struct {
+	matrix       [][]bool
+	margin       int
+	totalModules int
+	sizePt       float64
+	fg, bg       color.Color
+}
+
+func newVectorLayout(url string, opts Options) (*vectorLayout, error) {
+	if opts.Size <= 0 {
+		opts.Size = 512
+	}
+	if err := applyPrintProfile(&opts); err != nil {
+		return nil, err
+	}
+
+	matrix, err := encodeQR(url, opts.ErrorCorrection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR data: %w", err)
+	}
+
+	margin := opts.Margin
+	if margin < 0 {
+		margin = 4
+	}
+
+	// Without a print profile, one pixel maps to one point (72 DPI)
+	sizePt := float64(opts.Size)
+	if opts.Print != nil {
+		sizePt = opts.Print.WidthMM / mmPerInch * pointsPerInch
+	}
+
+	return &vectorLayout{
+		matrix:       matrix,
+		margin:       margin,
+		totalModules: len(matrix) + 2*margin,
+		sizePt:       sizePt,
+		fg:           parseHexColorWithDefault(opts.ForegroundColor, color.Black),
+		bg:           parseHexColorWithDefault(opts.BackgroundColor, color.White),
+	}, nil
+}
+
+// runs calls fn for each horizontal run of dark modules, with coordinates in
+// module units and the origin at the bottom-left as PDF and PostScript expect.
+func (l *vectorLayout) runs(fn func(x, y, width int)) {
+	for row, cells := range l.matrix {
+		y := l.totalModules - (row + l.margin) - 1
+		for col := 0; col < len(cells); {
+			if !cells[col] {
+				col++
+				continue
+			}
+			start := col
+			for col < len(cells) && cells[col] {
+				col++
+			}
+			fn(start+l.margin, y, col-start)
+		}
+	}
+}
+
+// GeneratePDF creates a single-page vector PDF QR code.
+func (g *Generator) GeneratePDF(url string, opts Options) ([]byte, error) {
+	l, err := newVectorLayout(url, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	scale := l.sizePt / float64(l.totalModules)
+	fmt.Fprintf(&content, "%s 0 0 %s 0 0 cm\n", formatPt(scale), formatPt(scale))
+	fmt.Fprintf(&content, "%s rg\n0 0 %d %d re f\n", rgbOperands(l.bg), l.totalModules, l.totalModules)
+	fmt.Fprintf(&content, "%s rg\n", rgbOperands(l.fg))
+	l.runs(func(x, y, width int) {
+		fmt.Fprintf(&content, "%d %d %d 1 re\n", x, y, width)
+	})
+	content.WriteString("f\n")
+
+	size := formatPt(l.sizePt)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Contents 4 0 R /Resources << >> >>", size, size),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes(), nil
+}
+
+// GenerateEPS creates an Encapsulated PostScript QR code.
+func (g *Generator) GenerateEPS(url string, opts Options) ([]byte, error) {
+	l, err := newVectorLayout(url, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	size := formatPt(l.sizePt)
+	scale := l.sizePt / float64(l.totalModules)
+
+	var buf bytes.Buffer
+	buf.WriteString("%!PS-Adobe-3.0 EPSF-3.0\n")
+	fmt.Fprintf(&buf, "%%%%BoundingBox: 0 0 %d %d\n", ceilInt(l.sizePt), ceilInt(l.sizePt))
+	fmt.Fprintf(&buf, "%%%%HiResBoundingBox: 0 0 %s %s\n", size, size)
+	buf.WriteString("%%Creator: Linkrift\n%%Pages: 1\n%%EndComments\n")
+	buf.WriteString("gsave\n")
+	fmt.Fprintf(&buf, "%s %s scale\n", formatPt(scale), formatPt(scale))
+	fmt.Fprintf(&buf, "%s setrgbcolor\n0 0 %d %d rectfill\n", rgbOperands(l.bg), l.totalModules, l.totalModules)
+	fmt.Fprintf(&buf, "%s setrgbcolor\n", rgbOperands(l.fg))
+	l.runs(func(x, y, width int) {
+		fmt.Fprintf(&buf, "%d %d %d 1 rectfill\n", x, y, width)
+	})
+	buf.WriteString("grestore\nshowpage\n%%EOF\n")
+
+	return buf.Bytes(), nil
+}
+
+func rgbOperands(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("%s %s %s",
+		formatPt(float64(r)/0xffff), formatPt(float64(g)/0xffff), formatPt(float64(b)/0xffff))
+}
+
+func formatPt(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
+
+func ceilInt(v float64) int {
+	n := int(v)
+	if float64(n) < v {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,43 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePDF_Structure(t *testing.T) {
+	g := NewGenerator(nil)
+	opts := DefaultOptions()
+	opts.Print = &PrintProfile{WidthMM: 25.4, DPI: 300}
+
+	data, err := g.GeneratePDF("https://example.com", opts)
+	if err != nil {
+		t.Fatalf("GeneratePDF: %v", err)
+	}
+	out := string(data)
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Error("missing PDF header or trailer")
+	}
+	// 25.4 mm is one inch, i.e. 72 points
+	if !strings.Contains(out, "/MediaBox [0 0 72.0000 72.0000]") {
+		t.Error("expected a 72pt media box")
+	}
+}
+
+func TestGenerateEPS_BoundingBox(t *testing.T) {
+	g := NewGenerator(nil)
+	opts := DefaultOptions()
+	opts.Size = 200
+
+	data, err := g.GenerateEPS("https://example.com", opts)
+	if err != nil {
+		t.Fatalf("GenerateEPS: %v", err)
+	}
+	out := string(data)
+	if !strings.HasPrefix(out, "%!PS-Adobe-3.0 EPSF-3.0") {
+		t.Error("missing EPS header")
+	}
+	if !strings.Contains(out, "%%BoundingBox: 0 0 200 200") {
+		t.Error("expected 200pt bounding box")
+	}
+}
@@ -0,0 +1,132 @@
+package realtime
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Rolling click counters are kept in hourly buckets per workspace: a click
+// count and a sorted set of clicks per link. A snapshot sums the current
+// bucket and the ones before it, so the window covers the last
+// counterBuckets hours, the current one partly.
+const (
+	counterBucket    = time.Hour
+	counterBuckets   = 24
+	counterKeyPrefix = "realtime:clicks:"
+	topKeyPrefix     = "realtime:top:"
+	codesKeyPrefix   = "realtime:codes:"
+)
+
+// counterTTL keeps a bucket until it has left every window.
+const counterTTL = (counterBuckets + 1) * counterBucket
+
+func bucketStart(t time.Time) time.Time {
+	return t.UTC().Truncate(counterBucket)
+}
+
+func counterKey(prefix string, workspaceID uuid.UUID, bucket time.Time) string {
+	return prefix + workspaceID.String() + ":" + strconv.FormatInt(bucket.Unix(), 10)
+}
+
+// windowKeys returns the keys of the buckets in the window ending at now,
+// newest first.
+func windowKeys(prefix string, workspaceID uuid.UUID, now time.Time) []string {
+	start := bucketStart(now)
+	keys := make([]string, counterBuckets)
+	for i := range keys {
+		keys[i] = counterKey(prefix, workspaceID, start.Add(-time.Duration(i)*counterBucket))
+	}
+	return keys
+}
+
+// RecordClick adds clicks, more than one for a sampled click, to the
+// workspace's rolling counters.
+func RecordClick(ctx context.Context, rdb *redis.Client, n *models.ClickNotification, clicks int64) error {
+	bucket := bucketStart(n.Timestamp)
+	countKey := counterKey(counterKeyPrefix, n.WorkspaceID, bucket)
+	topKey := counterKey(topKeyPrefix, n.WorkspaceID, bucket)
+	codesKey := codesKeyPrefix + n.WorkspaceID.String()
+
+	pipe := rdb.Pipeline()
+	pipe.IncrBy(ctx, countKey, clicks)
+	pipe.Expire(ctx, countKey, counterTTL)
+	pipe.ZIncrBy(ctx, topKey, float64(clicks), n.LinkID.String())
+	pipe.Expire(ctx, topKey, counterTTL)
+	if n.ShortCode != "" {
+		pipe.HSet(ctx, codesKey, n.LinkID.String(), n.ShortCode)
+		pipe.Expire(ctx, codesKey, counterTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Snapshot reads the workspace's rolling counters for the window ending at
+// now.
+func Snapshot(ctx context.Context, rdb *redis.Client, workspaceID uuid.UUID, now time.Time) (*models.RealtimeSnapshot, error) {
+	counts, err := rdb.MGet(ctx, windowKeys(counterKeyPrefix, workspaceID, now)...).Result()
+	if err != nil {
+		return nil, err
+	}
+	top, err := rdb.ZUnionWithScores(ctx, redis.ZStore{
+		Keys:      windowKeys(topKeyPrefix, workspaceID, now),
+		Aggregate: "SUM",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.RealtimeSnapshot{
+		WorkspaceID: workspaceID,
+		WindowStart: bucketStart(now).Add(-(counterBuckets - 1) * counterBucket),
+		Clicks:      sumCounts(counts),
+		Timestamp:   now,
+	}
+	if topLink := topMember(top); topLink != nil {
+		code, err := rdb.HGet(ctx, codesKeyPrefix+workspaceID.String(), topLink.LinkID.String()).Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		topLink.ShortCode = code
+		snapshot.TopLink = topLink
+	}
+	return snapshot, nil
+}
+
+// sumCounts adds up MGET results, skipping buckets with no clicks.
+func sumCounts(counts []any) int64 {
+	var total int64
+	for _, v := range counts {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			total += n
+		}
+	}
+	return total
+}
+
+// topMember returns the highest scoring link of a ZUNION result.
+func topMember(members []redis.Z) *models.RealtimeTopLink {
+	var top *models.RealtimeTopLink
+	for _, m := range members {
+		member, ok := m.Member.(string)
+		if !ok {
+			continue
+		}
+		linkID, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		if top == nil || int64(m.Score) > top.Clicks {
+			top = &models.RealtimeTopLink{LinkID: linkID, Clicks: int64(m.Score)}
+		}
+	}
+	return top
+}
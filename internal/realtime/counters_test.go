@@ -0,0 +1,77 @@
+package realtime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestWindowKeys(t *testing.T) {
+	ws := uuid.New()
+	now := time.Date(2026, 10, 16, 14, 35, 0, 0, time.UTC)
+
+	keys := windowKeys(counterKeyPrefix, ws, now)
+	if len(keys) != counterBuckets {
+		t.Fatalf("got %d keys, want %d", len(keys), counterBuckets)
+	}
+	newest := counterKeyPrefix + ws.String() + ":" + "1792159200" // 14:00
+	if keys[0] != newest {
+		t.Errorf("newest key = %q, want %q", keys[0], newest)
+	}
+	oldest := counterKeyPrefix + ws.String() + ":" + "1792076400" // 15:00 the day before
+	if keys[len(keys)-1] != oldest {
+		t.Errorf("oldest key = %q, want %q", keys[len(keys)-1], oldest)
+	}
+
+	// Clicks are recorded in the bucket the snapshot reads first.
+	if got := counterKey(counterKeyPrefix, ws, bucketStart(now.In(time.FixedZone("JST", 9*3600)))); got != newest {
+		t.Errorf("bucket for a zoned time = %q, want %q", got, newest)
+	}
+}
+
+func TestSumCounts(t *testing.T) {
+	if got := sumCounts([]any{"3", nil, "4", "junk"}); got != 7 {
+		t.Errorf("sumCounts() = %d, want 7", got)
+	}
+}
+
+func TestTopMember(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	top := topMember([]redis.Z{
+		{Member: a.String(), Score: 3},
+		{Member: "not-a-link", Score: 99},
+		{Member: b.String(), Score: 8},
+	})
+	if top == nil || top.LinkID != b || top.Clicks != 8 {
+		t.Errorf("topMember() = %+v, want %s with 8 clicks", top, b)
+	}
+	if topMember(nil) != nil {
+		t.Error("topMember(nil) should be nil")
+	}
+}
+
+func TestHub_BroadcastSnapshot(t *testing.T) {
+	hub := NewHub(zap.NewNop())
+	ws := uuid.New()
+	client := &Client{hub: hub, WorkspaceID: ws, subscribedLinks: map[uuid.UUID]bool{}, send: make(chan []byte, 1)}
+	hub.addClient(client)
+
+	if ids := hub.Workspaces(); len(ids) != 1 || ids[0] != ws {
+		t.Fatalf("Workspaces() = %v, want [%s]", ids, ws)
+	}
+
+	hub.BroadcastSnapshot(&models.RealtimeSnapshot{WorkspaceID: ws, Clicks: 5})
+	select {
+	case msg := <-client.send:
+		if !strings.Contains(string(msg), `"type":"snapshot"`) {
+			t.Errorf("message = %s", msg)
+		}
+	default:
+		t.Fatal("client got no snapshot")
+	}
+}
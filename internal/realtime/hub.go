@@ -12,10 +12,11 @@ import (
 )
 
 const (
-	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingInterval   = 30 * time.Second
-	maxMessageSize = 512
+	writeWait = 10 * time.Second
+	// DefaultIdleTimeout is how long a client may go without answering a
+	// ping before its connection is closed, unless SetIdleTimeout changes it.
+	DefaultIdleTimeout = 60 * time.Second
+	maxMessageSize     = 512
 )
 
 // Hub manages WebSocket client connections and broadcasts.
@@ -25,6 +26,7 @@ type Hub struct {
 	linkClients       map[uuid.UUID]map[*Client]bool
 	register          chan *Client
 	unregister        chan *Client
+	idleTimeout       time.Duration
 	logger            *zap.Logger
 }
 
@@ -34,10 +36,19 @@ func NewHub(logger *zap.Logger) *Hub {
 		linkClients:      make(map[uuid.UUID]map[*Client]bool),
 		register:         make(chan *Client, 64),
 		unregister:       make(chan *Client, 64),
+		idleTimeout:      DefaultIdleTimeout,
 		logger:           logger,
 	}
 }
 
+// SetIdleTimeout changes how long clients may go without answering a ping.
+// Clients are pinged at half this interval. Call before serving clients.
+func (h *Hub) SetIdleTimeout(d time.Duration) {
+	if d > 0 {
+		h.idleTimeout = d
+	}
+}
+
 // Run starts the hub event loop. Call in a goroutine.
 func (h *Hub) Run() {
 	for {
@@ -166,6 +177,66 @@ func (h *Hub) BroadcastToLink(linkID uuid.UUID, notification *models.ClickNotifi
 	}
 }
 
+// BroadcastComment sends a new link comment to all clients in the comment's
+// workspace.
+func (h *Hub) BroadcastComment(comment *models.LinkComment) {
+	data, err := json.Marshal(map[string]any{
+		"type": "comment",
+		"data": comment,
+	})
+	if err != nil {
+		h.logger.Warn("failed to marshal comment", zap.Error(err))
+		return
+	}
+
+	h.mu.RLock()
+	clients := h.workspaceClients[comment.WorkspaceID]
+	h.mu.RUnlock()
+
+	for c := range clients {
+		select {
+		case c.send <- data:
+		default:
+		}
+	}
+}
+
+// BroadcastSnapshot sends a workspace's headline numbers to all clients in
+// the workspace.
+func (h *Hub) BroadcastSnapshot(snapshot *models.RealtimeSnapshot) {
+	data, err := json.Marshal(map[string]any{
+		"type": "snapshot",
+		"data": snapshot,
+	})
+	if err != nil {
+		h.logger.Warn("failed to marshal snapshot", zap.Error(err))
+		return
+	}
+
+	h.mu.RLock()
+	clients := h.workspaceClients[snapshot.WorkspaceID]
+	h.mu.RUnlock()
+
+	for c := range clients {
+		select {
+		case c.send <- data:
+		default:
+		}
+	}
+}
+
+// Workspaces returns the workspaces with at least one connected client.
+func (h *Hub) Workspaces() []uuid.UUID {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ids := make([]uuid.UUID, 0, len(h.workspaceClients))
+	for id := range h.workspaceClients {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 // Client represents a single WebSocket connection.
 type Client struct {
 	hub             *Hub
@@ -193,10 +264,13 @@ func (c *Client) ReadPump() {
 		c.conn.Close()
 	}()
 
+	// A client that stops answering pings hits the read deadline, which
+	// ends the loop and reaps the connection.
+	idleTimeout := c.hub.idleTimeout
 	c.conn.SetReadLimit(maxMessageSize)
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetReadDeadline(time.Now().Add(idleTimeout))
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.conn.SetReadDeadline(time.Now().Add(idleTimeout))
 		return nil
 	})
 
@@ -230,7 +304,7 @@ func (c *Client) ReadPump() {
 
 // WritePump sends messages from the send channel to the WebSocket connection.
 func (c *Client) WritePump() {
-	ticker := time.NewTicker(pingInterval)
+	ticker := time.NewTicker(c.hub.idleTimeout / 2)
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -0,0 +1,99 @@
+package realtime
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Reasons a connection is refused by a ConnLimiter.
+var (
+	ErrServerFull     = errors.New("server is at its connection limit")
+	ErrTooManyForIP   = errors.New("too many connections from this address")
+	ErrTooManyForUser = errors.New("too many connections for this user")
+)
+
+// ConnLimits caps concurrent WebSocket connections. Zero means no cap.
+type ConnLimits struct {
+	Max     int
+	PerIP   int
+	PerUser int
+}
+
+// ConnLimiter counts open WebSocket connections so new ones can be refused
+// before they are registered with the hub.
+type ConnLimiter struct {
+	limits ConnLimits
+
+	mu     sync.Mutex
+	total  int
+	byIP   map[string]int
+	byUser map[uuid.UUID]int
+}
+
+func NewConnLimiter(limits ConnLimits) *ConnLimiter {
+	return &ConnLimiter{
+		limits: limits,
+		byIP:   make(map[string]int),
+		byUser: make(map[uuid.UUID]int),
+	}
+}
+
+// Acquire takes a connection slot for ip and userID, or returns
+// ErrServerFull, ErrTooManyForIP or ErrTooManyForUser. Every successful
+// Acquire must be paired with a Release.
+func (l *ConnLimiter) Acquire(ip string, userID uuid.UUID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limits.Max > 0 && l.total >= l.limits.Max {
+		return ErrServerFull
+	}
+	if l.limits.PerIP > 0 && l.byIP[ip] >= l.limits.PerIP {
+		return ErrTooManyForIP
+	}
+	if l.limits.PerUser > 0 && l.byUser[userID] >= l.limits.PerUser {
+		return ErrTooManyForUser
+	}
+
+	l.total++
+	l.byIP[ip]++
+	l.byUser[userID]++
+	return nil
+}
+
+// Release frees a slot taken by Acquire.
+func (l *ConnLimiter) Release(ip string, userID uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.byIP[ip] == 0 || l.byUser[userID] == 0 {
+		return
+	}
+	l.total--
+	if l.byIP[ip]--; l.byIP[ip] == 0 {
+		delete(l.byIP, ip)
+	}
+	if l.byUser[userID]--; l.byUser[userID] == 0 {
+		delete(l.byUser, userID)
+	}
+}
+
+// Count returns the number of open connections.
+func (l *ConnLimiter) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total
+}
+
+// CloseCode is the WebSocket close code a connection refused with err is
+// closed with: 1013 (try again later) when the server is full, since that
+// clears as others disconnect, and 1008 (policy violation) otherwise.
+func CloseCode(err error) int {
+	if errors.Is(err, ErrServerFull) {
+		return websocket.CloseTryAgainLater
+	}
+	return websocket.ClosePolicyViolation
+}
@@ -0,0 +1,66 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+func TestConnLimiter(t *testing.T) {
+	l := NewConnLimiter(ConnLimits{Max: 3, PerIP: 2, PerUser: 2})
+	alice, bob := uuid.New(), uuid.New()
+
+	if err := l.Acquire("10.0.0.1", alice); err != nil {
+		t.Fatalf("first connection: %v", err)
+	}
+	if err := l.Acquire("10.0.0.1", alice); err != nil {
+		t.Fatalf("second connection: %v", err)
+	}
+	if err := l.Acquire("10.0.0.2", alice); !errors.Is(err, ErrTooManyForUser) {
+		t.Errorf("third connection for user: err = %v, want ErrTooManyForUser", err)
+	}
+	if err := l.Acquire("10.0.0.1", bob); !errors.Is(err, ErrTooManyForIP) {
+		t.Errorf("third connection from IP: err = %v, want ErrTooManyForIP", err)
+	}
+	if err := l.Acquire("10.0.0.2", bob); err != nil {
+		t.Fatalf("other user and IP: %v", err)
+	}
+	if err := l.Acquire("10.0.0.3", uuid.New()); !errors.Is(err, ErrServerFull) {
+		t.Errorf("over the global cap: err = %v, want ErrServerFull", err)
+	}
+
+	l.Release("10.0.0.1", alice)
+	if got := l.Count(); got != 2 {
+		t.Errorf("Count() = %d after release, want 2", got)
+	}
+	if err := l.Acquire("10.0.0.1", bob); err != nil {
+		t.Errorf("after release: %v", err)
+	}
+
+	// Releasing a slot that was never taken doesn't free anyone else's.
+	l.Release("10.0.0.9", uuid.New())
+	if got := l.Count(); got != 3 {
+		t.Errorf("Count() = %d after unmatched release, want 3", got)
+	}
+}
+
+func TestConnLimiter_Unlimited(t *testing.T) {
+	l := NewConnLimiter(ConnLimits{})
+	user := uuid.New()
+	for i := 0; i < 100; i++ {
+		if err := l.Acquire("10.0.0.1", user); err != nil {
+			t.Fatalf("connection %d: %v", i, err)
+		}
+	}
+}
+
+func TestCloseCode(t *testing.T) {
+	if got := CloseCode(ErrServerFull); got != websocket.CloseTryAgainLater {
+		t.Errorf("CloseCode(ErrServerFull) = %d", got)
+	}
+	if got := CloseCode(ErrTooManyForIP); got != websocket.ClosePolicyViolation {
+		t.Errorf("CloseCode(ErrTooManyForIP) = %d", got)
+	}
+}
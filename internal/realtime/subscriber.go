@@ -3,24 +3,34 @@ package realtime
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
-const realtimeChannel = "clicks:realtime"
+const (
+	realtimeChannel = "clicks:realtime"
+	commentChannel  = "comments:realtime"
+)
 
-// StartRedisSubscriber subscribes to the Redis Pub/Sub channel for real-time click
-// notifications and broadcasts them to the WebSocket hub.
-func StartRedisSubscriber(ctx context.Context, redisClient *redis.Client, hub *Hub, logger *zap.Logger) {
-	pubsub := redisClient.Subscribe(ctx, realtimeChannel)
+// StartRedisSubscriber subscribes to the Redis Pub/Sub channels for real-time click
+// notifications and link comments and broadcasts them to the WebSocket hub.
+// Every snapshotInterval it also sends each connected workspace a snapshot
+// of its rolling click counters; zero turns snapshots off.
+func StartRedisSubscriber(ctx context.Context, redisClient *redis.Client, hub *Hub, snapshotInterval time.Duration, logger *zap.Logger) {
+	pubsub := redisClient.Subscribe(ctx, realtimeChannel, commentChannel)
 	ch := pubsub.Channel()
 
+	if snapshotInterval > 0 {
+		go broadcastSnapshots(ctx, redisClient, hub, snapshotInterval, logger)
+	}
+
 	go func() {
 		defer pubsub.Close()
 
-		logger.Info("realtime Redis subscriber started", zap.String("channel", realtimeChannel))
+		logger.Info("realtime Redis subscriber started", zap.Strings("channels", []string{realtimeChannel, commentChannel}))
 
 		for {
 			select {
@@ -32,6 +42,16 @@ func StartRedisSubscriber(ctx context.Context, redisClient *redis.Client, hub *H
 					return
 				}
 
+				if msg.Channel == commentChannel {
+					var comment models.LinkComment
+					if err := json.Unmarshal([]byte(msg.Payload), &comment); err != nil {
+						logger.Warn("failed to unmarshal link comment", zap.Error(err))
+						continue
+					}
+					hub.BroadcastComment(&comment)
+					continue
+				}
+
 				var notification models.ClickNotification
 				if err := json.Unmarshal([]byte(msg.Payload), &notification); err != nil {
 					logger.Warn("failed to unmarshal click notification", zap.Error(err))
@@ -44,3 +64,29 @@ func StartRedisSubscriber(ctx context.Context, redisClient *redis.Client, hub *H
 		}
 	}()
 }
+
+// broadcastSnapshots sends each workspace with connected clients a snapshot
+// of its rolling counters every interval until ctx is done.
+func broadcastSnapshots(ctx context.Context, redisClient *redis.Client, hub *Hub, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, workspaceID := range hub.Workspaces() {
+				snapshot, err := Snapshot(ctx, redisClient, workspaceID, now)
+				if err != nil {
+					logger.Warn("failed to read realtime counters",
+						zap.String("workspace_id", workspaceID.String()),
+						zap.Error(err),
+					)
+					continue
+				}
+				hub.BroadcastSnapshot(snapshot)
+			}
+		}
+	}
+}
@@ -3,11 +3,15 @@ package redirect
 import (
 	"regexp"
 	"strings"
+	"sync/atomic"
 )
 
 // BotDetector identifies bot/crawler traffic from User-Agent strings.
 type BotDetector struct {
 	patterns []*regexp.Regexp
+	// allowlist holds lowercased User-Agent substrings exempt from the
+	// patterns.
+	allowlist atomic.Pointer[[]string]
 }
 
 func NewBotDetector() *BotDetector {
@@ -74,13 +78,34 @@ func NewBotDetector() *BotDetector {
 	return &BotDetector{patterns: patterns}
 }
 
-// IsBot returns true if the User-Agent string matches a known bot pattern.
+// SetAllowlist replaces the User-Agent substrings that are never treated as
+// bots. Matching is case-insensitive; empty entries are ignored.
+func (d *BotDetector) SetAllowlist(entries []string) {
+	allow := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			allow = append(allow, e)
+		}
+	}
+	d.allowlist.Store(&allow)
+}
+
+// IsBot returns true if the User-Agent string matches a known bot pattern
+// and isn't allowlisted.
 func (d *BotDetector) IsBot(userAgent string) bool {
 	if userAgent == "" {
 		return true // No UA is likely a bot
 	}
 
 	ua := strings.TrimSpace(userAgent)
+	if allow := d.allowlist.Load(); allow != nil {
+		lower := strings.ToLower(ua)
+		for _, a := range *allow {
+			if strings.Contains(lower, a) {
+				return false
+			}
+		}
+	}
 	for _, p := range d.patterns {
 		if p.MatchString(ua) {
 			return true
@@ -63,6 +63,26 @@ func TestBotDetector_HumanBrowsers(t *testing.T) {
 	}
 }
 
+func TestBotDetectorAllowlist(t *testing.T) {
+	d := NewBotDetector()
+	d.SetAllowlist([]string{" UptimeRobot ", ""})
+
+	if d.IsBot("Mozilla/5.0+(compatible; UptimeRobot/2.0; http://www.uptimerobot.com/)") {
+		t.Error("allowlisted UA should not be a bot")
+	}
+	if !d.IsBot("Googlebot/2.1 (+http://www.google.com/bot.html)") {
+		t.Error("other bots should still be detected")
+	}
+	if !d.IsBot("") {
+		t.Error("empty UA should still be a bot")
+	}
+
+	d.SetAllowlist(nil)
+	if !d.IsBot("Mozilla/5.0+(compatible; UptimeRobot/2.0; http://www.uptimerobot.com/)") {
+		t.Error("cleared allowlist should detect the bot again")
+	}
+}
+
 // --- Benchmarks ---
 
 func BenchmarkBotDetectorIsBot_Human(b *testing.B) {
@@ -4,27 +4,47 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 const redisKeyPrefix = "link:resolve:"
 
-// CachedLink holds the minimal fields needed for redirect resolution.
+// CachedLink holds the minimal fields needed for redirect resolution. Fields
+// meant only for workspace members, like the internal note, stay out of it.
 type CachedLink struct {
-	ID             uuid.UUID `json:"id"`
-	WorkspaceID    uuid.UUID `json:"workspace_id"`
-	ShortCode      string    `json:"short_code"`
-	DestinationURL string    `json:"destination_url"`
-	IsActive       bool      `json:"is_active"`
-	HasPassword    bool      `json:"has_password"`
-	PasswordHash   string    `json:"password_hash,omitempty"`
-	ExpiresAt      *int64    `json:"expires_at,omitempty"` // unix timestamp
-	MaxClicks      *int32    `json:"max_clicks,omitempty"`
-	TotalClicks    int64     `json:"total_clicks"`
+	ID              uuid.UUID `json:"id"`
+	WorkspaceID     uuid.UUID `json:"workspace_id"`
+	ShortCode       string    `json:"short_code"`
+	DestinationURL  string    `json:"destination_url"`
+	IsActive        bool      `json:"is_active"`
+	HasPassword     bool      `json:"has_password"`
+	PasswordHash    string    `json:"password_hash,omitempty"`
+	PasswordHint    string    `json:"password_hint,omitempty"`
+	ExpiresAt       *int64    `json:"expires_at,omitempty"` // unix timestamp
+	MaxClicks       *int32    `json:"max_clicks,omitempty"`
+	TotalClicks     int64     `json:"total_clicks"`
+	Title           string    `json:"title,omitempty"`
+	Cloak           bool      `json:"cloak,omitempty"`
+	ForwardParams   bool      `json:"forward_params,omitempty"`
+	ParamPrecedence string    `json:"param_precedence,omitempty"`
+	ForceHTTPS      bool      `json:"force_https,omitempty"`
+	OncePerVisitor  bool      `json:"once_per_visitor,omitempty"`
+	RepeatVisitURL  string    `json:"repeat_visit_url,omitempty"`
+	IOSURL          string    `json:"ios_url,omitempty"`
+	AndroidURL      string    `json:"android_url,omitempty"`
+	FallbackURL     string    `json:"fallback_url,omitempty"`
+	FacebookPixelID string    `json:"facebook_pixel_id,omitempty"`
+	GoogleTagID     string    `json:"google_tag_id,omitempty"`
+	Interstitial    bool      `json:"interstitial,omitempty"`
+
+	Schedule     *models.LinkSchedule `json:"schedule,omitempty"`
+	FailoverURLs []string             `json:"failover_urls,omitempty"`
 }
 
 type l1Entry struct {
@@ -35,21 +55,29 @@ type l1Entry struct {
 // Cache provides a multi-layer caching strategy for link resolution.
 // L1: in-memory sync.Map with TTL entries.
 // L2: Redis with configurable TTL.
+// The TTLs are durations stored atomically so a config reload can change them.
 type Cache struct {
-	l1        sync.Map
-	l1TTL     time.Duration
-	redis     *redis.Client
-	redisTTL  time.Duration
-	logger    *zap.Logger
+	l1       sync.Map
+	l1TTL    atomic.Int64
+	redis    *redis.Client
+	redisTTL atomic.Int64
+	logger   *zap.Logger
 }
 
 func NewCache(redisClient *redis.Client, l1TTL, redisTTL time.Duration, logger *zap.Logger) *Cache {
-	return &Cache{
-		l1TTL:    l1TTL,
-		redis:    redisClient,
-		redisTTL: redisTTL,
-		logger:   logger,
+	c := &Cache{
+		redis:  redisClient,
+		logger: logger,
 	}
+	c.SetTTLs(l1TTL, redisTTL)
+	return c
+}
+
+// SetTTLs changes the L1 and Redis TTLs. Entries already cached keep the
+// expiry they were stored with.
+func (c *Cache) SetTTLs(l1TTL, redisTTL time.Duration) {
+	c.l1TTL.Store(int64(l1TTL))
+	c.redisTTL.Store(int64(redisTTL))
 }
 
 // GetL1 checks the local in-memory cache.
@@ -70,7 +98,7 @@ func (c *Cache) GetL1(shortCode string) (*CachedLink, bool) {
 func (c *Cache) SetL1(shortCode string, link *CachedLink) {
 	c.l1.Store(shortCode, &l1Entry{
 		link:      link,
-		expiresAt: time.Now().Add(c.l1TTL),
+		expiresAt: time.Now().Add(time.Duration(c.l1TTL.Load())),
 	})
 }
 
@@ -104,7 +132,7 @@ func (c *Cache) SetL2(ctx context.Context, shortCode string, link *CachedLink) {
 		return
 	}
 
-	if err := c.redis.Set(ctx, redisKeyPrefix+shortCode, data, c.redisTTL).Err(); err != nil {
+	if err := c.redis.Set(ctx, redisKeyPrefix+shortCode, data, time.Duration(c.redisTTL.Load())).Err(); err != nil {
 		c.logger.Warn("failed to set redis cache", zap.Error(err), zap.String("short_code", shortCode))
 	}
 }
@@ -16,8 +16,15 @@ func makeCachedLink(shortCode string) *CachedLink {
 	}
 }
 
+// newL1Cache returns a cache with only the in-memory layer.
+func newL1Cache(ttl time.Duration) *Cache {
+	c := &Cache{}
+	c.l1TTL.Store(int64(ttl))
+	return c
+}
+
 func TestL1Cache_SetGet(t *testing.T) {
-	c := &Cache{l1TTL: 5 * time.Minute}
+	c := newL1Cache(5 * time.Minute)
 
 	link := makeCachedLink("abc123")
 	c.SetL1("abc123", link)
@@ -32,7 +39,7 @@ func TestL1Cache_SetGet(t *testing.T) {
 }
 
 func TestL1Cache_Miss(t *testing.T) {
-	c := &Cache{l1TTL: 5 * time.Minute}
+	c := newL1Cache(5 * time.Minute)
 
 	_, ok := c.GetL1("missing")
 	if ok {
@@ -41,7 +48,7 @@ func TestL1Cache_Miss(t *testing.T) {
 }
 
 func TestL1Cache_Expiration(t *testing.T) {
-	c := &Cache{l1TTL: 1 * time.Millisecond}
+	c := newL1Cache(1 * time.Millisecond)
 
 	link := makeCachedLink("expire")
 	c.SetL1("expire", link)
@@ -54,8 +61,24 @@ func TestL1Cache_Expiration(t *testing.T) {
 	}
 }
 
+func TestL1Cache_SetTTLs(t *testing.T) {
+	c := newL1Cache(5 * time.Minute)
+	c.SetL1("before", makeCachedLink("before"))
+
+	c.SetTTLs(time.Millisecond, time.Hour)
+	c.SetL1("after", makeCachedLink("after"))
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.GetL1("before"); !ok {
+		t.Error("entry cached before the change should keep its expiry")
+	}
+	if _, ok := c.GetL1("after"); ok {
+		t.Error("entry cached after the change should use the new TTL")
+	}
+}
+
 func TestL1Cache_Invalidate(t *testing.T) {
-	c := &Cache{l1TTL: 5 * time.Minute}
+	c := newL1Cache(5 * time.Minute)
 
 	link := makeCachedLink("del")
 	c.SetL1("del", link)
@@ -69,7 +92,7 @@ func TestL1Cache_Invalidate(t *testing.T) {
 }
 
 func TestL1Cache_Overwrite(t *testing.T) {
-	c := &Cache{l1TTL: 5 * time.Minute}
+	c := newL1Cache(5 * time.Minute)
 
 	link1 := makeCachedLink("overwrite")
 	c.SetL1("overwrite", link1)
@@ -94,7 +117,7 @@ func TestL1Cache_Overwrite(t *testing.T) {
 // --- Benchmarks ---
 
 func BenchmarkCacheGetL1_Hit(b *testing.B) {
-	c := &Cache{l1TTL: 5 * time.Minute}
+	c := newL1Cache(5 * time.Minute)
 	link := makeCachedLink("bench")
 	c.SetL1("bench", link)
 
@@ -105,7 +128,7 @@ func BenchmarkCacheGetL1_Hit(b *testing.B) {
 }
 
 func BenchmarkCacheGetL1_Miss(b *testing.B) {
-	c := &Cache{l1TTL: 5 * time.Minute}
+	c := newL1Cache(5 * time.Minute)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -114,7 +137,7 @@ func BenchmarkCacheGetL1_Miss(b *testing.B) {
 }
 
 func BenchmarkCacheSetL1(b *testing.B) {
-	c := &Cache{l1TTL: 5 * time.Minute}
+	c := newL1Cache(5 * time.Minute)
 	link := makeCachedLink("bench-set")
 
 	b.ResetTimer()
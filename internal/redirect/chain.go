@@ -0,0 +1,83 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// Chain is where a destination ends up and the redirects on the way there.
+// Error is set when the destination couldn't be followed to the end; the
+// redirects seen before that are still reported.
+type Chain struct {
+	FinalURL  string                   `json:"final_url,omitempty"`
+	Redirects []models.LinkRedirectHop `json:"redirects"`
+	Warning   string                   `json:"warning,omitempty"`
+	Error     string                   `json:"error,omitempty"`
+}
+
+type chainEntry struct {
+	chain     *Chain
+	expiresAt time.Time
+}
+
+// ChainTracer follows a destination's redirects for link previews. Results
+// are cached per destination, so previews can't be used to make the redirect
+// service fetch the same site over and over.
+type ChainTracer struct {
+	prober  DestinationProber
+	ttl     atomic.Int64 // time.Duration
+	results sync.Map
+	logger  *zap.Logger
+}
+
+func NewChainTracer(prober DestinationProber, ttl time.Duration, logger *zap.Logger) *ChainTracer {
+	t := &ChainTracer{prober: prober, logger: logger}
+	t.SetTTL(ttl)
+	return t
+}
+
+// SetTTL changes how long traced chains are reused. Chains already stored
+// keep their expiry.
+func (t *ChainTracer) SetTTL(ttl time.Duration) {
+	t.ttl.Store(int64(ttl))
+}
+
+// Trace returns the redirect chain of destination. Failures are reported in
+// the chain rather than returned, without details about the network.
+func (t *ChainTracer) Trace(ctx context.Context, destination string) *Chain {
+	if val, ok := t.results.Load(destination); ok {
+		entry := val.(*chainEntry)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.chain
+		}
+		t.results.Delete(destination)
+	}
+
+	chain := &Chain{Redirects: []models.LinkRedirectHop{}}
+	res, err := t.prober.Check(ctx, destination)
+	if res != nil {
+		for _, hop := range res.Redirects {
+			chain.Redirects = append(chain.Redirects, models.LinkRedirectHop{URL: hop.URL, StatusCode: hop.StatusCode})
+		}
+		chain.Warning = models.RedirectChainWarning(len(chain.Redirects))
+	}
+	switch {
+	case errors.Is(err, httputil.ErrTooManyRedirects):
+		chain.Error = "too many redirects"
+	case err != nil:
+		t.logger.Debug("redirect chain trace failed", zap.String("destination", destination), zap.Error(err))
+		chain.Error = "destination could not be reached"
+	default:
+		chain.FinalURL = res.FinalURL
+	}
+
+	t.results.Store(destination, &chainEntry{chain: chain, expiresAt: time.Now().Add(time.Duration(t.ttl.Load()))})
+	return chain
+}
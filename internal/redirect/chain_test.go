@@ -0,0 +1,81 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type chainProber struct {
+	result *httputil.CheckResult
+	err    error
+	calls  int
+}
+
+func (p *chainProber) Check(_ context.Context, _ string) (*httputil.CheckResult, error) {
+	p.calls++
+	return p.result, p.err
+}
+
+func TestChainTracer_Trace(t *testing.T) {
+	prober := &chainProber{result: &httputil.CheckResult{
+		StatusCode: 200,
+		FinalURL:   "https://example.com/landing",
+		Redirects: []httputil.RedirectHop{
+			{URL: "http://example.com/a", StatusCode: 301},
+			{URL: "https://example.com/a", StatusCode: 302},
+			{URL: "https://example.com/b", StatusCode: 302},
+		},
+	}}
+	tracer := NewChainTracer(prober, time.Minute, zap.NewNop())
+
+	chain := tracer.Trace(context.Background(), "http://example.com/a")
+	if chain.FinalURL != "https://example.com/landing" {
+		t.Errorf("FinalURL = %q, want the landing page", chain.FinalURL)
+	}
+	if len(chain.Redirects) != 3 || chain.Redirects[1].StatusCode != 302 {
+		t.Errorf("Redirects = %+v, want the 3 hops", chain.Redirects)
+	}
+	if chain.Warning == "" {
+		t.Error("expected a warning for a long redirect chain")
+	}
+
+	tracer.Trace(context.Background(), "http://example.com/a")
+	if prober.calls != 1 {
+		t.Errorf("expected the chain to be cached, got %d probes", prober.calls)
+	}
+}
+
+func TestChainTracer_TooManyRedirects(t *testing.T) {
+	prober := &chainProber{
+		result: &httputil.CheckResult{Redirects: []httputil.RedirectHop{{URL: "https://loop.example/", StatusCode: 302}}},
+		err:    httputil.ErrTooManyRedirects,
+	}
+	chain := NewChainTracer(prober, time.Minute, zap.NewNop()).Trace(context.Background(), "https://loop.example/")
+
+	if chain.Error != "too many redirects" {
+		t.Errorf("Error = %q, want too many redirects", chain.Error)
+	}
+	if len(chain.Redirects) != 1 {
+		t.Errorf("expected the partial chain, got %+v", chain.Redirects)
+	}
+	if chain.FinalURL != "" {
+		t.Errorf("FinalURL = %q, want none", chain.FinalURL)
+	}
+}
+
+func TestChainTracer_HidesNetworkErrors(t *testing.T) {
+	prober := &chainProber{err: errors.New("dial tcp 10.0.0.1:80: connection refused")}
+	chain := NewChainTracer(prober, time.Minute, zap.NewNop()).Trace(context.Background(), "https://down.example/")
+
+	if chain.Error != "destination could not be reached" {
+		t.Errorf("Error = %q, want a generic message", chain.Error)
+	}
+	if chain.Redirects == nil {
+		t.Error("expected an empty redirect list, not nil")
+	}
+}
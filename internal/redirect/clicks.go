@@ -0,0 +1,64 @@
+package redirect
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+// ClickSink queues clicks for the worker. *ClickTracker implements it.
+type ClickSink interface {
+	Track(event *models.ClickEvent)
+	Skip(linkID uuid.UUID)
+}
+
+// ClickRecorder counts visits to short links as clicks. HEAD requests, bots
+// and visitors who opted out of tracking are never counted. For workspaces
+// that sample clicks, a visit left out by sampling only adds to the link's
+// total.
+type ClickRecorder struct {
+	sink    ClickSink
+	sampler *ClickSampler
+	bots    *BotDetector
+	optOut  *OptOutPolicy
+}
+
+func NewClickRecorder(sink ClickSink, sampler *ClickSampler, bots *BotDetector, optOut *OptOutPolicy) *ClickRecorder {
+	return &ClickRecorder{
+		sink:    sink,
+		sampler: sampler,
+		bots:    bots,
+		optOut:  optOut,
+	}
+}
+
+// Record counts r's visit to result's link, from the visitor at clientIP,
+// and reports whether it was counted.
+func (cr *ClickRecorder) Record(r *http.Request, result *ResolveResult, clientIP string) bool {
+	if r.Method == http.MethodHead || cr.bots.IsBot(r.UserAgent()) || cr.optOut.OptedOut(r) {
+		return false
+	}
+
+	rate, record := cr.sampler.Sample(r.Context(), result.WorkspaceID)
+	if !record {
+		cr.sink.Skip(result.LinkID)
+		return true
+	}
+	event := &models.ClickEvent{
+		LinkID:      result.LinkID,
+		WorkspaceID: result.WorkspaceID,
+		ShortCode:   result.ShortCode,
+		IP:          clientIP,
+		UserAgent:   r.UserAgent(),
+		Referer:     r.Referer(),
+		Timestamp:   time.Now(),
+		Source:      ClickSource(r),
+	}
+	if rate > 1 {
+		event.SampleRate = rate
+	}
+	cr.sink.Track(event)
+	return true
+}
@@ -0,0 +1,101 @@
+package redirect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+type recordingSink struct {
+	tracked []*models.ClickEvent
+	skipped []uuid.UUID
+}
+
+func (s *recordingSink) Track(event *models.ClickEvent) { s.tracked = append(s.tracked, event) }
+func (s *recordingSink) Skip(linkID uuid.UUID)          { s.skipped = append(s.skipped, linkID) }
+
+func TestClickRecorder(t *testing.T) {
+	result := &ResolveResult{
+		LinkID:         uuid.New(),
+		WorkspaceID:    uuid.New(),
+		ShortCode:      "abc123",
+		DestinationURL: "https://example.com",
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		honor   bool
+		prepare func(r *http.Request)
+		tracked bool
+	}{
+		{"visit", http.MethodGet, true, func(*http.Request) {}, true},
+		{"do not track", http.MethodGet, true, func(r *http.Request) { r.Header.Set("DNT", "1") }, false},
+		{"opt-out cookie", http.MethodGet, true, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: DefaultOptOutCookie, Value: "1"})
+		}, false},
+		{"do not track not honored", http.MethodGet, false, func(r *http.Request) { r.Header.Set("DNT", "1") }, true},
+		{"head", http.MethodHead, true, func(*http.Request) {}, false},
+		{"bot", http.MethodGet, true, func(r *http.Request) { r.Header.Set("User-Agent", "Googlebot/2.1") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			sampler := NewClickSampler(&fakeWorkspaceLookup{}, nil, zap.NewNop())
+			recorder := NewClickRecorder(sink, sampler, NewBotDetector(), NewOptOutPolicy(tt.honor, ""))
+
+			// Visitors are redirected whether or not the click is counted
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				recorder.Record(r, result, "203.0.113.7")
+				http.Redirect(w, r, result.DestinationURL, http.StatusFound)
+			})
+
+			req := httptest.NewRequest(tt.method, "/abc123", nil)
+			req.Header.Set("User-Agent", "Mozilla/5.0")
+			tt.prepare(req)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusFound {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusFound)
+			}
+			if got := len(sink.tracked) == 1; got != tt.tracked {
+				t.Fatalf("tracked %d clicks, want tracked=%v", len(sink.tracked), tt.tracked)
+			}
+			if len(sink.skipped) != 0 {
+				t.Errorf("skipped %d clicks, want none", len(sink.skipped))
+			}
+			if tt.tracked {
+				event := sink.tracked[0]
+				if event.LinkID != result.LinkID || event.IP != "203.0.113.7" || event.SampleRate != 0 {
+					t.Errorf("event = %+v, want link %s from 203.0.113.7 unsampled", event, result.LinkID)
+				}
+			}
+		})
+	}
+}
+
+func TestClickRecorder_SampledOut(t *testing.T) {
+	lookup := &fakeWorkspaceLookup{settings: models.WorkspaceSettings{
+		ClickSampling: &models.WorkspaceClickSampling{Rate: 10},
+	}}
+	sampler := NewClickSampler(lookup, nil, zap.NewNop())
+	sampler.intN = func(int) int { return 1 }
+	sink := &recordingSink{}
+	recorder := NewClickRecorder(sink, sampler, NewBotDetector(), NewOptOutPolicy(true, ""))
+	result := &ResolveResult{LinkID: uuid.New(), WorkspaceID: uuid.New()}
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	if !recorder.Record(req, result, "203.0.113.7") {
+		t.Error("Record() = false, want a sampled-out visit counted")
+	}
+	if len(sink.tracked) != 0 || len(sink.skipped) != 1 || sink.skipped[0] != result.LinkID {
+		t.Errorf("tracked %d, skipped %v; want only link %s skipped", len(sink.tracked), sink.skipped, result.LinkID)
+	}
+}
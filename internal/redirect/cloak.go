@@ -0,0 +1,192 @@
+package redirect
+
+import (
+	"container/list"
+	"context"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var cloakPageTmpl = template.Must(template.New("cloak").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <meta name="robots" content="noindex, nofollow">
+  <title>{{.Title}}</title>
+  <style>
+    html, body { margin: 0; padding: 0; height: 100%; overflow: hidden; }
+    iframe { display: block; border: 0; width: 100%; height: 100%; }
+  </style>
+</head>
+<body>
+  <iframe src="{{.URL}}" allow="fullscreen; clipboard-write" referrerpolicy="no-referrer-when-downgrade"></iframe>
+</body>
+</html>`))
+
+// WriteCloakPage renders the page that frames destination for a cloaked link.
+func WriteCloakPage(w io.Writer, title, destination string) error {
+	return cloakPageTmpl.Execute(w, map[string]string{
+		"Title": title,
+		"URL":   destination,
+	})
+}
+
+// HeaderFetcher fetches the response headers of a URL.
+type HeaderFetcher interface {
+	Headers(ctx context.Context, rawURL string) (http.Header, error)
+}
+
+// frameCacheSize bounds how many origins FrameChecker remembers. The least
+// recently used origin is evicted first.
+const frameCacheSize = 10000
+
+type frameEntry struct {
+	origin    string
+	allowed   bool
+	expiresAt time.Time
+}
+
+// FrameChecker decides whether a destination can be shown in an iframe by
+// probing its X-Frame-Options and Content-Security-Policy headers. Results
+// are cached per origin, since sites set framing policy site-wide, so
+// forwarded query parameters or differing paths don't each pay for a probe.
+type FrameChecker struct {
+	fetcher HeaderFetcher
+	ttl     atomic.Int64 // time.Duration
+	timeout time.Duration
+	maxSize int
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	order   *list.List // of *frameEntry, most recently used first
+	results map[string]*list.Element
+}
+
+func NewFrameChecker(fetcher HeaderFetcher, ttl time.Duration, logger *zap.Logger) *FrameChecker {
+	fc := &FrameChecker{
+		fetcher: fetcher,
+		timeout: 3 * time.Second,
+		maxSize: frameCacheSize,
+		logger:  logger,
+		order:   list.New(),
+		results: make(map[string]*list.Element),
+	}
+	fc.SetTTL(ttl)
+	return fc
+}
+
+// SetTTL changes how long probe results are reused. Results already stored
+// keep their expiry.
+func (fc *FrameChecker) SetTTL(ttl time.Duration) {
+	fc.ttl.Store(int64(ttl))
+}
+
+// CanFrame reports whether destination may be served inside a cloak page.
+// Only HTTPS destinations are framed, since browsers block plain HTTP inside
+// an HTTPS page. Probe failures are treated as not frameable so the visitor
+// gets a normal redirect.
+func (fc *FrameChecker) CanFrame(ctx context.Context, destination string) bool {
+	u, err := url.Parse(destination)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return false
+	}
+	origin := "https://" + strings.ToLower(u.Host)
+
+	if allowed, ok := fc.lookup(origin); ok {
+		return allowed
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fc.timeout)
+	defer cancel()
+
+	allowed := false
+	header, err := fc.fetcher.Headers(ctx, destination)
+	if err != nil {
+		fc.logger.Debug("frame probe failed", zap.String("origin", origin), zap.Error(err))
+	} else {
+		allowed = FramingAllowed(header)
+	}
+
+	fc.store(origin, allowed)
+	return allowed
+}
+
+func (fc *FrameChecker) lookup(origin string) (allowed, ok bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	el, ok := fc.results[origin]
+	if !ok {
+		return false, false
+	}
+	entry := el.Value.(*frameEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		fc.order.Remove(el)
+		delete(fc.results, origin)
+		return false, false
+	}
+	fc.order.MoveToFront(el)
+	return entry.allowed, true
+}
+
+func (fc *FrameChecker) store(origin string, allowed bool) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	entry := &frameEntry{origin: origin, allowed: allowed, expiresAt: time.Now().Add(time.Duration(fc.ttl.Load()))}
+	if el, ok := fc.results[origin]; ok {
+		el.Value = entry
+		fc.order.MoveToFront(el)
+		return
+	}
+	fc.results[origin] = fc.order.PushFront(entry)
+	for fc.order.Len() > fc.maxSize {
+		oldest := fc.order.Back()
+		fc.order.Remove(oldest)
+		delete(fc.results, oldest.Value.(*frameEntry).origin)
+	}
+}
+
+// FramingAllowed reports whether response headers let the page be embedded
+// in a cross-origin iframe. A frame-ancestors directive only allows it when
+// it lists "*", since the cloak page's origin can't be known to the site.
+func FramingAllowed(h http.Header) bool {
+	for _, v := range h.Values("X-Frame-Options") {
+		switch strings.ToUpper(strings.TrimSpace(v)) {
+		case "DENY", "SAMEORIGIN":
+			return false
+		}
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(v)), "ALLOW-FROM") {
+			return false
+		}
+	}
+
+	for _, policy := range h.Values("Content-Security-Policy") {
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) == 0 || !strings.EqualFold(fields[0], "frame-ancestors") {
+				continue
+			}
+			wildcard := false
+			for _, src := range fields[1:] {
+				if src == "*" {
+					wildcard = true
+				}
+			}
+			if !wildcard {
+				return false
+			}
+		}
+	}
+
+	return true
+}
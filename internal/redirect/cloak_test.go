@@ -0,0 +1,132 @@
+package redirect
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestFramingAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{"no headers", http.Header{}, true},
+		{"xfo deny", http.Header{"X-Frame-Options": {"DENY"}}, false},
+		{"xfo sameorigin", http.Header{"X-Frame-Options": {"sameorigin"}}, false},
+		{"csp wildcard", http.Header{"Content-Security-Policy": {"default-src 'self'; frame-ancestors *"}}, true},
+		{"csp self", http.Header{"Content-Security-Policy": {"frame-ancestors 'self'"}}, false},
+		{"csp without frame-ancestors", http.Header{"Content-Security-Policy": {"default-src 'self'"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FramingAllowed(tt.header); got != tt.want {
+				t.Errorf("FramingAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type stubHeaderFetcher struct {
+	header http.Header
+	err    error
+	calls  int
+}
+
+func (s *stubHeaderFetcher) Headers(_ context.Context, _ string) (http.Header, error) {
+	s.calls++
+	return s.header, s.err
+}
+
+func TestFrameChecker_CachesResult(t *testing.T) {
+	fetcher := &stubHeaderFetcher{header: http.Header{}}
+	fc := NewFrameChecker(fetcher, time.Minute, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		if !fc.CanFrame(context.Background(), "https://example.com/offer") {
+			t.Fatal("expected destination to be frameable")
+		}
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("expected 1 probe, got %d", fetcher.calls)
+	}
+}
+
+func TestFrameChecker_CachesPerOrigin(t *testing.T) {
+	fetcher := &stubHeaderFetcher{header: http.Header{}}
+	fc := NewFrameChecker(fetcher, time.Minute, zap.NewNop())
+
+	for _, dest := range []string{
+		"https://example.com/offer?utm_source=a",
+		"https://example.com/offer?utm_source=b",
+		"https://EXAMPLE.com/other",
+	} {
+		fc.CanFrame(context.Background(), dest)
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("expected 1 probe for one origin, got %d", fetcher.calls)
+	}
+
+	fc.CanFrame(context.Background(), "https://example.org/offer")
+	if fetcher.calls != 2 {
+		t.Errorf("expected a probe for a new origin, got %d probes", fetcher.calls)
+	}
+}
+
+func TestFrameChecker_EvictsLeastRecentlyUsed(t *testing.T) {
+	fetcher := &stubHeaderFetcher{header: http.Header{}}
+	fc := NewFrameChecker(fetcher, time.Minute, zap.NewNop())
+	fc.maxSize = 2
+
+	fc.CanFrame(context.Background(), "https://a.example")
+	fc.CanFrame(context.Background(), "https://b.example")
+	fc.CanFrame(context.Background(), "https://a.example")
+	fc.CanFrame(context.Background(), "https://c.example")
+	if len(fc.results) != 2 || fc.order.Len() != 2 {
+		t.Fatalf("cache holds %d origins, want 2", len(fc.results))
+	}
+
+	calls := fetcher.calls
+	fc.CanFrame(context.Background(), "https://a.example")
+	if fetcher.calls != calls {
+		t.Error("expected recently used origin to stay cached")
+	}
+	fc.CanFrame(context.Background(), "https://b.example")
+	if fetcher.calls != calls+1 {
+		t.Error("expected least recently used origin to be evicted")
+	}
+}
+
+func TestFrameChecker_FallsBack(t *testing.T) {
+	fc := NewFrameChecker(&stubHeaderFetcher{err: errors.New("timeout")}, time.Minute, zap.NewNop())
+	if fc.CanFrame(context.Background(), "https://example.com") {
+		t.Error("expected probe failure to disable framing")
+	}
+
+	fc = NewFrameChecker(&stubHeaderFetcher{header: http.Header{}}, time.Minute, zap.NewNop())
+	if fc.CanFrame(context.Background(), "http://example.com") {
+		t.Error("expected plain HTTP destination not to be framed")
+	}
+}
+
+func TestWriteCloakPage_EscapesURL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCloakPage(&buf, "Offer", `https://example.com/?a=1&b="x"`); err != nil {
+		t.Fatalf("WriteCloakPage: %v", err)
+	}
+	page := buf.String()
+	if strings.Contains(page, `b="x"`) {
+		t.Error("expected destination URL to be escaped")
+	}
+	if !strings.Contains(page, `<meta name="robots" content="noindex, nofollow">`) {
+		t.Error("expected noindex meta tag")
+	}
+}
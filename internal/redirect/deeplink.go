@@ -0,0 +1,88 @@
+package redirect
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// deepLinkFallbackDelay is how long the deep link page waits for the app to
+// open before sending the visitor to the web fallback.
+const deepLinkFallbackDelay = 1500 * time.Millisecond
+
+var deepLinkPageTmpl = template.Must(template.New("deeplink").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <meta name="robots" content="noindex, nofollow">
+  <title>{{.Title}}</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 0; min-height: 100vh; display: flex; align-items: center; justify-content: center; background: #f8fafc; color: #1e293b; }
+    main { text-align: center; padding: 2rem; max-width: 24rem; }
+    h1 { font-size: 1.25rem; margin: 0 0 0.5rem; }
+    p { color: #64748b; margin: 0 0 1.5rem; }
+    .open { display: block; background: #3b82f6; color: #fff; text-decoration: none; padding: 0.75rem 1rem; border-radius: 0.5rem; font-weight: 600; margin-bottom: 1rem; }
+    .web { color: #3b82f6; text-decoration: none; font-size: 0.875rem; }
+  </style>
+</head>
+<body>
+  <main>
+    <h1>{{.Title}}</h1>
+    <p>Opening the app&hellip;</p>
+    <a class="open" href="{{.AppURL}}">Open in app</a>
+    <a class="web" href="{{.FallbackURL}}">Continue to the website</a>
+  </main>
+  <script>
+    (function () {
+      var fallback = {{.FallbackURL}};
+      var timer = setTimeout(function () { window.location.replace(fallback); }, {{.DelayMS}});
+      // The page is hidden once the app opens; don't follow up with the web page.
+      document.addEventListener('visibilitychange', function () {
+        if (document.hidden) { clearTimeout(timer); }
+      });
+      window.location.href = {{.AppURL}};
+    })();
+  </script>
+</body>
+</html>`))
+
+// AppURL returns the app URI to try for a visitor with the given User-Agent,
+// or "" when the link has none for their platform.
+func (r *ResolveResult) AppURL(userAgent string) string {
+	if r.IOSURL == "" && r.AndroidURL == "" {
+		return ""
+	}
+	switch os, _ := ParseOS(userAgent); os {
+	case "iOS":
+		return r.IOSURL
+	case "Android":
+		return r.AndroidURL
+	default:
+		return ""
+	}
+}
+
+// WebDestination returns where visitors without an app URI go: the fallback
+// URL when the link has one, and the destination otherwise.
+func (r *ResolveResult) WebDestination() string {
+	if r.FallbackURL != "" {
+		return r.FallbackURL
+	}
+	return r.DestinationURL
+}
+
+// WriteDeepLinkPage renders the page that tries to open appURL and sends the
+// visitor to fallbackURL if the app doesn't open. appURL must already be
+// validated, since custom schemes are passed through as trusted.
+func WriteDeepLinkPage(w io.Writer, title, appURL, fallbackURL string) error {
+	if title == "" {
+		title = "Opening app"
+	}
+	return deepLinkPageTmpl.Execute(w, map[string]any{
+		"Title":       title,
+		"AppURL":      template.URL(appURL),
+		"FallbackURL": fallbackURL,
+		"DelayMS":     deepLinkFallbackDelay.Milliseconds(),
+	})
+}
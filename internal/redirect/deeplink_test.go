@@ -0,0 +1,73 @@
+package redirect
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	iPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1"
+	iPadUA    = "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1"
+	androidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+	desktopUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+func TestAppURL(t *testing.T) {
+	both := &ResolveResult{IOSURL: "myapp://item/1", AndroidURL: "intent://item/1#Intent;scheme=myapp;end"}
+	iosOnly := &ResolveResult{IOSURL: "myapp://item/1"}
+
+	tests := []struct {
+		name   string
+		result *ResolveResult
+		ua     string
+		want   string
+	}{
+		{"iPhone", both, iPhoneUA, "myapp://item/1"},
+		{"iPad", both, iPadUA, "myapp://item/1"},
+		{"Android", both, androidUA, "intent://item/1#Intent;scheme=myapp;end"},
+		{"desktop", both, desktopUA, ""},
+		{"no URI for platform", iosOnly, androidUA, ""},
+		{"no app URIs", &ResolveResult{}, iPhoneUA, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.AppURL(tt.ua); got != tt.want {
+				t.Errorf("AppURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebDestination(t *testing.T) {
+	r := &ResolveResult{DestinationURL: "https://example.com/item"}
+	if got := r.WebDestination(); got != "https://example.com/item" {
+		t.Errorf("WebDestination() = %q, want the destination", got)
+	}
+	r.FallbackURL = "https://example.com/get-the-app"
+	if got := r.WebDestination(); got != "https://example.com/get-the-app" {
+		t.Errorf("WebDestination() = %q, want the fallback", got)
+	}
+}
+
+func TestWriteDeepLinkPage(t *testing.T) {
+	var b strings.Builder
+	err := WriteDeepLinkPage(&b, "<Item>", "myapp://item/1", "https://example.com/item?a=1&b=2")
+	if err != nil {
+		t.Fatalf("WriteDeepLinkPage() error = %v", err)
+	}
+	page := b.String()
+
+	if strings.Contains(page, "ZgotmplZ") {
+		t.Error("app URI was rejected by the template escaper")
+	}
+	if !strings.Contains(page, `href="myapp://item/1"`) {
+		t.Error("expected the app URI on the open button")
+	}
+	if !strings.Contains(page, `href="https://example.com/item?a=1&amp;b=2"`) {
+		t.Error("expected the escaped fallback URL on the website link")
+	}
+	if strings.Contains(page, "<Item>") {
+		t.Error("title was not escaped")
+	}
+}
@@ -0,0 +1,39 @@
+package redirect
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// WantsJSON reports whether r asks for JSON error bodies rather than the
+// HTML error page: application/json is listed in its Accept header before
+// any HTML type. Browsers, and clients that send no Accept header or */*,
+// get HTML. Quality values are not weighed.
+func WantsJSON(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json":
+			return true
+		case "text/html", "application/xhtml+xml":
+			return false
+		}
+	}
+	return false
+}
+
+// ErrorResponse is the JSON body sent in place of an error page, in the
+// API's response shape. The code is derived from the page title, so
+// "Link Not Found" becomes LINK_NOT_FOUND.
+func ErrorResponse(title, message string) httputil.Response {
+	code := strings.ToUpper(strings.Join(strings.Fields(title), "_"))
+	return httputil.Response{
+		Success: false,
+		Error: &httputil.ErrorBody{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
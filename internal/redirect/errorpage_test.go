@@ -0,0 +1,43 @@
+package redirect
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWantsJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", true},
+		{"Application/JSON; charset=utf-8", true},
+		{"application/json, text/plain, */*", true},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"text/html, application/json", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/abc", nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+		if got := WantsJSON(r); got != tt.want {
+			t.Errorf("WantsJSON(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	resp := ErrorResponse("Link Not Found", "The link you're looking for doesn't exist.")
+	if resp.Success || resp.Error == nil {
+		t.Fatalf("response = %+v, want an error", resp)
+	}
+	if resp.Error.Code != "LINK_NOT_FOUND" {
+		t.Errorf("code = %q, want LINK_NOT_FOUND", resp.Error.Code)
+	}
+	if resp.Error.Message != "The link you're looking for doesn't exist." {
+		t.Errorf("message = %q", resp.Error.Message)
+	}
+}
@@ -0,0 +1,93 @@
+package redirect
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	destinationHealthKeyPrefix = "destination_health:"
+
+	destinationUp   = "up"
+	destinationDown = "down"
+)
+
+// Failover sends visitors of links with failover URLs to the first of the
+// link's destinations that is up. Whether a destination is up is recorded
+// in Redis by the worker's periodic health checks; the redirect path only
+// reads it and never probes a destination itself, so a failover link costs
+// one Redis round trip rather than a request to the destination.
+type Failover struct {
+	redis  *redis.Client
+	logger *zap.Logger
+}
+
+func NewFailover(redisClient *redis.Client, logger *zap.Logger) *Failover {
+	return &Failover{
+		redis:  redisClient,
+		logger: logger,
+	}
+}
+
+// Record stores whether destination was up at its latest health check. The
+// result expires after ttl, after which the destination counts as unchecked.
+func (f *Failover) Record(ctx context.Context, destination string, up bool, ttl time.Duration) error {
+	state := destinationDown
+	if up {
+		state = destinationUp
+	}
+	return f.redis.Set(ctx, destinationHealthKey(destination), state, ttl).Err()
+}
+
+// Destination returns where to send a visitor of result's link: its web
+// destination, unless that was found down, in which case the first failover
+// URL found up. Without health data for the web destination, or with no
+// failover URL found up, the web destination is used.
+func (f *Failover) Destination(ctx context.Context, result *ResolveResult) string {
+	primary := result.WebDestination()
+	if f == nil || f.redis == nil || len(result.FailoverURLs) == 0 {
+		return primary
+	}
+
+	candidates := append([]string{primary}, result.FailoverURLs...)
+	keys := make([]string, len(candidates))
+	for i, u := range candidates {
+		keys[i] = destinationHealthKey(u)
+	}
+	vals, err := f.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		f.logger.Warn("failed to read destination health", zap.String("short_code", result.ShortCode), zap.Error(err))
+		return primary
+	}
+
+	states := make([]string, len(vals))
+	for i, v := range vals {
+		states[i], _ = v.(string)
+	}
+	return pickDestination(candidates, states)
+}
+
+// pickDestination returns candidates[0] unless states[0] is down, and then
+// the first later candidate whose state is up, if any. states holds each
+// candidate's recorded state, empty when it has none.
+func pickDestination(candidates, states []string) string {
+	if states[0] != destinationDown {
+		return candidates[0]
+	}
+	for i := 1; i < len(candidates); i++ {
+		if states[i] == destinationUp {
+			return candidates[i]
+		}
+	}
+	return candidates[0]
+}
+
+func destinationHealthKey(destination string) string {
+	sum := sha256.Sum256([]byte(destination))
+	return destinationHealthKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,76 @@
+package redirect
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestPickDestination(t *testing.T) {
+	candidates := []string{"https://example.com", "https://backup1.example.com", "https://backup2.example.com"}
+
+	tests := []struct {
+		name   string
+		states []string
+		want   string
+	}{
+		{"no health data", []string{"", "", ""}, candidates[0]},
+		{"primary up", []string{destinationUp, destinationUp, destinationUp}, candidates[0]},
+		{"primary unchecked", []string{"", destinationUp, destinationUp}, candidates[0]},
+		{"primary down", []string{destinationDown, destinationUp, destinationUp}, candidates[1]},
+		{"first failover down", []string{destinationDown, destinationDown, destinationUp}, candidates[2]},
+		{"first failover unchecked", []string{destinationDown, "", destinationUp}, candidates[2]},
+		{"all down", []string{destinationDown, destinationDown, destinationDown}, candidates[0]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pickDestination(candidates, tt.states); got != tt.want {
+				t.Errorf("pickDestination() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailoverDestinationWithoutHealth(t *testing.T) {
+	result := &ResolveResult{
+		DestinationURL: "https://example.com",
+		FallbackURL:    "https://example.com/web",
+		FailoverURLs:   []string{"https://backup.example.com"},
+	}
+
+	// Without Redis there is no health data, so the web destination is used
+	f := NewFailover(nil, zap.NewNop())
+	if got := f.Destination(context.Background(), result); got != result.FallbackURL {
+		t.Errorf("Destination() = %q, want %q", got, result.FallbackURL)
+	}
+
+	var unset *Failover
+	if got := unset.Destination(context.Background(), result); got != result.FallbackURL {
+		t.Errorf("nil Failover Destination() = %q, want %q", got, result.FallbackURL)
+	}
+}
+
+func TestFailoverURLsCached(t *testing.T) {
+	failovers := []string{"https://backup1.example.com", "https://backup2.example.com"}
+	cl := newCachedLink(&models.Link{URL: "https://example.com", IsActive: true, FailoverURLs: failovers})
+
+	// Entries round-trip through JSON in the Redis layer
+	data, err := json.Marshal(cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded CachedLink
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resultAt(&decoded, time.Now()).FailoverURLs; !slices.Equal(got, failovers) {
+		t.Errorf("failover URLs = %v, want %v", got, failovers)
+	}
+}
@@ -0,0 +1,108 @@
+package redirect
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// DestinationProber checks that a URL answers. *httputil.SafeClient
+// satisfies it.
+type DestinationProber interface {
+	Check(ctx context.Context, rawURL string) (*httputil.CheckResult, error)
+}
+
+type httpsEntry struct {
+	available bool
+	expiresAt time.Time
+}
+
+// HTTPSUpgrader rewrites http:// destinations to https:// for links that
+// force HTTPS. Whether a host answers on HTTPS is probed on first use and
+// cached per host, so only the first visit in each TTL pays for the probe.
+type HTTPSUpgrader struct {
+	prober  DestinationProber
+	ttl     atomic.Int64 // time.Duration
+	timeout time.Duration
+	results sync.Map
+	logger  *zap.Logger
+}
+
+func NewHTTPSUpgrader(prober DestinationProber, ttl time.Duration, logger *zap.Logger) *HTTPSUpgrader {
+	u := &HTTPSUpgrader{
+		prober:  prober,
+		timeout: 3 * time.Second,
+		logger:  logger,
+	}
+	u.SetTTL(ttl)
+	return u
+}
+
+// SetTTL changes how long probe results are reused. Results already stored
+// keep their expiry.
+func (u *HTTPSUpgrader) SetTTL(ttl time.Duration) {
+	u.ttl.Store(int64(ttl))
+}
+
+// Upgrade returns destination over HTTPS when its host answers on HTTPS, and
+// destination unchanged otherwise, so visitors still reach sites that only
+// serve plain HTTP. Destinations that aren't upgradable are returned as is.
+func (u *HTTPSUpgrader) Upgrade(ctx context.Context, destination string) string {
+	upgraded, host, ok := UpgradeScheme(destination)
+	if !ok {
+		return destination
+	}
+
+	if val, ok := u.results.Load(host); ok {
+		entry := val.(*httpsEntry)
+		if time.Now().Before(entry.expiresAt) {
+			return pick(entry.available, upgraded, destination)
+		}
+		u.results.Delete(host)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	_, err := u.prober.Check(ctx, "https://"+host+"/")
+	if err != nil {
+		u.logger.Debug("https probe failed", zap.String("host", host), zap.Error(err))
+	}
+	available := err == nil
+
+	u.results.Store(host, &httpsEntry{available: available, expiresAt: time.Now().Add(time.Duration(u.ttl.Load()))})
+	return pick(available, upgraded, destination)
+}
+
+func pick(upgrade bool, upgraded, destination string) string {
+	if upgrade {
+		return upgraded
+	}
+	return destination
+}
+
+// UpgradeScheme rewrites an http:// URL to https://, dropping an explicit
+// port 80. It returns the rewritten URL and its host, and false for URLs that
+// aren't plain HTTP or that use another port, since HTTPS on the same
+// non-standard port is unlikely to exist.
+func UpgradeScheme(rawURL string) (string, string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Scheme, "http") || u.Hostname() == "" {
+		return "", "", false
+	}
+	switch u.Port() {
+	case "":
+	case "80":
+		u.Host = strings.TrimSuffix(u.Host, ":80")
+	default:
+		return "", "", false
+	}
+	u.Scheme = "https"
+	return u.String(), u.Host, true
+}
@@ -0,0 +1,110 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+func TestUpgradeScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		want     string
+		wantHost string
+		wantOK   bool
+	}{
+		{"http", "http://example.com/path?q=1#top", "https://example.com/path?q=1#top", "example.com", true},
+		{"uppercase scheme", "HTTP://example.com/", "https://example.com/", "example.com", true},
+		{"default port", "http://example.com:80/a", "https://example.com/a", "example.com", true},
+		{"custom port", "http://example.com:8080/a", "", "", false},
+		{"already https", "https://example.com/", "", "", false},
+		{"other scheme", "ftp://example.com/file", "", "", false},
+		{"no host", "http:///path", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, host, ok := UpgradeScheme(tt.url)
+			if got != tt.want || host != tt.wantHost || ok != tt.wantOK {
+				t.Errorf("UpgradeScheme(%q) = %q, %q, %v, want %q, %q, %v", tt.url, got, host, ok, tt.want, tt.wantHost, tt.wantOK)
+			}
+		})
+	}
+}
+
+type stubProber struct {
+	err   error
+	urls  []string
+	calls int
+}
+
+func (s *stubProber) Check(_ context.Context, rawURL string) (*httputil.CheckResult, error) {
+	s.calls++
+	s.urls = append(s.urls, rawURL)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &httputil.CheckResult{StatusCode: 200, FinalURL: rawURL}, nil
+}
+
+func TestHTTPSUpgrader_Upgrades(t *testing.T) {
+	prober := &stubProber{}
+	u := NewHTTPSUpgrader(prober, time.Minute, zap.NewNop())
+
+	for _, dest := range []string{"http://example.com/a", "http://example.com/b"} {
+		want := "https" + dest[len("http"):]
+		if got := u.Upgrade(context.Background(), dest); got != want {
+			t.Errorf("Upgrade(%q) = %q, want %q", dest, got, want)
+		}
+	}
+	if prober.calls != 1 {
+		t.Errorf("expected 1 probe per host, got %d", prober.calls)
+	}
+	if prober.urls[0] != "https://example.com/" {
+		t.Errorf("probed %q, want the host's HTTPS root", prober.urls[0])
+	}
+}
+
+func TestHTTPSUpgrader_FallsBackWithoutHTTPS(t *testing.T) {
+	prober := &stubProber{err: errors.New("tls: handshake failure")}
+	u := NewHTTPSUpgrader(prober, time.Minute, zap.NewNop())
+
+	for i := 0; i < 2; i++ {
+		if got := u.Upgrade(context.Background(), "http://legacy.example/page"); got != "http://legacy.example/page" {
+			t.Errorf("Upgrade() = %q, want the original http URL", got)
+		}
+	}
+	if prober.calls != 1 {
+		t.Errorf("expected failed probe to be cached, got %d probes", prober.calls)
+	}
+}
+
+func TestHTTPSUpgrader_LeavesOtherURLsAlone(t *testing.T) {
+	prober := &stubProber{}
+	u := NewHTTPSUpgrader(prober, time.Minute, zap.NewNop())
+
+	for _, dest := range []string{"https://example.com/", "http://example.com:8080/", "mailto:someone@example.com"} {
+		if got := u.Upgrade(context.Background(), dest); got != dest {
+			t.Errorf("Upgrade(%q) = %q, want it unchanged", dest, got)
+		}
+	}
+	if prober.calls != 0 {
+		t.Errorf("expected no probes, got %d", prober.calls)
+	}
+}
+
+func TestHTTPSUpgrader_ProbesAgainAfterTTL(t *testing.T) {
+	prober := &stubProber{}
+	u := NewHTTPSUpgrader(prober, -time.Second, zap.NewNop())
+
+	u.Upgrade(context.Background(), "http://example.com/")
+	u.Upgrade(context.Background(), "http://example.com/")
+	if prober.calls != 2 {
+		t.Errorf("expected expired result to be probed again, got %d probes", prober.calls)
+	}
+}
@@ -0,0 +1,299 @@
+package redirect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// interstitialKeyPrefix keys each workspace's cached name, interstitial
+	// settings and branding. The API deletes the key when any changes.
+	interstitialKeyPrefix   = "workspace:interstitial:"
+	interstitialRedisTTL    = 10 * time.Minute
+	interstitialLocalTTL    = 30 * time.Second
+	consentCookiePrefix     = "lr_consent_"
+	defaultInterstitialText = "This link takes you to another website. Its content isn't controlled by the site you came from."
+)
+
+// DefaultInterstitialTemplate is the interstitial page used unless
+// REDIRECT_INTERSTITIAL_TEMPLATE_PATH names another. It is executed with an
+// InterstitialPage.
+const DefaultInterstitialTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <meta name="robots" content="noindex, nofollow">
+{{- if .DelaySeconds}}
+  <meta http-equiv="refresh" content="{{.DelaySeconds}};url={{.ContinueURL}}">
+{{- end}}
+  <title>{{.Title}}</title>
+  <style>
+    * { margin: 0; padding: 0; box-sizing: border-box; }
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; background: #f9fafb; display: flex; align-items: center; justify-content: center; min-height: 100vh; }
+    .card { background: white; border-radius: 12px; box-shadow: 0 1px 3px rgba(0,0,0,0.1); padding: 2rem; max-width: 440px; width: 90%; }
+    h1 { font-size: 1.25rem; margin-bottom: 0.5rem; color: #111827; }
+    p { font-size: 0.875rem; color: #6b7280; margin-bottom: 1.5rem; white-space: pre-line; }
+    .destination { color: #111827; word-break: break-all; }
+    .destination small { color: #6b7280; }
+    a.button { display: block; text-align: center; padding: 0.625rem; background: #2563eb; color: white; border-radius: 6px; font-size: 0.875rem; font-weight: 500; text-decoration: none; }
+    a.button:hover { background: #1d4ed8; }
+    .countdown { margin: 1rem 0 0; text-align: center; }
+  </style>
+</head>
+<body>
+  <div class="card">
+    <h1>{{.Title}}</h1>
+    <p>{{.Message}}</p>
+    <p class="destination">Continuing to <strong>{{.DestinationHost}}</strong><br><small>{{.DestinationURL}}</small></p>
+    <a class="button" href="{{.ContinueURL}}">Continue</a>
+{{- if .DelaySeconds}}
+    <p class="countdown">Continuing in <span id="countdown">{{.DelaySeconds}}</span>s&hellip;</p>
+    <script>
+      (function () {
+        var left = {{.DelaySeconds}};
+        var el = document.getElementById('countdown');
+        var timer = setInterval(function () {
+          left--;
+          if (left <= 0) { clearInterval(timer); window.location.replace({{.ContinueURL}}); return; }
+          el.textContent = left;
+        }, 1000);
+      })();
+    </script>
+{{- end}}
+  </div>
+</body>
+</html>`
+
+var defaultInterstitialTmpl = template.Must(template.New("interstitial").Parse(DefaultInterstitialTemplate))
+
+// LoadInterstitialTemplate parses the interstitial template at path, or
+// returns the default template when path is empty.
+func LoadInterstitialTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return defaultInterstitialTmpl, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read interstitial template: %w", err)
+	}
+	tmpl, err := template.New("interstitial").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse interstitial template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// InterstitialPage is the data the interstitial template is executed with.
+// ContinueURL records the visitor's consent and sends them on; DelaySeconds
+// is 0 when they have to click it.
+type InterstitialPage struct {
+	Title           string
+	Message         string
+	WorkspaceName   string
+	LinkTitle       string
+	ShortCode       string
+	DestinationURL  string
+	DestinationHost string
+	ContinueURL     string
+	DelaySeconds    int
+}
+
+// interstitialSettings is what's cached per workspace.
+type interstitialSettings struct {
+	WorkspaceName string                        `json:"workspace_name"`
+	Interstitial  *models.WorkspaceInterstitial `json:"interstitial,omitempty"`
+	Branding      *models.WorkspaceBranding     `json:"branding,omitempty"`
+}
+
+type interstitialEntry struct {
+	settings  interstitialSettings
+	expiresAt time.Time
+}
+
+// Interstitials decides which links show a "you are leaving" page before
+// redirecting and renders it. Links show it when they or their workspace
+// turn it on; visitors who continued past it skip it for a while, through a
+// consent cookie per short code. Workspace settings are cached in Redis and
+// in memory like click sample rates.
+type Interstitials struct {
+	workspaces    WorkspaceLookup
+	redis         *redis.Client
+	local         sync.Map // uuid.UUID -> interstitialEntry
+	tmpl          *template.Template
+	defaultDelay  time.Duration
+	consentTTL    time.Duration
+	secureCookies bool
+	logger        *zap.Logger
+}
+
+// NewInterstitials renders pages with tmpl, counting down defaultDelay
+// unless the workspace sets its own delay, and remembers consent for
+// consentTTL in a cookie marked Secure when secureCookies is true.
+func NewInterstitials(
+	workspaces WorkspaceLookup,
+	redisClient *redis.Client,
+	tmpl *template.Template,
+	defaultDelay, consentTTL time.Duration,
+	secureCookies bool,
+	logger *zap.Logger,
+) *Interstitials {
+	return &Interstitials{
+		workspaces:    workspaces,
+		redis:         redisClient,
+		tmpl:          tmpl,
+		defaultDelay:  defaultDelay,
+		consentTTL:    consentTTL,
+		secureCookies: secureCookies,
+		logger:        logger,
+	}
+}
+
+// Page returns the interstitial to show before redirecting to the link's
+// destination, or nil when the link has none. rawQuery is the short link
+// request's query string, carried through the continue URL.
+func (i *Interstitials) Page(ctx context.Context, result *ResolveResult, rawQuery string) *InterstitialPage {
+	settings := i.settings(ctx, result.WorkspaceID)
+	ws := settings.Interstitial
+	if !result.Interstitial && (ws == nil || !ws.Enabled) {
+		return nil
+	}
+
+	destination := result.WebDestination()
+	page := &InterstitialPage{
+		Title:          "You're leaving this site",
+		Message:        defaultInterstitialText,
+		WorkspaceName:  settings.WorkspaceName,
+		LinkTitle:      result.Title,
+		ShortCode:      result.ShortCode,
+		DestinationURL: destination,
+		DelaySeconds:   int(i.defaultDelay.Round(time.Second) / time.Second),
+	}
+	if u, err := url.Parse(destination); err == nil {
+		page.DestinationHost = u.Hostname()
+	}
+	if ws != nil {
+		if ws.Title != "" {
+			page.Title = ws.Title
+		}
+		if ws.Message != "" {
+			page.Message = ws.Message
+		}
+		if ws.DelaySeconds != nil {
+			page.DelaySeconds = *ws.DelaySeconds
+		}
+	}
+	continueURL := url.URL{Path: "/" + result.ShortCode + "/continue", RawQuery: rawQuery}
+	page.ContinueURL = continueURL.String()
+	return page
+}
+
+// Write renders page to w. If a custom template fails, the default one is
+// used instead, so visitors aren't left on a broken page.
+func (i *Interstitials) Write(w io.Writer, page *InterstitialPage) error {
+	var buf bytes.Buffer
+	if err := i.tmpl.Execute(&buf, page); err != nil {
+		i.logger.Error("interstitial template failed, using the default", zap.Error(err))
+		buf.Reset()
+		if err := defaultInterstitialTmpl.Execute(&buf, page); err != nil {
+			return err
+		}
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// Branding returns the workspace's branding for the password and error
+// pages, or nil when it has none. It shares the interstitial settings'
+// cache.
+func (i *Interstitials) Branding(ctx context.Context, workspaceID uuid.UUID) *models.WorkspaceBranding {
+	return i.settings(ctx, workspaceID).Branding
+}
+
+// Consented reports whether the visitor continued past the short code's
+// interstitial recently.
+func (i *Interstitials) Consented(r *http.Request, shortCode string) bool {
+	cookie, err := r.Cookie(consentCookiePrefix + shortCode)
+	return err == nil && cookie.Value == "1"
+}
+
+// Consent remembers that the visitor continued past the short code's
+// interstitial.
+func (i *Interstitials) Consent(w http.ResponseWriter, shortCode string) {
+	http.SetCookie(w, httputil.NewCookie(consentCookiePrefix+shortCode, "1", int(i.consentTTL/time.Second), i.secureCookies))
+}
+
+func (i *Interstitials) settings(ctx context.Context, workspaceID uuid.UUID) interstitialSettings {
+	if v, ok := i.local.Load(workspaceID); ok {
+		if entry := v.(interstitialEntry); time.Now().Before(entry.expiresAt) {
+			return entry.settings
+		}
+	}
+
+	// A failed lookup leaves only link-level interstitials until the entry
+	// expires, rather than retrying on every click.
+	settings := i.loadSettings(ctx, workspaceID)
+	i.local.Store(workspaceID, interstitialEntry{settings: settings, expiresAt: time.Now().Add(interstitialLocalTTL)})
+	return settings
+}
+
+// loadSettings reads the settings from Redis, falling back to the workspace
+// and caching them in Redis. It returns empty settings if neither works.
+func (i *Interstitials) loadSettings(ctx context.Context, workspaceID uuid.UUID) interstitialSettings {
+	var settings interstitialSettings
+	key := interstitialKeyPrefix + workspaceID.String()
+	if i.redis != nil {
+		cached, err := i.redis.Get(ctx, key).Bytes()
+		if err == nil && json.Unmarshal(cached, &settings) == nil {
+			return settings
+		}
+		if err != nil && !errors.Is(err, redis.Nil) {
+			i.logger.Warn("interstitial settings lookup failed", zap.Error(err))
+		}
+	}
+
+	ws, err := i.workspaces.GetByID(ctx, workspaceID)
+	if err != nil {
+		i.logger.Warn("failed to load workspace for interstitial",
+			zap.String("workspace_id", workspaceID.String()),
+			zap.Error(err),
+		)
+		return interstitialSettings{}
+	}
+	parsed := ws.ParsedSettings()
+	settings = interstitialSettings{
+		WorkspaceName: ws.Name,
+		Interstitial:  parsed.Interstitial,
+	}
+	// The API validates branding; skip anything that slipped past it
+	// rather than put it on a page.
+	if b := parsed.Branding; b != nil {
+		if _, err := b.Validate(); err == nil {
+			settings.Branding = b
+		}
+	}
+
+	if i.redis != nil {
+		data, _ := json.Marshal(settings)
+		if err := i.redis.Set(ctx, key, data, interstitialRedisTTL).Err(); err != nil {
+			i.logger.Warn("failed to cache interstitial settings", zap.Error(err))
+		}
+	}
+	return settings
+}
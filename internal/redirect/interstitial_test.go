@@ -0,0 +1,142 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestInterstitials_Page(t *testing.T) {
+	delay := 0
+	lookup := &fakeWorkspaceLookup{}
+	i := NewInterstitials(lookup, nil, defaultInterstitialTmpl, 5*time.Second, time.Hour, true, zap.NewNop())
+	result := &ResolveResult{
+		WorkspaceID:    uuid.New(),
+		ShortCode:      "abc123",
+		DestinationURL: "https://partner.example.com/offer",
+	}
+
+	if page := i.Page(context.Background(), result, ""); page != nil {
+		t.Fatalf("expected no interstitial, got %+v", page)
+	}
+
+	result.Interstitial = true
+	page := i.Page(context.Background(), result, "utm_source=mail")
+	if page == nil {
+		t.Fatal("expected an interstitial for the link")
+	}
+	if page.DelaySeconds != 5 || page.DestinationHost != "partner.example.com" {
+		t.Errorf("page = %+v, want the default delay and the destination host", page)
+	}
+	if page.ContinueURL != "/abc123/continue?utm_source=mail" {
+		t.Errorf("ContinueURL = %q", page.ContinueURL)
+	}
+
+	// Workspace settings turn it on for every link and override the wording
+	// and delay.
+	lookup.settings = models.WorkspaceSettings{Interstitial: &models.WorkspaceInterstitial{
+		Enabled:      true,
+		Message:      "Affiliate link",
+		DelaySeconds: &delay,
+	}}
+	other := &ResolveResult{WorkspaceID: uuid.New(), ShortCode: "xyz", DestinationURL: "https://example.com/"}
+	page = i.Page(context.Background(), other, "")
+	if page == nil {
+		t.Fatal("expected the workspace's interstitial")
+	}
+	if page.Message != "Affiliate link" || page.DelaySeconds != 0 || page.Title == "" {
+		t.Errorf("page = %+v, want the workspace's message, no countdown and the default title", page)
+	}
+}
+
+func TestInterstitials_LookupFailure(t *testing.T) {
+	lookup := &fakeWorkspaceLookup{err: errors.New("connection refused")}
+	i := NewInterstitials(lookup, nil, defaultInterstitialTmpl, time.Second, time.Hour, true, zap.NewNop())
+	result := &ResolveResult{WorkspaceID: uuid.New(), ShortCode: "abc", DestinationURL: "https://example.com/"}
+
+	if page := i.Page(context.Background(), result, ""); page != nil {
+		t.Error("expected no interstitial when the workspace can't be loaded")
+	}
+	result.Interstitial = true
+	if page := i.Page(context.Background(), result, ""); page == nil {
+		t.Error("link-level interstitials should still be shown")
+	}
+	if lookup.calls != 1 {
+		t.Errorf("workspace looked up %d times, want 1", lookup.calls)
+	}
+}
+
+func TestInterstitials_Consent(t *testing.T) {
+	i := NewInterstitials(&fakeWorkspaceLookup{}, nil, defaultInterstitialTmpl, time.Second, time.Hour, true, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	i.Consent(rec, "abc")
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge != 3600 {
+		t.Fatalf("cookies = %+v, want one cookie lasting an hour", cookies)
+	}
+	if c := cookies[0]; !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("cookie = %+v, want HttpOnly, Secure and SameSite=Lax", c)
+	}
+
+	req := httptest.NewRequest("GET", "/abc", nil)
+	req.AddCookie(cookies[0])
+	if !i.Consented(req, "abc") {
+		t.Error("expected consent for the same short code")
+	}
+	if i.Consented(req, "other") {
+		t.Error("consent should not carry over to other links")
+	}
+}
+
+func TestInterstitials_Write(t *testing.T) {
+	page := &InterstitialPage{
+		Title:           "Leaving",
+		Message:         "<b>Careful</b>",
+		DestinationURL:  "https://example.com/p?a=1&b=2",
+		DestinationHost: "example.com",
+		ContinueURL:     "/abc/continue",
+		DelaySeconds:    3,
+	}
+	i := NewInterstitials(&fakeWorkspaceLookup{}, nil, defaultInterstitialTmpl, time.Second, time.Hour, true, zap.NewNop())
+
+	var b strings.Builder
+	if err := i.Write(&b, page); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`content="3;url=/abc/continue"`,
+		`&lt;b&gt;Careful&lt;/b&gt;`,
+		`https://example.com/p?a=1&amp;b=2`,
+		`href="/abc/continue"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("page is missing %s", want)
+		}
+	}
+
+	// Without a delay the visitor has to click.
+	page.DelaySeconds = 0
+	b.Reset()
+	_ = i.Write(&b, page)
+	if strings.Contains(b.String(), "http-equiv") {
+		t.Error("page without a delay should not refresh")
+	}
+
+	// A custom template that fails falls back to the default.
+	i.tmpl = template.Must(template.New("broken").Parse(`{{.Missing}}`))
+	b.Reset()
+	if err := i.Write(&b, page); err != nil || !strings.Contains(b.String(), "Continue") {
+		t.Errorf("expected the default page, got %q, %v", b.String(), err)
+	}
+}
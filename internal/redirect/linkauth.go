@@ -0,0 +1,39 @@
+package redirect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+const (
+	linkAuthCookiePrefix = "lr_auth_"
+	linkAuthCookieMaxAge = 24 * 60 * 60
+)
+
+// SetLinkAuth remembers, for a day, that the visitor entered the password of
+// the link in result, so they aren't asked again on every visit.
+func SetLinkAuth(w http.ResponseWriter, result *ResolveResult, secure bool) {
+	http.SetCookie(w, httputil.NewCookie(linkAuthCookiePrefix+result.ShortCode, linkAuthToken(result), linkAuthCookieMaxAge, secure))
+}
+
+// HasLinkAuth reports whether the visitor entered the password of the link
+// in result recently.
+func HasLinkAuth(r *http.Request, result *ResolveResult) bool {
+	cookie, err := r.Cookie(linkAuthCookiePrefix + result.ShortCode)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(cookie.Value), []byte(linkAuthToken(result)))
+}
+
+// linkAuthToken is keyed by the link's password hash, so it can't be forged
+// without it and stops working when the password changes.
+func linkAuthToken(result *ResolveResult) string {
+	mac := hmac.New(sha256.New, []byte(result.PasswordHash))
+	mac.Write([]byte(result.ShortCode))
+	return hex.EncodeToString(mac.Sum(nil))
+}
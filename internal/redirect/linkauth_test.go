@@ -0,0 +1,51 @@
+package redirect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLinkAuth(t *testing.T) {
+	result := &ResolveResult{ShortCode: "abc123", HasPassword: true, PasswordHash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$aGFzaA"}
+
+	rec := httptest.NewRecorder()
+	SetLinkAuth(rec, result, true)
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "lr_auth_abc123" {
+		t.Fatalf("cookies = %+v, want one lr_auth_abc123 cookie", cookies)
+	}
+	if c := cookies[0]; !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode || c.Path != "/" {
+		t.Errorf("cookie = %+v, want HttpOnly, Secure, SameSite=Lax and Path=/", c)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.AddCookie(cookies[0])
+	if !HasLinkAuth(req, result) {
+		t.Error("expected the cookie to authorize the link")
+	}
+
+	changed := *result
+	changed.PasswordHash = "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$b3RoZXI"
+	if HasLinkAuth(req, &changed) {
+		t.Error("a password change must invalidate the cookie")
+	}
+}
+
+func TestLinkAuth_RejectsForgedCookie(t *testing.T) {
+	result := &ResolveResult{ShortCode: "abc123", HasPassword: true, PasswordHash: "hash"}
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.AddCookie(&http.Cookie{Name: "lr_auth_abc123", Value: "1"})
+	if HasLinkAuth(req, result) {
+		t.Error("a forged cookie must not authorize the link")
+	}
+}
+
+func TestLinkAuth_NotSecure(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SetLinkAuth(rec, &ResolveResult{ShortCode: "abc123", PasswordHash: "hash"}, false)
+	if c := rec.Result().Cookies()[0]; c.Secure || !c.HttpOnly {
+		t.Errorf("cookie = %+v, want HttpOnly without Secure for local development", c)
+	}
+}
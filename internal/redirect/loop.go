@@ -0,0 +1,47 @@
+package redirect
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HopParam counts the short links a visitor has been redirected through.
+// It is added only to destinations served by the redirect service itself.
+const HopParam = "lr_hop"
+
+// MaxShortLinkHops is how many of our own short links a redirect may pass
+// through before it is treated as a loop. The API rejects destinations that
+// chain further when links are saved; the redirect service enforces it as a
+// safety net for chains created by later edits.
+const MaxShortLinkHops = 5
+
+// Hops returns the number of short links the request has already been
+// redirected through, 0 for a direct visit.
+func Hops(r *http.Request) int {
+	n, err := strconv.Atoi(r.URL.Query().Get(HopParam))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// MarkHop sets the hop count on destination when it is served by one of
+// hosts, so the next short link sees how far the visitor has come. Other
+// destinations are returned unchanged.
+func MarkHop(destination string, hops int, hosts ...string) string {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+	for _, host := range hosts {
+		if host != "" && strings.EqualFold(u.Host, host) {
+			query := u.Query()
+			query.Set(HopParam, strconv.Itoa(hops))
+			u.RawQuery = query.Encode()
+			return u.String()
+		}
+	}
+	return destination
+}
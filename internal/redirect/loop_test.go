@@ -0,0 +1,37 @@
+package redirect
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHops(t *testing.T) {
+	tests := map[string]int{
+		"/abc":           0,
+		"/abc?lr_hop=3":  3,
+		"/abc?lr_hop=-1": 0,
+		"/abc?lr_hop=x":  0,
+	}
+	for target, want := range tests {
+		if got := Hops(httptest.NewRequest("GET", target, nil)); got != want {
+			t.Errorf("Hops(%s) = %d, want %d", target, got, want)
+		}
+	}
+}
+
+func TestMarkHop(t *testing.T) {
+	tests := []struct {
+		destination string
+		want        string
+	}{
+		{"https://lnk.rs/abc", "https://lnk.rs/abc?lr_hop=2"},
+		{"https://LNK.rs/abc?lr_hop=1&x=y", "https://LNK.rs/abc?lr_hop=2&x=y"},
+		{"https://go.example.com/abc", "https://go.example.com/abc?lr_hop=2"},
+		{"https://example.com/abc?x=y", "https://example.com/abc?x=y"},
+	}
+	for _, tt := range tests {
+		if got := MarkHop(tt.destination, 2, "go.example.com", "lnk.rs"); got != tt.want {
+			t.Errorf("MarkHop(%s) = %s, want %s", tt.destination, got, tt.want)
+		}
+	}
+}
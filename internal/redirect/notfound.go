@@ -0,0 +1,138 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// notFoundDomainTTL is how long a host's domain, or its absence, is reused
+// before the database is asked again.
+const notFoundDomainTTL = time.Minute
+
+// DomainLookup finds a custom domain by its host name.
+type DomainLookup interface {
+	GetByDomain(ctx context.Context, domain string) (*models.Domain, error)
+}
+
+type notFoundEntry struct {
+	domain    *models.Domain
+	expiresAt time.Time
+}
+
+// NotFoundPolicies decides how unknown short codes and paths are answered
+// on each host. Verified custom domains can choose their own policy; other
+// hosts, and domains that don't choose, use the instance default.
+type NotFoundPolicies struct {
+	domains DomainLookup
+	local   sync.Map // host -> notFoundEntry
+	logger  *zap.Logger
+
+	mu       sync.RWMutex
+	fallback models.NotFoundPolicy
+}
+
+// NewNotFoundPolicies looks domains up through domains and falls back to
+// fallback.
+func NewNotFoundPolicies(domains DomainLookup, fallback models.NotFoundPolicy, logger *zap.Logger) *NotFoundPolicies {
+	return &NotFoundPolicies{domains: domains, fallback: fallback, logger: logger}
+}
+
+// DefaultNotFoundPolicy builds the instance default from its settings. An
+// empty mode redirects when url is set and renders the page otherwise, as
+// before modes existed.
+func DefaultNotFoundPolicy(mode string, status int, url string) models.NotFoundPolicy {
+	if mode == "" {
+		mode = models.NotFoundModePage
+		if url != "" {
+			mode = models.NotFoundModeRedirect
+		}
+	}
+	return models.NotFoundPolicy{Mode: mode, Status: status, URL: url}
+}
+
+// SetFallback replaces the instance default, e.g. after a config reload.
+func (p *NotFoundPolicies) SetFallback(fallback models.NotFoundPolicy) {
+	p.mu.Lock()
+	p.fallback = fallback
+	p.mu.Unlock()
+}
+
+// For returns the policy for requests to host, which may carry a port.
+func (p *NotFoundPolicies) For(ctx context.Context, host string) models.NotFoundPolicy {
+	p.mu.RLock()
+	fallback := p.fallback
+	p.mu.RUnlock()
+
+	d := p.domain(ctx, normalizeHost(host))
+	if d == nil || !d.IsVerified {
+		return fallback
+	}
+	policy := d.NotFoundPolicy(fallback)
+	if policy.Validate() != nil {
+		return fallback
+	}
+	return policy
+}
+
+func (p *NotFoundPolicies) domain(ctx context.Context, host string) *models.Domain {
+	if host == "" || p.domains == nil {
+		return nil
+	}
+	if v, ok := p.local.Load(host); ok {
+		if entry := v.(notFoundEntry); time.Now().Before(entry.expiresAt) {
+			return entry.domain
+		}
+	}
+
+	// A failed lookup serves the instance default until the entry expires,
+	// rather than hitting the database on every unknown code.
+	d, err := p.domains.GetByDomain(ctx, host)
+	if err != nil {
+		if !errors.Is(err, httputil.ErrNotFound) {
+			p.logger.Warn("failed to load domain for not-found policy",
+				zap.String("host", host),
+				zap.Error(err),
+			)
+		}
+		d = nil
+	}
+	p.local.Store(host, notFoundEntry{domain: d, expiresAt: time.Now().Add(notFoundDomainTTL)})
+	return d
+}
+
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// WriteNotFound answers r according to policy. page renders the branded
+// not-found page, or its JSON equivalent, with the given status. JSON
+// clients always get a body and are never redirected, so API callers see
+// the miss rather than the search page it leads to.
+func WriteNotFound(w http.ResponseWriter, r *http.Request, policy models.NotFoundPolicy, page func(status int)) {
+	status := policy.StatusCode()
+	switch {
+	case policy.Mode == models.NotFoundModeRedirect && WantsJSON(r):
+		page(http.StatusNotFound)
+	case policy.Mode == models.NotFoundModeRedirect:
+		// Keep browsers from caching a permanent redirect, so a code
+		// created later isn't hidden behind it.
+		w.Header().Set("Cache-Control", "no-store")
+		http.Redirect(w, r, policy.URL, status)
+	case policy.Mode == models.NotFoundModeStatus && !WantsJSON(r):
+		w.WriteHeader(status)
+	default:
+		page(status)
+	}
+}
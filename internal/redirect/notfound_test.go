@@ -0,0 +1,148 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type fakeDomainLookup struct {
+	domains map[string]*models.Domain
+	err     error
+	calls   int
+}
+
+func (f *fakeDomainLookup) GetByDomain(_ context.Context, domain string) (*models.Domain, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	d, ok := f.domains[domain]
+	if !ok {
+		return nil, httputil.NotFound("domain")
+	}
+	return d, nil
+}
+
+func TestWriteNotFound(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     models.NotFoundPolicy
+		accept     string
+		wantStatus int
+		wantPage   bool
+		wantTarget string
+	}{
+		{"page", models.NotFoundPolicy{Mode: models.NotFoundModePage}, "", 404, true, ""},
+		{"page gone", models.NotFoundPolicy{Mode: models.NotFoundModePage, Status: 410}, "", 410, true, ""},
+		{"redirect", models.NotFoundPolicy{Mode: models.NotFoundModeRedirect, URL: "https://example.com/search"}, "", 302, false, "https://example.com/search"},
+		{"permanent redirect", models.NotFoundPolicy{Mode: models.NotFoundModeRedirect, Status: 301, URL: "https://example.com/search"}, "", 301, false, "https://example.com/search"},
+		{"redirect to JSON client", models.NotFoundPolicy{Mode: models.NotFoundModeRedirect, URL: "https://example.com/search"}, "application/json", 404, true, ""},
+		{"status", models.NotFoundPolicy{Mode: models.NotFoundModeStatus}, "", 404, false, ""},
+		{"status gone", models.NotFoundPolicy{Mode: models.NotFoundModeStatus, Status: 410}, "", 410, false, ""},
+		{"status to JSON client", models.NotFoundPolicy{Mode: models.NotFoundModeStatus, Status: 410}, "application/json", 410, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/missing", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			rendered := false
+			WriteNotFound(w, r, tt.policy, func(status int) {
+				rendered = true
+				w.WriteHeader(status)
+			})
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if rendered != tt.wantPage {
+				t.Errorf("page rendered = %v, want %v", rendered, tt.wantPage)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantTarget {
+				t.Errorf("Location = %q, want %q", got, tt.wantTarget)
+			}
+			if !tt.wantPage && tt.wantTarget == "" && w.Body.Len() != 0 {
+				t.Errorf("status-only body = %q, want empty", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNotFoundPolicies_For(t *testing.T) {
+	mode := func(s string) *string { return &s }
+	status := func(n int) *int { return &n }
+	lookup := &fakeDomainLookup{domains: map[string]*models.Domain{
+		"go.example.com": {
+			Domain: "go.example.com", IsVerified: true,
+			NotFoundMode: mode(models.NotFoundModeStatus), NotFoundStatus: status(410),
+		},
+		"unverified.example.com": {
+			Domain:       "unverified.example.com",
+			NotFoundMode: mode(models.NotFoundModeStatus),
+		},
+		"default.example.com": {Domain: "default.example.com", IsVerified: true},
+		// Redirect mode without a URL can't be followed.
+		"broken.example.com": {
+			Domain: "broken.example.com", IsVerified: true,
+			NotFoundMode: mode(models.NotFoundModeRedirect),
+		},
+	}}
+	fallback := DefaultNotFoundPolicy("", 0, "")
+	policies := NewNotFoundPolicies(lookup, fallback, zap.NewNop())
+	ctx := context.Background()
+
+	if got := policies.For(ctx, "Go.Example.com:443"); got.Mode != models.NotFoundModeStatus || got.StatusCode() != 410 {
+		t.Errorf("custom domain policy = %+v, want status 410", got)
+	}
+	for _, host := range []string{"unverified.example.com", "default.example.com", "broken.example.com", "lnk.example.com"} {
+		if got := policies.For(ctx, host); got != fallback {
+			t.Errorf("For(%q) = %+v, want the fallback", host, got)
+		}
+	}
+
+	// Hosts, known or not, are cached.
+	calls := lookup.calls
+	policies.For(ctx, "go.example.com")
+	policies.For(ctx, "lnk.example.com")
+	if lookup.calls != calls {
+		t.Errorf("lookups = %d after cached hosts, want %d", lookup.calls, calls)
+	}
+
+	redirectAll := DefaultNotFoundPolicy("", 0, "https://example.com")
+	policies.SetFallback(redirectAll)
+	if got := policies.For(ctx, "lnk.example.com"); got != redirectAll {
+		t.Errorf("after SetFallback = %+v, want %+v", got, redirectAll)
+	}
+}
+
+func TestNotFoundPolicies_LookupError(t *testing.T) {
+	lookup := &fakeDomainLookup{err: errors.New("connection refused")}
+	fallback := DefaultNotFoundPolicy(models.NotFoundModeStatus, 0, "")
+	policies := NewNotFoundPolicies(lookup, fallback, zap.NewNop())
+
+	if got := policies.For(context.Background(), "go.example.com"); got != fallback {
+		t.Errorf("policy on lookup error = %+v, want the fallback", got)
+	}
+}
+
+func TestDefaultNotFoundPolicy(t *testing.T) {
+	if got := DefaultNotFoundPolicy("", 0, ""); got.Mode != models.NotFoundModePage {
+		t.Errorf("no URL: mode = %q, want page", got.Mode)
+	}
+	if got := DefaultNotFoundPolicy("", 0, "https://example.com"); got.Mode != models.NotFoundModeRedirect || got.StatusCode() != 302 {
+		t.Errorf("URL only: %+v, want a 302 redirect", got)
+	}
+	if got := DefaultNotFoundPolicy(models.NotFoundModeStatus, 410, "https://example.com"); got.Mode != models.NotFoundModeStatus {
+		t.Errorf("explicit mode: %+v, want status", got)
+	}
+}
@@ -0,0 +1,59 @@
+package redirect
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// DefaultOptOutCookie is the cookie name used when none is configured.
+const DefaultOptOutCookie = "lr_optout"
+
+// OptOutPolicy decides whether a visitor has asked not to be tracked, either
+// via the Do-Not-Track header or a site-wide opt-out cookie. Opted-out
+// visitors are still redirected; only click tracking is skipped.
+type OptOutPolicy struct {
+	settings atomic.Pointer[optOutSettings]
+}
+
+type optOutSettings struct {
+	enabled    bool
+	cookieName string
+}
+
+// NewOptOutPolicy returns a policy that honors DNT and the opt-out cookie when
+// enabled is true. An empty cookieName falls back to DefaultOptOutCookie.
+func NewOptOutPolicy(enabled bool, cookieName string) *OptOutPolicy {
+	p := &OptOutPolicy{}
+	p.Update(enabled, cookieName)
+	return p
+}
+
+// Update replaces the policy's settings, with the same defaults as
+// NewOptOutPolicy.
+func (p *OptOutPolicy) Update(enabled bool, cookieName string) {
+	if cookieName == "" {
+		cookieName = DefaultOptOutCookie
+	}
+	p.settings.Store(&optOutSettings{enabled: enabled, cookieName: cookieName})
+}
+
+// OptedOut reports whether tracking should be skipped for the request.
+func (p *OptOutPolicy) OptedOut(r *http.Request) bool {
+	if p == nil {
+		return false
+	}
+	settings := p.settings.Load()
+	if settings == nil || !settings.enabled {
+		return false
+	}
+
+	if r.Header.Get("DNT") == "1" {
+		return true
+	}
+
+	if cookie, err := r.Cookie(settings.cookieName); err == nil && cookie.Value == "1" {
+		return true
+	}
+
+	return false
+}
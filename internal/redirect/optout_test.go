@@ -0,0 +1,61 @@
+package redirect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOptOutPolicy_DNTHeader(t *testing.T) {
+	p := NewOptOutPolicy(true, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.Header.Set("DNT", "1")
+	if !p.OptedOut(req) {
+		t.Error("expected DNT: 1 to opt out of tracking")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.Header.Set("DNT", "0")
+	if p.OptedOut(req) {
+		t.Error("expected DNT: 0 to allow tracking")
+	}
+}
+
+func TestOptOutPolicy_Cookie(t *testing.T) {
+	p := NewOptOutPolicy(true, "custom_optout")
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.AddCookie(&http.Cookie{Name: "custom_optout", Value: "1"})
+	if !p.OptedOut(req) {
+		t.Error("expected opt-out cookie to skip tracking")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.AddCookie(&http.Cookie{Name: DefaultOptOutCookie, Value: "1"})
+	if p.OptedOut(req) {
+		t.Error("expected default cookie name to be ignored when a custom one is configured")
+	}
+}
+
+func TestOptOutPolicy_Disabled(t *testing.T) {
+	p := NewOptOutPolicy(false, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.Header.Set("DNT", "1")
+	req.AddCookie(&http.Cookie{Name: DefaultOptOutCookie, Value: "1"})
+	if p.OptedOut(req) {
+		t.Error("expected tracking when opt-out handling is disabled")
+	}
+}
+
+func TestOptOutPolicy_Update(t *testing.T) {
+	p := NewOptOutPolicy(false, "")
+	p.Update(true, "custom_optout")
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.AddCookie(&http.Cookie{Name: "custom_optout", Value: "1"})
+	if !p.OptedOut(req) {
+		t.Error("expected opt-out after enabling with a custom cookie")
+	}
+}
@@ -0,0 +1,79 @@
+package redirect
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+// ForwardQuery merges the short link's incoming query parameters into the
+// destination URL. On a key present on both sides, precedence decides which
+// values are kept. The destination's own parameters stay in their original
+// order and encoding, since affiliate networks sometimes sign the raw query;
+// forwarded parameters are appended after them.
+func ForwardQuery(destination string, incoming url.Values, precedence string) string {
+	if len(incoming) == 0 {
+		return destination
+	}
+	u, err := url.Parse(destination)
+	if err != nil {
+		return destination
+	}
+
+	var kept []string
+	existing := make(map[string]bool)
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		rawKey, _, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		if _, clash := incoming[key]; clash && precedence == models.ParamPrecedenceIncoming {
+			continue
+		}
+		existing[key] = true
+		kept = append(kept, pair)
+	}
+
+	forwarded := url.Values{}
+	for key, values := range incoming {
+		if existing[key] {
+			continue
+		}
+		forwarded[key] = values
+	}
+	if extra := forwarded.Encode(); extra != "" {
+		kept = append(kept, extra)
+	}
+
+	u.RawQuery = strings.Join(kept, "&")
+	return u.String()
+}
+
+// ClickSource returns the entry point the visitor reached the short link
+// through, from the models.ClickSourceParam query or form value, or "" for a
+// plain visit. Unknown values are ignored.
+func ClickSource(r *http.Request) string {
+	if r.FormValue(models.ClickSourceParam) == models.ClickSourceQR {
+		return models.ClickSourceQR
+	}
+	return ""
+}
+
+// ForwardableQuery returns the request's query parameters without the click
+// source and hop markers, which are only meant for the redirect service.
+func ForwardableQuery(r *http.Request) url.Values {
+	return forwardable(r.URL.Query())
+}
+
+// forwardable removes the click source and hop markers from query.
+func forwardable(query url.Values) url.Values {
+	query.Del(models.ClickSourceParam)
+	query.Del(HopParam)
+	return query
+}
@@ -0,0 +1,106 @@
+package redirect
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+func TestForwardQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		incoming    string
+		precedence  string
+		want        string
+	}{
+		{
+			name:        "no incoming params",
+			destination: "https://example.com/p?a=1",
+			incoming:    "",
+			precedence:  models.ParamPrecedenceDestination,
+			want:        "https://example.com/p?a=1",
+		},
+		{
+			name:        "appends to destination without query",
+			destination: "https://example.com/p",
+			incoming:    "ref=twitter&x=1",
+			precedence:  models.ParamPrecedenceDestination,
+			want:        "https://example.com/p?ref=twitter&x=1",
+		},
+		{
+			name:        "overlap keeps destination value",
+			destination: "https://example.com/p?ref=site&aff=42",
+			incoming:    "ref=twitter&x=1",
+			precedence:  models.ParamPrecedenceDestination,
+			want:        "https://example.com/p?ref=site&aff=42&x=1",
+		},
+		{
+			name:        "overlap takes incoming value",
+			destination: "https://example.com/p?ref=site&aff=42",
+			incoming:    "ref=twitter&x=1",
+			precedence:  models.ParamPrecedenceIncoming,
+			want:        "https://example.com/p?aff=42&ref=twitter&x=1",
+		},
+		{
+			name:        "incoming repeated key replaces all destination values",
+			destination: "https://example.com/p?tag=a&tag=b",
+			incoming:    "tag=c&tag=d",
+			precedence:  models.ParamPrecedenceIncoming,
+			want:        "https://example.com/p?tag=c&tag=d",
+		},
+		{
+			name:        "preserves destination encoding and fragment",
+			destination: "https://example.com/p?sig=a%2Fb&z=1#top",
+			incoming:    "z=2&utm_source=news",
+			precedence:  models.ParamPrecedenceDestination,
+			want:        "https://example.com/p?sig=a%2Fb&z=1&utm_source=news#top",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incoming, err := url.ParseQuery(tt.incoming)
+			if err != nil {
+				t.Fatalf("ParseQuery: %v", err)
+			}
+			if got := ForwardQuery(tt.destination, incoming, tt.precedence); got != tt.want {
+				t.Errorf("ForwardQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClickSource(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/abc", ""},
+		{"/abc?lr_src=qr", models.ClickSourceQR},
+		{"/abc?lr_src=email", ""},
+		{"/abc?utm_source=qr", ""},
+	}
+	for _, tt := range tests {
+		if got := ClickSource(httptest.NewRequest("GET", tt.target, nil)); got != tt.want {
+			t.Errorf("ClickSource(%s) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	// The password form posts the marker back as a field
+	req := httptest.NewRequest("POST", "/abc/verify", strings.NewReader("password=x&lr_src=qr"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if got := ClickSource(req); got != models.ClickSourceQR {
+		t.Errorf("ClickSource(form) = %q, want qr", got)
+	}
+}
+
+func TestForwardableQuery(t *testing.T) {
+	req := httptest.NewRequest("GET", "/abc?lr_src=qr&utm_source=flyer", nil)
+	if got := ForwardableQuery(req).Encode(); got != "utm_source=flyer" {
+		t.Errorf("ForwardableQuery() = %q, want the marker removed", got)
+	}
+}
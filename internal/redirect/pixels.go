@@ -0,0 +1,105 @@
+package redirect
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+const (
+	// pixelMaxDelay caps how long the pixel page holds the visitor, however
+	// slowly the pixel scripts load.
+	pixelMaxDelay = 1000 * time.Millisecond
+	// pixelSettleDelay gives the pixels' tracking requests time to leave
+	// once their scripts have loaded.
+	pixelSettleDelay = 250 * time.Millisecond
+)
+
+var pixelPageTmpl = template.Must(template.New("pixels").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <meta name="robots" content="noindex, nofollow">
+  <meta name="referrer" content="no-referrer-when-downgrade">
+  <meta http-equiv="refresh" content="{{.RefreshSeconds}};url={{.DestinationURL}}">
+  <title>Redirecting&hellip;</title>
+  <script>
+    (function () {
+      var destination = {{.DestinationURL}};
+      var pending = 0;
+      var done = false;
+      function go() {
+        if (done) { return; }
+        done = true;
+        window.location.replace(destination);
+      }
+      function loaded() {
+        pending--;
+        if (pending <= 0) { setTimeout(go, {{.SettleMS}}); }
+      }
+      function load(src) {
+        pending++;
+        var s = document.createElement('script');
+        s.async = true;
+        s.src = src;
+        s.onload = loaded;
+        s.onerror = loaded;
+        document.head.appendChild(s);
+      }
+      setTimeout(go, {{.MaxDelayMS}});
+{{- if .FacebookPixelID}}
+      var fbq = window.fbq = function () {
+        fbq.callMethod ? fbq.callMethod.apply(fbq, arguments) : fbq.queue.push(arguments);
+      };
+      window._fbq = fbq;
+      fbq.push = fbq;
+      fbq.loaded = true;
+      fbq.version = '2.0';
+      fbq.queue = [];
+      fbq('init', {{.FacebookPixelID}});
+      fbq('track', 'PageView');
+      load('https://connect.facebook.net/en_US/fbevents.js');
+{{- end}}
+{{- if .GoogleTagID}}
+      window.dataLayer = window.dataLayer || [];
+      window.gtag = function () { window.dataLayer.push(arguments); };
+      gtag('js', new Date());
+      gtag('config', {{.GoogleTagID}});
+      load('https://www.googletagmanager.com/gtag/js?id=' + encodeURIComponent({{.GoogleTagID}}));
+{{- end}}
+    })();
+  </script>
+</head>
+<body>
+{{- if .FacebookPixelID}}
+  <noscript><img height="1" width="1" style="display:none" alt="" src="https://www.facebook.com/tr?id={{.FacebookPixelID}}&ev=PageView&noscript=1"></noscript>
+{{- end}}
+  <p><a href="{{.DestinationURL}}">Continue</a></p>
+</body>
+</html>`))
+
+// HasPixels reports whether the link has retargeting pixels to fire before
+// redirecting.
+func (r *ResolveResult) HasPixels() bool {
+	return r.FacebookPixelID != "" || r.GoogleTagID != ""
+}
+
+// WritePixelPage renders the interstitial that fires the link's retargeting
+// pixels and then redirects to destinationURL. The redirect happens shortly
+// after the pixel scripts load and never later than pixelMaxDelay; a meta
+// refresh covers visitors without JavaScript.
+func WritePixelPage(w io.Writer, result *ResolveResult, destinationURL string) error {
+	refresh := int(pixelMaxDelay.Round(time.Second) / time.Second)
+	if refresh < 1 {
+		refresh = 1
+	}
+	return pixelPageTmpl.Execute(w, map[string]any{
+		"DestinationURL":  destinationURL,
+		"FacebookPixelID": result.FacebookPixelID,
+		"GoogleTagID":     result.GoogleTagID,
+		"RefreshSeconds":  refresh,
+		"SettleMS":        pixelSettleDelay.Milliseconds(),
+		"MaxDelayMS":      pixelMaxDelay.Milliseconds(),
+	})
+}
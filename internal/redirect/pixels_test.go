@@ -0,0 +1,45 @@
+package redirect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHasPixels(t *testing.T) {
+	if (&ResolveResult{}).HasPixels() {
+		t.Error("expected no pixels on a plain link")
+	}
+	if !(&ResolveResult{GoogleTagID: "AW-123456789"}).HasPixels() {
+		t.Error("expected pixels with a Google tag")
+	}
+}
+
+func TestWritePixelPage(t *testing.T) {
+	var b strings.Builder
+	result := &ResolveResult{FacebookPixelID: "1234567890123456", GoogleTagID: "AW-123456789"}
+	if err := WritePixelPage(&b, result, "https://example.com/p?a=1&b=2"); err != nil {
+		t.Fatalf("WritePixelPage() error = %v", err)
+	}
+	page := b.String()
+
+	for _, want := range []string{
+		`fbq('init', "1234567890123456")`,
+		`gtag('config', "AW-123456789")`,
+		`content="1;url=https://example.com/p?a=1&amp;b=2"`,
+		"setTimeout(go,  1000 )",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page is missing %s", want)
+		}
+	}
+}
+
+func TestWritePixelPage_OnlyConfiguredPixels(t *testing.T) {
+	var b strings.Builder
+	if err := WritePixelPage(&b, &ResolveResult{GoogleTagID: "G-ABC123"}, "https://example.com/"); err != nil {
+		t.Fatalf("WritePixelPage() error = %v", err)
+	}
+	if strings.Contains(b.String(), "fbevents.js") {
+		t.Error("Facebook pixel loaded without a pixel ID")
+	}
+}
@@ -2,10 +2,13 @@ package redirect
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
 	"go.uber.org/zap"
 )
 
@@ -20,13 +23,60 @@ type ResolveResult struct {
 	PasswordHash   string
 	IsExpired      bool
 	IsOverLimit    bool
+	Title          string
+	Cloak          bool
+	ForwardParams  bool
+	// ParamPrecedence decides whether the destination's or the incoming
+	// request's value wins when a forwarded parameter is already set.
+	ParamPrecedence string
+	// ForceHTTPS upgrades an http:// destination when its host supports HTTPS.
+	ForceHTTPS bool
+	// OncePerVisitor sends visitors who already followed the link to
+	// RepeatVisitURL, or an "already used" page when it is empty.
+	OncePerVisitor bool
+	RepeatVisitURL string
+	ExpiresAt      *time.Time
+	// IOSURL and AndroidURL are app URIs tried on those platforms before
+	// FallbackURL, which replaces DestinationURL when set.
+	IOSURL      string
+	AndroidURL  string
+	FallbackURL string
+	// FacebookPixelID and GoogleTagID are retargeting pixels fired on an
+	// interstitial page before the redirect.
+	FacebookPixelID string
+	GoogleTagID     string
+	// Interstitial shows a "you are leaving" page before redirecting.
+	Interstitial bool
+	// OutsideSchedule is set when the link has a schedule and none of its
+	// windows is open; IsActive is then false. ScheduleFallbackURL is where
+	// visitors go instead, or empty to show the disabled page.
+	OutsideSchedule     bool
+	ScheduleFallbackURL string
+	// PasswordHint is shown on the password form.
+	PasswordHint string
+	// FailoverURLs replace the web destination, in order, while health
+	// checks find it down; see Failover.
+	FailoverURLs []string
+	// Branding styles the workspace's password and error pages; nil uses the
+	// defaults.
+	Branding *models.WorkspaceBranding
+}
+
+// BrandingLookup returns a workspace's branding for the pages the redirect
+// service shows, or nil when it has none.
+type BrandingLookup interface {
+	Branding(ctx context.Context, workspaceID uuid.UUID) *models.WorkspaceBranding
 }
 
 // Resolver resolves short codes to their destination URLs using multi-layer caching.
 type Resolver struct {
-	cache    *Cache
-	linkRepo repository.LinkRepository
-	logger   *zap.Logger
+	cache           *Cache
+	linkRepo        repository.LinkRepository
+	caseInsensitive bool
+	branding        BrandingLookup
+	// now is overridden in tests; nil means time.Now.
+	now    func() time.Time
+	logger *zap.Logger
 }
 
 func NewResolver(cache *Cache, linkRepo repository.LinkRepository, logger *zap.Logger) *Resolver {
@@ -37,8 +87,24 @@ func NewResolver(cache *Cache, linkRepo repository.LinkRepository, logger *zap.L
 	}
 }
 
+// SetCaseInsensitive makes short codes resolve regardless of case by
+// lowercasing them before lookup. Links must be stored with lowercase codes.
+func (r *Resolver) SetCaseInsensitive(enabled bool) {
+	r.caseInsensitive = enabled
+}
+
+// SetBranding has resolve results carry their workspace's branding, looked
+// up through branding. Without it results use the default pages.
+func (r *Resolver) SetBranding(branding BrandingLookup) {
+	r.branding = branding
+}
+
 // Resolve looks up a short code through the cache layers and returns the resolve result.
 func (r *Resolver) Resolve(ctx context.Context, shortCode string) (*ResolveResult, error) {
+	if r.caseInsensitive {
+		shortCode = strings.ToLower(shortCode)
+	}
+
 	// Try cache first (L1 → L2)
 	cached, layer := r.cache.Get(ctx, shortCode)
 	if cached != nil {
@@ -46,7 +112,7 @@ func (r *Resolver) Resolve(ctx context.Context, shortCode string) (*ResolveResul
 			zap.String("short_code", shortCode),
 			zap.Int("layer", layer),
 		)
-		return r.cachedToResult(cached), nil
+		return r.withBranding(ctx, r.cachedToResult(cached)), nil
 	}
 
 	// Cache miss — go to database
@@ -54,20 +120,76 @@ func (r *Resolver) Resolve(ctx context.Context, shortCode string) (*ResolveResul
 	if err != nil {
 		return nil, err
 	}
+	// A reserved code has no destination yet. Reservations aren't cached,
+	// so claiming one needs no invalidation.
+	if link.IsReserved() {
+		return nil, httputil.NotFound("link")
+	}
+
+	cl := newCachedLink(link)
+
+	// Populate caches
+	r.cache.Set(ctx, shortCode, cl)
+
+	return r.withBranding(ctx, r.cachedToResult(cl)), nil
+}
+
+// withBranding sets the result's workspace branding. Branding is cached per
+// workspace rather than in each link's cache entry, so a change applies to
+// every link at once.
+func (r *Resolver) withBranding(ctx context.Context, result *ResolveResult) *ResolveResult {
+	if r.branding != nil {
+		result.Branding = r.branding.Branding(ctx, result.WorkspaceID)
+	}
+	return result
+}
 
-	// Build cached entry
+// newCachedLink builds the cache entry for link.
+func newCachedLink(link *models.Link) *CachedLink {
 	cl := &CachedLink{
-		ID:             link.ID,
-		WorkspaceID:    link.WorkspaceID,
-		ShortCode:      link.ShortCode,
-		DestinationURL: link.URL,
-		IsActive:       link.IsActive,
-		HasPassword:    link.HasPassword,
-		TotalClicks:    link.TotalClicks,
+		ID:              link.ID,
+		WorkspaceID:     link.WorkspaceID,
+		ShortCode:       link.ShortCode,
+		DestinationURL:  link.URL,
+		IsActive:        link.IsActive,
+		HasPassword:     link.HasPassword,
+		TotalClicks:     link.TotalClicks,
+		Cloak:           link.Cloak,
+		ForwardParams:   link.ForwardParams,
+		ParamPrecedence: link.ParamPrecedence,
+		ForceHTTPS:      link.ForceHTTPS,
+		OncePerVisitor:  link.OncePerVisitor,
+		Interstitial:    link.Interstitial,
+		Schedule:        link.Schedule,
+		FailoverURLs:    link.FailoverURLs,
+	}
+	if link.Title != nil {
+		cl.Title = *link.Title
 	}
 	if link.PasswordHash != nil {
 		cl.PasswordHash = *link.PasswordHash
 	}
+	if link.PasswordHint != nil {
+		cl.PasswordHint = *link.PasswordHint
+	}
+	if link.RepeatVisitURL != nil {
+		cl.RepeatVisitURL = *link.RepeatVisitURL
+	}
+	if link.IOSURL != nil {
+		cl.IOSURL = *link.IOSURL
+	}
+	if link.AndroidURL != nil {
+		cl.AndroidURL = *link.AndroidURL
+	}
+	if link.FallbackURL != nil {
+		cl.FallbackURL = *link.FallbackURL
+	}
+	if link.FacebookPixelID != nil {
+		cl.FacebookPixelID = *link.FacebookPixelID
+	}
+	if link.GoogleTagID != nil {
+		cl.GoogleTagID = *link.GoogleTagID
+	}
 	if link.ExpiresAt != nil {
 		ts := link.ExpiresAt.Unix()
 		cl.ExpiresAt = &ts
@@ -75,27 +197,77 @@ func (r *Resolver) Resolve(ctx context.Context, shortCode string) (*ResolveResul
 	if link.MaxClicks != nil {
 		cl.MaxClicks = link.MaxClicks
 	}
+	return cl
+}
 
-	// Populate caches
-	r.cache.Set(ctx, shortCode, cl)
-
-	return r.cachedToResult(cl), nil
+// Warm preloads up to limit of the most clicked redirectable links into both
+// cache layers, so a freshly started service doesn't send every hot short
+// code to the database at once. It returns how many links were cached.
+func (r *Resolver) Warm(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+	links, err := r.linkRepo.ListMostClicked(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, link := range links {
+		shortCode := link.ShortCode
+		if r.caseInsensitive {
+			shortCode = strings.ToLower(shortCode)
+		}
+		r.cache.Set(ctx, shortCode, newCachedLink(link))
+	}
+	return len(links), nil
 }
 
 func (r *Resolver) cachedToResult(cl *CachedLink) *ResolveResult {
+	now := time.Now()
+	if r.now != nil {
+		now = r.now()
+	}
+	return resultAt(cl, now)
+}
+
+// resultAt builds the resolve result for a cached link at the time now.
+func resultAt(cl *CachedLink, now time.Time) *ResolveResult {
 	result := &ResolveResult{
-		LinkID:         cl.ID,
-		WorkspaceID:    cl.WorkspaceID,
-		ShortCode:      cl.ShortCode,
-		DestinationURL: cl.DestinationURL,
-		IsActive:       cl.IsActive,
-		HasPassword:    cl.HasPassword,
-		PasswordHash:   cl.PasswordHash,
+		LinkID:          cl.ID,
+		WorkspaceID:     cl.WorkspaceID,
+		ShortCode:       cl.ShortCode,
+		DestinationURL:  cl.DestinationURL,
+		IsActive:        cl.IsActive,
+		HasPassword:     cl.HasPassword,
+		PasswordHash:    cl.PasswordHash,
+		PasswordHint:    cl.PasswordHint,
+		Title:           cl.Title,
+		Cloak:           cl.Cloak,
+		ForwardParams:   cl.ForwardParams,
+		ParamPrecedence: cl.ParamPrecedence,
+		ForceHTTPS:      cl.ForceHTTPS,
+		OncePerVisitor:  cl.OncePerVisitor,
+		RepeatVisitURL:  cl.RepeatVisitURL,
+		IOSURL:          cl.IOSURL,
+		AndroidURL:      cl.AndroidURL,
+		FallbackURL:     cl.FallbackURL,
+		FacebookPixelID: cl.FacebookPixelID,
+		GoogleTagID:     cl.GoogleTagID,
+		Interstitial:    cl.Interstitial,
+		FailoverURLs:    cl.FailoverURLs,
 	}
 
 	// Check expiration
 	if cl.ExpiresAt != nil {
-		result.IsExpired = time.Now().Unix() > *cl.ExpiresAt
+		expiresAt := time.Unix(*cl.ExpiresAt, 0)
+		result.ExpiresAt = &expiresAt
+		result.IsExpired = now.Unix() > *cl.ExpiresAt
+	}
+
+	// Check the schedule. It only ever turns an active link off.
+	if cl.IsActive && !cl.Schedule.ActiveAt(now) {
+		result.IsActive = false
+		result.OutsideSchedule = true
+		result.ScheduleFallbackURL = cl.Schedule.FallbackURL
 	}
 
 	// Check click limit
@@ -16,6 +16,7 @@ import (
 
 type mockLinkRepo struct {
 	getByShortCodeFn func(ctx context.Context, shortCode string) (*models.Link, error)
+	mostClicked      []*models.Link
 }
 
 func (m *mockLinkRepo) Create(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
@@ -39,16 +40,80 @@ func (m *mockLinkRepo) List(_ context.Context, _ sqlc.ListLinksForWorkspaceParam
 func (m *mockLinkRepo) Update(_ context.Context, _ sqlc.UpdateLinkParams) (*models.Link, error) {
 	return nil, nil
 }
+func (m *mockLinkRepo) UpdateMetadata(_ context.Context, _ sqlc.UpdateLinkMetadataParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) Transfer(_ context.Context, _ sqlc.TransferLinkParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) Archive(_ context.Context, _ uuid.UUID, _ bool) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) Unarchive(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+	return nil, nil
+}
 func (m *mockLinkRepo) SoftDelete(_ context.Context, _ uuid.UUID) error   { return nil }
+func (m *mockLinkRepo) SoftDeleteForWorkspace(_ context.Context, _ uuid.UUID) (int64, error) {
+	return 0, nil
+}
+func (m *mockLinkRepo) Reserve(_ context.Context, _ sqlc.ReserveShortCodeParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) GetReservation(_ context.Context, _ uuid.UUID, _ string) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) ClaimReservation(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) ReleaseExpiredReservation(_ context.Context, _ string) error { return nil }
+func (m *mockLinkRepo) ReleaseExpiredReservations(_ context.Context) (int64, error) {
+	return 0, nil
+}
 func (m *mockLinkRepo) ShortCodeExists(_ context.Context, _ string) (bool, error) {
 	return false, nil
 }
+func (m *mockLinkRepo) ShortCodeExistsIgnoreCase(_ context.Context, _ string) (bool, error) {
+	return false, nil
+}
 func (m *mockLinkRepo) IncrementClicks(_ context.Context, _ uuid.UUID) error       { return nil }
 func (m *mockLinkRepo) IncrementUniqueClicks(_ context.Context, _ uuid.UUID) error { return nil }
+func (m *mockLinkRepo) AddClicks(_ context.Context, _ uuid.UUID, _ int64) error    { return nil }
+func (m *mockLinkRepo) ReconcileUniqueClicks(_ context.Context, _ time.Time, _ time.Duration) (int64, error) {
+	return 0, nil
+}
 func (m *mockLinkRepo) GetQuickStats(_ context.Context, _ uuid.UUID) (*models.LinkQuickStats, error) {
 	return nil, nil
 }
-func (m *mockLinkRepo) GetCountForWorkspace(_ context.Context, _ uuid.UUID) (int64, error) {
+func (m *mockLinkRepo) CountCreatedForWorkspace(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockLinkRepo) LockLinkLimit(_ context.Context, _ uuid.UUID) error {
+	return nil
+}
+func (m *mockLinkRepo) ListIDsByTag(_ context.Context, _, _ uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (m *mockLinkRepo) AddTag(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
+
+func (m *mockLinkRepo) ListWithFailovers(_ context.Context, _ uuid.UUID, _ int) ([]*models.Link, error) {
+	return nil, nil
+}
+
+func (m *mockLinkRepo) ListMostClicked(_ context.Context, limit int) ([]*models.Link, error) {
+	if len(m.mostClicked) > limit {
+		return m.mostClicked[:limit], nil
+	}
+	return m.mostClicked, nil
+}
+
+func (m *mockLinkRepo) EnsureTag(_ context.Context, _ uuid.UUID, _ string) (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+func (m *mockLinkRepo) CountWorkspaceTags(_ context.Context, _ uuid.UUID, _ []uuid.UUID) (int64, error) {
 	return 0, nil
 }
 
@@ -56,7 +121,7 @@ func (m *mockLinkRepo) GetCountForWorkspace(_ context.Context, _ uuid.UUID) (int
 
 func TestResolver_CacheHit(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	cache := &Cache{l1TTL: 5 * time.Minute}
+	cache := newL1Cache(5 * time.Minute)
 	repo := &mockLinkRepo{}
 
 	link := &CachedLink{
@@ -95,7 +160,7 @@ func TestResolver_CacheMiss_DBHit(t *testing.T) {
 
 	// Use a custom resolver that bypasses L2 cache (no Redis in unit tests).
 	// We test by pre-populating the L1 cache miss and directly calling the resolver.
-	cache := &Cache{l1TTL: 5 * time.Minute}
+	cache := newL1Cache(5 * time.Minute)
 	resolver := &Resolver{
 		cache:    cache,
 		linkRepo: repo,
@@ -136,7 +201,7 @@ func TestResolver_CacheMiss_DBHit(t *testing.T) {
 
 func TestResolver_NotFound(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	cache := &Cache{l1TTL: 5 * time.Minute}
+	cache := newL1Cache(5 * time.Minute)
 
 	repo := &mockLinkRepo{
 		getByShortCodeFn: func(_ context.Context, _ string) (*models.Link, error) {
@@ -152,9 +217,138 @@ func TestResolver_NotFound(t *testing.T) {
 	}
 }
 
+func TestResolver_ReservedIsNotFound(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	cache := newL1Cache(5 * time.Minute)
+
+	reservedUntil := time.Now().Add(time.Hour)
+	repo := &mockLinkRepo{
+		getByShortCodeFn: func(_ context.Context, code string) (*models.Link, error) {
+			return &models.Link{ID: uuid.New(), ShortCode: code, ReservedUntil: &reservedUntil}, nil
+		},
+	}
+
+	resolver := NewResolver(cache, repo, logger)
+
+	if _, err := resolver.Resolve(context.Background(), "launch"); err == nil {
+		t.Fatal("expected error for a reserved short code")
+	}
+	if cl, _ := cache.Get(context.Background(), "launch"); cl != nil {
+		t.Error("reservation was cached")
+	}
+}
+
+type stubBranding map[uuid.UUID]*models.WorkspaceBranding
+
+func (b stubBranding) Branding(_ context.Context, workspaceID uuid.UUID) *models.WorkspaceBranding {
+	return b[workspaceID]
+}
+
+func TestResolver_PasswordHintAndBranding(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	cache := newL1Cache(5 * time.Minute)
+
+	workspaceID := uuid.New()
+	hint := "the usual one"
+	hash := "hashed"
+	repo := &mockLinkRepo{
+		getByShortCodeFn: func(_ context.Context, code string) (*models.Link, error) {
+			return &models.Link{
+				ID:           uuid.New(),
+				WorkspaceID:  workspaceID,
+				ShortCode:    code,
+				URL:          "https://example.com",
+				IsActive:     true,
+				PasswordHash: &hash,
+				HasPassword:  true,
+				PasswordHint: &hint,
+			}, nil
+		},
+	}
+	branding := &models.WorkspaceBranding{LogoURL: "https://cdn.example.com/logo.png", AccentColor: "#0f766e"}
+
+	resolver := NewResolver(cache, repo, logger)
+	resolver.SetBranding(stubBranding{workspaceID: branding})
+
+	// The second resolve comes from the cache
+	for range 2 {
+		result, err := resolver.Resolve(context.Background(), "secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.PasswordHint != hint {
+			t.Errorf("password hint = %q, want %q", result.PasswordHint, hint)
+		}
+		if result.Branding != branding {
+			t.Errorf("branding = %+v, want the workspace's", result.Branding)
+		}
+	}
+}
+
+func TestResolver_CaseInsensitive(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	cache := newL1Cache(5 * time.Minute)
+
+	var lookedUp []string
+	repo := &mockLinkRepo{
+		getByShortCodeFn: func(_ context.Context, code string) (*models.Link, error) {
+			lookedUp = append(lookedUp, code)
+			return &models.Link{ID: uuid.New(), ShortCode: code, URL: "https://example.com", IsActive: true}, nil
+		},
+	}
+
+	resolver := NewResolver(cache, repo, logger)
+	resolver.SetCaseInsensitive(true)
+
+	for _, code := range []string{"MyLink", "mylink", "MYLINK"} {
+		if _, err := resolver.Resolve(context.Background(), code); err != nil {
+			t.Fatalf("Resolve(%q) error = %v", code, err)
+		}
+	}
+	if len(lookedUp) != 1 || lookedUp[0] != "mylink" {
+		t.Errorf("expected one lookup of the lowercased code, got %v", lookedUp)
+	}
+}
+
+func TestResolver_Warm(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	cache := newL1Cache(5 * time.Minute)
+	repo := &mockLinkRepo{
+		getByShortCodeFn: func(_ context.Context, _ string) (*models.Link, error) {
+			t.Error("warmed link was looked up in the database")
+			return nil, httputil.NotFound("link")
+		},
+		mostClicked: []*models.Link{
+			{ID: uuid.New(), ShortCode: "hot", URL: "https://example.com/hot", IsActive: true, TotalClicks: 900},
+			{ID: uuid.New(), ShortCode: "warm", URL: "https://example.com/warm", IsActive: true, TotalClicks: 500},
+			{ID: uuid.New(), ShortCode: "cool", URL: "https://example.com/cool", IsActive: true, TotalClicks: 10},
+		},
+	}
+	resolver := NewResolver(cache, repo, logger)
+
+	warmed, err := resolver.Warm(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if warmed != 2 {
+		t.Errorf("warmed %d links, want 2", warmed)
+	}
+
+	result, err := resolver.Resolve(context.Background(), "warm")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if result.DestinationURL != "https://example.com/warm" {
+		t.Errorf("destination = %q", result.DestinationURL)
+	}
+	if _, ok := cache.GetL1("cool"); ok {
+		t.Error("link past the limit was cached")
+	}
+}
+
 func TestResolver_ExpiredLink(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	cache := &Cache{l1TTL: 5 * time.Minute}
+	cache := newL1Cache(5 * time.Minute)
 
 	past := time.Now().Add(-1 * time.Hour).Unix()
 	link := &CachedLink{
@@ -179,7 +373,7 @@ func TestResolver_ExpiredLink(t *testing.T) {
 
 func TestResolver_OverClickLimit(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	cache := &Cache{l1TTL: 5 * time.Minute}
+	cache := newL1Cache(5 * time.Minute)
 
 	maxClicks := int32(100)
 	link := &CachedLink{
@@ -205,7 +399,7 @@ func TestResolver_OverClickLimit(t *testing.T) {
 
 func TestResolver_InvalidateCache(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	cache := &Cache{l1TTL: 5 * time.Minute}
+	cache := newL1Cache(5 * time.Minute)
 
 	link := &CachedLink{
 		ID:             uuid.New(),
@@ -228,7 +422,7 @@ func TestResolver_InvalidateCache(t *testing.T) {
 
 func BenchmarkResolverResolve_CacheHit(b *testing.B) {
 	logger := zap.NewNop()
-	cache := &Cache{l1TTL: 5 * time.Minute}
+	cache := newL1Cache(5 * time.Minute)
 
 	link := &CachedLink{
 		ID:             uuid.New(),
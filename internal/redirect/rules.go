@@ -68,26 +68,31 @@ func (re *RuleEngine) parseCondition(raw json.RawMessage) string {
 }
 
 func (re *RuleEngine) matchRule(rule sqlc.LinkRule, ua string, r *http.Request) bool {
-	switch rule.RuleType {
+	return MatchRule(rule.RuleType, re.parseCondition(rule.Conditions), ua)
+}
+
+// MatchRule reports whether a rule of ruleType with the condition value
+// matches a visitor with the given User-Agent.
+func MatchRule(ruleType, value, ua string) bool {
+	if value == "" {
+		return false
+	}
+	uaLower := strings.ToLower(ua)
+	condLower := strings.ToLower(value)
+
+	switch ruleType {
 	case "device":
-		return re.matchDevice(rule, ua)
+		return matchDevice(condLower, uaLower)
 	case "browser":
-		return re.matchBrowser(rule, ua)
+		return matchBrowser(condLower, uaLower)
 	case "os":
-		return re.matchOS(rule, ua)
+		return matchOS(condLower, uaLower)
 	default:
 		return false
 	}
 }
 
-func (re *RuleEngine) matchDevice(rule sqlc.LinkRule, ua string) bool {
-	condValue := re.parseCondition(rule.Conditions)
-	if condValue == "" {
-		return false
-	}
-	uaLower := strings.ToLower(ua)
-	condLower := strings.ToLower(condValue)
-
+func matchDevice(condLower, uaLower string) bool {
 	switch condLower {
 	case "mobile":
 		return strings.Contains(uaLower, "mobile") || strings.Contains(uaLower, "android") || strings.Contains(uaLower, "iphone")
@@ -100,14 +105,7 @@ func (re *RuleEngine) matchDevice(rule sqlc.LinkRule, ua string) bool {
 	}
 }
 
-func (re *RuleEngine) matchBrowser(rule sqlc.LinkRule, ua string) bool {
-	condValue := re.parseCondition(rule.Conditions)
-	if condValue == "" {
-		return false
-	}
-	uaLower := strings.ToLower(ua)
-	condLower := strings.ToLower(condValue)
-
+func matchBrowser(condLower, uaLower string) bool {
 	switch condLower {
 	case "chrome":
 		return strings.Contains(uaLower, "chrome") && !strings.Contains(uaLower, "edg")
@@ -122,14 +120,7 @@ func (re *RuleEngine) matchBrowser(rule sqlc.LinkRule, ua string) bool {
 	}
 }
 
-func (re *RuleEngine) matchOS(rule sqlc.LinkRule, ua string) bool {
-	condValue := re.parseCondition(rule.Conditions)
-	if condValue == "" {
-		return false
-	}
-	uaLower := strings.ToLower(ua)
-	condLower := strings.ToLower(condValue)
-
+func matchOS(condLower, uaLower string) bool {
 	switch condLower {
 	case "windows":
 		return strings.Contains(uaLower, "windows")
@@ -0,0 +1,114 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// sampleRateKeyPrefix keys each workspace's cached click sample rate.
+	// The API deletes the key when the workspace's setting changes.
+	sampleRateKeyPrefix = "workspace:click_sample_rate:"
+	sampleRateRedisTTL  = 10 * time.Minute
+	sampleRateLocalTTL  = 30 * time.Second
+)
+
+// WorkspaceLookup loads a workspace. repository.WorkspaceRepository
+// implements it.
+type WorkspaceLookup interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
+}
+
+type sampleRateEntry struct {
+	rate      int
+	expiresAt time.Time
+}
+
+// ClickSampler decides, per click, whether to record a click event for
+// workspaces that sample clicks. Rates are read from workspace settings and
+// cached in Redis and in memory, so the decision rarely leaves the process.
+type ClickSampler struct {
+	workspaces WorkspaceLookup
+	redis      *redis.Client
+	local      sync.Map // uuid.UUID -> sampleRateEntry
+	logger     *zap.Logger
+	intN       func(n int) int
+}
+
+func NewClickSampler(workspaces WorkspaceLookup, redisClient *redis.Client, logger *zap.Logger) *ClickSampler {
+	return &ClickSampler{
+		workspaces: workspaces,
+		redis:      redisClient,
+		logger:     logger,
+		intN:       rand.IntN,
+	}
+}
+
+// Sample returns the workspace's sample rate and whether this click should
+// be recorded as a click event: always at rate 1, otherwise one time in
+// rate. If the rate can't be looked up every click is recorded.
+func (s *ClickSampler) Sample(ctx context.Context, workspaceID uuid.UUID) (int, bool) {
+	rate := s.rate(ctx, workspaceID)
+	if rate <= 1 {
+		return 1, true
+	}
+	return rate, s.intN(rate) == 0
+}
+
+func (s *ClickSampler) rate(ctx context.Context, workspaceID uuid.UUID) int {
+	if v, ok := s.local.Load(workspaceID); ok {
+		if entry := v.(sampleRateEntry); time.Now().Before(entry.expiresAt) {
+			return entry.rate
+		}
+	}
+
+	// A failed lookup records every click until the entry expires, rather
+	// than retrying on every click.
+	rate, ok := s.loadRate(ctx, workspaceID)
+	if !ok {
+		rate = 1
+	}
+	s.local.Store(workspaceID, sampleRateEntry{rate: rate, expiresAt: time.Now().Add(sampleRateLocalTTL)})
+	return rate
+}
+
+// loadRate reads the rate from Redis, falling back to the workspace
+// settings and caching them in Redis. It reports false if neither works.
+func (s *ClickSampler) loadRate(ctx context.Context, workspaceID uuid.UUID) (int, bool) {
+	key := sampleRateKeyPrefix + workspaceID.String()
+	if s.redis != nil {
+		cached, err := s.redis.Get(ctx, key).Int()
+		if err == nil {
+			return cached, true
+		}
+		if !errors.Is(err, redis.Nil) {
+			s.logger.Warn("click sample rate lookup failed", zap.Error(err))
+		}
+	}
+
+	ws, err := s.workspaces.GetByID(ctx, workspaceID)
+	if err != nil {
+		s.logger.Warn("failed to load workspace for click sampling",
+			zap.String("workspace_id", workspaceID.String()),
+			zap.Error(err),
+		)
+		return 0, false
+	}
+	rate := ws.ParsedSettings().ClickSampleRate()
+
+	if s.redis != nil {
+		if err := s.redis.Set(ctx, key, strconv.Itoa(rate), sampleRateRedisTTL).Err(); err != nil {
+			s.logger.Warn("failed to cache click sample rate", zap.Error(err))
+		}
+	}
+	return rate, true
+}
@@ -0,0 +1,73 @@
+package redirect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+type fakeWorkspaceLookup struct {
+	settings models.WorkspaceSettings
+	err      error
+	calls    int
+}
+
+func (f *fakeWorkspaceLookup) GetByID(_ context.Context, id uuid.UUID) (*models.Workspace, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	raw, _ := json.Marshal(f.settings)
+	return &models.Workspace{ID: id, Settings: raw}, nil
+}
+
+func TestClickSampler_Sample(t *testing.T) {
+	lookup := &fakeWorkspaceLookup{settings: models.WorkspaceSettings{
+		ClickSampling: &models.WorkspaceClickSampling{Rate: 10},
+	}}
+	s := NewClickSampler(lookup, nil, zap.NewNop())
+	next := 0
+	s.intN = func(n int) int { return next % n }
+	wsID := uuid.New()
+
+	next = 0
+	if rate, record := s.Sample(context.Background(), wsID); rate != 10 || !record {
+		t.Errorf("Sample() = %d, %v, want 10, true", rate, record)
+	}
+	next = 3
+	if rate, record := s.Sample(context.Background(), wsID); rate != 10 || record {
+		t.Errorf("Sample() = %d, %v, want 10, false", rate, record)
+	}
+	if lookup.calls != 1 {
+		t.Errorf("workspace looked up %d times, want once while cached", lookup.calls)
+	}
+}
+
+func TestClickSampler_RecordsEverythingByDefault(t *testing.T) {
+	s := NewClickSampler(&fakeWorkspaceLookup{}, nil, zap.NewNop())
+	s.intN = func(int) int { t.Fatal("unsampled workspace should not draw"); return 0 }
+
+	if rate, record := s.Sample(context.Background(), uuid.New()); rate != 1 || !record {
+		t.Errorf("Sample() = %d, %v, want 1, true", rate, record)
+	}
+}
+
+func TestClickSampler_LookupFailureRecords(t *testing.T) {
+	lookup := &fakeWorkspaceLookup{err: errors.New("db down")}
+	s := NewClickSampler(lookup, nil, zap.NewNop())
+	wsID := uuid.New()
+
+	for i := 0; i < 3; i++ {
+		if _, record := s.Sample(context.Background(), wsID); !record {
+			t.Fatal("expected clicks to be recorded when the rate can't be loaded")
+		}
+	}
+	if lookup.calls != 1 {
+		t.Errorf("workspace looked up %d times, want the failure cached", lookup.calls)
+	}
+}
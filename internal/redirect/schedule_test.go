@@ -0,0 +1,127 @@
+package redirect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestLinkSchedule_ActiveAt(t *testing.T) {
+	businessHours := &models.LinkSchedule{
+		Timezone: "America/New_York",
+		Windows: []models.LinkScheduleWindow{
+			{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "17:00"},
+		},
+	}
+	fridayNight := &models.LinkSchedule{
+		Windows: []models.LinkScheduleWindow{{Days: []string{"Fri"}, Start: "22:00", End: "02:00"}},
+	}
+	saturday := &models.LinkSchedule{
+		Windows: []models.LinkScheduleWindow{{Days: []string{"sat"}, Start: "00:00", End: "00:00"}},
+	}
+
+	// 2026-10-16 is a Friday; New York is on UTC-4.
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 10, day, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		schedule *models.LinkSchedule
+		t        time.Time
+		want     bool
+	}{
+		{"no schedule", nil, at(16, 3, 0), true},
+		{"business hours, friday morning", businessHours, at(16, 14, 0), true},
+		{"business hours, before opening", businessHours, at(16, 12, 30), false},
+		{"business hours, end is exclusive", businessHours, at(16, 21, 0), false},
+		{"business hours, saturday", businessHours, at(17, 14, 0), false},
+		{"business hours, friday evening UTC is still friday in new york", businessHours, at(16, 20, 59), true},
+		{"overnight, friday evening", fridayNight, at(16, 23, 0), true},
+		{"overnight, early saturday", fridayNight, at(17, 1, 59), true},
+		{"overnight, after closing", fridayNight, at(17, 2, 0), false},
+		{"overnight, early friday belongs to thursday", fridayNight, at(16, 1, 0), false},
+		{"whole day", saturday, at(17, 23, 59), true},
+		{"whole day, other day", saturday, at(18, 0, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.ActiveAt(tt.t); got != tt.want {
+				t.Errorf("ActiveAt(%s) = %v, want %v", tt.t.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkSchedule_Validate(t *testing.T) {
+	window := models.LinkScheduleWindow{Start: "09:00", End: "17:00"}
+	tests := []struct {
+		name     string
+		schedule models.LinkSchedule
+		wantErr  bool
+	}{
+		{"valid", models.LinkSchedule{Timezone: "Europe/Berlin", Windows: []models.LinkScheduleWindow{window}}, false},
+		{"unknown timezone", models.LinkSchedule{Timezone: "Mars/Olympus", Windows: []models.LinkScheduleWindow{window}}, true},
+		{"local timezone", models.LinkSchedule{Timezone: "Local", Windows: []models.LinkScheduleWindow{window}}, true},
+		{"unknown day", models.LinkSchedule{Windows: []models.LinkScheduleWindow{{Days: []string{"funday"}, Start: "09:00", End: "17:00"}}}, true},
+		{"bad start", models.LinkSchedule{Windows: []models.LinkScheduleWindow{{Start: "25:00", End: "17:00"}}}, true},
+		{"bad end", models.LinkSchedule{Windows: []models.LinkScheduleWindow{{Start: "09:00", End: "5pm"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schedule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolver_Schedule(t *testing.T) {
+	cache := newL1Cache(5 * time.Minute)
+	schedule := &models.LinkSchedule{
+		Windows:     []models.LinkScheduleWindow{{Start: "09:00", End: "17:00"}},
+		FallbackURL: "https://example.com/closed",
+	}
+	cache.SetL1("store", &CachedLink{
+		ID:             uuid.New(),
+		ShortCode:      "store",
+		DestinationURL: "https://example.com",
+		IsActive:       true,
+		Schedule:       schedule,
+	})
+	cache.SetL1("off", &CachedLink{
+		ID:             uuid.New(),
+		ShortCode:      "off",
+		DestinationURL: "https://example.com",
+		Schedule:       schedule,
+	})
+
+	resolver := NewResolver(cache, nil, zap.NewNop())
+
+	resolver.now = func() time.Time { return time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC) }
+	result, err := resolver.Resolve(context.Background(), "store")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsActive || result.OutsideSchedule {
+		t.Errorf("inside window: IsActive = %v, OutsideSchedule = %v", result.IsActive, result.OutsideSchedule)
+	}
+
+	resolver.now = func() time.Time { return time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC) }
+	result, _ = resolver.Resolve(context.Background(), "store")
+	if result.IsActive || !result.OutsideSchedule {
+		t.Errorf("outside window: IsActive = %v, OutsideSchedule = %v", result.IsActive, result.OutsideSchedule)
+	}
+	if result.ScheduleFallbackURL != "https://example.com/closed" {
+		t.Errorf("ScheduleFallbackURL = %q", result.ScheduleFallbackURL)
+	}
+
+	// A disabled link stays disabled rather than following the fallback.
+	result, _ = resolver.Resolve(context.Background(), "off")
+	if result.IsActive || result.OutsideSchedule {
+		t.Errorf("disabled link: IsActive = %v, OutsideSchedule = %v", result.IsActive, result.OutsideSchedule)
+	}
+}
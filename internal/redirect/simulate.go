@@ -0,0 +1,145 @@
+package redirect
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+// simulatedPlatforms is the User-Agent platform part used for each OS a
+// simulated visitor can have, by device.
+var simulatedPlatforms = map[string]map[string]string{
+	"windows": {"desktop": "Windows NT 10.0; Win64; x64"},
+	"macos":   {"desktop": "Macintosh; Intel Mac OS X 10_15_7"},
+	"linux":   {"desktop": "X11; Linux x86_64"},
+	"ios": {
+		"mobile": "iPhone; CPU iPhone OS 17_4 like Mac OS X",
+		"tablet": "iPad; CPU OS 17_4 like Mac OS X",
+	},
+	"android": {
+		"mobile": "Linux; Android 14; Pixel 8",
+		"tablet": "Linux; Android 14; SM-X710",
+	},
+}
+
+// SimulatedUserAgent returns a representative User-Agent for a visitor with
+// the given device, OS and browser, which take rule condition values. Empty
+// values default to what's most common for the others. It reports false for
+// unknown values and combinations that don't exist, like Safari on Windows.
+func SimulatedUserAgent(device, os, browser string) (string, bool) {
+	device = strings.ToLower(strings.TrimSpace(device))
+	os = strings.ToLower(strings.TrimSpace(os))
+	browser = strings.ToLower(strings.TrimSpace(browser))
+
+	if os == "mac" {
+		os = "macos"
+	}
+	if os == "" {
+		switch device {
+		case "mobile":
+			os = "android"
+		case "tablet":
+			os = "ios"
+		default:
+			os = "windows"
+		}
+	}
+	if device == "" {
+		device = "desktop"
+		if os == "ios" || os == "android" {
+			device = "mobile"
+		}
+	}
+	if browser == "" {
+		browser = "chrome"
+		if os == "ios" || os == "macos" {
+			browser = "safari"
+		}
+	}
+
+	platform, ok := simulatedPlatforms[os][device]
+	if !ok {
+		return "", false
+	}
+
+	var product string
+	switch browser {
+	case "chrome", "edge":
+		product = "AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0"
+		if device == "mobile" {
+			product += " Mobile"
+		}
+		product += " Safari/537.36"
+		if browser == "edge" {
+			product += " Edg/124.0.0.0"
+		}
+	case "firefox":
+		platform += "; rv:125.0"
+		product = "Gecko/20100101 Firefox/125.0"
+	case "safari":
+		switch os {
+		case "ios":
+			product = "AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+		case "macos":
+			product = "AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15"
+		default:
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	return "Mozilla/5.0 (" + platform + ") " + product, true
+}
+
+// Simulate works out where the redirect service would send a visitor with
+// the given User-Agent who opens link with query, at the time now. rules
+// are the link's rules in priority order; inactive ones are skipped. Nothing
+// is recorded, and checks that depend on the visitor's history, like
+// once-per-visitor links, assume a first visit. The HTTPS upgrade of links
+// that force it needs a probe, so destinations are returned without it.
+func Simulate(link *models.Link, rules []*models.LinkRule, userAgent string, query url.Values, now time.Time) *models.RedirectSimulation {
+	result := resultAt(newCachedLink(link), now)
+	sim := &models.RedirectSimulation{UserAgent: userAgent}
+
+	// Same order of checks as the redirect handler
+	switch {
+	case result.OutsideSchedule && result.ScheduleFallbackURL != "":
+		sim.Outcome = models.SimulationScheduleFallback
+		sim.DestinationURL = result.ScheduleFallbackURL
+		return sim
+	case result.OutsideSchedule:
+		sim.Outcome = models.SimulationUnavailable
+		return sim
+	case !result.IsActive:
+		sim.Outcome = models.SimulationDisabled
+		return sim
+	case result.IsExpired:
+		sim.Outcome = models.SimulationExpired
+		return sim
+	case result.IsOverLimit:
+		sim.Outcome = models.SimulationLimitReached
+		return sim
+	}
+
+	sim.Outcome = models.SimulationRedirect
+	sim.Interstitial = result.Interstitial
+	sim.PasswordRequired = result.HasPassword
+
+	destinationURL := result.WebDestination()
+	for _, rule := range rules {
+		if rule.IsActive && MatchRule(rule.RuleType, rule.Value, userAgent) {
+			destinationURL = rule.DestinationURL
+			ruleID := rule.ID
+			sim.MatchedRuleID = &ruleID
+			break
+		}
+	}
+	if result.ForwardParams {
+		destinationURL = ForwardQuery(destinationURL, forwardable(query), result.ParamPrecedence)
+	}
+	sim.DestinationURL = destinationURL
+	sim.AppURL = result.AppURL(userAgent)
+	return sim
+}
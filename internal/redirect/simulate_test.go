@@ -0,0 +1,100 @@
+package redirect
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+func TestSimulatedUserAgent(t *testing.T) {
+	tests := []struct {
+		device, os, browser string
+		wantDevice          string
+		wantOS              string
+		wantBrowser         string
+	}{
+		{"", "", "", "desktop", "Windows", "Chrome"},
+		{"mobile", "", "", "mobile", "Android", "Chrome"},
+		{"", "ios", "", "mobile", "iOS", "Safari"},
+		{"tablet", "ios", "", "tablet", "iOS", "Safari"},
+		{"desktop", "mac", "firefox", "desktop", "macOS", "Firefox"},
+		{"desktop", "linux", "edge", "desktop", "Linux", "Edge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.device+"/"+tt.os+"/"+tt.browser, func(t *testing.T) {
+			ua, ok := SimulatedUserAgent(tt.device, tt.os, tt.browser)
+			if !ok {
+				t.Fatal("expected a User-Agent")
+			}
+			if got := ParseDeviceType(ua); got != tt.wantDevice {
+				t.Errorf("device = %q, want %q", got, tt.wantDevice)
+			}
+			if got, _ := ParseOS(ua); got != tt.wantOS {
+				t.Errorf("os = %q, want %q", got, tt.wantOS)
+			}
+			if got, _ := ParseBrowser(ua); got != tt.wantBrowser {
+				t.Errorf("browser = %q, want %q", got, tt.wantBrowser)
+			}
+		})
+	}
+
+	for _, bad := range [][3]string{{"desktop", "ios", ""}, {"", "windows", "safari"}, {"watch", "", ""}} {
+		if _, ok := SimulatedUserAgent(bad[0], bad[1], bad[2]); ok {
+			t.Errorf("SimulatedUserAgent%v should fail", bad)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	link := &models.Link{
+		ShortCode:     "launch",
+		URL:           "https://example.com/page?ref=link",
+		IsActive:      true,
+		ForwardParams: true,
+	}
+	mobileRule := &models.LinkRule{
+		ID:             uuid.New(),
+		RuleType:       "device",
+		Value:          "mobile",
+		DestinationURL: "https://m.example.com/page",
+		IsActive:       true,
+	}
+	inactiveRule := &models.LinkRule{
+		ID:             uuid.New(),
+		RuleType:       "os",
+		Value:          "windows",
+		DestinationURL: "https://example.com/windows",
+	}
+	rules := []*models.LinkRule{inactiveRule, mobileRule}
+	query := url.Values{"utm_source": {"newsletter"}, HopParam: {"1"}}
+
+	desktop, _ := SimulatedUserAgent("desktop", "windows", "")
+	sim := Simulate(link, rules, desktop, query, time.Now())
+	if sim.Outcome != models.SimulationRedirect || sim.MatchedRuleID != nil {
+		t.Fatalf("simulation = %+v", sim)
+	}
+	if want := "https://example.com/page?ref=link&utm_source=newsletter"; sim.DestinationURL != want {
+		t.Errorf("destination = %q, want %q", sim.DestinationURL, want)
+	}
+
+	mobile, _ := SimulatedUserAgent("mobile", "", "")
+	sim = Simulate(link, rules, mobile, nil, time.Now())
+	if sim.MatchedRuleID == nil || *sim.MatchedRuleID != mobileRule.ID {
+		t.Errorf("matched rule = %v, want the mobile rule", sim.MatchedRuleID)
+	}
+	if sim.DestinationURL != mobileRule.DestinationURL {
+		t.Errorf("destination = %q, want %q", sim.DestinationURL, mobileRule.DestinationURL)
+	}
+
+	expired := *link
+	past := time.Now().Add(-time.Hour)
+	expired.ExpiresAt = &past
+	sim = Simulate(&expired, rules, mobile, nil, time.Now())
+	if sim.Outcome != models.SimulationExpired || sim.DestinationURL != "" {
+		t.Errorf("expired link simulation = %+v", sim)
+	}
+}
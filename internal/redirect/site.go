@@ -0,0 +1,79 @@
+package redirect
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// DefaultRobotsTxt keeps crawlers off every path on the redirect host, so
+// short links are neither indexed nor counted as crawler clicks.
+const DefaultRobotsTxt = "User-agent: *\nDisallow: /\n"
+
+// SiteRoutes serves the redirect host's paths that aren't short links: the
+// bare root, robots.txt and favicon.ico. Unknown paths are answered by
+// NotFoundPolicies.
+type SiteRoutes struct {
+	rootURL    string
+	faviconURL string
+	robotsTxt  []byte
+}
+
+// NewSiteRoutes returns routes that redirect to the given URLs. An empty URL
+// leaves that path unhandled so the caller can render its own 404, and an
+// empty robotsTxt falls back to DefaultRobotsTxt.
+func NewSiteRoutes(rootURL, faviconURL string, robotsTxt []byte) *SiteRoutes {
+	if len(robotsTxt) == 0 {
+		robotsTxt = []byte(DefaultRobotsTxt)
+	}
+	return &SiteRoutes{
+		rootURL:    rootURL,
+		faviconURL: faviconURL,
+		robotsTxt:  robotsTxt,
+	}
+}
+
+// LoadRobotsTxt reads a robots.txt override from path. An empty path returns
+// DefaultRobotsTxt.
+func LoadRobotsTxt(path string) ([]byte, error) {
+	if path == "" {
+		return []byte(DefaultRobotsTxt), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read robots.txt: %w", err)
+	}
+	return data, nil
+}
+
+// RedirectRoot sends the bare root to the configured root URL and reports
+// whether it did.
+func (s *SiteRoutes) RedirectRoot(w http.ResponseWriter, r *http.Request) bool {
+	return redirectIfSet(w, r, s.rootURL)
+}
+
+// ServeRobots writes the robots.txt body.
+func (s *SiteRoutes) ServeRobots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(s.robotsTxt)
+	}
+}
+
+// ServeFavicon redirects to the configured favicon URL, or answers 404 with
+// an empty body when none is set.
+func (s *SiteRoutes) ServeFavicon(w http.ResponseWriter, r *http.Request) {
+	if !redirectIfSet(w, r, s.faviconURL) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func redirectIfSet(w http.ResponseWriter, r *http.Request, target string) bool {
+	if target == "" {
+		return false
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+	return true
+}
@@ -0,0 +1,74 @@
+package redirect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSiteRoutes_Unconfigured(t *testing.T) {
+	site := NewSiteRoutes("", "", nil)
+
+	w := httptest.NewRecorder()
+	if site.RedirectRoot(w, httptest.NewRequest(http.MethodGet, "/", nil)) {
+		t.Error("RedirectRoot handled the request without a root URL")
+	}
+
+	w = httptest.NewRecorder()
+	site.ServeFavicon(w, httptest.NewRequest(http.MethodGet, "/favicon.ico", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("favicon status = %d, want 404", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	site.ServeRobots(w, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+	if w.Code != http.StatusOK || w.Body.String() != DefaultRobotsTxt {
+		t.Errorf("robots = %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestSiteRoutes_Redirects(t *testing.T) {
+	site := NewSiteRoutes("https://example.com", "https://example.com/favicon.ico", nil)
+
+	tests := []struct {
+		name  string
+		serve func(w http.ResponseWriter, r *http.Request)
+		want  string
+	}{
+		{"root", func(w http.ResponseWriter, r *http.Request) { site.RedirectRoot(w, r) }, "https://example.com"},
+		{"favicon", site.ServeFavicon, "https://example.com/favicon.ico"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.serve(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != http.StatusFound || w.Header().Get("Location") != tt.want {
+				t.Errorf("got %d -> %q, want 302 -> %q", w.Code, w.Header().Get("Location"), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRobotsTxt(t *testing.T) {
+	data, err := LoadRobotsTxt("")
+	if err != nil || string(data) != DefaultRobotsTxt {
+		t.Errorf("default = %q, %v", data, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "robots.txt")
+	custom := "User-agent: *\nAllow: /\n"
+	if err := os.WriteFile(path, []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err = LoadRobotsTxt(path)
+	if err != nil || string(data) != custom {
+		t.Errorf("custom = %q, %v", data, err)
+	}
+
+	if _, err := LoadRobotsTxt(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -14,6 +15,10 @@ import (
 const (
 	clickQueueKey  = "clicks:queue"
 	defaultBatch   = 500
+
+	// unsampledClicksKey is a hash of link ID to clicks skipped by sampling,
+	// which the worker adds to the links' totals.
+	unsampledClicksKey = "clicks:unsampled"
 )
 
 // ClickTracker provides non-blocking, async click event tracking.
@@ -26,6 +31,11 @@ type ClickTracker struct {
 	flushTick time.Duration
 	wg        sync.WaitGroup
 	done      chan struct{}
+
+	// skipped counts clicks left out by sampling per link since the last
+	// flush.
+	skippedMu sync.Mutex
+	skipped   map[uuid.UUID]int64
 }
 
 func NewClickTracker(redisClient *redis.Client, bufferSize int, flushInterval time.Duration, logger *zap.Logger) *ClickTracker {
@@ -36,6 +46,7 @@ func NewClickTracker(redisClient *redis.Client, bufferSize int, flushInterval ti
 		batchSize: defaultBatch,
 		flushTick: flushInterval,
 		done:      make(chan struct{}),
+		skipped:   make(map[uuid.UUID]int64),
 	}
 	ct.wg.Add(1)
 	go ct.processLoop()
@@ -53,6 +64,14 @@ func (ct *ClickTracker) Track(event *models.ClickEvent) {
 	}
 }
 
+// Skip counts a click that sampling left out, so the link's total stays
+// exact without a click event.
+func (ct *ClickTracker) Skip(linkID uuid.UUID) {
+	ct.skippedMu.Lock()
+	ct.skipped[linkID]++
+	ct.skippedMu.Unlock()
+}
+
 // Shutdown gracefully stops the tracker and flushes remaining events.
 func (ct *ClickTracker) Shutdown(ctx context.Context) {
 	close(ct.done)
@@ -83,11 +102,13 @@ func (ct *ClickTracker) processLoop() {
 				ct.flush(context.Background(), batch)
 				batch = make([]*models.ClickEvent, 0, ct.batchSize)
 			}
+			ct.flushSkipped(context.Background())
 		case <-ct.done:
 			// Flush remaining batch
 			if len(batch) > 0 {
 				ct.flush(context.Background(), batch)
 			}
+			ct.flushSkipped(context.Background())
 			return
 		}
 	}
@@ -120,6 +141,35 @@ func (ct *ClickTracker) flush(ctx context.Context, batch []*models.ClickEvent) {
 	}
 }
 
+// flushSkipped adds the skipped click counts to the unsampled clicks hash.
+// Counts that fail to be written are kept for the next flush.
+func (ct *ClickTracker) flushSkipped(ctx context.Context) {
+	ct.skippedMu.Lock()
+	skipped := ct.skipped
+	if len(skipped) == 0 {
+		ct.skippedMu.Unlock()
+		return
+	}
+	ct.skipped = make(map[uuid.UUID]int64)
+	ct.skippedMu.Unlock()
+
+	pipe := ct.redis.Pipeline()
+	for linkID, n := range skipped {
+		pipe.HIncrBy(ctx, unsampledClicksKey, linkID.String(), n)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		ct.logger.Error("failed to push unsampled click counts to Redis",
+			zap.Error(err),
+			zap.Int("links", len(skipped)),
+		)
+		ct.skippedMu.Lock()
+		for linkID, n := range skipped {
+			ct.skipped[linkID] += n
+		}
+		ct.skippedMu.Unlock()
+	}
+}
+
 func (ct *ClickTracker) flushRemaining(ctx context.Context) {
 	batch := make([]*models.ClickEvent, 0, ct.batchSize)
 	for {
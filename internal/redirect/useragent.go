@@ -0,0 +1,78 @@
+package redirect
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Simple User-Agent parsing, shared by click processing and platform-specific
+// redirects.
+
+var (
+	chromeRe  = regexp.MustCompile(`Chrome/(\d+[\.\d]*)`)
+	firefoxRe = regexp.MustCompile(`Firefox/(\d+[\.\d]*)`)
+	safariRe  = regexp.MustCompile(`Version/(\d+[\.\d]*).*Safari`)
+	edgeRe    = regexp.MustCompile(`Edg/(\d+[\.\d]*)`)
+	operaRe   = regexp.MustCompile(`OPR/(\d+[\.\d]*)`)
+
+	windowsRe = regexp.MustCompile(`Windows NT (\d+[\.\d]*)`)
+	macRe     = regexp.MustCompile(`Mac OS X (\d+[_\.\d]*)`)
+	linuxRe   = regexp.MustCompile(`Linux`)
+	androidRe = regexp.MustCompile(`Android (\d+[\.\d]*)`)
+	iosRe     = regexp.MustCompile(`(?:iPhone|iPad|iPod)[^)]*? OS (\d+[_\.\d]*)`)
+)
+
+// ParseBrowser returns the browser name and version from a User-Agent, or
+// empty strings when it isn't recognized.
+func ParseBrowser(ua string) (name, version string) {
+	if m := edgeRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "Edge", m[1]
+	}
+	if m := operaRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "Opera", m[1]
+	}
+	if m := chromeRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "Chrome", m[1]
+	}
+	if m := firefoxRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "Firefox", m[1]
+	}
+	if m := safariRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "Safari", m[1]
+	}
+	return "", ""
+}
+
+// ParseOS returns the operating system name (iOS, Android, macOS, Windows or
+// Linux) and version from a User-Agent, or empty strings when it isn't
+// recognized.
+func ParseOS(ua string) (name, version string) {
+	if m := iosRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "iOS", strings.ReplaceAll(m[1], "_", ".")
+	}
+	if m := androidRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "Android", m[1]
+	}
+	if m := macRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "macOS", strings.ReplaceAll(m[1], "_", ".")
+	}
+	if m := windowsRe.FindStringSubmatch(ua); len(m) > 1 {
+		return "Windows", m[1]
+	}
+	if linuxRe.MatchString(ua) {
+		return "Linux", ""
+	}
+	return "", ""
+}
+
+// ParseDeviceType classifies a User-Agent as mobile, tablet or desktop.
+func ParseDeviceType(ua string) string {
+	uaLower := strings.ToLower(ua)
+	if strings.Contains(uaLower, "tablet") || strings.Contains(uaLower, "ipad") {
+		return "tablet"
+	}
+	if strings.Contains(uaLower, "mobile") || strings.Contains(uaLower, "iphone") || strings.Contains(uaLower, "android") {
+		return "mobile"
+	}
+	return "desktop"
+}
@@ -0,0 +1,115 @@
+package redirect
+
+import "testing"
+
+func TestParseBrowser(t *testing.T) {
+	tests := []struct {
+		ua          string
+		wantName    string
+		wantVersion string
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
+			"Chrome", "91.0.4472.124",
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:89.0) Gecko/20100101 Firefox/89.0",
+			"Firefox", "89.0",
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1.1 Safari/605.1.15",
+			"Safari", "14.1.1",
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36 Edg/91.0.864.59",
+			"Edge", "91.0.864.59",
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0 Safari/537.36 OPR/77.0",
+			"Opera", "77.0",
+		},
+		{"", "", ""},
+		{"some random string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			name, version := ParseBrowser(tt.ua)
+			if name != tt.wantName {
+				t.Errorf("ParseBrowser(%q) name = %q, want %q", tt.ua, name, tt.wantName)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("ParseBrowser(%q) version = %q, want %q", tt.ua, version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParseOS(t *testing.T) {
+	tests := []struct {
+		ua          string
+		wantName    string
+		wantVersion string
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
+			"Windows", "10.0",
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)",
+			"macOS", "10.15.7",
+		},
+		{
+			"Mozilla/5.0 (X11; Linux x86_64)",
+			"Linux", "",
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 11; SM-G998B)",
+			"Android", "11",
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 14_6 like Mac OS X)",
+			"iOS", "14.6",
+		},
+		{
+			"Mozilla/5.0 (iPad; CPU OS 14_6 like Mac OS X)",
+			"iOS", "14.6",
+		},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			name, version := ParseOS(tt.ua)
+			if name != tt.wantName {
+				t.Errorf("ParseOS(%q) name = %q, want %q", tt.ua, name, tt.wantName)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("ParseOS(%q) version = %q, want %q", tt.ua, version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestParseDeviceType(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64)", "desktop"},
+		{"Mozilla/5.0 (Linux; Android 11) Mobile Safari", "mobile"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 14_6)", "mobile"},
+		{"Mozilla/5.0 (iPad; CPU OS 14_6 like Mac OS X)", "tablet"},
+		{"Mozilla/5.0 (Linux; Android 11; SM-T870) Tablet", "tablet"},
+		{"", "desktop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want+"_"+tt.ua[:min(20, len(tt.ua))], func(t *testing.T) {
+			got := ParseDeviceType(tt.ua)
+			if got != tt.want {
+				t.Errorf("ParseDeviceType(%q) = %q, want %q", tt.ua, got, tt.want)
+			}
+		})
+	}
+}
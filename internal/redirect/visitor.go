@@ -0,0 +1,86 @@
+package redirect
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	visitorKeyPrefix    = "link:visitors:"
+	visitorCookieName   = "lr_vid"
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
+
+	// visitorIPContext separates visitor IP hashes from other uses of the
+	// server secret.
+	visitorIPContext = "visitor_ip."
+)
+
+// VisitorLimiter enforces once-per-visitor links. Visitors who followed a
+// link are kept in a Redis set per link, identified either by a long-lived
+// random cookie or by a hash of their IP address keyed by the server secret,
+// so raw IPs are never stored and the hashes can't be reversed.
+type VisitorLimiter struct {
+	redis         *redis.Client
+	identity      string
+	secret        string
+	secureCookies bool
+	logger        *zap.Logger
+}
+
+// NewVisitorLimiter returns a limiter identifying visitors with identity,
+// config.VisitorIdentityCookie or config.VisitorIdentityIP. secret keys the
+// IP hashes and secureCookies marks the visitor cookie Secure.
+func NewVisitorLimiter(redisClient *redis.Client, identity, secret string, secureCookies bool, logger *zap.Logger) *VisitorLimiter {
+	return &VisitorLimiter{
+		redis:         redisClient,
+		identity:      identity,
+		secret:        secret,
+		secureCookies: secureCookies,
+		logger:        logger,
+	}
+}
+
+// Visitor returns the identifier of the visitor making r. With cookie
+// identity a visitor without a valid cookie gets a new ID, which is set on w.
+func (v *VisitorLimiter) Visitor(w http.ResponseWriter, r *http.Request, clientIP string) string {
+	if v.identity == config.VisitorIdentityIP {
+		return "ip:" + crypto.SignHMAC(v.secret, []byte(visitorIPContext+clientIP))
+	}
+
+	if cookie, err := r.Cookie(visitorCookieName); err == nil {
+		if id, err := uuid.Parse(cookie.Value); err == nil {
+			return "c:" + id.String()
+		}
+	}
+	id := uuid.NewString()
+	http.SetCookie(w, httputil.NewCookie(visitorCookieName, id, visitorCookieMaxAge, v.secureCookies))
+	return "c:" + id
+}
+
+// FirstVisit records visitor against the link and reports whether this is
+// their first visit. The set expires with the link when it has an expiry.
+// If Redis is unavailable the visitor is let through.
+func (v *VisitorLimiter) FirstVisit(ctx context.Context, linkID uuid.UUID, visitor string, expiresAt *time.Time) bool {
+	if v.redis == nil {
+		return true
+	}
+
+	key := visitorKeyPrefix + linkID.String()
+	added, err := v.redis.SAdd(ctx, key, visitor).Result()
+	if err != nil {
+		v.logger.Warn("failed to record link visitor", zap.String("link_id", linkID.String()), zap.Error(err))
+		return true
+	}
+	if added == 1 && expiresAt != nil {
+		v.redis.ExpireAt(ctx, key, *expiresAt)
+	}
+	return added == 1
+}
@@ -0,0 +1,97 @@
+package redirect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestVisitor_IPIdentity(t *testing.T) {
+	v := NewVisitorLimiter(nil, config.VisitorIdentityIP, "test-secret", true, zap.NewNop())
+	r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+
+	w := httptest.NewRecorder()
+	first := v.Visitor(w, r, "203.0.113.7")
+	second := v.Visitor(httptest.NewRecorder(), r, "203.0.113.7")
+	other := v.Visitor(httptest.NewRecorder(), r, "203.0.113.8")
+
+	if first != second {
+		t.Errorf("same IP gave different visitors %q and %q", first, second)
+	}
+	if first == other {
+		t.Error("different IPs gave the same visitor")
+	}
+	if strings.Contains(first, "203.0.113.7") {
+		t.Errorf("visitor %q contains the raw IP", first)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("IP identity must not set a cookie")
+	}
+
+	rekeyed := NewVisitorLimiter(nil, config.VisitorIdentityIP, "other-secret", true, zap.NewNop())
+	if rekeyed.Visitor(httptest.NewRecorder(), r, "203.0.113.7") == first {
+		t.Error("expected the visitor hash to be keyed by the secret")
+	}
+}
+
+func TestVisitor_CookieIdentity(t *testing.T) {
+	v := NewVisitorLimiter(nil, config.VisitorIdentityCookie, "test-secret", true, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	first := v.Visitor(w, httptest.NewRequest(http.MethodGet, "/abc123", nil), "203.0.113.7")
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != visitorCookieName {
+		t.Fatalf("expected a %s cookie, got %v", visitorCookieName, cookies)
+	}
+	if c := cookies[0]; !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("cookie = %+v, want HttpOnly, Secure and SameSite=Lax", c)
+	}
+
+	// A returning visitor is recognized by the cookie, whatever their IP.
+	r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	r.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	if got := v.Visitor(w, r, "198.51.100.1"); got != first {
+		t.Errorf("Visitor() = %q, want %q from the cookie", got, first)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("a valid cookie must not be replaced")
+	}
+}
+
+func TestVisitor_InvalidCookieReplaced(t *testing.T) {
+	v := NewVisitorLimiter(nil, config.VisitorIdentityCookie, "test-secret", true, zap.NewNop())
+	r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	r.AddCookie(&http.Cookie{Name: visitorCookieName, Value: "not-a-uuid"})
+
+	w := httptest.NewRecorder()
+	got := v.Visitor(w, r, "203.0.113.7")
+	if strings.Contains(got, "not-a-uuid") {
+		t.Errorf("Visitor() = %q, must not trust a malformed cookie", got)
+	}
+	if len(w.Result().Cookies()) != 1 {
+		t.Error("expected a new cookie to be set")
+	}
+}
+
+func TestFirstVisit_FailsOpen(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer client.Close()
+
+	for _, v := range []*VisitorLimiter{
+		NewVisitorLimiter(nil, config.VisitorIdentityCookie, "test-secret", true, zap.NewNop()),
+		NewVisitorLimiter(client, config.VisitorIdentityCookie, "test-secret", true, zap.NewNop()),
+	} {
+		if !v.FirstVisit(context.Background(), uuid.New(), "c:visitor", nil) {
+			t.Error("expected visitors to be let through without Redis")
+		}
+	}
+}
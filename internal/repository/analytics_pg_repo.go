@@ -32,7 +32,8 @@ func (r *pgAnalyticsRepo) GetLinkStats(ctx context.Context, linkID uuid.UUID, dr
 			COUNT(DISTINCT ip_address) FILTER (WHERE clicked_at >= $1 AND clicked_at <= $2) AS unique_clicks,
 			COUNT(*) FILTER (WHERE clicked_at >= $3) AS clicks_24h,
 			COUNT(*) FILTER (WHERE clicked_at >= $4) AS clicks_7d,
-			COUNT(*) FILTER (WHERE clicked_at >= $5) AS clicks_30d
+			COUNT(*) FILTER (WHERE clicked_at >= $5) AS clicks_30d,
+			COUNT(*) FILTER (WHERE clicked_at >= $1 AND clicked_at <= $2 AND source = 'qr') AS qr_scans
 		FROM clicks
 		WHERE link_id = $6 AND is_bot = false
 	`,
@@ -47,6 +48,7 @@ func (r *pgAnalyticsRepo) GetLinkStats(ctx context.Context, linkID uuid.UUID, dr
 		&stats.Clicks24h,
 		&stats.Clicks7d,
 		&stats.Clicks30d,
+		&stats.QRScans,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("pg get link stats: %w", err)
@@ -125,14 +127,14 @@ func (r *pgAnalyticsRepo) GetTimeSeries(ctx context.Context, linkID uuid.UUID, i
 
 	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
 		SELECT
-			date_trunc('%s', clicked_at) AS ts,
+			date_trunc('%s', clicked_at AT TIME ZONE $4) AT TIME ZONE $4 AS ts,
 			COUNT(*) AS clicks,
 			COUNT(DISTINCT ip_address) AS uniq
 		FROM clicks
 		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = false
 		GROUP BY ts
 		ORDER BY ts ASC
-	`, trunc), linkID, dr.Start, dr.End)
+	`, trunc), linkID, dr.Start, dr.End, dr.TimeZone())
 	if err != nil {
 		return nil, fmt.Errorf("pg get time series: %w", err)
 	}
@@ -151,16 +153,22 @@ func (r *pgAnalyticsRepo) GetTimeSeries(ctx context.Context, linkID uuid.UUID, i
 }
 
 func (r *pgAnalyticsRepo) GetTopReferrers(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
-	rows, err := r.pool.Query(ctx, `
+	return r.topReferrers(ctx, linkScope(linkID), dr, limit)
+}
+
+func (r *pgAnalyticsRepo) topReferrers(ctx context.Context, scope clickScope, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+	where, args := scope.pgWhere(dr)
+	args = append(args, limit)
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
 		SELECT
 			COALESCE(NULLIF(referer, ''), 'Direct') AS ref,
 			COUNT(*) AS clicks
 		FROM clicks
-		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = false
+		WHERE %s
 		GROUP BY ref
 		ORDER BY clicks DESC
-		LIMIT $4
-	`, linkID, dr.Start, dr.End, limit)
+		LIMIT $%d
+	`, where, len(args)), args...)
 	if err != nil {
 		return nil, fmt.Errorf("pg get referrers: %w", err)
 	}
@@ -187,16 +195,22 @@ func (r *pgAnalyticsRepo) GetTopReferrers(ctx context.Context, linkID uuid.UUID,
 }
 
 func (r *pgAnalyticsRepo) GetTopCountries(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error) {
-	rows, err := r.pool.Query(ctx, `
+	return r.topCountries(ctx, linkScope(linkID), dr, limit)
+}
+
+func (r *pgAnalyticsRepo) topCountries(ctx context.Context, scope clickScope, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+	where, args := scope.pgWhere(dr)
+	args = append(args, limit)
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
 		SELECT
 			COALESCE(NULLIF(country_code, ''), 'Unknown') AS cc,
 			COUNT(*) AS clicks
 		FROM clicks
-		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = false
+		WHERE %s
 		GROUP BY cc
 		ORDER BY clicks DESC
-		LIMIT $4
-	`, linkID, dr.Start, dr.End, limit)
+		LIMIT $%d
+	`, where, len(args)), args...)
 	if err != nil {
 		return nil, fmt.Errorf("pg get countries: %w", err)
 	}
@@ -224,14 +238,19 @@ func (r *pgAnalyticsRepo) GetTopCountries(ctx context.Context, linkID uuid.UUID,
 }
 
 func (r *pgAnalyticsRepo) GetDeviceBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error) {
-	rows, err := r.pool.Query(ctx, `
+	return r.deviceBreakdown(ctx, linkScope(linkID), dr)
+}
+
+func (r *pgAnalyticsRepo) deviceBreakdown(ctx context.Context, scope clickScope, dr models.DateRange) (*models.DeviceBreakdown, error) {
+	where, args := scope.pgWhere(dr)
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
 		SELECT
 			COALESCE(NULLIF(device_type, ''), 'desktop') AS dt,
 			COUNT(*) AS clicks
 		FROM clicks
-		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = false
+		WHERE %s
 		GROUP BY dt
-	`, linkID, dr.Start, dr.End)
+	`, where), args...)
 	if err != nil {
 		return nil, fmt.Errorf("pg get devices: %w", err)
 	}
@@ -259,6 +278,18 @@ func (r *pgAnalyticsRepo) GetDeviceBreakdown(ctx context.Context, linkID uuid.UU
 	return breakdown, nil
 }
 
+func (r *pgAnalyticsRepo) GetWorkspaceReferrers(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+	return r.topReferrers(ctx, workspaceScope(workspaceID, filter), dr, limit)
+}
+
+func (r *pgAnalyticsRepo) GetWorkspaceCountries(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+	return r.topCountries(ctx, workspaceScope(workspaceID, filter), dr, limit)
+}
+
+func (r *pgAnalyticsRepo) GetWorkspaceDevices(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange) (*models.DeviceBreakdown, error) {
+	return r.deviceBreakdown(ctx, workspaceScope(workspaceID, filter), dr)
+}
+
 func (r *pgAnalyticsRepo) GetBrowserBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error) {
 	rows, err := r.pool.Query(ctx, `
 		SELECT
@@ -295,6 +326,102 @@ func (r *pgAnalyticsRepo) GetBrowserBreakdown(ctx context.Context, linkID uuid.U
 	return stats, nil
 }
 
+func (r *pgAnalyticsRepo) GetClicksByHourOfWeek(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.ClickHeatmap, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			EXTRACT(ISODOW FROM clicked_at AT TIME ZONE $4)::int AS dow,
+			EXTRACT(HOUR FROM clicked_at AT TIME ZONE $4)::int AS hour,
+			COUNT(*) AS clicks
+		FROM clicks
+		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = false
+		GROUP BY dow, hour
+	`, linkID, dr.Start, dr.End, dr.TimeZone())
+	if err != nil {
+		return nil, fmt.Errorf("pg get heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	heatmap := &models.ClickHeatmap{}
+	for rows.Next() {
+		var dow, hour int
+		var clicks int64
+		if err := rows.Scan(&dow, &hour, &clicks); err != nil {
+			return nil, fmt.Errorf("pg scan heatmap: %w", err)
+		}
+		heatmap.Add(dow, hour, clicks)
+	}
+
+	return heatmap, nil
+}
+
+func (r *pgAnalyticsRepo) GetLinkClickSummaries(ctx context.Context, linkIDs []uuid.UUID, dr models.DateRange) (map[uuid.UUID]models.LinkClickSummary, error) {
+	summaries := make(map[uuid.UUID]models.LinkClickSummary, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return summaries, nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			link_id,
+			COUNT(*) AS clicks,
+			COUNT(DISTINCT ip_address) AS uniq,
+			MAX(clicked_at) AS last_clicked_at
+		FROM clicks
+		WHERE link_id = ANY($1) AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = false
+		GROUP BY link_id
+	`, linkIDs, dr.Start, dr.End)
+	if err != nil {
+		return nil, fmt.Errorf("pg get link click summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			linkID      uuid.UUID
+			summary     models.LinkClickSummary
+			lastClicked time.Time
+		)
+		if err := rows.Scan(&linkID, &summary.Clicks, &summary.UniqueClicks, &lastClicked); err != nil {
+			return nil, fmt.Errorf("pg scan link click summary: %w", err)
+		}
+		summary.LastClickedAt = &lastClicked
+		summaries[linkID] = summary
+	}
+
+	return summaries, nil
+}
+
+func (r *pgAnalyticsRepo) GetLastClickedAt(ctx context.Context, linkIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	lastClicked := make(map[uuid.UUID]time.Time, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return lastClicked, nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT link_id, MAX(clicked_at) AS last_clicked_at
+		FROM clicks
+		WHERE link_id = ANY($1) AND is_bot = false
+		GROUP BY link_id
+	`, linkIDs)
+	if err != nil {
+		return nil, fmt.Errorf("pg get last clicked at: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			linkID uuid.UUID
+			at     time.Time
+		)
+		if err := rows.Scan(&linkID, &at); err != nil {
+			return nil, fmt.Errorf("pg scan last clicked at: %w", err)
+		}
+		lastClicked[linkID] = at
+	}
+
+	return lastClicked, nil
+}
+
 func pgTruncInterval(interval models.TimeSeriesInterval) string {
 	switch interval {
 	case models.IntervalHour:
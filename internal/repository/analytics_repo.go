@@ -20,6 +20,14 @@ type AnalyticsRepository interface {
 	GetTopCountries(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error)
 	GetDeviceBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error)
 	GetBrowserBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error)
+	GetClicksByHourOfWeek(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.ClickHeatmap, error)
+	GetWorkspaceReferrers(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.ReferrerStats, error)
+	GetWorkspaceCountries(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.CountryStats, error)
+	GetWorkspaceDevices(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange) (*models.DeviceBreakdown, error)
+	GetLinkClickSummaries(ctx context.Context, linkIDs []uuid.UUID, dr models.DateRange) (map[uuid.UUID]models.LinkClickSummary, error)
+	// GetLastClickedAt returns when each link was last clicked by a
+	// non-bot visitor. Links that never were are left out.
+	GetLastClickedAt(ctx context.Context, linkIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
 }
 
 type clickhouseAnalyticsRepo struct {
@@ -42,7 +50,8 @@ func (r *clickhouseAnalyticsRepo) GetLinkStats(ctx context.Context, linkID uuid.
 			uniqExactIf(ip_address, clicked_at >= $1 AND clicked_at <= $2) AS unique_clicks,
 			countIf(clicked_at >= $3) AS clicks_24h,
 			countIf(clicked_at >= $4) AS clicks_7d,
-			countIf(clicked_at >= $5) AS clicks_30d
+			countIf(clicked_at >= $5) AS clicks_30d,
+			countIf(clicked_at >= $1 AND clicked_at <= $2 AND source = 'qr') AS qr_scans
 		FROM clicks
 		WHERE link_id = $6 AND is_bot = 0
 	`,
@@ -57,6 +66,7 @@ func (r *clickhouseAnalyticsRepo) GetLinkStats(ctx context.Context, linkID uuid.
 		&stats.Clicks24h,
 		&stats.Clicks7d,
 		&stats.Clicks30d,
+		&stats.QRScans,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("clickhouse get link stats: %w", err)
@@ -123,18 +133,16 @@ func (r *clickhouseAnalyticsRepo) GetWorkspaceStats(ctx context.Context, workspa
 }
 
 func (r *clickhouseAnalyticsRepo) GetTimeSeries(ctx context.Context, linkID uuid.UUID, interval models.TimeSeriesInterval, dr models.DateRange) ([]models.TimeSeriesPoint, error) {
-	fn := chTruncFunc(interval)
-
 	rows, err := r.conn.Query(ctx, fmt.Sprintf(`
 		SELECT
-			%s(clicked_at) AS ts,
+			%s AS ts,
 			count() AS clicks,
 			uniqExact(ip_address) AS uniq
 		FROM clicks
 		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = 0
 		GROUP BY ts
 		ORDER BY ts ASC
-	`, fn), linkID, dr.Start, dr.End)
+	`, chTruncExpr(interval, "$4")), linkID, dr.Start, dr.End, dr.TimeZone())
 	if err != nil {
 		return nil, fmt.Errorf("clickhouse get time series: %w", err)
 	}
@@ -153,16 +161,22 @@ func (r *clickhouseAnalyticsRepo) GetTimeSeries(ctx context.Context, linkID uuid
 }
 
 func (r *clickhouseAnalyticsRepo) GetTopReferrers(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
-	rows, err := r.conn.Query(ctx, `
+	return r.topReferrers(ctx, linkScope(linkID), dr, limit)
+}
+
+func (r *clickhouseAnalyticsRepo) topReferrers(ctx context.Context, scope clickScope, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+	where, args := scope.chWhere(dr)
+	args = append(args, limit)
+	rows, err := r.conn.Query(ctx, fmt.Sprintf(`
 		SELECT
 			if(referer = '', 'Direct', domain(referer)) AS ref,
 			count() AS clicks
 		FROM clicks
-		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = 0
+		WHERE %s
 		GROUP BY ref
 		ORDER BY clicks DESC
-		LIMIT $4
-	`, linkID, dr.Start, dr.End, limit)
+		LIMIT $%d
+	`, where, len(args)), args...)
 	if err != nil {
 		return nil, fmt.Errorf("clickhouse get referrers: %w", err)
 	}
@@ -189,16 +203,22 @@ func (r *clickhouseAnalyticsRepo) GetTopReferrers(ctx context.Context, linkID uu
 }
 
 func (r *clickhouseAnalyticsRepo) GetTopCountries(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error) {
-	rows, err := r.conn.Query(ctx, `
+	return r.topCountries(ctx, linkScope(linkID), dr, limit)
+}
+
+func (r *clickhouseAnalyticsRepo) topCountries(ctx context.Context, scope clickScope, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+	where, args := scope.chWhere(dr)
+	args = append(args, limit)
+	rows, err := r.conn.Query(ctx, fmt.Sprintf(`
 		SELECT
 			if(country_code = '', 'Unknown', country_code) AS cc,
 			count() AS clicks
 		FROM clicks
-		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = 0
+		WHERE %s
 		GROUP BY cc
 		ORDER BY clicks DESC
-		LIMIT $4
-	`, linkID, dr.Start, dr.End, limit)
+		LIMIT $%d
+	`, where, len(args)), args...)
 	if err != nil {
 		return nil, fmt.Errorf("clickhouse get countries: %w", err)
 	}
@@ -226,14 +246,19 @@ func (r *clickhouseAnalyticsRepo) GetTopCountries(ctx context.Context, linkID uu
 }
 
 func (r *clickhouseAnalyticsRepo) GetDeviceBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error) {
-	rows, err := r.conn.Query(ctx, `
+	return r.deviceBreakdown(ctx, linkScope(linkID), dr)
+}
+
+func (r *clickhouseAnalyticsRepo) deviceBreakdown(ctx context.Context, scope clickScope, dr models.DateRange) (*models.DeviceBreakdown, error) {
+	where, args := scope.chWhere(dr)
+	rows, err := r.conn.Query(ctx, fmt.Sprintf(`
 		SELECT
 			if(device_type = '', 'desktop', device_type) AS dt,
 			count() AS clicks
 		FROM clicks
-		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = 0
+		WHERE %s
 		GROUP BY dt
-	`, linkID, dr.Start, dr.End)
+	`, where), args...)
 	if err != nil {
 		return nil, fmt.Errorf("clickhouse get devices: %w", err)
 	}
@@ -261,6 +286,18 @@ func (r *clickhouseAnalyticsRepo) GetDeviceBreakdown(ctx context.Context, linkID
 	return breakdown, nil
 }
 
+func (r *clickhouseAnalyticsRepo) GetWorkspaceReferrers(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+	return r.topReferrers(ctx, workspaceScope(workspaceID, filter), dr, limit)
+}
+
+func (r *clickhouseAnalyticsRepo) GetWorkspaceCountries(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+	return r.topCountries(ctx, workspaceScope(workspaceID, filter), dr, limit)
+}
+
+func (r *clickhouseAnalyticsRepo) GetWorkspaceDevices(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange) (*models.DeviceBreakdown, error) {
+	return r.deviceBreakdown(ctx, workspaceScope(workspaceID, filter), dr)
+}
+
 func (r *clickhouseAnalyticsRepo) GetBrowserBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error) {
 	rows, err := r.conn.Query(ctx, `
 		SELECT
@@ -297,17 +334,124 @@ func (r *clickhouseAnalyticsRepo) GetBrowserBreakdown(ctx context.Context, linkI
 	return stats, nil
 }
 
-func chTruncFunc(interval models.TimeSeriesInterval) string {
+func (r *clickhouseAnalyticsRepo) GetClicksByHourOfWeek(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.ClickHeatmap, error) {
+	rows, err := r.conn.Query(ctx, `
+		SELECT
+			toDayOfWeek(toTimeZone(clicked_at, $4)) AS dow,
+			toHour(toTimeZone(clicked_at, $4)) AS hour,
+			count() AS clicks
+		FROM clicks
+		WHERE link_id = $1 AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = 0
+		GROUP BY dow, hour
+	`, linkID, dr.Start, dr.End, dr.TimeZone())
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse get heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	heatmap := &models.ClickHeatmap{}
+	for rows.Next() {
+		var dow, hour uint8
+		var clicks uint64
+		if err := rows.Scan(&dow, &hour, &clicks); err != nil {
+			return nil, fmt.Errorf("clickhouse scan heatmap: %w", err)
+		}
+		heatmap.Add(int(dow), int(hour), int64(clicks))
+	}
+
+	return heatmap, nil
+}
+
+func (r *clickhouseAnalyticsRepo) GetLinkClickSummaries(ctx context.Context, linkIDs []uuid.UUID, dr models.DateRange) (map[uuid.UUID]models.LinkClickSummary, error) {
+	summaries := make(map[uuid.UUID]models.LinkClickSummary, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return summaries, nil
+	}
+
+	ids := make([]string, len(linkIDs))
+	for i, id := range linkIDs {
+		ids[i] = id.String()
+	}
+
+	rows, err := r.conn.Query(ctx, `
+		SELECT
+			link_id,
+			count() AS clicks,
+			uniqExact(ip_address) AS uniq,
+			max(clicked_at) AS last_clicked_at
+		FROM clicks
+		WHERE has($1, toString(link_id)) AND clicked_at >= $2 AND clicked_at <= $3 AND is_bot = 0
+		GROUP BY link_id
+	`, ids, dr.Start, dr.End)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse get link click summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			linkID      uuid.UUID
+			summary     models.LinkClickSummary
+			lastClicked time.Time
+		)
+		if err := rows.Scan(&linkID, &summary.Clicks, &summary.UniqueClicks, &lastClicked); err != nil {
+			return nil, fmt.Errorf("clickhouse scan link click summary: %w", err)
+		}
+		summary.LastClickedAt = &lastClicked
+		summaries[linkID] = summary
+	}
+
+	return summaries, nil
+}
+
+func (r *clickhouseAnalyticsRepo) GetLastClickedAt(ctx context.Context, linkIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	lastClicked := make(map[uuid.UUID]time.Time, len(linkIDs))
+	if len(linkIDs) == 0 {
+		return lastClicked, nil
+	}
+
+	ids := make([]string, len(linkIDs))
+	for i, id := range linkIDs {
+		ids[i] = id.String()
+	}
+
+	rows, err := r.conn.Query(ctx, `
+		SELECT link_id, max(clicked_at) AS last_clicked_at
+		FROM clicks
+		WHERE has($1, toString(link_id)) AND is_bot = 0
+		GROUP BY link_id
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse get last clicked at: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			linkID uuid.UUID
+			at     time.Time
+		)
+		if err := rows.Scan(&linkID, &at); err != nil {
+			return nil, fmt.Errorf("clickhouse scan last clicked at: %w", err)
+		}
+		lastClicked[linkID] = at
+	}
+
+	return lastClicked, nil
+}
+
+// chTruncExpr returns the expression truncating clicked_at to the start of
+// its interval in the time zone bound to tzParam. Week and month starts are
+// Dates, so they're turned back into that zone's midnight.
+func chTruncExpr(interval models.TimeSeriesInterval, tzParam string) string {
 	switch interval {
 	case models.IntervalHour:
-		return "toStartOfHour"
-	case models.IntervalDay:
-		return "toStartOfDay"
+		return fmt.Sprintf("toStartOfHour(clicked_at, %s)", tzParam)
 	case models.IntervalWeek:
-		return "toStartOfWeek"
+		return fmt.Sprintf("toDateTime(toStartOfWeek(clicked_at, 0, %[1]s), %[1]s)", tzParam)
 	case models.IntervalMonth:
-		return "toStartOfMonth"
+		return fmt.Sprintf("toDateTime(toStartOfMonth(clicked_at, %[1]s), %[1]s)", tzParam)
 	default:
-		return "toStartOfDay"
+		return fmt.Sprintf("toStartOfDay(clicked_at, %s)", tzParam)
 	}
 }
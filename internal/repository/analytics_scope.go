@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+// clickScope selects the clicks a breakdown query aggregates: those of a
+// single link, or of a whole workspace optionally narrowed to a set of links.
+type clickScope struct {
+	column  string // "link_id" or "workspace_id"
+	id      uuid.UUID
+	linkIDs []uuid.UUID
+}
+
+func linkScope(linkID uuid.UUID) clickScope {
+	return clickScope{column: "link_id", id: linkID}
+}
+
+func workspaceScope(workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter) clickScope {
+	return clickScope{column: "workspace_id", id: workspaceID, linkIDs: filter.LinkIDs}
+}
+
+// chWhere renders the scope and date range as ClickHouse conditions with
+// placeholders starting at $1. It returns the args to bind.
+func (s clickScope) chWhere(dr models.DateRange) (string, []any) {
+	where := s.column + " = $1"
+	args := []any{s.id}
+	if len(s.linkIDs) > 0 {
+		ids := make([]string, len(s.linkIDs))
+		for i, id := range s.linkIDs {
+			ids[i] = id.String()
+		}
+		args = append(args, ids)
+		where += fmt.Sprintf(" AND has($%d, toString(link_id))", len(args))
+	}
+	args = append(args, dr.Start, dr.End)
+	where += fmt.Sprintf(" AND clicked_at >= $%d AND clicked_at <= $%d AND is_bot = 0", len(args)-1, len(args))
+	return where, args
+}
+
+// pgWhere is the PostgreSQL equivalent of chWhere. The PostgreSQL clicks
+// table has no workspace_id, so workspace scope goes through links.
+func (s clickScope) pgWhere(dr models.DateRange) (string, []any) {
+	where := "link_id = $1"
+	if s.column == "workspace_id" {
+		where = "link_id IN (SELECT id FROM links WHERE workspace_id = $1)"
+	}
+	args := []any{s.id}
+	if len(s.linkIDs) > 0 {
+		args = append(args, s.linkIDs)
+		where += fmt.Sprintf(" AND link_id = ANY($%d)", len(args))
+	}
+	args = append(args, dr.Start, dr.End)
+	where += fmt.Sprintf(" AND clicked_at >= $%d AND clicked_at <= $%d AND is_bot = false", len(args)-1, len(args))
+	return where, args
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type AnalyticsShareRepository interface {
+	Create(ctx context.Context, params sqlc.CreateAnalyticsShareTokenParams) (*models.AnalyticsShare, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.AnalyticsShare, error)
+	ListForLink(ctx context.Context, linkID uuid.UUID) ([]*models.AnalyticsShare, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+type analyticsShareRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewAnalyticsShareRepository(queries *sqlc.Queries, logger *zap.Logger) AnalyticsShareRepository {
+	return &analyticsShareRepository{queries: queries, logger: logger}
+}
+
+func (r *analyticsShareRepository) Create(ctx context.Context, params sqlc.CreateAnalyticsShareTokenParams) (*models.AnalyticsShare, error) {
+	s, err := r.queries.CreateAnalyticsShareToken(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to create analytics share")
+	}
+	return models.AnalyticsShareFromSqlc(s), nil
+}
+
+func (r *analyticsShareRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AnalyticsShare, error) {
+	s, err := r.queries.GetAnalyticsShareToken(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("analytics_share")
+		}
+		return nil, httputil.Wrap(err, "failed to get analytics share")
+	}
+	return models.AnalyticsShareFromSqlc(s), nil
+}
+
+func (r *analyticsShareRepository) ListForLink(ctx context.Context, linkID uuid.UUID) ([]*models.AnalyticsShare, error) {
+	shares, err := r.queries.ListAnalyticsShareTokensForLink(ctx, linkID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list analytics shares")
+	}
+
+	result := make([]*models.AnalyticsShare, 0, len(shares))
+	for _, s := range shares {
+		result = append(result, models.AnalyticsShareFromSqlc(s))
+	}
+	return result, nil
+}
+
+func (r *analyticsShareRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	err := r.queries.RevokeAnalyticsShareToken(ctx, id)
+	if err != nil {
+		return httputil.Wrap(err, "failed to revoke analytics share")
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
 	"github.com/link-rift/link-rift/pkg/httputil"
@@ -32,6 +33,20 @@ type BioPageRepository interface {
 	UpdateLinkPosition(ctx context.Context, params sqlc.UpdateBioPageLinkPositionParams) error
 	IncrementLinkClickCount(ctx context.Context, id uuid.UUID) error
 	GetMaxLinkPosition(ctx context.Context, bioPageID uuid.UUID) (int32, error)
+	GetLinkCount(ctx context.Context, bioPageID uuid.UUID) (int64, error)
+
+	// Custom Themes
+	CreateTheme(ctx context.Context, params sqlc.CreateBioThemeParams) (*models.BioPageTheme, error)
+	GetThemeByID(ctx context.Context, id uuid.UUID) (*models.BioPageTheme, error)
+	ListThemes(ctx context.Context, workspaceID uuid.UUID) ([]*models.BioPageTheme, error)
+	UpdateTheme(ctx context.Context, params sqlc.UpdateBioThemeParams) (*models.BioPageTheme, error)
+	DeleteTheme(ctx context.Context, id uuid.UUID) error
+
+	// Email Capture Submissions
+	CreateSubmission(ctx context.Context, params sqlc.CreateBioPageSubmissionParams) error
+	ListSubmissions(ctx context.Context, params sqlc.ListBioPageSubmissionsParams) ([]*models.BioPageSubmission, error)
+	CountSubmissions(ctx context.Context, bioPageID uuid.UUID) (int64, error)
+	ListSubmissionsForExport(ctx context.Context, bioPageID uuid.UUID) ([]*models.BioPageSubmission, error)
 }
 
 type bioPageRepository struct {
@@ -199,3 +214,113 @@ func (r *bioPageRepository) GetMaxLinkPosition(ctx context.Context, bioPageID uu
 	}
 	return pos, nil
 }
+
+func (r *bioPageRepository) GetLinkCount(ctx context.Context, bioPageID uuid.UUID) (int64, error) {
+	count, err := r.queries.GetBioPageLinkCount(ctx, bioPageID)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count bio page links")
+	}
+	return count, nil
+}
+
+// Custom Themes
+
+func (r *bioPageRepository) CreateTheme(ctx context.Context, params sqlc.CreateBioThemeParams) (*models.BioPageTheme, error) {
+	t, err := r.queries.CreateBioTheme(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to create bio theme")
+	}
+	return models.BioThemeFromSqlc(t), nil
+}
+
+func (r *bioPageRepository) GetThemeByID(ctx context.Context, id uuid.UUID) (*models.BioPageTheme, error) {
+	t, err := r.queries.GetBioThemeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("theme")
+		}
+		return nil, httputil.Wrap(err, "failed to get bio theme")
+	}
+	return models.BioThemeFromSqlc(t), nil
+}
+
+func (r *bioPageRepository) ListThemes(ctx context.Context, workspaceID uuid.UUID) ([]*models.BioPageTheme, error) {
+	rows, err := r.queries.ListBioThemesForWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list bio themes")
+	}
+
+	themes := make([]*models.BioPageTheme, 0, len(rows))
+	for _, row := range rows {
+		themes = append(themes, models.BioThemeFromSqlc(row))
+	}
+	return themes, nil
+}
+
+func (r *bioPageRepository) UpdateTheme(ctx context.Context, params sqlc.UpdateBioThemeParams) (*models.BioPageTheme, error) {
+	t, err := r.queries.UpdateBioTheme(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("theme")
+		}
+		return nil, httputil.Wrap(err, "failed to update bio theme")
+	}
+	return models.BioThemeFromSqlc(t), nil
+}
+
+// DeleteTheme removes a custom theme and resets any pages using it to the
+// default theme.
+func (r *bioPageRepository) DeleteTheme(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.ClearBioPageTheme(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return httputil.Wrap(err, "failed to clear bio page theme")
+	}
+	if err := r.queries.DeleteBioTheme(ctx, id); err != nil {
+		return httputil.Wrap(err, "failed to delete bio theme")
+	}
+	return nil
+}
+
+// Email Capture Submissions
+
+// CreateSubmission stores a submission. Repeat submissions of the same email
+// to a page are ignored.
+func (r *bioPageRepository) CreateSubmission(ctx context.Context, params sqlc.CreateBioPageSubmissionParams) error {
+	if err := r.queries.CreateBioPageSubmission(ctx, params); err != nil {
+		return httputil.Wrap(err, "failed to create bio page submission")
+	}
+	return nil
+}
+
+func (r *bioPageRepository) ListSubmissions(ctx context.Context, params sqlc.ListBioPageSubmissionsParams) ([]*models.BioPageSubmission, error) {
+	rows, err := r.queries.ListBioPageSubmissions(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list bio page submissions")
+	}
+
+	subs := make([]*models.BioPageSubmission, 0, len(rows))
+	for _, row := range rows {
+		subs = append(subs, models.BioPageSubmissionFromSqlc(row))
+	}
+	return subs, nil
+}
+
+func (r *bioPageRepository) CountSubmissions(ctx context.Context, bioPageID uuid.UUID) (int64, error) {
+	count, err := r.queries.CountBioPageSubmissions(ctx, bioPageID)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count bio page submissions")
+	}
+	return count, nil
+}
+
+func (r *bioPageRepository) ListSubmissionsForExport(ctx context.Context, bioPageID uuid.UUID) ([]*models.BioPageSubmission, error) {
+	rows, err := r.queries.ListBioPageSubmissionsForExport(ctx, bioPageID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list bio page submissions")
+	}
+
+	subs := make([]*models.BioPageSubmission, 0, len(rows))
+	for _, row := range rows {
+		subs = append(subs, models.BioPageSubmissionFromSqlc(row))
+	}
+	return subs, nil
+}
@@ -21,6 +21,7 @@ type DomainRepository interface {
 	Update(ctx context.Context, params sqlc.UpdateDomainParams) (*models.Domain, error)
 	SoftDelete(ctx context.Context, id uuid.UUID) error
 	GetCountForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	ListOverLimit(ctx context.Context, maxDomains int64) (map[uuid.UUID]int64, error)
 }
 
 type domainRepository struct {
@@ -105,3 +106,17 @@ func (r *domainRepository) GetCountForWorkspace(ctx context.Context, workspaceID
 	}
 	return count, nil
 }
+
+// ListOverLimit returns the domain count of every workspace with more than
+// maxDomains domains.
+func (r *domainRepository) ListOverLimit(ctx context.Context, maxDomains int64) (map[uuid.UUID]int64, error) {
+	rows, err := r.queries.ListWorkspacesOverDomainLimit(ctx, maxDomains)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list workspaces over domain limit")
+	}
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.WorkspaceID] = row.DomainCount
+	}
+	return counts, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkCommentRepository interface {
+	Create(ctx context.Context, params sqlc.CreateLinkCommentParams) (*models.LinkComment, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.LinkComment, error)
+	ListForLink(ctx context.Context, linkID uuid.UUID, limit, offset int32) ([]*models.LinkComment, error)
+	CountForLink(ctx context.Context, linkID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type linkCommentRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewLinkCommentRepository(queries *sqlc.Queries, logger *zap.Logger) LinkCommentRepository {
+	return &linkCommentRepository{queries: queries, logger: logger}
+}
+
+func (r *linkCommentRepository) Create(ctx context.Context, params sqlc.CreateLinkCommentParams) (*models.LinkComment, error) {
+	row, err := r.queries.CreateLinkComment(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to create link comment")
+	}
+	return models.LinkCommentFromSqlcRow(sqlc.ListLinkCommentsRow(row)), nil
+}
+
+func (r *linkCommentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.LinkComment, error) {
+	comment, err := r.queries.GetLinkCommentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("comment")
+		}
+		return nil, httputil.Wrap(err, "failed to get link comment")
+	}
+	return models.LinkCommentFromSqlc(comment), nil
+}
+
+func (r *linkCommentRepository) ListForLink(ctx context.Context, linkID uuid.UUID, limit, offset int32) ([]*models.LinkComment, error) {
+	rows, err := r.queries.ListLinkComments(ctx, sqlc.ListLinkCommentsParams{
+		LinkID: linkID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list link comments")
+	}
+	result := make([]*models.LinkComment, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, models.LinkCommentFromSqlcRow(row))
+	}
+	return result, nil
+}
+
+func (r *linkCommentRepository) CountForLink(ctx context.Context, linkID uuid.UUID) (int64, error) {
+	count, err := r.queries.CountLinkComments(ctx, linkID)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count link comments")
+	}
+	return count, nil
+}
+
+func (r *linkCommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.DeleteLinkComment(ctx, id); err != nil {
+		return httputil.Wrap(err, "failed to delete link comment")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkFlagRepository interface {
+	Upsert(ctx context.Context, params sqlc.UpsertLinkFlagParams) (*models.LinkFlag, error)
+	Get(ctx context.Context, linkID uuid.UUID) (*models.LinkFlag, error)
+	ListByStatus(ctx context.Context, status string, limit, offset int32) ([]*models.LinkFlag, error)
+	CountByStatus(ctx context.Context, status string) (int64, error)
+	Review(ctx context.Context, linkID, reviewerID uuid.UUID, status string) (*models.LinkFlag, error)
+}
+
+type linkFlagRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewLinkFlagRepository(queries *sqlc.Queries, logger *zap.Logger) LinkFlagRepository {
+	return &linkFlagRepository{queries: queries, logger: logger}
+}
+
+func (r *linkFlagRepository) Upsert(ctx context.Context, params sqlc.UpsertLinkFlagParams) (*models.LinkFlag, error) {
+	flag, err := r.queries.UpsertLinkFlag(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to flag link")
+	}
+	return models.LinkFlagFromSqlc(flag), nil
+}
+
+func (r *linkFlagRepository) Get(ctx context.Context, linkID uuid.UUID) (*models.LinkFlag, error) {
+	flag, err := r.queries.GetLinkFlag(ctx, linkID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link flag")
+		}
+		return nil, httputil.Wrap(err, "failed to get link flag")
+	}
+	return models.LinkFlagFromSqlc(flag), nil
+}
+
+func (r *linkFlagRepository) ListByStatus(ctx context.Context, status string, limit, offset int32) ([]*models.LinkFlag, error) {
+	flags, err := r.queries.ListLinkFlagsByStatus(ctx, sqlc.ListLinkFlagsByStatusParams{
+		Status: status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list link flags")
+	}
+	result := make([]*models.LinkFlag, 0, len(flags))
+	for _, f := range flags {
+		result = append(result, models.LinkFlagFromSqlc(f))
+	}
+	return result, nil
+}
+
+func (r *linkFlagRepository) CountByStatus(ctx context.Context, status string) (int64, error) {
+	count, err := r.queries.CountLinkFlagsByStatus(ctx, status)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count link flags")
+	}
+	return count, nil
+}
+
+// Review records an admin's decision on a pending flag. It returns NotFound
+// if the link has no pending flag.
+func (r *linkFlagRepository) Review(ctx context.Context, linkID, reviewerID uuid.UUID, status string) (*models.LinkFlag, error) {
+	flag, err := r.queries.ReviewLinkFlag(ctx, sqlc.ReviewLinkFlagParams{
+		LinkID:     linkID,
+		Status:     status,
+		ReviewedBy: pgtype.UUID{Bytes: reviewerID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("pending link flag")
+		}
+		return nil, httputil.Wrap(err, "failed to review link flag")
+	}
+	return models.LinkFlagFromSqlc(flag), nil
+}
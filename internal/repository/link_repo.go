@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
 	"github.com/link-rift/link-rift/pkg/httputil"
@@ -19,13 +21,57 @@ type LinkRepository interface {
 	GetByShortCode(ctx context.Context, shortCode string) (*models.Link, error)
 	GetByURL(ctx context.Context, params sqlc.GetLinkByURLParams) (*models.Link, error)
 	List(ctx context.Context, params sqlc.ListLinksForWorkspaceParams) ([]*models.Link, int64, error)
+	// ListMostClicked returns up to limit redirectable links across all
+	// workspaces, most clicked first.
+	ListMostClicked(ctx context.Context, limit int) ([]*models.Link, error)
+	// ListWithFailovers returns up to limit redirectable links that have
+	// failover URLs, ordered by ID and starting after afterID.
+	ListWithFailovers(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Link, error)
 	Update(ctx context.Context, params sqlc.UpdateLinkParams) (*models.Link, error)
+	UpdateMetadata(ctx context.Context, params sqlc.UpdateLinkMetadataParams) (*models.Link, error)
+	Transfer(ctx context.Context, params sqlc.TransferLinkParams) (*models.Link, error)
+	Archive(ctx context.Context, id uuid.UUID, deactivate bool) (*models.Link, error)
+	Unarchive(ctx context.Context, id uuid.UUID) (*models.Link, error)
 	SoftDelete(ctx context.Context, id uuid.UUID) error
+	// SoftDeleteForWorkspace deletes every remaining link of a workspace and
+	// returns how many there were.
+	SoftDeleteForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	// Reserve holds params.ShortCode for the workspace with a placeholder
+	// link.
+	Reserve(ctx context.Context, params sqlc.ReserveShortCodeParams) (*models.Link, error)
+	// GetReservation returns the workspace's unexpired reservation of
+	// shortCode.
+	GetReservation(ctx context.Context, workspaceID uuid.UUID, shortCode string) (*models.Link, error)
+	// ClaimReservation turns the workspace's unexpired reservation of
+	// params.ShortCode into a link with the given settings.
+	ClaimReservation(ctx context.Context, params sqlc.CreateLinkParams) (*models.Link, error)
+	// ReleaseExpiredReservation frees shortCode if it is held by an expired
+	// reservation.
+	ReleaseExpiredReservation(ctx context.Context, shortCode string) error
+	// ReleaseExpiredReservations frees every expired reservation and returns
+	// how many there were.
+	ReleaseExpiredReservations(ctx context.Context) (int64, error)
 	ShortCodeExists(ctx context.Context, shortCode string) (bool, error)
+	ShortCodeExistsIgnoreCase(ctx context.Context, shortCode string) (bool, error)
 	IncrementClicks(ctx context.Context, id uuid.UUID) error
+	// AddClicks adds clicks counted without a click event, such as those
+	// skipped by click sampling, to the link's total.
+	AddClicks(ctx context.Context, id uuid.UUID, count int64) error
 	IncrementUniqueClicks(ctx context.Context, id uuid.UUID) error
+	ReconcileUniqueClicks(ctx context.Context, since time.Time, window time.Duration) (int64, error)
 	GetQuickStats(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error)
-	GetCountForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	// CountCreatedForWorkspace counts the links a workspace created in
+	// [start, end), as metered for its usage.
+	CountCreatedForWorkspace(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (int64, error)
+	// LockLinkLimit serializes the workspace's link limit checks until the
+	// transaction ends. It must be called inside a transaction.
+	LockLinkLimit(ctx context.Context, workspaceID uuid.UUID) error
+	ListIDsByTag(ctx context.Context, workspaceID, tagID uuid.UUID) ([]uuid.UUID, error)
+	AddTag(ctx context.Context, linkID, tagID uuid.UUID) error
+	// EnsureTag returns the ID of the workspace tag with name, creating it
+	// if needed.
+	EnsureTag(ctx context.Context, workspaceID uuid.UUID, name string) (uuid.UUID, error)
+	CountWorkspaceTags(ctx context.Context, workspaceID uuid.UUID, tagIDs []uuid.UUID) (int64, error)
 }
 
 type linkRepository struct {
@@ -98,6 +144,35 @@ func (r *linkRepository) List(ctx context.Context, params sqlc.ListLinksForWorks
 	return links, total, nil
 }
 
+func (r *linkRepository) ListMostClicked(ctx context.Context, limit int) ([]*models.Link, error) {
+	rows, err := r.queries.ListMostClickedLinks(ctx, int32(limit))
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list most clicked links")
+	}
+
+	links := make([]*models.Link, 0, len(rows))
+	for _, row := range rows {
+		links = append(links, models.LinkFromSqlc(row))
+	}
+	return links, nil
+}
+
+func (r *linkRepository) ListWithFailovers(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Link, error) {
+	rows, err := r.queries.ListLinksWithFailovers(ctx, sqlc.ListLinksWithFailoversParams{
+		ID:    afterID,
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list links with failovers")
+	}
+
+	links := make([]*models.Link, 0, len(rows))
+	for _, row := range rows {
+		links = append(links, models.LinkFromSqlc(row))
+	}
+	return links, nil
+}
+
 func (r *linkRepository) Update(ctx context.Context, params sqlc.UpdateLinkParams) (*models.Link, error) {
 	l, err := r.queries.UpdateLink(ctx, params)
 	if err != nil {
@@ -109,6 +184,54 @@ func (r *linkRepository) Update(ctx context.Context, params sqlc.UpdateLinkParam
 	return models.LinkFromSqlc(l), nil
 }
 
+func (r *linkRepository) UpdateMetadata(ctx context.Context, params sqlc.UpdateLinkMetadataParams) (*models.Link, error) {
+	l, err := r.queries.UpdateLinkMetadata(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link")
+		}
+		return nil, httputil.Wrap(err, "failed to update link metadata")
+	}
+	return models.LinkFromSqlc(l), nil
+}
+
+func (r *linkRepository) Transfer(ctx context.Context, params sqlc.TransferLinkParams) (*models.Link, error) {
+	l, err := r.queries.TransferLink(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link")
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, httputil.AlreadyExists("short_code")
+		}
+		return nil, httputil.Wrap(err, "failed to transfer link")
+	}
+	return models.LinkFromSqlc(l), nil
+}
+
+func (r *linkRepository) Archive(ctx context.Context, id uuid.UUID, deactivate bool) (*models.Link, error) {
+	l, err := r.queries.ArchiveLink(ctx, sqlc.ArchiveLinkParams{ID: id, Deactivate: deactivate})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link")
+		}
+		return nil, httputil.Wrap(err, "failed to archive link")
+	}
+	return models.LinkFromSqlc(l), nil
+}
+
+func (r *linkRepository) Unarchive(ctx context.Context, id uuid.UUID) (*models.Link, error) {
+	l, err := r.queries.UnarchiveLink(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link")
+		}
+		return nil, httputil.Wrap(err, "failed to unarchive link")
+	}
+	return models.LinkFromSqlc(l), nil
+}
+
 func (r *linkRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
 	err := r.queries.SoftDeleteLink(ctx, id)
 	if err != nil {
@@ -117,6 +240,66 @@ func (r *linkRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+func (r *linkRepository) SoftDeleteForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	n, err := r.queries.SoftDeleteWorkspaceLinks(ctx, workspaceID)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to delete workspace links")
+	}
+	return n, nil
+}
+
+func (r *linkRepository) Reserve(ctx context.Context, params sqlc.ReserveShortCodeParams) (*models.Link, error) {
+	l, err := r.queries.ReserveShortCode(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, httputil.AlreadyExists("short_code")
+		}
+		return nil, httputil.Wrap(err, "failed to reserve short code")
+	}
+	return models.LinkFromSqlc(l), nil
+}
+
+func (r *linkRepository) GetReservation(ctx context.Context, workspaceID uuid.UUID, shortCode string) (*models.Link, error) {
+	l, err := r.queries.GetShortCodeReservation(ctx, sqlc.GetShortCodeReservationParams{
+		WorkspaceID: workspaceID,
+		ShortCode:   shortCode,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("reservation")
+		}
+		return nil, httputil.Wrap(err, "failed to get reservation")
+	}
+	return models.LinkFromSqlc(l), nil
+}
+
+func (r *linkRepository) ClaimReservation(ctx context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+	l, err := r.queries.ClaimReservedLink(ctx, sqlc.ClaimReservedLinkParams(params))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("reservation")
+		}
+		return nil, httputil.Wrap(err, "failed to claim reservation")
+	}
+	return models.LinkFromSqlc(l), nil
+}
+
+func (r *linkRepository) ReleaseExpiredReservation(ctx context.Context, shortCode string) error {
+	if err := r.queries.ReleaseExpiredReservation(ctx, shortCode); err != nil {
+		return httputil.Wrap(err, "failed to release expired reservation")
+	}
+	return nil
+}
+
+func (r *linkRepository) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	n, err := r.queries.ReleaseExpiredReservations(ctx)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to release expired reservations")
+	}
+	return n, nil
+}
+
 func (r *linkRepository) ShortCodeExists(ctx context.Context, shortCode string) (bool, error) {
 	exists, err := r.queries.ShortCodeExists(ctx, shortCode)
 	if err != nil {
@@ -125,6 +308,14 @@ func (r *linkRepository) ShortCodeExists(ctx context.Context, shortCode string)
 	return exists, nil
 }
 
+func (r *linkRepository) ShortCodeExistsIgnoreCase(ctx context.Context, shortCode string) (bool, error) {
+	exists, err := r.queries.ShortCodeExistsIgnoreCase(ctx, shortCode)
+	if err != nil {
+		return false, httputil.Wrap(err, "failed to check short code")
+	}
+	return exists, nil
+}
+
 func (r *linkRepository) IncrementClicks(ctx context.Context, id uuid.UUID) error {
 	err := r.queries.IncrementLinkClicks(ctx, id)
 	if err != nil {
@@ -133,6 +324,14 @@ func (r *linkRepository) IncrementClicks(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+func (r *linkRepository) AddClicks(ctx context.Context, id uuid.UUID, count int64) error {
+	err := r.queries.IncrementLinkClicksBy(ctx, sqlc.IncrementLinkClicksByParams{Count: count, ID: id})
+	if err != nil {
+		return httputil.Wrap(err, "failed to add clicks")
+	}
+	return nil
+}
+
 func (r *linkRepository) IncrementUniqueClicks(ctx context.Context, id uuid.UUID) error {
 	err := r.queries.IncrementLinkUniqueClicks(ctx, id)
 	if err != nil {
@@ -141,6 +340,21 @@ func (r *linkRepository) IncrementUniqueClicks(ctx context.Context, id uuid.UUID
 	return nil
 }
 
+// ReconcileUniqueClicks recomputes the unique click counter of every link
+// clicked since the given time from the clicks table. A click is unique when
+// the same visitor has no non-bot click on the link in the preceding window;
+// clicks recorded without a visitor ID fall back to the IP address.
+func (r *linkRepository) ReconcileUniqueClicks(ctx context.Context, since time.Time, window time.Duration) (int64, error) {
+	n, err := r.queries.ReconcileLinkUniqueClicks(ctx, sqlc.ReconcileLinkUniqueClicksParams{
+		WindowSeconds: window.Seconds(),
+		Since:         pgtype.Timestamptz{Time: since, Valid: true},
+	})
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to reconcile unique clicks")
+	}
+	return n, nil
+}
+
 func (r *linkRepository) GetQuickStats(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error) {
 	row, err := r.queries.GetLinkQuickStats(ctx, id)
 	if err != nil {
@@ -163,10 +377,58 @@ func (r *linkRepository) GetQuickStats(ctx context.Context, id uuid.UUID) (*mode
 	return stats, nil
 }
 
-func (r *linkRepository) GetCountForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
-	count, err := r.queries.GetLinkCountForWorkspace(ctx, workspaceID)
+func (r *linkRepository) CountCreatedForWorkspace(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (int64, error) {
+	count, err := r.queries.CountLinksCreatedForWorkspace(ctx, sqlc.CountLinksCreatedForWorkspaceParams{
+		WorkspaceID: workspaceID,
+		PeriodStart: pgtype.Timestamptz{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Timestamptz{Time: end, Valid: true},
+	})
 	if err != nil {
 		return 0, httputil.Wrap(err, "failed to get link count")
 	}
 	return count, nil
 }
+
+func (r *linkRepository) LockLinkLimit(ctx context.Context, workspaceID uuid.UUID) error {
+	if err := r.queries.LockWorkspaceLinkLimit(ctx, workspaceID); err != nil {
+		return httputil.Wrap(err, "failed to lock link limit")
+	}
+	return nil
+}
+
+func (r *linkRepository) ListIDsByTag(ctx context.Context, workspaceID, tagID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := r.queries.ListLinkIDsByTag(ctx, sqlc.ListLinkIDsByTagParams{
+		TagID:       tagID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list links by tag")
+	}
+	return ids, nil
+}
+
+func (r *linkRepository) AddTag(ctx context.Context, linkID, tagID uuid.UUID) error {
+	if err := r.queries.AddLinkTag(ctx, sqlc.AddLinkTagParams{LinkID: linkID, TagID: tagID}); err != nil {
+		return httputil.Wrap(err, "failed to tag link")
+	}
+	return nil
+}
+
+func (r *linkRepository) EnsureTag(ctx context.Context, workspaceID uuid.UUID, name string) (uuid.UUID, error) {
+	id, err := r.queries.UpsertTag(ctx, sqlc.UpsertTagParams{WorkspaceID: workspaceID, Name: name})
+	if err != nil {
+		return uuid.Nil, httputil.Wrap(err, "failed to save tag")
+	}
+	return id, nil
+}
+
+func (r *linkRepository) CountWorkspaceTags(ctx context.Context, workspaceID uuid.UUID, tagIDs []uuid.UUID) (int64, error) {
+	count, err := r.queries.CountWorkspaceTags(ctx, sqlc.CountWorkspaceTagsParams{
+		WorkspaceID: workspaceID,
+		TagIds:      tagIDs,
+	})
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count workspace tags")
+	}
+	return count, nil
+}
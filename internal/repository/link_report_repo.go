@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkReportRepository interface {
+	Upsert(ctx context.Context, params sqlc.UpsertLinkReportParams) (*models.LinkReport, error)
+	CountSinceReview(ctx context.Context, linkID uuid.UUID) (int64, error)
+	ListForLink(ctx context.Context, linkID uuid.UUID) ([]*models.LinkReport, error)
+}
+
+type linkReportRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewLinkReportRepository(queries *sqlc.Queries, logger *zap.Logger) LinkReportRepository {
+	return &linkReportRepository{queries: queries, logger: logger}
+}
+
+func (r *linkReportRepository) Upsert(ctx context.Context, params sqlc.UpsertLinkReportParams) (*models.LinkReport, error) {
+	report, err := r.queries.UpsertLinkReport(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to save link report")
+	}
+	return models.LinkReportFromSqlc(report), nil
+}
+
+// CountSinceReview counts the reports filed since the link's flag was last
+// approved, so an approval starts the count again.
+func (r *linkReportRepository) CountSinceReview(ctx context.Context, linkID uuid.UUID) (int64, error) {
+	count, err := r.queries.CountLinkReportsSinceReview(ctx, linkID)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count link reports")
+	}
+	return count, nil
+}
+
+func (r *linkReportRepository) ListForLink(ctx context.Context, linkID uuid.UUID) ([]*models.LinkReport, error) {
+	reports, err := r.queries.ListLinkReportsForLink(ctx, linkID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list link reports")
+	}
+	result := make([]*models.LinkReport, 0, len(reports))
+	for _, report := range reports {
+		result = append(result, models.LinkReportFromSqlc(report))
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkRuleRepository interface {
+	Create(ctx context.Context, params sqlc.CreateLinkRuleParams) (*models.LinkRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.LinkRule, error)
+	ListForLink(ctx context.Context, linkID uuid.UUID) ([]*models.LinkRule, error)
+	Update(ctx context.Context, params sqlc.UpdateLinkRuleParams) (*models.LinkRule, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type linkRuleRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewLinkRuleRepository(queries *sqlc.Queries, logger *zap.Logger) LinkRuleRepository {
+	return &linkRuleRepository{queries: queries, logger: logger}
+}
+
+func (r *linkRuleRepository) Create(ctx context.Context, params sqlc.CreateLinkRuleParams) (*models.LinkRule, error) {
+	rule, err := r.queries.CreateLinkRule(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to create link rule")
+	}
+	return models.LinkRuleFromSqlc(rule), nil
+}
+
+func (r *linkRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.LinkRule, error) {
+	rule, err := r.queries.GetLinkRuleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link rule")
+		}
+		return nil, httputil.Wrap(err, "failed to get link rule")
+	}
+	return models.LinkRuleFromSqlc(rule), nil
+}
+
+func (r *linkRuleRepository) ListForLink(ctx context.Context, linkID uuid.UUID) ([]*models.LinkRule, error) {
+	rules, err := r.queries.ListRulesForLink(ctx, linkID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list link rules")
+	}
+	result := make([]*models.LinkRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, models.LinkRuleFromSqlc(rule))
+	}
+	return result, nil
+}
+
+func (r *linkRuleRepository) Update(ctx context.Context, params sqlc.UpdateLinkRuleParams) (*models.LinkRule, error) {
+	rule, err := r.queries.UpdateLinkRule(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link rule")
+		}
+		return nil, httputil.Wrap(err, "failed to update link rule")
+	}
+	return models.LinkRuleFromSqlc(rule), nil
+}
+
+func (r *linkRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.DeleteLinkRule(ctx, id); err != nil {
+		return httputil.Wrap(err, "failed to delete link rule")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type LinkTemplateRepository interface {
+	Create(ctx context.Context, params sqlc.CreateLinkTemplateParams) (*models.LinkTemplate, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.LinkTemplate, error)
+	List(ctx context.Context, workspaceID uuid.UUID) ([]*models.LinkTemplate, error)
+	Count(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	Update(ctx context.Context, params sqlc.UpdateLinkTemplateParams) (*models.LinkTemplate, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type linkTemplateRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewLinkTemplateRepository(queries *sqlc.Queries, logger *zap.Logger) LinkTemplateRepository {
+	return &linkTemplateRepository{queries: queries, logger: logger}
+}
+
+func (r *linkTemplateRepository) Create(ctx context.Context, params sqlc.CreateLinkTemplateParams) (*models.LinkTemplate, error) {
+	tmpl, err := r.queries.CreateLinkTemplate(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, httputil.AlreadyExists("link template")
+		}
+		return nil, httputil.Wrap(err, "failed to create link template")
+	}
+	return models.LinkTemplateFromSqlc(tmpl), nil
+}
+
+func (r *linkTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.LinkTemplate, error) {
+	tmpl, err := r.queries.GetLinkTemplateByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link template")
+		}
+		return nil, httputil.Wrap(err, "failed to get link template")
+	}
+	return models.LinkTemplateFromSqlc(tmpl), nil
+}
+
+func (r *linkTemplateRepository) List(ctx context.Context, workspaceID uuid.UUID) ([]*models.LinkTemplate, error) {
+	templates, err := r.queries.ListLinkTemplatesForWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list link templates")
+	}
+	result := make([]*models.LinkTemplate, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, models.LinkTemplateFromSqlc(t))
+	}
+	return result, nil
+}
+
+func (r *linkTemplateRepository) Count(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	count, err := r.queries.CountLinkTemplatesForWorkspace(ctx, workspaceID)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count link templates")
+	}
+	return count, nil
+}
+
+func (r *linkTemplateRepository) Update(ctx context.Context, params sqlc.UpdateLinkTemplateParams) (*models.LinkTemplate, error) {
+	tmpl, err := r.queries.UpdateLinkTemplate(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("link template")
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, httputil.AlreadyExists("link template")
+		}
+		return nil, httputil.Wrap(err, "failed to update link template")
+	}
+	return models.LinkTemplateFromSqlc(tmpl), nil
+}
+
+func (r *linkTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.DeleteLinkTemplate(ctx, id); err != nil {
+		return httputil.Wrap(err, "failed to delete link template")
+	}
+	return nil
+}
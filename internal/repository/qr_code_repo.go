@@ -17,6 +17,9 @@ type QRCodeRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.QRCode, error)
 	GetByLinkID(ctx context.Context, linkID uuid.UUID) (*models.QRCode, error)
 	ListForLink(ctx context.Context, linkID uuid.UUID) ([]*models.QRCode, error)
+	// ListForWorkspace returns the QR codes of all the workspace's links,
+	// deleted ones included.
+	ListForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]*models.QRCode, error)
 	Update(ctx context.Context, params sqlc.UpdateQRCodeParams) (*models.QRCode, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	IncrementScanCount(ctx context.Context, id uuid.UUID) error
@@ -74,6 +77,19 @@ func (r *qrCodeRepository) ListForLink(ctx context.Context, linkID uuid.UUID) ([
 	return qrs, nil
 }
 
+func (r *qrCodeRepository) ListForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]*models.QRCode, error) {
+	rows, err := r.queries.ListQRCodesForWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list workspace QR codes")
+	}
+
+	qrs := make([]*models.QRCode, 0, len(rows))
+	for _, row := range rows {
+		qrs = append(qrs, models.QRCodeFromSqlc(row))
+	}
+	return qrs, nil
+}
+
 func (r *qrCodeRepository) Update(ctx context.Context, params sqlc.UpdateQRCodeParams) (*models.QRCode, error) {
 	q, err := r.queries.UpdateQRCode(ctx, params)
 	if err != nil {
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type ScheduledReportRepository interface {
+	Create(ctx context.Context, params sqlc.CreateScheduledReportParams) (*models.ScheduledReport, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ScheduledReport, error)
+	List(ctx context.Context, workspaceID uuid.UUID) ([]*models.ScheduledReport, error)
+	Count(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	Update(ctx context.Context, params sqlc.UpdateScheduledReportParams) (*models.ScheduledReport, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDue returns active reports whose next run is at or before now,
+	// oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int32) ([]*models.ScheduledReport, error)
+	// RecordRun stores the outcome of a run and when the report runs next.
+	// runErr is empty after a successful run.
+	RecordRun(ctx context.Context, id uuid.UUID, ranAt, nextRunAt time.Time, runErr string) error
+}
+
+type scheduledReportRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewScheduledReportRepository(queries *sqlc.Queries, logger *zap.Logger) ScheduledReportRepository {
+	return &scheduledReportRepository{queries: queries, logger: logger}
+}
+
+func (r *scheduledReportRepository) Create(ctx context.Context, params sqlc.CreateScheduledReportParams) (*models.ScheduledReport, error) {
+	report, err := r.queries.CreateScheduledReport(ctx, params)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to create scheduled report")
+	}
+	return models.ScheduledReportFromSqlc(report), nil
+}
+
+func (r *scheduledReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ScheduledReport, error) {
+	report, err := r.queries.GetScheduledReportByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("scheduled report")
+		}
+		return nil, httputil.Wrap(err, "failed to get scheduled report")
+	}
+	return models.ScheduledReportFromSqlc(report), nil
+}
+
+func (r *scheduledReportRepository) List(ctx context.Context, workspaceID uuid.UUID) ([]*models.ScheduledReport, error) {
+	reports, err := r.queries.ListScheduledReportsForWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list scheduled reports")
+	}
+	return scheduledReportsFromSqlc(reports), nil
+}
+
+func (r *scheduledReportRepository) Count(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	count, err := r.queries.CountScheduledReportsForWorkspace(ctx, workspaceID)
+	if err != nil {
+		return 0, httputil.Wrap(err, "failed to count scheduled reports")
+	}
+	return count, nil
+}
+
+func (r *scheduledReportRepository) Update(ctx context.Context, params sqlc.UpdateScheduledReportParams) (*models.ScheduledReport, error) {
+	report, err := r.queries.UpdateScheduledReport(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("scheduled report")
+		}
+		return nil, httputil.Wrap(err, "failed to update scheduled report")
+	}
+	return models.ScheduledReportFromSqlc(report), nil
+}
+
+func (r *scheduledReportRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.DeleteScheduledReport(ctx, id); err != nil {
+		return httputil.Wrap(err, "failed to delete scheduled report")
+	}
+	return nil
+}
+
+func (r *scheduledReportRepository) ListDue(ctx context.Context, now time.Time, limit int32) ([]*models.ScheduledReport, error) {
+	reports, err := r.queries.ListDueScheduledReports(ctx, sqlc.ListDueScheduledReportsParams{
+		Now:   pgtype.Timestamptz{Time: now, Valid: true},
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list due scheduled reports")
+	}
+	return scheduledReportsFromSqlc(reports), nil
+}
+
+func (r *scheduledReportRepository) RecordRun(ctx context.Context, id uuid.UUID, ranAt, nextRunAt time.Time, runErr string) error {
+	err := r.queries.RecordScheduledReportRun(ctx, sqlc.RecordScheduledReportRunParams{
+		ID:        id,
+		LastRunAt: pgtype.Timestamptz{Time: ranAt, Valid: true},
+		NextRunAt: pgtype.Timestamptz{Time: nextRunAt, Valid: true},
+		LastError: pgtype.Text{String: runErr, Valid: runErr != ""},
+	})
+	if err != nil {
+		return httputil.Wrap(err, "failed to record scheduled report run")
+	}
+	return nil
+}
+
+func scheduledReportsFromSqlc(reports []sqlc.ScheduledReport) []*models.ScheduledReport {
+	result := make([]*models.ScheduledReport, 0, len(reports))
+	for _, r := range reports {
+		result = append(result, models.ScheduledReportFromSqlc(r))
+	}
+	return result
+}
@@ -15,6 +15,7 @@ import (
 type SessionRepository interface {
 	Create(ctx context.Context, params sqlc.CreateSessionParams) (*models.Session, error)
 	GetByRefreshTokenHash(ctx context.Context, tokenHash string) (*models.Session, error)
+	GetActiveByID(ctx context.Context, id uuid.UUID) (*models.Session, error)
 	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Session, error)
 	Revoke(ctx context.Context, id uuid.UUID) error
 	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
@@ -49,6 +50,17 @@ func (r *sessionRepository) GetByRefreshTokenHash(ctx context.Context, tokenHash
 	return models.SessionFromSqlc(s), nil
 }
 
+func (r *sessionRepository) GetActiveByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	s, err := r.queries.GetActiveSessionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("session")
+		}
+		return nil, httputil.Wrap(err, "failed to get session")
+	}
+	return models.SessionFromSqlc(s), nil
+}
+
 func (r *sessionRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Session, error) {
 	sessions, err := r.queries.ListUserSessions(ctx, userID)
 	if err != nil {
@@ -258,9 +258,9 @@ func (q *Queries) GetBioPageCountForWorkspace(ctx context.Context, workspaceID u
 // ============================================================================
 
 const createBioPageLink = `-- name: CreateBioPageLink :one
-INSERT INTO bio_page_links (bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at
+INSERT INTO bio_page_links (bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, link_type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at, link_type
 `
 
 type CreateBioPageLinkParams struct {
@@ -272,6 +272,7 @@ type CreateBioPageLinkParams struct {
 	IsVisible    bool               `json:"is_visible"`
 	VisibleFrom  pgtype.Timestamptz `json:"visible_from"`
 	VisibleUntil pgtype.Timestamptz `json:"visible_until"`
+	LinkType     string             `json:"link_type"`
 }
 
 func (q *Queries) CreateBioPageLink(ctx context.Context, arg CreateBioPageLinkParams) (BioPageLink, error) {
@@ -284,6 +285,7 @@ func (q *Queries) CreateBioPageLink(ctx context.Context, arg CreateBioPageLinkPa
 		arg.IsVisible,
 		arg.VisibleFrom,
 		arg.VisibleUntil,
+		arg.LinkType,
 	)
 	var i BioPageLink
 	err := row.Scan(
@@ -299,12 +301,13 @@ func (q *Queries) CreateBioPageLink(ctx context.Context, arg CreateBioPageLinkPa
 		&i.ClickCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LinkType,
 	)
 	return i, err
 }
 
 const getBioPageLinkByID = `-- name: GetBioPageLinkByID :one
-SELECT id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at FROM bio_page_links
+SELECT id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at, link_type FROM bio_page_links
 WHERE id = $1
 `
 
@@ -324,12 +327,13 @@ func (q *Queries) GetBioPageLinkByID(ctx context.Context, id uuid.UUID) (BioPage
 		&i.ClickCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LinkType,
 	)
 	return i, err
 }
 
 const listBioPageLinks = `-- name: ListBioPageLinks :many
-SELECT id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at FROM bio_page_links
+SELECT id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at, link_type FROM bio_page_links
 WHERE bio_page_id = $1
 ORDER BY position ASC
 `
@@ -356,6 +360,7 @@ func (q *Queries) ListBioPageLinks(ctx context.Context, bioPageID uuid.UUID) ([]
 			&i.ClickCount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.LinkType,
 		); err != nil {
 			return nil, err
 		}
@@ -376,9 +381,10 @@ SET
     is_visible = COALESCE($5, is_visible),
     visible_from = COALESCE($6, visible_from),
     visible_until = COALESCE($7, visible_until),
+    link_type = COALESCE($8, link_type),
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at
+RETURNING id, bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, click_count, created_at, updated_at, link_type
 `
 
 type UpdateBioPageLinkParams struct {
@@ -389,6 +395,7 @@ type UpdateBioPageLinkParams struct {
 	IsVisible    pgtype.Bool        `json:"is_visible"`
 	VisibleFrom  pgtype.Timestamptz `json:"visible_from"`
 	VisibleUntil pgtype.Timestamptz `json:"visible_until"`
+	LinkType     pgtype.Text        `json:"link_type"`
 }
 
 func (q *Queries) UpdateBioPageLink(ctx context.Context, arg UpdateBioPageLinkParams) (BioPageLink, error) {
@@ -400,6 +407,7 @@ func (q *Queries) UpdateBioPageLink(ctx context.Context, arg UpdateBioPageLinkPa
 		arg.IsVisible,
 		arg.VisibleFrom,
 		arg.VisibleUntil,
+		arg.LinkType,
 	)
 	var i BioPageLink
 	err := row.Scan(
@@ -415,6 +423,7 @@ func (q *Queries) UpdateBioPageLink(ctx context.Context, arg UpdateBioPageLinkPa
 		&i.ClickCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.LinkType,
 	)
 	return i, err
 }
@@ -467,3 +476,15 @@ func (q *Queries) GetMaxBioPageLinkPosition(ctx context.Context, bioPageID uuid.
 	err := row.Scan(&maxPosition)
 	return maxPosition, err
 }
+
+const getBioPageLinkCount = `-- name: GetBioPageLinkCount :one
+SELECT COUNT(*) AS count FROM bio_page_links
+WHERE bio_page_id = $1
+`
+
+func (q *Queries) GetBioPageLinkCount(ctx context.Context, bioPageID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getBioPageLinkCount, bioPageID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
@@ -13,7 +13,7 @@ import (
 )
 
 const getClicksByLinkID = `-- name: GetClicksByLinkID :many
-SELECT id, link_id, clicked_at, visitor_id, ip_address, user_agent, referer, country_code, region, city, device_type, browser, browser_version, os, os_version, is_bot, utm_source, utm_medium, utm_campaign FROM clicks
+SELECT id, link_id, clicked_at, visitor_id, ip_address, user_agent, referer, country_code, region, city, device_type, browser, browser_version, os, os_version, is_bot, utm_source, utm_medium, utm_campaign, source FROM clicks
 WHERE link_id = $1
     AND clicked_at >= $2
     AND clicked_at <= $3
@@ -64,6 +64,7 @@ func (q *Queries) GetClicksByLinkID(ctx context.Context, arg GetClicksByLinkIDPa
 			&i.UtmSource,
 			&i.UtmMedium,
 			&i.UtmCampaign,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -79,9 +80,9 @@ const insertClick = `-- name: InsertClick :exec
 INSERT INTO clicks (
     link_id, clicked_at, visitor_id, ip_address, user_agent, referer,
     country_code, region, city, device_type, browser, browser_version,
-    os, os_version, is_bot, utm_source, utm_medium, utm_campaign
+    os, os_version, is_bot, utm_source, utm_medium, utm_campaign, source
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 `
 
 type InsertClickParams struct {
@@ -103,6 +104,7 @@ type InsertClickParams struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 func (q *Queries) InsertClick(ctx context.Context, arg InsertClickParams) error {
@@ -125,6 +127,7 @@ func (q *Queries) InsertClick(ctx context.Context, arg InsertClickParams) error
 		arg.UtmSource,
 		arg.UtmMedium,
 		arg.UtmCampaign,
+		arg.Source,
 	)
 	return err
 }
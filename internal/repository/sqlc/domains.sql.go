@@ -15,7 +15,7 @@ import (
 const createDomain = `-- name: CreateDomain :one
 INSERT INTO domains (workspace_id, domain)
 VALUES ($1, $2)
-RETURNING id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at, not_found_mode, not_found_status
 `
 
 type CreateDomainParams struct {
@@ -41,12 +41,14 @@ func (q *Queries) CreateDomain(ctx context.Context, arg CreateDomainParams) (Dom
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.NotFoundMode,
+		&i.NotFoundStatus,
 	)
 	return i, err
 }
 
 const getDomainByDomain = `-- name: GetDomainByDomain :one
-SELECT id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at FROM domains
+SELECT id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at, not_found_mode, not_found_status FROM domains
 WHERE domain = $1 AND deleted_at IS NULL
 `
 
@@ -68,12 +70,14 @@ func (q *Queries) GetDomainByDomain(ctx context.Context, domain string) (Domain,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.NotFoundMode,
+		&i.NotFoundStatus,
 	)
 	return i, err
 }
 
 const getDomainByID = `-- name: GetDomainByID :one
-SELECT id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at FROM domains
+SELECT id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at, not_found_mode, not_found_status FROM domains
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -95,12 +99,14 @@ func (q *Queries) GetDomainByID(ctx context.Context, id uuid.UUID) (Domain, erro
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.NotFoundMode,
+		&i.NotFoundStatus,
 	)
 	return i, err
 }
 
 const listDomainsForWorkspace = `-- name: ListDomainsForWorkspace :many
-SELECT id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at FROM domains
+SELECT id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at, not_found_mode, not_found_status FROM domains
 WHERE workspace_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -129,6 +135,8 @@ func (q *Queries) ListDomainsForWorkspace(ctx context.Context, workspaceID uuid.
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.NotFoundMode,
+			&i.NotFoundStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -174,9 +182,11 @@ SET
     last_dns_check_at = COALESCE($7, last_dns_check_at),
     default_redirect_url = COALESCE($8, default_redirect_url),
     custom_404_url = COALESCE($9, custom_404_url),
+    not_found_mode = COALESCE($10, not_found_mode),
+    not_found_status = COALESCE($11, not_found_status),
     updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at
+RETURNING id, workspace_id, domain, is_verified, verified_at, ssl_status, ssl_expires_at, dns_records, last_dns_check_at, default_redirect_url, custom_404_url, created_at, updated_at, deleted_at, not_found_mode, not_found_status
 `
 
 type UpdateDomainParams struct {
@@ -189,6 +199,8 @@ type UpdateDomainParams struct {
 	LastDnsCheckAt     pgtype.Timestamptz `json:"last_dns_check_at"`
 	DefaultRedirectUrl pgtype.Text        `json:"default_redirect_url"`
 	Custom404Url       pgtype.Text        `json:"custom_404_url"`
+	NotFoundMode       pgtype.Text        `json:"not_found_mode"`
+	NotFoundStatus     pgtype.Int4        `json:"not_found_status"`
 }
 
 func (q *Queries) UpdateDomain(ctx context.Context, arg UpdateDomainParams) (Domain, error) {
@@ -202,6 +214,8 @@ func (q *Queries) UpdateDomain(ctx context.Context, arg UpdateDomainParams) (Dom
 		arg.LastDnsCheckAt,
 		arg.DefaultRedirectUrl,
 		arg.Custom404Url,
+		arg.NotFoundMode,
+		arg.NotFoundStatus,
 	)
 	var i Domain
 	err := row.Scan(
@@ -219,6 +233,43 @@ func (q *Queries) UpdateDomain(ctx context.Context, arg UpdateDomainParams) (Dom
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.NotFoundMode,
+		&i.NotFoundStatus,
 	)
 	return i, err
 }
+
+const listWorkspacesOverDomainLimit = `-- name: ListWorkspacesOverDomainLimit :many
+SELECT d.workspace_id, COUNT(*) AS domain_count
+FROM domains d
+JOIN workspaces w ON w.id = d.workspace_id AND w.deleted_at IS NULL
+WHERE d.deleted_at IS NULL
+GROUP BY d.workspace_id
+HAVING COUNT(*) > $1::bigint
+ORDER BY d.workspace_id
+`
+
+type ListWorkspacesOverDomainLimitRow struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	DomainCount int64     `json:"domain_count"`
+}
+
+func (q *Queries) ListWorkspacesOverDomainLimit(ctx context.Context, maxDomains int64) ([]ListWorkspacesOverDomainLimitRow, error) {
+	rows, err := q.db.Query(ctx, listWorkspacesOverDomainLimit, maxDomains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWorkspacesOverDomainLimitRow{}
+	for rows.Next() {
+		var i ListWorkspacesOverDomainLimitRow
+		if err := rows.Scan(&i.WorkspaceID, &i.DomainCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -125,6 +125,43 @@ func (q *Queries) GetLinkRuleByID(ctx context.Context, id uuid.UUID) (LinkRule,
 	return i, err
 }
 
+const listRulesForLink = `-- name: ListRulesForLink :many
+SELECT id, link_id, rule_type, priority, is_active, conditions, destination_url, weight, created_at, updated_at FROM link_rules
+WHERE link_id = $1
+ORDER BY priority ASC, created_at ASC
+`
+
+func (q *Queries) ListRulesForLink(ctx context.Context, linkID uuid.UUID) ([]LinkRule, error) {
+	rows, err := q.db.Query(ctx, listRulesForLink, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []LinkRule{}
+	for rows.Next() {
+		var i LinkRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.LinkID,
+			&i.RuleType,
+			&i.Priority,
+			&i.IsActive,
+			&i.Conditions,
+			&i.DestinationUrl,
+			&i.Weight,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateLinkRule = `-- name: UpdateLinkRule :one
 UPDATE link_rules
 SET
@@ -12,34 +12,289 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addLinkTag = `-- name: AddLinkTag :exec
+INSERT INTO link_tags (link_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddLinkTagParams struct {
+	LinkID uuid.UUID `json:"link_id"`
+	TagID  uuid.UUID `json:"tag_id"`
+}
+
+func (q *Queries) AddLinkTag(ctx context.Context, arg AddLinkTagParams) error {
+	_, err := q.db.Exec(ctx, addLinkTag, arg.LinkID, arg.TagID)
+	return err
+}
+
+const archiveLink = `-- name: ArchiveLink :one
+UPDATE links
+SET
+    archived_at = NOW(),
+    is_active = CASE WHEN $2::boolean THEN FALSE ELSE is_active END,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
+`
+
+type ArchiveLinkParams struct {
+	ID         uuid.UUID `json:"id"`
+	Deactivate bool      `json:"deactivate"`
+}
+
+func (q *Queries) ArchiveLink(ctx context.Context, arg ArchiveLinkParams) (Link, error) {
+	row := q.db.QueryRow(ctx, archiveLink, arg.ID, arg.Deactivate)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.DomainID,
+		&i.Url,
+		&i.ShortCode,
+		&i.Title,
+		&i.Description,
+		&i.FaviconUrl,
+		&i.OgImageUrl,
+		&i.IsActive,
+		&i.PasswordHash,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.TotalClicks,
+		&i.UniqueClicks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
+	)
+	return i, err
+}
+
+const claimReservedLink = `-- name: ClaimReservedLink :one
+UPDATE links
+SET
+    user_id = $1, domain_id = $3, url = $4,
+    title = $6, description = $7, is_active = $8, password_hash = $9,
+    expires_at = $10, max_clicks = $11,
+    utm_source = $12, utm_medium = $13, utm_campaign = $14, utm_term = $15, utm_content = $16, cloak = $17,
+    forward_params = $18, param_precedence = $19, internal_note = $20, force_https = $21,
+    once_per_visitor = $22, repeat_visit_url = $23, ios_url = $24, android_url = $25, fallback_url = $26,
+    facebook_pixel_id = $27, google_tag_id = $28, interstitial = $29, schedule = $30,
+    password_hint = $31, failover_urls = $32,
+    reserved_until = NULL,
+    created_at = NOW(),
+    updated_at = NOW()
+WHERE workspace_id = $2 AND short_code = $5
+    AND reserved_until > NOW() AND deleted_at IS NULL
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
+`
+
+type ClaimReservedLinkParams struct {
+	UserID          uuid.UUID          `json:"user_id"`
+	WorkspaceID     uuid.UUID          `json:"workspace_id"`
+	DomainID        pgtype.UUID        `json:"domain_id"`
+	Url             string             `json:"url"`
+	ShortCode       string             `json:"short_code"`
+	Title           pgtype.Text        `json:"title"`
+	Description     pgtype.Text        `json:"description"`
+	IsActive        bool               `json:"is_active"`
+	PasswordHash    pgtype.Text        `json:"password_hash"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	MaxClicks       pgtype.Int4        `json:"max_clicks"`
+	UtmSource       pgtype.Text        `json:"utm_source"`
+	UtmMedium       pgtype.Text        `json:"utm_medium"`
+	UtmCampaign     pgtype.Text        `json:"utm_campaign"`
+	UtmTerm         pgtype.Text        `json:"utm_term"`
+	UtmContent      pgtype.Text        `json:"utm_content"`
+	Cloak           bool               `json:"cloak"`
+	ForwardParams   bool               `json:"forward_params"`
+	ParamPrecedence string             `json:"param_precedence"`
+	InternalNote    pgtype.Text        `json:"internal_note"`
+	ForceHttps      bool               `json:"force_https"`
+	OncePerVisitor  bool               `json:"once_per_visitor"`
+	RepeatVisitUrl  pgtype.Text        `json:"repeat_visit_url"`
+	IosUrl          pgtype.Text        `json:"ios_url"`
+	AndroidUrl      pgtype.Text        `json:"android_url"`
+	FallbackUrl     pgtype.Text        `json:"fallback_url"`
+	FacebookPixelID pgtype.Text        `json:"facebook_pixel_id"`
+	GoogleTagID     pgtype.Text        `json:"google_tag_id"`
+	Interstitial    bool               `json:"interstitial"`
+	Schedule        []byte             `json:"schedule"`
+	PasswordHint    pgtype.Text        `json:"password_hint"`
+	FailoverUrls    []string           `json:"failover_urls"`
+}
+
+func (q *Queries) ClaimReservedLink(ctx context.Context, arg ClaimReservedLinkParams) (Link, error) {
+	row := q.db.QueryRow(ctx, claimReservedLink,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.DomainID,
+		arg.Url,
+		arg.ShortCode,
+		arg.Title,
+		arg.Description,
+		arg.IsActive,
+		arg.PasswordHash,
+		arg.ExpiresAt,
+		arg.MaxClicks,
+		arg.UtmSource,
+		arg.UtmMedium,
+		arg.UtmCampaign,
+		arg.UtmTerm,
+		arg.UtmContent,
+		arg.Cloak,
+		arg.ForwardParams,
+		arg.ParamPrecedence,
+		arg.InternalNote,
+		arg.ForceHttps,
+		arg.OncePerVisitor,
+		arg.RepeatVisitUrl,
+		arg.IosUrl,
+		arg.AndroidUrl,
+		arg.FallbackUrl,
+		arg.FacebookPixelID,
+		arg.GoogleTagID,
+		arg.Interstitial,
+		arg.Schedule,
+		arg.PasswordHint,
+		arg.FailoverUrls,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.DomainID,
+		&i.Url,
+		&i.ShortCode,
+		&i.Title,
+		&i.Description,
+		&i.FaviconUrl,
+		&i.OgImageUrl,
+		&i.IsActive,
+		&i.PasswordHash,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.TotalClicks,
+		&i.UniqueClicks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
+	)
+	return i, err
+}
+
+const countWorkspaceTags = `-- name: CountWorkspaceTags :one
+SELECT COUNT(*) FROM tags
+WHERE workspace_id = $1 AND id = ANY($2::uuid[])
+`
+
+type CountWorkspaceTagsParams struct {
+	WorkspaceID uuid.UUID   `json:"workspace_id"`
+	TagIds      []uuid.UUID `json:"tag_ids"`
+}
+
+func (q *Queries) CountWorkspaceTags(ctx context.Context, arg CountWorkspaceTagsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countWorkspaceTags, arg.WorkspaceID, arg.TagIds)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createLink = `-- name: CreateLink :one
 INSERT INTO links (
     user_id, workspace_id, domain_id, url, short_code,
     title, description, is_active, password_hash,
     expires_at, max_clicks,
-    utm_source, utm_medium, utm_campaign, utm_term, utm_content
+    utm_source, utm_medium, utm_campaign, utm_term, utm_content, cloak,
+    forward_params, param_precedence, internal_note, force_https,
+    once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url,
+    facebook_pixel_id, google_tag_id, interstitial, schedule, password_hint,
+    failover_urls
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
-RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
 `
 
 type CreateLinkParams struct {
-	UserID       uuid.UUID          `json:"user_id"`
-	WorkspaceID  uuid.UUID          `json:"workspace_id"`
-	DomainID     pgtype.UUID        `json:"domain_id"`
-	Url          string             `json:"url"`
-	ShortCode    string             `json:"short_code"`
-	Title        pgtype.Text        `json:"title"`
-	Description  pgtype.Text        `json:"description"`
-	IsActive     bool               `json:"is_active"`
-	PasswordHash pgtype.Text        `json:"password_hash"`
-	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
-	MaxClicks    pgtype.Int4        `json:"max_clicks"`
-	UtmSource    pgtype.Text        `json:"utm_source"`
-	UtmMedium    pgtype.Text        `json:"utm_medium"`
-	UtmCampaign  pgtype.Text        `json:"utm_campaign"`
-	UtmTerm      pgtype.Text        `json:"utm_term"`
-	UtmContent   pgtype.Text        `json:"utm_content"`
+	UserID          uuid.UUID          `json:"user_id"`
+	WorkspaceID     uuid.UUID          `json:"workspace_id"`
+	DomainID        pgtype.UUID        `json:"domain_id"`
+	Url             string             `json:"url"`
+	ShortCode       string             `json:"short_code"`
+	Title           pgtype.Text        `json:"title"`
+	Description     pgtype.Text        `json:"description"`
+	IsActive        bool               `json:"is_active"`
+	PasswordHash    pgtype.Text        `json:"password_hash"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	MaxClicks       pgtype.Int4        `json:"max_clicks"`
+	UtmSource       pgtype.Text        `json:"utm_source"`
+	UtmMedium       pgtype.Text        `json:"utm_medium"`
+	UtmCampaign     pgtype.Text        `json:"utm_campaign"`
+	UtmTerm         pgtype.Text        `json:"utm_term"`
+	UtmContent      pgtype.Text        `json:"utm_content"`
+	Cloak           bool               `json:"cloak"`
+	ForwardParams   bool               `json:"forward_params"`
+	ParamPrecedence string             `json:"param_precedence"`
+	InternalNote    pgtype.Text        `json:"internal_note"`
+	ForceHttps      bool               `json:"force_https"`
+	OncePerVisitor  bool               `json:"once_per_visitor"`
+	RepeatVisitUrl  pgtype.Text        `json:"repeat_visit_url"`
+	IosUrl          pgtype.Text        `json:"ios_url"`
+	AndroidUrl      pgtype.Text        `json:"android_url"`
+	FallbackUrl     pgtype.Text        `json:"fallback_url"`
+	FacebookPixelID pgtype.Text        `json:"facebook_pixel_id"`
+	GoogleTagID     pgtype.Text        `json:"google_tag_id"`
+	Interstitial    bool               `json:"interstitial"`
+	Schedule        []byte             `json:"schedule"`
+	PasswordHint    pgtype.Text        `json:"password_hint"`
+	FailoverUrls    []string           `json:"failover_urls"`
 }
 
 func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (Link, error) {
@@ -60,6 +315,22 @@ func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (Link, e
 		arg.UtmCampaign,
 		arg.UtmTerm,
 		arg.UtmContent,
+		arg.Cloak,
+		arg.ForwardParams,
+		arg.ParamPrecedence,
+		arg.InternalNote,
+		arg.ForceHttps,
+		arg.OncePerVisitor,
+		arg.RepeatVisitUrl,
+		arg.IosUrl,
+		arg.AndroidUrl,
+		arg.FallbackUrl,
+		arg.FacebookPixelID,
+		arg.GoogleTagID,
+		arg.Interstitial,
+		arg.Schedule,
+		arg.PasswordHint,
+		arg.FailoverUrls,
 	)
 	var i Link
 	err := row.Scan(
@@ -87,12 +358,30 @@ func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (Link, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
 	)
 	return i, err
 }
 
 const getLinkByID = `-- name: GetLinkByID :one
-SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at FROM links
+SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls FROM links
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -124,12 +413,30 @@ func (q *Queries) GetLinkByID(ctx context.Context, id uuid.UUID) (Link, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
 	)
 	return i, err
 }
 
 const getLinkByShortCode = `-- name: GetLinkByShortCode :one
-SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at FROM links
+SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls FROM links
 WHERE short_code = $1 AND deleted_at IS NULL
 `
 
@@ -161,12 +468,30 @@ func (q *Queries) GetLinkByShortCode(ctx context.Context, shortCode string) (Lin
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
 	)
 	return i, err
 }
 
 const getLinkByURL = `-- name: GetLinkByURL :one
-SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at FROM links
+SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls FROM links
 WHERE url = $1 AND workspace_id = $2 AND deleted_at IS NULL
 `
 
@@ -203,22 +528,62 @@ func (q *Queries) GetLinkByURL(ctx context.Context, arg GetLinkByURLParams) (Lin
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
 	)
 	return i, err
 }
 
-const getLinkCountForWorkspace = `-- name: GetLinkCountForWorkspace :one
+const countLinksCreatedForWorkspace = `-- name: CountLinksCreatedForWorkspace :one
 SELECT COUNT(*) AS count FROM links
-WHERE workspace_id = $1 AND deleted_at IS NULL
+WHERE workspace_id = $1
+    AND created_at >= $2::timestamptz
+    AND created_at < $3::timestamptz
+    AND NOT (reserved_until IS NOT NULL AND deleted_at IS NOT NULL)
 `
 
-func (q *Queries) GetLinkCountForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, getLinkCountForWorkspace, workspaceID)
+type CountLinksCreatedForWorkspaceParams struct {
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	PeriodStart pgtype.Timestamptz `json:"period_start"`
+	PeriodEnd   pgtype.Timestamptz `json:"period_end"`
+}
+
+// Links count even when deleted since, except reservations released
+// unclaimed, as in GetWorkspaceUsage.
+func (q *Queries) CountLinksCreatedForWorkspace(ctx context.Context, arg CountLinksCreatedForWorkspaceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countLinksCreatedForWorkspace, arg.WorkspaceID, arg.PeriodStart, arg.PeriodEnd)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const lockWorkspaceLinkLimit = `-- name: LockWorkspaceLinkLimit :exec
+SELECT pg_advisory_xact_lock(hashtextextended('link_limit:' || $1::text, 0))
+`
+
+// Held until the transaction ends, so that link limit checks and the
+// inserts they allow don't interleave.
+func (q *Queries) LockWorkspaceLinkLimit(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, lockWorkspaceLinkLimit, workspaceID)
+	return err
+}
+
 const getLinkQuickStats = `-- name: GetLinkQuickStats :one
 SELECT
     l.total_clicks,
@@ -251,6 +616,67 @@ func (q *Queries) GetLinkQuickStats(ctx context.Context, id uuid.UUID) (GetLinkQ
 	return i, err
 }
 
+const getShortCodeReservation = `-- name: GetShortCodeReservation :one
+SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls FROM links
+WHERE workspace_id = $1 AND short_code = $2
+    AND reserved_until > NOW() AND deleted_at IS NULL
+`
+
+type GetShortCodeReservationParams struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	ShortCode   string    `json:"short_code"`
+}
+
+func (q *Queries) GetShortCodeReservation(ctx context.Context, arg GetShortCodeReservationParams) (Link, error) {
+	row := q.db.QueryRow(ctx, getShortCodeReservation, arg.WorkspaceID, arg.ShortCode)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.DomainID,
+		&i.Url,
+		&i.ShortCode,
+		&i.Title,
+		&i.Description,
+		&i.FaviconUrl,
+		&i.OgImageUrl,
+		&i.IsActive,
+		&i.PasswordHash,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.TotalClicks,
+		&i.UniqueClicks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
+	)
+	return i, err
+}
+
 const incrementLinkClicks = `-- name: IncrementLinkClicks :exec
 UPDATE links
 SET total_clicks = total_clicks + 1, updated_at = NOW()
@@ -262,6 +688,22 @@ func (q *Queries) IncrementLinkClicks(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const incrementLinkClicksBy = `-- name: IncrementLinkClicksBy :exec
+UPDATE links
+SET total_clicks = total_clicks + $1::bigint, updated_at = NOW()
+WHERE id = $2
+`
+
+type IncrementLinkClicksByParams struct {
+	Count int64     `json:"count"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (q *Queries) IncrementLinkClicksBy(ctx context.Context, arg IncrementLinkClicksByParams) error {
+	_, err := q.db.Exec(ctx, incrementLinkClicksBy, arg.Count, arg.ID)
+	return err
+}
+
 const incrementLinkUniqueClicks = `-- name: IncrementLinkUniqueClicks :exec
 UPDATE links
 SET unique_clicks = unique_clicks + 1, updated_at = NOW()
@@ -273,9 +715,40 @@ func (q *Queries) IncrementLinkUniqueClicks(ctx context.Context, id uuid.UUID) e
 	return err
 }
 
+const listLinkIDsByTag = `-- name: ListLinkIDsByTag :many
+SELECT lt.link_id FROM link_tags lt
+JOIN links l ON l.id = lt.link_id
+WHERE lt.tag_id = $1 AND l.workspace_id = $2 AND l.deleted_at IS NULL
+`
+
+type ListLinkIDsByTagParams struct {
+	TagID       uuid.UUID `json:"tag_id"`
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+}
+
+func (q *Queries) ListLinkIDsByTag(ctx context.Context, arg ListLinkIDsByTagParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listLinkIDsByTag, arg.TagID, arg.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var link_id uuid.UUID
+		if err := rows.Scan(&link_id); err != nil {
+			return nil, err
+		}
+		items = append(items, link_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLinksForWorkspace = `-- name: ListLinksForWorkspace :many
 SELECT
-    l.id, l.user_id, l.workspace_id, l.domain_id, l.url, l.short_code, l.title, l.description, l.favicon_url, l.og_image_url, l.is_active, l.password_hash, l.expires_at, l.max_clicks, l.utm_source, l.utm_medium, l.utm_campaign, l.utm_term, l.utm_content, l.total_clicks, l.unique_clicks, l.created_at, l.updated_at, l.deleted_at,
+    l.id, l.user_id, l.workspace_id, l.domain_id, l.url, l.short_code, l.title, l.description, l.favicon_url, l.og_image_url, l.is_active, l.password_hash, l.expires_at, l.max_clicks, l.utm_source, l.utm_medium, l.utm_campaign, l.utm_term, l.utm_content, l.total_clicks, l.unique_clicks, l.created_at, l.updated_at, l.deleted_at, l.cloak, l.forward_params, l.param_precedence, l.internal_note, l.archived_at, l.force_https, l.once_per_visitor, l.repeat_visit_url, l.ios_url, l.android_url, l.fallback_url, l.facebook_pixel_id, l.google_tag_id, l.interstitial, l.schedule, l.reserved_until, l.password_hint, l.failover_urls,
     COUNT(*) OVER() AS total_count
 FROM links l
 WHERE l.workspace_id = $1
@@ -283,6 +756,7 @@ WHERE l.workspace_id = $1
     AND ($4::text IS NULL OR
          to_tsvector('english', COALESCE(l.title, '') || ' ' || COALESCE(l.description, '')) @@
          plainto_tsquery('english', $4::text))
+    AND ($5::boolean IS NULL OR (l.archived_at IS NOT NULL) = $5::boolean)
 ORDER BY l.created_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -292,34 +766,53 @@ type ListLinksForWorkspaceParams struct {
 	Limit       int32       `json:"limit"`
 	Offset      int32       `json:"offset"`
 	Search      pgtype.Text `json:"search"`
+	Archived    pgtype.Bool `json:"archived"`
 }
 
 type ListLinksForWorkspaceRow struct {
-	ID           uuid.UUID          `json:"id"`
-	UserID       uuid.UUID          `json:"user_id"`
-	WorkspaceID  uuid.UUID          `json:"workspace_id"`
-	DomainID     pgtype.UUID        `json:"domain_id"`
-	Url          string             `json:"url"`
-	ShortCode    string             `json:"short_code"`
-	Title        pgtype.Text        `json:"title"`
-	Description  pgtype.Text        `json:"description"`
-	FaviconUrl   pgtype.Text        `json:"favicon_url"`
-	OgImageUrl   pgtype.Text        `json:"og_image_url"`
-	IsActive     bool               `json:"is_active"`
-	PasswordHash pgtype.Text        `json:"password_hash"`
-	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
-	MaxClicks    pgtype.Int4        `json:"max_clicks"`
-	UtmSource    pgtype.Text        `json:"utm_source"`
-	UtmMedium    pgtype.Text        `json:"utm_medium"`
-	UtmCampaign  pgtype.Text        `json:"utm_campaign"`
-	UtmTerm      pgtype.Text        `json:"utm_term"`
-	UtmContent   pgtype.Text        `json:"utm_content"`
-	TotalClicks  int64              `json:"total_clicks"`
-	UniqueClicks int64              `json:"unique_clicks"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
-	TotalCount   int64              `json:"total_count"`
+	ID              uuid.UUID          `json:"id"`
+	UserID          uuid.UUID          `json:"user_id"`
+	WorkspaceID     uuid.UUID          `json:"workspace_id"`
+	DomainID        pgtype.UUID        `json:"domain_id"`
+	Url             string             `json:"url"`
+	ShortCode       string             `json:"short_code"`
+	Title           pgtype.Text        `json:"title"`
+	Description     pgtype.Text        `json:"description"`
+	FaviconUrl      pgtype.Text        `json:"favicon_url"`
+	OgImageUrl      pgtype.Text        `json:"og_image_url"`
+	IsActive        bool               `json:"is_active"`
+	PasswordHash    pgtype.Text        `json:"password_hash"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	MaxClicks       pgtype.Int4        `json:"max_clicks"`
+	UtmSource       pgtype.Text        `json:"utm_source"`
+	UtmMedium       pgtype.Text        `json:"utm_medium"`
+	UtmCampaign     pgtype.Text        `json:"utm_campaign"`
+	UtmTerm         pgtype.Text        `json:"utm_term"`
+	UtmContent      pgtype.Text        `json:"utm_content"`
+	TotalClicks     int64              `json:"total_clicks"`
+	UniqueClicks    int64              `json:"unique_clicks"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	Cloak           bool               `json:"cloak"`
+	ForwardParams   bool               `json:"forward_params"`
+	ParamPrecedence string             `json:"param_precedence"`
+	InternalNote    pgtype.Text        `json:"internal_note"`
+	ArchivedAt      pgtype.Timestamptz `json:"archived_at"`
+	ForceHttps      bool               `json:"force_https"`
+	OncePerVisitor  bool               `json:"once_per_visitor"`
+	RepeatVisitUrl  pgtype.Text        `json:"repeat_visit_url"`
+	IosUrl          pgtype.Text        `json:"ios_url"`
+	AndroidUrl      pgtype.Text        `json:"android_url"`
+	FallbackUrl     pgtype.Text        `json:"fallback_url"`
+	FacebookPixelID pgtype.Text        `json:"facebook_pixel_id"`
+	GoogleTagID     pgtype.Text        `json:"google_tag_id"`
+	Interstitial    bool               `json:"interstitial"`
+	Schedule        []byte             `json:"schedule"`
+	ReservedUntil   pgtype.Timestamptz `json:"reserved_until"`
+	PasswordHint    pgtype.Text        `json:"password_hint"`
+	FailoverUrls    []string           `json:"failover_urls"`
+	TotalCount      int64              `json:"total_count"`
 }
 
 func (q *Queries) ListLinksForWorkspace(ctx context.Context, arg ListLinksForWorkspaceParams) ([]ListLinksForWorkspaceRow, error) {
@@ -328,6 +821,7 @@ func (q *Queries) ListLinksForWorkspace(ctx context.Context, arg ListLinksForWor
 		arg.Limit,
 		arg.Offset,
 		arg.Search,
+		arg.Archived,
 	)
 	if err != nil {
 		return nil, err
@@ -361,6 +855,24 @@ func (q *Queries) ListLinksForWorkspace(ctx context.Context, arg ListLinksForWor
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Cloak,
+			&i.ForwardParams,
+			&i.ParamPrecedence,
+			&i.InternalNote,
+			&i.ArchivedAt,
+			&i.ForceHttps,
+			&i.OncePerVisitor,
+			&i.RepeatVisitUrl,
+			&i.IosUrl,
+			&i.AndroidUrl,
+			&i.FallbackUrl,
+			&i.FacebookPixelID,
+			&i.GoogleTagID,
+			&i.Interstitial,
+			&i.Schedule,
+			&i.ReservedUntil,
+			&i.PasswordHint,
+			&i.FailoverUrls,
 			&i.TotalCount,
 		); err != nil {
 			return nil, err
@@ -373,6 +885,131 @@ func (q *Queries) ListLinksForWorkspace(ctx context.Context, arg ListLinksForWor
 	return items, nil
 }
 
+const reconcileLinkUniqueClicks = `-- name: ReconcileLinkUniqueClicks :execrows
+UPDATE links l
+SET unique_clicks = (
+    SELECT COUNT(*) FROM clicks c
+    WHERE c.link_id = l.id
+        AND NOT c.is_bot
+        AND NOT EXISTS (
+            SELECT 1 FROM clicks p
+            WHERE p.link_id = c.link_id
+                AND NOT p.is_bot
+                AND COALESCE(p.visitor_id, host(p.ip_address)) = COALESCE(c.visitor_id, host(c.ip_address))
+                AND p.clicked_at < c.clicked_at
+                AND p.clicked_at >= c.clicked_at - make_interval(secs => $1::float8)
+        )
+), updated_at = NOW()
+WHERE l.deleted_at IS NULL
+    AND l.id IN (SELECT DISTINCT link_id FROM clicks WHERE clicked_at >= $2)
+`
+
+type ReconcileLinkUniqueClicksParams struct {
+	WindowSeconds float64            `json:"window_seconds"`
+	Since         pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) ReconcileLinkUniqueClicks(ctx context.Context, arg ReconcileLinkUniqueClicksParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reconcileLinkUniqueClicks, arg.WindowSeconds, arg.Since)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseExpiredReservation = `-- name: ReleaseExpiredReservation :exec
+UPDATE links
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE short_code = $1 AND reserved_until <= NOW() AND deleted_at IS NULL
+`
+
+func (q *Queries) ReleaseExpiredReservation(ctx context.Context, shortCode string) error {
+	_, err := q.db.Exec(ctx, releaseExpiredReservation, shortCode)
+	return err
+}
+
+const releaseExpiredReservations = `-- name: ReleaseExpiredReservations :execrows
+UPDATE links
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE reserved_until <= NOW() AND deleted_at IS NULL
+`
+
+func (q *Queries) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, releaseExpiredReservations)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reserveShortCode = `-- name: ReserveShortCode :one
+INSERT INTO links (user_id, workspace_id, url, short_code, is_active, reserved_until)
+VALUES ($1, $2, '', $3, FALSE, $4)
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
+`
+
+type ReserveShortCodeParams struct {
+	UserID        uuid.UUID          `json:"user_id"`
+	WorkspaceID   uuid.UUID          `json:"workspace_id"`
+	ShortCode     string             `json:"short_code"`
+	ReservedUntil pgtype.Timestamptz `json:"reserved_until"`
+}
+
+func (q *Queries) ReserveShortCode(ctx context.Context, arg ReserveShortCodeParams) (Link, error) {
+	row := q.db.QueryRow(ctx, reserveShortCode,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.ShortCode,
+		arg.ReservedUntil,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.DomainID,
+		&i.Url,
+		&i.ShortCode,
+		&i.Title,
+		&i.Description,
+		&i.FaviconUrl,
+		&i.OgImageUrl,
+		&i.IsActive,
+		&i.PasswordHash,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.TotalClicks,
+		&i.UniqueClicks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
+	)
+	return i, err
+}
+
 const shortCodeExists = `-- name: ShortCodeExists :one
 SELECT EXISTS(
     SELECT 1 FROM links
@@ -387,6 +1024,20 @@ func (q *Queries) ShortCodeExists(ctx context.Context, shortCode string) (bool,
 	return exists, err
 }
 
+const shortCodeExistsIgnoreCase = `-- name: ShortCodeExistsIgnoreCase :one
+SELECT EXISTS(
+    SELECT 1 FROM links
+    WHERE lower(short_code) = lower($1) AND deleted_at IS NULL
+) AS exists
+`
+
+func (q *Queries) ShortCodeExistsIgnoreCase(ctx context.Context, lower string) (bool, error) {
+	row := q.db.QueryRow(ctx, shortCodeExistsIgnoreCase, lower)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const softDeleteLink = `-- name: SoftDeleteLink :exec
 UPDATE links
 SET deleted_at = NOW(), updated_at = NOW()
@@ -398,6 +1049,143 @@ func (q *Queries) SoftDeleteLink(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const softDeleteWorkspaceLinks = `-- name: SoftDeleteWorkspaceLinks :execrows
+UPDATE links
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE workspace_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteWorkspaceLinks(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteWorkspaceLinks, workspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const transferLink = `-- name: TransferLink :one
+UPDATE links
+SET
+    workspace_id = $2,
+    domain_id = $3,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
+`
+
+type TransferLinkParams struct {
+	ID          uuid.UUID   `json:"id"`
+	WorkspaceID uuid.UUID   `json:"workspace_id"`
+	DomainID    pgtype.UUID `json:"domain_id"`
+}
+
+func (q *Queries) TransferLink(ctx context.Context, arg TransferLinkParams) (Link, error) {
+	row := q.db.QueryRow(ctx, transferLink, arg.ID, arg.WorkspaceID, arg.DomainID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.DomainID,
+		&i.Url,
+		&i.ShortCode,
+		&i.Title,
+		&i.Description,
+		&i.FaviconUrl,
+		&i.OgImageUrl,
+		&i.IsActive,
+		&i.PasswordHash,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.TotalClicks,
+		&i.UniqueClicks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
+	)
+	return i, err
+}
+
+const unarchiveLink = `-- name: UnarchiveLink :one
+UPDATE links
+SET archived_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
+`
+
+func (q *Queries) UnarchiveLink(ctx context.Context, id uuid.UUID) (Link, error) {
+	row := q.db.QueryRow(ctx, unarchiveLink, id)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.DomainID,
+		&i.Url,
+		&i.ShortCode,
+		&i.Title,
+		&i.Description,
+		&i.FaviconUrl,
+		&i.OgImageUrl,
+		&i.IsActive,
+		&i.PasswordHash,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.TotalClicks,
+		&i.UniqueClicks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
+	)
+	return i, err
+}
+
 const updateLink = `-- name: UpdateLink :one
 UPDATE links
 SET
@@ -408,20 +1196,52 @@ SET
     password_hash = COALESCE($6, password_hash),
     expires_at = COALESCE($7, expires_at),
     max_clicks = COALESCE($8, max_clicks),
+    cloak = COALESCE($9, cloak),
+    forward_params = COALESCE($10, forward_params),
+    param_precedence = COALESCE($11, param_precedence),
+    internal_note = COALESCE($12, internal_note),
+    force_https = COALESCE($13, force_https),
+    once_per_visitor = COALESCE($14, once_per_visitor),
+    repeat_visit_url = COALESCE($15, repeat_visit_url),
+    ios_url = COALESCE($16, ios_url),
+    android_url = COALESCE($17, android_url),
+    fallback_url = COALESCE($18, fallback_url),
+    facebook_pixel_id = COALESCE($19, facebook_pixel_id),
+    google_tag_id = COALESCE($20, google_tag_id),
+    interstitial = COALESCE($21, interstitial),
+    schedule = COALESCE($22, schedule),
+    password_hint = COALESCE($23, password_hint),
+    failover_urls = COALESCE($24, failover_urls),
     updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
 `
 
 type UpdateLinkParams struct {
-	ID           uuid.UUID          `json:"id"`
-	Title        pgtype.Text        `json:"title"`
-	Description  pgtype.Text        `json:"description"`
-	Url          pgtype.Text        `json:"url"`
-	IsActive     pgtype.Bool        `json:"is_active"`
-	PasswordHash pgtype.Text        `json:"password_hash"`
-	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
-	MaxClicks    pgtype.Int4        `json:"max_clicks"`
+	ID              uuid.UUID          `json:"id"`
+	Title           pgtype.Text        `json:"title"`
+	Description     pgtype.Text        `json:"description"`
+	Url             pgtype.Text        `json:"url"`
+	IsActive        pgtype.Bool        `json:"is_active"`
+	PasswordHash    pgtype.Text        `json:"password_hash"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	MaxClicks       pgtype.Int4        `json:"max_clicks"`
+	Cloak           pgtype.Bool        `json:"cloak"`
+	ForwardParams   pgtype.Bool        `json:"forward_params"`
+	ParamPrecedence pgtype.Text        `json:"param_precedence"`
+	InternalNote    pgtype.Text        `json:"internal_note"`
+	ForceHttps      pgtype.Bool        `json:"force_https"`
+	OncePerVisitor  pgtype.Bool        `json:"once_per_visitor"`
+	RepeatVisitUrl  pgtype.Text        `json:"repeat_visit_url"`
+	IosUrl          pgtype.Text        `json:"ios_url"`
+	AndroidUrl      pgtype.Text        `json:"android_url"`
+	FallbackUrl     pgtype.Text        `json:"fallback_url"`
+	FacebookPixelID pgtype.Text        `json:"facebook_pixel_id"`
+	GoogleTagID     pgtype.Text        `json:"google_tag_id"`
+	Interstitial    pgtype.Bool        `json:"interstitial"`
+	Schedule        []byte             `json:"schedule"`
+	PasswordHint    pgtype.Text        `json:"password_hint"`
+	FailoverUrls    []string           `json:"failover_urls"`
 }
 
 func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) (Link, error) {
@@ -434,6 +1254,22 @@ func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) (Link, e
 		arg.PasswordHash,
 		arg.ExpiresAt,
 		arg.MaxClicks,
+		arg.Cloak,
+		arg.ForwardParams,
+		arg.ParamPrecedence,
+		arg.InternalNote,
+		arg.ForceHttps,
+		arg.OncePerVisitor,
+		arg.RepeatVisitUrl,
+		arg.IosUrl,
+		arg.AndroidUrl,
+		arg.FallbackUrl,
+		arg.FacebookPixelID,
+		arg.GoogleTagID,
+		arg.Interstitial,
+		arg.Schedule,
+		arg.PasswordHint,
+		arg.FailoverUrls,
 	)
 	var i Link
 	err := row.Scan(
@@ -461,6 +1297,271 @@ func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) (Link, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
 	)
 	return i, err
 }
+
+const updateLinkMetadata = `-- name: UpdateLinkMetadata :one
+UPDATE links
+SET
+    title = COALESCE($2, title),
+    description = COALESCE($3, description),
+    favicon_url = COALESCE($4, favicon_url),
+    og_image_url = COALESCE($5, og_image_url),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls
+`
+
+type UpdateLinkMetadataParams struct {
+	ID          uuid.UUID   `json:"id"`
+	Title       pgtype.Text `json:"title"`
+	Description pgtype.Text `json:"description"`
+	FaviconUrl  pgtype.Text `json:"favicon_url"`
+	OgImageUrl  pgtype.Text `json:"og_image_url"`
+}
+
+func (q *Queries) UpdateLinkMetadata(ctx context.Context, arg UpdateLinkMetadataParams) (Link, error) {
+	row := q.db.QueryRow(ctx, updateLinkMetadata,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.FaviconUrl,
+		arg.OgImageUrl,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.DomainID,
+		&i.Url,
+		&i.ShortCode,
+		&i.Title,
+		&i.Description,
+		&i.FaviconUrl,
+		&i.OgImageUrl,
+		&i.IsActive,
+		&i.PasswordHash,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.UtmSource,
+		&i.UtmMedium,
+		&i.UtmCampaign,
+		&i.UtmTerm,
+		&i.UtmContent,
+		&i.TotalClicks,
+		&i.UniqueClicks,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Cloak,
+		&i.ForwardParams,
+		&i.ParamPrecedence,
+		&i.InternalNote,
+		&i.ArchivedAt,
+		&i.ForceHttps,
+		&i.OncePerVisitor,
+		&i.RepeatVisitUrl,
+		&i.IosUrl,
+		&i.AndroidUrl,
+		&i.FallbackUrl,
+		&i.FacebookPixelID,
+		&i.GoogleTagID,
+		&i.Interstitial,
+		&i.Schedule,
+		&i.ReservedUntil,
+		&i.PasswordHint,
+		&i.FailoverUrls,
+	)
+	return i, err
+}
+
+const upsertTag = `-- name: UpsertTag :one
+INSERT INTO tags (workspace_id, name)
+VALUES ($1, $2)
+ON CONFLICT (workspace_id, name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id
+`
+
+type UpsertTagParams struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	Name        string    `json:"name"`
+}
+
+func (q *Queries) UpsertTag(ctx context.Context, arg UpsertTagParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, upsertTag, arg.WorkspaceID, arg.Name)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listMostClickedLinks = `-- name: ListMostClickedLinks :many
+SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls FROM links
+WHERE deleted_at IS NULL
+    AND is_active = true
+    AND archived_at IS NULL
+    AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY total_clicks DESC
+LIMIT $1
+`
+
+func (q *Queries) ListMostClickedLinks(ctx context.Context, limit int32) ([]Link, error) {
+	rows, err := q.db.Query(ctx, listMostClickedLinks, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.DomainID,
+			&i.Url,
+			&i.ShortCode,
+			&i.Title,
+			&i.Description,
+			&i.FaviconUrl,
+			&i.OgImageUrl,
+			&i.IsActive,
+			&i.PasswordHash,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.UtmSource,
+			&i.UtmMedium,
+			&i.UtmCampaign,
+			&i.UtmTerm,
+			&i.UtmContent,
+			&i.TotalClicks,
+			&i.UniqueClicks,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Cloak,
+			&i.ForwardParams,
+			&i.ParamPrecedence,
+			&i.InternalNote,
+			&i.ArchivedAt,
+			&i.ForceHttps,
+			&i.OncePerVisitor,
+			&i.RepeatVisitUrl,
+			&i.IosUrl,
+			&i.AndroidUrl,
+			&i.FallbackUrl,
+			&i.FacebookPixelID,
+			&i.GoogleTagID,
+			&i.Interstitial,
+			&i.Schedule,
+			&i.ReservedUntil,
+			&i.PasswordHint,
+			&i.FailoverUrls,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLinksWithFailovers = `-- name: ListLinksWithFailovers :many
+SELECT id, user_id, workspace_id, domain_id, url, short_code, title, description, favicon_url, og_image_url, is_active, password_hash, expires_at, max_clicks, utm_source, utm_medium, utm_campaign, utm_term, utm_content, total_clicks, unique_clicks, created_at, updated_at, deleted_at, cloak, forward_params, param_precedence, internal_note, archived_at, force_https, once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url, facebook_pixel_id, google_tag_id, interstitial, schedule, reserved_until, password_hint, failover_urls FROM links
+WHERE deleted_at IS NULL
+    AND is_active = true
+    AND archived_at IS NULL
+    AND cardinality(failover_urls) > 0
+    AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListLinksWithFailoversParams struct {
+	ID    uuid.UUID `json:"id"`
+	Limit int32     `json:"limit"`
+}
+
+func (q *Queries) ListLinksWithFailovers(ctx context.Context, arg ListLinksWithFailoversParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, listLinksWithFailovers, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.DomainID,
+			&i.Url,
+			&i.ShortCode,
+			&i.Title,
+			&i.Description,
+			&i.FaviconUrl,
+			&i.OgImageUrl,
+			&i.IsActive,
+			&i.PasswordHash,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.UtmSource,
+			&i.UtmMedium,
+			&i.UtmCampaign,
+			&i.UtmTerm,
+			&i.UtmContent,
+			&i.TotalClicks,
+			&i.UniqueClicks,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Cloak,
+			&i.ForwardParams,
+			&i.ParamPrecedence,
+			&i.InternalNote,
+			&i.ArchivedAt,
+			&i.ForceHttps,
+			&i.OncePerVisitor,
+			&i.RepeatVisitUrl,
+			&i.IosUrl,
+			&i.AndroidUrl,
+			&i.FallbackUrl,
+			&i.FacebookPixelID,
+			&i.GoogleTagID,
+			&i.Interstitial,
+			&i.Schedule,
+			&i.ReservedUntil,
+			&i.PasswordHint,
+			&i.FailoverUrls,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,6 +11,17 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AnalyticsShareToken struct {
+	ID          uuid.UUID          `json:"id"`
+	LinkID      uuid.UUID          `json:"link_id"`
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	RangeStart  pgtype.Timestamptz `json:"range_start"`
+	RangeEnd    pgtype.Timestamptz `json:"range_end"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt   pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
 type ApiKey struct {
 	ID           uuid.UUID          `json:"id"`
 	UserID       uuid.UUID          `json:"user_id"`
@@ -73,6 +84,27 @@ type BioPageLink struct {
 	ClickCount   int64              `json:"click_count"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	LinkType     string             `json:"link_type"`
+}
+
+type BioPageSubmission struct {
+	ID        uuid.UUID          `json:"id"`
+	BioPageID uuid.UUID          `json:"bio_page_id"`
+	BlockID   pgtype.UUID        `json:"block_id"`
+	Email     string             `json:"email"`
+	Name      pgtype.Text        `json:"name"`
+	IpHash    pgtype.Text        `json:"ip_hash"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type BioTheme struct {
+	ID          uuid.UUID          `json:"id"`
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	Styles      []byte             `json:"styles"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Click struct {
@@ -95,6 +127,7 @@ type Click struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202501 struct {
@@ -117,6 +150,7 @@ type Clicks202501 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202502 struct {
@@ -139,6 +173,7 @@ type Clicks202502 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202503 struct {
@@ -161,6 +196,7 @@ type Clicks202503 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202504 struct {
@@ -183,6 +219,7 @@ type Clicks202504 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202505 struct {
@@ -205,6 +242,7 @@ type Clicks202505 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202506 struct {
@@ -227,6 +265,7 @@ type Clicks202506 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202507 struct {
@@ -249,6 +288,7 @@ type Clicks202507 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202508 struct {
@@ -271,6 +311,7 @@ type Clicks202508 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202509 struct {
@@ -293,6 +334,7 @@ type Clicks202509 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202510 struct {
@@ -315,6 +357,7 @@ type Clicks202510 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202511 struct {
@@ -337,6 +380,7 @@ type Clicks202511 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202512 struct {
@@ -359,6 +403,7 @@ type Clicks202512 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202601 struct {
@@ -381,6 +426,7 @@ type Clicks202601 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202602 struct {
@@ -403,6 +449,7 @@ type Clicks202602 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202603 struct {
@@ -425,6 +472,7 @@ type Clicks202603 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202604 struct {
@@ -447,6 +495,7 @@ type Clicks202604 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202605 struct {
@@ -469,6 +518,7 @@ type Clicks202605 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Clicks202606 struct {
@@ -491,6 +541,7 @@ type Clicks202606 struct {
 	UtmSource      pgtype.Text        `json:"utm_source"`
 	UtmMedium      pgtype.Text        `json:"utm_medium"`
 	UtmCampaign    pgtype.Text        `json:"utm_campaign"`
+	Source         pgtype.Text        `json:"source"`
 }
 
 type Domain struct {
@@ -508,33 +559,85 @@ type Domain struct {
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	NotFoundMode       pgtype.Text        `json:"not_found_mode"`
+	NotFoundStatus     pgtype.Int4        `json:"not_found_status"`
 }
 
 type Link struct {
-	ID           uuid.UUID          `json:"id"`
-	UserID       uuid.UUID          `json:"user_id"`
-	WorkspaceID  uuid.UUID          `json:"workspace_id"`
-	DomainID     pgtype.UUID        `json:"domain_id"`
-	Url          string             `json:"url"`
-	ShortCode    string             `json:"short_code"`
-	Title        pgtype.Text        `json:"title"`
-	Description  pgtype.Text        `json:"description"`
-	FaviconUrl   pgtype.Text        `json:"favicon_url"`
-	OgImageUrl   pgtype.Text        `json:"og_image_url"`
-	IsActive     bool               `json:"is_active"`
-	PasswordHash pgtype.Text        `json:"password_hash"`
-	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
-	MaxClicks    pgtype.Int4        `json:"max_clicks"`
-	UtmSource    pgtype.Text        `json:"utm_source"`
-	UtmMedium    pgtype.Text        `json:"utm_medium"`
-	UtmCampaign  pgtype.Text        `json:"utm_campaign"`
-	UtmTerm      pgtype.Text        `json:"utm_term"`
-	UtmContent   pgtype.Text        `json:"utm_content"`
-	TotalClicks  int64              `json:"total_clicks"`
-	UniqueClicks int64              `json:"unique_clicks"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+	ID              uuid.UUID          `json:"id"`
+	UserID          uuid.UUID          `json:"user_id"`
+	WorkspaceID     uuid.UUID          `json:"workspace_id"`
+	DomainID        pgtype.UUID        `json:"domain_id"`
+	Url             string             `json:"url"`
+	ShortCode       string             `json:"short_code"`
+	Title           pgtype.Text        `json:"title"`
+	Description     pgtype.Text        `json:"description"`
+	FaviconUrl      pgtype.Text        `json:"favicon_url"`
+	OgImageUrl      pgtype.Text        `json:"og_image_url"`
+	IsActive        bool               `json:"is_active"`
+	PasswordHash    pgtype.Text        `json:"password_hash"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	MaxClicks       pgtype.Int4        `json:"max_clicks"`
+	UtmSource       pgtype.Text        `json:"utm_source"`
+	UtmMedium       pgtype.Text        `json:"utm_medium"`
+	UtmCampaign     pgtype.Text        `json:"utm_campaign"`
+	UtmTerm         pgtype.Text        `json:"utm_term"`
+	UtmContent      pgtype.Text        `json:"utm_content"`
+	TotalClicks     int64              `json:"total_clicks"`
+	UniqueClicks    int64              `json:"unique_clicks"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	Cloak           bool               `json:"cloak"`
+	ForwardParams   bool               `json:"forward_params"`
+	ParamPrecedence string             `json:"param_precedence"`
+	InternalNote    pgtype.Text        `json:"internal_note"`
+	ArchivedAt      pgtype.Timestamptz `json:"archived_at"`
+	ForceHttps      bool               `json:"force_https"`
+	OncePerVisitor  bool               `json:"once_per_visitor"`
+	RepeatVisitUrl  pgtype.Text        `json:"repeat_visit_url"`
+	IosUrl          pgtype.Text        `json:"ios_url"`
+	AndroidUrl      pgtype.Text        `json:"android_url"`
+	FallbackUrl     pgtype.Text        `json:"fallback_url"`
+	FacebookPixelID pgtype.Text        `json:"facebook_pixel_id"`
+	GoogleTagID     pgtype.Text        `json:"google_tag_id"`
+	Interstitial    bool               `json:"interstitial"`
+	Schedule        []byte             `json:"schedule"`
+	ReservedUntil   pgtype.Timestamptz `json:"reserved_until"`
+	PasswordHint    pgtype.Text        `json:"password_hint"`
+	FailoverUrls    []string           `json:"failover_urls"`
+}
+
+type LinkComment struct {
+	ID          uuid.UUID          `json:"id"`
+	LinkID      uuid.UUID          `json:"link_id"`
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Body        string             `json:"body"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type LinkFlag struct {
+	LinkID      uuid.UUID          `json:"link_id"`
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	Url         string             `json:"url"`
+	Source      string             `json:"source"`
+	Reason      string             `json:"reason"`
+	Status      string             `json:"status"`
+	ReviewedBy  pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt  pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type LinkReport struct {
+	ID            uuid.UUID          `json:"id"`
+	LinkID        uuid.UUID          `json:"link_id"`
+	WorkspaceID   uuid.UUID          `json:"workspace_id"`
+	Reason        string             `json:"reason"`
+	Details       pgtype.Text        `json:"details"`
+	ReporterEmail pgtype.Text        `json:"reporter_email"`
+	ReporterHash  string             `json:"reporter_hash"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
 
 type LinkRule struct {
@@ -555,6 +658,18 @@ type LinkTag struct {
 	TagID  uuid.UUID `json:"tag_id"`
 }
 
+type LinkTemplate struct {
+	ID           uuid.UUID          `json:"id"`
+	WorkspaceID  uuid.UUID          `json:"workspace_id"`
+	CreatedBy    pgtype.UUID        `json:"created_by"`
+	Name         string             `json:"name"`
+	Description  pgtype.Text        `json:"description"`
+	Settings     json.RawMessage    `json:"settings"`
+	PasswordHash pgtype.Text        `json:"password_hash"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
 type PasswordReset struct {
 	ID        uuid.UUID          `json:"id"`
 	UserID    uuid.UUID          `json:"user_id"`
@@ -583,6 +698,23 @@ type QrCode struct {
 	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 }
 
+type ScheduledReport struct {
+	ID          uuid.UUID          `json:"id"`
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	CreatedBy   pgtype.UUID        `json:"created_by"`
+	Name        string             `json:"name"`
+	Schedule    string             `json:"schedule"`
+	Timezone    string             `json:"timezone"`
+	Recipients  []string           `json:"recipients"`
+	Scope       json.RawMessage    `json:"scope"`
+	IsActive    bool               `json:"is_active"`
+	NextRunAt   pgtype.Timestamptz `json:"next_run_at"`
+	LastRunAt   pgtype.Timestamptz `json:"last_run_at"`
+	LastError   pgtype.Text        `json:"last_error"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Session struct {
 	ID               uuid.UUID          `json:"id"`
 	UserID           uuid.UUID          `json:"user_id"`
@@ -621,6 +753,17 @@ type Tag struct {
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 }
 
+type UsageSnapshot struct {
+	WorkspaceID       uuid.UUID          `json:"workspace_id"`
+	PeriodStart       pgtype.Date        `json:"period_start"`
+	Tier              string             `json:"tier"`
+	LinksCreated      int64              `json:"links_created"`
+	ClicksTracked     int64              `json:"clicks_tracked"`
+	QrCodesGenerated  int64              `json:"qr_codes_generated"`
+	WebhookDeliveries int64              `json:"webhook_deliveries"`
+	CapturedAt        pgtype.Timestamptz `json:"captured_at"`
+}
+
 type User struct {
 	ID               uuid.UUID          `json:"id"`
 	Email            string             `json:"email"`
@@ -636,17 +779,20 @@ type User struct {
 }
 
 type Webhook struct {
-	ID              uuid.UUID          `json:"id"`
-	WorkspaceID     uuid.UUID          `json:"workspace_id"`
-	Url             string             `json:"url"`
-	Secret          string             `json:"secret"`
-	Events          []string           `json:"events"`
-	IsActive        bool               `json:"is_active"`
-	FailureCount    int32              `json:"failure_count"`
-	LastTriggeredAt pgtype.Timestamptz `json:"last_triggered_at"`
-	LastSuccessAt   pgtype.Timestamptz `json:"last_success_at"`
-	CreatedAt       pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ID                      uuid.UUID          `json:"id"`
+	WorkspaceID             uuid.UUID          `json:"workspace_id"`
+	Url                     string             `json:"url"`
+	Secret                  string             `json:"secret"`
+	Events                  []string           `json:"events"`
+	IsActive                bool               `json:"is_active"`
+	FailureCount            int32              `json:"failure_count"`
+	LastTriggeredAt         pgtype.Timestamptz `json:"last_triggered_at"`
+	LastSuccessAt           pgtype.Timestamptz `json:"last_success_at"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
+	PayloadVersion          pgtype.Int4        `json:"payload_version"`
+	PreviousSecret          pgtype.Text        `json:"previous_secret"`
+	PreviousSecretExpiresAt pgtype.Timestamptz `json:"previous_secret_expires_at"`
 }
 
 type WebhookDelivery struct {
@@ -661,18 +807,20 @@ type WebhookDelivery struct {
 	LastAttemptAt  pgtype.Timestamptz `json:"last_attempt_at"`
 	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	NextRetryAt    pgtype.Timestamptz `json:"next_retry_at"`
 }
 
 type Workspace struct {
-	ID        uuid.UUID          `json:"id"`
-	Name      string             `json:"name"`
-	Slug      string             `json:"slug"`
-	OwnerID   uuid.UUID          `json:"owner_id"`
-	Plan      string             `json:"plan"`
-	Settings  json.RawMessage    `json:"settings"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
-	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	ID              uuid.UUID          `json:"id"`
+	Name            string             `json:"name"`
+	Slug            string             `json:"slug"`
+	OwnerID         uuid.UUID          `json:"owner_id"`
+	Plan            string             `json:"plan"`
+	Settings        json.RawMessage    `json:"settings"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	AssetsCleanedAt pgtype.Timestamptz `json:"assets_cleaned_at"`
 }
 
 type WorkspaceMember struct {
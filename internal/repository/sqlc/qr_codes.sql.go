@@ -191,6 +191,50 @@ func (q *Queries) ListQRCodesForLink(ctx context.Context, linkID uuid.UUID) ([]Q
 	return items, nil
 }
 
+const listQRCodesForWorkspace = `-- name: ListQRCodesForWorkspace :many
+SELECT q.id, q.link_id, q.qr_type, q.error_correction, q.foreground_color, q.background_color, q.logo_url, q.png_url, q.svg_url, q.dot_style, q.corner_style, q.size, q.margin, q.scan_count, q.created_at, q.updated_at FROM qr_codes q
+JOIN links l ON l.id = q.link_id
+WHERE l.workspace_id = $1
+ORDER BY q.created_at
+`
+
+func (q *Queries) ListQRCodesForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]QrCode, error) {
+	rows, err := q.db.Query(ctx, listQRCodesForWorkspace, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QrCode{}
+	for rows.Next() {
+		var i QrCode
+		if err := rows.Scan(
+			&i.ID,
+			&i.LinkID,
+			&i.QrType,
+			&i.ErrorCorrection,
+			&i.ForegroundColor,
+			&i.BackgroundColor,
+			&i.LogoUrl,
+			&i.PngUrl,
+			&i.SvgUrl,
+			&i.DotStyle,
+			&i.CornerStyle,
+			&i.Size,
+			&i.Margin,
+			&i.ScanCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateQRCode = `-- name: UpdateQRCode :one
 UPDATE qr_codes SET
     qr_type = COALESCE($2, qr_type),
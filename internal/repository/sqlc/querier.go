@@ -12,25 +12,61 @@ import (
 )
 
 type Querier interface {
+	AddLinkTag(ctx context.Context, arg AddLinkTagParams) error
 	AddWorkspaceMember(ctx context.Context, arg AddWorkspaceMemberParams) (WorkspaceMember, error)
+	ClearBioPageTheme(ctx context.Context, themeID pgtype.UUID) error
+	CountBioPageSubmissions(ctx context.Context, bioPageID uuid.UUID) (int64, error)
+	CountLinkComments(ctx context.Context, linkID uuid.UUID) (int64, error)
+	CountLinkFlagsByStatus(ctx context.Context, status string) (int64, error)
+	// Reports filed since the link's flag was last approved, or all of them if
+	// it never was.
+	CountLinkReportsSinceReview(ctx context.Context, linkID uuid.UUID) (int64, error)
 	CountRecentWebhookFailures(ctx context.Context, webhookID uuid.UUID) (int64, error)
 	CountWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) (int64, error)
+	CountWorkspaceTags(ctx context.Context, arg CountWorkspaceTagsParams) (int64, error)
 	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAnalyticsShareToken(ctx context.Context, arg CreateAnalyticsShareTokenParams) (AnalyticsShareToken, error)
 	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
+	CreateBioPageSubmission(ctx context.Context, arg CreateBioPageSubmissionParams) error
+	CreateBioTheme(ctx context.Context, arg CreateBioThemeParams) (BioTheme, error)
 	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
 	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
 	CreateBioPage(ctx context.Context, arg CreateBioPageParams) (BioPage, error)
 	CreateBioPageLink(ctx context.Context, arg CreateBioPageLinkParams) (BioPageLink, error)
 	CreateDomain(ctx context.Context, arg CreateDomainParams) (Domain, error)
+	CreateLinkComment(ctx context.Context, arg CreateLinkCommentParams) (CreateLinkCommentRow, error)
 	CreateLink(ctx context.Context, arg CreateLinkParams) (Link, error)
 	CreateQRCode(ctx context.Context, arg CreateQRCodeParams) (QrCode, error)
+	DeleteBioTheme(ctx context.Context, id uuid.UUID) error
+	DeleteLinkComment(ctx context.Context, id uuid.UUID) error
 	DeleteQRCode(ctx context.Context, id uuid.UUID) error
 	DeleteWebhook(ctx context.Context, id uuid.UUID) error
 	DisableWebhook(ctx context.Context, id uuid.UUID) error
+	GetActiveSessionByID(ctx context.Context, id uuid.UUID) (Session, error)
+	GetAnalyticsShareToken(ctx context.Context, id uuid.UUID) (AnalyticsShareToken, error)
+	GetBioPageLinkCount(ctx context.Context, bioPageID uuid.UUID) (int64, error)
+	GetBioThemeByID(ctx context.Context, id uuid.UUID) (BioTheme, error)
+	GetLinkCommentByID(ctx context.Context, id uuid.UUID) (LinkComment, error)
+	GetLinkFlag(ctx context.Context, linkID uuid.UUID) (LinkFlag, error)
 	GetQRCodeByID(ctx context.Context, id uuid.UUID) (QrCode, error)
 	GetQRCodeByLinkID(ctx context.Context, linkID uuid.UUID) (QrCode, error)
 	IncrementQRScanCount(ctx context.Context, id uuid.UUID) error
+	ListAnalyticsShareTokensForLink(ctx context.Context, linkID uuid.UUID) ([]AnalyticsShareToken, error)
+	ListBioPageSubmissions(ctx context.Context, arg ListBioPageSubmissionsParams) ([]BioPageSubmission, error)
+	ListBioPageSubmissionsForExport(ctx context.Context, bioPageID uuid.UUID) ([]BioPageSubmission, error)
+	ListBioThemesForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]BioTheme, error)
+	ListLinkComments(ctx context.Context, arg ListLinkCommentsParams) ([]ListLinkCommentsRow, error)
+	ListLinkFlagsByStatus(ctx context.Context, arg ListLinkFlagsByStatusParams) ([]LinkFlag, error)
+	ListLinkIDsByTag(ctx context.Context, arg ListLinkIDsByTagParams) ([]uuid.UUID, error)
+	ListLinkReportsForLink(ctx context.Context, linkID uuid.UUID) ([]LinkReport, error)
 	ListQRCodesForLink(ctx context.Context, linkID uuid.UUID) ([]QrCode, error)
+	ListRulesForLink(ctx context.Context, linkID uuid.UUID) ([]LinkRule, error)
+	ListWorkspacesOverDomainLimit(ctx context.Context, maxDomains int64) ([]ListWorkspacesOverDomainLimitRow, error)
+	ListWorkspacesOverMemberLimit(ctx context.Context, maxMembers int64) ([]ListWorkspacesOverMemberLimitRow, error)
+	ReviewLinkFlag(ctx context.Context, arg ReviewLinkFlagParams) (LinkFlag, error)
+	RevokeAnalyticsShareToken(ctx context.Context, id uuid.UUID) error
+	TransferLink(ctx context.Context, arg TransferLinkParams) (Link, error)
+	UpdateBioTheme(ctx context.Context, arg UpdateBioThemeParams) (BioTheme, error)
 	UpdateQRCode(ctx context.Context, arg UpdateQRCodeParams) (QrCode, error)
 	CreateLinkRule(ctx context.Context, arg CreateLinkRuleParams) (LinkRule, error)
 	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
@@ -60,7 +96,12 @@ type Querier interface {
 	GetLinkByID(ctx context.Context, id uuid.UUID) (Link, error)
 	GetLinkByShortCode(ctx context.Context, shortCode string) (Link, error)
 	GetLinkByURL(ctx context.Context, arg GetLinkByURLParams) (Link, error)
-	GetLinkCountForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	// Links count even when deleted since, except reservations released
+	// unclaimed, as in GetWorkspaceUsage.
+	CountLinksCreatedForWorkspace(ctx context.Context, arg CountLinksCreatedForWorkspaceParams) (int64, error)
+	// Held until the transaction ends, so that link limit checks and the
+	// inserts they allow don't interleave.
+	LockWorkspaceLinkLimit(ctx context.Context, workspaceID uuid.UUID) error
 	GetLinkQuickStats(ctx context.Context, id uuid.UUID) (GetLinkQuickStatsRow, error)
 	GetLinkRuleByID(ctx context.Context, id uuid.UUID) (LinkRule, error)
 	GetPasswordResetByToken(ctx context.Context, tokenHash string) (PasswordReset, error)
@@ -71,10 +112,15 @@ type Querier interface {
 	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
 	GetWorkspaceBySlug(ctx context.Context, slug string) (Workspace, error)
 	GetWorkspaceMember(ctx context.Context, arg GetWorkspaceMemberParams) (WorkspaceMember, error)
+	// Links count even when deleted since, except reservations released
+	// unclaimed, and clicks exclude bots.
+	GetWorkspaceUsage(ctx context.Context, arg GetWorkspaceUsageParams) (GetWorkspaceUsageRow, error)
 	IncrementBioPageLinkClickCount(ctx context.Context, id uuid.UUID) error
 	IncrementWebhookFailureCount(ctx context.Context, id uuid.UUID) error
 	IncrementLinkClicks(ctx context.Context, id uuid.UUID) error
+	IncrementLinkClicksBy(ctx context.Context, arg IncrementLinkClicksByParams) error
 	IncrementLinkUniqueClicks(ctx context.Context, id uuid.UUID) error
+	ReconcileLinkUniqueClicks(ctx context.Context, arg ReconcileLinkUniqueClicksParams) (int64, error)
 	InsertClick(ctx context.Context, arg InsertClickParams) error
 	ListAPIKeysForWorkspace(ctx context.Context, workspaceID pgtype.UUID) ([]ApiKey, error)
 	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
@@ -84,17 +130,32 @@ type Querier interface {
 	ListBioPagesForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]BioPage, error)
 	ListDomainsForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]Domain, error)
 	ListLinksForWorkspace(ctx context.Context, arg ListLinksForWorkspaceParams) ([]ListLinksForWorkspaceRow, error)
+	ListMostClickedLinks(ctx context.Context, limit int32) ([]Link, error)
 	ListUserSessions(ctx context.Context, userID uuid.UUID) ([]Session, error)
 	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]ListWorkspaceMembersRow, error)
 	ListWorkspacesForUser(ctx context.Context, userID uuid.UUID) ([]Workspace, error)
+	ListActiveWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error)
+	ListUsageSnapshots(ctx context.Context, arg ListUsageSnapshotsParams) ([]UsageSnapshot, error)
+	CountScheduledReportsForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	CreateScheduledReport(ctx context.Context, arg CreateScheduledReportParams) (ScheduledReport, error)
+	DeleteScheduledReport(ctx context.Context, id uuid.UUID) error
+	GetScheduledReportByID(ctx context.Context, id uuid.UUID) (ScheduledReport, error)
+	// Reports of deleted workspaces are skipped.
+	ListDueScheduledReports(ctx context.Context, arg ListDueScheduledReportsParams) ([]ScheduledReport, error)
+	ListScheduledReportsForWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]ScheduledReport, error)
+	RecordScheduledReportRun(ctx context.Context, arg RecordScheduledReportRunParams) error
+	UpdateScheduledReport(ctx context.Context, arg UpdateScheduledReportParams) (ScheduledReport, error)
 	MarkPasswordResetUsed(ctx context.Context, id uuid.UUID) error
 	RemoveWorkspaceMember(ctx context.Context, arg RemoveWorkspaceMemberParams) error
+	RequeueWebhookDelivery(ctx context.Context, id uuid.UUID) (int64, error)
 	ResetWebhookFailureCount(ctx context.Context, id uuid.UUID) error
 	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
 	RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) error
 	RevokeSession(ctx context.Context, id uuid.UUID) error
+	RotateWebhookSecret(ctx context.Context, arg RotateWebhookSecretParams) (Webhook, error)
 	SetEmailVerified(ctx context.Context, id uuid.UUID) error
 	ShortCodeExists(ctx context.Context, shortCode string) (bool, error)
+	ShortCodeExistsIgnoreCase(ctx context.Context, lower string) (bool, error)
 	SoftDeleteBioPage(ctx context.Context, id uuid.UUID) error
 	SoftDeleteDomain(ctx context.Context, id uuid.UUID) error
 	SoftDeleteLink(ctx context.Context, id uuid.UUID) error
@@ -109,12 +170,19 @@ type Querier interface {
 	UpdateBioPageLinkPosition(ctx context.Context, arg UpdateBioPageLinkPositionParams) error
 	UpdateDomain(ctx context.Context, arg UpdateDomainParams) (Domain, error)
 	UpdateLink(ctx context.Context, arg UpdateLinkParams) (Link, error)
+	UpdateLinkMetadata(ctx context.Context, arg UpdateLinkMetadataParams) (Link, error)
+	ArchiveLink(ctx context.Context, arg ArchiveLinkParams) (Link, error)
+	UnarchiveLink(ctx context.Context, id uuid.UUID) (Link, error)
 	UpdateLinkRule(ctx context.Context, arg UpdateLinkRuleParams) (LinkRule, error)
 	UpdateMemberRole(ctx context.Context, arg UpdateMemberRoleParams) (WorkspaceMember, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
 	UpdateWorkspaceOwner(ctx context.Context, arg UpdateWorkspaceOwnerParams) (Workspace, error)
+	UpsertLinkFlag(ctx context.Context, arg UpsertLinkFlagParams) (LinkFlag, error)
+	UpsertLinkReport(ctx context.Context, arg UpsertLinkReportParams) (LinkReport, error)
+	UpsertTag(ctx context.Context, arg UpsertTagParams) (uuid.UUID, error)
+	UpsertUsageSnapshot(ctx context.Context, arg UpsertUsageSnapshotParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -62,6 +62,31 @@ func (q *Queries) DeleteExpiredSessions(ctx context.Context) error {
 	return err
 }
 
+const getActiveSessionByID = `-- name: GetActiveSessionByID :one
+SELECT id, user_id, refresh_token_hash, ip_address, user_agent, device_name, is_revoked, last_active_at, created_at, expires_at FROM sessions
+WHERE id = $1
+    AND is_revoked = FALSE
+    AND expires_at > NOW()
+`
+
+func (q *Queries) GetActiveSessionByID(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, getActiveSessionByID, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.RefreshTokenHash,
+		&i.IpAddress,
+		&i.UserAgent,
+		&i.DeviceName,
+		&i.IsRevoked,
+		&i.LastActiveAt,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
 const getSessionByToken = `-- name: GetSessionByToken :one
 SELECT id, user_id, refresh_token_hash, ip_address, user_agent, device_name, is_revoked, last_active_at, created_at, expires_at FROM sessions
 WHERE refresh_token_hash = $1
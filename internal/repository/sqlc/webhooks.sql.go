@@ -14,17 +14,18 @@ import (
 )
 
 const createWebhook = `-- name: CreateWebhook :one
-INSERT INTO webhooks (workspace_id, url, secret, events, is_active)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at
+INSERT INTO webhooks (workspace_id, url, secret, events, is_active, payload_version)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at, payload_version, previous_secret, previous_secret_expires_at
 `
 
 type CreateWebhookParams struct {
-	WorkspaceID uuid.UUID `json:"workspace_id"`
-	Url         string    `json:"url"`
-	Secret      string    `json:"secret"`
-	Events      []string  `json:"events"`
-	IsActive    bool      `json:"is_active"`
+	WorkspaceID    uuid.UUID   `json:"workspace_id"`
+	Url            string      `json:"url"`
+	Secret         string      `json:"secret"`
+	Events         []string    `json:"events"`
+	IsActive       bool        `json:"is_active"`
+	PayloadVersion pgtype.Int4 `json:"payload_version"`
 }
 
 func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
@@ -34,6 +35,7 @@ func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (W
 		arg.Secret,
 		arg.Events,
 		arg.IsActive,
+		arg.PayloadVersion,
 	)
 	var i Webhook
 	err := row.Scan(
@@ -48,12 +50,15 @@ func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (W
 		&i.LastSuccessAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PayloadVersion,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const getWebhookByID = `-- name: GetWebhookByID :one
-SELECT id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at FROM webhooks
+SELECT id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at, payload_version, previous_secret, previous_secret_expires_at FROM webhooks
 WHERE id = $1
 `
 
@@ -72,12 +77,15 @@ func (q *Queries) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, er
 		&i.LastSuccessAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PayloadVersion,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
 
 const listWebhooksForWorkspace = `-- name: ListWebhooksForWorkspace :many
-SELECT id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at FROM webhooks
+SELECT id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at, payload_version, previous_secret, previous_secret_expires_at FROM webhooks
 WHERE workspace_id = $1
 ORDER BY created_at DESC
 `
@@ -103,6 +111,9 @@ func (q *Queries) ListWebhooksForWorkspace(ctx context.Context, workspaceID uuid
 			&i.LastSuccessAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PayloadVersion,
+			&i.PreviousSecret,
+			&i.PreviousSecretExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -121,7 +132,7 @@ SET url = COALESCE($2, url),
     is_active = COALESCE($4, is_active),
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at
+RETURNING id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at, payload_version, previous_secret, previous_secret_expires_at
 `
 
 type UpdateWebhookParams struct {
@@ -151,6 +162,47 @@ func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (W
 		&i.LastSuccessAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PayloadVersion,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
+	)
+	return i, err
+}
+
+const rotateWebhookSecret = `-- name: RotateWebhookSecret :one
+UPDATE webhooks
+SET previous_secret = secret,
+    previous_secret_expires_at = $3,
+    secret = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at, payload_version, previous_secret, previous_secret_expires_at
+`
+
+type RotateWebhookSecretParams struct {
+	ID                      uuid.UUID          `json:"id"`
+	Secret                  string             `json:"secret"`
+	PreviousSecretExpiresAt pgtype.Timestamptz `json:"previous_secret_expires_at"`
+}
+
+func (q *Queries) RotateWebhookSecret(ctx context.Context, arg RotateWebhookSecretParams) (Webhook, error) {
+	row := q.db.QueryRow(ctx, rotateWebhookSecret, arg.ID, arg.Secret, arg.PreviousSecretExpiresAt)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Url,
+		&i.Secret,
+		&i.Events,
+		&i.IsActive,
+		&i.FailureCount,
+		&i.LastTriggeredAt,
+		&i.LastSuccessAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PayloadVersion,
+		&i.PreviousSecret,
+		&i.PreviousSecretExpiresAt,
 	)
 	return i, err
 }
@@ -166,7 +218,7 @@ func (q *Queries) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
 }
 
 const getActiveWebhooksForEvent = `-- name: GetActiveWebhooksForEvent :many
-SELECT id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at FROM webhooks
+SELECT id, workspace_id, url, secret, events, is_active, failure_count, last_triggered_at, last_success_at, created_at, updated_at, payload_version, previous_secret, previous_secret_expires_at FROM webhooks
 WHERE workspace_id = $1
   AND is_active = TRUE
   AND $2::text = ANY(events)
@@ -198,6 +250,9 @@ func (q *Queries) GetActiveWebhooksForEvent(ctx context.Context, arg GetActiveWe
 			&i.LastSuccessAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PayloadVersion,
+			&i.PreviousSecret,
+			&i.PreviousSecretExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -256,7 +311,7 @@ func (q *Queries) DisableWebhook(ctx context.Context, id uuid.UUID) error {
 const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
 INSERT INTO webhook_deliveries (webhook_id, event, payload, max_attempts)
 VALUES ($1, $2, $3, $4)
-RETURNING id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at
+RETURNING id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at, next_retry_at
 `
 
 type CreateWebhookDeliveryParams struct {
@@ -286,12 +341,13 @@ func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDe
 		&i.LastAttemptAt,
 		&i.CompletedAt,
 		&i.CreatedAt,
+		&i.NextRetryAt,
 	)
 	return i, err
 }
 
 const getWebhookDeliveryByID = `-- name: GetWebhookDeliveryByID :one
-SELECT id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at FROM webhook_deliveries
+SELECT id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at, next_retry_at FROM webhook_deliveries
 WHERE id = $1
 `
 
@@ -310,12 +366,13 @@ func (q *Queries) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (Web
 		&i.LastAttemptAt,
 		&i.CompletedAt,
 		&i.CreatedAt,
+		&i.NextRetryAt,
 	)
 	return i, err
 }
 
 const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
-SELECT id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at FROM webhook_deliveries
+SELECT id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at, next_retry_at FROM webhook_deliveries
 WHERE webhook_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -348,6 +405,7 @@ func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeli
 			&i.LastAttemptAt,
 			&i.CompletedAt,
 			&i.CreatedAt,
+			&i.NextRetryAt,
 		); err != nil {
 			return nil, err
 		}
@@ -377,7 +435,8 @@ SET response_status = $2,
     response_body = $3,
     attempts = $4,
     last_attempt_at = NOW(),
-    completed_at = $5
+    completed_at = $5,
+    next_retry_at = $6
 WHERE id = $1
 `
 
@@ -387,6 +446,7 @@ type UpdateWebhookDeliveryParams struct {
 	ResponseBody   pgtype.Text        `json:"response_body"`
 	Attempts       int32              `json:"attempts"`
 	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
+	NextRetryAt    pgtype.Timestamptz `json:"next_retry_at"`
 }
 
 func (q *Queries) UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) error {
@@ -396,15 +456,33 @@ func (q *Queries) UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDe
 		arg.ResponseBody,
 		arg.Attempts,
 		arg.CompletedAt,
+		arg.NextRetryAt,
 	)
 	return err
 }
 
+const requeueWebhookDelivery = `-- name: RequeueWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET attempts = 0,
+    last_attempt_at = NULL,
+    completed_at = NULL,
+    next_retry_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) RequeueWebhookDelivery(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, requeueWebhookDelivery, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getPendingWebhookDeliveries = `-- name: GetPendingWebhookDeliveries :many
-SELECT id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at FROM webhook_deliveries
+SELECT id, webhook_id, event, payload, response_status, response_body, attempts, max_attempts, last_attempt_at, completed_at, created_at, next_retry_at FROM webhook_deliveries
 WHERE completed_at IS NULL
   AND attempts < max_attempts
-  AND (last_attempt_at IS NULL OR last_attempt_at < NOW() - INTERVAL '30 seconds')
+  AND COALESCE(next_retry_at, last_attempt_at + INTERVAL '30 seconds', NOW()) <= NOW()
 ORDER BY created_at ASC
 LIMIT 50
 `
@@ -430,6 +508,7 @@ func (q *Queries) GetPendingWebhookDeliveries(ctx context.Context) ([]WebhookDel
 			&i.LastAttemptAt,
 			&i.CompletedAt,
 			&i.CreatedAt,
+			&i.NextRetryAt,
 		); err != nil {
 			return nil, err
 		}
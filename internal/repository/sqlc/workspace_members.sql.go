@@ -175,3 +175,37 @@ func (q *Queries) UpdateMemberRole(ctx context.Context, arg UpdateMemberRolePara
 	)
 	return i, err
 }
+
+const listWorkspacesOverMemberLimit = `-- name: ListWorkspacesOverMemberLimit :many
+SELECT wm.workspace_id, COUNT(*) AS member_count
+FROM workspace_members wm
+JOIN workspaces w ON w.id = wm.workspace_id AND w.deleted_at IS NULL
+GROUP BY wm.workspace_id
+HAVING COUNT(*) > $1::bigint
+ORDER BY wm.workspace_id
+`
+
+type ListWorkspacesOverMemberLimitRow struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	MemberCount int64     `json:"member_count"`
+}
+
+func (q *Queries) ListWorkspacesOverMemberLimit(ctx context.Context, maxMembers int64) ([]ListWorkspacesOverMemberLimitRow, error) {
+	rows, err := q.db.Query(ctx, listWorkspacesOverMemberLimit, maxMembers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWorkspacesOverMemberLimitRow{}
+	for rows.Next() {
+		var i ListWorkspacesOverMemberLimitRow
+		if err := rows.Scan(&i.WorkspaceID, &i.MemberCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
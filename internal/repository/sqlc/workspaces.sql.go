@@ -16,7 +16,7 @@ import (
 const createWorkspace = `-- name: CreateWorkspace :one
 INSERT INTO workspaces (name, slug, owner_id, plan, settings)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at
+RETURNING id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at, assets_cleaned_at
 `
 
 type CreateWorkspaceParams struct {
@@ -46,12 +46,13 @@ func (q *Queries) CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AssetsCleanedAt,
 	)
 	return i, err
 }
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
-SELECT id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at FROM workspaces
+SELECT id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at, assets_cleaned_at FROM workspaces
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -68,12 +69,13 @@ func (q *Queries) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AssetsCleanedAt,
 	)
 	return i, err
 }
 
 const getWorkspaceBySlug = `-- name: GetWorkspaceBySlug :one
-SELECT id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at FROM workspaces
+SELECT id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at, assets_cleaned_at FROM workspaces
 WHERE slug = $1 AND deleted_at IS NULL
 `
 
@@ -90,12 +92,13 @@ func (q *Queries) GetWorkspaceBySlug(ctx context.Context, slug string) (Workspac
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AssetsCleanedAt,
 	)
 	return i, err
 }
 
 const listWorkspacesForUser = `-- name: ListWorkspacesForUser :many
-SELECT w.id, w.name, w.slug, w.owner_id, w.plan, w.settings, w.created_at, w.updated_at, w.deleted_at FROM workspaces w
+SELECT w.id, w.name, w.slug, w.owner_id, w.plan, w.settings, w.created_at, w.updated_at, w.deleted_at, w.assets_cleaned_at FROM workspaces w
 JOIN workspace_members wm ON wm.workspace_id = w.id
 WHERE wm.user_id = $1 AND w.deleted_at IS NULL
 ORDER BY w.created_at DESC
@@ -120,6 +123,7 @@ func (q *Queries) ListWorkspacesForUser(ctx context.Context, userID uuid.UUID) (
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.AssetsCleanedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -144,6 +148,44 @@ func (q *Queries) GetWorkspaceCountForUser(ctx context.Context, userID uuid.UUID
 	return count, err
 }
 
+const listWorkspacesPendingCleanup = `-- name: ListWorkspacesPendingCleanup :many
+SELECT id FROM workspaces
+WHERE deleted_at IS NOT NULL AND assets_cleaned_at IS NULL
+ORDER BY deleted_at
+LIMIT $1
+`
+
+func (q *Queries) ListWorkspacesPendingCleanup(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listWorkspacesPendingCleanup, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markWorkspaceAssetsCleaned = `-- name: MarkWorkspaceAssetsCleaned :exec
+UPDATE workspaces
+SET assets_cleaned_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) MarkWorkspaceAssetsCleaned(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markWorkspaceAssetsCleaned, id)
+	return err
+}
+
 const softDeleteWorkspace = `-- name: SoftDeleteWorkspace :exec
 UPDATE workspaces
 SET deleted_at = NOW(), updated_at = NOW()
@@ -164,7 +206,7 @@ SET
     settings = COALESCE($5, settings),
     updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at
+RETURNING id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at, assets_cleaned_at
 `
 
 type UpdateWorkspaceParams struct {
@@ -194,6 +236,7 @@ func (q *Queries) UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AssetsCleanedAt,
 	)
 	return i, err
 }
@@ -202,7 +245,7 @@ const updateWorkspaceOwner = `-- name: UpdateWorkspaceOwner :one
 UPDATE workspaces
 SET owner_id = $2, updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at
+RETURNING id, name, slug, owner_id, plan, settings, created_at, updated_at, deleted_at, assets_cleaned_at
 `
 
 type UpdateWorkspaceOwnerParams struct {
@@ -223,6 +266,7 @@ func (q *Queries) UpdateWorkspaceOwner(ctx context.Context, arg UpdateWorkspaceO
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AssetsCleanedAt,
 	)
 	return i, err
 }
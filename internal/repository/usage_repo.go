@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type UsageRepository interface {
+	// CountForPeriod counts a workspace's metered usage in [start, end).
+	CountForPeriod(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*models.UsageCounts, error)
+	ListWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error)
+	UpsertSnapshot(ctx context.Context, workspaceID uuid.UUID, periodStart time.Time, tier string, counts models.UsageCounts) error
+	ListSnapshots(ctx context.Context, workspaceID uuid.UUID, limit int32) ([]*models.UsageSnapshot, error)
+}
+
+type usageRepository struct {
+	queries *sqlc.Queries
+	logger  *zap.Logger
+}
+
+func NewUsageRepository(queries *sqlc.Queries, logger *zap.Logger) UsageRepository {
+	return &usageRepository{queries: queries, logger: logger}
+}
+
+func (r *usageRepository) CountForPeriod(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (*models.UsageCounts, error) {
+	row, err := r.queries.GetWorkspaceUsage(ctx, sqlc.GetWorkspaceUsageParams{
+		WorkspaceID: workspaceID,
+		PeriodStart: pgtype.Timestamptz{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to count workspace usage")
+	}
+	return &models.UsageCounts{
+		LinksCreated:      row.LinksCreated,
+		ClicksTracked:     row.ClicksTracked,
+		QRCodesGenerated:  row.QrCodesGenerated,
+		WebhookDeliveries: row.WebhookDeliveries,
+	}, nil
+}
+
+func (r *usageRepository) ListWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error) {
+	ids, err := r.queries.ListActiveWorkspaceIDs(ctx)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list workspaces")
+	}
+	return ids, nil
+}
+
+func (r *usageRepository) UpsertSnapshot(ctx context.Context, workspaceID uuid.UUID, periodStart time.Time, tier string, counts models.UsageCounts) error {
+	err := r.queries.UpsertUsageSnapshot(ctx, sqlc.UpsertUsageSnapshotParams{
+		WorkspaceID:       workspaceID,
+		PeriodStart:       pgtype.Date{Time: periodStart, Valid: true},
+		Tier:              tier,
+		LinksCreated:      counts.LinksCreated,
+		ClicksTracked:     counts.ClicksTracked,
+		QrCodesGenerated:  counts.QRCodesGenerated,
+		WebhookDeliveries: counts.WebhookDeliveries,
+	})
+	if err != nil {
+		return httputil.Wrap(err, "failed to save usage snapshot")
+	}
+	return nil
+}
+
+func (r *usageRepository) ListSnapshots(ctx context.Context, workspaceID uuid.UUID, limit int32) ([]*models.UsageSnapshot, error) {
+	snapshots, err := r.queries.ListUsageSnapshots(ctx, sqlc.ListUsageSnapshotsParams{
+		WorkspaceID: workspaceID,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list usage snapshots")
+	}
+	result := make([]*models.UsageSnapshot, 0, len(snapshots))
+	for _, s := range snapshots {
+		result = append(result, models.UsageSnapshotFromSqlc(s))
+	}
+	return result, nil
+}
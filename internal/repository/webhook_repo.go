@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
 	"github.com/link-rift/link-rift/pkg/httputil"
@@ -17,6 +19,9 @@ type WebhookRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
 	List(ctx context.Context, workspaceID uuid.UUID) ([]*models.Webhook, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// RotateSecret replaces the signing secret, keeping the old one valid for
+	// signing until previousExpiresAt.
+	RotateSecret(ctx context.Context, id uuid.UUID, secret string, previousExpiresAt time.Time) (*models.Webhook, error)
 	GetActiveForEvent(ctx context.Context, workspaceID uuid.UUID, event string) ([]*models.Webhook, error)
 	IncrementFailureCount(ctx context.Context, id uuid.UUID) error
 	UpdateLastTriggered(ctx context.Context, id uuid.UUID) error
@@ -25,6 +30,8 @@ type WebhookRepository interface {
 	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int32) ([]*models.WebhookDelivery, error)
 	CountDeliveries(ctx context.Context, webhookID uuid.UUID) (int64, error)
 	UpdateDelivery(ctx context.Context, params sqlc.UpdateWebhookDeliveryParams) error
+	// RequeueDelivery resets a delivery's attempts so the worker retries it.
+	RequeueDelivery(ctx context.Context, id uuid.UUID) error
 	GetPendingDeliveries(ctx context.Context) ([]*models.WebhookDelivery, error)
 	CountRecentFailures(ctx context.Context, webhookID uuid.UUID) (int64, error)
 }
@@ -76,6 +83,21 @@ func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+func (r *webhookRepository) RotateSecret(ctx context.Context, id uuid.UUID, secret string, previousExpiresAt time.Time) (*models.Webhook, error) {
+	w, err := r.queries.RotateWebhookSecret(ctx, sqlc.RotateWebhookSecretParams{
+		ID:                      id,
+		Secret:                  secret,
+		PreviousSecretExpiresAt: pgtype.Timestamptz{Time: previousExpiresAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, httputil.NotFound("webhook")
+		}
+		return nil, httputil.Wrap(err, "failed to rotate webhook secret")
+	}
+	return models.WebhookFromSqlc(w), nil
+}
+
 func (r *webhookRepository) GetActiveForEvent(ctx context.Context, workspaceID uuid.UUID, event string) ([]*models.Webhook, error) {
 	webhooks, err := r.queries.GetActiveWebhooksForEvent(ctx, sqlc.GetActiveWebhooksForEventParams{
 		WorkspaceID: workspaceID,
@@ -151,6 +173,17 @@ func (r *webhookRepository) UpdateDelivery(ctx context.Context, params sqlc.Upda
 	return nil
 }
 
+func (r *webhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) error {
+	n, err := r.queries.RequeueWebhookDelivery(ctx, id)
+	if err != nil {
+		return httputil.Wrap(err, "failed to requeue webhook delivery")
+	}
+	if n == 0 {
+		return httputil.NotFound("webhook delivery")
+	}
+	return nil
+}
+
 func (r *webhookRepository) GetPendingDeliveries(ctx context.Context) ([]*models.WebhookDelivery, error) {
 	deliveries, err := r.queries.GetPendingWebhookDeliveries(ctx)
 	if err != nil {
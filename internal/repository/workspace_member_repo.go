@@ -20,6 +20,7 @@ type WorkspaceMemberRepository interface {
 	UpdateRole(ctx context.Context, params sqlc.UpdateMemberRoleParams) (*models.WorkspaceMember, error)
 	Remove(ctx context.Context, workspaceID, userID uuid.UUID) error
 	GetCount(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	ListOverLimit(ctx context.Context, maxMembers int64) (map[uuid.UUID]int64, error)
 }
 
 type workspaceMemberRepository struct {
@@ -100,3 +101,17 @@ func (r *workspaceMemberRepository) GetCount(ctx context.Context, workspaceID uu
 	}
 	return count, nil
 }
+
+// ListOverLimit returns the member count of every workspace with more than
+// maxMembers members.
+func (r *workspaceMemberRepository) ListOverLimit(ctx context.Context, maxMembers int64) (map[uuid.UUID]int64, error) {
+	rows, err := r.queries.ListWorkspacesOverMemberLimit(ctx, maxMembers)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list workspaces over member limit")
+	}
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.WorkspaceID] = row.MemberCount
+	}
+	return counts, nil
+}
@@ -22,6 +22,10 @@ type WorkspaceRepository interface {
 	UpdateOwner(ctx context.Context, params sqlc.UpdateWorkspaceOwnerParams) (*models.Workspace, error)
 	SoftDelete(ctx context.Context, id uuid.UUID) error
 	GetCountForUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	// ListPendingCleanup returns up to limit deleted workspaces whose
+	// resources haven't been cleaned up yet, oldest deletion first.
+	ListPendingCleanup(ctx context.Context, limit int32) ([]uuid.UUID, error)
+	MarkAssetsCleaned(ctx context.Context, id uuid.UUID) error
 }
 
 type workspaceRepository struct {
@@ -122,3 +126,18 @@ func (r *workspaceRepository) GetCountForUser(ctx context.Context, userID uuid.U
 	}
 	return count, nil
 }
+
+func (r *workspaceRepository) ListPendingCleanup(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	ids, err := r.queries.ListWorkspacesPendingCleanup(ctx, limit)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list workspaces pending cleanup")
+	}
+	return ids, nil
+}
+
+func (r *workspaceRepository) MarkAssetsCleaned(ctx context.Context, id uuid.UUID) error {
+	if err := r.queries.MarkWorkspaceAssetsCleaned(ctx, id); err != nil {
+		return httputil.Wrap(err, "failed to mark workspace cleaned up")
+	}
+	return nil
+}
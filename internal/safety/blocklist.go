@@ -0,0 +1,89 @@
+package safety
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SourceBlocklist is the Verdict source of Blocklist matches.
+const SourceBlocklist = "blocklist"
+
+// Blocklist matches destinations whose host is a listed domain or one of
+// its subdomains.
+type Blocklist struct {
+	domains map[string]struct{}
+}
+
+// NewBlocklist builds a blocklist from domain names.
+func NewBlocklist(domains []string) *Blocklist {
+	b := &Blocklist{domains: make(map[string]struct{}, len(domains))}
+	for _, d := range domains {
+		d = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			b.domains[d] = struct{}{}
+		}
+	}
+	return b
+}
+
+// LoadBlocklist reads a blocklist file with one domain per line. Blank lines
+// and lines starting with '#' are ignored, as is anything after the first
+// field, so hosts-file style lists ("0.0.0.0 example.com") also work.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening blocklist: %w", err)
+	}
+	defer f.Close()
+
+	domains, err := readDomains(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading blocklist %s: %w", path, err)
+	}
+	return NewBlocklist(domains), nil
+}
+
+func readDomains(r io.Reader) ([]string, error) {
+	var domains []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		domain := fields[0]
+		if len(fields) > 1 && (domain == "0.0.0.0" || domain == "127.0.0.1") {
+			domain = fields[1]
+		}
+		domains = append(domains, domain)
+	}
+	return domains, scanner.Err()
+}
+
+// Len returns the number of listed domains.
+func (b *Blocklist) Len() int {
+	return len(b.domains)
+}
+
+func (b *Blocklist) Check(_ context.Context, rawURL string) (*Verdict, error) {
+	host, err := hostname(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	for d := host; d != ""; {
+		if _, ok := b.domains[d]; ok {
+			return &Verdict{Source: SourceBlocklist, Reason: fmt.Sprintf("domain %s is on the blocklist", d)}, nil
+		}
+		i := strings.IndexByte(d, '.')
+		if i < 0 {
+			break
+		}
+		d = d[i+1:]
+	}
+	return nil, nil
+}
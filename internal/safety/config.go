@@ -0,0 +1,30 @@
+package safety
+
+import (
+	"net/http"
+
+	"github.com/link-rift/link-rift/internal/config"
+	"go.uber.org/zap"
+)
+
+// FromConfig builds the screening providers enabled in cfg. It returns nil
+// when screening is off.
+func FromConfig(cfg config.SafetyConfig, logger *zap.Logger) (Checker, error) {
+	if cfg.Mode == config.SafetyModeOff {
+		return nil, nil
+	}
+
+	var chain Chain
+	if cfg.BlocklistPath != "" {
+		blocklist, err := LoadBlocklist(cfg.BlocklistPath)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("loaded destination blocklist", zap.Int("domains", blocklist.Len()))
+		chain = append(chain, blocklist)
+	}
+	if cfg.SafeBrowsingAPIKey != "" {
+		chain = append(chain, NewSafeBrowsing(cfg.SafeBrowsingAPIKey, &http.Client{Timeout: cfg.Timeout}))
+	}
+	return chain, nil
+}
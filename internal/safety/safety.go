@@ -0,0 +1,60 @@
+// Package safety screens link destinations against malware and phishing
+// sources. Providers implement Checker; self-hosters can combine the
+// built-in ones with Chain or supply their own.
+package safety
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Verdict describes why a destination was matched.
+type Verdict struct {
+	// Source names the provider that matched, e.g. "blocklist".
+	Source string `json:"source"`
+	// Reason is a short human-readable explanation.
+	Reason string `json:"reason"`
+}
+
+// Checker screens a destination URL. It returns a nil Verdict when the URL
+// is not known to be harmful, and an error only when the check itself could
+// not be completed.
+type Checker interface {
+	Check(ctx context.Context, rawURL string) (*Verdict, error)
+}
+
+// Chain runs checkers in order and returns the first match. A checker that
+// fails doesn't stop the others; its error is returned only if no checker
+// matched.
+type Chain []Checker
+
+func (c Chain) Check(ctx context.Context, rawURL string) (*Verdict, error) {
+	var errs []error
+	for _, checker := range c {
+		verdict, err := checker.Check(ctx, rawURL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if verdict != nil {
+			return verdict, nil
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// hostname returns the lower-cased host of rawURL without a port or
+// trailing dot.
+func hostname(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return "", errors.New("safety: URL has no host")
+	}
+	return host, nil
+}
@@ -0,0 +1,144 @@
+package safety
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlocklistMatchesDomainAndSubdomains(t *testing.T) {
+	b := NewBlocklist([]string{"evil.example", "Phish.TEST."})
+
+	tests := []struct {
+		url   string
+		match bool
+	}{
+		{"https://evil.example/login", true},
+		{"https://www.evil.example:8443/", true},
+		{"https://phish.test", true},
+		{"https://notevil.example", false},
+		{"https://evil.example.org", false},
+		{"https://example", false},
+	}
+	for _, tt := range tests {
+		verdict, err := b.Check(context.Background(), tt.url)
+		if err != nil {
+			t.Fatalf("Check(%q): %v", tt.url, err)
+		}
+		if (verdict != nil) != tt.match {
+			t.Errorf("Check(%q) = %v, want match %v", tt.url, verdict, tt.match)
+		}
+		if verdict != nil && verdict.Source != SourceBlocklist {
+			t.Errorf("source = %q, want %q", verdict.Source, SourceBlocklist)
+		}
+	}
+}
+
+func TestLoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	content := "# phishing\nevil.example\n\n0.0.0.0 malware.test\n  spaced.test  # trailing note\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := LoadBlocklist(path)
+	if err != nil {
+		t.Fatalf("LoadBlocklist: %v", err)
+	}
+	if b.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", b.Len())
+	}
+	for _, u := range []string{"https://evil.example", "https://malware.test", "https://spaced.test"} {
+		if v, _ := b.Check(context.Background(), u); v == nil {
+			t.Errorf("expected %s to match", u)
+		}
+	}
+
+	if _, err := LoadBlocklist(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestSafeBrowsing(t *testing.T) {
+	var gotKey, gotURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.URL.Query().Get("key")
+		var req safeBrowsingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		gotURL = req.ThreatInfo.ThreatEntries[0]["url"]
+		if gotURL == "https://bad.example/" {
+			w.Write([]byte(`{"matches":[{"threatType":"SOCIAL_ENGINEERING"}]}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	sb := NewSafeBrowsing("test-key", srv.Client())
+	sb.endpoint = srv.URL
+
+	verdict, err := sb.Check(context.Background(), "https://bad.example/")
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if gotKey != "test-key" || gotURL != "https://bad.example/" {
+		t.Errorf("request key=%q url=%q", gotKey, gotURL)
+	}
+	if verdict == nil || verdict.Source != SourceSafeBrowsing || verdict.Reason != "reported as social engineering" {
+		t.Errorf("verdict = %+v", verdict)
+	}
+
+	verdict, err = sb.Check(context.Background(), "https://good.example/")
+	if err != nil || verdict != nil {
+		t.Errorf("clean URL: verdict=%v err=%v", verdict, err)
+	}
+}
+
+func TestSafeBrowsingErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	sb := NewSafeBrowsing("bad-key", srv.Client())
+	sb.endpoint = srv.URL
+	if _, err := sb.Check(context.Background(), "https://example.com"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+type checkerFunc func(ctx context.Context, rawURL string) (*Verdict, error)
+
+func (f checkerFunc) Check(ctx context.Context, rawURL string) (*Verdict, error) {
+	return f(ctx, rawURL)
+}
+
+func TestChain(t *testing.T) {
+	failing := checkerFunc(func(context.Context, string) (*Verdict, error) {
+		return nil, errors.New("provider down")
+	})
+	clean := checkerFunc(func(context.Context, string) (*Verdict, error) { return nil, nil })
+	matching := NewBlocklist([]string{"evil.example"})
+
+	verdict, err := Chain{failing, matching}.Check(context.Background(), "https://evil.example")
+	if err != nil || verdict == nil {
+		t.Errorf("expected a match despite the failing checker, got verdict=%v err=%v", verdict, err)
+	}
+
+	verdict, err = Chain{failing, clean}.Check(context.Background(), "https://ok.example")
+	if verdict != nil || err == nil {
+		t.Errorf("expected the provider error with no match, got verdict=%v err=%v", verdict, err)
+	}
+
+	verdict, err = Chain{clean, matching}.Check(context.Background(), "https://ok.example")
+	if verdict != nil || err != nil {
+		t.Errorf("expected no match, got verdict=%v err=%v", verdict, err)
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Circuit defaults, used when not set in config.
+const (
+	defaultAnalyticsCircuitFailures = 5
+	defaultAnalyticsCircuitCooldown = 30 * time.Second
+)
+
+// CircuitState is the state of an AnalyticsCircuit.
+type CircuitState string
+
+const (
+	// CircuitClosed sends queries to ClickHouse.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen sends queries straight to PostgreSQL until the cooldown
+	// ends.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets one query try ClickHouse again; the rest still
+	// go to PostgreSQL until it succeeds.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// AnalyticsCircuitStatus is a snapshot of an AnalyticsCircuit, as reported
+// by the readiness endpoint.
+type AnalyticsCircuitStatus struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"`
+	LastError           string       `json:"last_error,omitempty"`
+}
+
+// AnalyticsCircuit stops sending analytics queries to ClickHouse after
+// repeated failures. While it is open, queries go to PostgreSQL, which holds
+// the same clicks; after the cooldown one query tries ClickHouse again and
+// closes the circuit if it succeeds. A nil circuit never opens.
+type AnalyticsCircuit struct {
+	failureThreshold int
+	cooldown         time.Duration
+	logger           *zap.Logger
+	now              func() time.Time
+
+	mu         sync.Mutex
+	state      CircuitState
+	failures   int
+	openedAt   time.Time
+	probeStart time.Time
+	lastErr    string
+}
+
+// NewAnalyticsCircuit creates a closed circuit that opens after
+// failureThreshold consecutive failures and retries ClickHouse once cooldown
+// has passed.
+func NewAnalyticsCircuit(failureThreshold int, cooldown time.Duration, logger *zap.Logger) *AnalyticsCircuit {
+	if failureThreshold <= 0 {
+		failureThreshold = defaultAnalyticsCircuitFailures
+	}
+	if cooldown <= 0 {
+		cooldown = defaultAnalyticsCircuitCooldown
+	}
+	return &AnalyticsCircuit{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		logger:           logger,
+		now:              time.Now,
+		state:            CircuitClosed,
+	}
+}
+
+// Allow reports whether a query should try ClickHouse.
+func (c *AnalyticsCircuit) Allow() bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	switch c.state {
+	case CircuitOpen:
+		if now.Sub(c.openedAt) < c.cooldown {
+			return false
+		}
+		c.state = CircuitHalfOpen
+		c.probeStart = now
+		c.logger.Info("retrying ClickHouse analytics", zap.String("circuit", string(c.state)))
+		return true
+	case CircuitHalfOpen:
+		// A probe whose outcome was never reported, e.g. because its request
+		// was cancelled, doesn't hold the circuit half-open forever.
+		if now.Sub(c.probeStart) < c.cooldown {
+			return false
+		}
+		c.probeStart = now
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a ClickHouse query that succeeded.
+func (c *AnalyticsCircuit) Success() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != CircuitClosed {
+		c.logger.Info("ClickHouse analytics recovered", zap.String("circuit", string(CircuitClosed)))
+	}
+	c.state = CircuitClosed
+	c.failures = 0
+	c.lastErr = ""
+}
+
+// Failure records a ClickHouse query that failed.
+func (c *AnalyticsCircuit) Failure(err error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	c.lastErr = err.Error()
+	if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= c.failureThreshold) {
+		c.state = CircuitOpen
+		c.openedAt = c.now()
+		c.logger.Warn("ClickHouse analytics failing, using PostgreSQL",
+			zap.String("circuit", string(c.state)),
+			zap.Int("consecutive_failures", c.failures),
+			zap.Duration("retry_in", c.cooldown),
+			zap.Error(err),
+		)
+	}
+}
+
+// Status returns the circuit's current state.
+func (c *AnalyticsCircuit) Status() AnalyticsCircuitStatus {
+	if c == nil {
+		return AnalyticsCircuitStatus{State: CircuitClosed}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := AnalyticsCircuitStatus{
+		State:               c.state,
+		ConsecutiveFailures: c.failures,
+		LastError:           c.lastErr,
+	}
+	if c.state != CircuitClosed {
+		openedAt := c.openedAt
+		retryAt := openedAt.Add(c.cooldown)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+	return status
+}
+
+// queryWithFallback runs query on ClickHouse unless the circuit is open, and
+// on PostgreSQL when ClickHouse is skipped or fails. Queries cancelled by
+// the caller don't count as failures.
+func queryWithFallback[T any](ctx context.Context, backends AnalyticsBackends, logger *zap.Logger, query func(repository.AnalyticsRepository) (T, error), fields ...zap.Field) (T, error) {
+	if !backends.Circuit.Allow() {
+		return query(backends.Postgres)
+	}
+	result, err := query(backends.ClickHouse)
+	if err == nil {
+		backends.Circuit.Success()
+		return result, nil
+	}
+	if ctx.Err() != nil {
+		return result, err
+	}
+	backends.Circuit.Failure(err)
+	logger.Warn("ClickHouse analytics query failed, falling back to PostgreSQL", append(fields, zap.Error(err))...)
+	return query(backends.Postgres)
+}
+
+// fallbackAnalyticsRepo is the ClickHouse store with queries falling back
+// to PostgreSQL as in queryWithFallback. It is what AnalyticsBackends.Default
+// returns when ClickHouse is the default.
+type fallbackAnalyticsRepo struct {
+	backends AnalyticsBackends
+	logger   *zap.Logger
+}
+
+func (r *fallbackAnalyticsRepo) GetLinkStats(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.LinkAnalytics, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) (*models.LinkAnalytics, error) {
+		return repo.GetLinkStats(ctx, linkID, dr)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetWorkspaceStats(ctx context.Context, workspaceID uuid.UUID, dr models.DateRange) (*models.WorkspaceAnalytics, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) (*models.WorkspaceAnalytics, error) {
+		return repo.GetWorkspaceStats(ctx, workspaceID, dr)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetTimeSeries(ctx context.Context, linkID uuid.UUID, interval models.TimeSeriesInterval, dr models.DateRange) ([]models.TimeSeriesPoint, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) ([]models.TimeSeriesPoint, error) {
+		return repo.GetTimeSeries(ctx, linkID, interval, dr)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetTopReferrers(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) ([]models.ReferrerStats, error) {
+		return repo.GetTopReferrers(ctx, linkID, dr, limit)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetTopCountries(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) ([]models.CountryStats, error) {
+		return repo.GetTopCountries(ctx, linkID, dr, limit)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetDeviceBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) (*models.DeviceBreakdown, error) {
+		return repo.GetDeviceBreakdown(ctx, linkID, dr)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetBrowserBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) ([]models.BrowserStats, error) {
+		return repo.GetBrowserBreakdown(ctx, linkID, dr, limit)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetClicksByHourOfWeek(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.ClickHeatmap, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) (*models.ClickHeatmap, error) {
+		return repo.GetClicksByHourOfWeek(ctx, linkID, dr)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetWorkspaceReferrers(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) ([]models.ReferrerStats, error) {
+		return repo.GetWorkspaceReferrers(ctx, workspaceID, filter, dr, limit)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetWorkspaceCountries(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) ([]models.CountryStats, error) {
+		return repo.GetWorkspaceCountries(ctx, workspaceID, filter, dr, limit)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetWorkspaceDevices(ctx context.Context, workspaceID uuid.UUID, filter models.WorkspaceAnalyticsFilter, dr models.DateRange) (*models.DeviceBreakdown, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) (*models.DeviceBreakdown, error) {
+		return repo.GetWorkspaceDevices(ctx, workspaceID, filter, dr)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetLinkClickSummaries(ctx context.Context, linkIDs []uuid.UUID, dr models.DateRange) (map[uuid.UUID]models.LinkClickSummary, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) (map[uuid.UUID]models.LinkClickSummary, error) {
+		return repo.GetLinkClickSummaries(ctx, linkIDs, dr)
+	})
+}
+
+func (r *fallbackAnalyticsRepo) GetLastClickedAt(ctx context.Context, linkIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	return queryWithFallback(ctx, r.backends, r.logger, func(repo repository.AnalyticsRepository) (map[uuid.UUID]time.Time, error) {
+		return repo.GetLastClickedAt(ctx, linkIDs)
+	})
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+type countingAnalyticsRepo struct {
+	mockAnalyticsRepo
+	calls int
+}
+
+func (m *countingAnalyticsRepo) GetLinkStats(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.LinkAnalytics, error) {
+	m.calls++
+	return m.mockAnalyticsRepo.GetLinkStats(ctx, linkID, dr)
+}
+
+func TestAnalyticsCircuit(t *testing.T) {
+	pg := &mockAnalyticsRepo{linkStats: &models.LinkAnalytics{TotalClicks: 1}}
+	ch := &countingAnalyticsRepo{mockAnalyticsRepo: mockAnalyticsRepo{
+		linkStats: &models.LinkAnalytics{TotalClicks: 2},
+		err:       errors.New("clickhouse: connection refused"),
+	}}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	circuit := NewAnalyticsCircuit(3, time.Minute, zap.NewNop())
+	circuit.now = func() time.Time { return now }
+
+	repo := AnalyticsBackends{Postgres: pg, ClickHouse: ch, ClickHouseDefault: true, Circuit: circuit}.Default(zap.NewNop())
+	query := func() int64 {
+		t.Helper()
+		stats, err := repo.GetLinkStats(context.Background(), uuid.New(), models.DateRange{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return stats.TotalClicks
+	}
+
+	// Failures fall back to PostgreSQL until the circuit opens
+	for i := 0; i < 3; i++ {
+		if got := query(); got != 1 {
+			t.Fatalf("query %d: got %d clicks, want the PostgreSQL stats", i, got)
+		}
+	}
+	if status := circuit.Status(); status.State != CircuitOpen || status.RetryAt == nil || !status.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("status = %+v, want open until a minute from now", status)
+	}
+
+	// While open, ClickHouse isn't queried at all
+	query()
+	if ch.calls != 3 {
+		t.Errorf("ClickHouse queried %d times, want 3", ch.calls)
+	}
+
+	// After the cooldown one failed probe reopens the circuit
+	now = now.Add(time.Minute)
+	query()
+	query()
+	if ch.calls != 4 || circuit.Status().State != CircuitOpen {
+		t.Errorf("after a failed probe: %d ClickHouse calls, state %s", ch.calls, circuit.Status().State)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	ch.err = nil
+	if got := query(); got != 2 {
+		t.Errorf("got %d clicks after recovery, want the ClickHouse stats", got)
+	}
+	if status := circuit.Status(); status.State != CircuitClosed || status.ConsecutiveFailures != 0 {
+		t.Errorf("status = %+v, want closed", status)
+	}
+}
+
+func TestAnalyticsCircuit_IgnoresCancelledQueries(t *testing.T) {
+	circuit := NewAnalyticsCircuit(1, time.Minute, zap.NewNop())
+	ch := &mockAnalyticsRepo{err: context.Canceled}
+	backends := AnalyticsBackends{Postgres: &mockAnalyticsRepo{}, ClickHouse: ch, ClickHouseDefault: true, Circuit: circuit}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := backends.Default(zap.NewNop()).GetLinkStats(ctx, uuid.New(), models.DateRange{}); err == nil {
+		t.Error("expected the cancelled query's error")
+	}
+	if circuit.Status().State != CircuitClosed {
+		t.Error("a cancelled query opened the circuit")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/link-rift/link-rift/internal/license"
@@ -17,35 +18,148 @@ import (
 
 // AnalyticsService provides analytics data with feature gating and retention clamping.
 type AnalyticsService interface {
-	GetLinkStats(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.LinkAnalytics, error)
+	GetLinkStats(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange) (*models.LinkAnalytics, error)
 	GetWorkspaceStats(ctx context.Context, workspaceID uuid.UUID, dr models.DateRange) (*models.WorkspaceAnalytics, error)
-	GetTimeSeries(ctx context.Context, linkID uuid.UUID, interval models.TimeSeriesInterval, dr models.DateRange) ([]models.TimeSeriesPoint, error)
-	GetTopReferrers(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error)
-	GetTopCountries(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error)
-	GetDeviceBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error)
-	GetBrowserBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error)
-	ExportLinkData(ctx context.Context, linkID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat) ([]byte, string, error)
+	GetTimeSeries(ctx context.Context, linkID, workspaceID uuid.UUID, interval models.TimeSeriesInterval, dr models.DateRange) ([]models.TimeSeriesPoint, error)
+	GetTopReferrers(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error)
+	GetTopCountries(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error)
+	GetDeviceBreakdown(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error)
+	GetBrowserBreakdown(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error)
+	GetClickHeatmap(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange) (*models.ClickHeatmap, error)
+	GetWorkspaceReferrers(ctx context.Context, workspaceID uuid.UUID, tagID *uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error)
+	GetWorkspaceCountries(ctx context.Context, workspaceID uuid.UUID, tagID *uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error)
+	GetWorkspaceDevices(ctx context.Context, workspaceID uuid.UUID, tagID *uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error)
+	ExportLinkData(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat) ([]byte, string, error)
+	CreateShareToken(ctx context.Context, linkID, workspaceID uuid.UUID, ttl time.Duration, dr models.DateRange) (*models.CreateAnalyticsShareResponse, error)
+	ListShareTokens(ctx context.Context, linkID, workspaceID uuid.UUID) ([]*models.AnalyticsShare, error)
+	RevokeShareToken(ctx context.Context, shareID, workspaceID uuid.UUID) error
+	GetSharedLinkAnalytics(ctx context.Context, token string) (*models.SharedLinkAnalytics, error)
+}
+
+// AnalyticsBackends are the analytics stores a workspace's queries can be
+// routed to. Postgres holds every click and is always set; ClickHouse is nil
+// when it isn't configured or couldn't be reached at startup.
+type AnalyticsBackends struct {
+	Postgres   repository.AnalyticsRepository
+	ClickHouse repository.AnalyticsRepository
+	// ClickHouseDefault routes workspaces without an analytics_backend
+	// setting to ClickHouse.
+	ClickHouseDefault bool
+	// Circuit, when set, skips ClickHouse for a while after repeated
+	// failures. Failed ClickHouse queries fall back to Postgres either way.
+	Circuit *AnalyticsCircuit
+}
+
+// Default returns the store used for workspaces that haven't chosen one.
+// When that is ClickHouse, its queries fall back to Postgres as the
+// analytics service's do.
+func (b AnalyticsBackends) Default(logger *zap.Logger) repository.AnalyticsRepository {
+	if b.ClickHouse != nil && b.ClickHouseDefault {
+		return &fallbackAnalyticsRepo{backends: b, logger: logger}
+	}
+	return b.Postgres
 }
 
 type analyticsService struct {
-	repo       repository.AnalyticsRepository
-	clickRepo  repository.ClickRepository
-	licManager *license.Manager
-	logger     *zap.Logger
+	backends      AnalyticsBackends
+	workspaceRepo repository.WorkspaceRepository
+	clickRepo     repository.ClickRepository
+	linkRepo      repository.LinkRepository
+	shareRepo     repository.AnalyticsShareRepository
+	shareSecret   string
+	licManager    *license.Manager
+	logger        *zap.Logger
 }
 
+// NewAnalyticsService creates the analytics service. Each workspace's
+// queries go to the backend its settings choose. shareSecret signs public
+// analytics share tokens.
 func NewAnalyticsService(
-	repo repository.AnalyticsRepository,
+	backends AnalyticsBackends,
+	workspaceRepo repository.WorkspaceRepository,
 	clickRepo repository.ClickRepository,
+	linkRepo repository.LinkRepository,
+	shareRepo repository.AnalyticsShareRepository,
+	shareSecret string,
 	licManager *license.Manager,
 	logger *zap.Logger,
 ) AnalyticsService {
 	return &analyticsService{
-		repo:       repo,
-		clickRepo:  clickRepo,
-		licManager: licManager,
-		logger:     logger,
+		backends:      backends,
+		workspaceRepo: workspaceRepo,
+		clickRepo:     clickRepo,
+		linkRepo:      linkRepo,
+		shareRepo:     shareRepo,
+		shareSecret:   shareSecret,
+		licManager:    licManager,
+		logger:        logger,
+	}
+}
+
+// backendFor returns the analytics store for a workspace, and whether it is
+// ClickHouse. Workspaces whose settings can't be loaded use the default.
+func (s *analyticsService) backendFor(ctx context.Context, workspaceID uuid.UUID) (repository.AnalyticsRepository, bool) {
+	if s.backends.ClickHouse == nil {
+		return s.backends.Postgres, false
+	}
+	useClickHouse := s.backends.ClickHouseDefault
+	ws, err := s.workspaceRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		s.logger.Warn("failed to load workspace analytics backend", zap.String("workspace_id", workspaceID.String()), zap.Error(err))
+	} else {
+		switch ws.ParsedSettings().AnalyticsBackend {
+		case models.AnalyticsBackendPostgres:
+			useClickHouse = false
+		case models.AnalyticsBackendClickHouse:
+			useClickHouse = true
+		}
+	}
+	if useClickHouse {
+		return s.backends.ClickHouse, true
+	}
+	return s.backends.Postgres, false
+}
+
+// workspaceLocation returns the workspace's analytics time zone, UTC when
+// its settings can't be loaded.
+func (s *analyticsService) workspaceLocation(ctx context.Context, workspaceID uuid.UUID) *time.Location {
+	ws, err := s.workspaceRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		s.logger.Warn("failed to load workspace time zone", zap.String("workspace_id", workspaceID.String()), zap.Error(err))
+		return time.UTC
+	}
+	return ws.ParsedSettings().Location()
+}
+
+// queryAnalytics runs query against the workspace's analytics store. A failed
+// ClickHouse query is retried on Postgres, which holds the same clicks, and
+// while the circuit is open ClickHouse isn't tried at all.
+func queryAnalytics[T any](ctx context.Context, s *analyticsService, workspaceID uuid.UUID, query func(repository.AnalyticsRepository) (T, error)) (T, error) {
+	repo, isClickHouse := s.backendFor(ctx, workspaceID)
+	if !isClickHouse {
+		return query(repo)
 	}
+	return queryWithFallback(ctx, s.backends, s.logger, query, zap.String("workspace_id", workspaceID.String()))
+}
+
+// linkReport is the stats and daily time series exports and shares include.
+type linkReport struct {
+	stats      *models.LinkAnalytics
+	timeSeries []models.TimeSeriesPoint
+}
+
+func (s *analyticsService) getLinkReport(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange) (linkReport, error) {
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) (linkReport, error) {
+		var r linkReport
+		var err error
+		if r.stats, err = repo.GetLinkStats(ctx, linkID, dr); err != nil {
+			return r, fmt.Errorf("get stats: %w", err)
+		}
+		if r.timeSeries, err = repo.GetTimeSeries(ctx, linkID, models.IntervalDay, dr); err != nil {
+			return r, fmt.Errorf("get time series: %w", err)
+		}
+		return r, nil
+	})
 }
 
 func (s *analyticsService) clampDateRange(dr models.DateRange) models.DateRange {
@@ -53,70 +167,150 @@ func (s *analyticsService) clampDateRange(dr models.DateRange) models.DateRange
 	return dr.ClampToRetention(retentionDays)
 }
 
-func (s *analyticsService) GetLinkStats(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.LinkAnalytics, error) {
+func (s *analyticsService) GetLinkStats(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange) (*models.LinkAnalytics, error) {
 	dr = s.clampDateRange(dr)
-	return s.repo.GetLinkStats(ctx, linkID, dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) (*models.LinkAnalytics, error) {
+		return repo.GetLinkStats(ctx, linkID, dr)
+	})
 }
 
 func (s *analyticsService) GetWorkspaceStats(ctx context.Context, workspaceID uuid.UUID, dr models.DateRange) (*models.WorkspaceAnalytics, error) {
 	dr = s.clampDateRange(dr)
-	return s.repo.GetWorkspaceStats(ctx, workspaceID, dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) (*models.WorkspaceAnalytics, error) {
+		return repo.GetWorkspaceStats(ctx, workspaceID, dr)
+	})
 }
 
-func (s *analyticsService) GetTimeSeries(ctx context.Context, linkID uuid.UUID, interval models.TimeSeriesInterval, dr models.DateRange) ([]models.TimeSeriesPoint, error) {
+func (s *analyticsService) GetTimeSeries(ctx context.Context, linkID, workspaceID uuid.UUID, interval models.TimeSeriesInterval, dr models.DateRange) ([]models.TimeSeriesPoint, error) {
 	dr = s.clampDateRange(dr)
-	return s.repo.GetTimeSeries(ctx, linkID, interval, dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) ([]models.TimeSeriesPoint, error) {
+		return repo.GetTimeSeries(ctx, linkID, interval, dr)
+	})
 }
 
-func (s *analyticsService) GetTopReferrers(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+func (s *analyticsService) GetTopReferrers(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
 	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
 		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
 	}
 	dr = s.clampDateRange(dr)
-	return s.repo.GetTopReferrers(ctx, linkID, dr, limit)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) ([]models.ReferrerStats, error) {
+		return repo.GetTopReferrers(ctx, linkID, dr, limit)
+	})
 }
 
-func (s *analyticsService) GetTopCountries(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+func (s *analyticsService) GetTopCountries(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error) {
 	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
 		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
 	}
 	dr = s.clampDateRange(dr)
-	return s.repo.GetTopCountries(ctx, linkID, dr, limit)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) ([]models.CountryStats, error) {
+		return repo.GetTopCountries(ctx, linkID, dr, limit)
+	})
 }
 
-func (s *analyticsService) GetDeviceBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error) {
+func (s *analyticsService) GetDeviceBreakdown(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error) {
 	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
 		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
 	}
 	dr = s.clampDateRange(dr)
-	return s.repo.GetDeviceBreakdown(ctx, linkID, dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) (*models.DeviceBreakdown, error) {
+		return repo.GetDeviceBreakdown(ctx, linkID, dr)
+	})
 }
 
-func (s *analyticsService) GetBrowserBreakdown(ctx context.Context, linkID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error) {
+func (s *analyticsService) GetBrowserBreakdown(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, limit int) ([]models.BrowserStats, error) {
 	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
 		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
 	}
 	dr = s.clampDateRange(dr)
-	return s.repo.GetBrowserBreakdown(ctx, linkID, dr, limit)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) ([]models.BrowserStats, error) {
+		return repo.GetBrowserBreakdown(ctx, linkID, dr, limit)
+	})
 }
 
-func (s *analyticsService) ExportLinkData(ctx context.Context, linkID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat) ([]byte, string, error) {
-	if !s.licManager.HasFeature(license.FeatureExportData) {
-		return nil, "", httputil.PaymentRequiredWithDetails(string(license.FeatureExportData), "pro")
+func (s *analyticsService) GetClickHeatmap(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange) (*models.ClickHeatmap, error) {
+	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
+		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
+	}
+	dr = s.clampDateRange(dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) (*models.ClickHeatmap, error) {
+		return repo.GetClicksByHourOfWeek(ctx, linkID, dr)
+	})
+}
+
+// workspaceFilter resolves an optional tag into the set of links to
+// aggregate. ok is false when the tag matches no links, so callers can return
+// an empty result without querying. Links have no folders, so tags are the
+// only grouping a workspace breakdown can be filtered by.
+func (s *analyticsService) workspaceFilter(ctx context.Context, workspaceID uuid.UUID, tagID *uuid.UUID) (filter models.WorkspaceAnalyticsFilter, ok bool, err error) {
+	if tagID == nil {
+		return filter, true, nil
+	}
+	ids, err := s.linkRepo.ListIDsByTag(ctx, workspaceID, *tagID)
+	if err != nil {
+		return filter, false, err
 	}
+	filter.LinkIDs = ids
+	return filter, len(ids) > 0, nil
+}
 
+func (s *analyticsService) GetWorkspaceReferrers(ctx context.Context, workspaceID uuid.UUID, tagID *uuid.UUID, dr models.DateRange, limit int) ([]models.ReferrerStats, error) {
+	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
+		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
+	}
+	filter, ok, err := s.workspaceFilter(ctx, workspaceID, tagID)
+	if err != nil || !ok {
+		return []models.ReferrerStats{}, err
+	}
+	dr = s.clampDateRange(dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) ([]models.ReferrerStats, error) {
+		return repo.GetWorkspaceReferrers(ctx, workspaceID, filter, dr, limit)
+	})
+}
+
+func (s *analyticsService) GetWorkspaceCountries(ctx context.Context, workspaceID uuid.UUID, tagID *uuid.UUID, dr models.DateRange, limit int) ([]models.CountryStats, error) {
+	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
+		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
+	}
+	filter, ok, err := s.workspaceFilter(ctx, workspaceID, tagID)
+	if err != nil || !ok {
+		return []models.CountryStats{}, err
+	}
 	dr = s.clampDateRange(dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) ([]models.CountryStats, error) {
+		return repo.GetWorkspaceCountries(ctx, workspaceID, filter, dr, limit)
+	})
+}
 
-	// Get stats + time series for export
-	stats, err := s.repo.GetLinkStats(ctx, linkID, dr)
+func (s *analyticsService) GetWorkspaceDevices(ctx context.Context, workspaceID uuid.UUID, tagID *uuid.UUID, dr models.DateRange) (*models.DeviceBreakdown, error) {
+	if !s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
+		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureAdvancedAnalytics), "pro")
+	}
+	filter, ok, err := s.workspaceFilter(ctx, workspaceID, tagID)
 	if err != nil {
-		return nil, "", fmt.Errorf("export get stats: %w", err)
+		return nil, err
 	}
+	if !ok {
+		return &models.DeviceBreakdown{}, nil
+	}
+	dr = s.clampDateRange(dr)
+	return queryAnalytics(ctx, s, workspaceID, func(repo repository.AnalyticsRepository) (*models.DeviceBreakdown, error) {
+		return repo.GetWorkspaceDevices(ctx, workspaceID, filter, dr)
+	})
+}
+
+func (s *analyticsService) ExportLinkData(ctx context.Context, linkID, workspaceID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat) ([]byte, string, error) {
+	if !s.licManager.HasFeature(license.FeatureExportData) {
+		return nil, "", httputil.PaymentRequiredWithDetails(string(license.FeatureExportData), "pro")
+	}
+
+	dr = s.clampDateRange(dr)
 
-	timeSeries, err := s.repo.GetTimeSeries(ctx, linkID, models.IntervalDay, dr)
+	report, err := s.getLinkReport(ctx, linkID, workspaceID, dr)
 	if err != nil {
-		return nil, "", fmt.Errorf("export get time series: %w", err)
+		return nil, "", fmt.Errorf("export: %w", err)
 	}
+	stats, timeSeries := report.stats, report.timeSeries
 
 	switch format {
 	case models.ExportJSON:
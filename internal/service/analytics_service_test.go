@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -21,7 +23,12 @@ type mockAnalyticsRepo struct {
 	countries       []models.CountryStats
 	deviceBreakdown *models.DeviceBreakdown
 	browsers        []models.BrowserStats
+	heatmap         *models.ClickHeatmap
+	clickSummaries  map[uuid.UUID]models.LinkClickSummary
+	lastClicked     map[uuid.UUID]time.Time
 	err             error
+	// timeSeriesRange is the range of the last GetTimeSeries call.
+	timeSeriesRange models.DateRange
 }
 
 func (m *mockAnalyticsRepo) GetLinkStats(_ context.Context, _ uuid.UUID, _ models.DateRange) (*models.LinkAnalytics, error) {
@@ -30,7 +37,8 @@ func (m *mockAnalyticsRepo) GetLinkStats(_ context.Context, _ uuid.UUID, _ model
 func (m *mockAnalyticsRepo) GetWorkspaceStats(_ context.Context, _ uuid.UUID, _ models.DateRange) (*models.WorkspaceAnalytics, error) {
 	return m.workspaceStats, m.err
 }
-func (m *mockAnalyticsRepo) GetTimeSeries(_ context.Context, _ uuid.UUID, _ models.TimeSeriesInterval, _ models.DateRange) ([]models.TimeSeriesPoint, error) {
+func (m *mockAnalyticsRepo) GetTimeSeries(_ context.Context, _ uuid.UUID, _ models.TimeSeriesInterval, dr models.DateRange) ([]models.TimeSeriesPoint, error) {
+	m.timeSeriesRange = dr
 	return m.timeSeries, m.err
 }
 func (m *mockAnalyticsRepo) GetTopReferrers(_ context.Context, _ uuid.UUID, _ models.DateRange, _ int) ([]models.ReferrerStats, error) {
@@ -45,6 +53,27 @@ func (m *mockAnalyticsRepo) GetDeviceBreakdown(_ context.Context, _ uuid.UUID, _
 func (m *mockAnalyticsRepo) GetBrowserBreakdown(_ context.Context, _ uuid.UUID, _ models.DateRange, _ int) ([]models.BrowserStats, error) {
 	return m.browsers, m.err
 }
+func (m *mockAnalyticsRepo) GetClicksByHourOfWeek(_ context.Context, _ uuid.UUID, _ models.DateRange) (*models.ClickHeatmap, error) {
+	return m.heatmap, m.err
+}
+
+func (m *mockAnalyticsRepo) GetWorkspaceReferrers(_ context.Context, _ uuid.UUID, _ models.WorkspaceAnalyticsFilter, _ models.DateRange, _ int) ([]models.ReferrerStats, error) {
+	return m.referrers, m.err
+}
+func (m *mockAnalyticsRepo) GetWorkspaceCountries(_ context.Context, _ uuid.UUID, _ models.WorkspaceAnalyticsFilter, _ models.DateRange, _ int) ([]models.CountryStats, error) {
+	return m.countries, m.err
+}
+func (m *mockAnalyticsRepo) GetWorkspaceDevices(_ context.Context, _ uuid.UUID, _ models.WorkspaceAnalyticsFilter, _ models.DateRange) (*models.DeviceBreakdown, error) {
+	return m.deviceBreakdown, m.err
+}
+
+func (m *mockAnalyticsRepo) GetLinkClickSummaries(_ context.Context, _ []uuid.UUID, _ models.DateRange) (map[uuid.UUID]models.LinkClickSummary, error) {
+	return m.clickSummaries, m.err
+}
+
+func (m *mockAnalyticsRepo) GetLastClickedAt(_ context.Context, _ []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	return m.lastClicked, m.err
+}
 
 func newTestLicenseManager(tier license.Tier) *license.Manager {
 	v, _ := license.NewVerifier()
@@ -66,10 +95,10 @@ func TestGetLinkStats(t *testing.T) {
 		},
 	}
 
-	svc := NewAnalyticsService(repo, nil, newTestLicenseManager(license.TierFree), zap.NewNop())
+	svc := NewAnalyticsService(AnalyticsBackends{Postgres: repo}, nil, nil, nil, nil, "", newTestLicenseManager(license.TierFree), zap.NewNop())
 
-	dr := models.DateRangeFromPreset("7d")
-	stats, err := svc.GetLinkStats(context.Background(), uuid.New(), dr)
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
+	stats, err := svc.GetLinkStats(context.Background(), uuid.New(), uuid.New(), dr)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -78,6 +107,59 @@ func TestGetLinkStats(t *testing.T) {
 	}
 }
 
+func TestAnalyticsBackendRouting(t *testing.T) {
+	pg := &mockAnalyticsRepo{linkStats: &models.LinkAnalytics{TotalClicks: 1}}
+	ch := &mockAnalyticsRepo{linkStats: &models.LinkAnalytics{TotalClicks: 2}}
+	defaultWS, pgWS, chWS := uuid.New(), uuid.New(), uuid.New()
+	wsRepo := &mockWorkspaceRepo{workspaces: map[uuid.UUID]*models.Workspace{
+		defaultWS: {ID: defaultWS, Settings: json.RawMessage(`{}`)},
+		pgWS:      {ID: pgWS, Settings: json.RawMessage(`{"analytics_backend":"postgres"}`)},
+		chWS:      {ID: chWS, Settings: json.RawMessage(`{"analytics_backend":"clickhouse"}`)},
+	}}
+	backends := AnalyticsBackends{Postgres: pg, ClickHouse: ch}
+	svc := NewAnalyticsService(backends, wsRepo, nil, nil, nil, "", newTestLicenseManager(license.TierFree), zap.NewNop())
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
+
+	tests := []struct {
+		name      string
+		workspace uuid.UUID
+		want      int64
+	}{
+		{"default", defaultWS, 1},
+		{"postgres", pgWS, 1},
+		{"clickhouse", chWS, 2},
+	}
+	for _, tt := range tests {
+		stats, err := svc.GetLinkStats(context.Background(), uuid.New(), tt.workspace, dr)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if stats.TotalClicks != tt.want {
+			t.Errorf("%s: got stats from the wrong backend (%d clicks)", tt.name, stats.TotalClicks)
+		}
+	}
+
+	// With ClickHouse as the default only an explicit postgres setting opts out
+	backends.ClickHouseDefault = true
+	svc = NewAnalyticsService(backends, wsRepo, nil, nil, nil, "", newTestLicenseManager(license.TierFree), zap.NewNop())
+	if stats, _ := svc.GetLinkStats(context.Background(), uuid.New(), defaultWS, dr); stats.TotalClicks != 2 {
+		t.Errorf("default workspace should read from ClickHouse, got %d clicks", stats.TotalClicks)
+	}
+	if stats, _ := svc.GetLinkStats(context.Background(), uuid.New(), pgWS, dr); stats.TotalClicks != 1 {
+		t.Errorf("postgres workspace should read from PostgreSQL, got %d clicks", stats.TotalClicks)
+	}
+
+	// A failing ClickHouse query falls back to PostgreSQL
+	ch.err = errors.New("clickhouse: connection refused")
+	stats, err := svc.GetLinkStats(context.Background(), uuid.New(), chWS, dr)
+	if err != nil {
+		t.Fatalf("expected fallback to PostgreSQL, got %v", err)
+	}
+	if stats.TotalClicks != 1 {
+		t.Errorf("expected PostgreSQL stats after fallback, got %d clicks", stats.TotalClicks)
+	}
+}
+
 func TestGetTimeSeries(t *testing.T) {
 	now := time.Now().UTC()
 	repo := &mockAnalyticsRepo{
@@ -87,10 +169,10 @@ func TestGetTimeSeries(t *testing.T) {
 		},
 	}
 
-	svc := NewAnalyticsService(repo, nil, newTestLicenseManager(license.TierFree), zap.NewNop())
+	svc := NewAnalyticsService(AnalyticsBackends{Postgres: repo}, nil, nil, nil, nil, "", newTestLicenseManager(license.TierFree), zap.NewNop())
 
-	dr := models.DateRangeFromPreset("7d")
-	points, err := svc.GetTimeSeries(context.Background(), uuid.New(), models.IntervalDay, dr)
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
+	points, err := svc.GetTimeSeries(context.Background(), uuid.New(), uuid.New(), models.IntervalDay, dr)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -105,10 +187,10 @@ func TestAdvancedAnalyticsGated(t *testing.T) {
 	}
 
 	// Free tier should not have advanced analytics
-	svc := NewAnalyticsService(repo, nil, newTestLicenseManager(license.TierFree), zap.NewNop())
-	dr := models.DateRangeFromPreset("7d")
+	svc := NewAnalyticsService(AnalyticsBackends{Postgres: repo}, nil, nil, nil, nil, "", newTestLicenseManager(license.TierFree), zap.NewNop())
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
 
-	_, err := svc.GetTopReferrers(context.Background(), uuid.New(), dr, 10)
+	_, err := svc.GetTopReferrers(context.Background(), uuid.New(), uuid.New(), dr, 10)
 	if err == nil {
 		t.Fatal("expected payment required error for free tier")
 	}
@@ -119,13 +201,39 @@ func TestAdvancedAnalyticsGated(t *testing.T) {
 	}
 }
 
+func TestClickHeatmapGated(t *testing.T) {
+	svc := NewAnalyticsService(AnalyticsBackends{Postgres: &mockAnalyticsRepo{heatmap: &models.ClickHeatmap{}}}, nil, nil, nil, nil, "", newTestLicenseManager(license.TierFree), zap.NewNop())
+
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
+	_, err := svc.GetClickHeatmap(context.Background(), uuid.New(), uuid.New(), dr)
+	appErr, ok := err.(*httputil.AppError)
+	if !ok || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Errorf("expected PAYMENT_REQUIRED error, got: %v", err)
+	}
+}
+
+func TestClickHeatmapAdd(t *testing.T) {
+	var h models.ClickHeatmap
+	h.Add(1, 9, 5)  // Monday 09:00
+	h.Add(7, 23, 2) // Sunday 23:00
+	h.Add(0, 5, 100)
+	h.Add(3, 24, 100)
+
+	if h.Days[0][9] != 5 || h.Days[6][23] != 2 {
+		t.Errorf("unexpected cells: mon09=%d sun23=%d", h.Days[0][9], h.Days[6][23])
+	}
+	if h.Total != 7 {
+		t.Errorf("expected total 7, got %d", h.Total)
+	}
+}
+
 func TestExportDataGated(t *testing.T) {
 	repo := &mockAnalyticsRepo{}
 
-	svc := NewAnalyticsService(repo, nil, newTestLicenseManager(license.TierFree), zap.NewNop())
-	dr := models.DateRangeFromPreset("7d")
+	svc := NewAnalyticsService(AnalyticsBackends{Postgres: repo}, nil, nil, nil, nil, "", newTestLicenseManager(license.TierFree), zap.NewNop())
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
 
-	_, _, err := svc.ExportLinkData(context.Background(), uuid.New(), dr, models.ExportJSON)
+	_, _, err := svc.ExportLinkData(context.Background(), uuid.New(), uuid.New(), dr, models.ExportJSON)
 	if err == nil {
 		t.Fatal("expected payment required error for free tier export")
 	}
@@ -155,3 +263,34 @@ func TestDateRangeClampToRetention(t *testing.T) {
 		t.Error("unlimited retention should not clamp")
 	}
 }
+
+func TestWorkspaceFilter_ResolvesTag(t *testing.T) {
+	tagged := []uuid.UUID{uuid.New(), uuid.New()}
+	emptyTag := uuid.New()
+	linkRepo := &mockLinkRepo{
+		listIDsByTagFn: func(_ context.Context, _, tagID uuid.UUID) ([]uuid.UUID, error) {
+			if tagID == emptyTag {
+				return nil, nil
+			}
+			return tagged, nil
+		},
+	}
+	svc := &analyticsService{linkRepo: linkRepo}
+	wsID := uuid.New()
+
+	filter, ok, err := svc.workspaceFilter(context.Background(), wsID, nil)
+	if err != nil || !ok || filter.LinkIDs != nil {
+		t.Errorf("no tag: got (%v, %v, %v), want unfiltered", filter, ok, err)
+	}
+
+	tagID := uuid.New()
+	filter, ok, err = svc.workspaceFilter(context.Background(), wsID, &tagID)
+	if err != nil || !ok || len(filter.LinkIDs) != 2 {
+		t.Errorf("tag: got (%v, %v, %v), want 2 links", filter, ok, err)
+	}
+
+	_, ok, err = svc.workspaceFilter(context.Background(), wsID, &emptyTag)
+	if err != nil || ok {
+		t.Errorf("empty tag: got ok=%v err=%v, want ok=false", ok, err)
+	}
+}
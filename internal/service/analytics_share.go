@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+var errShareSecretUnset = errors.New("analytics share secret is not configured")
+
+// shareTokenContext separates share-token signatures from other uses of the
+// same secret.
+const shareTokenContext = "analytics_share."
+
+// shareClaims are the signed contents of a share token. The expiry is
+// carried in the token so expired tokens are rejected without a lookup;
+// revocation and the date range live in the database row.
+type shareClaims struct {
+	ShareID uuid.UUID `json:"sid"`
+	LinkID  uuid.UUID `json:"lid"`
+	Expires int64     `json:"exp"`
+}
+
+func (s *analyticsService) CreateShareToken(ctx context.Context, linkID, workspaceID uuid.UUID, ttl time.Duration, dr models.DateRange) (*models.CreateAnalyticsShareResponse, error) {
+	if s.shareSecret == "" {
+		return nil, httputil.Wrap(errShareSecretUnset, "failed to create share token")
+	}
+	if ttl <= 0 || ttl > models.MaxAnalyticsShareTTL {
+		return nil, httputil.Validation("ttl_hours", "share links must expire within 90 days")
+	}
+	if !dr.End.After(dr.Start) {
+		return nil, httputil.Validation("end", "end must be after start")
+	}
+
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("link")
+	}
+
+	share, err := s.shareRepo.Create(ctx, sqlc.CreateAnalyticsShareTokenParams{
+		LinkID:      linkID,
+		WorkspaceID: workspaceID,
+		RangeStart:  pgtype.Timestamptz{Time: dr.Start, Valid: true},
+		RangeEnd:    pgtype.Timestamptz{Time: dr.End, Valid: true},
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.signShareToken(shareClaims{
+		ShareID: share.ID,
+		LinkID:  share.LinkID,
+		Expires: share.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CreateAnalyticsShareResponse{Share: share, Token: token}, nil
+}
+
+func (s *analyticsService) ListShareTokens(ctx context.Context, linkID, workspaceID uuid.UUID) ([]*models.AnalyticsShare, error) {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("link")
+	}
+	return s.shareRepo.ListForLink(ctx, linkID)
+}
+
+func (s *analyticsService) RevokeShareToken(ctx context.Context, shareID, workspaceID uuid.UUID) error {
+	share, err := s.shareRepo.GetByID(ctx, shareID)
+	if err != nil {
+		return err
+	}
+	if share.WorkspaceID != workspaceID {
+		return httputil.NotFound("analytics_share")
+	}
+	return s.shareRepo.Revoke(ctx, shareID)
+}
+
+// GetSharedLinkAnalytics returns the stats a share token grants access to.
+// Malformed, expired and revoked tokens all look like a missing share.
+func (s *analyticsService) GetSharedLinkAnalytics(ctx context.Context, token string) (*models.SharedLinkAnalytics, error) {
+	claims, ok := s.verifyShareToken(token)
+	if !ok || time.Now().Unix() >= claims.Expires {
+		return nil, httputil.NotFound("analytics_share")
+	}
+
+	share, err := s.shareRepo.GetByID(ctx, claims.ShareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.LinkID != claims.LinkID || !share.IsUsable(time.Now()) {
+		return nil, httputil.NotFound("analytics_share")
+	}
+
+	link, err := s.linkRepo.GetByID(ctx, share.LinkID)
+	if err != nil {
+		return nil, err
+	}
+
+	dr := share.DateRange()
+	dr.Location = s.workspaceLocation(ctx, share.WorkspaceID)
+	dr = s.clampDateRange(dr)
+	report, err := s.getLinkReport(ctx, share.LinkID, share.WorkspaceID, dr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SharedLinkAnalytics{
+		ShortCode:  link.ShortCode,
+		Title:      link.Title,
+		RangeStart: dr.Start,
+		RangeEnd:   dr.End,
+		ExpiresAt:  share.ExpiresAt,
+		Stats:      report.stats,
+		TimeSeries: report.timeSeries,
+	}, nil
+}
+
+// signShareToken encodes claims as base64url JSON followed by a dot and the
+// HMAC of that payload.
+func (s *analyticsService) signShareToken(claims shareClaims) (string, error) {
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return "", httputil.Wrap(err, "failed to encode share token")
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + crypto.SignHMAC(s.shareSecret, []byte(shareTokenContext+payload)), nil
+}
+
+func (s *analyticsService) verifyShareToken(token string) (shareClaims, bool) {
+	var claims shareClaims
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || s.shareSecret == "" || !crypto.VerifyHMAC(s.shareSecret, []byte(shareTokenContext+payload), signature) {
+		return claims, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || json.Unmarshal(data, &claims) != nil {
+		return claims, false
+	}
+	return claims, true
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// mockShareRepo is an in-memory AnalyticsShareRepository.
+type mockShareRepo struct {
+	shares map[uuid.UUID]*models.AnalyticsShare
+}
+
+func (m *mockShareRepo) Create(_ context.Context, p sqlc.CreateAnalyticsShareTokenParams) (*models.AnalyticsShare, error) {
+	share := &models.AnalyticsShare{
+		ID:          uuid.New(),
+		LinkID:      p.LinkID,
+		WorkspaceID: p.WorkspaceID,
+		RangeStart:  p.RangeStart.Time,
+		RangeEnd:    p.RangeEnd.Time,
+		ExpiresAt:   p.ExpiresAt.Time,
+		CreatedAt:   time.Now(),
+	}
+	m.shares[share.ID] = share
+	return share, nil
+}
+func (m *mockShareRepo) GetByID(_ context.Context, id uuid.UUID) (*models.AnalyticsShare, error) {
+	if share, ok := m.shares[id]; ok {
+		return share, nil
+	}
+	return nil, httputil.NotFound("analytics_share")
+}
+func (m *mockShareRepo) ListForLink(_ context.Context, _ uuid.UUID) ([]*models.AnalyticsShare, error) {
+	return nil, nil
+}
+func (m *mockShareRepo) Revoke(_ context.Context, id uuid.UUID) error {
+	now := time.Now()
+	m.shares[id].RevokedAt = &now
+	return nil
+}
+
+func newShareTestService(link *models.Link) (AnalyticsService, *mockShareRepo) {
+	shares := &mockShareRepo{shares: map[uuid.UUID]*models.AnalyticsShare{}}
+	linkRepo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+			if id != link.ID {
+				return nil, httputil.NotFound("link")
+			}
+			return link, nil
+		},
+	}
+	repo := &mockAnalyticsRepo{
+		linkStats:  &models.LinkAnalytics{TotalClicks: 42},
+		timeSeries: []models.TimeSeriesPoint{{Clicks: 42}},
+	}
+	wsRepo := &mockWorkspaceRepo{workspaces: map[uuid.UUID]*models.Workspace{
+		link.WorkspaceID: {ID: link.WorkspaceID, Settings: json.RawMessage(`{"timezone":"Asia/Tokyo"}`)},
+	}}
+	svc := NewAnalyticsService(AnalyticsBackends{Postgres: repo}, wsRepo, nil, linkRepo, shares, "test-secret", newTestLicenseManager(license.TierFree), zap.NewNop())
+	return svc, shares
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, httputil.ErrNotFound)
+}
+
+func TestShareToken_RoundTrip(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "abc123")
+	svc, _ := newShareTestService(link)
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
+
+	created, err := svc.CreateShareToken(context.Background(), link.ID, link.WorkspaceID, time.Hour, dr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shared, err := svc.GetSharedLinkAnalytics(context.Background(), created.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shared.ShortCode != "abc123" || shared.Stats.TotalClicks != 42 || len(shared.TimeSeries) != 1 {
+		t.Errorf("shared analytics = %+v", shared)
+	}
+	if !shared.RangeEnd.Equal(dr.End) {
+		t.Errorf("range end = %v, want %v", shared.RangeEnd, dr.End)
+	}
+	repo := svc.(*analyticsService).backends.Postgres.(*mockAnalyticsRepo)
+	if tz := repo.timeSeriesRange.TimeZone(); tz != "Asia/Tokyo" {
+		t.Errorf("time series bucketed in %s, want the workspace's Asia/Tokyo", tz)
+	}
+}
+
+func TestShareToken_Rejected(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "abc123")
+	svc, shares := newShareTestService(link)
+	ctx := context.Background()
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
+
+	created, err := svc.CreateShareToken(ctx, link.ID, link.WorkspaceID, time.Hour, dr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, sig, _ := strings.Cut(created.Token, ".")
+
+	other, _ := newShareTestService(link)
+	otherToken, _ := other.CreateShareToken(ctx, link.ID, link.WorkspaceID, time.Hour, dr)
+
+	for name, token := range map[string]string{
+		"empty":            "",
+		"no signature":     payload,
+		"bad signature":    payload + "." + strings.Repeat("0", len(sig)),
+		"tampered payload": "e30." + sig,
+		"unknown share":    otherToken.Token,
+	} {
+		if _, err := svc.GetSharedLinkAnalytics(ctx, token); !isNotFound(err) {
+			t.Errorf("%s: expected not found, got %v", name, err)
+		}
+	}
+
+	if err := svc.RevokeShareToken(ctx, created.Share.ID, link.WorkspaceID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := svc.GetSharedLinkAnalytics(ctx, created.Token); !isNotFound(err) {
+		t.Errorf("revoked: expected not found, got %v", err)
+	}
+
+	expired, _ := svc.CreateShareToken(ctx, link.ID, link.WorkspaceID, time.Hour, dr)
+	shares.shares[expired.Share.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := svc.GetSharedLinkAnalytics(ctx, expired.Token); !isNotFound(err) {
+		t.Errorf("expired: expected not found, got %v", err)
+	}
+}
+
+func TestCreateShareToken_Validation(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "abc123")
+	svc, _ := newShareTestService(link)
+	ctx := context.Background()
+	dr, _ := models.DateRangeFromPreset("7d", time.UTC)
+
+	if _, err := svc.CreateShareToken(ctx, link.ID, link.WorkspaceID, models.MaxAnalyticsShareTTL+time.Hour, dr); err == nil {
+		t.Error("expected error for TTL over the maximum")
+	}
+	if _, err := svc.CreateShareToken(ctx, link.ID, link.WorkspaceID, time.Hour, models.DateRange{Start: dr.End, End: dr.Start}); err == nil {
+		t.Error("expected error for inverted date range")
+	}
+	if _, err := svc.CreateShareToken(ctx, link.ID, uuid.New(), time.Hour, dr); !isNotFound(err) {
+		t.Errorf("expected not found for another workspace's link, got %v", err)
+	}
+	if err := svc.RevokeShareToken(ctx, uuid.New(), link.WorkspaceID); !isNotFound(err) {
+		t.Errorf("expected not found revoking unknown share, got %v", err)
+	}
+}
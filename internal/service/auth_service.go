@@ -40,6 +40,7 @@ type authService struct {
 	userRepo     repository.UserRepository
 	sessionRepo  repository.SessionRepository
 	resetRepo    repository.PasswordResetRepository
+	wsRepo       repository.WorkspaceRepository
 	tokenMaker   paseto.Maker
 	pool         *pgxpool.Pool
 	redis        *redis.Client
@@ -51,6 +52,7 @@ func NewAuthService(
 	userRepo repository.UserRepository,
 	sessionRepo repository.SessionRepository,
 	resetRepo repository.PasswordResetRepository,
+	wsRepo repository.WorkspaceRepository,
 	tokenMaker paseto.Maker,
 	pool *pgxpool.Pool,
 	redisClient *redis.Client,
@@ -61,6 +63,7 @@ func NewAuthService(
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
 		resetRepo:   resetRepo,
+		wsRepo:      wsRepo,
 		tokenMaker:  tokenMaker,
 		pool:        pool,
 		redis:       redisClient,
@@ -181,6 +184,10 @@ func (s *authService) Login(ctx context.Context, input models.LoginInput, ip, us
 		return nil, httputil.Unauthorized("invalid email or password")
 	}
 
+	s.rehashPasswordIfNeeded(ctx, user.ID, user.PasswordHash, input.Password)
+
+	accessExpiry, refreshExpiry := s.effectiveTokenExpiry(ctx, user.ID)
+
 	refreshToken, refreshTokenHash, err := generateRefreshToken()
 	if err != nil {
 		return nil, err
@@ -192,7 +199,7 @@ func (s *authService) Login(ctx context.Context, input models.LoginInput, ip, us
 		IpAddress:        ip,
 		UserAgent:        pgtype.Text{String: userAgent, Valid: userAgent != ""},
 		DeviceName:       pgtype.Text{},
-		ExpiresAt:        pgtype.Timestamptz{Time: time.Now().Add(s.cfg.Auth.RefreshTokenExpiry), Valid: true},
+		ExpiresAt:        pgtype.Timestamptz{Time: time.Now().Add(refreshExpiry), Valid: true},
 	})
 	if err != nil {
 		return nil, err
@@ -202,7 +209,7 @@ func (s *authService) Login(ctx context.Context, input models.LoginInput, ip, us
 		user.ID,
 		user.Email,
 		session.ID,
-		s.cfg.Auth.AccessTokenExpiry,
+		accessExpiry,
 	)
 	if err != nil {
 		return nil, httputil.Wrap(err, "failed to create access token")
@@ -215,8 +222,30 @@ func (s *authService) Login(ctx context.Context, input models.LoginInput, ip, us
 	}, nil
 }
 
+// rehashPasswordIfNeeded upgrades a stored hash that uses a legacy algorithm
+// or outdated cost parameters. Failures are logged and never block login.
+func (s *authService) rehashPasswordIfNeeded(ctx context.Context, userID uuid.UUID, storedHash, password string) {
+	if !crypto.NeedsRehash(storedHash) {
+		return
+	}
+
+	newHash, err := crypto.HashPassword(password)
+	if err != nil {
+		s.logger.Warn("failed to rehash password", zap.String("user_id", userID.String()), zap.Error(err))
+		return
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, newHash); err != nil {
+		s.logger.Warn("failed to store rehashed password", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+}
+
 func (s *authService) Logout(ctx context.Context, sessionID uuid.UUID) error {
-	return s.sessionRepo.Revoke(ctx, sessionID)
+	if err := s.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+	s.invalidateSessions(ctx, sessionID)
+	return nil
 }
 
 func (s *authService) RefreshToken(ctx context.Context, refreshToken, ip, userAgent string) (*models.AuthResponse, error) {
@@ -234,12 +263,15 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 	if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
 		return nil, err
 	}
+	s.invalidateSessions(ctx, session.ID)
 
 	user, err := s.userRepo.GetByID(ctx, session.UserID)
 	if err != nil {
 		return nil, err
 	}
 
+	accessExpiry, refreshExpiry := s.effectiveTokenExpiry(ctx, user.ID)
+
 	// Create new session with new refresh token
 	newRefreshToken, newRefreshTokenHash, err := generateRefreshToken()
 	if err != nil {
@@ -252,7 +284,7 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 		IpAddress:        ip,
 		UserAgent:        pgtype.Text{String: userAgent, Valid: userAgent != ""},
 		DeviceName:       pgtype.Text{},
-		ExpiresAt:        pgtype.Timestamptz{Time: time.Now().Add(s.cfg.Auth.RefreshTokenExpiry), Valid: true},
+		ExpiresAt:        pgtype.Timestamptz{Time: time.Now().Add(refreshExpiry), Valid: true},
 	})
 	if err != nil {
 		return nil, err
@@ -262,7 +294,7 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 		user.ID,
 		user.Email,
 		newSession.ID,
-		s.cfg.Auth.AccessTokenExpiry,
+		accessExpiry,
 	)
 	if err != nil {
 		return nil, httputil.Wrap(err, "failed to create access token")
@@ -275,6 +307,41 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken, ip, userAg
 	}, nil
 }
 
+// effectiveTokenExpiry returns the token lifetimes for a new session of
+// userID. Tokens aren't tied to a workspace, so the strictest security policy
+// among the user's workspaces applies wherever it is shorter than the global
+// config. If the workspaces can't be loaded the global config is used.
+func (s *authService) effectiveTokenExpiry(ctx context.Context, userID uuid.UUID) (access, refresh time.Duration) {
+	access, refresh = s.cfg.Auth.AccessTokenExpiry, s.cfg.Auth.RefreshTokenExpiry
+	if s.wsRepo == nil {
+		return access, refresh
+	}
+
+	workspaces, err := s.wsRepo.ListForUser(ctx, userID)
+	if err != nil {
+		s.logger.Warn("failed to load workspace security policies",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return access, refresh
+	}
+
+	for _, ws := range workspaces {
+		policy := ws.ParsedSettings().SecurityPolicy
+		if policy == nil {
+			continue
+		}
+		policyAccess, policyRefresh := policy.Expiries()
+		if policyAccess > 0 && policyAccess < access {
+			access = policyAccess
+		}
+		if policyRefresh > 0 && policyRefresh < refresh {
+			refresh = policyRefresh
+		}
+	}
+	return access, refresh
+}
+
 func (s *authService) GetCurrentUser(ctx context.Context, userID uuid.UUID) (*models.UserResponse, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -339,13 +406,31 @@ func (s *authService) ResetPassword(ctx context.Context, input models.ResetPassw
 		return err
 	}
 
+	sessions, err := s.sessionRepo.ListByUserID(ctx, reset.UserID)
+	if err != nil {
+		return err
+	}
 	if err := s.sessionRepo.RevokeAllForUser(ctx, reset.UserID); err != nil {
 		return err
 	}
+	sessionIDs := make([]uuid.UUID, len(sessions))
+	for i, session := range sessions {
+		sessionIDs[i] = session.ID
+	}
+	s.invalidateSessions(ctx, sessionIDs...)
 
 	return nil
 }
 
+// invalidateSessions clears cached session status after a revocation. The
+// revocation itself is already persisted, so a cache failure only delays
+// rejection of outstanding access tokens by the cache TTL.
+func (s *authService) invalidateSessions(ctx context.Context, sessionIDs ...uuid.UUID) {
+	if err := invalidateSessionCache(ctx, s.redis, sessionIDs...); err != nil {
+		s.logger.Warn("failed to invalidate session cache", zap.Error(err))
+	}
+}
+
 func (s *authService) VerifyEmail(ctx context.Context, input models.VerifyEmailInput) error {
 	tokenHash := hashToken(input.Token)
 	key := fmt.Sprintf("email_verify:%s", tokenHash)
@@ -388,8 +473,7 @@ func hashToken(token string) string {
 }
 
 func generateWorkspaceSlug(name string) (string, error) {
-	slug := strings.ToLower(strings.TrimSpace(name))
-	slug = strings.ReplaceAll(slug, " ", "-")
+	slug := slugify(name)
 	// Append short random suffix to avoid collisions
 	suffix := make([]byte, 4)
 	if _, err := rand.Read(suffix); err != nil {
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+type policyWorkspaceRepo struct {
+	mockWorkspaceRepo
+	forUser []*models.Workspace
+	err     error
+}
+
+func (m *policyWorkspaceRepo) ListForUser(_ context.Context, _ uuid.UUID) ([]*models.Workspace, error) {
+	return m.forUser, m.err
+}
+
+func TestEffectiveTokenExpiry(t *testing.T) {
+	cfg := &config.Config{Auth: config.AuthConfig{
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	}}
+	withPolicy := func(settings string) *models.Workspace {
+		return &models.Workspace{ID: uuid.New(), Settings: json.RawMessage(settings)}
+	}
+
+	tests := []struct {
+		name        string
+		workspaces  []*models.Workspace
+		err         error
+		wantAccess  time.Duration
+		wantRefresh time.Duration
+	}{
+		{"no workspaces", nil, nil, 15 * time.Minute, 7 * 24 * time.Hour},
+		{"no policy", []*models.Workspace{withPolicy(`{}`)}, nil, 15 * time.Minute, 7 * 24 * time.Hour},
+		{
+			"strictest across workspaces",
+			[]*models.Workspace{
+				withPolicy(`{"security_policy":{"access_token_expiry":"10m","refresh_token_expiry":"24h"}}`),
+				withPolicy(`{"security_policy":{"access_token_expiry":"5m"}}`),
+				withPolicy(`{"security_policy":{"refresh_token_expiry":"8h"}}`),
+			},
+			nil, 5 * time.Minute, 8 * time.Hour,
+		},
+		{
+			"policy never extends",
+			[]*models.Workspace{withPolicy(`{"security_policy":{"access_token_expiry":"1h","refresh_token_expiry":"720h"}}`)},
+			nil, 15 * time.Minute, 7 * 24 * time.Hour,
+		},
+		{"lookup error", nil, errors.New("db down"), 15 * time.Minute, 7 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &authService{
+				wsRepo: &policyWorkspaceRepo{forUser: tt.workspaces, err: tt.err},
+				cfg:    cfg,
+				logger: zap.NewNop(),
+			}
+			access, refresh := svc.effectiveTokenExpiry(context.Background(), uuid.New())
+			if access != tt.wantAccess || refresh != tt.wantRefresh {
+				t.Errorf("effectiveTokenExpiry() = %v, %v; want %v, %v", access, refresh, tt.wantAccess, tt.wantRefresh)
+			}
+		})
+	}
+}
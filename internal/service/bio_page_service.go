@@ -2,17 +2,32 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/link-rift/link-rift/internal/license"
 	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/ogimage"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/crypto"
 	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/sanitize"
+	"github.com/link-rift/link-rift/pkg/storage"
+	"github.com/link-rift/link-rift/pkg/validator"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +43,9 @@ type BioPageService interface {
 	PublishBioPage(ctx context.Context, id, workspaceID uuid.UUID) (*models.BioPage, error)
 	UnpublishBioPage(ctx context.Context, id, workspaceID uuid.UUID) (*models.BioPage, error)
 
+	// Open Graph image
+	GenerateOGImage(ctx context.Context, id, workspaceID uuid.UUID) (*models.BioPage, error)
+
 	// Links
 	AddLink(ctx context.Context, pageID, workspaceID uuid.UUID, input models.CreateBioPageLinkInput) (*models.BioPageLink, error)
 	UpdateLink(ctx context.Context, pageID, linkID, workspaceID uuid.UUID, input models.UpdateBioPageLinkInput) (*models.BioPageLink, error)
@@ -37,17 +55,34 @@ type BioPageService interface {
 	TrackLinkClick(ctx context.Context, linkID uuid.UUID) error
 
 	// Themes
-	ListThemes() []models.BioPageTheme
-	GetTheme(themeID string) (*models.BioPageTheme, error)
+	ListThemes(ctx context.Context, workspaceID uuid.UUID) ([]models.BioPageTheme, error)
+	GetTheme(ctx context.Context, workspaceID uuid.UUID, themeID string) (*models.BioPageTheme, error)
+	CreateTheme(ctx context.Context, workspaceID uuid.UUID, input models.CreateBioThemeInput) (*models.BioPageTheme, error)
+	UpdateTheme(ctx context.Context, themeID, workspaceID uuid.UUID, input models.UpdateBioThemeInput) (*models.BioPageTheme, error)
+	DeleteTheme(ctx context.Context, themeID, workspaceID uuid.UUID) error
+
+	// Email capture
+	SubmitEmail(ctx context.Context, slug string, input models.BioEmailSubmissionInput, clientIP string) error
+	ListSubmissions(ctx context.Context, pageID, workspaceID uuid.UUID, limit, offset int) ([]*models.BioPageSubmission, int64, error)
+	ExportSubmissions(ctx context.Context, pageID, workspaceID uuid.UUID, w io.Writer) error
 
 	// Public
 	GetPublicPage(ctx context.Context, slug string) (*models.PublicBioPageResponse, error)
 }
 
+// OGImageRenderer renders the Open Graph card for a bio page as a PNG.
+type OGImageRenderer interface {
+	Render(ctx context.Context, card ogimage.Card) ([]byte, error)
+}
+
 type bioPageService struct {
 	bioPageRepo repository.BioPageRepository
 	licManager  *license.Manager
 	events      EventPublisher
+	ogRenderer  OGImageRenderer
+	store       storage.ObjectStorage
+	redis       *redis.Client
+	ipSecret    string
 	logger      *zap.Logger
 }
 
@@ -55,18 +90,52 @@ func NewBioPageService(
 	bioPageRepo repository.BioPageRepository,
 	licManager *license.Manager,
 	events EventPublisher,
+	ogRenderer OGImageRenderer,
+	store storage.ObjectStorage,
+	redisClient *redis.Client,
+	ipSecret string,
 	logger *zap.Logger,
 ) BioPageService {
 	return &bioPageService{
 		bioPageRepo: bioPageRepo,
 		licManager:  licManager,
 		events:      events,
+		ogRenderer:  ogRenderer,
+		store:       store,
+		redis:       redisClient,
+		ipSecret:    ipSecret,
 		logger:      logger,
 	}
 }
 
+const (
+	// submissionRateLimit caps email capture submissions per visitor IP and
+	// page within submissionRateWindow.
+	submissionRateLimit  = 5
+	submissionRateWindow = time.Hour
+
+	// submissionIPContext separates submitter IP hashes from other uses of
+	// the server secret.
+	submissionIPContext = "bio_submission_ip."
+
+	maxSubmissionNameLength = 100
+
+	// ogImageTimeout bounds background OG image generation, including the
+	// avatar fetch and the upload.
+	ogImageTimeout = 30 * time.Second
+)
+
 var slugRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*[a-z0-9]$|^[a-z0-9]$`)
 
+var (
+	themeColorRegex     = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|rgba?\([0-9.,%\s]+\)|[a-zA-Z]+)$`)
+	themeFontRegex      = regexp.MustCompile(`^[a-zA-Z0-9 ,'"-]{1,200}$`)
+	themeDirectionRegex = regexp.MustCompile(`^(to (top|bottom)( (left|right))?|to (left|right)|[0-9]{1,3}deg)$`)
+)
+
+// themeButtonStyles are the button shapes the bio page renderer supports.
+var themeButtonStyles = map[string]bool{"rounded": true, "pill": true, "square": true}
+
 func (s *bioPageService) CreateBioPage(ctx context.Context, workspaceID uuid.UUID, input models.CreateBioPageInput) (*models.BioPage, error) {
 	// Check license
 	if !s.licManager.HasFeature(license.FeatureBioPages) {
@@ -93,7 +162,10 @@ func (s *bioPageService) CreateBioPage(ctx context.Context, workspaceID uuid.UUI
 		params.AvatarUrl = pgtype.Text{String: *input.AvatarURL, Valid: true}
 	}
 	if input.ThemeID != nil {
-		themeUUID := models.ThemeIDToUUID(*input.ThemeID)
+		themeUUID, err := s.resolveThemeID(ctx, workspaceID, *input.ThemeID)
+		if err != nil {
+			return nil, err
+		}
 		params.ThemeID = pgtype.UUID{Bytes: themeUUID, Valid: true}
 	}
 	if input.MetaTitle != nil {
@@ -116,6 +188,10 @@ func (s *bioPageService) CreateBioPage(ctx context.Context, workspaceID uuid.UUI
 		s.logger.Warn("failed to publish biopage.created event", zap.Error(err))
 	}
 
+	if ogImageReplaceable(page) {
+		s.scheduleOGImage(page)
+	}
+
 	return page, nil
 }
 
@@ -180,7 +256,10 @@ func (s *bioPageService) UpdateBioPage(ctx context.Context, id, workspaceID uuid
 		params.AvatarUrl = pgtype.Text{String: *input.AvatarURL, Valid: true}
 	}
 	if input.ThemeID != nil {
-		themeUUID := models.ThemeIDToUUID(*input.ThemeID)
+		themeUUID, err := s.resolveThemeID(ctx, workspaceID, *input.ThemeID)
+		if err != nil {
+			return nil, err
+		}
 		params.ThemeID = pgtype.UUID{Bytes: themeUUID, Valid: true}
 	}
 	if input.CustomCSS != nil {
@@ -188,9 +267,9 @@ func (s *bioPageService) UpdateBioPage(ctx context.Context, id, workspaceID uuid
 		if !s.licManager.HasFeature(license.FeatureCustomCSS) {
 			return nil, httputil.PaymentRequiredWithDetails("custom_css", "enterprise")
 		}
-		sanitized, err := sanitizeCSS(*input.CustomCSS)
+		sanitized, _, err := sanitizeCSS(*input.CustomCSS)
 		if err != nil {
-			return nil, httputil.Validation("custom_css", err.Error())
+			return nil, err
 		}
 		params.CustomCss = pgtype.Text{String: sanitized, Valid: true}
 	}
@@ -214,6 +293,14 @@ func (s *bioPageService) UpdateBioPage(ctx context.Context, id, workspaceID uuid
 		s.logger.Warn("failed to publish biopage.updated event", zap.Error(err))
 	}
 
+	// Regenerate the OG card when its content changed or the custom image
+	// was cleared.
+	cardChanged := updated.Title != page.Title || stringFromPtr(updated.AvatarURL) != stringFromPtr(page.AvatarURL)
+	ogCleared := input.OgImageURL != nil && *input.OgImageURL == ""
+	if ogImageReplaceable(updated) && (cardChanged || ogCleared) {
+		s.scheduleOGImage(updated)
+	}
+
 	return updated, nil
 }
 
@@ -259,6 +346,79 @@ func (s *bioPageService) UnpublishBioPage(ctx context.Context, id, workspaceID u
 	})
 }
 
+// Open Graph image
+
+// ogImageKey is the storage key of a bio page's generated OG image.
+func ogImageKey(pageID uuid.UUID) string {
+	return fmt.Sprintf("bio-pages/%s/og.png", pageID)
+}
+
+// ogImageReplaceable reports whether page has no OG image of its own, so a
+// generated one may be set or replaced.
+func ogImageReplaceable(page *models.BioPage) bool {
+	if page.OgImageURL == nil || *page.OgImageURL == "" {
+		return true
+	}
+	return strings.Contains(*page.OgImageURL, ogImageKey(page.ID))
+}
+
+func (s *bioPageService) GenerateOGImage(ctx context.Context, id, workspaceID uuid.UUID) (*models.BioPage, error) {
+	page, err := s.bioPageRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if page.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("bio page does not belong to this workspace")
+	}
+	if s.ogRenderer == nil || s.store == nil {
+		return nil, httputil.Validation("og_image", "OG image generation is not available")
+	}
+
+	return s.refreshOGImage(ctx, page)
+}
+
+// scheduleOGImage generates page's OG image in the background so saving the
+// page does not wait on the avatar fetch and upload.
+func (s *bioPageService) scheduleOGImage(page *models.BioPage) {
+	if s.ogRenderer == nil || s.store == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ogImageTimeout)
+		defer cancel()
+		if _, err := s.refreshOGImage(ctx, page); err != nil {
+			s.logger.Warn("failed to generate bio page OG image",
+				zap.String("bio_page_id", page.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// refreshOGImage renders, uploads and saves page's OG image. The URL carries
+// a content hash so caches pick up a regenerated card.
+func (s *bioPageService) refreshOGImage(ctx context.Context, page *models.BioPage) (*models.BioPage, error) {
+	data, err := s.ogRenderer.Render(ctx, ogimage.Card{
+		Title:     page.Title,
+		AvatarURL: stringFromPtr(page.AvatarURL),
+	})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to render OG image")
+	}
+
+	url, err := s.store.Upload(ctx, s.store.WorkspaceKey(page.WorkspaceID, ogImageKey(page.ID)), data, "image/png")
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to store OG image")
+	}
+	sum := sha256.Sum256(data)
+	url += "?v=" + hex.EncodeToString(sum[:6])
+
+	return s.bioPageRepo.Update(ctx, sqlc.UpdateBioPageParams{
+		ID:         page.ID,
+		OgImageUrl: pgtype.Text{String: url, Valid: true},
+	})
+}
+
 // Links
 
 func (s *bioPageService) AddLink(ctx context.Context, pageID, workspaceID uuid.UUID, input models.CreateBioPageLinkInput) (*models.BioPageLink, error) {
@@ -271,6 +431,15 @@ func (s *bioPageService) AddLink(ctx context.Context, pageID, workspaceID uuid.U
 		return nil, httputil.Forbidden("bio page does not belong to this workspace")
 	}
 
+	// Check link count limit; hidden links count too
+	count, err := s.bioPageRepo.GetLinkCount(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.licManager.CheckLimit(license.LimitMaxBioLinks, count) {
+		return nil, httputil.PaymentRequired("bio link limit reached, upgrade your plan for more links")
+	}
+
 	// Get next position
 	maxPos, err := s.bioPageRepo.GetMaxLinkPosition(ctx, pageID)
 	if err != nil {
@@ -282,15 +451,37 @@ func (s *bioPageService) AddLink(ctx context.Context, pageID, workspaceID uuid.U
 		isVisible = *input.IsVisible
 	}
 
+	linkType := models.BioLinkTypeRegular
+	if input.LinkType != nil {
+		linkType = *input.LinkType
+	}
+	if !models.IsValidBioLinkType(linkType) {
+		return nil, httputil.Validation("link_type", "link_type must be regular, social or email_capture")
+	}
+	if err := s.checkLinkURL(linkType, input.URL); err != nil {
+		return nil, err
+	}
+	if linkType == models.BioLinkTypeEmailCapture {
+		// Capture blocks render a form instead of linking anywhere.
+		input.URL = ""
+	}
+
 	params := sqlc.CreateBioPageLinkParams{
 		BioPageID: pageID,
 		Title:     strings.TrimSpace(input.Title),
 		Url:       strings.TrimSpace(input.URL),
 		Position:  maxPos + 1,
 		IsVisible: isVisible,
+		LinkType:  linkType,
 	}
 
-	if input.Icon != nil {
+	if linkType == models.BioLinkTypeSocial {
+		icon, err := socialLinkIcon(params.Url)
+		if err != nil {
+			return nil, err
+		}
+		params.Icon = pgtype.Text{String: icon, Valid: true}
+	} else if input.Icon != nil {
 		params.Icon = pgtype.Text{String: *input.Icon, Valid: true}
 	}
 	if input.VisibleFrom != nil {
@@ -338,7 +529,37 @@ func (s *bioPageService) UpdateLink(ctx context.Context, pageID, linkID, workspa
 	if input.URL != nil {
 		params.Url = pgtype.Text{String: strings.TrimSpace(*input.URL), Valid: true}
 	}
-	if input.Icon != nil {
+	if input.LinkType != nil {
+		if !models.IsValidBioLinkType(*input.LinkType) {
+			return nil, httputil.Validation("link_type", "link_type must be regular, social or email_capture")
+		}
+		params.LinkType = pgtype.Text{String: *input.LinkType, Valid: true}
+	}
+
+	// Social links always carry their platform's icon, so it's re-derived
+	// whenever the link is social after this update.
+	linkType, linkURL := link.LinkType, link.URL
+	if params.LinkType.Valid {
+		linkType = params.LinkType.String
+	}
+	if params.Url.Valid {
+		linkURL = params.Url.String
+	}
+	if params.LinkType.Valid || params.Url.Valid {
+		if err := s.checkLinkURL(linkType, linkURL); err != nil {
+			return nil, err
+		}
+	}
+	if linkType == models.BioLinkTypeEmailCapture && linkURL != "" {
+		params.Url = pgtype.Text{String: "", Valid: true}
+	}
+	if linkType == models.BioLinkTypeSocial {
+		icon, err := socialLinkIcon(linkURL)
+		if err != nil {
+			return nil, err
+		}
+		params.Icon = pgtype.Text{String: icon, Valid: true}
+	} else if input.Icon != nil {
 		params.Icon = pgtype.Text{String: *input.Icon, Valid: true}
 	}
 	if input.IsVisible != nil {
@@ -422,20 +643,318 @@ func (s *bioPageService) TrackLinkClick(ctx context.Context, linkID uuid.UUID) e
 
 // Themes
 
-func (s *bioPageService) ListThemes() []models.BioPageTheme {
-	themes := make([]models.BioPageTheme, 0, len(models.PredefinedThemes))
-	for _, theme := range models.PredefinedThemes {
-		themes = append(themes, theme)
+// ListThemes returns the built-in themes followed by the workspace's custom
+// themes.
+func (s *bioPageService) ListThemes(ctx context.Context, workspaceID uuid.UUID) ([]models.BioPageTheme, error) {
+	ids := make([]string, 0, len(models.PredefinedThemes))
+	for id := range models.PredefinedThemes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	themes := make([]models.BioPageTheme, 0, len(ids))
+	for _, id := range ids {
+		themes = append(themes, models.PredefinedThemes[id])
+	}
+
+	custom, err := s.bioPageRepo.ListThemes(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, theme := range custom {
+		themes = append(themes, *theme)
 	}
-	return themes
+	return themes, nil
 }
 
-func (s *bioPageService) GetTheme(themeID string) (*models.BioPageTheme, error) {
-	theme, ok := models.PredefinedThemes[themeID]
-	if !ok {
+// GetTheme looks up a built-in theme by its string ID or UUID, or one of the
+// workspace's custom themes by UUID.
+func (s *bioPageService) GetTheme(ctx context.Context, workspaceID uuid.UUID, themeID string) (*models.BioPageTheme, error) {
+	if theme, ok := models.PredefinedThemes[themeID]; ok {
+		return &theme, nil
+	}
+
+	id, err := uuid.Parse(themeID)
+	if err != nil {
 		return nil, httputil.NotFound("theme")
 	}
-	return &theme, nil
+	if key := models.ThemeUUIDToID(id); key != "" {
+		theme := models.PredefinedThemes[key]
+		return &theme, nil
+	}
+	return s.getCustomTheme(ctx, id, workspaceID)
+}
+
+func (s *bioPageService) CreateTheme(ctx context.Context, workspaceID uuid.UUID, input models.CreateBioThemeInput) (*models.BioPageTheme, error) {
+	if !s.licManager.HasFeature(license.FeatureCustomBioThemes) {
+		return nil, httputil.PaymentRequiredWithDetails("custom_bio_themes", "pro")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, httputil.Validation("name", "name is required")
+	}
+	styles, err := encodeThemeStyles(input.Styles)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.CreateBioThemeParams{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Styles:      styles,
+	}
+	if input.Description != nil {
+		params.Description = pgtype.Text{String: strings.TrimSpace(*input.Description), Valid: true}
+	}
+
+	return s.bioPageRepo.CreateTheme(ctx, params)
+}
+
+func (s *bioPageService) UpdateTheme(ctx context.Context, themeID, workspaceID uuid.UUID, input models.UpdateBioThemeInput) (*models.BioPageTheme, error) {
+	if !s.licManager.HasFeature(license.FeatureCustomBioThemes) {
+		return nil, httputil.PaymentRequiredWithDetails("custom_bio_themes", "pro")
+	}
+
+	if _, err := s.getCustomTheme(ctx, themeID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateBioThemeParams{ID: themeID}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, httputil.Validation("name", "name cannot be empty")
+		}
+		params.Name = pgtype.Text{String: name, Valid: true}
+	}
+	if input.Description != nil {
+		params.Description = pgtype.Text{String: strings.TrimSpace(*input.Description), Valid: true}
+	}
+	if input.Styles != nil {
+		styles, err := encodeThemeStyles(*input.Styles)
+		if err != nil {
+			return nil, err
+		}
+		params.Styles = styles
+	}
+
+	return s.bioPageRepo.UpdateTheme(ctx, params)
+}
+
+// DeleteTheme deletes a custom theme. Pages using it fall back to the
+// default theme. Deleting doesn't need the license feature, so workspaces
+// that downgrade can still clean up.
+func (s *bioPageService) DeleteTheme(ctx context.Context, themeID, workspaceID uuid.UUID) error {
+	if _, err := s.getCustomTheme(ctx, themeID, workspaceID); err != nil {
+		return err
+	}
+	return s.bioPageRepo.DeleteTheme(ctx, themeID)
+}
+
+func (s *bioPageService) getCustomTheme(ctx context.Context, themeID, workspaceID uuid.UUID) (*models.BioPageTheme, error) {
+	theme, err := s.bioPageRepo.GetThemeByID(ctx, themeID)
+	if err != nil {
+		return nil, err
+	}
+	if theme.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("theme does not belong to this workspace")
+	}
+	return theme, nil
+}
+
+// resolveThemeID maps a page's theme_id input to the UUID stored on the page.
+// Built-in themes may be given by string ID or UUID; custom themes by UUID,
+// and only the workspace's own.
+func (s *bioPageService) resolveThemeID(ctx context.Context, workspaceID uuid.UUID, themeID string) (uuid.UUID, error) {
+	if _, ok := models.PredefinedThemes[themeID]; ok {
+		return models.ThemeIDToUUID(themeID), nil
+	}
+
+	id, err := uuid.Parse(themeID)
+	if err != nil {
+		return uuid.Nil, httputil.Validation("theme_id", "unknown theme: "+themeID)
+	}
+	if models.ThemeUUIDToID(id) != "" {
+		return id, nil
+	}
+	if _, err := s.getCustomTheme(ctx, id, workspaceID); err != nil {
+		if errors.Is(err, httputil.ErrNotFound) {
+			return uuid.Nil, httputil.Validation("theme_id", "unknown theme: "+themeID)
+		}
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// Email capture
+
+// SubmitEmail stores an email posted to one of a published page's capture
+// blocks. Honeypot submissions are accepted without being stored so bots
+// can't tell they were caught.
+func (s *bioPageService) SubmitEmail(ctx context.Context, slug string, input models.BioEmailSubmissionInput, clientIP string) error {
+	if !s.licManager.HasFeature(license.FeatureBioEmailCapture) {
+		return httputil.PaymentRequiredWithDetails("bio_email_capture", "pro")
+	}
+
+	page, err := s.bioPageRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if !page.IsPublished {
+		return httputil.NotFound("bio page")
+	}
+
+	blockID, err := uuid.Parse(input.BlockID)
+	if err != nil {
+		return httputil.Validation("block_id", "invalid block ID")
+	}
+	block, err := s.bioPageRepo.GetLinkByID(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if block.BioPageID != page.ID || block.LinkType != models.BioLinkTypeEmailCapture || !block.IsVisible ||
+		(block.VisibleFrom != nil && now.Before(*block.VisibleFrom)) ||
+		(block.VisibleUntil != nil && now.After(*block.VisibleUntil)) {
+		return httputil.NotFound("email capture block")
+	}
+
+	if input.Website != "" {
+		return nil
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if len(email) > 254 || !validator.IsValidEmail(email) {
+		return httputil.Validation("email", "invalid email address")
+	}
+	params := sqlc.CreateBioPageSubmissionParams{
+		BioPageID: page.ID,
+		BlockID:   pgtype.UUID{Bytes: block.ID, Valid: true},
+		Email:     email,
+	}
+	if input.Name != nil {
+		if name := strings.TrimSpace(*input.Name); name != "" {
+			if utf8.RuneCountInString(name) > maxSubmissionNameLength {
+				return httputil.Validation("name", "name must be at most 100 characters")
+			}
+			params.Name = pgtype.Text{String: name, Valid: true}
+		}
+	}
+
+	var ipHash string
+	if clientIP != "" {
+		ipHash = s.hashSubmissionIP(clientIP)
+		params.IpHash = pgtype.Text{String: ipHash, Valid: true}
+	}
+	if err := s.checkSubmissionRate(ctx, page.ID, ipHash); err != nil {
+		return err
+	}
+
+	return s.bioPageRepo.CreateSubmission(ctx, params)
+}
+
+// hashSubmissionIP returns the stored and rate-limited form of a submitter's
+// IP. It is keyed by the server secret, so the hashes can't be reversed by
+// hashing every IPv4 address.
+func (s *bioPageService) hashSubmissionIP(clientIP string) string {
+	return crypto.SignHMAC(s.ipSecret, []byte(submissionIPContext+clientIP))
+}
+
+// checkSubmissionRate counts a submission against the visitor's limit for a
+// page. It fails open when Redis is unavailable.
+func (s *bioPageService) checkSubmissionRate(ctx context.Context, pageID uuid.UUID, ipHash string) error {
+	if s.redis == nil || ipHash == "" {
+		return nil
+	}
+
+	key := fmt.Sprintf("bio_submit:%s:%s", pageID, ipHash)
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		s.logger.Warn("failed to check bio submission rate limit", zap.Error(err))
+		return nil
+	}
+	if count == 1 {
+		s.redis.Expire(ctx, key, submissionRateWindow)
+	}
+	if count > submissionRateLimit {
+		return httputil.RateLimited()
+	}
+	return nil
+}
+
+func (s *bioPageService) ListSubmissions(ctx context.Context, pageID, workspaceID uuid.UUID, limit, offset int) ([]*models.BioPageSubmission, int64, error) {
+	if err := s.checkPageOwnership(ctx, pageID, workspaceID); err != nil {
+		return nil, 0, err
+	}
+
+	subs, err := s.bioPageRepo.ListSubmissions(ctx, sqlc.ListBioPageSubmissionsParams{
+		BioPageID: pageID,
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.bioPageRepo.CountSubmissions(ctx, pageID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return subs, total, nil
+}
+
+// ExportSubmissions writes a page's submissions to w as CSV, oldest first.
+func (s *bioPageService) ExportSubmissions(ctx context.Context, pageID, workspaceID uuid.UUID, w io.Writer) error {
+	if err := s.checkPageOwnership(ctx, pageID, workspaceID); err != nil {
+		return err
+	}
+
+	subs, err := s.bioPageRepo.ListSubmissionsForExport(ctx, pageID)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(models.BioSubmissionCSVHeader); err != nil {
+		return httputil.Wrap(err, "failed to write export")
+	}
+	for _, sub := range subs {
+		if err := cw.Write(sub.CSVRecord()); err != nil {
+			return httputil.Wrap(err, "failed to write export")
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return httputil.Wrap(err, "failed to write export")
+	}
+	return nil
+}
+
+func (s *bioPageService) checkPageOwnership(ctx context.Context, pageID, workspaceID uuid.UUID) error {
+	page, err := s.bioPageRepo.GetByID(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	if page.WorkspaceID != workspaceID {
+		return httputil.Forbidden("bio page does not belong to this workspace")
+	}
+	return nil
+}
+
+// checkLinkURL checks that a block of the given type has a URL if it needs
+// one, and that the workspace may create email capture blocks.
+func (s *bioPageService) checkLinkURL(linkType, rawURL string) error {
+	if linkType == models.BioLinkTypeEmailCapture {
+		if !s.licManager.HasFeature(license.FeatureBioEmailCapture) {
+			return httputil.PaymentRequiredWithDetails("bio_email_capture", "pro")
+		}
+		return nil
+	}
+	if strings.TrimSpace(rawURL) == "" {
+		return httputil.Validation("url", "url is required")
+	}
+	return nil
 }
 
 // Public
@@ -457,11 +976,15 @@ func (s *bioPageService) GetPublicPage(ctx context.Context, slug string) (*model
 	}
 
 	now := time.Now()
+	emailCapture := s.licManager.HasFeature(license.FeatureBioEmailCapture)
 	publicLinks := make([]models.PublicBioLink, 0)
 	for _, link := range allLinks {
 		if !link.IsVisible {
 			continue
 		}
+		if link.LinkType == models.BioLinkTypeEmailCapture && !emailCapture {
+			continue
+		}
 		if link.VisibleFrom != nil && now.Before(*link.VisibleFrom) {
 			continue
 		}
@@ -469,10 +992,12 @@ func (s *bioPageService) GetPublicPage(ctx context.Context, slug string) (*model
 			continue
 		}
 		publicLinks = append(publicLinks, models.PublicBioLink{
-			ID:    link.ID,
-			Title: link.Title,
-			URL:   link.URL,
-			Icon:  link.Icon,
+			ID:       link.ID,
+			Title:    link.Title,
+			URL:      link.URL,
+			Icon:     link.Icon,
+			LinkType: link.LinkType,
+			Platform: link.Platform,
 		})
 	}
 
@@ -488,12 +1013,18 @@ func (s *bioPageService) GetPublicPage(ctx context.Context, slug string) (*model
 		Links:           publicLinks,
 	}
 
-	// Resolve theme
+	// Resolve theme: built-ins first, then the page's workspace's custom themes
 	if page.ThemeID != nil {
-		themeKey := models.ThemeUUIDToID(*page.ThemeID)
-		if themeKey != "" {
+		if themeKey := models.ThemeUUIDToID(*page.ThemeID); themeKey != "" {
 			theme := models.PredefinedThemes[themeKey]
 			resp.Theme = &theme
+		} else {
+			theme, err := s.getCustomTheme(ctx, *page.ThemeID, page.WorkspaceID)
+			if err == nil {
+				resp.Theme = theme
+			} else if !errors.Is(err, httputil.ErrNotFound) && !errors.Is(err, httputil.ErrForbidden) {
+				s.logger.Warn("failed to load custom bio theme", zap.String("theme_id", page.ThemeID.String()), zap.Error(err))
+			}
 		}
 	}
 
@@ -509,25 +1040,61 @@ func isValidSlug(slug string) bool {
 	return slugRegex.MatchString(slug)
 }
 
-// sanitizeCSS removes dangerous CSS patterns.
-func sanitizeCSS(css string) (string, error) {
-	lower := strings.ToLower(css)
+// encodeThemeStyles validates custom theme styles and encodes them for
+// storage. Values end up in inline styles on the public page, so only plain
+// colors, font names and gradient directions are accepted.
+func encodeThemeStyles(styles models.ThemeStyles) ([]byte, error) {
+	colors := map[string]string{
+		"styles.background_color":  styles.BackgroundColor,
+		"styles.text_color":        styles.TextColor,
+		"styles.button_color":      styles.ButtonColor,
+		"styles.button_text_color": styles.ButtonTextColor,
+	}
+	if styles.Gradient != nil {
+		colors["styles.gradient.from"] = styles.Gradient.From
+		colors["styles.gradient.to"] = styles.Gradient.To
+		if !themeDirectionRegex.MatchString(styles.Gradient.Direction) {
+			return nil, httputil.Validation("styles.gradient.direction", "direction must be like \"to bottom right\" or \"45deg\"")
+		}
+	}
+	for field, color := range colors {
+		if !themeColorRegex.MatchString(color) {
+			return nil, httputil.Validation(field, "must be a hex, rgb(a) or named color")
+		}
+	}
+	if !themeButtonStyles[styles.ButtonStyle] {
+		return nil, httputil.Validation("styles.button_style", "button_style must be one of rounded, pill, square")
+	}
+	if !themeFontRegex.MatchString(styles.FontFamily) {
+		return nil, httputil.Validation("styles.font_family", "font_family must be a list of font names")
+	}
 
-	dangerousPatterns := []string{
-		"javascript:",
-		"expression(",
-		"@import",
-		"data:",
-		"behavior:",
-		"-moz-binding",
-		"url(",
+	data, err := json.Marshal(styles)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to encode theme styles")
 	}
+	return data, nil
+}
 
-	for _, pattern := range dangerousPatterns {
-		if strings.Contains(lower, pattern) {
-			return "", httputil.Validation("custom_css", "CSS contains forbidden pattern: "+pattern)
-		}
+// socialLinkIcon returns the icon ID for a social link, which is the ID of
+// the platform its URL points at.
+func socialLinkIcon(rawURL string) (string, error) {
+	platform := models.DetectSocialPlatform(rawURL)
+	if platform == "" {
+		return "", httputil.Validation("url", "URL does not belong to a supported social platform")
 	}
+	return platform, nil
+}
+
+// maxCustomCSSLength caps custom CSS before it is parsed.
+const maxCustomCSSLength = 64 * 1024
 
-	return css, nil
+// sanitizeCSS rebuilds custom CSS from its allowlisted rules and
+// declarations, returning the result and how many were dropped.
+func sanitizeCSS(css string) (string, int, error) {
+	if len(css) > maxCustomCSSLength {
+		return "", 0, httputil.Validation("custom_css", "custom CSS must be at most 64 KB")
+	}
+	sanitized, dropped := sanitize.CSS(css)
+	return sanitized, dropped, nil
 }
@@ -0,0 +1,539 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/ogimage"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// --- Mock BioPageRepository ---
+
+type mockBioPageRepo struct {
+	repository.BioPageRepository
+	page        *models.BioPage
+	themes      map[uuid.UUID]*models.BioPageTheme
+	links       map[uuid.UUID]*models.BioPageLink
+	createdLink *sqlc.CreateBioPageLinkParams
+	updatedLink *sqlc.UpdateBioPageLinkParams
+	submissions []*models.BioPageSubmission
+}
+
+func (m *mockBioPageRepo) GetByID(_ context.Context, _ uuid.UUID) (*models.BioPage, error) {
+	if m.page == nil {
+		return nil, httputil.NotFound("bio page")
+	}
+	return m.page, nil
+}
+
+func (m *mockBioPageRepo) Update(_ context.Context, params sqlc.UpdateBioPageParams) (*models.BioPage, error) {
+	page := *m.page
+	if params.Title.Valid {
+		page.Title = params.Title.String
+	}
+	if params.OgImageUrl.Valid {
+		page.OgImageURL = &params.OgImageUrl.String
+	}
+	return &page, nil
+}
+
+func (m *mockBioPageRepo) GetMaxLinkPosition(_ context.Context, _ uuid.UUID) (int32, error) {
+	return -1, nil
+}
+
+func (m *mockBioPageRepo) GetLinkCount(_ context.Context, _ uuid.UUID) (int64, error) {
+	return int64(len(m.links)), nil
+}
+
+func (m *mockBioPageRepo) CreateLink(_ context.Context, params sqlc.CreateBioPageLinkParams) (*models.BioPageLink, error) {
+	m.createdLink = &params
+	return &models.BioPageLink{ID: uuid.New(), BioPageID: params.BioPageID}, nil
+}
+
+func (m *mockBioPageRepo) GetLinkByID(_ context.Context, id uuid.UUID) (*models.BioPageLink, error) {
+	link, ok := m.links[id]
+	if !ok {
+		return nil, httputil.NotFound("link")
+	}
+	return link, nil
+}
+
+func (m *mockBioPageRepo) UpdateLink(_ context.Context, params sqlc.UpdateBioPageLinkParams) (*models.BioPageLink, error) {
+	m.updatedLink = &params
+	return m.links[params.ID], nil
+}
+
+func (m *mockBioPageRepo) GetBySlug(_ context.Context, _ string) (*models.BioPage, error) {
+	if m.page == nil {
+		return nil, httputil.NotFound("bio page")
+	}
+	return m.page, nil
+}
+
+func (m *mockBioPageRepo) ListLinks(_ context.Context, _ uuid.UUID) ([]*models.BioPageLink, error) {
+	links := make([]*models.BioPageLink, 0, len(m.links))
+	for _, link := range m.links {
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+func (m *mockBioPageRepo) ListSubmissionsForExport(_ context.Context, _ uuid.UUID) ([]*models.BioPageSubmission, error) {
+	return m.submissions, nil
+}
+
+func (m *mockBioPageRepo) GetThemeByID(_ context.Context, id uuid.UUID) (*models.BioPageTheme, error) {
+	theme, ok := m.themes[id]
+	if !ok {
+		return nil, httputil.NotFound("theme")
+	}
+	return theme, nil
+}
+
+func newTestBioPageService(repo *mockBioPageRepo) *bioPageService {
+	return &bioPageService{
+		bioPageRepo: repo,
+		licManager:  newTestLicenseManager(license.TierFree),
+		logger:      zap.NewNop(),
+	}
+}
+
+func validThemeStyles() models.ThemeStyles {
+	return models.ThemeStyles{
+		BackgroundColor: "#101010",
+		TextColor:       "white",
+		ButtonColor:     "rgba(255, 255, 255, 0.2)",
+		ButtonTextColor: "#fff",
+		ButtonStyle:     "pill",
+		FontFamily:      `"Space Grotesk", sans-serif`,
+		Gradient:        &models.GradientConfig{From: "#ff6b35", To: "#f72585", Direction: "to bottom right"},
+	}
+}
+
+func TestResolveThemeID(t *testing.T) {
+	wsID := uuid.New()
+	ownID, otherID := uuid.New(), uuid.New()
+	svc := newTestBioPageService(&mockBioPageRepo{themes: map[uuid.UUID]*models.BioPageTheme{
+		ownID:   {ID: ownID.String(), IsCustom: true, WorkspaceID: wsID},
+		otherID: {ID: otherID.String(), IsCustom: true, WorkspaceID: uuid.New()},
+	}})
+	ctx := context.Background()
+
+	builtin := models.ThemeIDToUUID("minimal_dark")
+	if got, err := svc.resolveThemeID(ctx, wsID, "minimal_dark"); err != nil || got != builtin {
+		t.Errorf("built-in by key = %v, %v", got, err)
+	}
+	if got, err := svc.resolveThemeID(ctx, wsID, builtin.String()); err != nil || got != builtin {
+		t.Errorf("built-in by UUID = %v, %v", got, err)
+	}
+	if got, err := svc.resolveThemeID(ctx, wsID, ownID.String()); err != nil || got != ownID {
+		t.Errorf("custom theme = %v, %v", got, err)
+	}
+
+	if _, err := svc.resolveThemeID(ctx, wsID, otherID.String()); !errors.Is(err, httputil.ErrForbidden) {
+		t.Errorf("other workspace's theme: expected forbidden, got %v", err)
+	}
+	for _, id := range []string{"no_such_theme", uuid.NewString()} {
+		var appErr *httputil.AppError
+		_, err := svc.resolveThemeID(ctx, wsID, id)
+		if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+			t.Errorf("%q: expected validation error, got %v", id, err)
+		}
+	}
+}
+
+func TestGetPublicPage_ResolvesCustomTheme(t *testing.T) {
+	wsID, themeID := uuid.New(), uuid.New()
+	repo := &mockBioPageRepo{
+		page: &models.BioPage{ID: uuid.New(), WorkspaceID: wsID, Slug: "acme", IsPublished: true, ThemeID: &themeID},
+		themes: map[uuid.UUID]*models.BioPageTheme{
+			themeID: {ID: themeID.String(), Name: "Brand", IsCustom: true, WorkspaceID: wsID, Styles: validThemeStyles()},
+		},
+	}
+	svc := newTestBioPageService(repo)
+
+	resp, err := svc.GetPublicPage(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Theme == nil || resp.Theme.Name != "Brand" {
+		t.Errorf("theme = %+v, want custom theme", resp.Theme)
+	}
+
+	// A theme from another workspace is never rendered.
+	repo.themes[themeID].WorkspaceID = uuid.New()
+	resp, err = svc.GetPublicPage(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Theme != nil {
+		t.Errorf("theme = %+v, want none", resp.Theme)
+	}
+
+	// Built-ins still resolve without touching the repository.
+	builtin := models.ThemeIDToUUID("minimal_light")
+	repo.page.ThemeID = &builtin
+	resp, _ = svc.GetPublicPage(context.Background(), "acme")
+	if resp.Theme == nil || resp.Theme.ID != "minimal_light" {
+		t.Errorf("theme = %+v, want minimal_light", resp.Theme)
+	}
+}
+
+func TestCreateTheme_RequiresLicense(t *testing.T) {
+	svc := newTestBioPageService(&mockBioPageRepo{})
+	_, err := svc.CreateTheme(context.Background(), uuid.New(), models.CreateBioThemeInput{
+		Name:   "Brand",
+		Styles: validThemeStyles(),
+	})
+	if !errors.Is(err, httputil.ErrPaymentRequired) {
+		t.Errorf("expected payment required, got %v", err)
+	}
+}
+
+func TestEncodeThemeStyles(t *testing.T) {
+	if _, err := encodeThemeStyles(validThemeStyles()); err != nil {
+		t.Fatalf("valid styles rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*models.ThemeStyles)
+	}{
+		{"css injection in color", func(s *models.ThemeStyles) { s.BackgroundColor = "red; background: url(https://evil.test)" }},
+		{"empty color", func(s *models.ThemeStyles) { s.TextColor = "" }},
+		{"unknown button style", func(s *models.ThemeStyles) { s.ButtonStyle = "blob" }},
+		{"font with url", func(s *models.ThemeStyles) { s.FontFamily = "url(x)" }},
+		{"bad gradient color", func(s *models.ThemeStyles) { s.Gradient.To = "expression(alert(1))" }},
+		{"bad gradient direction", func(s *models.ThemeStyles) { s.Gradient.Direction = "sideways" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			styles := validThemeStyles()
+			tt.mutate(&styles)
+			var appErr *httputil.AppError
+			if _, err := encodeThemeStyles(styles); !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAddLink_SocialDerivesIcon(t *testing.T) {
+	wsID := uuid.New()
+	repo := &mockBioPageRepo{page: &models.BioPage{ID: uuid.New(), WorkspaceID: wsID}}
+	svc := newTestBioPageService(repo)
+	social := models.BioLinkTypeSocial
+	icon := "star"
+
+	_, err := svc.AddLink(context.Background(), repo.page.ID, wsID, models.CreateBioPageLinkInput{
+		Title:    "Watch me",
+		URL:      "https://m.youtube.com/@acme",
+		LinkType: &social,
+		Icon:     &icon,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.createdLink; got.LinkType != social || got.Icon.String != "youtube" {
+		t.Errorf("created link type=%q icon=%q, want social/youtube", got.LinkType, got.Icon.String)
+	}
+
+	_, err = svc.AddLink(context.Background(), repo.page.ID, wsID, models.CreateBioPageLinkInput{
+		Title:    "Blog",
+		URL:      "https://blog.example.com",
+		LinkType: &social,
+	})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+		t.Errorf("unknown platform: expected validation error, got %v", err)
+	}
+
+	if _, err := svc.AddLink(context.Background(), repo.page.ID, wsID, models.CreateBioPageLinkInput{
+		Title: "Blog",
+		URL:   "https://blog.example.com",
+		Icon:  &icon,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.createdLink; got.LinkType != models.BioLinkTypeRegular || got.Icon.String != "star" {
+		t.Errorf("regular link type=%q icon=%q", got.LinkType, got.Icon.String)
+	}
+}
+
+func TestUpdateLink_SocialURLChangeUpdatesIcon(t *testing.T) {
+	wsID, linkID := uuid.New(), uuid.New()
+	page := &models.BioPage{ID: uuid.New(), WorkspaceID: wsID}
+	repo := &mockBioPageRepo{
+		page: page,
+		links: map[uuid.UUID]*models.BioPageLink{
+			linkID: {ID: linkID, BioPageID: page.ID, URL: "https://instagram.com/acme", LinkType: models.BioLinkTypeSocial},
+		},
+	}
+	svc := newTestBioPageService(repo)
+
+	newURL := "https://www.tiktok.com/@acme"
+	if _, err := svc.UpdateLink(context.Background(), page.ID, linkID, wsID, models.UpdateBioPageLinkInput{URL: &newURL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.updatedLink.Icon; !got.Valid || got.String != "tiktok" {
+		t.Errorf("icon = %+v, want tiktok", got)
+	}
+
+	badURL := "https://example.com"
+	var appErr *httputil.AppError
+	_, err := svc.UpdateLink(context.Background(), page.ID, linkID, wsID, models.UpdateBioPageLinkInput{URL: &badURL})
+	if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+func TestDetectSocialPlatform(t *testing.T) {
+	tests := map[string]string{
+		"https://www.instagram.com/acme":    "instagram",
+		"https://youtu.be/dQw4w9WgXcQ":      "youtube",
+		"https://twitter.com/acme":          "x",
+		"https://open.spotify.com/artist/1": "spotify",
+		"HTTPS://GITHUB.COM/acme":           "github",
+		"https://notinstagram.com/acme":     "",
+		"https://instagram.com.evil.test/x": "",
+		"not a url":                         "",
+	}
+	for in, want := range tests {
+		if got := models.DetectSocialPlatform(in); got != want {
+			t.Errorf("DetectSocialPlatform(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAddLink_EmailCapture(t *testing.T) {
+	wsID := uuid.New()
+	repo := &mockBioPageRepo{page: &models.BioPage{ID: uuid.New(), WorkspaceID: wsID}}
+	svc := newTestBioPageService(repo)
+	capture := models.BioLinkTypeEmailCapture
+
+	_, err := svc.AddLink(context.Background(), repo.page.ID, wsID, models.CreateBioPageLinkInput{
+		Title:    "Join the newsletter",
+		LinkType: &capture,
+	})
+	if !errors.Is(err, httputil.ErrPaymentRequired) {
+		t.Errorf("expected payment required, got %v", err)
+	}
+
+	// Other blocks still need a URL now that it isn't required by binding.
+	_, err = svc.AddLink(context.Background(), repo.page.ID, wsID, models.CreateBioPageLinkInput{Title: "Blog"})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+		t.Errorf("missing url: expected validation error, got %v", err)
+	}
+}
+
+func TestSubmitEmail_RequiresLicense(t *testing.T) {
+	repo := &mockBioPageRepo{page: &models.BioPage{ID: uuid.New(), Slug: "acme", IsPublished: true}}
+	svc := newTestBioPageService(repo)
+
+	err := svc.SubmitEmail(context.Background(), "acme", models.BioEmailSubmissionInput{
+		BlockID: uuid.NewString(),
+		Email:   "fan@example.com",
+	}, "203.0.113.7")
+	if !errors.Is(err, httputil.ErrPaymentRequired) {
+		t.Errorf("expected payment required, got %v", err)
+	}
+}
+
+func TestHashSubmissionIP(t *testing.T) {
+	hash := func(secret string) string {
+		return (&bioPageService{ipSecret: secret}).hashSubmissionIP("203.0.113.7")
+	}
+
+	first := hash("secret-a")
+	unsalted := sha256.Sum256([]byte("203.0.113.7"))
+	if first == hex.EncodeToString(unsalted[:]) {
+		t.Error("expected the IP hash to be keyed, got plain SHA-256")
+	}
+	if len(first) > 64 {
+		t.Errorf("hash is %d characters, want it to fit ip_hash VARCHAR(64)", len(first))
+	}
+	if hash("secret-a") != first {
+		t.Error("expected the same IP and secret to give the same hash")
+	}
+	if hash("secret-b") == first {
+		t.Error("expected a different secret to give a different hash")
+	}
+}
+
+func TestGetPublicPage_HidesEmailCaptureWithoutLicense(t *testing.T) {
+	pageID, linkID, captureID := uuid.New(), uuid.New(), uuid.New()
+	repo := &mockBioPageRepo{
+		page: &models.BioPage{ID: pageID, Slug: "acme", IsPublished: true},
+		links: map[uuid.UUID]*models.BioPageLink{
+			linkID:    {ID: linkID, BioPageID: pageID, URL: "https://example.com", IsVisible: true, LinkType: models.BioLinkTypeRegular},
+			captureID: {ID: captureID, BioPageID: pageID, IsVisible: true, LinkType: models.BioLinkTypeEmailCapture},
+		},
+	}
+	svc := newTestBioPageService(repo)
+
+	resp, err := svc.GetPublicPage(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Links) != 1 || resp.Links[0].ID != linkID || resp.Links[0].LinkType != models.BioLinkTypeRegular {
+		t.Errorf("links = %+v, want only the regular link", resp.Links)
+	}
+}
+
+func TestExportSubmissions(t *testing.T) {
+	wsID := uuid.New()
+	name := "=HYPERLINK(\"https://evil.test\")"
+	repo := &mockBioPageRepo{
+		page: &models.BioPage{ID: uuid.New(), WorkspaceID: wsID},
+		submissions: []*models.BioPageSubmission{
+			{Email: "fan@example.com", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+			{Email: "other@example.com", Name: &name, CreatedAt: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	svc := newTestBioPageService(repo)
+
+	var buf bytes.Buffer
+	if err := svc.ExportSubmissions(context.Background(), repo.page.ID, wsID, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "email,name,submitted_at\n" +
+		"fan@example.com,,2026-01-02T03:04:05Z\n" +
+		"other@example.com,\"'=HYPERLINK(\"\"https://evil.test\"\")\",2026-01-03T00:00:00Z\n"
+	if buf.String() != want {
+		t.Errorf("export =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	if err := svc.ExportSubmissions(context.Background(), repo.page.ID, uuid.New(), &buf); !errors.Is(err, httputil.ErrForbidden) {
+		t.Errorf("other workspace: expected forbidden, got %v", err)
+	}
+}
+
+func TestAddLink_EnforcesLinkLimit(t *testing.T) {
+	wsID := uuid.New()
+	page := &models.BioPage{ID: uuid.New(), WorkspaceID: wsID}
+	repo := &mockBioPageRepo{page: page, links: map[uuid.UUID]*models.BioPageLink{}}
+	svc := newTestBioPageService(repo)
+	limit := int(svc.licManager.GetLimits().MaxBioLinks)
+
+	// Hidden links count toward the limit.
+	for i := 0; i < limit; i++ {
+		id := uuid.New()
+		repo.links[id] = &models.BioPageLink{ID: id, BioPageID: page.ID, IsVisible: i%2 == 0}
+	}
+	_, err := svc.AddLink(context.Background(), page.ID, wsID, models.CreateBioPageLinkInput{
+		Title: "One too many",
+		URL:   "https://example.com",
+	})
+	if !errors.Is(err, httputil.ErrPaymentRequired) {
+		t.Errorf("expected payment required, got %v", err)
+	}
+
+	for id := range repo.links {
+		delete(repo.links, id)
+		break
+	}
+	if _, err := svc.AddLink(context.Background(), page.ID, wsID, models.CreateBioPageLinkInput{
+		Title: "Fits",
+		URL:   "https://example.com",
+	}); err != nil {
+		t.Errorf("unexpected error below limit: %v", err)
+	}
+}
+
+type fakeOGRenderer struct {
+	rendered chan ogimage.Card
+}
+
+func (r *fakeOGRenderer) Render(_ context.Context, card ogimage.Card) ([]byte, error) {
+	if r.rendered != nil {
+		r.rendered <- card
+	}
+	return []byte("png"), nil
+}
+
+func TestGenerateOGImage(t *testing.T) {
+	workspaceID := uuid.New()
+	custom := "https://example.com/custom.png"
+	page := &models.BioPage{ID: uuid.New(), WorkspaceID: workspaceID, Title: "Jane", OgImageURL: &custom}
+	store := &recordingStorage{}
+	svc := newTestBioPageService(&mockBioPageRepo{page: page})
+	svc.ogRenderer = &fakeOGRenderer{}
+	svc.store = store
+
+	updated, err := svc.GenerateOGImage(context.Background(), page.ID, workspaceID)
+	if err != nil {
+		t.Fatalf("GenerateOGImage() error = %v", err)
+	}
+	key := "bio-pages/" + page.ID.String() + "/og.png"
+	if len(store.keys) != 1 || store.keys[0] != key {
+		t.Errorf("uploaded keys = %v, want [%s]", store.keys, key)
+	}
+	if updated.OgImageURL == nil || !strings.HasPrefix(*updated.OgImageURL, "https://cdn.example.com/"+key+"?v=") {
+		t.Errorf("og_image_url = %v, want the generated image with a version", updated.OgImageURL)
+	}
+
+	if _, err := svc.GenerateOGImage(context.Background(), page.ID, uuid.New()); err == nil {
+		t.Error("expected error for another workspace's page")
+	}
+}
+
+func TestOgImageReplaceable(t *testing.T) {
+	id := uuid.New()
+	generated := "https://cdn.example.com/bio-pages/" + id.String() + "/og.png?v=abc"
+	custom := "https://example.com/card.png"
+	empty := ""
+
+	tests := []struct {
+		name string
+		url  *string
+		want bool
+	}{
+		{"unset", nil, true},
+		{"empty", &empty, true},
+		{"generated", &generated, true},
+		{"custom", &custom, false},
+	}
+	for _, tt := range tests {
+		if got := ogImageReplaceable(&models.BioPage{ID: id, OgImageURL: tt.url}); got != tt.want {
+			t.Errorf("%s: ogImageReplaceable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateBioPage_TitleChangeRegeneratesOGImage(t *testing.T) {
+	workspaceID := uuid.New()
+	page := &models.BioPage{ID: uuid.New(), WorkspaceID: workspaceID, Title: "Old"}
+	renderer := &fakeOGRenderer{rendered: make(chan ogimage.Card, 1)}
+	svc := newTestBioPageService(&mockBioPageRepo{page: page})
+	svc.events = NewNoopEventPublisher()
+	svc.ogRenderer = renderer
+	svc.store = &recordingStorage{}
+
+	title := "New"
+	if _, err := svc.UpdateBioPage(context.Background(), page.ID, workspaceID, models.UpdateBioPageInput{Title: &title}); err != nil {
+		t.Fatalf("UpdateBioPage() error = %v", err)
+	}
+
+	select {
+	case card := <-renderer.rendered:
+		if card.Title != "New" {
+			t.Errorf("rendered title = %q, want %q", card.Title, "New")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OG image was not regenerated")
+	}
+}
@@ -38,6 +38,7 @@ type DomainService interface {
 	GetDomain(ctx context.Context, id uuid.UUID) (*models.Domain, error)
 	ListDomains(ctx context.Context, workspaceID uuid.UUID) ([]*models.Domain, error)
 	VerifyDomain(ctx context.Context, id, workspaceID uuid.UUID) (*models.Domain, error)
+	UpdateDomain(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateDomainInput) (*models.Domain, error)
 	RemoveDomain(ctx context.Context, id, workspaceID uuid.UUID) error
 	GetDNSRecords(ctx context.Context, id uuid.UUID) (*models.VerificationInstructions, error)
 }
@@ -214,6 +215,60 @@ func (s *domainService) VerifyDomain(ctx context.Context, id, workspaceID uuid.U
 	return d, nil
 }
 
+// UpdateDomain changes the domain's redirect settings. The not-found mode,
+// status and URL are checked together, so a redirect mode can't be saved
+// without a URL to send visitors to.
+func (s *domainService) UpdateDomain(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateDomainInput) (*models.Domain, error) {
+	d, err := s.domainRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("domain does not belong to this workspace")
+	}
+
+	defaultRedirect, err := resolveOptionalURL("default_redirect_url", input.DefaultRedirectURL)
+	if err != nil {
+		return nil, err
+	}
+	custom404, err := resolveOptionalURL("custom_404_url", input.Custom404URL)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateDomainParams{
+		ID:                 d.ID,
+		DefaultRedirectUrl: defaultRedirect,
+		Custom404Url:       custom404,
+	}
+	if input.NotFoundMode != nil {
+		params.NotFoundMode = pgtype.Text{String: strings.TrimSpace(*input.NotFoundMode), Valid: true}
+	}
+	if input.NotFoundStatus != nil {
+		params.NotFoundStatus = pgtype.Int4{Int32: int32(*input.NotFoundStatus), Valid: true}
+	}
+
+	// Check the policy the domain ends up with, not just the fields sent.
+	policy := d.NotFoundPolicy(models.NotFoundPolicy{})
+	if params.NotFoundMode.Valid {
+		policy.Mode = params.NotFoundMode.String
+	}
+	if params.NotFoundStatus.Valid {
+		policy.Status = int(params.NotFoundStatus.Int32)
+	}
+	if custom404.Valid {
+		policy.URL = custom404.String
+	}
+	if policy.Mode != "" {
+		if err := policy.Validate(); err != nil {
+			return nil, httputil.Validation("not_found_mode", err.Error())
+		}
+	}
+
+	return s.domainRepo.Update(ctx, params)
+}
+
 func (s *domainService) RemoveDomain(ctx context.Context, id, workspaceID uuid.UUID) error {
 	d, err := s.domainRepo.GetByID(ctx, id)
 	if err != nil {
@@ -21,6 +21,7 @@ type mockDomainRepo struct {
 	domains      map[uuid.UUID]*models.Domain
 	domainsByStr map[string]*models.Domain
 	count        int64
+	overLimit    map[uuid.UUID]int64
 	createErr    error
 }
 
@@ -99,6 +100,18 @@ func (m *mockDomainRepo) Update(_ context.Context, params sqlc.UpdateDomainParam
 		t := params.LastDnsCheckAt.Time
 		d.LastDNSCheckAt = &t
 	}
+	if params.Custom404Url.Valid {
+		u := params.Custom404Url.String
+		d.Custom404URL = &u
+	}
+	if params.NotFoundMode.Valid {
+		mode := params.NotFoundMode.String
+		d.NotFoundMode = &mode
+	}
+	if params.NotFoundStatus.Valid {
+		status := int(params.NotFoundStatus.Int32)
+		d.NotFoundStatus = &status
+	}
 	d.UpdatedAt = time.Now()
 	return d, nil
 }
@@ -117,6 +130,10 @@ func (m *mockDomainRepo) GetCountForWorkspace(_ context.Context, _ uuid.UUID) (i
 	return m.count, nil
 }
 
+func (m *mockDomainRepo) ListOverLimit(_ context.Context, _ int64) (map[uuid.UUID]int64, error) {
+	return m.overLimit, nil
+}
+
 // --- Mock DNS Resolver ---
 
 type mockDNSResolver struct {
@@ -162,6 +179,7 @@ func newTestDomainService(repo *mockDomainRepo, tier license.Tier, resolver DNSR
 		sslProvider: NewMockSSLProvider(),
 		dnsResolver: resolver,
 		cfg:         cfg,
+		events:      NewNoopEventPublisher(),
 		logger:      logger,
 	}
 
@@ -461,3 +479,56 @@ func TestVerifyDomain_AlreadyVerified(t *testing.T) {
 	}
 }
 
+func TestUpdateDomain_NotFoundMode(t *testing.T) {
+	repo := newMockDomainRepo()
+	wsID := uuid.New()
+	domainID := uuid.New()
+	repo.domains[domainID] = &models.Domain{ID: domainID, WorkspaceID: wsID, Domain: "go.example.com"}
+
+	svc := newTestDomainService(repo, license.TierPro, nil)
+	ctx := context.Background()
+	mode := func(s string) *string { return &s }
+	status := func(n int) *int { return &n }
+
+	// A redirect needs somewhere to go.
+	if _, err := svc.UpdateDomain(ctx, domainID, wsID, models.UpdateDomainInput{NotFoundMode: mode(models.NotFoundModeRedirect)}); err == nil {
+		t.Fatal("expected error for redirect mode without a URL")
+	}
+
+	d, err := svc.UpdateDomain(ctx, domainID, wsID, models.UpdateDomainInput{
+		NotFoundMode:   mode(models.NotFoundModeRedirect),
+		NotFoundStatus: status(301),
+		Custom404URL:   mode("example.com/search"),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	policy := d.NotFoundPolicy(models.NotFoundPolicy{Mode: models.NotFoundModePage})
+	if policy.Mode != models.NotFoundModeRedirect || policy.StatusCode() != 301 || policy.URL != "https://example.com/search" {
+		t.Errorf("policy = %+v", policy)
+	}
+
+	// Switching modes checks the saved status against the new mode.
+	if _, err := svc.UpdateDomain(ctx, domainID, wsID, models.UpdateDomainInput{NotFoundMode: mode(models.NotFoundModeStatus)}); err == nil {
+		t.Error("expected error for status mode with a 301 status")
+	}
+	if _, err := svc.UpdateDomain(ctx, domainID, wsID, models.UpdateDomainInput{
+		NotFoundMode:   mode(models.NotFoundModeStatus),
+		NotFoundStatus: status(410),
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// An empty mode goes back to the instance default.
+	d, err = svc.UpdateDomain(ctx, domainID, wsID, models.UpdateDomainInput{NotFoundMode: mode("")})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := d.NotFoundPolicy(models.NotFoundPolicy{Mode: models.NotFoundModePage}); got.Mode != models.NotFoundModePage {
+		t.Errorf("policy after reset = %+v, want the fallback", got)
+	}
+
+	if _, err := svc.UpdateDomain(ctx, domainID, uuid.New(), models.UpdateDomainInput{}); err == nil {
+		t.Error("expected error for another workspace")
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// LicenseFeatures describes what the active license allows, for the frontend
+// to decide what to show.
+type LicenseFeatures struct {
+	Tier     license.Tier            `json:"tier"`
+	Features []license.FeatureStatus `json:"features"`
+	Limits   license.Limits          `json:"limits"`
+	Usage    *LicenseUsage           `json:"usage,omitempty"`
+}
+
+// LicenseUsage is current usage of the limited resources. Workspaces counts
+// the user's workspaces; the rest are for one workspace. Links counts the
+// links created in the current usage period, as limited by
+// max_links_per_month.
+type LicenseUsage struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	Links       int64     `json:"links"`
+	Domains     int64     `json:"domains"`
+	Members     int64     `json:"members"`
+	Workspaces  int64     `json:"workspaces"`
+}
+
+type LicenseService interface {
+	// GetFeatures returns the feature matrix and limits, plus usage counts
+	// when workspaceID is set.
+	GetFeatures(ctx context.Context, userID uuid.UUID, workspaceID *uuid.UUID) (*LicenseFeatures, error)
+	// ReconcileUsage finds workspaces using more than the current license
+	// allows and records them as license warnings. It runs whenever the
+	// license changes.
+	ReconcileUsage(ctx context.Context) error
+}
+
+type licenseService struct {
+	licManager *license.Manager
+	wsRepo     repository.WorkspaceRepository
+	memberRepo repository.WorkspaceMemberRepository
+	linkRepo   repository.LinkRepository
+	domainRepo repository.DomainRepository
+	events     EventPublisher
+	logger     *zap.Logger
+}
+
+func NewLicenseService(
+	licManager *license.Manager,
+	wsRepo repository.WorkspaceRepository,
+	memberRepo repository.WorkspaceMemberRepository,
+	linkRepo repository.LinkRepository,
+	domainRepo repository.DomainRepository,
+	events EventPublisher,
+	logger *zap.Logger,
+) LicenseService {
+	return &licenseService{
+		licManager: licManager,
+		wsRepo:     wsRepo,
+		memberRepo: memberRepo,
+		linkRepo:   linkRepo,
+		domainRepo: domainRepo,
+		events:     events,
+		logger:     logger,
+	}
+}
+
+func (s *licenseService) GetFeatures(ctx context.Context, userID uuid.UUID, workspaceID *uuid.UUID) (*LicenseFeatures, error) {
+	resp := &LicenseFeatures{
+		Tier:     s.licManager.GetTier(),
+		Features: s.licManager.FeatureMatrix(),
+		Limits:   s.licManager.GetLimits(),
+	}
+	if workspaceID == nil {
+		return resp, nil
+	}
+
+	if _, err := s.memberRepo.Get(ctx, *workspaceID, userID); err != nil {
+		var appErr *httputil.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+			return nil, httputil.Forbidden("you are not a member of this workspace")
+		}
+		return nil, err
+	}
+
+	usage := &LicenseUsage{WorkspaceID: *workspaceID}
+	var err error
+	start, end := UsagePeriod(time.Now())
+	if usage.Links, err = s.linkRepo.CountCreatedForWorkspace(ctx, *workspaceID, start, end); err != nil {
+		return nil, err
+	}
+	if usage.Domains, err = s.domainRepo.GetCountForWorkspace(ctx, *workspaceID); err != nil {
+		return nil, err
+	}
+	if usage.Members, err = s.memberRepo.GetCount(ctx, *workspaceID); err != nil {
+		return nil, err
+	}
+	if usage.Workspaces, err = s.wsRepo.GetCountForUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	resp.Usage = usage
+
+	return resp, nil
+}
+
+func (s *licenseService) ReconcileUsage(ctx context.Context) error {
+	limits := s.licManager.GetLimits()
+	var warnings []license.LimitWarning
+
+	if limits.MaxUsers >= 0 {
+		counts, err := s.memberRepo.ListOverLimit(ctx, limits.MaxUsers)
+		if err != nil {
+			return err
+		}
+		warnings = appendLimitWarnings(warnings, license.LimitMaxUsers, limits.MaxUsers, counts)
+	}
+	if limits.MaxDomains >= 0 {
+		counts, err := s.domainRepo.ListOverLimit(ctx, limits.MaxDomains)
+		if err != nil {
+			return err
+		}
+		warnings = appendLimitWarnings(warnings, license.LimitMaxDomains, limits.MaxDomains, counts)
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Limit != warnings[j].Limit {
+			return warnings[i].Limit < warnings[j].Limit
+		}
+		return warnings[i].WorkspaceID < warnings[j].WorkspaceID
+	})
+
+	// Only notify workspaces that weren't already over this limit, so
+	// re-verifying an unchanged license doesn't repeat the webhook.
+	known := make(map[string]bool)
+	for _, w := range s.licManager.LimitWarnings() {
+		known[string(w.Limit)+"/"+w.WorkspaceID] = true
+	}
+	for _, w := range warnings {
+		if known[string(w.Limit)+"/"+w.WorkspaceID] {
+			continue
+		}
+		wsID, _ := uuid.Parse(w.WorkspaceID)
+		if err := s.events.Publish(ctx, "license.limit_exceeded", wsID, w); err != nil {
+			s.logger.Warn("failed to publish license.limit_exceeded event", zap.Error(err))
+		}
+	}
+
+	s.licManager.SetLimitWarnings(warnings)
+	if len(warnings) > 0 {
+		s.logger.Warn("license limits exceeded", zap.Int("warnings", len(warnings)))
+	}
+	return nil
+}
+
+func appendLimitWarnings(warnings []license.LimitWarning, lt license.LimitType, limit int64, counts map[uuid.UUID]int64) []license.LimitWarning {
+	for wsID, count := range counts {
+		warnings = append(warnings, license.LimitWarning{
+			Limit:       lt,
+			WorkspaceID: wsID.String(),
+			Current:     count,
+			Max:         limit,
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type mockUserWorkspaceCounter struct {
+	repository.WorkspaceRepository
+	count int64
+}
+
+func (m *mockUserWorkspaceCounter) GetCountForUser(_ context.Context, _ uuid.UUID) (int64, error) {
+	return m.count, nil
+}
+
+func TestLicenseService_GetFeatures(t *testing.T) {
+	wsID := uuid.New()
+	domainRepo := newMockDomainRepo()
+	domainRepo.count = 2
+	svc := NewLicenseService(
+		newTestLicenseManager(license.TierFree),
+		&mockUserWorkspaceCounter{count: 1},
+		&mockMemberRepo{roles: map[uuid.UUID]models.WorkspaceRole{wsID: models.RoleViewer}},
+		&mockLinkRepo{countCreatedFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) { return 42, nil }},
+		domainRepo,
+		NewNoopEventPublisher(),
+		zap.NewNop(),
+	)
+	ctx := context.Background()
+
+	resp, err := svc.GetFeatures(ctx, uuid.New(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Tier != license.TierFree || resp.Usage != nil {
+		t.Errorf("tier = %q, usage = %+v; want free and no usage", resp.Tier, resp.Usage)
+	}
+	if len(resp.Features) != len(license.AllFeatures()) {
+		t.Errorf("got %d features, want %d", len(resp.Features), len(license.AllFeatures()))
+	}
+	for _, f := range resp.Features {
+		want := license.TierFree.IncludesTier(f.MinTier)
+		if f.Available != want {
+			t.Errorf("%s available = %v, want %v", f.Feature, f.Available, want)
+		}
+	}
+	if resp.Limits != license.DefaultLimits(license.TierFree) {
+		t.Errorf("limits = %+v, want free defaults", resp.Limits)
+	}
+
+	resp, err = svc.GetFeatures(ctx, uuid.New(), &wsID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := LicenseUsage{WorkspaceID: wsID, Links: 42, Domains: 2, Members: 1, Workspaces: 1}
+	if resp.Usage == nil || *resp.Usage != want {
+		t.Errorf("usage = %+v, want %+v", resp.Usage, want)
+	}
+
+	other := uuid.New()
+	if _, err := svc.GetFeatures(ctx, uuid.New(), &other); !errors.Is(err, httputil.ErrForbidden) {
+		t.Errorf("non-member: expected forbidden, got %v", err)
+	}
+}
+
+type recordingPublisher struct {
+	events []string
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event string, workspaceID uuid.UUID, _ any) error {
+	p.events = append(p.events, event+":"+workspaceID.String())
+	return nil
+}
+
+func TestLicenseService_ReconcileUsage(t *testing.T) {
+	overMembers, overDomains := uuid.New(), uuid.New()
+	domainRepo := newMockDomainRepo()
+	domainRepo.overLimit = map[uuid.UUID]int64{overDomains: 2}
+	memberRepo := &mockMemberRepo{overLimit: map[uuid.UUID]int64{overMembers: 4}}
+	events := &recordingPublisher{}
+	mgr := newTestLicenseManager(license.TierFree)
+	svc := NewLicenseService(mgr, &mockUserWorkspaceCounter{}, memberRepo, &mockLinkRepo{}, domainRepo, events, zap.NewNop())
+
+	if err := svc.ReconcileUsage(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	limits := mgr.GetLimits()
+	want := []license.LimitWarning{
+		{Limit: license.LimitMaxDomains, WorkspaceID: overDomains.String(), Current: 2, Max: limits.MaxDomains},
+		{Limit: license.LimitMaxUsers, WorkspaceID: overMembers.String(), Current: 4, Max: limits.MaxUsers},
+	}
+	got := mgr.GetLicenseResponse().Warnings
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("warnings = %+v, want %+v", got, want)
+	}
+	if len(events.events) != 2 {
+		t.Errorf("events = %v, want one per workspace", events.events)
+	}
+
+	// Reconciling again doesn't re-send webhooks for known warnings.
+	if err := svc.ReconcileUsage(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events.events) != 2 {
+		t.Errorf("events = %v, want no repeats", events.events)
+	}
+
+	// Back under the limit clears the warning.
+	memberRepo.overLimit = nil
+	domainRepo.overLimit = nil
+	if err := svc.ReconcileUsage(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mgr.GetLicenseResponse().Warnings; len(got) != 0 {
+		t.Errorf("warnings = %+v, want none", got)
+	}
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/safety"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/shortcode"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	bulkLinkJobQueue     = "bulk_link_job:queue"
+	bulkLinkJobKeyPrefix = "bulk_link_job:"
+	// bulkLinkJobInputPrefix holds a queued job's links until the worker
+	// runs it.
+	bulkLinkJobInputPrefix = "bulk_link_job:input:"
+
+	// bulkLinkJobRetention is how long a job's status stays available after
+	// it is started.
+	bulkLinkJobRetention = 7 * 24 * time.Hour
+	// bulkLinkJobTimeout bounds how long one job may wait in the queue and
+	// run.
+	bulkLinkJobTimeout = time.Hour
+	// bulkLinkJobGrace is how long past its timeout an unfinished job is
+	// given to save its final status before it is reported as failed.
+	bulkLinkJobGrace = 5 * time.Minute
+)
+
+// SubmitBulkCreateLinks creates a bulk request's links right away when there
+// are at most Links.BulkAsyncThreshold of them. Larger requests, up to
+// Links.BulkMaxLinks, are checked against the link limit and then queued
+// for the worker, and the job's status is returned instead.
+func (s *linkService) SubmitBulkCreateLinks(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, *models.BulkLinkJob, error) {
+	if maxLinks := s.cfg.Links.BulkMaxLinks; maxLinks > 0 && len(input.Links) > maxLinks {
+		return nil, nil, httputil.Validation("links", fmt.Sprintf("at most %d links per request", maxLinks))
+	}
+
+	batchSize := s.cfg.Links.BulkAsyncThreshold
+	if batchSize <= 0 || len(input.Links) <= batchSize {
+		links, err := s.BulkCreateLinks(ctx, userID, workspaceID, input)
+		return links, nil, err
+	}
+
+	// Fail now rather than part way through the job
+	if err := s.checkLinkLimit(ctx, workspaceID, int64(len(input.Links))); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	job := &models.BulkLinkJob{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		RequestedBy: userID,
+		Status:      models.BulkLinkJobPending,
+		Requested:   len(input.Links),
+		LinkIDs:     []uuid.UUID{},
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(bulkLinkJobRetention),
+	}
+	if err := saveBulkLinkJob(ctx, s.redis, job); err != nil {
+		return nil, nil, err
+	}
+	if err := saveBulkLinkJobInput(ctx, s.redis, job.ID, &bulkLinkJobInput{Links: input.Links, BatchSize: batchSize}); err != nil {
+		return nil, nil, err
+	}
+	if err := s.redis.RPush(ctx, bulkLinkJobQueue, job.ID.String()).Err(); err != nil {
+		return nil, nil, httputil.Wrap(err, "failed to queue bulk link job")
+	}
+
+	return nil, job, nil
+}
+
+func (s *linkService) GetBulkLinkJob(ctx context.Context, workspaceID, jobID uuid.UUID) (*models.BulkLinkJob, error) {
+	job, err := loadBulkLinkJob(ctx, s.redis, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("bulk link job")
+	}
+	if failAbandonedBulkLinkJob(job, time.Now()) {
+		if err := saveBulkLinkJob(ctx, s.redis, job); err != nil {
+			s.logger.Warn("failed to save bulk link job", zap.String("job_id", job.ID.String()), zap.Error(err))
+		}
+	}
+	return job, nil
+}
+
+// failAbandonedBulkLinkJob marks job failed when it is still unfinished well
+// past its timeout. A worker that crashes or restarts mid-job, or a queue no
+// worker reads, leaves jobs pending or processing forever; this gives
+// clients polling the job a final status. It reports whether job changed.
+func failAbandonedBulkLinkJob(job *models.BulkLinkJob, now time.Time) bool {
+	if job.IsFinished() || now.Before(job.CreatedAt.Add(bulkLinkJobTimeout+bulkLinkJobGrace)) {
+		return false
+	}
+	job.Status = models.BulkLinkJobFailed
+	job.Error = fmt.Sprintf("job stopped after creating %d of %d links", job.Created, job.Requested)
+	job.CompletedAt = &now
+	return true
+}
+
+// BulkLinkJobRunner creates the links of queued bulk link jobs. It runs in
+// the worker process.
+type BulkLinkJobRunner struct {
+	links *linkService
+}
+
+// NewBulkLinkJobRunner creates a runner with what bulk link creation needs.
+// urlChecker may be nil when destination screening is off.
+func NewBulkLinkJobRunner(
+	linkRepo repository.LinkRepository,
+	wsRepo repository.WorkspaceRepository,
+	domainRepo repository.DomainRepository,
+	templateRepo repository.LinkTemplateRepository,
+	flagRepo repository.LinkFlagRepository,
+	urlChecker safety.Checker,
+	pool *pgxpool.Pool,
+	redisClient *redis.Client,
+	licManager *license.Manager,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *BulkLinkJobRunner {
+	return &BulkLinkJobRunner{links: &linkService{
+		linkRepo:     linkRepo,
+		wsRepo:       wsRepo,
+		domainRepo:   domainRepo,
+		templateRepo: templateRepo,
+		flagRepo:     flagRepo,
+		urlChecker:   urlChecker,
+		pool:         pool,
+		redis:        redisClient,
+		licManager:   licManager,
+		cfg:          cfg,
+		codeGen:      shortcode.NewGenerator(),
+		logger:       logger,
+	}}
+}
+
+// Run creates the links of the bulk link job with the given ID, recording
+// progress and the outcome on the job. Jobs that already finished are
+// skipped.
+func (r *BulkLinkJobRunner) Run(ctx context.Context, jobID uuid.UUID) error {
+	s := r.links
+	job, err := loadBulkLinkJob(ctx, s.redis, jobID)
+	if err != nil {
+		return err
+	}
+	if job.IsFinished() {
+		return nil
+	}
+
+	defer s.redis.Del(context.WithoutCancel(ctx), bulkLinkJobInputPrefix+jobID.String())
+
+	input, err := loadBulkLinkJobInput(ctx, s.redis, jobID)
+	if err != nil && !errors.Is(err, httputil.ErrNotFound) {
+		return err
+	}
+	if input == nil || !time.Now().Before(job.CreatedAt.Add(bulkLinkJobTimeout)) {
+		// The job waited in the queue for as long as it was allowed to run.
+		now := time.Now()
+		job.Status = models.BulkLinkJobFailed
+		job.Error = "job expired before it could run"
+		job.CompletedAt = &now
+		return saveBulkLinkJob(ctx, s.redis, job)
+	}
+
+	s.runBulkLinkJob(ctx, job, input.Links, input.BatchSize)
+	return nil
+}
+
+// runBulkLinkJob creates the links batchSize at a time, each batch in its
+// own transaction, saving progress after every batch. It stops at the first
+// batch that fails, or when the job's timeout, counted from when it was
+// queued, runs out.
+func (s *linkService) runBulkLinkJob(ctx context.Context, job *models.BulkLinkJob, links []models.CreateLinkInput, batchSize int) {
+	ctx, cancel := context.WithDeadline(ctx, job.CreatedAt.Add(bulkLinkJobTimeout))
+	defer cancel()
+
+	job.Status = models.BulkLinkJobProcessing
+	if err := saveBulkLinkJob(ctx, s.redis, job); err != nil {
+		s.logger.Warn("failed to save bulk link job", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	var runErr error
+	for start := 0; start < len(links); start += batchSize {
+		end := min(start+batchSize, len(links))
+		created, err := s.BulkCreateLinks(ctx, job.RequestedBy, job.WorkspaceID, models.BulkCreateLinkInput{Links: links[start:end]})
+		if err != nil {
+			runErr = err
+			job.Error = fmt.Sprintf("links %d to %d: %s", start, end-1, bulkLinkJobErrorMessage(err))
+			break
+		}
+		for _, link := range created {
+			job.LinkIDs = append(job.LinkIDs, link.ID)
+		}
+		job.Created += len(created)
+		if err := saveBulkLinkJob(ctx, s.redis, job); err != nil {
+			s.logger.Warn("failed to save bulk link job", zap.String("job_id", job.ID.String()), zap.Error(err))
+		}
+	}
+
+	now := time.Now()
+	job.CompletedAt = &now
+	if runErr != nil {
+		s.logger.Error("bulk link job failed",
+			zap.String("job_id", job.ID.String()),
+			zap.String("workspace_id", job.WorkspaceID.String()),
+			zap.Error(runErr),
+		)
+		job.Status = models.BulkLinkJobFailed
+	} else {
+		job.Status = models.BulkLinkJobCompleted
+		s.logger.Info("bulk links created",
+			zap.String("job_id", job.ID.String()),
+			zap.String("workspace_id", job.WorkspaceID.String()),
+			zap.Int("created", job.Created),
+		)
+	}
+
+	if err := saveBulkLinkJob(context.WithoutCancel(ctx), s.redis, job); err != nil {
+		s.logger.Warn("failed to save bulk link job", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+}
+
+// bulkLinkJobErrorMessage is the reason shown on a failed job. Client errors
+// such as an invalid URL are shown as is; anything else is generic.
+func bulkLinkJobErrorMessage(err error) string {
+	var appErr *httputil.AppError
+	if errors.As(err, &appErr) && httputil.MapToHTTPStatus(err) < http.StatusInternalServerError {
+		return appErr.Message
+	}
+	return "links could not be created"
+}
+
+// bulkLinkJobInput is the part of a bulk request a queued job needs.
+type bulkLinkJobInput struct {
+	Links     []models.CreateLinkInput `json:"links"`
+	BatchSize int                      `json:"batch_size"`
+}
+
+func loadBulkLinkJobInput(ctx context.Context, rdb *redis.Client, jobID uuid.UUID) (*bulkLinkJobInput, error) {
+	data, err := rdb.Get(ctx, bulkLinkJobInputPrefix+jobID.String()).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, httputil.NotFound("bulk link job input")
+		}
+		return nil, httputil.Wrap(err, "failed to load bulk link job input")
+	}
+
+	var input bulkLinkJobInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, httputil.Wrap(err, "failed to decode bulk link job input")
+	}
+	return &input, nil
+}
+
+// saveBulkLinkJobInput stores a job's links, which may include link
+// passwords, only for as long as the job can still be run.
+func saveBulkLinkJobInput(ctx context.Context, rdb *redis.Client, jobID uuid.UUID, input *bulkLinkJobInput) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return httputil.Wrap(err, "failed to encode bulk link job input")
+	}
+	if err := rdb.Set(ctx, bulkLinkJobInputPrefix+jobID.String(), data, bulkLinkJobTimeout+bulkLinkJobGrace).Err(); err != nil {
+		return httputil.Wrap(err, "failed to queue bulk link job")
+	}
+	return nil
+}
+
+func loadBulkLinkJob(ctx context.Context, rdb *redis.Client, id uuid.UUID) (*models.BulkLinkJob, error) {
+	data, err := rdb.Get(ctx, bulkLinkJobKeyPrefix+id.String()).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, httputil.NotFound("bulk link job")
+		}
+		return nil, httputil.Wrap(err, "failed to load bulk link job")
+	}
+
+	var job models.BulkLinkJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, httputil.Wrap(err, "failed to decode bulk link job")
+	}
+	return &job, nil
+}
+
+func saveBulkLinkJob(ctx context.Context, rdb *redis.Client, job *models.BulkLinkJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return httputil.Wrap(err, "failed to encode bulk link job")
+	}
+
+	ttl := time.Until(job.ExpiresAt)
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	if err := rdb.Set(ctx, bulkLinkJobKeyPrefix+job.ID.String(), data, ttl).Err(); err != nil {
+		return httputil.Wrap(err, "failed to save bulk link job")
+	}
+	return nil
+}
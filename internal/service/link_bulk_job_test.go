@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+func bulkInput(n int) models.BulkCreateLinkInput {
+	input := models.BulkCreateLinkInput{Links: make([]models.CreateLinkInput, n)}
+	for i := range input.Links {
+		input.Links[i] = models.CreateLinkInput{URL: "https://example.com"}
+	}
+	return input
+}
+
+func TestSubmitBulkCreateLinks_MaxLinks(t *testing.T) {
+	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+	svc.cfg.Links.BulkMaxLinks = 10
+
+	_, _, err := svc.SubmitBulkCreateLinks(context.Background(), uuid.New(), uuid.New(), bulkInput(11))
+	if got := validationField(err); got != "links" {
+		t.Errorf("error = %v, want a validation error on links", err)
+	}
+}
+
+func TestSubmitBulkCreateLinks_AsyncChecksLimitFirst(t *testing.T) {
+	repo := &mockLinkRepo{
+		countCreatedFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) { return 90, nil },
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.cfg.Links.BulkAsyncThreshold = 5
+
+	// The free tier allows 100 links, so 20 more can't be queued
+	links, job, err := svc.SubmitBulkCreateLinks(context.Background(), uuid.New(), uuid.New(), bulkInput(20))
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Errorf("expected PAYMENT_REQUIRED error, got %v", err)
+	}
+	if links != nil || job != nil {
+		t.Errorf("got links %v and job %v, want neither", links, job)
+	}
+}
+
+func TestFailAbandonedBulkLinkJob(t *testing.T) {
+	created := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	newJob := func(status models.BulkLinkJobStatus) *models.BulkLinkJob {
+		return &models.BulkLinkJob{Status: status, Requested: 500, Created: 200, CreatedAt: created}
+	}
+
+	running := newJob(models.BulkLinkJobProcessing)
+	if failAbandonedBulkLinkJob(running, created.Add(bulkLinkJobTimeout)) {
+		t.Error("job failed while it may still be running")
+	}
+
+	for _, status := range []models.BulkLinkJobStatus{models.BulkLinkJobPending, models.BulkLinkJobProcessing} {
+		job := newJob(status)
+		now := created.Add(bulkLinkJobTimeout + bulkLinkJobGrace)
+		if !failAbandonedBulkLinkJob(job, now) {
+			t.Fatalf("%s job past its timeout was not failed", status)
+		}
+		if job.Status != models.BulkLinkJobFailed || job.CompletedAt == nil || !job.CompletedAt.Equal(now) {
+			t.Errorf("job = %+v, want failed at %v", job, now)
+		}
+		if job.Error != "job stopped after creating 200 of 500 links" {
+			t.Errorf("error = %q", job.Error)
+		}
+	}
+
+	done := newJob(models.BulkLinkJobCompleted)
+	if failAbandonedBulkLinkJob(done, created.Add(24*time.Hour)) || done.Status != models.BulkLinkJobCompleted {
+		t.Error("completed job was changed")
+	}
+}
+
+func TestBulkLinkJobInput_KeepsSentFields(t *testing.T) {
+	var input models.BulkCreateLinkInput
+	body := `{"links":[{"url":"https://example.com","cloak":false,"domain_id":null}]}`
+	if err := json.Unmarshal([]byte(body), &input); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(&bulkLinkJobInput{Links: input.Links, BatchSize: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var queued bulkLinkJobInput
+	if err := json.Unmarshal(data, &queued); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queued.Links) != 1 || queued.BatchSize != 50 {
+		t.Fatalf("queued = %+v", queued)
+	}
+	link := queued.Links[0]
+	if link.URL != "https://example.com" {
+		t.Errorf("url = %q", link.URL)
+	}
+	// An explicit false or null overrides a template, so it must survive
+	// the queue.
+	for _, field := range []string{"url", "cloak", "domain_id"} {
+		if !link.Sent(field) {
+			t.Errorf("%s is no longer reported as sent", field)
+		}
+	}
+	if link.Sent("title") {
+		t.Error("title is reported as sent")
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// Destination checks make outbound requests, so each workspace gets a
+// limited number per window.
+const (
+	destinationCheckLimit  = 30
+	destinationCheckWindow = time.Minute
+)
+
+// DestinationChecker probes a destination URL without downloading it.
+// *httputil.SafeClient satisfies it.
+type DestinationChecker interface {
+	Check(ctx context.Context, rawURL string) (*httputil.CheckResult, error)
+}
+
+// CheckDestination reports whether rawURL responds and which redirects it
+// goes through. Nothing is stored.
+func (s *linkService) CheckDestination(ctx context.Context, workspaceID uuid.UUID, rawURL string) (*models.LinkDestinationCheck, error) {
+	normalizedURL, err := normalizeURL(rawURL)
+	if err != nil {
+		return nil, httputil.Validation("url", "invalid URL format")
+	}
+
+	if err := s.checkDestinationRate(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return s.checkDestination(ctx, normalizedURL), nil
+}
+
+func (s *linkService) checkDestinationRate(ctx context.Context, workspaceID uuid.UUID) error {
+	if s.redis == nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("link_check:%s", workspaceID)
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		s.logger.Warn("failed to check destination check rate limit", zap.Error(err))
+		return nil
+	}
+	if count == 1 {
+		s.redis.Expire(ctx, key, destinationCheckWindow)
+	}
+	if count > destinationCheckLimit {
+		return httputil.RateLimited()
+	}
+	return nil
+}
+
+// checkDestination probes a normalized URL. Failures to get a response are
+// reported in the result rather than returned.
+func (s *linkService) checkDestination(ctx context.Context, destination string) *models.LinkDestinationCheck {
+	check := &models.LinkDestinationCheck{
+		URL:       destination,
+		Redirects: []models.LinkRedirectHop{},
+	}
+	if s.destChecker == nil {
+		check.Error = "destination checks are not available"
+		return check
+	}
+
+	res, err := s.destChecker.Check(ctx, destination)
+	if res != nil {
+		for _, hop := range res.Redirects {
+			check.Redirects = append(check.Redirects, models.LinkRedirectHop{URL: hop.URL, StatusCode: hop.StatusCode})
+		}
+		check.Warning = models.RedirectChainWarning(len(check.Redirects))
+	}
+	if err != nil {
+		s.logger.Debug("destination check failed", zap.String("url", destination), zap.Error(err))
+		check.Error = destinationCheckError(err)
+		return check
+	}
+
+	check.StatusCode = res.StatusCode
+	check.FinalURL = res.FinalURL
+	check.Reachable = res.StatusCode < 400
+	return check
+}
+
+// destinationCheckError describes why a destination gave no response
+// without exposing the raw error, which can mention internal addresses.
+func destinationCheckError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, httputil.ErrBlockedAddress):
+		return "destination address is not allowed"
+	case errors.Is(err, httputil.ErrTooManyRedirects):
+		return "too many redirects"
+	case errors.As(err, &dnsErr):
+		return "host could not be resolved"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "destination timed out"
+	default:
+		return "destination could not be reached"
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+type mockDestChecker struct {
+	result *httputil.CheckResult
+	err    error
+	urls   []string
+}
+
+func (m *mockDestChecker) Check(_ context.Context, rawURL string) (*httputil.CheckResult, error) {
+	m.urls = append(m.urls, rawURL)
+	return m.result, m.err
+}
+
+func TestCheckDestination(t *testing.T) {
+	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+	checker := &mockDestChecker{result: &httputil.CheckResult{
+		StatusCode: 404,
+		FinalURL:   "https://example.com/new",
+		Redirects:  []httputil.RedirectHop{{URL: "https://example.com/old", StatusCode: 301}},
+	}}
+	svc.destChecker = checker
+
+	check, err := svc.CheckDestination(context.Background(), uuid.New(), "example.com/old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checker.urls[0] != "https://example.com/old" {
+		t.Errorf("checked %q, want the normalized URL", checker.urls[0])
+	}
+	if check.Reachable || check.StatusCode != 404 || check.FinalURL != "https://example.com/new" {
+		t.Errorf("unexpected check %+v", check)
+	}
+	if len(check.Redirects) != 1 || check.Redirects[0].StatusCode != 301 {
+		t.Errorf("unexpected redirects %+v", check.Redirects)
+	}
+
+	if _, err := svc.CheckDestination(context.Background(), uuid.New(), "http://"); err == nil {
+		t.Error("expected validation error for a URL without a host")
+	}
+}
+
+func TestCheckDestination_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("dial: %w", httputil.ErrBlockedAddress), "destination address is not allowed"},
+		{httputil.ErrTooManyRedirects, "too many redirects"},
+		{&net.DNSError{Err: "no such host", Name: "exmaple.com"}, "host could not be resolved"},
+		{context.DeadlineExceeded, "destination timed out"},
+		{fmt.Errorf("connection refused"), "destination could not be reached"},
+	}
+	for _, tt := range tests {
+		svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+		svc.destChecker = &mockDestChecker{err: tt.err}
+
+		check, err := svc.CheckDestination(context.Background(), uuid.New(), "https://exmaple.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if check.Reachable || check.Error != tt.want {
+			t.Errorf("%v: got %+v, want error %q", tt.err, check, tt.want)
+		}
+	}
+}
+
+func TestCheckDestination_LongRedirectChain(t *testing.T) {
+	hops := []httputil.RedirectHop{
+		{URL: "https://a.example/", StatusCode: 301},
+		{URL: "https://b.example/", StatusCode: 302},
+		{URL: "https://c.example/", StatusCode: 302},
+	}
+	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+	svc.destChecker = &mockDestChecker{result: &httputil.CheckResult{StatusCode: 200, FinalURL: "https://d.example/", Redirects: hops}}
+
+	check, err := svc.CheckDestination(context.Background(), uuid.New(), "https://a.example/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !check.Reachable || check.Warning != "destination redirects 3 times before landing" {
+		t.Errorf("expected a reachable destination with a warning, got %+v", check)
+	}
+
+	// Past the redirect limit the chain so far is still reported
+	svc.destChecker = &mockDestChecker{result: &httputil.CheckResult{Redirects: hops}, err: httputil.ErrTooManyRedirects}
+	check, _ = svc.CheckDestination(context.Background(), uuid.New(), "https://a.example/")
+	if check.Reachable || check.Error != "too many redirects" || len(check.Redirects) != 3 || check.Warning == "" {
+		t.Errorf("expected the partial chain with an error, got %+v", check)
+	}
+
+	svc.destChecker = &mockDestChecker{result: &httputil.CheckResult{StatusCode: 200, Redirects: hops[:2]}}
+	check, _ = svc.CheckDestination(context.Background(), uuid.New(), "https://a.example/")
+	if check.Warning != "" {
+		t.Errorf("expected no warning for 2 redirects, got %q", check.Warning)
+	}
+}
+
+func TestCreateLink_CheckDestinationDoesNotBlock(t *testing.T) {
+	userID := uuid.New()
+	workspaceID := uuid.New()
+
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			return makeLink(uuid.New(), userID, workspaceID, "dead123"), nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "dead123"})
+	svc.destChecker = &mockDestChecker{err: fmt.Errorf("connection refused")}
+
+	link, err := svc.CreateLink(context.Background(), userID, workspaceID, models.CreateLinkInput{
+		URL:              "https://example.com",
+		CheckDestination: true,
+	})
+	if err != nil {
+		t.Fatalf("expected link creation to succeed, got error: %v", err)
+	}
+	if link.DestinationCheck == nil || link.DestinationCheck.Reachable {
+		t.Errorf("expected an unreachable destination check, got %+v", link.DestinationCheck)
+	}
+}
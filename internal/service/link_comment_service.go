@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/sanitize"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// linkCommentChannel is the Redis Pub/Sub channel new comments are published
+// on. Every API server relays them to its WebSocket clients.
+const linkCommentChannel = "comments:realtime"
+
+// LinkCommentService manages the comment threads on a workspace's links.
+type LinkCommentService interface {
+	CreateComment(ctx context.Context, linkID, workspaceID, userID uuid.UUID, input models.CreateLinkCommentInput) (*models.LinkComment, error)
+	ListComments(ctx context.Context, linkID, workspaceID uuid.UUID, pagination models.Pagination) (*models.LinkCommentListResult, error)
+	DeleteComment(ctx context.Context, commentID, linkID, workspaceID uuid.UUID, member *models.WorkspaceMember) error
+}
+
+type linkCommentService struct {
+	commentRepo repository.LinkCommentRepository
+	linkRepo    repository.LinkRepository
+	redis       *redis.Client
+	events      EventPublisher
+	logger      *zap.Logger
+}
+
+func NewLinkCommentService(
+	commentRepo repository.LinkCommentRepository,
+	linkRepo repository.LinkRepository,
+	redisClient *redis.Client,
+	events EventPublisher,
+	logger *zap.Logger,
+) LinkCommentService {
+	return &linkCommentService{
+		commentRepo: commentRepo,
+		linkRepo:    linkRepo,
+		redis:       redisClient,
+		events:      events,
+		logger:      logger,
+	}
+}
+
+func (s *linkCommentService) CreateComment(ctx context.Context, linkID, workspaceID, userID uuid.UUID, input models.CreateLinkCommentInput) (*models.LinkComment, error) {
+	if err := s.checkLinkOwnership(ctx, linkID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, httputil.Validation("body", "comment can't be empty")
+	}
+	if utf8.RuneCountInString(body) > models.MaxLinkCommentLength {
+		return nil, httputil.Validation("body", fmt.Sprintf("must be at most %d characters", models.MaxLinkCommentLength))
+	}
+
+	comment, err := s.commentRepo.Create(ctx, sqlc.CreateLinkCommentParams{
+		LinkID:      linkID,
+		WorkspaceID: workspaceID,
+		UserID:      pgtype.UUID{Bytes: userID, Valid: true},
+		Body:        sanitize.Markdown(body),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.events.Publish(ctx, "link.commented", workspaceID, comment); err != nil {
+		s.logger.Warn("failed to publish link.commented event", zap.Error(err))
+	}
+	s.publishRealtime(ctx, comment)
+
+	return comment, nil
+}
+
+func (s *linkCommentService) ListComments(ctx context.Context, linkID, workspaceID uuid.UUID, pagination models.Pagination) (*models.LinkCommentListResult, error) {
+	if err := s.checkLinkOwnership(ctx, linkID, workspaceID); err != nil {
+		return nil, err
+	}
+	if pagination.Limit == 0 {
+		pagination.Limit = 20
+	}
+
+	comments, err := s.commentRepo.ListForLink(ctx, linkID, int32(pagination.Limit), int32(pagination.Offset))
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.commentRepo.CountForLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LinkCommentListResult{Comments: comments, Total: total}, nil
+}
+
+// DeleteComment deletes a comment. Authors can delete their own comments and
+// admins anyone's.
+func (s *linkCommentService) DeleteComment(ctx context.Context, commentID, linkID, workspaceID uuid.UUID, member *models.WorkspaceMember) error {
+	if err := s.checkLinkOwnership(ctx, linkID, workspaceID); err != nil {
+		return err
+	}
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.LinkID != linkID {
+		return httputil.NotFound("comment")
+	}
+
+	isAuthor := comment.UserID != nil && *comment.UserID == member.UserID
+	if !isAuthor && !member.Role.HasPermission(models.RoleAdmin) {
+		return httputil.Forbidden("only the author or an admin can delete a comment")
+	}
+	return s.commentRepo.Delete(ctx, commentID)
+}
+
+func (s *linkCommentService) checkLinkOwnership(ctx context.Context, linkID, workspaceID uuid.UUID) error {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link.WorkspaceID != workspaceID {
+		return httputil.Forbidden("link does not belong to this workspace")
+	}
+	return nil
+}
+
+// publishRealtime sends a new comment to teammates connected over WebSocket.
+func (s *linkCommentService) publishRealtime(ctx context.Context, comment *models.LinkComment) {
+	if s.redis == nil {
+		return
+	}
+	data, err := json.Marshal(comment)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Publish(ctx, linkCommentChannel, data).Err(); err != nil {
+		s.logger.Warn("failed to publish realtime comment", zap.Error(err))
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type mockLinkCommentRepo struct {
+	comments map[uuid.UUID]*models.LinkComment
+	deleted  []uuid.UUID
+}
+
+func (m *mockLinkCommentRepo) Create(_ context.Context, params sqlc.CreateLinkCommentParams) (*models.LinkComment, error) {
+	userID := uuid.UUID(params.UserID.Bytes)
+	c := &models.LinkComment{
+		ID:          uuid.New(),
+		LinkID:      params.LinkID,
+		WorkspaceID: params.WorkspaceID,
+		UserID:      &userID,
+		Body:        params.Body,
+	}
+	m.comments[c.ID] = c
+	return c, nil
+}
+
+func (m *mockLinkCommentRepo) GetByID(_ context.Context, id uuid.UUID) (*models.LinkComment, error) {
+	c, ok := m.comments[id]
+	if !ok {
+		return nil, httputil.NotFound("comment")
+	}
+	return c, nil
+}
+
+func (m *mockLinkCommentRepo) ListForLink(_ context.Context, linkID uuid.UUID, _, _ int32) ([]*models.LinkComment, error) {
+	var out []*models.LinkComment
+	for _, c := range m.comments {
+		if c.LinkID == linkID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockLinkCommentRepo) CountForLink(ctx context.Context, linkID uuid.UUID) (int64, error) {
+	comments, _ := m.ListForLink(ctx, linkID, 0, 0)
+	return int64(len(comments)), nil
+}
+
+func (m *mockLinkCommentRepo) Delete(_ context.Context, id uuid.UUID) error {
+	delete(m.comments, id)
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func newTestCommentService(link *models.Link) (LinkCommentService, *mockLinkCommentRepo, *recordingPublisher) {
+	linkRepo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+			if id != link.ID {
+				return nil, httputil.NotFound("link")
+			}
+			return link, nil
+		},
+	}
+	commentRepo := &mockLinkCommentRepo{comments: map[uuid.UUID]*models.LinkComment{}}
+	events := &recordingPublisher{}
+	return NewLinkCommentService(commentRepo, linkRepo, nil, events, zap.NewNop()), commentRepo, events
+}
+
+func TestCreateComment(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "cmt1234")
+	svc, _, events := newTestCommentService(link)
+	userID := uuid.New()
+
+	comment, err := svc.CreateComment(context.Background(), link.ID, link.WorkspaceID, userID,
+		models.CreateLinkCommentInput{Body: "  Can we swap the *UTM* tags?  "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.Body != `Can we swap the \*UTM\* tags?` {
+		t.Errorf("Body = %q, want it trimmed and escaped", comment.Body)
+	}
+	if comment.UserID == nil || *comment.UserID != userID {
+		t.Errorf("expected the author to be recorded, got %v", comment.UserID)
+	}
+	if len(events.events) != 1 || events.events[0] != "link.commented:"+link.WorkspaceID.String() {
+		t.Errorf("expected a link.commented event, got %v", events.events)
+	}
+}
+
+func TestCreateComment_Validation(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "cmt5678")
+	svc, commentRepo, _ := newTestCommentService(link)
+
+	for _, body := range []string{"   ", strings.Repeat("a", models.MaxLinkCommentLength+1)} {
+		_, err := svc.CreateComment(context.Background(), link.ID, link.WorkspaceID, uuid.New(), models.CreateLinkCommentInput{Body: body})
+		var appErr *httputil.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+			t.Errorf("expected validation error for a %d character body, got %v", len(body), err)
+		}
+	}
+
+	// Links in other workspaces can't be commented on
+	if _, err := svc.CreateComment(context.Background(), link.ID, uuid.New(), uuid.New(), models.CreateLinkCommentInput{Body: "hi"}); err == nil {
+		t.Error("expected an error for a link in another workspace")
+	}
+	if len(commentRepo.comments) != 0 {
+		t.Errorf("expected no comments, got %d", len(commentRepo.comments))
+	}
+}
+
+func TestDeleteComment_Permissions(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "del1234")
+	svc, commentRepo, _ := newTestCommentService(link)
+	author := uuid.New()
+
+	comment, err := svc.CreateComment(context.Background(), link.ID, link.WorkspaceID, author, models.CreateLinkCommentInput{Body: "ship it"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	editor := &models.WorkspaceMember{UserID: uuid.New(), Role: models.RoleEditor}
+	if err := svc.DeleteComment(context.Background(), comment.ID, link.ID, link.WorkspaceID, editor); err == nil {
+		t.Error("expected another editor to be unable to delete the comment")
+	}
+
+	// A comment is only found through its own link
+	if err := svc.DeleteComment(context.Background(), comment.ID, uuid.New(), link.WorkspaceID, editor); err == nil {
+		t.Error("expected an error for the wrong link")
+	}
+
+	owner := &models.WorkspaceMember{UserID: author, Role: models.RoleViewer}
+	if err := svc.DeleteComment(context.Background(), comment.ID, link.ID, link.WorkspaceID, owner); err != nil {
+		t.Errorf("expected the author to delete their comment, got %v", err)
+	}
+
+	comment, _ = svc.CreateComment(context.Background(), link.ID, link.WorkspaceID, author, models.CreateLinkCommentInput{Body: "again"})
+	admin := &models.WorkspaceMember{UserID: uuid.New(), Role: models.RoleAdmin}
+	if err := svc.DeleteComment(context.Background(), comment.ID, link.ID, link.WorkspaceID, admin); err != nil {
+		t.Errorf("expected an admin to delete any comment, got %v", err)
+	}
+	if len(commentRepo.deleted) != 2 {
+		t.Errorf("expected 2 deletions, got %d", len(commentRepo.deleted))
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// ListLinkDomains returns the workspace's verified custom domains, the ones
+// a new link can be created on.
+func (s *linkService) ListLinkDomains(ctx context.Context, workspaceID uuid.UUID) ([]*models.Domain, error) {
+	verified := []*models.Domain{}
+	if s.domainRepo == nil {
+		return verified, nil
+	}
+
+	domains, err := s.domainRepo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range domains {
+		if d.IsVerified {
+			verified = append(verified, d)
+		}
+	}
+	return verified, nil
+}
+
+// resolveLinkDomain checks that a link may be created on domainID: a
+// verified custom domain of the workspace. A nil domainID means the default
+// redirect host.
+func (s *linkService) resolveLinkDomain(ctx context.Context, workspaceID uuid.UUID, domainID *uuid.UUID) (pgtype.UUID, error) {
+	return resolveWorkspaceDomain(ctx, s.domainRepo, workspaceID, domainID)
+}
+
+// resolveWorkspaceDomain implements resolveLinkDomain for services that
+// don't have a linkService, such as link templates.
+func resolveWorkspaceDomain(ctx context.Context, domainRepo repository.DomainRepository, workspaceID uuid.UUID, domainID *uuid.UUID) (pgtype.UUID, error) {
+	if domainID == nil {
+		return pgtype.UUID{}, nil
+	}
+	if domainRepo == nil {
+		return pgtype.UUID{}, httputil.Validation("domain_id", "custom domains are not available")
+	}
+
+	domain, err := domainRepo.GetByID(ctx, *domainID)
+	if err != nil {
+		var appErr *httputil.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+			return pgtype.UUID{}, httputil.Validation("domain_id", "domain not found in this workspace")
+		}
+		return pgtype.UUID{}, err
+	}
+	// Other workspaces' domains are reported as missing, not forbidden
+	if domain.WorkspaceID != workspaceID {
+		return pgtype.UUID{}, httputil.Validation("domain_id", "domain not found in this workspace")
+	}
+	if !domain.IsVerified {
+		return pgtype.UUID{}, httputil.Validation("domain_id", "domain is not verified")
+	}
+	return pgtype.UUID{Bytes: domain.ID, Valid: true}, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+func TestCreateLink_CustomDomain(t *testing.T) {
+	workspaceID := uuid.New()
+	domains := newMockDomainRepo()
+	verified := &models.Domain{ID: uuid.New(), WorkspaceID: workspaceID, Domain: "go.brand.example", IsVerified: true}
+	pending := &models.Domain{ID: uuid.New(), WorkspaceID: workspaceID, Domain: "new.brand.example"}
+	foreign := &models.Domain{ID: uuid.New(), WorkspaceID: uuid.New(), Domain: "other.example", IsVerified: true}
+	for _, d := range []*models.Domain{verified, pending, foreign} {
+		domains.domains[d.ID] = d
+	}
+
+	var created *sqlc.CreateLinkParams
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			created = &params
+			return makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode), nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "brand1"})
+	svc.domainRepo = domains
+
+	_, err := svc.CreateLink(context.Background(), uuid.New(), workspaceID, models.CreateLinkInput{URL: "https://example.com", DomainID: &verified.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created == nil || !created.DomainID.Valid || created.DomainID.Bytes != verified.ID {
+		t.Errorf("expected the link to be created on the verified domain, got %+v", created)
+	}
+
+	missing := uuid.New()
+	for name, id := range map[string]uuid.UUID{"unverified": pending.ID, "other workspace": foreign.ID, "missing": missing} {
+		created = nil
+		_, err := svc.CreateLink(context.Background(), uuid.New(), workspaceID, models.CreateLinkInput{URL: "https://example.com", DomainID: &id})
+		var appErr *httputil.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" || appErr.Details["field"] != "domain_id" {
+			t.Errorf("%s: expected a domain_id validation error, got %v", name, err)
+		}
+		if created != nil {
+			t.Errorf("%s: expected no link to be created", name)
+		}
+	}
+}
+
+func TestListLinkDomains(t *testing.T) {
+	workspaceID := uuid.New()
+	domains := newMockDomainRepo()
+	verified := &models.Domain{ID: uuid.New(), WorkspaceID: workspaceID, Domain: "go.brand.example", IsVerified: true}
+	domains.domains[verified.ID] = verified
+	pending := &models.Domain{ID: uuid.New(), WorkspaceID: workspaceID, Domain: "new.brand.example"}
+	domains.domains[pending.ID] = pending
+
+	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+	svc.domainRepo = domains
+
+	got, err := svc.ListLinkDomains(context.Background(), workspaceID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != verified.ID {
+		t.Errorf("expected only the verified domain, got %v", got)
+	}
+}
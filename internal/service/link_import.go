@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/linkimport"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	linkImportKeyPrefix = "link_import:"
+	// linkImportActivePrefix maps a workspace to its unfinished import so
+	// repeated requests don't start parallel jobs.
+	linkImportActivePrefix = "link_import:active:"
+
+	// linkImportRetention is how long a job's status stays available after
+	// it is started.
+	linkImportRetention = 7 * 24 * time.Hour
+	// linkImportTimeout bounds how long one import may run.
+	linkImportTimeout = time.Hour
+	// linkImportGrace is how long past its timeout an unfinished import is
+	// given to save its final status before it is reported as failed.
+	linkImportGrace = 5 * time.Minute
+
+	// maxTagNameLength matches the tags.name column.
+	maxTagNameLength = 50
+)
+
+// LinkImportService imports links from other shorteners' APIs.
+type LinkImportService interface {
+	StartImport(ctx context.Context, workspaceID, userID uuid.UUID, input models.StartLinkImportInput) (*models.LinkImport, error)
+	GetImport(ctx context.Context, workspaceID, importID uuid.UUID) (*models.LinkImport, error)
+}
+
+type linkImportService struct {
+	importers   map[string]linkimport.Importer
+	linkService LinkService
+	linkRepo    repository.LinkRepository
+	wsRepo      repository.WorkspaceRepository
+	redis       *redis.Client
+	cfg         *config.Config
+	logger      *zap.Logger
+}
+
+// NewLinkImportService creates the service. importers maps each supported
+// source name to its connector.
+func NewLinkImportService(
+	importers map[string]linkimport.Importer,
+	linkService LinkService,
+	linkRepo repository.LinkRepository,
+	wsRepo repository.WorkspaceRepository,
+	redisClient *redis.Client,
+	cfg *config.Config,
+	logger *zap.Logger,
+) LinkImportService {
+	return &linkImportService{
+		importers:   importers,
+		linkService: linkService,
+		linkRepo:    linkRepo,
+		wsRepo:      wsRepo,
+		redis:       redisClient,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// StartImport checks the token against the source and starts the import in
+// the background. The token lives only in the running job's memory. If the
+// workspace already has an import running, that job is returned instead.
+func (s *linkImportService) StartImport(ctx context.Context, workspaceID, userID uuid.UUID, input models.StartLinkImportInput) (*models.LinkImport, error) {
+	importer, ok := s.importers[input.Source]
+	if !ok {
+		return nil, httputil.Validation("source", "unsupported import source")
+	}
+	onConflict, err := normalizeLinkImportConflict(input.OnConflict)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := importer.Verify(ctx, input.Token); err != nil {
+		if errors.Is(err, linkimport.ErrUnauthorized) {
+			return nil, httputil.Validation("token", "the API token was rejected by "+input.Source)
+		}
+		return nil, httputil.Wrap(err, "failed to reach "+input.Source)
+	}
+
+	now := time.Now()
+	job := &models.LinkImport{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		RequestedBy: userID,
+		Source:      input.Source,
+		OnConflict:  onConflict,
+		Status:      models.LinkImportPending,
+		Conflicts:   []models.ImportConflict{},
+		Warnings:    []models.ImportWarning{},
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(linkImportRetention),
+	}
+
+	activeKey := linkImportActivePrefix + workspaceID.String()
+	acquired, err := s.redis.SetNX(ctx, activeKey, job.ID.String(), linkImportTimeout).Result()
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to start link import")
+	}
+	if !acquired {
+		activeID, err := s.redis.Get(ctx, activeKey).Result()
+		if err == nil {
+			if id, err := uuid.Parse(activeID); err == nil {
+				if existing, err := loadLinkImport(ctx, s.redis, id); err == nil && !existing.IsFinished() {
+					return existing, nil
+				}
+			}
+		}
+		// The marker points at a finished or expired job; take it over.
+		if err := s.redis.Set(ctx, activeKey, job.ID.String(), linkImportTimeout).Err(); err != nil {
+			return nil, httputil.Wrap(err, "failed to start link import")
+		}
+	}
+
+	if err := saveLinkImport(ctx, s.redis, job); err != nil {
+		return nil, err
+	}
+
+	// The job is updated in place while it runs; respond with a snapshot.
+	started := *job
+	go s.run(job, importer, input.Token)
+
+	return &started, nil
+}
+
+func (s *linkImportService) GetImport(ctx context.Context, workspaceID, importID uuid.UUID) (*models.LinkImport, error) {
+	job, err := loadLinkImport(ctx, s.redis, importID)
+	if err != nil {
+		return nil, err
+	}
+	if job.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("link import")
+	}
+	if failAbandonedLinkImport(job, time.Now()) {
+		if err := saveLinkImport(ctx, s.redis, job); err != nil {
+			s.logger.Warn("failed to save link import", zap.String("import_id", job.ID.String()), zap.Error(err))
+		}
+	}
+	return job, nil
+}
+
+// failAbandonedLinkImport marks job failed when it is still unfinished well
+// past its timeout. Imports run in the API process that accepted them, since
+// the source's API token is only ever held in that process's memory, so a
+// restart or crash leaves them pending or processing forever; this gives
+// clients polling the import a final status. It reports whether job changed.
+func failAbandonedLinkImport(job *models.LinkImport, now time.Time) bool {
+	if job.IsFinished() || now.Before(job.CreatedAt.Add(linkImportTimeout+linkImportGrace)) {
+		return false
+	}
+	job.Status = models.LinkImportFailed
+	job.Error = "import did not finish; links imported before the failure were kept"
+	job.CompletedAt = &now
+	return true
+}
+
+// run pulls every link from the source, importing and saving progress page
+// by page. Pages imported before a failure are kept.
+func (s *linkImportService) run(job *models.LinkImport, importer linkimport.Importer, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), linkImportTimeout)
+	defer cancel()
+	defer s.redis.Del(context.WithoutCancel(ctx), linkImportActivePrefix+job.WorkspaceID.String())
+
+	job.Status = models.LinkImportProcessing
+	if err := saveLinkImport(ctx, s.redis, job); err != nil {
+		s.logger.Warn("failed to save link import", zap.String("import_id", job.ID.String()), zap.Error(err))
+	}
+
+	imp := &linkImportRun{s: s, job: job, tags: map[string]uuid.UUID{}}
+	runErr := importer.Links(ctx, token, func(page []linkimport.Link) error {
+		job.Fetched += len(page)
+		if err := imp.importPage(ctx, page); err != nil {
+			return err
+		}
+		return saveLinkImport(ctx, s.redis, job)
+	})
+
+	now := time.Now()
+	job.CompletedAt = &now
+	if runErr != nil {
+		s.logger.Error("link import failed",
+			zap.String("import_id", job.ID.String()),
+			zap.String("workspace_id", job.WorkspaceID.String()),
+			zap.Error(runErr),
+		)
+		job.Status = models.LinkImportFailed
+		job.Error = linkImportErrorMessage(runErr)
+	} else {
+		job.Status = models.LinkImportCompleted
+		s.logger.Info("links imported",
+			zap.String("import_id", job.ID.String()),
+			zap.String("workspace_id", job.WorkspaceID.String()),
+			zap.String("source", job.Source),
+			zap.Int("created", job.Links.Created),
+			zap.Int("skipped", job.Links.Skipped),
+		)
+	}
+
+	if err := saveLinkImport(context.WithoutCancel(ctx), s.redis, job); err != nil {
+		s.logger.Warn("failed to save link import", zap.String("import_id", job.ID.String()), zap.Error(err))
+	}
+}
+
+// linkImportErrorMessage is the reason shown on a failed job. Client errors
+// such as a reached link limit are shown as is; anything else is generic.
+func linkImportErrorMessage(err error) string {
+	if errors.Is(err, linkimport.ErrUnauthorized) {
+		return "the API token was rejected by the source"
+	}
+	var appErr *httputil.AppError
+	if errors.As(err, &appErr) && httputil.MapToHTTPStatus(err) < http.StatusInternalServerError {
+		return appErr.Message
+	}
+	return "import did not finish; links imported before the failure were kept"
+}
+
+func normalizeLinkImportConflict(onConflict string) (string, error) {
+	switch onConflict {
+	case "":
+		return models.ImportConflictSkip, nil
+	case models.ImportConflictSkip, models.ImportConflictSuffix:
+		return onConflict, nil
+	default:
+		return "", httputil.Validation("on_conflict", "must be \"skip\" or \"suffix\"")
+	}
+}
+
+// linkImportRun is the state of one running import.
+type linkImportRun struct {
+	s   *linkImportService
+	job *models.LinkImport
+	// tags caches tag IDs by name for the run.
+	tags map[string]uuid.UUID
+}
+
+func (r *linkImportRun) warn(key, message string) {
+	r.job.AddWarning(models.ImportWarning{Type: "link", Key: key, Message: message})
+}
+
+// importPage creates one page of source links through the bulk-create path,
+// then copies their tags and click totals.
+func (r *linkImportRun) importPage(ctx context.Context, page []linkimport.Link) error {
+	minLen, maxLen, err := r.shortCodeBounds(ctx)
+	if err != nil {
+		return err
+	}
+
+	inputs := make([]models.CreateLinkInput, 0, len(page))
+	sources := make([]linkimport.Link, 0, len(page))
+	for _, src := range page {
+		if _, err := normalizeURL(src.URL); err != nil {
+			r.warn(src.ShortCode, "invalid destination URL, link was not imported")
+			r.job.Links.Skipped++
+			continue
+		}
+
+		code, skip, err := r.shortCode(ctx, src.ShortCode, minLen, maxLen)
+		if err != nil {
+			return err
+		}
+		if skip {
+			r.job.Links.Skipped++
+			continue
+		}
+
+		input := models.CreateLinkInput{URL: src.URL}
+		if code != "" {
+			input.ShortCode = &code
+		}
+		if title := strings.TrimSpace(src.Title); title != "" {
+			input.Title = &title
+		}
+		inputs = append(inputs, input)
+		sources = append(sources, src)
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	links, err := r.s.linkService.BulkCreateLinks(ctx, r.job.RequestedBy, r.job.WorkspaceID, models.BulkCreateLinkInput{Links: inputs})
+	if err != nil {
+		return err
+	}
+
+	for i, link := range links {
+		src := sources[i]
+		if err := r.tagLink(ctx, link, src.Tags); err != nil {
+			return err
+		}
+		if src.Clicks != nil && *src.Clicks > 0 {
+			if err := r.s.linkRepo.AddClicks(ctx, link.ID, *src.Clicks); err != nil {
+				return err
+			}
+		}
+	}
+	r.job.Links.Created += len(links)
+	return nil
+}
+
+// shortCode picks the short code for a source link: the source's code when
+// it fits and is free, a suffixed variant or skip on conflict, or "" to have
+// one generated.
+func (r *linkImportRun) shortCode(ctx context.Context, code string, minLen, maxLen int) (string, bool, error) {
+	if code == "" {
+		return "", false, nil
+	}
+	if !shortCodeFits(code, minLen, maxLen) {
+		r.warn(code, "short code does not fit this workspace's short code rules, a new one was generated")
+		return "", false, nil
+	}
+
+	code = r.s.cfg.Links.NormalizeShortCode(code)
+	taken, err := shortCodeExists(ctx, r.s.linkRepo, r.s.cfg.Links, code)
+	if err != nil {
+		return "", false, err
+	}
+	if !taken {
+		return code, false, nil
+	}
+
+	conflict := models.ImportConflict{Type: "link", Key: code, Resolution: "skipped"}
+	if r.job.OnConflict == models.ImportConflictSuffix {
+		for n := 2; n < importSuffixMaxAttempts+2; n++ {
+			candidate := suffixedKey(code, n, maxLen)
+			taken, err := shortCodeExists(ctx, r.s.linkRepo, r.s.cfg.Links, candidate)
+			if err != nil {
+				return "", false, err
+			}
+			if !taken {
+				conflict.Resolution = "suffixed"
+				conflict.NewKey = candidate
+				r.job.AddConflict(conflict)
+				return candidate, false, nil
+			}
+		}
+	}
+
+	r.job.AddConflict(conflict)
+	return "", true, nil
+}
+
+// tagLink adds the named workspace tags to link, creating missing ones.
+func (r *linkImportRun) tagLink(ctx context.Context, link *models.Link, names []string) error {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxTagNameLength {
+			r.warn(link.ShortCode, "tag "+name+" is too long and was not imported")
+			continue
+		}
+
+		tagID, ok := r.tags[name]
+		if !ok {
+			var err error
+			tagID, err = r.s.linkRepo.EnsureTag(ctx, r.job.WorkspaceID, name)
+			if err != nil {
+				return err
+			}
+			r.tags[name] = tagID
+		}
+		if err := r.s.linkRepo.AddTag(ctx, link.ID, tagID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *linkImportRun) shortCodeBounds(ctx context.Context) (int, int, error) {
+	if r.s.wsRepo == nil {
+		minLen, maxLen := (*models.WorkspaceShortCodeLength)(nil).Bounds()
+		return minLen, maxLen, nil
+	}
+	ws, err := r.s.wsRepo.GetByID(ctx, r.job.WorkspaceID)
+	if err != nil {
+		return 0, 0, err
+	}
+	minLen, maxLen := ws.ParsedSettings().ShortCodeLength.Bounds()
+	return minLen, maxLen, nil
+}
+
+func loadLinkImport(ctx context.Context, rdb *redis.Client, id uuid.UUID) (*models.LinkImport, error) {
+	data, err := rdb.Get(ctx, linkImportKeyPrefix+id.String()).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, httputil.NotFound("link import")
+		}
+		return nil, httputil.Wrap(err, "failed to load link import")
+	}
+
+	var job models.LinkImport
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, httputil.Wrap(err, "failed to decode link import")
+	}
+	return &job, nil
+}
+
+func saveLinkImport(ctx context.Context, rdb *redis.Client, job *models.LinkImport) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return httputil.Wrap(err, "failed to encode link import")
+	}
+
+	ttl := time.Until(job.ExpiresAt)
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	if err := rdb.Set(ctx, linkImportKeyPrefix+job.ID.String(), data, ttl).Err(); err != nil {
+		return httputil.Wrap(err, "failed to save link import")
+	}
+	return nil
+}
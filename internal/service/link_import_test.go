@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/linkimport"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+func newTestLinkImportRun(onConflict string, taken map[string]bool) *linkImportRun {
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, code string) (bool, error) {
+			return taken[code], nil
+		},
+	}
+	return &linkImportRun{
+		s:    &linkImportService{linkRepo: repo, cfg: &config.Config{}},
+		job:  &models.LinkImport{OnConflict: onConflict},
+		tags: map[string]uuid.UUID{},
+	}
+}
+
+func TestLinkImportRun_ShortCode(t *testing.T) {
+	taken := map[string]bool{"promo": true, "promo-2": true}
+	ctx := context.Background()
+
+	skip := newTestLinkImportRun(models.ImportConflictSkip, taken)
+	if code, skipped, _ := skip.shortCode(ctx, "fresh", 3, 50); code != "fresh" || skipped {
+		t.Errorf("free code = %q, skipped %v", code, skipped)
+	}
+	if _, skipped, _ := skip.shortCode(ctx, "promo", 3, 50); !skipped {
+		t.Error("taken code was not skipped")
+	}
+	if c := skip.job.Conflicts; len(c) != 1 || c[0].Resolution != "skipped" {
+		t.Errorf("conflicts = %+v", c)
+	}
+
+	suffix := newTestLinkImportRun(models.ImportConflictSuffix, taken)
+	if code, skipped, _ := suffix.shortCode(ctx, "promo", 3, 50); code != "promo-3" || skipped {
+		t.Errorf("taken code = %q, skipped %v, want promo-3", code, skipped)
+	}
+
+	// Codes outside the workspace's rules get a generated one instead.
+	if code, skipped, _ := suffix.shortCode(ctx, "a b", 3, 50); code != "" || skipped {
+		t.Errorf("invalid code = %q, skipped %v, want generated", code, skipped)
+	}
+	if len(suffix.job.Warnings) != 1 {
+		t.Errorf("warnings = %+v", suffix.job.Warnings)
+	}
+}
+
+func TestLinkImportRun_TagLinkCachesTags(t *testing.T) {
+	run := newTestLinkImportRun(models.ImportConflictSkip, nil)
+	link := &models.Link{ID: uuid.New(), ShortCode: "abc"}
+
+	long := "this tag name is far too long to fit in the tags name column"
+	if err := run.tagLink(context.Background(), link, []string{"promo", " promo ", "", long}); err != nil {
+		t.Fatalf("tagLink() error = %v", err)
+	}
+	if len(run.tags) != 1 {
+		t.Errorf("cached tags = %v, want just promo", run.tags)
+	}
+	if len(run.job.Warnings) != 1 {
+		t.Errorf("warnings = %+v, want one for the long tag", run.job.Warnings)
+	}
+}
+
+func TestNormalizeLinkImportConflict(t *testing.T) {
+	if got, _ := normalizeLinkImportConflict(""); got != models.ImportConflictSkip {
+		t.Errorf("default = %q, want skip", got)
+	}
+	if _, err := normalizeLinkImportConflict("overwrite"); !errors.Is(err, httputil.ErrValidation) {
+		t.Errorf("error = %v, want validation error", err)
+	}
+}
+
+func TestLinkImportErrorMessage(t *testing.T) {
+	if got := linkImportErrorMessage(httputil.PaymentRequired("link limit reached")); got != "link limit reached" {
+		t.Errorf("client error message = %q", got)
+	}
+	if got := linkImportErrorMessage(linkimport.ErrUnauthorized); got != "the API token was rejected by the source" {
+		t.Errorf("rejected token message = %q", got)
+	}
+	if got := linkImportErrorMessage(errors.New("connection reset")); got == "connection reset" {
+		t.Error("internal error leaked into the job")
+	}
+}
+
+func TestFailAbandonedLinkImport(t *testing.T) {
+	created := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	newJob := func(status models.LinkImportStatus) *models.LinkImport {
+		return &models.LinkImport{Status: status, CreatedAt: created}
+	}
+
+	running := newJob(models.LinkImportProcessing)
+	if failAbandonedLinkImport(running, created.Add(linkImportTimeout)) {
+		t.Error("import failed while it may still be running")
+	}
+
+	for _, status := range []models.LinkImportStatus{models.LinkImportPending, models.LinkImportProcessing} {
+		job := newJob(status)
+		now := created.Add(linkImportTimeout + linkImportGrace)
+		if !failAbandonedLinkImport(job, now) {
+			t.Fatalf("%s import past its timeout was not failed", status)
+		}
+		if job.Status != models.LinkImportFailed || job.CompletedAt == nil || !job.CompletedAt.Equal(now) || job.Error == "" {
+			t.Errorf("job = %+v, want failed at %v with an error", job, now)
+		}
+	}
+
+	done := newJob(models.LinkImportCompleted)
+	if failAbandonedLinkImport(done, created.Add(24*time.Hour)) || done.Status != models.LinkImportCompleted {
+		t.Error("completed import was changed")
+	}
+}
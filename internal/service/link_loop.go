@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/redirect"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// checkRedirectLoop rejects a destination for the link with shortCode that
+// leads back to a short link already in its chain, or that passes through
+// more short links than the redirect service follows. Following stops at a
+// destination we don't serve or a short code that doesn't exist.
+func (s *linkService) checkRedirectLoop(ctx context.Context, shortCode, destination string) error {
+	seen := map[string]bool{s.cfg.Links.NormalizeShortCode(shortCode): true}
+	// The link itself is the first hop
+	for hops := 1; ; hops++ {
+		code, ok := s.ownShortCode(ctx, destination)
+		if !ok {
+			return nil
+		}
+		if seen[code] {
+			return httputil.Validation("url", "destination redirects back to this link")
+		}
+		if hops >= redirect.MaxShortLinkHops {
+			return httputil.Validation("url", fmt.Sprintf("destination redirects through more than %d short links", redirect.MaxShortLinkHops-1))
+		}
+		seen[code] = true
+
+		next, err := s.linkRepo.GetByShortCode(ctx, code)
+		if err != nil {
+			var appErr *httputil.AppError
+			if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+				return nil
+			}
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		destination = next.URL
+	}
+}
+
+// ownShortCode returns the short code destination points at when it is a
+// short link on the redirect host or a verified custom domain.
+func (s *linkService) ownShortCode(ctx context.Context, destination string) (string, bool) {
+	u, err := url.Parse(destination)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	code := strings.Trim(u.Path, "/")
+	if !shortCodeFits(code, models.MinShortCodeLength, models.MaxShortCodeLength) {
+		return "", false
+	}
+
+	own := false
+	if base, err := url.Parse(s.cfg.App.RedirectURL); err == nil && strings.EqualFold(u.Host, base.Host) {
+		own = true
+	} else if s.domainRepo != nil {
+		domain, err := s.domainRepo.GetByDomain(ctx, strings.ToLower(u.Hostname()))
+		own = err == nil && domain != nil && domain.IsVerified
+	}
+	if !own {
+		return "", false
+	}
+	return s.cfg.Links.NormalizeShortCode(code), true
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// newLoopTestService serves the given short code → destination links.
+func newLoopTestService(links map[string]string) *linkService {
+	repo := &mockLinkRepo{
+		getByShortCodeFn: func(_ context.Context, code string) (*models.Link, error) {
+			url, ok := links[code]
+			if !ok {
+				return nil, httputil.NotFound("link")
+			}
+			link := makeLink(uuid.New(), uuid.New(), uuid.New(), code)
+			link.URL = url
+			return link, nil
+		},
+	}
+	return newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+}
+
+func isValidationError(err error) bool {
+	var appErr *httputil.AppError
+	return errors.As(err, &appErr) && appErr.Code == "VALIDATION_ERROR"
+}
+
+func TestCheckRedirectLoop_Direct(t *testing.T) {
+	svc := newLoopTestService(nil)
+	ctx := context.Background()
+
+	if err := svc.checkRedirectLoop(ctx, "self", "http://localhost:8081/self"); !isValidationError(err) {
+		t.Errorf("expected a link pointing at itself to be rejected, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "self", "http://localhost:8081/self?utm_source=x"); !isValidationError(err) {
+		t.Errorf("expected query parameters not to hide the loop, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "self", "https://example.com/self"); err != nil {
+		t.Errorf("expected another host to be allowed, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "self", "http://localhost:8081/self/preview"); err != nil {
+		t.Errorf("expected a non-redirect path to be allowed, got %v", err)
+	}
+}
+
+func TestCheckRedirectLoop_Indirect(t *testing.T) {
+	svc := newLoopTestService(map[string]string{
+		"bbb": "http://localhost:8081/ccc",
+		"ccc": "http://localhost:8081/aaa",
+		"ddd": "https://example.com",
+		"eee": "http://localhost:8081/eee",
+	})
+	ctx := context.Background()
+
+	if err := svc.checkRedirectLoop(ctx, "aaa", "http://localhost:8081/bbb"); !isValidationError(err) {
+		t.Errorf("expected aaa → bbb → ccc → aaa to be rejected, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "aaa", "http://localhost:8081/ddd"); err != nil {
+		t.Errorf("expected a chain ending elsewhere to be allowed, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "aaa", "http://localhost:8081/eee"); !isValidationError(err) {
+		t.Errorf("expected a chain into another loop to be rejected, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "aaa", "http://localhost:8081/missing"); err != nil {
+		t.Errorf("expected a missing short link to end the chain, got %v", err)
+	}
+}
+
+func TestCheckRedirectLoop_TooManyHops(t *testing.T) {
+	svc := newLoopTestService(map[string]string{
+		"hop1": "http://localhost:8081/hop2",
+		"hop2": "http://localhost:8081/hop3",
+		"hop3": "http://localhost:8081/hop4",
+		"hop4": "http://localhost:8081/hop5",
+		"hop5": "https://example.com",
+	})
+	ctx := context.Background()
+
+	if err := svc.checkRedirectLoop(ctx, "start", "http://localhost:8081/hop1"); !isValidationError(err) {
+		t.Errorf("expected a chain through 5 more short links to be rejected, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "start", "http://localhost:8081/hop2"); err != nil {
+		t.Errorf("expected a chain through 4 more short links to be allowed, got %v", err)
+	}
+}
+
+func TestCheckRedirectLoop_CustomDomain(t *testing.T) {
+	svc := newLoopTestService(nil)
+	domains := newMockDomainRepo()
+	verified, _ := domains.Create(context.Background(), sqlc.CreateDomainParams{WorkspaceID: uuid.New(), Domain: "go.example.com"})
+	verified.IsVerified = true
+	_, _ = domains.Create(context.Background(), sqlc.CreateDomainParams{WorkspaceID: uuid.New(), Domain: "pending.example.com"})
+	svc.domainRepo = domains
+	ctx := context.Background()
+
+	if err := svc.checkRedirectLoop(ctx, "promo", "https://GO.example.com/promo"); !isValidationError(err) {
+		t.Errorf("expected a loop through a custom domain to be rejected, got %v", err)
+	}
+	if err := svc.checkRedirectLoop(ctx, "promo", "https://pending.example.com/promo"); err != nil {
+		t.Errorf("expected an unverified domain to be ignored, got %v", err)
+	}
+}
+
+func TestCreateAndUpdateLink_RejectLoops(t *testing.T) {
+	userID := uuid.New()
+	workspaceID := uuid.New()
+	linkID := uuid.New()
+
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+			return makeLink(linkID, userID, workspaceID, "abc123"), nil
+		},
+		getByShortCodeFn: func(_ context.Context, code string) (*models.Link, error) {
+			if code == "other" {
+				link := makeLink(uuid.New(), userID, workspaceID, code)
+				link.URL = "http://localhost:8081/abc123"
+				return link, nil
+			}
+			return nil, httputil.NotFound("link")
+		},
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			t.Error("expected the looping link not to be created")
+			return nil, nil
+		},
+		updateFn: func(_ context.Context, _ sqlc.UpdateLinkParams) (*models.Link, error) {
+			t.Error("expected the looping link not to be updated")
+			return nil, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	_, err := svc.CreateLink(context.Background(), userID, workspaceID, models.CreateLinkInput{
+		URL:       "http://localhost:8081/mine",
+		ShortCode: strPtr("mine"),
+	})
+	if !isValidationError(err) {
+		t.Errorf("CreateLink: expected validation error, got %v", err)
+	}
+
+	_, err = svc.UpdateLink(context.Background(), linkID, workspaceID, models.UpdateLinkInput{
+		URL: strPtr("http://localhost:8081/other"),
+	})
+	if !isValidationError(err) {
+		t.Errorf("UpdateLink: expected validation error, got %v", err)
+	}
+}
+
+func TestBulkCreateLinks_RejectsLoops(t *testing.T) {
+	repo := &mockLinkRepo{
+		getByShortCodeFn: func(_ context.Context, code string) (*models.Link, error) {
+			if code == "other" {
+				link := makeLink(uuid.New(), uuid.New(), uuid.New(), code)
+				link.URL = "http://localhost:8081/mine"
+				return link, nil
+			}
+			return nil, httputil.NotFound("link")
+		},
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			t.Error("expected the looping link not to be created")
+			return nil, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"direct", "http://localhost:8081/mine"},
+		{"indirect", "http://localhost:8081/other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.BulkCreateLinks(context.Background(), uuid.New(), uuid.New(), models.BulkCreateLinkInput{
+				Links: []models.CreateLinkInput{{URL: tt.url, ShortCode: strPtr("mine")}},
+			})
+			if !isValidationError(err) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+)
+
+// Column limits for fetched metadata; see the links table.
+const (
+	maxMetadataTitleLen = 500
+	maxMetadataURLLen   = 500
+)
+
+// PageFetcher fetches a destination page for metadata extraction.
+// *httputil.SafeClient satisfies it.
+type PageFetcher interface {
+	Get(ctx context.Context, rawURL string) (*httputil.FetchResult, error)
+}
+
+// pageMetadata is what parsePageMetadata found in a page's head. Empty
+// fields were not present.
+type pageMetadata struct {
+	Title       string
+	Description string
+	FaviconURL  string
+	OgImageURL  string
+}
+
+// RefreshLinkMetadata re-fetches the link's destination and stores its title,
+// description, favicon and OG image. Values the page doesn't provide are
+// left as they were. Each link can be refreshed once per cooldown.
+func (s *linkService) RefreshLinkMetadata(ctx context.Context, id, workspaceID uuid.UUID) (*models.LinkMetadata, error) {
+	existing, err := s.linkRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("link does not belong to this workspace")
+	}
+	if err := requireNotReserved(existing); err != nil {
+		return nil, err
+	}
+
+	cooldown := s.cfg.Links.MetadataRefreshCooldown
+	if cooldown <= 0 {
+		cooldown = defaultMetadataRefreshCooldown
+	}
+	acquired, err := s.redis.SetNX(ctx, "link_metadata_refresh:"+id.String(), 1, cooldown).Result()
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to check rate limit")
+	}
+	if !acquired {
+		return nil, httputil.RateLimited()
+	}
+
+	page, err := s.pageFetcher.Get(ctx, existing.URL)
+	if err != nil {
+		s.logger.Debug("metadata fetch failed", zap.String("url", existing.URL), zap.Error(err))
+		return nil, httputil.Validation("url", "destination could not be fetched")
+	}
+
+	meta := parsePageMetadata(page.Body, page.FinalURL)
+	link, err := s.linkRepo.UpdateMetadata(ctx, sqlc.UpdateLinkMetadataParams{
+		ID:          id,
+		Title:       optionalNonEmpty(meta.Title),
+		Description: optionalNonEmpty(meta.Description),
+		FaviconUrl:  optionalNonEmpty(meta.FaviconURL),
+		OgImageUrl:  optionalNonEmpty(meta.OgImageURL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish webhook event (best-effort)
+	if err := s.events.Publish(ctx, "link.updated", workspaceID, link); err != nil {
+		s.logger.Warn("failed to publish link.updated event", zap.Error(err))
+	}
+
+	return &models.LinkMetadata{
+		Title:       link.Title,
+		Description: link.Description,
+		FaviconURL:  link.FaviconURL,
+		OgImageURL:  link.OgImageURL,
+	}, nil
+}
+
+// parsePageMetadata extracts the title, description, favicon and OG image
+// from an HTML document. Open Graph values win over <title> and the plain
+// description meta tag. Relative URLs are resolved against pageURL, and the
+// favicon falls back to /favicon.ico on the page's origin.
+func parsePageMetadata(body []byte, pageURL string) pageMetadata {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return pageMetadata{}
+	}
+
+	var (
+		meta                  pageMetadata
+		titleTag, description string
+		inTitle               bool
+	)
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+scan:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break scan
+		case html.TextToken:
+			if inTitle {
+				titleTag += string(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break scan
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			attrs := map[string]string{}
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				attrs[string(key)] = string(val)
+			}
+
+			switch string(name) {
+			case "title":
+				inTitle = titleTag == ""
+			case "body":
+				break scan
+			case "meta":
+				content := strings.TrimSpace(attrs["content"])
+				switch strings.ToLower(attrs["property"]) {
+				case "og:title":
+					meta.Title = content
+				case "og:description":
+					meta.Description = content
+				case "og:image":
+					meta.OgImageURL = resolveMetadataURL(base, content)
+				}
+				if strings.EqualFold(attrs["name"], "description") {
+					description = content
+				}
+			case "link":
+				if meta.FaviconURL != "" {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
+					if rel == "icon" {
+						meta.FaviconURL = resolveMetadataURL(base, attrs["href"])
+						break
+					}
+				}
+			}
+		}
+	}
+
+	if meta.Title == "" {
+		meta.Title = strings.Join(strings.Fields(titleTag), " ")
+	}
+	if meta.Description == "" {
+		meta.Description = description
+	}
+	if meta.FaviconURL == "" && base.Host != "" {
+		meta.FaviconURL = resolveMetadataURL(base, "/favicon.ico")
+	}
+	if r := []rune(meta.Title); len(r) > maxMetadataTitleLen {
+		meta.Title = string(r[:maxMetadataTitleLen])
+	}
+	return meta
+}
+
+// resolveMetadataURL resolves ref against base, keeping only http(s) URLs
+// that fit in the links table.
+func resolveMetadataURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	s := u.String()
+	if len(s) > maxMetadataURLLen {
+		return ""
+	}
+	return s
+}
+
+// optionalNonEmpty maps an empty string to NULL so COALESCE keeps the
+// stored value.
+func optionalNonEmpty(v string) pgtype.Text {
+	return pgtype.Text{String: v, Valid: v != ""}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+func TestParsePageMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want pageMetadata
+	}{
+		{
+			name: "open graph wins",
+			body: `<html><head>
+				<title>Plain Title</title>
+				<meta name="description" content="Plain description">
+				<meta property="og:title" content="OG Title">
+				<meta property="og:description" content="OG description">
+				<meta property="og:image" content="/img/card.png">
+				<link rel="shortcut icon" href="/static/icon.png">
+			</head><body></body></html>`,
+			want: pageMetadata{
+				Title:       "OG Title",
+				Description: "OG description",
+				FaviconURL:  "https://example.com/static/icon.png",
+				OgImageURL:  "https://example.com/img/card.png",
+			},
+		},
+		{
+			name: "falls back to title and description",
+			body: `<html><head><title>
+				Plain   Title
+			</title><meta name="description" content="Plain description"></head></html>`,
+			want: pageMetadata{
+				Title:       "Plain Title",
+				Description: "Plain description",
+				FaviconURL:  "https://example.com/favicon.ico",
+			},
+		},
+		{
+			name: "ignores non-http image and body content",
+			body: `<html><head><meta property="og:image" content="javascript:alert(1)"></head>
+				<body><title>Not the title</title></body></html>`,
+			want: pageMetadata{
+				FaviconURL: "https://example.com/favicon.ico",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePageMetadata([]byte(tt.body), "https://example.com/articles/1")
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshLinkMetadata_WrongWorkspace(t *testing.T) {
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+			return makeLink(id, uuid.New(), uuid.New(), "abc123"), nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	_, err := svc.RefreshLinkMetadata(context.Background(), uuid.New(), uuid.New())
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "FORBIDDEN" {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+}
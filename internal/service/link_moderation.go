@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// LinkModerationService lets instance admins review links whose destination
+// was flagged as malware or phishing.
+type LinkModerationService interface {
+	ListFlaggedLinks(ctx context.Context, status string, pagination models.Pagination) (*models.LinkFlagListResult, error)
+	ApproveLink(ctx context.Context, linkID, reviewerID uuid.UUID) (*models.LinkFlag, error)
+	RejectLink(ctx context.Context, linkID, reviewerID uuid.UUID) (*models.LinkFlag, error)
+	ListReports(ctx context.Context, linkID uuid.UUID) ([]*models.LinkReport, error)
+}
+
+type linkModerationService struct {
+	flagRepo   repository.LinkFlagRepository
+	reportRepo repository.LinkReportRepository
+	linkRepo   repository.LinkRepository
+	events     EventPublisher
+	logger     *zap.Logger
+}
+
+func NewLinkModerationService(
+	flagRepo repository.LinkFlagRepository,
+	reportRepo repository.LinkReportRepository,
+	linkRepo repository.LinkRepository,
+	events EventPublisher,
+	logger *zap.Logger,
+) LinkModerationService {
+	return &linkModerationService{
+		flagRepo:   flagRepo,
+		reportRepo: reportRepo,
+		linkRepo:   linkRepo,
+		events:     events,
+		logger:     logger,
+	}
+}
+
+func (s *linkModerationService) ListFlaggedLinks(ctx context.Context, status string, pagination models.Pagination) (*models.LinkFlagListResult, error) {
+	if status == "" {
+		status = models.LinkFlagPending
+	}
+	switch status {
+	case models.LinkFlagPending, models.LinkFlagApproved, models.LinkFlagRejected:
+	default:
+		return nil, httputil.Validation("status", "status must be pending, approved or rejected")
+	}
+
+	flags, err := s.flagRepo.ListByStatus(ctx, status, int32(pagination.Limit), int32(pagination.Offset))
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.flagRepo.CountByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LinkFlagListResult{Flags: flags, Total: total}, nil
+}
+
+// ApproveLink clears a pending flag and reactivates the link.
+func (s *linkModerationService) ApproveLink(ctx context.Context, linkID, reviewerID uuid.UUID) (*models.LinkFlag, error) {
+	flag, err := s.flagRepo.Review(ctx, linkID, reviewerID, models.LinkFlagApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.linkRepo.Update(ctx, sqlc.UpdateLinkParams{
+		ID:       linkID,
+		IsActive: pgtype.Bool{Bool: true, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish webhook event (best-effort)
+	if err := s.events.Publish(ctx, "link.updated", link.WorkspaceID, link); err != nil {
+		s.logger.Warn("failed to publish link.updated event", zap.Error(err))
+	}
+
+	return flag, nil
+}
+
+// RejectLink confirms a pending flag. The link stays inactive and its
+// workspace can't turn it back on.
+func (s *linkModerationService) RejectLink(ctx context.Context, linkID, reviewerID uuid.UUID) (*models.LinkFlag, error) {
+	return s.flagRepo.Review(ctx, linkID, reviewerID, models.LinkFlagRejected)
+}
+
+// ListReports returns the abuse reports filed against a link, newest first.
+func (s *linkModerationService) ListReports(ctx context.Context, linkID uuid.UUID) ([]*models.LinkReport, error) {
+	return s.reportRepo.ListForLink(ctx, linkID)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+func TestApproveLink_ReactivatesLink(t *testing.T) {
+	linkID := uuid.New()
+	reviewerID := uuid.New()
+
+	var params sqlc.UpdateLinkParams
+	linkRepo := &mockLinkRepo{
+		updateFn: func(_ context.Context, p sqlc.UpdateLinkParams) (*models.Link, error) {
+			params = p
+			return makeLink(linkID, uuid.New(), uuid.New(), "ok12345"), nil
+		},
+	}
+	flagRepo := newMockLinkFlagRepo()
+	flagRepo.flags[linkID] = &models.LinkFlag{LinkID: linkID, Status: models.LinkFlagPending}
+
+	svc := NewLinkModerationService(flagRepo, &mockLinkReportRepo{}, linkRepo, NewNoopEventPublisher(), zap.NewNop())
+	flag, err := svc.ApproveLink(context.Background(), linkID, reviewerID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flag.Status != models.LinkFlagApproved || flag.ReviewedBy == nil || *flag.ReviewedBy != reviewerID {
+		t.Errorf("unexpected flag %+v", flag)
+	}
+	if params.ID != linkID || !params.IsActive.Valid || !params.IsActive.Bool {
+		t.Errorf("expected link to be reactivated, got %+v", params)
+	}
+
+	if _, err := svc.RejectLink(context.Background(), linkID, reviewerID); !errors.Is(err, httputil.ErrNotFound) {
+		t.Errorf("expected not found for an already reviewed flag, got %v", err)
+	}
+}
+
+func TestListFlaggedLinks_RejectsUnknownStatus(t *testing.T) {
+	svc := NewLinkModerationService(newMockLinkFlagRepo(), &mockLinkReportRepo{}, &mockLinkRepo{}, NewNoopEventPublisher(), zap.NewNop())
+	if _, err := svc.ListFlaggedLinks(context.Background(), "deleted", models.Pagination{Limit: 20}); err == nil {
+		t.Error("expected validation error")
+	}
+}
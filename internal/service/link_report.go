@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/validator"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// reportRateLimit caps the abuse reports one visitor IP can file across
+	// all links within reportRateWindow.
+	reportRateLimit  = 10
+	reportRateWindow = time.Hour
+
+	maxReportDetailsLength = 1000
+
+	// linkFlagSourceReports is the flag source of links disabled by reports.
+	linkFlagSourceReports = "abuse_reports"
+
+	// reporterIPContext separates reporter IP hashes from other uses of the
+	// server secret.
+	reporterIPContext = "link_report_ip."
+)
+
+// ReportLinkResult tells the redirect service whether a report disabled the
+// link, so it can drop the link from its cache.
+type ReportLinkResult struct {
+	LinkID    uuid.UUID
+	ShortCode string
+	Disabled  bool
+}
+
+// AbuseReportService records abuse reports filed by visitors against links.
+type AbuseReportService interface {
+	ReportLink(ctx context.Context, shortCode string, input models.ReportLinkInput, clientIP string) (*ReportLinkResult, error)
+}
+
+type abuseReportService struct {
+	linkRepo   repository.LinkRepository
+	reportRepo repository.LinkReportRepository
+	flagRepo   repository.LinkFlagRepository
+	redis      *redis.Client
+	cfg        *config.Config
+	events     EventPublisher
+	logger     *zap.Logger
+}
+
+func NewAbuseReportService(
+	linkRepo repository.LinkRepository,
+	reportRepo repository.LinkReportRepository,
+	flagRepo repository.LinkFlagRepository,
+	redisClient *redis.Client,
+	cfg *config.Config,
+	events EventPublisher,
+	logger *zap.Logger,
+) AbuseReportService {
+	return &abuseReportService{
+		linkRepo:   linkRepo,
+		reportRepo: reportRepo,
+		flagRepo:   flagRepo,
+		redis:      redisClient,
+		cfg:        cfg,
+		events:     events,
+		logger:     logger,
+	}
+}
+
+// ReportLink stores a visitor's report against a link. Once reports from
+// Safety.ReportThreshold different visitors have come in since the link was
+// last approved, the link is disabled and flagged for admin review. The
+// owning workspace is notified of every report with a link.reported event.
+// Honeypot submissions are accepted without being stored.
+func (s *abuseReportService) ReportLink(ctx context.Context, shortCode string, input models.ReportLinkInput, clientIP string) (*ReportLinkResult, error) {
+	reason := strings.ToLower(strings.TrimSpace(input.Reason))
+	if !models.IsValidLinkReportReason(reason) {
+		return nil, httputil.Validation("reason", "reason must be phishing, malware, spam, illegal or other")
+	}
+
+	params := sqlc.UpsertLinkReportParams{Reason: reason}
+	if input.Details != nil {
+		if details := strings.TrimSpace(*input.Details); details != "" {
+			if utf8.RuneCountInString(details) > maxReportDetailsLength {
+				return nil, httputil.Validation("details", "details must be at most 1000 characters")
+			}
+			params.Details = pgtype.Text{String: details, Valid: true}
+		}
+	}
+	if input.Email != nil {
+		if email := strings.ToLower(strings.TrimSpace(*input.Email)); email != "" {
+			if !validator.IsValidEmail(email) {
+				return nil, httputil.Validation("email", "invalid email address")
+			}
+			params.ReporterEmail = pgtype.Text{String: email, Valid: true}
+		}
+	}
+
+	params.ReporterHash = s.hashReporterIP(clientIP)
+	if err := s.checkReportRate(ctx, params.ReporterHash); err != nil {
+		return nil, err
+	}
+
+	link, err := s.linkRepo.GetByShortCode(ctx, s.cfg.Links.NormalizeShortCode(shortCode))
+	if err != nil {
+		return nil, err
+	}
+	result := &ReportLinkResult{LinkID: link.ID, ShortCode: link.ShortCode}
+
+	if input.Website != "" {
+		return result, nil
+	}
+
+	params.LinkID = link.ID
+	params.WorkspaceID = link.WorkspaceID
+	if _, err := s.reportRepo.Upsert(ctx, params); err != nil {
+		return nil, err
+	}
+
+	count, err := s.reportRepo.CountSinceReview(ctx, link.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.IsActive && count >= int64(s.cfg.Safety.ReportThreshold) {
+		disabled, err := s.disableLink(ctx, link, count)
+		if err != nil {
+			return nil, err
+		}
+		result.Disabled = disabled
+	}
+
+	// Publish webhook event (best-effort)
+	event := models.LinkReportedEvent{
+		LinkID:      link.ID,
+		ShortCode:   link.ShortCode,
+		Reason:      reason,
+		ReportCount: count,
+		Disabled:    result.Disabled,
+	}
+	if err := s.events.Publish(ctx, "link.reported", link.WorkspaceID, event); err != nil {
+		s.logger.Warn("failed to publish link.reported event", zap.Error(err))
+	}
+
+	return result, nil
+}
+
+// disableLink deactivates a reported link and flags it for review, unless a
+// review is already pending or the link was rejected.
+func (s *abuseReportService) disableLink(ctx context.Context, link *models.Link, count int64) (bool, error) {
+	flag, err := s.flagRepo.Get(ctx, link.ID)
+	if err != nil && !errors.Is(err, httputil.ErrNotFound) {
+		return false, err
+	}
+	if flag != nil && flag.Status != models.LinkFlagApproved {
+		return false, nil
+	}
+
+	if _, err := s.linkRepo.Update(ctx, sqlc.UpdateLinkParams{
+		ID:       link.ID,
+		IsActive: pgtype.Bool{Bool: false, Valid: true},
+	}); err != nil {
+		return false, err
+	}
+	if _, err := s.flagRepo.Upsert(ctx, sqlc.UpsertLinkFlagParams{
+		LinkID:      link.ID,
+		WorkspaceID: link.WorkspaceID,
+		Url:         link.URL,
+		Source:      linkFlagSourceReports,
+		Reason:      fmt.Sprintf("%d abuse reports", count),
+	}); err != nil {
+		return false, err
+	}
+
+	s.logger.Info("link disabled after abuse reports",
+		zap.String("link_id", link.ID.String()),
+		zap.Int64("reports", count),
+	)
+	return true, nil
+}
+
+// hashReporterIP returns the stored and rate-limited form of a reporter's IP.
+// It is keyed by the server secret, so the hashes can't be reversed by
+// hashing every IPv4 address.
+func (s *abuseReportService) hashReporterIP(clientIP string) string {
+	return crypto.SignHMAC(s.cfg.App.SecretKey, []byte(reporterIPContext+clientIP))
+}
+
+// checkReportRate counts a report against the visitor's limit. It fails
+// open when Redis is unavailable.
+func (s *abuseReportService) checkReportRate(ctx context.Context, reporterHash string) error {
+	if s.redis == nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("link_report:%s", reporterHash)
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		s.logger.Warn("failed to check link report rate limit", zap.Error(err))
+		return nil
+	}
+	if count == 1 {
+		s.redis.Expire(ctx, key, reportRateWindow)
+	}
+	if count > reportRateLimit {
+		return httputil.RateLimited()
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"go.uber.org/zap"
+)
+
+type mockLinkReportRepo struct {
+	reports map[string]sqlc.UpsertLinkReportParams
+}
+
+func (m *mockLinkReportRepo) Upsert(_ context.Context, params sqlc.UpsertLinkReportParams) (*models.LinkReport, error) {
+	if m.reports == nil {
+		m.reports = map[string]sqlc.UpsertLinkReportParams{}
+	}
+	m.reports[params.ReporterHash] = params
+	return &models.LinkReport{LinkID: params.LinkID, Reason: params.Reason}, nil
+}
+
+func (m *mockLinkReportRepo) CountSinceReview(_ context.Context, _ uuid.UUID) (int64, error) {
+	return int64(len(m.reports)), nil
+}
+
+func (m *mockLinkReportRepo) ListForLink(_ context.Context, _ uuid.UUID) ([]*models.LinkReport, error) {
+	return nil, nil
+}
+
+func newTestReportService(link *models.Link, threshold int) (AbuseReportService, *mockLinkReportRepo, *mockLinkFlagRepo, *recordingPublisher, *bool) {
+	deactivated := new(bool)
+	linkRepo := &mockLinkRepo{
+		getByShortCodeFn: func(_ context.Context, _ string) (*models.Link, error) { return link, nil },
+		updateFn: func(_ context.Context, p sqlc.UpdateLinkParams) (*models.Link, error) {
+			*deactivated = p.IsActive.Valid && !p.IsActive.Bool
+			return link, nil
+		},
+	}
+	reportRepo := &mockLinkReportRepo{}
+	flagRepo := newMockLinkFlagRepo()
+	events := &recordingPublisher{}
+	cfg := &config.Config{Safety: config.SafetyConfig{ReportThreshold: threshold}}
+	svc := NewAbuseReportService(linkRepo, reportRepo, flagRepo, nil, cfg, events, zap.NewNop())
+	return svc, reportRepo, flagRepo, events, deactivated
+}
+
+func TestReportLink_DisablesAtThreshold(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "rep1234")
+	svc, reportRepo, flagRepo, events, deactivated := newTestReportService(link, 2)
+	input := models.ReportLinkInput{Reason: "Phishing", Email: strPtr("reporter@example.com")}
+
+	result, err := svc.ReportLink(context.Background(), "rep1234", input, "203.0.113.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Disabled || *deactivated {
+		t.Fatal("link should stay active below the threshold")
+	}
+
+	// A repeat report from the same visitor doesn't count twice
+	if _, err := svc.ReportLink(context.Background(), "rep1234", input, "203.0.113.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reportRepo.reports) != 1 || *deactivated {
+		t.Fatalf("expected one report and an active link, got %d reports", len(reportRepo.reports))
+	}
+
+	result, err = svc.ReportLink(context.Background(), "rep1234", input, "203.0.113.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Disabled || !*deactivated {
+		t.Error("expected link to be disabled at the threshold")
+	}
+	flag, ok := flagRepo.flags[link.ID]
+	if !ok || flag.Source != linkFlagSourceReports || !flag.IsPending() {
+		t.Errorf("expected pending abuse report flag, got %+v", flag)
+	}
+	if len(events.events) != 3 || events.events[2] != "link.reported:"+link.WorkspaceID.String() {
+		t.Errorf("expected a link.reported event per report, got %v", events.events)
+	}
+	for _, r := range reportRepo.reports {
+		if r.Reason != models.LinkReportPhishing || r.ReporterHash == "203.0.113.2" {
+			t.Errorf("unexpected stored report %+v", r)
+		}
+	}
+}
+
+func TestReportLink_AlreadyHeldIsNotDisabledAgain(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "held999")
+	svc, _, flagRepo, _, deactivated := newTestReportService(link, 1)
+	flagRepo.flags[link.ID] = &models.LinkFlag{LinkID: link.ID, Status: models.LinkFlagRejected}
+
+	result, err := svc.ReportLink(context.Background(), "held999", models.ReportLinkInput{Reason: "spam"}, "203.0.113.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Disabled || *deactivated || flagRepo.flags[link.ID].Status != models.LinkFlagRejected {
+		t.Error("a rejected link should keep its flag and not be updated")
+	}
+}
+
+func TestReportLink_Validation(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "val1234")
+	svc, reportRepo, _, _, _ := newTestReportService(link, 5)
+
+	tests := []models.ReportLinkInput{
+		{Reason: "boring"},
+		{Reason: "spam", Email: strPtr("not-an-email")},
+	}
+	for _, input := range tests {
+		if _, err := svc.ReportLink(context.Background(), "val1234", input, "203.0.113.1"); err == nil {
+			t.Errorf("expected validation error for %+v", input)
+		}
+	}
+
+	// Honeypot submissions look accepted but aren't stored
+	if _, err := svc.ReportLink(context.Background(), "val1234", models.ReportLinkInput{Reason: "spam", Website: "http://bot"}, "203.0.113.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reportRepo.reports) != 0 {
+		t.Error("honeypot report should not be stored")
+	}
+}
+
+func TestHashReporterIP(t *testing.T) {
+	hash := func(secret string) string {
+		svc := &abuseReportService{cfg: &config.Config{App: config.AppConfig{SecretKey: secret}}}
+		return svc.hashReporterIP("203.0.113.7")
+	}
+
+	first := hash("secret-a")
+	unsalted := sha256.Sum256([]byte("203.0.113.7"))
+	if first == hex.EncodeToString(unsalted[:]) {
+		t.Error("expected the IP hash to be keyed, got plain SHA-256")
+	}
+	if len(first) > 64 {
+		t.Errorf("hash is %d characters, want it to fit reporter_hash VARCHAR(64)", len(first))
+	}
+	if hash("secret-a") != first {
+		t.Error("expected the same IP and secret to give the same hash")
+	}
+	if hash("secret-b") == first {
+		t.Error("expected a different secret to give a different hash")
+	}
+}
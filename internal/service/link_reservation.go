@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// ReserveShortCode holds a custom short code for the workspace with an
+// inactive placeholder link until the reservation expires. Creating a link
+// with the code claims the reservation. Reservations count towards the link
+// limit and don't redirect.
+func (s *linkService) ReserveShortCode(ctx context.Context, userID, workspaceID uuid.UUID, input models.ReserveLinkInput) (*models.Link, error) {
+	ttl := models.DefaultLinkReservationTTL
+	if input.TTLHours != 0 {
+		ttl = time.Duration(input.TTLHours) * time.Hour
+	}
+	if ttl <= 0 || ttl > models.MaxLinkReservationTTL {
+		return nil, httputil.Validation("ttl_hours", "reservations must expire within 90 days")
+	}
+
+	code := s.cfg.Links.NormalizeShortCode(input.ShortCode)
+	minLen, maxLen, err := s.shortCodeBounds(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !shortCodeFits(code, minLen, maxLen) {
+		return nil, httputil.Validation("short_code", shortCodeLengthMessage(minLen, maxLen))
+	}
+	reserved, err := claimableShortCode(ctx, s.linkRepo, s.cfg.Links, workspaceID, code)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, httputil.AlreadyExists("short_code")
+	}
+
+	var link *models.Link
+	err = s.withinLinkLimit(ctx, workspaceID, 1, func(linkRepo repository.LinkRepository) error {
+		var err error
+		link, err = linkRepo.Reserve(ctx, sqlc.ReserveShortCodeParams{
+			UserID:        userID,
+			WorkspaceID:   workspaceID,
+			ShortCode:     code,
+			ReservedUntil: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// claimableShortCode checks that a custom short code can be given to a new
+// link in the workspace, freeing it first if its reservation has expired.
+// It reports whether the code is held by the workspace's own reservation,
+// which the link then claims; a code taken by anything else is an
+// ALREADY_EXISTS error.
+func claimableShortCode(ctx context.Context, linkRepo repository.LinkRepository, cfg config.LinksConfig, workspaceID uuid.UUID, code string) (bool, error) {
+	if err := linkRepo.ReleaseExpiredReservation(ctx, code); err != nil {
+		return false, err
+	}
+	exists, err := shortCodeExists(ctx, linkRepo, cfg, code)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, nil
+	}
+
+	if _, err := linkRepo.GetReservation(ctx, workspaceID, code); err != nil {
+		var appErr *httputil.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+			return false, httputil.AlreadyExists("short_code")
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// requireNotReserved rejects changes to a reservation other than claiming
+// or deleting it.
+func requireNotReserved(link *models.Link) error {
+	if link.IsReserved() {
+		return httputil.Validation("short_code", "short code is reserved; create a link with it to claim the reservation")
+	}
+	return nil
+}
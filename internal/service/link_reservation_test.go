@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+func makeReservation(workspaceID uuid.UUID, shortCode string) *models.Link {
+	link := makeLink(uuid.New(), uuid.New(), workspaceID, shortCode)
+	reservedUntil := time.Now().Add(time.Hour)
+	link.URL = ""
+	link.IsActive = false
+	link.ReservedUntil = &reservedUntil
+	return link
+}
+
+func TestReserveShortCode(t *testing.T) {
+	workspaceID := uuid.New()
+	var released []string
+	var reserved *sqlc.ReserveShortCodeParams
+	repo := &mockLinkRepo{
+		releaseExpiredFn: func(_ context.Context, code string) error {
+			released = append(released, code)
+			return nil
+		},
+		reserveFn: func(_ context.Context, params sqlc.ReserveShortCodeParams) (*models.Link, error) {
+			reserved = &params
+			return makeReservation(params.WorkspaceID, params.ShortCode), nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	before := time.Now()
+	if _, err := svc.ReserveShortCode(context.Background(), uuid.New(), workspaceID, models.ReserveLinkInput{ShortCode: "launch"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reserved == nil || reserved.ShortCode != "launch" || reserved.WorkspaceID != workspaceID {
+		t.Fatalf("reservation params = %+v", reserved)
+	}
+	if until := reserved.ReservedUntil.Time; until.Before(before.Add(models.DefaultLinkReservationTTL)) {
+		t.Errorf("reserved until %v, want the default TTL", until)
+	}
+	if len(released) != 1 || released[0] != "launch" {
+		t.Errorf("expired reservation of the code not released first: %v", released)
+	}
+
+	if _, err := svc.ReserveShortCode(context.Background(), uuid.New(), workspaceID, models.ReserveLinkInput{
+		ShortCode: "launch",
+		TTLHours:  24 * 91,
+	}); validationField(err) != "ttl_hours" {
+		t.Errorf("error = %v, want a validation error on ttl_hours", err)
+	}
+}
+
+func TestReserveShortCode_Taken(t *testing.T) {
+	workspaceID := uuid.New()
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return true, nil },
+		getReservationFn: func(_ context.Context, wsID uuid.UUID, code string) (*models.Link, error) {
+			return makeReservation(wsID, code), nil
+		},
+		reserveFn: func(_ context.Context, _ sqlc.ReserveShortCodeParams) (*models.Link, error) {
+			t.Error("reserve should not be called")
+			return nil, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	// Reserving a code twice fails like any other taken code
+	_, err := svc.ReserveShortCode(context.Background(), uuid.New(), workspaceID, models.ReserveLinkInput{ShortCode: "launch"})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "ALREADY_EXISTS" {
+		t.Errorf("expected ALREADY_EXISTS error, got %v", err)
+	}
+}
+
+func TestCreateLink_ClaimsReservation(t *testing.T) {
+	workspaceID := uuid.New()
+	userID := uuid.New()
+
+	var claimed *sqlc.CreateLinkParams
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return true, nil },
+		getReservationFn: func(_ context.Context, wsID uuid.UUID, code string) (*models.Link, error) {
+			if wsID != workspaceID {
+				return nil, httputil.NotFound("reservation")
+			}
+			return makeReservation(wsID, code), nil
+		},
+		// The reservation already counts towards the limit
+		countCreatedFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) { return 100, nil },
+		claimReservationFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			claimed = &params
+			return makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode), nil
+		},
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			t.Error("create should not be called")
+			return nil, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	input := models.CreateLinkInput{URL: "https://example.com/launch", ShortCode: strPtr("launch")}
+	if _, err := svc.CreateLink(context.Background(), userID, workspaceID, input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claimed == nil || claimed.Url != "https://example.com/launch" || claimed.UserID != userID || !claimed.IsActive {
+		t.Errorf("claim params = %+v", claimed)
+	}
+
+	// Another workspace can't claim it
+	claimed = nil
+	_, err := svc.CreateLink(context.Background(), userID, uuid.New(), input)
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "ALREADY_EXISTS" {
+		t.Errorf("expected ALREADY_EXISTS error, got %v", err)
+	}
+	if claimed != nil {
+		t.Error("reservation claimed by another workspace")
+	}
+}
+
+func TestUpdateLink_Reserved(t *testing.T) {
+	workspaceID := uuid.New()
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+			return makeReservation(workspaceID, "launch"), nil
+		},
+		updateFn: func(_ context.Context, _ sqlc.UpdateLinkParams) (*models.Link, error) {
+			t.Error("update should not be called")
+			return nil, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	_, err := svc.UpdateLink(context.Background(), uuid.New(), workspaceID, models.UpdateLinkInput{URL: strPtr("https://example.com")})
+	if got := validationField(err); got != "short_code" {
+		t.Errorf("error = %v, want a validation error on short_code", err)
+	}
+}
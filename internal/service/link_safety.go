@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/safety"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// screenDestination checks a normalized destination against the configured
+// malware and phishing sources. In block mode a match is returned as a
+// validation error; in flag mode the verdict is returned so the caller can
+// create the link inactive and flag it. Provider failures are logged and
+// let the link through, so an outage never blocks link creation.
+func (s *linkService) screenDestination(ctx context.Context, destination string) (*safety.Verdict, error) {
+	if s.urlChecker == nil || s.cfg.Safety.Mode == "" || s.cfg.Safety.Mode == config.SafetyModeOff {
+		return nil, nil
+	}
+
+	verdict, err := s.urlChecker.Check(ctx, destination)
+	if err != nil {
+		s.logger.Warn("destination screening failed", zap.String("url", destination), zap.Error(err))
+	}
+	if verdict == nil {
+		return nil, nil
+	}
+
+	s.logger.Info("destination matched a blocklist",
+		zap.String("url", destination),
+		zap.String("source", verdict.Source),
+		zap.String("reason", verdict.Reason),
+		zap.String("mode", s.cfg.Safety.Mode),
+	)
+	if s.cfg.Safety.Mode == config.SafetyModeBlock {
+		return nil, httputil.Validation("url", "destination is reported as malware or phishing")
+	}
+	return verdict, nil
+}
+
+// screenFailoverURLs runs a link's failover URLs through the checks its
+// destination gets, since visitors are sent to them while it is down. A URL
+// that loops back to the link, or matches a blocklist in block mode, is
+// refused. In flag mode the first flagged URL is returned with its verdict
+// so the caller can hold the link for review.
+func (s *linkService) screenFailoverURLs(ctx context.Context, shortCode string, urls []string) (string, *safety.Verdict, error) {
+	var flaggedURL string
+	var flagged *safety.Verdict
+	for i, u := range urls {
+		field := fmt.Sprintf("failover_urls[%d]", i)
+		verdict, err := s.screenDestination(ctx, u)
+		if err != nil {
+			return "", nil, onField(err, field)
+		}
+		if err := s.checkRedirectLoop(ctx, shortCode, u); err != nil {
+			return "", nil, onField(err, field)
+		}
+		if verdict != nil && flagged == nil {
+			flaggedURL, flagged = u, verdict
+		}
+	}
+	return flaggedURL, flagged, nil
+}
+
+// onField moves a validation error onto field, leaving other errors as is.
+func onField(err error, field string) error {
+	var appErr *httputil.AppError
+	if errors.As(err, &appErr) && errors.Is(err, httputil.ErrValidation) {
+		return httputil.Validation(field, appErr.Message)
+	}
+	return err
+}
+
+// flagLink queues a link created or updated with a flagged destination or
+// failover URL for admin review and attaches the flag to it. url is the URL
+// that was flagged.
+func (s *linkService) flagLink(ctx context.Context, flagRepo repository.LinkFlagRepository, link *models.Link, url string, verdict *safety.Verdict) error {
+	flag, err := flagRepo.Upsert(ctx, sqlc.UpsertLinkFlagParams{
+		LinkID:      link.ID,
+		WorkspaceID: link.WorkspaceID,
+		Url:         url,
+		Source:      verdict.Source,
+		Reason:      verdict.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	link.Flag = flag
+	return nil
+}
+
+// heldForReview reports whether a link has a flag that hasn't been approved.
+func (s *linkService) heldForReview(ctx context.Context, linkID uuid.UUID) (bool, error) {
+	if s.flagRepo == nil {
+		return false, nil
+	}
+	flag, err := s.flagRepo.Get(ctx, linkID)
+	if err != nil {
+		if errors.Is(err, httputil.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return flag.Status != models.LinkFlagApproved, nil
+}
+
+// requireNotHeld refuses to reactivate a link held for review.
+func (s *linkService) requireNotHeld(ctx context.Context, linkID uuid.UUID) error {
+	held, err := s.heldForReview(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if held {
+		return httputil.Forbidden("link is held for review and can't be activated")
+	}
+	return nil
+}
+
+// skipHeldLinks drops links held for review from a bulk activation and
+// reports them as skipped.
+func (s *linkService) skipHeldLinks(ctx context.Context, links []*models.Link, result *models.BulkLinkResult) ([]*models.Link, error) {
+	kept := links[:0]
+	result.LinkIDs = result.LinkIDs[:0]
+	for _, link := range links {
+		held, err := s.heldForReview(ctx, link.ID)
+		if err != nil {
+			return nil, err
+		}
+		if held {
+			result.Skipped = append(result.Skipped, models.BulkLinkSkip{LinkID: link.ID, Reason: "link is held for review"})
+			continue
+		}
+		kept = append(kept, link)
+		result.LinkIDs = append(result.LinkIDs, link.ID)
+	}
+	result.Succeeded = len(kept)
+	return kept, nil
+}
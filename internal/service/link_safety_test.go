@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/safety"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+type mockURLChecker struct {
+	verdict *safety.Verdict
+	err     error
+}
+
+func (m *mockURLChecker) Check(_ context.Context, _ string) (*safety.Verdict, error) {
+	return m.verdict, m.err
+}
+
+type mockLinkFlagRepo struct {
+	flags map[uuid.UUID]*models.LinkFlag
+}
+
+func newMockLinkFlagRepo() *mockLinkFlagRepo {
+	return &mockLinkFlagRepo{flags: map[uuid.UUID]*models.LinkFlag{}}
+}
+
+func (m *mockLinkFlagRepo) Upsert(_ context.Context, params sqlc.UpsertLinkFlagParams) (*models.LinkFlag, error) {
+	flag := &models.LinkFlag{
+		LinkID:      params.LinkID,
+		WorkspaceID: params.WorkspaceID,
+		URL:         params.Url,
+		Source:      params.Source,
+		Reason:      params.Reason,
+		Status:      models.LinkFlagPending,
+	}
+	m.flags[params.LinkID] = flag
+	return flag, nil
+}
+
+func (m *mockLinkFlagRepo) Get(_ context.Context, linkID uuid.UUID) (*models.LinkFlag, error) {
+	if flag, ok := m.flags[linkID]; ok {
+		return flag, nil
+	}
+	return nil, httputil.NotFound("link flag")
+}
+
+func (m *mockLinkFlagRepo) ListByStatus(_ context.Context, status string, _, _ int32) ([]*models.LinkFlag, error) {
+	var out []*models.LinkFlag
+	for _, f := range m.flags {
+		if f.Status == status {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockLinkFlagRepo) CountByStatus(ctx context.Context, status string) (int64, error) {
+	flags, _ := m.ListByStatus(ctx, status, 0, 0)
+	return int64(len(flags)), nil
+}
+
+func (m *mockLinkFlagRepo) Review(_ context.Context, linkID, reviewerID uuid.UUID, status string) (*models.LinkFlag, error) {
+	flag, ok := m.flags[linkID]
+	if !ok || !flag.IsPending() {
+		return nil, httputil.NotFound("pending link flag")
+	}
+	flag.Status = status
+	flag.ReviewedBy = &reviewerID
+	return flag, nil
+}
+
+func newScreeningService(t *testing.T, mode string, checker safety.Checker) (*linkService, *mockLinkFlagRepo, *bool) {
+	t.Helper()
+	createdActive := new(bool)
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			*createdActive = params.IsActive
+			link := makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode)
+			link.URL = params.Url
+			link.IsActive = params.IsActive
+			return link, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "scr1234"})
+	svc.cfg.Safety.Mode = mode
+	svc.urlChecker = checker
+	flagRepo := newMockLinkFlagRepo()
+	svc.flagRepo = flagRepo
+	return svc, flagRepo, createdActive
+}
+
+func TestCreateLink_FlagsBlocklistedDestination(t *testing.T) {
+	verdict := &safety.Verdict{Source: safety.SourceBlocklist, Reason: "domain evil.example is on the blocklist"}
+	svc, flagRepo, createdActive := newScreeningService(t, config.SafetyModeFlag, &mockURLChecker{verdict: verdict})
+
+	link, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{URL: "https://evil.example/login"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *createdActive {
+		t.Error("expected flagged link to be created inactive")
+	}
+	if link.Flag == nil || link.Flag.Source != safety.SourceBlocklist || !link.Flag.IsPending() {
+		t.Errorf("expected pending blocklist flag on link, got %+v", link.Flag)
+	}
+	if _, ok := flagRepo.flags[link.ID]; !ok {
+		t.Error("expected flag to be stored")
+	}
+}
+
+func TestCreateLink_BlocksBlocklistedDestination(t *testing.T) {
+	verdict := &safety.Verdict{Source: safety.SourceSafeBrowsing, Reason: "reported as malware"}
+	svc, flagRepo, _ := newScreeningService(t, config.SafetyModeBlock, &mockURLChecker{verdict: verdict})
+
+	_, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{URL: "https://evil.example"})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(flagRepo.flags) != 0 {
+		t.Error("blocked link should not be flagged")
+	}
+}
+
+func TestCreateLink_ScreeningFailureFailsOpen(t *testing.T) {
+	svc, flagRepo, createdActive := newScreeningService(t, config.SafetyModeBlock, &mockURLChecker{err: errors.New("provider down")})
+
+	link, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !*createdActive || link.Flag != nil || len(flagRepo.flags) != 0 {
+		t.Errorf("expected active unflagged link, got active=%v flag=%+v", *createdActive, link.Flag)
+	}
+}
+
+func TestCreateLink_ScreeningOff(t *testing.T) {
+	verdict := &safety.Verdict{Source: safety.SourceBlocklist, Reason: "listed"}
+	svc, _, createdActive := newScreeningService(t, config.SafetyModeOff, &mockURLChecker{verdict: verdict})
+
+	if _, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{URL: "https://evil.example"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !*createdActive {
+		t.Error("expected link to be active when screening is off")
+	}
+}
+
+func TestUpdateLink_HeldLinkCannotBeActivated(t *testing.T) {
+	workspaceID := uuid.New()
+	linkID := uuid.New()
+	existing := makeLink(linkID, uuid.New(), workspaceID, "held123")
+	existing.IsActive = false
+
+	updated := false
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) { return existing, nil },
+		updateFn: func(_ context.Context, _ sqlc.UpdateLinkParams) (*models.Link, error) {
+			updated = true
+			return existing, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	flagRepo := newMockLinkFlagRepo()
+	flagRepo.flags[linkID] = &models.LinkFlag{LinkID: linkID, Status: models.LinkFlagPending}
+	svc.flagRepo = flagRepo
+
+	active := true
+	_, err := svc.UpdateLink(context.Background(), linkID, workspaceID, models.UpdateLinkInput{IsActive: &active})
+	if !errors.Is(err, httputil.ErrForbidden) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if updated {
+		t.Error("held link should not be updated")
+	}
+
+	flagRepo.flags[linkID].Status = models.LinkFlagApproved
+	if _, err := svc.UpdateLink(context.Background(), linkID, workspaceID, models.UpdateLinkInput{IsActive: &active}); err != nil {
+		t.Fatalf("approved link should be activatable, got %v", err)
+	}
+}
+
+func TestUpdateLink_FlaggedDestinationDeactivates(t *testing.T) {
+	workspaceID := uuid.New()
+	linkID := uuid.New()
+	existing := makeLink(linkID, uuid.New(), workspaceID, "upd1234")
+
+	var params sqlc.UpdateLinkParams
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) { return existing, nil },
+		updateFn: func(_ context.Context, p sqlc.UpdateLinkParams) (*models.Link, error) {
+			params = p
+			link := *existing
+			link.URL = p.Url.String
+			link.IsActive = p.IsActive.Bool
+			return &link, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.cfg.Safety.Mode = config.SafetyModeFlag
+	svc.urlChecker = &mockURLChecker{verdict: &safety.Verdict{Source: safety.SourceBlocklist, Reason: "listed"}}
+	svc.flagRepo = newMockLinkFlagRepo()
+
+	newURL := "https://evil.example"
+	link, err := svc.UpdateLink(context.Background(), linkID, workspaceID, models.UpdateLinkInput{URL: &newURL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !params.IsActive.Valid || params.IsActive.Bool {
+		t.Errorf("expected link to be deactivated, got %+v", params.IsActive)
+	}
+	if link.Flag == nil {
+		t.Error("expected flag on updated link")
+	}
+}
+
+// urlListChecker matches only the URLs it lists.
+type urlListChecker map[string]*safety.Verdict
+
+func (c urlListChecker) Check(_ context.Context, u string) (*safety.Verdict, error) {
+	return c[u], nil
+}
+
+func TestCreateLink_ScreensFailoverURLs(t *testing.T) {
+	verdict := &safety.Verdict{Source: safety.SourceBlocklist, Reason: "listed"}
+	checker := urlListChecker{"https://evil.example": verdict}
+	input := models.CreateLinkInput{
+		URL:          "https://example.com",
+		FailoverURLs: []string{"https://backup.example.com", "https://evil.example"},
+	}
+
+	svc, flagRepo, createdActive := newScreeningService(t, config.SafetyModeFlag, checker)
+	link, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), input)
+	if err != nil {
+		t.Fatalf("flag mode: unexpected error: %v", err)
+	}
+	if *createdActive {
+		t.Error("expected a link with a flagged failover URL to be created inactive")
+	}
+	flag := flagRepo.flags[link.ID]
+	if flag == nil || flag.URL != "https://evil.example" {
+		t.Errorf("flag = %+v, want one on the failover URL", flag)
+	}
+
+	svc, flagRepo, _ = newScreeningService(t, config.SafetyModeBlock, checker)
+	_, err = svc.CreateLink(context.Background(), uuid.New(), uuid.New(), input)
+	if got := validationField(err); got != "failover_urls[1]" {
+		t.Errorf("block mode: error = %v, want a validation error on failover_urls[1]", err)
+	}
+	if len(flagRepo.flags) != 0 {
+		t.Error("blocked link should not be flagged")
+	}
+}
+
+func TestCreateAndUpdateLink_RejectFailoverLoops(t *testing.T) {
+	svc, _, _ := newScreeningService(t, config.SafetyModeOff, nil)
+	self := "http://localhost:8081/scr1234"
+
+	_, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{
+		URL:          "https://example.com",
+		FailoverURLs: []string{self},
+	})
+	if got := validationField(err); got != "failover_urls[0]" {
+		t.Errorf("create: error = %v, want a validation error on failover_urls[0]", err)
+	}
+
+	wsID := uuid.New()
+	existing := makeLink(uuid.New(), uuid.New(), wsID, "scr1234")
+	svc = newTestService(&mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) { return existing, nil },
+	}, &mockClickRepo{}, &mockCodeGen{})
+	_, err = svc.UpdateLink(context.Background(), existing.ID, wsID, models.UpdateLinkInput{
+		FailoverURLs: []string{"https://example.org", self},
+	})
+	if got := validationField(err); got != "failover_urls[1]" {
+		t.Errorf("update: error = %v, want a validation error on failover_urls[1]", err)
+	}
+}
@@ -2,18 +2,29 @@ package service
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/license"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/safety"
 	"github.com/link-rift/link-rift/pkg/crypto"
 	"github.com/link-rift/link-rift/pkg/httputil"
 	"github.com/link-rift/link-rift/pkg/shortcode"
@@ -21,71 +32,182 @@ import (
 	"go.uber.org/zap"
 )
 
-const maxShortCodeRetries = 5
+// Short code generation defaults, used when not set in config.
+const (
+	defaultShortCodeMaxRetries    = 10
+	defaultShortCodeEscalateAfter = 3
+)
+
+// defaultMetadataRefreshCooldown applies when links.metadata_refresh_cooldown
+// is not set.
+const defaultMetadataRefreshCooldown = time.Minute
+
+// exportPageSize is the number of links fetched per page during workspace exports.
+const exportPageSize = 200
 
 type LinkService interface {
 	CreateLink(ctx context.Context, userID, workspaceID uuid.UUID, input models.CreateLinkInput) (*models.Link, error)
+	ListLinkDomains(ctx context.Context, workspaceID uuid.UUID) ([]*models.Domain, error)
 	UpdateLink(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateLinkInput) (*models.Link, error)
 	DeleteLink(ctx context.Context, id, workspaceID uuid.UUID) error
+	ArchiveLink(ctx context.Context, id, workspaceID uuid.UUID, input models.ArchiveLinkInput) (*models.Link, error)
+	UnarchiveLink(ctx context.Context, id, workspaceID uuid.UUID) (*models.Link, error)
 	GetLink(ctx context.Context, id uuid.UUID) (*models.Link, error)
 	ListLinks(ctx context.Context, workspaceID uuid.UUID, filter models.LinkFilter, pagination models.Pagination) (*models.LinkListResult, error)
 	BulkCreateLinks(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, error)
+	SubmitBulkCreateLinks(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, *models.BulkLinkJob, error)
+	GetBulkLinkJob(ctx context.Context, workspaceID, jobID uuid.UUID) (*models.BulkLinkJob, error)
+	BulkUpdateLinks(ctx context.Context, workspaceID uuid.UUID, input models.BulkUpdateLinksInput) (*models.BulkLinkResult, error)
+	BulkDeleteLinks(ctx context.Context, workspaceID uuid.UUID, input models.BulkDeleteLinksInput) (*models.BulkLinkResult, error)
 	GetQuickStats(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error)
 	CheckShortCodeAvailable(ctx context.Context, code string) (bool, error)
 	VerifyLinkPassword(ctx context.Context, shortCode, password string) (bool, error)
+	ExportWorkspaceLinks(ctx context.Context, workspaceID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat, w io.Writer) error
+	TransferLink(ctx context.Context, linkID, fromWorkspaceID, toWorkspaceID, actorID uuid.UUID) (*models.Link, error)
+	RefreshLinkMetadata(ctx context.Context, id, workspaceID uuid.UUID) (*models.LinkMetadata, error)
+	CheckDestination(ctx context.Context, workspaceID uuid.UUID, rawURL string) (*models.LinkDestinationCheck, error)
+	ReserveShortCode(ctx context.Context, userID, workspaceID uuid.UUID, input models.ReserveLinkInput) (*models.Link, error)
 }
 
 type linkService struct {
-	linkRepo  repository.LinkRepository
-	clickRepo repository.ClickRepository
-	pool      *pgxpool.Pool
-	redis     *redis.Client
-	cfg       *config.Config
-	codeGen   shortcode.Generator
-	events    EventPublisher
-	logger    *zap.Logger
+	linkRepo      repository.LinkRepository
+	clickRepo     repository.ClickRepository
+	analyticsRepo repository.AnalyticsRepository
+	memberRepo    repository.WorkspaceMemberRepository
+	wsRepo        repository.WorkspaceRepository
+	domainRepo    repository.DomainRepository
+	qrService     QRCodeService
+	pageFetcher   PageFetcher
+	destChecker   DestinationChecker
+	urlChecker    safety.Checker
+	flagRepo      repository.LinkFlagRepository
+	commentRepo   repository.LinkCommentRepository
+	templateRepo  repository.LinkTemplateRepository
+	pool          *pgxpool.Pool
+	redis         *redis.Client
+	cfg           *config.Config
+	licManager    *license.Manager
+	codeGen       shortcode.Generator
+	events        EventPublisher
+	logger        *zap.Logger
 }
 
 func NewLinkService(
 	linkRepo repository.LinkRepository,
 	clickRepo repository.ClickRepository,
+	analyticsRepo repository.AnalyticsRepository,
+	memberRepo repository.WorkspaceMemberRepository,
+	wsRepo repository.WorkspaceRepository,
+	domainRepo repository.DomainRepository,
+	qrService QRCodeService,
+	pageFetcher PageFetcher,
+	destChecker DestinationChecker,
+	urlChecker safety.Checker,
+	flagRepo repository.LinkFlagRepository,
+	commentRepo repository.LinkCommentRepository,
+	templateRepo repository.LinkTemplateRepository,
 	pool *pgxpool.Pool,
 	redisClient *redis.Client,
 	cfg *config.Config,
+	licManager *license.Manager,
 	events EventPublisher,
 	logger *zap.Logger,
 ) LinkService {
 	return &linkService{
-		linkRepo:  linkRepo,
-		clickRepo: clickRepo,
-		pool:      pool,
-		redis:     redisClient,
-		cfg:       cfg,
-		codeGen:   shortcode.NewGenerator(),
-		events:    events,
-		logger:    logger,
+		linkRepo:      linkRepo,
+		clickRepo:     clickRepo,
+		analyticsRepo: analyticsRepo,
+		memberRepo:    memberRepo,
+		wsRepo:        wsRepo,
+		domainRepo:    domainRepo,
+		qrService:     qrService,
+		pageFetcher:   pageFetcher,
+		destChecker:   destChecker,
+		urlChecker:    urlChecker,
+		flagRepo:      flagRepo,
+		commentRepo:   commentRepo,
+		templateRepo:  templateRepo,
+		pool:          pool,
+		redis:         redisClient,
+		cfg:           cfg,
+		licManager:    licManager,
+		codeGen:       shortcode.NewGenerator(),
+		events:        events,
+		logger:        logger,
 	}
 }
 
 func (s *linkService) CreateLink(ctx context.Context, userID, workspaceID uuid.UUID, input models.CreateLinkInput) (*models.Link, error) {
+	input, templatePassword, err := s.applyTemplate(ctx, workspaceID, input, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	normalizedURL, err := normalizeURL(input.URL)
 	if err != nil {
 		return nil, httputil.Validation("url", "invalid URL format")
 	}
 
-	// Generate or validate short code
+	if input.Cloak {
+		if err := s.requireCloaking(); err != nil {
+			return nil, err
+		}
+	}
+
+	precedence, err := resolveParamPrecedence(input.ParamPrecedence)
+	if err != nil {
+		return nil, err
+	}
+	repeatVisitURL, err := resolveOptionalURL("repeat_visit_url", input.RepeatVisitURL)
+	if err != nil {
+		return nil, err
+	}
+	deepLinks, err := resolveDeepLinks(input.IOSURL, input.AndroidURL, input.FallbackURL)
+	if err != nil {
+		return nil, err
+	}
+	pixels, err := resolvePixels(input.FacebookPixelID, input.GoogleTagID)
+	if err != nil {
+		return nil, err
+	}
+	if pixels.enabled() {
+		if err := s.requirePixels(); err != nil {
+			return nil, err
+		}
+	}
+	schedule, err := resolveSchedule(input.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	passwordHint, err := resolvePasswordHint(input.PasswordHint, input.Password)
+	if err != nil {
+		return nil, err
+	}
+	failoverURLs, err := resolveFailoverURLs(input.FailoverURLs)
+	if err != nil {
+		return nil, err
+	}
+	domainID, err := s.resolveLinkDomain(ctx, workspaceID, input.DomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate or validate short code. A custom code the workspace has
+	// reserved is claimed.
 	var code string
+	var reserved bool
 	if input.ShortCode != nil && *input.ShortCode != "" {
-		code = *input.ShortCode
-		if !isValidShortCode(code) {
-			return nil, httputil.Validation("short_code", "short code must be 3-50 alphanumeric characters, hyphens, or underscores")
-		}
-		exists, err := s.linkRepo.ShortCodeExists(ctx, code)
+		code = s.cfg.Links.NormalizeShortCode(*input.ShortCode)
+		minLen, maxLen, err := s.shortCodeBounds(ctx, workspaceID)
 		if err != nil {
 			return nil, err
 		}
-		if exists {
-			return nil, httputil.AlreadyExists("short_code")
+		if !shortCodeFits(code, minLen, maxLen) {
+			return nil, httputil.Validation("short_code", shortCodeLengthMessage(minLen, maxLen))
+		}
+		reserved, err = claimableShortCode(ctx, s.linkRepo, s.cfg.Links, workspaceID, code)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		code, err = s.generateUniqueShortCode(ctx)
@@ -94,8 +216,31 @@ func (s *linkService) CreateLink(ctx context.Context, userID, workspaceID uuid.U
 		}
 	}
 
+	// A reservation already counts towards the limit
+	if !reserved {
+		if err := s.checkLinkLimit(ctx, workspaceID, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	verdict, err := s.screenDestination(ctx, normalizedURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRedirectLoop(ctx, code, normalizedURL); err != nil {
+		return nil, err
+	}
+	flaggedURL := normalizedURL
+	failoverFlagged, failoverVerdict, err := s.screenFailoverURLs(ctx, code, failoverURLs)
+	if err != nil {
+		return nil, err
+	}
+	if verdict == nil && failoverVerdict != nil {
+		flaggedURL, verdict = failoverFlagged, failoverVerdict
+	}
+
 	// Hash password if provided
-	var passwordHash pgtype.Text
+	passwordHash := templatePassword
 	if input.Password != nil && *input.Password != "" {
 		hash, err := crypto.HashPassword(*input.Password)
 		if err != nil {
@@ -118,28 +263,74 @@ func (s *linkService) CreateLink(ctx context.Context, userID, workspaceID uuid.U
 	}
 
 	params := sqlc.CreateLinkParams{
-		UserID:       userID,
-		WorkspaceID:  workspaceID,
-		Url:          normalizedURL,
-		ShortCode:    code,
-		Title:        models.OptionalText(input.Title),
-		Description:  models.OptionalText(input.Description),
-		IsActive:     true,
-		PasswordHash: passwordHash,
-		ExpiresAt:    expiresAt,
-		MaxClicks:    models.OptionalInt4(input.MaxClicks),
-		UtmSource:    models.OptionalText(input.UTMSource),
-		UtmMedium:    models.OptionalText(input.UTMMedium),
-		UtmCampaign:  models.OptionalText(input.UTMCampaign),
-		UtmTerm:      models.OptionalText(input.UTMTerm),
-		UtmContent:   models.OptionalText(input.UTMContent),
+		UserID:          userID,
+		WorkspaceID:     workspaceID,
+		DomainID:        domainID,
+		Url:             normalizedURL,
+		ShortCode:       code,
+		Title:           models.OptionalText(input.Title),
+		Description:     models.OptionalText(input.Description),
+		IsActive:        verdict == nil,
+		PasswordHash:    passwordHash,
+		ExpiresAt:       expiresAt,
+		MaxClicks:       models.OptionalInt4(input.MaxClicks),
+		UtmSource:       models.OptionalText(input.UTMSource),
+		UtmMedium:       models.OptionalText(input.UTMMedium),
+		UtmCampaign:     models.OptionalText(input.UTMCampaign),
+		UtmTerm:         models.OptionalText(input.UTMTerm),
+		UtmContent:      models.OptionalText(input.UTMContent),
+		Cloak:           input.Cloak,
+		ForwardParams:   input.ForwardParams,
+		ParamPrecedence: precedence,
+		InternalNote:    models.OptionalText(input.InternalNote),
+		ForceHttps:      input.ForceHTTPS,
+		OncePerVisitor:  input.OncePerVisitor,
+		RepeatVisitUrl:  repeatVisitURL,
+		IosUrl:          deepLinks.ios,
+		AndroidUrl:      deepLinks.android,
+		FallbackUrl:     deepLinks.fallback,
+		FacebookPixelID: pixels.facebook,
+		GoogleTagID:     pixels.google,
+		Interstitial:    input.Interstitial,
+		Schedule:        schedule,
+		PasswordHint:    passwordHint,
+		FailoverUrls:    failoverURLs,
 	}
 
-	link, err := s.linkRepo.Create(ctx, params)
+	adding := int64(1)
+	if reserved {
+		adding = 0
+	}
+	var link *models.Link
+	err = s.withinLinkLimit(ctx, workspaceID, adding, func(linkRepo repository.LinkRepository) error {
+		var err error
+		if reserved {
+			link, err = linkRepo.ClaimReservation(ctx, params)
+		} else {
+			link, err = linkRepo.Create(ctx, params)
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if verdict != nil {
+		if err := s.flagLink(ctx, s.flagRepo, link, flaggedURL, verdict); err != nil {
+			s.logger.Error("failed to flag link for review", zap.String("link_id", link.ID.String()), zap.Error(err))
+		}
+	}
+
+	if input.GenerateQR {
+		s.generateQRForLink(ctx, link, input.QROptions)
+	}
+
+	if input.CheckDestination {
+		if err := s.checkDestinationRate(ctx, workspaceID); err == nil {
+			link.DestinationCheck = s.checkDestination(ctx, normalizedURL)
+		}
+	}
+
 	// Publish webhook event (best-effort)
 	if err := s.events.Publish(ctx, "link.created", workspaceID, link); err != nil {
 		s.logger.Warn("failed to publish link.created event", zap.Error(err))
@@ -148,6 +339,76 @@ func (s *linkService) CreateLink(ctx context.Context, userID, workspaceID uuid.U
 	return link, nil
 }
 
+// applyTemplate fills in the settings of the link template named by
+// input.TemplateID, if any, returning the merged input and the template's
+// password hash when the link should use it. Templates already looked up
+// are taken from cache when it isn't nil.
+func (s *linkService) applyTemplate(ctx context.Context, workspaceID uuid.UUID, input models.CreateLinkInput, cache map[uuid.UUID]*models.LinkTemplate) (models.CreateLinkInput, pgtype.Text, error) {
+	if input.TemplateID == nil {
+		return input, pgtype.Text{}, nil
+	}
+	if s.templateRepo == nil {
+		return input, pgtype.Text{}, httputil.Validation("template_id", "link templates are not available")
+	}
+
+	tmpl, ok := cache[*input.TemplateID]
+	if !ok {
+		var err error
+		tmpl, err = getWorkspaceTemplate(ctx, s.templateRepo, *input.TemplateID, workspaceID)
+		if err != nil {
+			var appErr *httputil.AppError
+			if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+				return input, pgtype.Text{}, httputil.Validation("template_id", "template not found in this workspace")
+			}
+			return input, pgtype.Text{}, err
+		}
+		if cache != nil {
+			cache[tmpl.ID] = tmpl
+		}
+	}
+
+	// The template's domain may have been removed or unverified since it
+	// was saved; say so rather than reporting a domain_id the request
+	// didn't send.
+	if tmpl.Settings.DomainID != nil && input.DomainID == nil && !input.Sent("domain_id") {
+		if _, err := s.resolveLinkDomain(ctx, workspaceID, tmpl.Settings.DomainID); err != nil {
+			var appErr *httputil.AppError
+			if errors.As(err, &appErr) && appErr.Code == "VALIDATION_ERROR" {
+				return input, pgtype.Text{}, httputil.Validation("template_id", "the template's domain is no longer available; update the template or set domain_id")
+			}
+			return input, pgtype.Text{}, err
+		}
+	}
+
+	merged, passwordHash := applyLinkTemplate(tmpl, input, time.Now())
+	return merged, passwordHash, nil
+}
+
+// generateQRForLink creates a QR code for a freshly created link. Failures
+// (including a missing QR customization license) are logged and never fail
+// link creation.
+func (s *linkService) generateQRForLink(ctx context.Context, link *models.Link, opts *models.CreateQRCodeInput) {
+	if s.qrService == nil {
+		return
+	}
+
+	var input models.CreateQRCodeInput
+	if opts != nil {
+		input = *opts
+	}
+
+	qr, err := s.qrService.CreateQRCode(ctx, link.ID, link.WorkspaceID, input)
+	if err != nil {
+		s.logger.Warn("failed to generate QR code for new link",
+			zap.String("link_id", link.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	link.QRCodeURL = qr.PngURL
+}
+
 func (s *linkService) UpdateLink(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateLinkInput) (*models.Link, error) {
 	existing, err := s.linkRepo.GetByID(ctx, id)
 	if err != nil {
@@ -157,15 +418,97 @@ func (s *linkService) UpdateLink(ctx context.Context, id, workspaceID uuid.UUID,
 	if existing.WorkspaceID != workspaceID {
 		return nil, httputil.Forbidden("link does not belong to this workspace")
 	}
+	if err := requireNotReserved(existing); err != nil {
+		return nil, err
+	}
 
-	// If URL is being updated, validate it
+	// If URL is being updated, validate and screen it
 	var urlText pgtype.Text
+	var verdict *safety.Verdict
+	var flaggedURL string
 	if input.URL != nil {
 		normalizedURL, err := normalizeURL(*input.URL)
 		if err != nil {
 			return nil, httputil.Validation("url", "invalid URL format")
 		}
 		urlText = pgtype.Text{String: normalizedURL, Valid: true}
+		if normalizedURL != existing.URL {
+			if err := s.checkRedirectLoop(ctx, existing.ShortCode, normalizedURL); err != nil {
+				return nil, err
+			}
+			verdict, err = s.screenDestination(ctx, normalizedURL)
+			if err != nil {
+				return nil, err
+			}
+			flaggedURL = normalizedURL
+		}
+	}
+	failoverURLs, err := resolveFailoverURLs(input.FailoverURLs)
+	if err != nil {
+		return nil, err
+	}
+	failoverFlagged, failoverVerdict, err := s.screenFailoverURLs(ctx, existing.ShortCode, failoverURLs)
+	if err != nil {
+		return nil, err
+	}
+	if verdict == nil && failoverVerdict != nil {
+		flaggedURL, verdict = failoverFlagged, failoverVerdict
+	}
+
+	// A flagged destination deactivates the link, and a link held for
+	// review can't be turned back on.
+	isActive := models.OptionalBool(input.IsActive)
+	if verdict != nil {
+		isActive = pgtype.Bool{Bool: false, Valid: true}
+	} else if input.IsActive != nil && *input.IsActive {
+		if err := s.requireNotHeld(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Cloak != nil && *input.Cloak {
+		if err := s.requireCloaking(); err != nil {
+			return nil, err
+		}
+	}
+
+	var precedence pgtype.Text
+	if input.ParamPrecedence != nil {
+		p, err := resolveParamPrecedence(input.ParamPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		precedence = pgtype.Text{String: p, Valid: true}
+	}
+	repeatVisitURL, err := resolveOptionalURL("repeat_visit_url", input.RepeatVisitURL)
+	if err != nil {
+		return nil, err
+	}
+	deepLinks, err := resolveDeepLinks(input.IOSURL, input.AndroidURL, input.FallbackURL)
+	if err != nil {
+		return nil, err
+	}
+	// Pixels can be removed without the feature, e.g. after a downgrade.
+	pixels, err := resolvePixels(input.FacebookPixelID, input.GoogleTagID)
+	if err != nil {
+		return nil, err
+	}
+	if pixels.enabled() {
+		if err := s.requirePixels(); err != nil {
+			return nil, err
+		}
+	}
+	schedule, err := resolveSchedule(input.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHint, err := resolvePasswordHint(input.PasswordHint, input.Password)
+	if err != nil {
+		return nil, err
+	}
+	if input.Password != nil && *input.Password == "" {
+		passwordHint = pgtype.Text{String: "", Valid: true}
 	}
 
 	// Hash password if being updated
@@ -198,14 +541,30 @@ func (s *linkService) UpdateLink(ctx context.Context, id, workspaceID uuid.UUID,
 	}
 
 	params := sqlc.UpdateLinkParams{
-		ID:           id,
-		Title:        models.OptionalText(input.Title),
-		Description:  models.OptionalText(input.Description),
-		Url:          urlText,
-		IsActive:     models.OptionalBool(input.IsActive),
-		PasswordHash: passwordHash,
-		ExpiresAt:    expiresAt,
-		MaxClicks:    models.OptionalInt4(input.MaxClicks),
+		ID:              id,
+		Title:           models.OptionalText(input.Title),
+		Description:     models.OptionalText(input.Description),
+		Url:             urlText,
+		IsActive:        isActive,
+		PasswordHash:    passwordHash,
+		ExpiresAt:       expiresAt,
+		MaxClicks:       models.OptionalInt4(input.MaxClicks),
+		Cloak:           models.OptionalBool(input.Cloak),
+		ForwardParams:   models.OptionalBool(input.ForwardParams),
+		ParamPrecedence: precedence,
+		InternalNote:    models.OptionalText(input.InternalNote),
+		ForceHttps:      models.OptionalBool(input.ForceHTTPS),
+		OncePerVisitor:  models.OptionalBool(input.OncePerVisitor),
+		RepeatVisitUrl:  repeatVisitURL,
+		IosUrl:          deepLinks.ios,
+		AndroidUrl:      deepLinks.android,
+		FallbackUrl:     deepLinks.fallback,
+		FacebookPixelID: pixels.facebook,
+		GoogleTagID:     pixels.google,
+		Interstitial:    models.OptionalBool(input.Interstitial),
+		Schedule:        schedule,
+		PasswordHint:    passwordHint,
+		FailoverUrls:    failoverURLs,
 	}
 
 	link, err := s.linkRepo.Update(ctx, params)
@@ -213,6 +572,12 @@ func (s *linkService) UpdateLink(ctx context.Context, id, workspaceID uuid.UUID,
 		return nil, err
 	}
 
+	if verdict != nil {
+		if err := s.flagLink(ctx, s.flagRepo, link, flaggedURL, verdict); err != nil {
+			s.logger.Error("failed to flag link for review", zap.String("link_id", link.ID.String()), zap.Error(err))
+		}
+	}
+
 	// Publish webhook event (best-effort)
 	if err := s.events.Publish(ctx, "link.updated", workspaceID, link); err != nil {
 		s.logger.Warn("failed to publish link.updated event", zap.Error(err))
@@ -243,150 +608,1081 @@ func (s *linkService) DeleteLink(ctx context.Context, id, workspaceID uuid.UUID)
 	return nil
 }
 
-func (s *linkService) GetLink(ctx context.Context, id uuid.UUID) (*models.Link, error) {
-	return s.linkRepo.GetByID(ctx, id)
-}
+// ArchiveLink hides a link from the default link list while keeping its
+// history. The link keeps redirecting unless input.Deactivate is set.
+func (s *linkService) ArchiveLink(ctx context.Context, id, workspaceID uuid.UUID, input models.ArchiveLinkInput) (*models.Link, error) {
+	existing, err := s.linkRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *linkService) ListLinks(ctx context.Context, workspaceID uuid.UUID, filter models.LinkFilter, pagination models.Pagination) (*models.LinkListResult, error) {
-	if pagination.Limit == 0 {
-		pagination.Limit = 20
+	if existing.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("link does not belong to this workspace")
 	}
 
-	params := sqlc.ListLinksForWorkspaceParams{
-		WorkspaceID: workspaceID,
-		Limit:       int32(pagination.Limit),
-		Offset:      int32(pagination.Offset),
-		Search:      models.OptionalText(filter.Search),
+	if existing.IsArchived() && (!input.Deactivate || !existing.IsActive) {
+		return existing, nil
 	}
 
-	links, total, err := s.linkRepo.List(ctx, params)
+	link, err := s.linkRepo.Archive(ctx, id, input.Deactivate)
 	if err != nil {
 		return nil, err
 	}
 
-	redirectBaseURL := s.cfg.App.RedirectURL
-	responses := make([]*models.LinkResponse, 0, len(links))
-	for _, link := range links {
-		responses = append(responses, link.ToResponse(redirectBaseURL))
+	// Publish webhook event (best-effort)
+	if err := s.events.Publish(ctx, "link.updated", workspaceID, link); err != nil {
+		s.logger.Warn("failed to publish link.updated event", zap.Error(err))
 	}
 
-	return &models.LinkListResult{
-		Links: responses,
-		Total: total,
-	}, nil
+	return link, nil
 }
 
-func (s *linkService) BulkCreateLinks(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, error) {
-	tx, err := s.pool.Begin(ctx)
+// UnarchiveLink returns an archived link to the default link list. A link
+// deactivated when it was archived stays inactive until it is re-enabled.
+func (s *linkService) UnarchiveLink(ctx context.Context, id, workspaceID uuid.UUID) (*models.Link, error) {
+	existing, err := s.linkRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, httputil.Wrap(err, "failed to begin transaction")
+		return nil, err
 	}
-	defer tx.Rollback(ctx)
-
-	qtx := sqlc.New(tx)
-	txLinkRepo := repository.NewLinkRepository(qtx, s.logger)
-
-	links := make([]*models.Link, 0, len(input.Links))
-	for i, linkInput := range input.Links {
-		normalizedURL, err := normalizeURL(linkInput.URL)
-		if err != nil {
-			return nil, httputil.Validation("url", "invalid URL at index "+string(rune('0'+i)))
-		}
-
-		var code string
-		if linkInput.ShortCode != nil && *linkInput.ShortCode != "" {
-			code = *linkInput.ShortCode
-		} else {
-			code, err = s.generateUniqueShortCode(ctx)
-			if err != nil {
-				return nil, err
-			}
-		}
 
-		var passwordHash pgtype.Text
-		if linkInput.Password != nil && *linkInput.Password != "" {
-			hash, err := crypto.HashPassword(*linkInput.Password)
-			if err != nil {
-				return nil, httputil.Wrap(err, "failed to hash password")
-			}
-			passwordHash = pgtype.Text{String: hash, Valid: true}
-		}
+	if existing.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("link does not belong to this workspace")
+	}
 
-		var expiresAt pgtype.Timestamptz
-		if linkInput.ExpiresAt != nil && *linkInput.ExpiresAt != "" {
-			t, err := time.Parse(time.RFC3339, *linkInput.ExpiresAt)
-			if err != nil {
-				return nil, httputil.Validation("expires_at", "invalid date format at index "+string(rune('0'+i)))
-			}
-			expiresAt = pgtype.Timestamptz{Time: t, Valid: true}
-		}
+	if !existing.IsArchived() {
+		return existing, nil
+	}
 
-		params := sqlc.CreateLinkParams{
-			UserID:       userID,
-			WorkspaceID:  workspaceID,
-			Url:          normalizedURL,
-			ShortCode:    code,
-			Title:        models.OptionalText(linkInput.Title),
-			Description:  models.OptionalText(linkInput.Description),
-			IsActive:     true,
-			PasswordHash: passwordHash,
-			ExpiresAt:    expiresAt,
-			MaxClicks:    models.OptionalInt4(linkInput.MaxClicks),
-			UtmSource:    models.OptionalText(linkInput.UTMSource),
-			UtmMedium:    models.OptionalText(linkInput.UTMMedium),
-			UtmCampaign:  models.OptionalText(linkInput.UTMCampaign),
-			UtmTerm:      models.OptionalText(linkInput.UTMTerm),
-			UtmContent:   models.OptionalText(linkInput.UTMContent),
-		}
-
-		link, err := txLinkRepo.Create(ctx, params)
-		if err != nil {
-			return nil, err
-		}
-		links = append(links, link)
+	link, err := s.linkRepo.Unarchive(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, httputil.Wrap(err, "failed to commit transaction")
+	// Publish webhook event (best-effort)
+	if err := s.events.Publish(ctx, "link.updated", workspaceID, link); err != nil {
+		s.logger.Warn("failed to publish link.updated event", zap.Error(err))
 	}
 
-	return links, nil
+	return link, nil
 }
 
-func (s *linkService) GetQuickStats(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error) {
-	return s.linkRepo.GetQuickStats(ctx, id)
-}
+// TransferLink moves a link from one workspace to another, keeping its short
+// code, click history and analytics. The actor must be an editor in both
+// workspaces, and the destination must have room under its link limit. A
+// custom domain binding is only kept if the domain belongs to the destination.
+func (s *linkService) TransferLink(ctx context.Context, linkID, fromWorkspaceID, toWorkspaceID, actorID uuid.UUID) (*models.Link, error) {
+	if fromWorkspaceID == toWorkspaceID {
+		return nil, httputil.Validation("workspace_id", "link already belongs to this workspace")
+	}
 
-func (s *linkService) CheckShortCodeAvailable(ctx context.Context, code string) (bool, error) {
-	exists, err := s.linkRepo.ShortCodeExists(ctx, code)
+	existing, err := s.linkRepo.GetByID(ctx, linkID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return !exists, nil
-}
 
-func (s *linkService) VerifyLinkPassword(ctx context.Context, shortCode, password string) (bool, error) {
-	link, err := s.linkRepo.GetByShortCode(ctx, shortCode)
-	if err != nil {
-		return false, err
+	if existing.WorkspaceID != fromWorkspaceID {
+		return nil, httputil.Forbidden("link does not belong to this workspace")
+	}
+	if err := requireNotReserved(existing); err != nil {
+		return nil, err
 	}
 
-	if link.PasswordHash == nil {
-		return true, nil
+	if err := s.requireMemberPermission(ctx, fromWorkspaceID, actorID, models.PermissionDeleteLinks); err != nil {
+		return nil, err
+	}
+	if err := s.requireMemberPermission(ctx, toWorkspaceID, actorID, models.PermissionCreateLinks); err != nil {
+		return nil, err
 	}
 
-	match, err := crypto.VerifyPassword(password, *link.PasswordHash)
-	if err != nil {
-		return false, httputil.Wrap(err, "failed to verify password")
+	// The link only counts towards the destination's limit if it was
+	// created in the current usage period
+	var adding int64
+	if start, end := UsagePeriod(time.Now()); !existing.CreatedAt.Before(start) && existing.CreatedAt.Before(end) {
+		adding = 1
+	}
+	if err := s.checkLinkLimit(ctx, toWorkspaceID, adding); err != nil {
+		return nil, err
 	}
-	return match, nil
-}
 
-func (s *linkService) generateUniqueShortCode(ctx context.Context) (string, error) {
-	for i := 0; i < maxShortCodeRetries; i++ {
-		code := s.codeGen.Generate()
-		exists, err := s.linkRepo.ShortCodeExists(ctx, code)
-		if err != nil {
+	var domainID pgtype.UUID
+	if existing.DomainID != nil {
+		domain, err := s.domainRepo.GetByID(ctx, *existing.DomainID)
+		if err == nil && domain.WorkspaceID == toWorkspaceID {
+			domainID = pgtype.UUID{Bytes: *existing.DomainID, Valid: true}
+		}
+	}
+
+	var link *models.Link
+	err = s.withinLinkLimit(ctx, toWorkspaceID, adding, func(linkRepo repository.LinkRepository) error {
+		var err error
+		link, err = linkRepo.Transfer(ctx, sqlc.TransferLinkParams{
+			ID:          linkID,
+			WorkspaceID: toWorkspaceID,
+			DomainID:    domainID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish webhook events to both workspaces (best-effort)
+	payload := map[string]interface{}{
+		"link":              link,
+		"from_workspace_id": fromWorkspaceID,
+		"to_workspace_id":   toWorkspaceID,
+		"transferred_by":    actorID,
+	}
+	for _, wsID := range []uuid.UUID{fromWorkspaceID, toWorkspaceID} {
+		if err := s.events.Publish(ctx, "link.transferred", wsID, payload); err != nil {
+			s.logger.Warn("failed to publish link.transferred event", zap.Error(err))
+		}
+	}
+
+	return link, nil
+}
+
+// requireMemberPermission checks that userID is a member of workspaceID with
+// at least the role required for perm.
+func (s *linkService) requireMemberPermission(ctx context.Context, workspaceID, userID uuid.UUID, perm models.Permission) error {
+	member, err := s.memberRepo.Get(ctx, workspaceID, userID)
+	if err != nil {
+		var appErr *httputil.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+			return httputil.Forbidden("you are not a member of this workspace")
+		}
+		return err
+	}
+	if !models.CheckPermission(member.Role, perm) {
+		return httputil.Forbidden("insufficient workspace permissions")
+	}
+	return nil
+}
+
+// checkLinkLimit returns PAYMENT_REQUIRED if adding the given number of links
+// would exceed the workspace's licensed link limit. It fails early, before
+// any work; the limit is enforced by withinLinkLimit or enforceLinkLimit.
+func (s *linkService) checkLinkLimit(ctx context.Context, workspaceID uuid.UUID, adding int64) error {
+	return checkLinkLimit(ctx, s.licManager, s.linkRepo, workspaceID, adding)
+}
+
+// withinLinkLimit runs insert, which adds the given number of links to the
+// workspace, in a transaction that first enforces the link limit. Without a
+// database pool the limit is only checked.
+func (s *linkService) withinLinkLimit(ctx context.Context, workspaceID uuid.UUID, adding int64, insert func(linkRepo repository.LinkRepository) error) error {
+	if s.pool == nil || adding <= 0 || s.licManager.GetLimits().GetLimit(license.LimitMaxLinksPerMonth) < 0 {
+		if err := checkLinkLimit(ctx, s.licManager, s.linkRepo, workspaceID, adding); err != nil {
+			return err
+		}
+		return insert(s.linkRepo)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return httputil.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	txLinkRepo := repository.NewLinkRepository(sqlc.New(tx), s.logger)
+	if err := enforceLinkLimit(ctx, s.licManager, txLinkRepo, workspaceID, adding); err != nil {
+		return err
+	}
+	if err := insert(txLinkRepo); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return httputil.Wrap(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// checkLinkLimit returns PAYMENT_REQUIRED if adding the given number of links
+// would take the workspace past its licensed links per month: the links it
+// created in the current usage period, as counted for its usage.
+func checkLinkLimit(ctx context.Context, licManager *license.Manager, linkRepo repository.LinkRepository, workspaceID uuid.UUID, adding int64) error {
+	limit := licManager.GetLimits().GetLimit(license.LimitMaxLinksPerMonth)
+	if limit < 0 || adding <= 0 {
+		return nil
+	}
+
+	start, end := UsagePeriod(time.Now())
+	count, err := linkRepo.CountCreatedForWorkspace(ctx, workspaceID, start, end)
+	if err != nil {
+		return err
+	}
+
+	if count+adding > limit {
+		return httputil.PaymentRequired("link limit reached, upgrade your plan for more links")
+	}
+	return nil
+}
+
+// enforceLinkLimit checks the link limit inside the transaction of txLinkRepo
+// that adds the links, holding the workspace's link limit lock until it ends
+// so that concurrent requests can't all pass the check.
+func enforceLinkLimit(ctx context.Context, licManager *license.Manager, txLinkRepo repository.LinkRepository, workspaceID uuid.UUID, adding int64) error {
+	if adding <= 0 || licManager.GetLimits().GetLimit(license.LimitMaxLinksPerMonth) < 0 {
+		return nil
+	}
+	if err := txLinkRepo.LockLinkLimit(ctx, workspaceID); err != nil {
+		return err
+	}
+	return checkLinkLimit(ctx, licManager, txLinkRepo, workspaceID, adding)
+}
+
+// resolveParamPrecedence validates an optional precedence, defaulting to
+// the destination's own parameters winning.
+func resolveParamPrecedence(p *string) (string, error) {
+	if p == nil || *p == "" {
+		return models.ParamPrecedenceDestination, nil
+	}
+	if !models.IsValidParamPrecedence(*p) {
+		return "", httputil.Validation("param_precedence", "must be \"destination\" or \"incoming\"")
+	}
+	return *p, nil
+}
+
+// resolveOptionalURL normalizes an optional web URL. An empty value is kept
+// so that an update can clear it.
+func resolveOptionalURL(field string, u *string) (pgtype.Text, error) {
+	if u == nil {
+		return pgtype.Text{}, nil
+	}
+	if strings.TrimSpace(*u) == "" {
+		return pgtype.Text{String: "", Valid: true}, nil
+	}
+	normalized, err := normalizeURL(*u)
+	if err != nil {
+		return pgtype.Text{}, httputil.Validation(field, "invalid URL format")
+	}
+	return pgtype.Text{String: normalized, Valid: true}, nil
+}
+
+// resolveFailoverURLs normalizes a link's backup destinations. A nil list
+// is kept as nil so that an update leaves them alone, and an empty one as
+// empty so that it can remove them.
+func resolveFailoverURLs(urls []string) ([]string, error) {
+	if urls == nil {
+		return nil, nil
+	}
+	if len(urls) > models.MaxFailoverURLs {
+		return nil, httputil.Validation("failover_urls", fmt.Sprintf("at most %d URLs", models.MaxFailoverURLs))
+	}
+	normalized := make([]string, 0, len(urls))
+	for i, u := range urls {
+		field := fmt.Sprintf("failover_urls[%d]", i)
+		n, err := normalizeURL(u)
+		if err != nil {
+			return nil, httputil.Validation(field, "invalid URL format")
+		}
+		if slices.Contains(normalized, n) {
+			return nil, httputil.Validation(field, "duplicates another failover URL")
+		}
+		normalized = append(normalized, n)
+	}
+	return normalized, nil
+}
+
+// resolvePasswordHint cleans up an optional password hint: control and
+// invisible formatting characters are dropped and runs of whitespace become
+// one space. Hints are plain text, so markup is refused rather than stored.
+// An empty value is kept so that an update can clear it. password, when
+// given, may not appear in the hint.
+func resolvePasswordHint(hint, password *string) (pgtype.Text, error) {
+	if hint == nil {
+		return pgtype.Text{}, nil
+	}
+	cleaned := strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r):
+			return ' '
+		case unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, *hint)), " ")
+	if cleaned == "" {
+		return pgtype.Text{String: "", Valid: true}, nil
+	}
+
+	if utf8.RuneCountInString(cleaned) > models.MaxPasswordHintLength {
+		return pgtype.Text{}, httputil.Validation("password_hint", fmt.Sprintf("must be at most %d characters", models.MaxPasswordHintLength))
+	}
+	if strings.ContainsAny(cleaned, "<>") {
+		return pgtype.Text{}, httputil.Validation("password_hint", "must not contain < or >")
+	}
+	if password != nil && *password != "" && strings.Contains(strings.ToLower(cleaned), strings.ToLower(*password)) {
+		return pgtype.Text{}, httputil.Validation("password_hint", "must not contain the password")
+	}
+	return pgtype.Text{String: cleaned, Valid: true}, nil
+}
+
+// unsafeAppSchemes can run script or read local files when opened from the
+// deep link page, so they are never accepted as app URIs.
+var unsafeAppSchemes = map[string]bool{
+	"javascript": true,
+	"vbscript":   true,
+	"data":       true,
+	"file":       true,
+	"blob":       true,
+	"about":      true,
+}
+
+type deepLinkURLs struct {
+	ios, android, fallback pgtype.Text
+}
+
+// resolveDeepLinks validates a link's optional app URIs and web fallback.
+func resolveDeepLinks(ios, android, fallback *string) (deepLinkURLs, error) {
+	var urls deepLinkURLs
+	var err error
+	if urls.ios, err = resolveAppURL("ios_url", ios); err != nil {
+		return urls, err
+	}
+	if urls.android, err = resolveAppURL("android_url", android); err != nil {
+		return urls, err
+	}
+	if urls.fallback, err = resolveOptionalURL("fallback_url", fallback); err != nil {
+		return urls, err
+	}
+	return urls, nil
+}
+
+// resolveAppURL checks an optional app URI, which may use a custom scheme
+// such as myapp://product/123. An empty value is kept so that an update can
+// clear it.
+func resolveAppURL(field string, u *string) (pgtype.Text, error) {
+	if u == nil {
+		return pgtype.Text{}, nil
+	}
+	raw := strings.TrimSpace(*u)
+	if raw == "" {
+		return pgtype.Text{String: "", Valid: true}, nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" || unsafeAppSchemes[strings.ToLower(parsed.Scheme)] {
+		return pgtype.Text{}, httputil.Validation(field, "must be an app URI such as myapp://path")
+	}
+	if (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host == "" {
+		return pgtype.Text{}, httputil.Validation(field, "missing host")
+	}
+	return pgtype.Text{String: raw, Valid: true}, nil
+}
+
+// Pixel IDs are written into the redirect interstitial's scripts, so only
+// the formats the ad platforms issue are accepted.
+var (
+	facebookPixelIDRegex = regexp.MustCompile(`^[0-9]{6,20}$`)
+	googleTagIDRegex     = regexp.MustCompile(`^(AW|G|DC)-[A-Z0-9]{4,20}$`)
+)
+
+type retargetingPixels struct {
+	facebook, google pgtype.Text
+}
+
+// enabled reports whether any pixel is being set, as opposed to left alone
+// or cleared.
+func (p retargetingPixels) enabled() bool {
+	return p.facebook.String != "" || p.google.String != ""
+}
+
+// resolvePixels validates a link's optional retargeting pixel IDs. Empty
+// values are kept so that an update can clear them.
+func resolvePixels(facebook, google *string) (retargetingPixels, error) {
+	var pixels retargetingPixels
+	if facebook != nil {
+		id := strings.TrimSpace(*facebook)
+		if id != "" && !facebookPixelIDRegex.MatchString(id) {
+			return pixels, httputil.Validation("facebook_pixel_id", "must be a numeric pixel ID")
+		}
+		pixels.facebook = pgtype.Text{String: id, Valid: true}
+	}
+	if google != nil {
+		id := strings.ToUpper(strings.TrimSpace(*google))
+		if id != "" && !googleTagIDRegex.MatchString(id) {
+			return pixels, httputil.Validation("google_tag_id", "must be a tag ID such as AW-123456789")
+		}
+		pixels.google = pgtype.Text{String: id, Valid: true}
+	}
+	return pixels, nil
+}
+
+// resolveSchedule validates a link's optional schedule and encodes it for
+// storage. A schedule without windows encodes as JSON null so that an
+// update can clear it.
+func resolveSchedule(schedule *models.LinkSchedule) ([]byte, error) {
+	if schedule == nil {
+		return nil, nil
+	}
+	if len(schedule.Windows) == 0 {
+		return []byte("null"), nil
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, httputil.Validation("schedule", err.Error())
+	}
+	normalized := *schedule
+	if strings.TrimSpace(normalized.FallbackURL) != "" {
+		u, err := normalizeURL(normalized.FallbackURL)
+		if err != nil {
+			return nil, httputil.Validation("schedule.fallback_url", "invalid URL format")
+		}
+		normalized.FallbackURL = u
+	} else {
+		normalized.FallbackURL = ""
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to encode schedule")
+	}
+	return data, nil
+}
+
+func (s *linkService) requirePixels() error {
+	return requirePixelsLicense(s.licManager)
+}
+
+func (s *linkService) requireCloaking() error {
+	return requireCloakingLicense(s.licManager)
+}
+
+func requirePixelsLicense(licManager *license.Manager) error {
+	if !licManager.HasFeature(license.FeatureRetargetingPixels) {
+		return httputil.PaymentRequiredWithDetails("retargeting_pixels", "business")
+	}
+	return nil
+}
+
+func requireCloakingLicense(licManager *license.Manager) error {
+	if !licManager.HasFeature(license.FeatureLinkCloaking) {
+		return httputil.PaymentRequiredWithDetails("link_cloaking", "pro")
+	}
+	return nil
+}
+
+func (s *linkService) GetLink(ctx context.Context, id uuid.UUID) (*models.Link, error) {
+	link, err := s.linkRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.commentRepo != nil {
+		count, err := s.commentRepo.CountForLink(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		link.CommentCount = &count
+	}
+	return link, nil
+}
+
+func (s *linkService) ListLinks(ctx context.Context, workspaceID uuid.UUID, filter models.LinkFilter, pagination models.Pagination) (*models.LinkListResult, error) {
+	if pagination.Limit == 0 {
+		pagination.Limit = 20
+	}
+
+	var archived pgtype.Bool
+	switch filter.Status {
+	case "", models.LinkStatusActive:
+		archived = pgtype.Bool{Bool: false, Valid: true}
+	case models.LinkStatusArchived:
+		archived = pgtype.Bool{Bool: true, Valid: true}
+	case models.LinkStatusAll:
+	default:
+		return nil, httputil.Validation("status", "must be \"active\", \"archived\" or \"all\"")
+	}
+
+	params := sqlc.ListLinksForWorkspaceParams{
+		WorkspaceID: workspaceID,
+		Limit:       int32(pagination.Limit),
+		Offset:      int32(pagination.Offset),
+		Search:      models.OptionalText(filter.Search),
+		Archived:    archived,
+	}
+
+	links, total, err := s.linkRepo.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	redirectBaseURL := s.cfg.App.RedirectURL
+	responses := make([]*models.LinkResponse, 0, len(links))
+	for _, link := range links {
+		responses = append(responses, link.ToResponse(redirectBaseURL))
+	}
+	s.addLastClicked(ctx, responses)
+
+	if filter.IncludeQR && s.qrService != nil {
+		for _, resp := range responses {
+			dataURI, err := s.qrService.InlineDataURI(resp.ShortURL)
+			if err != nil {
+				s.logger.Warn("failed to generate inline QR code",
+					zap.String("link_id", resp.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			resp.QRCodeDataURI = &dataURI
+		}
+	}
+
+	return &models.LinkListResult{
+		Links: responses,
+		Total: total,
+	}, nil
+}
+
+func (s *linkService) BulkCreateLinks(ctx context.Context, userID, workspaceID uuid.UUID, input models.BulkCreateLinkInput) ([]*models.Link, error) {
+	// The whole batch counts against the remaining quota
+	if err := s.checkLinkLimit(ctx, workspaceID, int64(len(input.Links))); err != nil {
+		return nil, err
+	}
+
+	// Without a database pool the links are created outside a transaction
+	txLinkRepo, txFlagRepo := s.linkRepo, s.flagRepo
+	var tx pgx.Tx
+	if s.pool != nil {
+		var err error
+		tx, err = s.pool.Begin(ctx)
+		if err != nil {
+			return nil, httputil.Wrap(err, "failed to begin transaction")
+		}
+		defer tx.Rollback(ctx)
+
+		qtx := sqlc.New(tx)
+		txLinkRepo = repository.NewLinkRepository(qtx, s.logger)
+		txFlagRepo = repository.NewLinkFlagRepository(qtx, s.logger)
+	}
+
+	if err := enforceLinkLimit(ctx, s.licManager, txLinkRepo, workspaceID, int64(len(input.Links))); err != nil {
+		return nil, err
+	}
+
+	minLen, maxLen, err := s.shortCodeBounds(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[uuid.UUID]*models.LinkTemplate)
+	links := make([]*models.Link, 0, len(input.Links))
+	for i, linkInput := range input.Links {
+		linkInput, templatePassword, err := s.applyTemplate(ctx, workspaceID, linkInput, templates)
+		if err != nil {
+			return nil, err
+		}
+
+		normalizedURL, err := normalizeURL(linkInput.URL)
+		if err != nil {
+			return nil, httputil.Validation("url", fmt.Sprintf("invalid URL at index %d", i))
+		}
+		if linkInput.Cloak {
+			if err := s.requireCloaking(); err != nil {
+				return nil, err
+			}
+		}
+		precedence, err := resolveParamPrecedence(linkInput.ParamPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		repeatVisitURL, err := resolveOptionalURL("repeat_visit_url", linkInput.RepeatVisitURL)
+		if err != nil {
+			return nil, err
+		}
+		deepLinks, err := resolveDeepLinks(linkInput.IOSURL, linkInput.AndroidURL, linkInput.FallbackURL)
+		if err != nil {
+			return nil, err
+		}
+		pixels, err := resolvePixels(linkInput.FacebookPixelID, linkInput.GoogleTagID)
+		if err != nil {
+			return nil, err
+		}
+		schedule, err := resolveSchedule(linkInput.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		passwordHint, err := resolvePasswordHint(linkInput.PasswordHint, linkInput.Password)
+		if err != nil {
+			return nil, err
+		}
+		failoverURLs, err := resolveFailoverURLs(linkInput.FailoverURLs)
+		if err != nil {
+			return nil, err
+		}
+		if pixels.enabled() {
+			if err := s.requirePixels(); err != nil {
+				return nil, err
+			}
+		}
+		domainID, err := s.resolveLinkDomain(ctx, workspaceID, linkInput.DomainID)
+		if err != nil {
+			return nil, err
+		}
+		verdict, err := s.screenDestination(ctx, normalizedURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var code string
+		var reserved bool
+		if linkInput.ShortCode != nil && *linkInput.ShortCode != "" {
+			code = s.cfg.Links.NormalizeShortCode(*linkInput.ShortCode)
+			if !shortCodeFits(code, minLen, maxLen) {
+				return nil, httputil.Validation("short_code", fmt.Sprintf("link %d: %s", i, shortCodeLengthMessage(minLen, maxLen)))
+			}
+			reserved, err = claimableShortCode(ctx, txLinkRepo, s.cfg.Links, workspaceID, code)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			code, err = s.generateUniqueShortCode(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := s.checkRedirectLoop(ctx, code, normalizedURL); err != nil {
+			return nil, err
+		}
+		flaggedURL := normalizedURL
+		failoverFlagged, failoverVerdict, err := s.screenFailoverURLs(ctx, code, failoverURLs)
+		if err != nil {
+			return nil, err
+		}
+		if verdict == nil && failoverVerdict != nil {
+			flaggedURL, verdict = failoverFlagged, failoverVerdict
+		}
+
+		passwordHash := templatePassword
+		if linkInput.Password != nil && *linkInput.Password != "" {
+			hash, err := crypto.HashPassword(*linkInput.Password)
+			if err != nil {
+				return nil, httputil.Wrap(err, "failed to hash password")
+			}
+			passwordHash = pgtype.Text{String: hash, Valid: true}
+		}
+
+		var expiresAt pgtype.Timestamptz
+		if linkInput.ExpiresAt != nil && *linkInput.ExpiresAt != "" {
+			t, err := time.Parse(time.RFC3339, *linkInput.ExpiresAt)
+			if err != nil {
+				return nil, httputil.Validation("expires_at", fmt.Sprintf("invalid date format at index %d", i))
+			}
+			expiresAt = pgtype.Timestamptz{Time: t, Valid: true}
+		}
+
+		params := sqlc.CreateLinkParams{
+			UserID:          userID,
+			WorkspaceID:     workspaceID,
+			DomainID:        domainID,
+			Url:             normalizedURL,
+			ShortCode:       code,
+			Title:           models.OptionalText(linkInput.Title),
+			Description:     models.OptionalText(linkInput.Description),
+			IsActive:        verdict == nil,
+			PasswordHash:    passwordHash,
+			ExpiresAt:       expiresAt,
+			MaxClicks:       models.OptionalInt4(linkInput.MaxClicks),
+			UtmSource:       models.OptionalText(linkInput.UTMSource),
+			UtmMedium:       models.OptionalText(linkInput.UTMMedium),
+			UtmCampaign:     models.OptionalText(linkInput.UTMCampaign),
+			UtmTerm:         models.OptionalText(linkInput.UTMTerm),
+			UtmContent:      models.OptionalText(linkInput.UTMContent),
+			Cloak:           linkInput.Cloak,
+			ForwardParams:   linkInput.ForwardParams,
+			ParamPrecedence: precedence,
+			InternalNote:    models.OptionalText(linkInput.InternalNote),
+			ForceHttps:      linkInput.ForceHTTPS,
+			OncePerVisitor:  linkInput.OncePerVisitor,
+			RepeatVisitUrl:  repeatVisitURL,
+			IosUrl:          deepLinks.ios,
+			AndroidUrl:      deepLinks.android,
+			FallbackUrl:     deepLinks.fallback,
+			FacebookPixelID: pixels.facebook,
+			GoogleTagID:     pixels.google,
+			Interstitial:    linkInput.Interstitial,
+			Schedule:        schedule,
+			PasswordHint:    passwordHint,
+			FailoverUrls:    failoverURLs,
+		}
+
+		var link *models.Link
+		if reserved {
+			link, err = txLinkRepo.ClaimReservation(ctx, params)
+		} else {
+			link, err = txLinkRepo.Create(ctx, params)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if verdict != nil {
+			if err := s.flagLink(ctx, txFlagRepo, link, flaggedURL, verdict); err != nil {
+				return nil, err
+			}
+		}
+		links = append(links, link)
+	}
+
+	if tx != nil {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, httputil.Wrap(err, "failed to commit transaction")
+		}
+	}
+
+	return links, nil
+}
+
+// BulkUpdateLinks applies the same changes to every requested link in the
+// workspace inside one transaction. Links that don't exist or belong to
+// another workspace are skipped and reported in the result.
+func (s *linkService) BulkUpdateLinks(ctx context.Context, workspaceID uuid.UUID, input models.BulkUpdateLinksInput) (*models.BulkLinkResult, error) {
+	if input.IsActive == nil && input.ExpiresAt == nil && len(input.AddTagIDs) == 0 {
+		return nil, httputil.Validation("body", "no changes specified")
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if input.ExpiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *input.ExpiresAt)
+		if err != nil {
+			return nil, httputil.Validation("expires_at", "invalid date format, use RFC3339")
+		}
+		expiresAt = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	tagIDs := uniqueIDs(input.AddTagIDs)
+	if len(tagIDs) > 0 {
+		count, err := s.linkRepo.CountWorkspaceTags(ctx, workspaceID, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+		if count != int64(len(tagIDs)) {
+			return nil, httputil.Validation("add_tag_ids", "one or more tags do not exist in this workspace")
+		}
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	txLinkRepo := repository.NewLinkRepository(sqlc.New(tx), s.logger)
+
+	owned, result, err := partitionOwnedLinks(ctx, txLinkRepo, workspaceID, input.LinkIDs)
+	if err != nil {
+		return nil, err
+	}
+	if input.IsActive != nil && *input.IsActive {
+		owned, err = s.skipHeldLinks(ctx, owned, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updated := make([]*models.Link, 0, len(owned))
+	for _, link := range owned {
+		if input.IsActive != nil || input.ExpiresAt != nil {
+			link, err = txLinkRepo.Update(ctx, sqlc.UpdateLinkParams{
+				ID:        link.ID,
+				IsActive:  models.OptionalBool(input.IsActive),
+				ExpiresAt: expiresAt,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, tagID := range tagIDs {
+			if err := txLinkRepo.AddTag(ctx, link.ID, tagID); err != nil {
+				return nil, err
+			}
+		}
+		updated = append(updated, link)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, httputil.Wrap(err, "failed to commit transaction")
+	}
+
+	for _, link := range updated {
+		if err := s.events.Publish(ctx, "link.updated", workspaceID, link); err != nil {
+			s.logger.Warn("failed to publish link.updated event", zap.Error(err))
+		}
+	}
+
+	return result, nil
+}
+
+// BulkDeleteLinks soft-deletes every requested link in the workspace inside
+// one transaction, skipping links that don't exist or belong elsewhere.
+func (s *linkService) BulkDeleteLinks(ctx context.Context, workspaceID uuid.UUID, input models.BulkDeleteLinksInput) (*models.BulkLinkResult, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	txLinkRepo := repository.NewLinkRepository(sqlc.New(tx), s.logger)
+
+	owned, result, err := partitionOwnedLinks(ctx, txLinkRepo, workspaceID, input.LinkIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, link := range owned {
+		if err := txLinkRepo.SoftDelete(ctx, link.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, httputil.Wrap(err, "failed to commit transaction")
+	}
+
+	for _, link := range owned {
+		if err := s.events.Publish(ctx, "link.deleted", workspaceID, link); err != nil {
+			s.logger.Warn("failed to publish link.deleted event", zap.Error(err))
+		}
+	}
+
+	return result, nil
+}
+
+// partitionOwnedLinks loads the requested links and splits them into those
+// owned by the workspace and those to skip. The returned result already
+// lists the owned IDs as succeeded.
+func partitionOwnedLinks(ctx context.Context, repo repository.LinkRepository, workspaceID uuid.UUID, ids []uuid.UUID) ([]*models.Link, *models.BulkLinkResult, error) {
+	ids = uniqueIDs(ids)
+	result := &models.BulkLinkResult{
+		Requested: len(ids),
+		LinkIDs:   make([]uuid.UUID, 0, len(ids)),
+		Skipped:   []models.BulkLinkSkip{},
+	}
+
+	owned := make([]*models.Link, 0, len(ids))
+	for _, id := range ids {
+		link, err := repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, httputil.ErrNotFound) {
+				result.Skipped = append(result.Skipped, models.BulkLinkSkip{LinkID: id, Reason: "link not found"})
+				continue
+			}
+			return nil, nil, err
+		}
+		if link.WorkspaceID != workspaceID {
+			result.Skipped = append(result.Skipped, models.BulkLinkSkip{LinkID: id, Reason: "link does not belong to this workspace"})
+			continue
+		}
+		owned = append(owned, link)
+		result.LinkIDs = append(result.LinkIDs, id)
+	}
+	result.Succeeded = len(owned)
+
+	return owned, result, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func (s *linkService) GetQuickStats(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error) {
+	stats, err := s.linkRepo.GetQuickStats(ctx, id)
+	if err != nil || stats == nil {
+		return stats, err
+	}
+	lastClicked := s.lastClickedAt(ctx, []uuid.UUID{id})
+	if at, ok := lastClicked[id]; ok {
+		stats.LastClickedAt = &at
+	}
+	stats.Active24h = models.LinkActiveAt(stats.LastClickedAt, time.Now())
+	return stats, nil
+}
+
+// addLastClicked sets when each listed link was last clicked and whether
+// that makes it active. Without analytics the fields are left out.
+func (s *linkService) addLastClicked(ctx context.Context, responses []*models.LinkResponse) {
+	if s.analyticsRepo == nil || len(responses) == 0 {
+		return
+	}
+	ids := make([]uuid.UUID, len(responses))
+	for i, resp := range responses {
+		ids[i] = resp.ID
+	}
+	lastClicked := s.lastClickedAt(ctx, ids)
+	if lastClicked == nil {
+		return
+	}
+	now := time.Now()
+	for _, resp := range responses {
+		if at, ok := lastClicked[resp.ID]; ok {
+			resp.LastClickedAt = &at
+		}
+		active := models.LinkActiveAt(resp.LastClickedAt, now)
+		resp.Active24h = &active
+	}
+}
+
+// lastClickedAt looks the links' last clicks up, returning nil when
+// analytics are unavailable. A failure only costs the at-a-glance fields,
+// so it is logged rather than returned.
+func (s *linkService) lastClickedAt(ctx context.Context, ids []uuid.UUID) map[uuid.UUID]time.Time {
+	if s.analyticsRepo == nil {
+		return nil
+	}
+	lastClicked, err := s.analyticsRepo.GetLastClickedAt(ctx, ids)
+	if err != nil {
+		s.logger.Warn("failed to get last clicked times", zap.Int("links", len(ids)), zap.Error(err))
+		return nil
+	}
+	return lastClicked
+}
+
+func (s *linkService) CheckShortCodeAvailable(ctx context.Context, code string) (bool, error) {
+	exists, err := shortCodeExists(ctx, s.linkRepo, s.cfg.Links, code)
+	if err != nil {
+		return false, err
+	}
+	return !exists, nil
+}
+
+func (s *linkService) VerifyLinkPassword(ctx context.Context, shortCode, password string) (bool, error) {
+	link, err := s.linkRepo.GetByShortCode(ctx, s.cfg.Links.NormalizeShortCode(shortCode))
+	if err != nil {
+		return false, err
+	}
+
+	if link.PasswordHash == nil {
+		return true, nil
+	}
+
+	match, err := crypto.VerifyPassword(password, *link.PasswordHash)
+	if err != nil {
+		return false, httputil.Wrap(err, "failed to verify password")
+	}
+	return match, nil
+}
+
+// ExportWorkspaceLinks writes every link in the workspace together with its
+// click aggregates for the date range to w. Links are fetched page by page so
+// large workspaces are never loaded into memory at once.
+func (s *linkService) ExportWorkspaceLinks(ctx context.Context, workspaceID uuid.UUID, dr models.DateRange, format models.AnalyticsExportFormat, w io.Writer) error {
+	if !s.licManager.HasFeature(license.FeatureExportData) {
+		return httputil.PaymentRequiredWithDetails(string(license.FeatureExportData), "pro")
+	}
+	if format != models.ExportCSV && format != models.ExportJSON {
+		return httputil.Validation("format", "unsupported export format, use csv or json")
+	}
+
+	dr = dr.ClampToRetention(s.licManager.GetLimits().AnalyticsRetentionDays)
+
+	var (
+		csvWriter *csv.Writer
+		written   int
+	)
+	switch format {
+	case models.ExportCSV:
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(models.LinkExportCSVHeader); err != nil {
+			return httputil.Wrap(err, "failed to write export")
+		}
+	case models.ExportJSON:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return httputil.Wrap(err, "failed to write export")
+		}
+	}
+
+	redirectBaseURL := s.cfg.App.RedirectURL
+	for offset := 0; ; offset += exportPageSize {
+		links, _, err := s.linkRepo.List(ctx, sqlc.ListLinksForWorkspaceParams{
+			WorkspaceID: workspaceID,
+			Limit:       exportPageSize,
+			Offset:      int32(offset),
+		})
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(links))
+		for i, link := range links {
+			ids[i] = link.ID
+		}
+		summaries, err := s.analyticsRepo.GetLinkClickSummaries(ctx, ids, dr)
+		if err != nil {
+			return httputil.Wrap(err, "failed to get link click summaries")
+		}
+
+		for _, link := range links {
+			row := models.NewLinkExportRow(link, summaries[link.ID], redirectBaseURL)
+
+			switch format {
+			case models.ExportCSV:
+				if err := csvWriter.Write(row.CSVRecord()); err != nil {
+					return httputil.Wrap(err, "failed to write export")
+				}
+			case models.ExportJSON:
+				data, err := json.Marshal(row)
+				if err != nil {
+					return httputil.Wrap(err, "failed to marshal export row")
+				}
+				if written > 0 {
+					if _, err := io.WriteString(w, ","); err != nil {
+						return httputil.Wrap(err, "failed to write export")
+					}
+				}
+				if _, err := w.Write(data); err != nil {
+					return httputil.Wrap(err, "failed to write export")
+				}
+			}
+			written++
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return httputil.Wrap(err, "failed to write export")
+			}
+		}
+
+		if len(links) < exportPageSize {
+			break
+		}
+	}
+
+	if format == models.ExportJSON {
+		if _, err := io.WriteString(w, "]"); err != nil {
+			return httputil.Wrap(err, "failed to write export")
+		}
+	}
+
+	return nil
+}
+
+// generateUniqueShortCode generates random short codes until an unused one is
+// found. Every ShortCodeEscalateAfter collisions the code length grows by one,
+// so generation stays reliable as the namespace at the default length fills up.
+func (s *linkService) generateUniqueShortCode(ctx context.Context) (string, error) {
+	return findUniqueShortCode(ctx, s.linkRepo, s.codeGen, s.cfg.Links)
+}
+
+func findUniqueShortCode(ctx context.Context, linkRepo repository.LinkRepository, codeGen shortcode.Generator, cfg config.LinksConfig) (string, error) {
+	maxRetries := cfg.ShortCodeMaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultShortCodeMaxRetries
+	}
+	escalateAfter := cfg.ShortCodeEscalateAfter
+	if escalateAfter <= 0 {
+		escalateAfter = defaultShortCodeEscalateAfter
+	}
+
+	for i := 0; i < maxRetries; i++ {
+		length := shortcode.DefaultLength + i/escalateAfter
+		code := cfg.NormalizeShortCode(codeGen.GenerateWithLength(length))
+		exists, err := shortCodeExists(ctx, linkRepo, cfg, code)
+		if err != nil {
 			return "", err
 		}
 		if !exists {
@@ -396,6 +1692,15 @@ func (s *linkService) generateUniqueShortCode(ctx context.Context) (string, erro
 	return "", httputil.Wrap(errors.New("short code generation failed"), "failed to generate unique short code after retries")
 }
 
+// shortCodeExists reports whether code is taken, ignoring case when short
+// codes are case-insensitive.
+func shortCodeExists(ctx context.Context, linkRepo repository.LinkRepository, cfg config.LinksConfig, code string) (bool, error) {
+	if cfg.CaseInsensitiveShortCodes {
+		return linkRepo.ShortCodeExistsIgnoreCase(ctx, code)
+	}
+	return linkRepo.ShortCodeExists(ctx, code)
+}
+
 func normalizeURL(rawURL string) (string, error) {
 	rawURL = strings.TrimSpace(rawURL)
 	if rawURL == "" {
@@ -419,8 +1724,35 @@ func normalizeURL(rawURL string) (string, error) {
 	return parsed.String(), nil
 }
 
+// shortCodeBounds returns the custom short code length range configured for
+// the workspace, or the defaults.
+func (s *linkService) shortCodeBounds(ctx context.Context, workspaceID uuid.UUID) (int, int, error) {
+	if s.wsRepo == nil {
+		minLen, maxLen := (*models.WorkspaceShortCodeLength)(nil).Bounds()
+		return minLen, maxLen, nil
+	}
+	ws, err := s.wsRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return 0, 0, err
+	}
+	minLen, maxLen := ws.ParsedSettings().ShortCodeLength.Bounds()
+	return minLen, maxLen, nil
+}
+
+func shortCodeLengthMessage(minLen, maxLen int) string {
+	return fmt.Sprintf("short code must be %d-%d alphanumeric characters, hyphens, or underscores", minLen, maxLen)
+}
+
+// isValidShortCode reports whether code is a valid custom short code under
+// the default length bounds.
 func isValidShortCode(code string) bool {
-	if len(code) < 3 || len(code) > 50 {
+	return shortCodeFits(code, models.DefaultShortCodeMinLength, models.MaxShortCodeLength)
+}
+
+// shortCodeFits reports whether code is minLen to maxLen letters, digits,
+// hyphens or underscores.
+func shortCodeFits(code string, minLen, maxLen int) bool {
+	if len(code) < minLen || len(code) > maxLen {
 		return false
 	}
 	for _, c := range code {
@@ -1,14 +1,21 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/license"
 	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/qrcode"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
 	"github.com/link-rift/link-rift/pkg/httputil"
 	"github.com/link-rift/link-rift/pkg/shortcode"
@@ -24,12 +31,24 @@ type mockLinkRepo struct {
 	getByURLFn           func(ctx context.Context, params sqlc.GetLinkByURLParams) (*models.Link, error)
 	listFn               func(ctx context.Context, params sqlc.ListLinksForWorkspaceParams) ([]*models.Link, int64, error)
 	updateFn             func(ctx context.Context, params sqlc.UpdateLinkParams) (*models.Link, error)
+	updateMetadataFn     func(ctx context.Context, params sqlc.UpdateLinkMetadataParams) (*models.Link, error)
 	softDeleteFn         func(ctx context.Context, id uuid.UUID) error
+	softDeleteForWsFn    func(ctx context.Context, workspaceID uuid.UUID) (int64, error)
 	shortCodeExistsFn    func(ctx context.Context, shortCode string) (bool, error)
+	shortCodeFoldFn      func(ctx context.Context, shortCode string) (bool, error)
+	reserveFn            func(ctx context.Context, params sqlc.ReserveShortCodeParams) (*models.Link, error)
+	getReservationFn     func(ctx context.Context, workspaceID uuid.UUID, shortCode string) (*models.Link, error)
+	claimReservationFn   func(ctx context.Context, params sqlc.CreateLinkParams) (*models.Link, error)
+	releaseExpiredFn     func(ctx context.Context, shortCode string) error
 	incrementClicksFn    func(ctx context.Context, id uuid.UUID) error
 	incrementUniqueFn    func(ctx context.Context, id uuid.UUID) error
 	getQuickStatsFn      func(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error)
-	getCountFn           func(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	countCreatedFn       func(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (int64, error)
+	transferFn           func(ctx context.Context, params sqlc.TransferLinkParams) (*models.Link, error)
+	archiveFn            func(ctx context.Context, id uuid.UUID, deactivate bool) (*models.Link, error)
+	unarchiveFn          func(ctx context.Context, id uuid.UUID) (*models.Link, error)
+	listIDsByTagFn       func(ctx context.Context, workspaceID, tagID uuid.UUID) ([]uuid.UUID, error)
+	countTagsFn          func(ctx context.Context, workspaceID uuid.UUID, tagIDs []uuid.UUID) (int64, error)
 }
 
 func (m *mockLinkRepo) Create(ctx context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
@@ -74,6 +93,13 @@ func (m *mockLinkRepo) Update(ctx context.Context, params sqlc.UpdateLinkParams)
 	return nil, nil
 }
 
+func (m *mockLinkRepo) UpdateMetadata(ctx context.Context, params sqlc.UpdateLinkMetadataParams) (*models.Link, error) {
+	if m.updateMetadataFn != nil {
+		return m.updateMetadataFn(ctx, params)
+	}
+	return nil, nil
+}
+
 func (m *mockLinkRepo) SoftDelete(ctx context.Context, id uuid.UUID) error {
 	if m.softDeleteFn != nil {
 		return m.softDeleteFn(ctx, id)
@@ -81,6 +107,45 @@ func (m *mockLinkRepo) SoftDelete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+func (m *mockLinkRepo) SoftDeleteForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	if m.softDeleteForWsFn != nil {
+		return m.softDeleteForWsFn(ctx, workspaceID)
+	}
+	return 0, nil
+}
+
+func (m *mockLinkRepo) Reserve(ctx context.Context, params sqlc.ReserveShortCodeParams) (*models.Link, error) {
+	if m.reserveFn != nil {
+		return m.reserveFn(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkRepo) GetReservation(ctx context.Context, workspaceID uuid.UUID, shortCode string) (*models.Link, error) {
+	if m.getReservationFn != nil {
+		return m.getReservationFn(ctx, workspaceID, shortCode)
+	}
+	return nil, httputil.NotFound("reservation")
+}
+
+func (m *mockLinkRepo) ClaimReservation(ctx context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+	if m.claimReservationFn != nil {
+		return m.claimReservationFn(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkRepo) ReleaseExpiredReservation(ctx context.Context, shortCode string) error {
+	if m.releaseExpiredFn != nil {
+		return m.releaseExpiredFn(ctx, shortCode)
+	}
+	return nil
+}
+
+func (m *mockLinkRepo) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockLinkRepo) ShortCodeExists(ctx context.Context, shortCode string) (bool, error) {
 	if m.shortCodeExistsFn != nil {
 		return m.shortCodeExistsFn(ctx, shortCode)
@@ -88,6 +153,13 @@ func (m *mockLinkRepo) ShortCodeExists(ctx context.Context, shortCode string) (b
 	return false, nil
 }
 
+func (m *mockLinkRepo) ShortCodeExistsIgnoreCase(ctx context.Context, shortCode string) (bool, error) {
+	if m.shortCodeFoldFn != nil {
+		return m.shortCodeFoldFn(ctx, shortCode)
+	}
+	return false, nil
+}
+
 func (m *mockLinkRepo) IncrementClicks(ctx context.Context, id uuid.UUID) error {
 	if m.incrementClicksFn != nil {
 		return m.incrementClicksFn(ctx, id)
@@ -95,6 +167,10 @@ func (m *mockLinkRepo) IncrementClicks(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+func (m *mockLinkRepo) AddClicks(ctx context.Context, id uuid.UUID, count int64) error {
+	return nil
+}
+
 func (m *mockLinkRepo) IncrementUniqueClicks(ctx context.Context, id uuid.UUID) error {
 	if m.incrementUniqueFn != nil {
 		return m.incrementUniqueFn(ctx, id)
@@ -102,6 +178,10 @@ func (m *mockLinkRepo) IncrementUniqueClicks(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
+func (m *mockLinkRepo) ReconcileUniqueClicks(_ context.Context, _ time.Time, _ time.Duration) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockLinkRepo) GetQuickStats(ctx context.Context, id uuid.UUID) (*models.LinkQuickStats, error) {
 	if m.getQuickStatsFn != nil {
 		return m.getQuickStatsFn(ctx, id)
@@ -109,13 +189,107 @@ func (m *mockLinkRepo) GetQuickStats(ctx context.Context, id uuid.UUID) (*models
 	return nil, nil
 }
 
-func (m *mockLinkRepo) GetCountForWorkspace(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
-	if m.getCountFn != nil {
-		return m.getCountFn(ctx, workspaceID)
+func (m *mockLinkRepo) CountCreatedForWorkspace(ctx context.Context, workspaceID uuid.UUID, start, end time.Time) (int64, error) {
+	if m.countCreatedFn != nil {
+		return m.countCreatedFn(ctx, workspaceID, start, end)
+	}
+	return 0, nil
+}
+
+func (m *mockLinkRepo) LockLinkLimit(_ context.Context, _ uuid.UUID) error {
+	return nil
+}
+
+func (m *mockLinkRepo) Transfer(ctx context.Context, params sqlc.TransferLinkParams) (*models.Link, error) {
+	if m.transferFn != nil {
+		return m.transferFn(ctx, params)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkRepo) Archive(ctx context.Context, id uuid.UUID, deactivate bool) (*models.Link, error) {
+	if m.archiveFn != nil {
+		return m.archiveFn(ctx, id, deactivate)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkRepo) Unarchive(ctx context.Context, id uuid.UUID) (*models.Link, error) {
+	if m.unarchiveFn != nil {
+		return m.unarchiveFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkRepo) ListIDsByTag(ctx context.Context, workspaceID, tagID uuid.UUID) ([]uuid.UUID, error) {
+	if m.listIDsByTagFn != nil {
+		return m.listIDsByTagFn(ctx, workspaceID, tagID)
+	}
+	return nil, nil
+}
+
+func (m *mockLinkRepo) AddTag(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
+
+func (m *mockLinkRepo) ListWithFailovers(_ context.Context, _ uuid.UUID, _ int) ([]*models.Link, error) {
+	return nil, nil
+}
+
+func (m *mockLinkRepo) ListMostClicked(_ context.Context, _ int) ([]*models.Link, error) {
+	return nil, nil
+}
+
+func (m *mockLinkRepo) EnsureTag(_ context.Context, _ uuid.UUID, _ string) (uuid.UUID, error) {
+	return uuid.New(), nil
+}
+
+func (m *mockLinkRepo) CountWorkspaceTags(ctx context.Context, workspaceID uuid.UUID, tagIDs []uuid.UUID) (int64, error) {
+	if m.countTagsFn != nil {
+		return m.countTagsFn(ctx, workspaceID, tagIDs)
 	}
 	return 0, nil
 }
 
+// --- Mock WorkspaceMemberRepository ---
+
+type mockMemberRepo struct {
+	roles     map[uuid.UUID]models.WorkspaceRole // keyed by workspace ID
+	overLimit map[uuid.UUID]int64
+}
+
+func (m *mockMemberRepo) Add(_ context.Context, _ sqlc.AddWorkspaceMemberParams) (*models.WorkspaceMember, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockMemberRepo) Get(_ context.Context, workspaceID, userID uuid.UUID) (*models.WorkspaceMember, error) {
+	role, ok := m.roles[workspaceID]
+	if !ok {
+		return nil, httputil.NotFound("workspace member")
+	}
+	return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+func (m *mockMemberRepo) List(_ context.Context, _ uuid.UUID) ([]*models.WorkspaceMemberResponse, error) {
+	return nil, nil
+}
+
+func (m *mockMemberRepo) UpdateRole(_ context.Context, _ sqlc.UpdateMemberRoleParams) (*models.WorkspaceMember, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockMemberRepo) Remove(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
+
+func (m *mockMemberRepo) GetCount(_ context.Context, _ uuid.UUID) (int64, error) {
+	return int64(len(m.roles)), nil
+}
+
+func (m *mockMemberRepo) ListOverLimit(_ context.Context, _ int64) (map[uuid.UUID]int64, error) {
+	return m.overLimit, nil
+}
+
 // --- Mock ClickRepository ---
 
 type mockClickRepo struct {
@@ -156,6 +330,50 @@ func (m *mockCodeGen) GenerateWithLength(n int) string {
 	return m.Generate()
 }
 
+// --- Mock QRCodeService ---
+
+type mockQRService struct {
+	createFn  func(ctx context.Context, linkID, workspaceID uuid.UUID, input models.CreateQRCodeInput) (*models.QRCode, error)
+	dataURIFn func(url string) (string, error)
+}
+
+func (m *mockQRService) CreateQRCode(ctx context.Context, linkID, workspaceID uuid.UUID, input models.CreateQRCodeInput) (*models.QRCode, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, linkID, workspaceID, input)
+	}
+	return nil, errors.New("not implemented")
+}
+func (m *mockQRService) GetQRCode(ctx context.Context, id uuid.UUID) (*models.QRCode, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockQRService) GetQRCodeForLink(ctx context.Context, linkID uuid.UUID) (*models.QRCode, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockQRService) UpdateQRCode(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateQRCodeInput) (*models.QRCode, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockQRService) DownloadQRCode(ctx context.Context, linkID uuid.UUID, format string, print *qrcode.PrintProfile) ([]byte, string, error) {
+	return nil, "", errors.New("not implemented")
+}
+func (m *mockQRService) DeleteQRCode(ctx context.Context, id uuid.UUID) error {
+	return errors.New("not implemented")
+}
+func (m *mockQRService) BulkGenerateQRCodes(ctx context.Context, workspaceID uuid.UUID, input models.BulkQRCodeInput, progress qrcode.ProgressFunc) (*qrcode.BatchResult, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockQRService) StoreBatchArchive(ctx context.Context, workspaceID uuid.UUID, zipData []byte) (string, error) {
+	return "", errors.New("not implemented")
+}
+func (m *mockQRService) GetStyleTemplates() map[string]qrcode.StyleTemplate {
+	return nil
+}
+func (m *mockQRService) InlineDataURI(url string) (string, error) {
+	if m.dataURIFn != nil {
+		return m.dataURIFn(url)
+	}
+	return "", errors.New("not implemented")
+}
+
 // --- Helpers ---
 
 func newTestService(linkRepo *mockLinkRepo, clickRepo *mockClickRepo, codeGen shortcode.Generator) *linkService {
@@ -164,8 +382,10 @@ func newTestService(linkRepo *mockLinkRepo, clickRepo *mockClickRepo, codeGen sh
 		linkRepo:  linkRepo,
 		clickRepo: clickRepo,
 		cfg:       &config.Config{App: config.AppConfig{RedirectURL: "http://localhost:8081"}},
-		codeGen:   codeGen,
-		logger:    logger,
+		licManager: newTestLicenseManager(license.TierFree),
+		codeGen:    codeGen,
+		events:     NewNoopEventPublisher(),
+		logger:     logger,
 	}
 }
 
@@ -205,6 +425,9 @@ func TestCreateLink_ValidInput(t *testing.T) {
 			if params.ShortCode != "test123" {
 				t.Errorf("expected short_code test123, got %s", params.ShortCode)
 			}
+			if !params.InternalNote.Valid || params.InternalNote.String != "Q3 campaign, owned by growth" {
+				t.Errorf("expected internal note to be stored, got %+v", params.InternalNote)
+			}
 			return makeLink(linkID, userID, workspaceID, "test123"), nil
 		},
 	}
@@ -212,8 +435,9 @@ func TestCreateLink_ValidInput(t *testing.T) {
 	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "test123"})
 
 	input := models.CreateLinkInput{
-		URL:   "https://example.com",
-		Title: strPtr("Test Link"),
+		URL:          "https://example.com",
+		Title:        strPtr("Test Link"),
+		InternalNote: strPtr("Q3 campaign, owned by growth"),
 	}
 
 	link, err := svc.CreateLink(context.Background(), userID, workspaceID, input)
@@ -225,6 +449,118 @@ func TestCreateLink_ValidInput(t *testing.T) {
 	}
 }
 
+func TestCreateLink_GenerateQR(t *testing.T) {
+	userID := uuid.New()
+	workspaceID := uuid.New()
+	pngURL := "http://localhost:8080/uploads/qr/test.png"
+
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			return makeLink(uuid.New(), userID, workspaceID, "qr12345"), nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "qr12345"})
+	svc.qrService = &mockQRService{
+		createFn: func(_ context.Context, _, wsID uuid.UUID, input models.CreateQRCodeInput) (*models.QRCode, error) {
+			if wsID != workspaceID {
+				t.Errorf("expected workspace_id %s, got %s", workspaceID, wsID)
+			}
+			if input.ErrorCorrection != "H" {
+				t.Errorf("expected error correction H, got %q", input.ErrorCorrection)
+			}
+			return &models.QRCode{ID: uuid.New(), PngURL: &pngURL}, nil
+		},
+	}
+
+	input := models.CreateLinkInput{
+		URL:        "https://example.com",
+		GenerateQR: true,
+		QROptions:  &models.CreateQRCodeInput{ErrorCorrection: "H"},
+	}
+
+	link, err := svc.CreateLink(context.Background(), userID, workspaceID, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.QRCodeURL == nil || *link.QRCodeURL != pngURL {
+		t.Errorf("expected QR code URL %s, got %v", pngURL, link.QRCodeURL)
+	}
+}
+
+func TestCreateLink_GenerateQRFailureIsSoft(t *testing.T) {
+	userID := uuid.New()
+	workspaceID := uuid.New()
+
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			return makeLink(uuid.New(), userID, workspaceID, "qr12345"), nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "qr12345"})
+	svc.qrService = &mockQRService{
+		createFn: func(_ context.Context, _, _ uuid.UUID, _ models.CreateQRCodeInput) (*models.QRCode, error) {
+			return nil, httputil.PaymentRequiredWithDetails("qr_customization", "pro")
+		},
+	}
+
+	input := models.CreateLinkInput{
+		URL:        "https://example.com",
+		GenerateQR: true,
+		QROptions:  &models.CreateQRCodeInput{ForegroundColor: "#FF0000"},
+	}
+
+	link, err := svc.CreateLink(context.Background(), userID, workspaceID, input)
+	if err != nil {
+		t.Fatalf("expected link creation to succeed, got error: %v", err)
+	}
+	if link.QRCodeURL != nil {
+		t.Errorf("expected no QR code URL, got %s", *link.QRCodeURL)
+	}
+}
+
+func TestCreateLink_CloakRequiresLicense(t *testing.T) {
+	repo := &mockLinkRepo{
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			t.Error("link should not be created")
+			return nil, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "cloak12"})
+
+	_, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{
+		URL:   "https://example.com",
+		Cloak: true,
+	})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Errorf("expected payment required, got %v", err)
+	}
+}
+
+func TestCreateLink_PixelsRequireLicense(t *testing.T) {
+	repo := &mockLinkRepo{
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			t.Error("link should not be created")
+			return nil, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "pixel12"})
+
+	pixelID := "1234567890123456"
+	_, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{
+		URL:             "https://example.com",
+		FacebookPixelID: &pixelID,
+	})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Errorf("expected payment required, got %v", err)
+	}
+}
+
 func TestCreateLink_CustomShortCode(t *testing.T) {
 	userID := uuid.New()
 	workspaceID := uuid.New()
@@ -283,6 +619,48 @@ func TestCreateLink_DuplicateShortCode(t *testing.T) {
 	}
 }
 
+func TestCreateLink_CaseInsensitiveShortCode(t *testing.T) {
+	userID := uuid.New()
+	workspaceID := uuid.New()
+
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) {
+			t.Error("expected a case-insensitive short code check")
+			return false, nil
+		},
+		shortCodeFoldFn: func(_ context.Context, code string) (bool, error) {
+			return code != "promo", nil
+		},
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			return makeLink(uuid.New(), userID, workspaceID, params.ShortCode), nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.cfg.Links.CaseInsensitiveShortCodes = true
+
+	link, err := svc.CreateLink(context.Background(), userID, workspaceID, models.CreateLinkInput{
+		URL:       "https://example.com",
+		ShortCode: strPtr("Promo"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.ShortCode != "promo" {
+		t.Errorf("expected short code to be lowercased, got %s", link.ShortCode)
+	}
+
+	// Any other code counts as taken by the mock, e.g. an existing "Sale"
+	_, err = svc.CreateLink(context.Background(), userID, workspaceID, models.CreateLinkInput{
+		URL:       "https://example.com",
+		ShortCode: strPtr("SALE"),
+	})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "ALREADY_EXISTS" {
+		t.Errorf("expected ALREADY_EXISTS error, got %v", err)
+	}
+}
+
 func TestCreateLink_InvalidURL(t *testing.T) {
 	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
 
@@ -347,6 +725,134 @@ func TestCreateLink_WithPassword(t *testing.T) {
 	}
 }
 
+func TestCreateLink_PasswordHint(t *testing.T) {
+	tests := []struct {
+		name      string
+		hint      string
+		want      string
+		wantField string
+	}{
+		{"cleaned up", "  the\tusual\u202e  one\n", "the usual one", ""},
+		{"too long", strings.Repeat("x", models.MaxPasswordHintLength+1), "", "password_hint"},
+		{"markup", "<b>bold</b>", "", "password_hint"},
+		{"contains password", "It's Secret123", "", "password_hint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved string
+			repo := &mockLinkRepo{
+				shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+				createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+					saved = params.PasswordHint.String
+					return makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode), nil
+				},
+			}
+			svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+			_, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{
+				URL:          "https://example.com",
+				Password:     strPtr("secret123"),
+				PasswordHint: strPtr(tt.hint),
+			})
+			if tt.wantField != "" {
+				if field := validationField(err); field != tt.wantField {
+					t.Fatalf("error field = %q (%v), want %q", field, err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if saved != tt.want {
+				t.Errorf("saved hint = %q, want %q", saved, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateLink_FailoverURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		urls      []string
+		want      []string
+		wantField string
+	}{
+		{"none", nil, nil, ""},
+		{"normalized", []string{" backup.example.com ", "https://backup2.example.com/page"}, []string{"https://backup.example.com", "https://backup2.example.com/page"}, ""},
+		{"too many", []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com"}, nil, "failover_urls"},
+		{"invalid", []string{"https://backup.example.com", "https://"}, nil, "failover_urls[1]"},
+		{"duplicate", []string{"backup.example.com", "https://backup.example.com"}, nil, "failover_urls[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []string
+			repo := &mockLinkRepo{
+				shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+				createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+					saved = params.FailoverUrls
+					return makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode), nil
+				},
+			}
+			svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+			_, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{
+				URL:          "https://example.com",
+				FailoverURLs: tt.urls,
+			})
+			if tt.wantField != "" {
+				if field := validationField(err); field != tt.wantField {
+					t.Fatalf("error field = %q (%v), want %q", field, err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(saved, tt.want) {
+				t.Errorf("saved failover URLs = %q, want %q", saved, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateLink_FailoverURLs(t *testing.T) {
+	linkID := uuid.New()
+	workspaceID := uuid.New()
+
+	for _, tt := range []struct {
+		name    string
+		urls    []string
+		wantNil bool
+	}{
+		{"unchanged", nil, true},
+		{"removed", []string{}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []string
+			repo := &mockLinkRepo{
+				getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+					return makeLink(id, uuid.New(), workspaceID, "abc"), nil
+				},
+				updateFn: func(_ context.Context, params sqlc.UpdateLinkParams) (*models.Link, error) {
+					saved = params.FailoverUrls
+					return makeLink(params.ID, uuid.New(), workspaceID, "abc"), nil
+				},
+			}
+			svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+			if _, err := svc.UpdateLink(context.Background(), linkID, workspaceID, models.UpdateLinkInput{FailoverURLs: tt.urls}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// nil leaves the column alone; an empty list clears it
+			if (saved == nil) != tt.wantNil || len(saved) != 0 {
+				t.Errorf("saved failover URLs = %#v", saved)
+			}
+		})
+	}
+}
+
 func TestCreateLink_WithExpiration(t *testing.T) {
 	future := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
 
@@ -492,6 +998,9 @@ func TestUpdateLink_ClearPassword(t *testing.T) {
 			if !params.PasswordHash.Valid || params.PasswordHash.String != "" {
 				t.Error("expected password hash to be cleared (empty valid string)")
 			}
+			if !params.PasswordHint.Valid || params.PasswordHint.String != "" {
+				t.Error("expected password hint to be cleared with the password")
+			}
 			return makeLink(linkID, userID, workspaceID, "abc123"), nil
 		},
 	}
@@ -537,16 +1046,95 @@ func TestDeleteLink_Valid(t *testing.T) {
 	}
 }
 
-func TestDeleteLink_WorkspaceCheck(t *testing.T) {
-	linkID := uuid.New()
-	ownerID := uuid.New()
-	linkWorkspaceID := uuid.New()
-	otherWorkspaceID := uuid.New()
+func TestListLinks_StatusFilter(t *testing.T) {
+	tests := []struct {
+		status string
+		want   pgtype.Bool
+	}{
+		{"", pgtype.Bool{Bool: false, Valid: true}},
+		{models.LinkStatusActive, pgtype.Bool{Bool: false, Valid: true}},
+		{models.LinkStatusArchived, pgtype.Bool{Bool: true, Valid: true}},
+		{models.LinkStatusAll, pgtype.Bool{}},
+	}
 
-	repo := &mockLinkRepo{
-		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
-			return makeLink(linkID, ownerID, linkWorkspaceID, "abc123"), nil
-		},
+	for _, tt := range tests {
+		var got pgtype.Bool
+		repo := &mockLinkRepo{
+			listFn: func(_ context.Context, params sqlc.ListLinksForWorkspaceParams) ([]*models.Link, int64, error) {
+				got = params.Archived
+				return nil, 0, nil
+			},
+		}
+		svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+		if _, err := svc.ListLinks(context.Background(), uuid.New(), models.LinkFilter{Status: tt.status}, models.Pagination{}); err != nil {
+			t.Fatalf("status %q: unexpected error: %v", tt.status, err)
+		}
+		if got != tt.want {
+			t.Errorf("status %q: archived filter = %+v, want %+v", tt.status, got, tt.want)
+		}
+	}
+
+	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+	if _, err := svc.ListLinks(context.Background(), uuid.New(), models.LinkFilter{Status: "deleted"}, models.Pagination{}); err == nil {
+		t.Error("expected validation error for unknown status")
+	}
+}
+
+func TestArchiveLink(t *testing.T) {
+	linkID := uuid.New()
+	workspaceID := uuid.New()
+	now := time.Now()
+
+	var archiveCalls int
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+			link := makeLink(linkID, uuid.New(), workspaceID, "abc123")
+			link.ArchivedAt = &now
+			return link, nil
+		},
+		archiveFn: func(_ context.Context, _ uuid.UUID, deactivate bool) (*models.Link, error) {
+			archiveCalls++
+			link := makeLink(linkID, uuid.New(), workspaceID, "abc123")
+			link.ArchivedAt = &now
+			link.IsActive = !deactivate
+			return link, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+
+	// Archiving an archived link again is a no-op...
+	if _, err := svc.ArchiveLink(context.Background(), linkID, workspaceID, models.ArchiveLinkInput{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if archiveCalls != 0 {
+		t.Errorf("expected no archive call, got %d", archiveCalls)
+	}
+
+	// ...unless it also asks to stop redirects.
+	link, err := svc.ArchiveLink(context.Background(), linkID, workspaceID, models.ArchiveLinkInput{Deactivate: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if archiveCalls != 1 || link.IsActive {
+		t.Errorf("expected the link to be deactivated, calls=%d active=%v", archiveCalls, link.IsActive)
+	}
+
+	if _, err := svc.ArchiveLink(context.Background(), linkID, uuid.New(), models.ArchiveLinkInput{}); err == nil {
+		t.Error("expected forbidden error for another workspace")
+	}
+}
+
+func TestDeleteLink_WorkspaceCheck(t *testing.T) {
+	linkID := uuid.New()
+	ownerID := uuid.New()
+	linkWorkspaceID := uuid.New()
+	otherWorkspaceID := uuid.New()
+
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+			return makeLink(linkID, ownerID, linkWorkspaceID, "abc123"), nil
+		},
 	}
 
 	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
@@ -621,6 +1209,48 @@ func TestListLinks_DefaultPagination(t *testing.T) {
 	}
 }
 
+func TestListLinks_IncludeQR(t *testing.T) {
+	workspaceID := uuid.New()
+	repo := &mockLinkRepo{
+		listFn: func(_ context.Context, _ sqlc.ListLinksForWorkspaceParams) ([]*models.Link, int64, error) {
+			return []*models.Link{
+				makeLink(uuid.New(), uuid.New(), workspaceID, "abc123"),
+				makeLink(uuid.New(), uuid.New(), workspaceID, "def456"),
+			}, 2, nil
+		},
+	}
+
+	var encoded []string
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.qrService = &mockQRService{
+		dataURIFn: func(url string) (string, error) {
+			encoded = append(encoded, url)
+			return "data:image/png;base64,AAAA", nil
+		},
+	}
+
+	result, err := svc.ListLinks(context.Background(), workspaceID, models.LinkFilter{}, models.Pagination{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(encoded) != 0 || result.Links[0].QRCodeDataURI != nil {
+		t.Fatal("expected no inline QR codes without include_qr")
+	}
+
+	result, err = svc.ListLinks(context.Background(), workspaceID, models.LinkFilter{IncludeQR: true}, models.Pagination{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(encoded) != 2 || encoded[0] != "http://localhost:8081/abc123" {
+		t.Errorf("expected the page's short URLs to be encoded, got %v", encoded)
+	}
+	for _, link := range result.Links {
+		if link.QRCodeDataURI == nil || *link.QRCodeDataURI != "data:image/png;base64,AAAA" {
+			t.Errorf("link %s: expected an inline QR code", link.ShortCode)
+		}
+	}
+}
+
 func TestListLinks_WithFilter(t *testing.T) {
 	workspaceID := uuid.New()
 	search := "test"
@@ -689,6 +1319,74 @@ func TestGetQuickStats_NotFound(t *testing.T) {
 	}
 }
 
+func TestGetQuickStats_LastClickedAt(t *testing.T) {
+	linkID := uuid.New()
+	repo := &mockLinkRepo{
+		getQuickStatsFn: func(_ context.Context, _ uuid.UUID) (*models.LinkQuickStats, error) {
+			return &models.LinkQuickStats{TotalClicks: 3}, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	lastClicked := time.Now().Add(-2 * time.Hour)
+	svc.analyticsRepo = &mockAnalyticsRepo{lastClicked: map[uuid.UUID]time.Time{linkID: lastClicked}}
+
+	stats, err := svc.GetQuickStats(context.Background(), linkID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.LastClickedAt == nil || !stats.LastClickedAt.Equal(lastClicked) {
+		t.Errorf("LastClickedAt = %v, want %v", stats.LastClickedAt, lastClicked)
+	}
+	if !stats.Active24h {
+		t.Error("expected a link clicked 2h ago to be active")
+	}
+
+	// Analytics failures leave the fields out rather than failing.
+	svc.analyticsRepo = &mockAnalyticsRepo{err: errors.New("clickhouse down")}
+	stats, err = svc.GetQuickStats(context.Background(), linkID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.LastClickedAt != nil || stats.Active24h {
+		t.Errorf("stats = %+v, want no last click", stats)
+	}
+}
+
+func TestListLinks_LastClickedAt(t *testing.T) {
+	workspaceID := uuid.New()
+	recent, stale, never := uuid.New(), uuid.New(), uuid.New()
+	repo := &mockLinkRepo{
+		listFn: func(_ context.Context, _ sqlc.ListLinksForWorkspaceParams) ([]*models.Link, int64, error) {
+			return []*models.Link{
+				makeLink(recent, uuid.New(), workspaceID, "recent"),
+				makeLink(stale, uuid.New(), workspaceID, "stale"),
+				makeLink(never, uuid.New(), workspaceID, "never"),
+			}, 3, nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	now := time.Now()
+	svc.analyticsRepo = &mockAnalyticsRepo{lastClicked: map[uuid.UUID]time.Time{
+		recent: now.Add(-time.Hour),
+		stale:  now.Add(-72 * time.Hour),
+	}}
+
+	result, err := svc.ListLinks(context.Background(), workspaceID, models.LinkFilter{}, models.Pagination{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]bool{"recent": true, "stale": false, "never": false}
+	for _, link := range result.Links {
+		if link.Active24h == nil || *link.Active24h != want[link.ShortCode] {
+			t.Errorf("%s: Active24h = %v, want %v", link.ShortCode, link.Active24h, want[link.ShortCode])
+		}
+		if (link.LastClickedAt == nil) != (link.ShortCode == "never") {
+			t.Errorf("%s: LastClickedAt = %v", link.ShortCode, link.LastClickedAt)
+		}
+	}
+}
+
 func TestCheckShortCodeAvailable_Available(t *testing.T) {
 	repo := &mockLinkRepo{
 		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
@@ -759,9 +1457,13 @@ func TestVerifyLinkPassword_NotFound(t *testing.T) {
 }
 
 func TestBulkCreateLinks_NilPool(t *testing.T) {
-	// BulkCreateLinks requires a pgxpool which we can't easily mock in unit tests.
-	// Verify it handles the nil pool case by recovering from the panic.
-	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+	// Without a pool the batch is created directly on the link repository.
+	repo := &mockLinkRepo{
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			return makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode), nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "abc1234"})
 
 	input := models.BulkCreateLinkInput{
 		Links: []models.CreateLinkInput{
@@ -769,11 +1471,13 @@ func TestBulkCreateLinks_NilPool(t *testing.T) {
 		},
 	}
 
-	// pool is nil so Begin() will panic — verify the function signature is correct
-	// by checking it exists (compilation check). Skip actual execution.
-	_ = svc
-	_ = input
-	t.Skip("BulkCreateLinks requires a real pgxpool; covered by integration tests")
+	links, err := svc.BulkCreateLinks(context.Background(), uuid.New(), uuid.New(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(links) != 1 || links[0].ShortCode != "abc1234" {
+		t.Errorf("expected one link abc1234, got %+v", links)
+	}
 }
 
 // --- Helper function tests ---
@@ -808,6 +1512,71 @@ func TestNormalizeURL(t *testing.T) {
 	}
 }
 
+func TestResolveAppURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"custom scheme", "myapp://product/123", "myapp://product/123", false},
+		{"android intent", "intent://product/123#Intent;scheme=myapp;package=com.example.app;end", "intent://product/123#Intent;scheme=myapp;package=com.example.app;end", false},
+		{"universal link", "https://app.example.com/product/123", "https://app.example.com/product/123", false},
+		{"empty clears", "", "", false},
+		{"no scheme", "product/123", "", true},
+		{"javascript", "javascript:alert(1)", "", true},
+		{"data", "DATA:text/html,hi", "", true},
+		{"https without host", "https:///product", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			got, err := resolveAppURL("ios_url", &input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveAppURL(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if got.String != tt.want {
+				t.Errorf("resolveAppURL(%q) = %q, want %q", tt.input, got.String, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePixels(t *testing.T) {
+	tests := []struct {
+		name         string
+		facebook     string
+		google       string
+		wantFacebook string
+		wantGoogle   string
+		wantErr      bool
+	}{
+		{"both", "1234567890123456", "AW-123456789", "1234567890123456", "AW-123456789", false},
+		{"google lowercase", "", "aw-123456789", "", "AW-123456789", false},
+		{"ga4 tag", "", "G-ABC123XYZ", "", "G-ABC123XYZ", false},
+		{"empty clears", "", "", "", "", false},
+		{"facebook not numeric", "12345abc", "", "", "", true},
+		{"facebook script", "1');alert(1);//", "", "", "", true},
+		{"google unknown prefix", "", "UA-12345-1", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facebook, google := tt.facebook, tt.google
+			got, err := resolvePixels(&facebook, &google)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolvePixels(%q, %q) error = %v, wantErr %v", tt.facebook, tt.google, err, tt.wantErr)
+				return
+			}
+			if got.facebook.String != tt.wantFacebook || got.google.String != tt.wantGoogle {
+				t.Errorf("resolvePixels(%q, %q) = %q, %q, want %q, %q", tt.facebook, tt.google, got.facebook.String, got.google.String, tt.wantFacebook, tt.wantGoogle)
+			}
+		})
+	}
+}
+
 func TestIsValidShortCode(t *testing.T) {
 	tests := []struct {
 		code string
@@ -834,6 +1603,75 @@ func TestIsValidShortCode(t *testing.T) {
 	}
 }
 
+func TestCreateLink_WorkspaceShortCodeLength(t *testing.T) {
+	workspaceID := uuid.New()
+	tests := []struct {
+		name    string
+		length  *models.WorkspaceShortCodeLength
+		code    string
+		wantErr string
+	}{
+		{"default rejects 2 chars", nil, "ab", "short code must be 3-50 alphanumeric characters, hyphens, or underscores"},
+		{"minimum raised", &models.WorkspaceShortCodeLength{Min: 6}, "abcde", "short code must be 6-50 alphanumeric characters, hyphens, or underscores"},
+		{"minimum raised, long enough", &models.WorkspaceShortCodeLength{Min: 6}, "abcdef", ""},
+		{"minimum lowered", &models.WorkspaceShortCodeLength{Min: 2}, "ab", ""},
+		{"maximum lowered", &models.WorkspaceShortCodeLength{Max: 8}, "abcdefghi", "short code must be 3-8 alphanumeric characters, hyphens, or underscores"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, _ := json.Marshal(models.WorkspaceSettings{ShortCodeLength: tt.length})
+			repo := &mockLinkRepo{
+				createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+					return makeLink(uuid.New(), params.UserID, workspaceID, params.ShortCode), nil
+				},
+			}
+			svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+			svc.wsRepo = &mockWorkspaceRepo{workspaces: map[uuid.UUID]*models.Workspace{
+				workspaceID: {ID: workspaceID, Settings: settings},
+			}}
+
+			_, err := svc.CreateLink(context.Background(), uuid.New(), workspaceID, models.CreateLinkInput{
+				URL:       "https://example.com",
+				ShortCode: strPtr(tt.code),
+			})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var appErr *httputil.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+				t.Fatalf("expected VALIDATION_ERROR, got %v", err)
+			}
+			if appErr.Message != tt.wantErr {
+				t.Errorf("message = %q, want %q", appErr.Message, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkspaceShortCodeLength_Validate(t *testing.T) {
+	tests := []struct {
+		length    models.WorkspaceShortCodeLength
+		wantField string
+	}{
+		{models.WorkspaceShortCodeLength{Min: 6, Max: 12}, ""},
+		{models.WorkspaceShortCodeLength{Min: 2}, ""},
+		{models.WorkspaceShortCodeLength{Min: 1}, "min"},
+		{models.WorkspaceShortCodeLength{Max: 51}, "max"},
+		{models.WorkspaceShortCodeLength{Min: 10, Max: 8}, "max"},
+		{models.WorkspaceShortCodeLength{Max: 2}, "max"}, // below the default minimum of 3
+	}
+	for _, tt := range tests {
+		field, _ := tt.length.Validate()
+		if field != tt.wantField {
+			t.Errorf("Validate(%+v) field = %q, want %q", tt.length, field, tt.wantField)
+		}
+	}
+}
+
 func TestGenerateUniqueShortCode_Success(t *testing.T) {
 	callCount := 0
 	repo := &mockLinkRepo{
@@ -896,3 +1734,357 @@ func TestGenerateUniqueShortCode_ExhaustedRetries(t *testing.T) {
 	}
 }
 
+
+func TestExportWorkspaceLinks_RequiresExportFeature(t *testing.T) {
+	repo := &mockLinkRepo{
+		listFn: func(_ context.Context, _ sqlc.ListLinksForWorkspaceParams) ([]*models.Link, int64, error) {
+			t.Error("links should not be listed without the export feature")
+			return nil, 0, nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.licManager = newTestLicenseManager(license.TierFree)
+
+	var buf bytes.Buffer
+	dr, _ := models.DateRangeFromPreset("30d", time.UTC)
+	err := svc.ExportWorkspaceLinks(context.Background(), uuid.New(), dr, models.ExportCSV, &buf)
+	if err == nil {
+		t.Fatal("expected error for free tier")
+	}
+
+	appErr, ok := err.(*httputil.AppError)
+	if !ok || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Errorf("expected PAYMENT_REQUIRED error, got: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
+
+// lengthCodeGen returns codes of the requested length and records each length.
+type lengthCodeGen struct {
+	lengths []int
+}
+
+func (g *lengthCodeGen) Generate() string {
+	return g.GenerateWithLength(shortcode.DefaultLength)
+}
+
+func (g *lengthCodeGen) GenerateWithLength(n int) string {
+	g.lengths = append(g.lengths, n)
+	return strings.Repeat("a", n)
+}
+
+func TestGenerateUniqueShortCode_EscalatesLengthUnderHighCollision(t *testing.T) {
+	// Simulate a saturated namespace: every code at the default length exists.
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, code string) (bool, error) {
+			return len(code) <= shortcode.DefaultLength, nil
+		},
+	}
+
+	gen := &lengthCodeGen{}
+	svc := newTestService(repo, &mockClickRepo{}, gen)
+	svc.cfg.Links = config.LinksConfig{ShortCodeMaxRetries: 10, ShortCodeEscalateAfter: 3}
+
+	code, err := svc.generateUniqueShortCode(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(code) != shortcode.DefaultLength+1 {
+		t.Errorf("expected code length %d, got %d", shortcode.DefaultLength+1, len(code))
+	}
+
+	want := []int{7, 7, 7, 8}
+	if len(gen.lengths) != len(want) {
+		t.Fatalf("expected %d attempts, got %d (%v)", len(want), len(gen.lengths), gen.lengths)
+	}
+	for i, n := range want {
+		if gen.lengths[i] != n {
+			t.Errorf("attempt %d: expected length %d, got %d", i+1, n, gen.lengths[i])
+		}
+	}
+}
+
+func TestGenerateUniqueShortCode_ConfiguredMaxRetries(t *testing.T) {
+	calls := 0
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) {
+			calls++
+			return true, nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &lengthCodeGen{})
+	svc.cfg.Links = config.LinksConfig{ShortCodeMaxRetries: 2}
+
+	if _, err := svc.generateUniqueShortCode(context.Background()); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestTransferLink_ClearsForeignDomain(t *testing.T) {
+	linkID := uuid.New()
+	actorID := uuid.New()
+	fromWS := uuid.New()
+	toWS := uuid.New()
+
+	domainRepo := newMockDomainRepo()
+	domain, _ := domainRepo.Create(context.Background(), sqlc.CreateDomainParams{WorkspaceID: fromWS, Domain: "go.example.com"})
+
+	existing := makeLink(linkID, actorID, fromWS, "move-me")
+	existing.DomainID = &domain.ID
+
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) { return existing, nil },
+		transferFn: func(_ context.Context, params sqlc.TransferLinkParams) (*models.Link, error) {
+			if params.WorkspaceID != toWS {
+				t.Errorf("expected workspace_id %s, got %s", toWS, params.WorkspaceID)
+			}
+			if params.DomainID.Valid {
+				t.Error("expected domain binding to be cleared")
+			}
+			return makeLink(linkID, actorID, toWS, "move-me"), nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.memberRepo = &mockMemberRepo{roles: map[uuid.UUID]models.WorkspaceRole{
+		fromWS: models.RoleEditor,
+		toWS:   models.RoleAdmin,
+	}}
+	svc.domainRepo = domainRepo
+
+	link, err := svc.TransferLink(context.Background(), linkID, fromWS, toWS, actorID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.WorkspaceID != toWS {
+		t.Errorf("expected link in workspace %s, got %s", toWS, link.WorkspaceID)
+	}
+	if link.ShortCode != "move-me" {
+		t.Errorf("expected short code to be preserved, got %s", link.ShortCode)
+	}
+}
+
+func TestTransferLink_RequiresEditorInDestination(t *testing.T) {
+	linkID := uuid.New()
+	actorID := uuid.New()
+	fromWS := uuid.New()
+	toWS := uuid.New()
+
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+			return makeLink(linkID, actorID, fromWS, "move-me"), nil
+		},
+		transferFn: func(_ context.Context, _ sqlc.TransferLinkParams) (*models.Link, error) {
+			t.Error("transfer should not be called")
+			return nil, nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.memberRepo = &mockMemberRepo{roles: map[uuid.UUID]models.WorkspaceRole{
+		fromWS: models.RoleOwner,
+		toWS:   models.RoleViewer,
+	}}
+
+	_, err := svc.TransferLink(context.Background(), linkID, fromWS, toWS, actorID)
+	appErr, ok := err.(*httputil.AppError)
+	if !ok || appErr.Code != "FORBIDDEN" {
+		t.Fatalf("expected FORBIDDEN error, got %v", err)
+	}
+}
+
+func TestTransferLink_DestinationAtLinkLimit(t *testing.T) {
+	linkID := uuid.New()
+	actorID := uuid.New()
+	fromWS := uuid.New()
+	toWS := uuid.New()
+
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+			return makeLink(linkID, actorID, fromWS, "move-me"), nil
+		},
+		countCreatedFn: func(_ context.Context, workspaceID uuid.UUID, _, _ time.Time) (int64, error) {
+			if workspaceID != toWS {
+				t.Errorf("expected count for destination workspace, got %s", workspaceID)
+			}
+			return 100, nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.memberRepo = &mockMemberRepo{roles: map[uuid.UUID]models.WorkspaceRole{
+		fromWS: models.RoleEditor,
+		toWS:   models.RoleEditor,
+	}}
+
+	_, err := svc.TransferLink(context.Background(), linkID, fromWS, toWS, actorID)
+	appErr, ok := err.(*httputil.AppError)
+	if !ok || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Fatalf("expected PAYMENT_REQUIRED error, got %v", err)
+	}
+}
+
+func TestTransferLink_OlderLinkIgnoresDestinationLimit(t *testing.T) {
+	linkID := uuid.New()
+	actorID := uuid.New()
+	fromWS := uuid.New()
+	toWS := uuid.New()
+
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+			link := makeLink(linkID, actorID, fromWS, "move-me")
+			link.CreatedAt = time.Now().AddDate(0, -2, 0)
+			return link, nil
+		},
+		countCreatedFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) { return 100, nil },
+		transferFn: func(_ context.Context, params sqlc.TransferLinkParams) (*models.Link, error) {
+			return makeLink(params.ID, actorID, params.WorkspaceID, "move-me"), nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.memberRepo = &mockMemberRepo{roles: map[uuid.UUID]models.WorkspaceRole{
+		fromWS: models.RoleEditor,
+		toWS:   models.RoleEditor,
+	}}
+
+	// The link was created in an earlier month, so it isn't counted again
+	if _, err := svc.TransferLink(context.Background(), linkID, fromWS, toWS, actorID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateLink_LinkLimitCountsCurrentPeriod(t *testing.T) {
+	var gotStart, gotEnd time.Time
+	repo := &mockLinkRepo{
+		countCreatedFn: func(_ context.Context, _ uuid.UUID, start, end time.Time) (int64, error) {
+			gotStart, gotEnd = start, end
+			return 99, nil
+		},
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			return makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode), nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "abc1234"})
+
+	if _, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{URL: "https://example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantStart, wantEnd := UsagePeriod(time.Now())
+	if !gotStart.Equal(wantStart) || !gotEnd.Equal(wantEnd) {
+		t.Errorf("counted links created in [%s, %s), want [%s, %s)", gotStart, gotEnd, wantStart, wantEnd)
+	}
+}
+
+func TestCreateLink_LinkLimitReached(t *testing.T) {
+	repo := &mockLinkRepo{
+		countCreatedFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) { return 100, nil },
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			t.Error("create should not be called")
+			return nil, nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "abc1234"})
+
+	_, err := svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{URL: "https://example.com"})
+	appErr, ok := err.(*httputil.AppError)
+	if !ok || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Fatalf("expected PAYMENT_REQUIRED error, got %v", err)
+	}
+}
+
+func TestBulkCreateLinks_BatchExceedsRemainingQuota(t *testing.T) {
+	repo := &mockLinkRepo{
+		countCreatedFn: func(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) { return 98, nil },
+		createFn: func(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+			t.Error("create should not be called")
+			return nil, nil
+		},
+	}
+
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{code: "abc1234"})
+
+	input := models.BulkCreateLinkInput{Links: []models.CreateLinkInput{
+		{URL: "https://example.com/1"},
+		{URL: "https://example.com/2"},
+		{URL: "https://example.com/3"},
+	}}
+
+	// The limit is checked before any link is created
+	_, err := svc.BulkCreateLinks(context.Background(), uuid.New(), uuid.New(), input)
+	appErr, ok := err.(*httputil.AppError)
+	if !ok || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Fatalf("expected PAYMENT_REQUIRED error, got %v", err)
+	}
+}
+
+func TestPartitionOwnedLinks(t *testing.T) {
+	workspaceID := uuid.New()
+	owned := uuid.New()
+	foreign := uuid.New()
+	missing := uuid.New()
+
+	repo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+			switch id {
+			case owned:
+				return &models.Link{ID: id, WorkspaceID: workspaceID}, nil
+			case foreign:
+				return &models.Link{ID: id, WorkspaceID: uuid.New()}, nil
+			default:
+				return nil, httputil.NotFound("link")
+			}
+		},
+	}
+
+	links, result, err := partitionOwnedLinks(context.Background(), repo, workspaceID, []uuid.UUID{owned, foreign, missing, owned})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(links) != 1 || links[0].ID != owned {
+		t.Errorf("expected only the owned link, got %v", links)
+	}
+	if result.Requested != 3 || result.Succeeded != 1 {
+		t.Errorf("expected 3 requested and 1 succeeded, got %d and %d", result.Requested, result.Succeeded)
+	}
+	if len(result.Skipped) != 2 || result.Skipped[0].LinkID != foreign || result.Skipped[1].LinkID != missing {
+		t.Errorf("unexpected skipped links: %+v", result.Skipped)
+	}
+}
+
+func TestBulkUpdateLinks_Validation(t *testing.T) {
+	svc := newTestService(&mockLinkRepo{
+		countTagsFn: func(_ context.Context, _ uuid.UUID, tagIDs []uuid.UUID) (int64, error) {
+			return int64(len(tagIDs)) - 1, nil
+		},
+	}, &mockClickRepo{}, &mockCodeGen{})
+	linkIDs := []uuid.UUID{uuid.New()}
+
+	tests := []struct {
+		name  string
+		input models.BulkUpdateLinksInput
+	}{
+		{"no changes", models.BulkUpdateLinksInput{LinkIDs: linkIDs}},
+		{"bad expiry", models.BulkUpdateLinksInput{LinkIDs: linkIDs, ExpiresAt: strPtr("tomorrow")}},
+		{"foreign tag", models.BulkUpdateLinksInput{LinkIDs: linkIDs, AddTagIDs: []uuid.UUID{uuid.New()}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.BulkUpdateLinks(context.Background(), uuid.New(), tt.input)
+			var appErr *httputil.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+const (
+	maxLinkTemplatesPerWorkspace = 50
+	// maxTemplateExpiryDays caps expires_in_days at about ten years.
+	maxTemplateExpiryDays = 3650
+)
+
+// LinkTemplateService manages a workspace's link templates. Links are
+// created from them by LinkService.CreateLink.
+type LinkTemplateService interface {
+	CreateTemplate(ctx context.Context, workspaceID, userID uuid.UUID, input models.CreateLinkTemplateInput) (*models.LinkTemplate, error)
+	ListTemplates(ctx context.Context, workspaceID uuid.UUID) ([]*models.LinkTemplate, error)
+	GetTemplate(ctx context.Context, id, workspaceID uuid.UUID) (*models.LinkTemplate, error)
+	UpdateTemplate(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateLinkTemplateInput) (*models.LinkTemplate, error)
+	DeleteTemplate(ctx context.Context, id, workspaceID uuid.UUID) error
+}
+
+type linkTemplateService struct {
+	templateRepo repository.LinkTemplateRepository
+	domainRepo   repository.DomainRepository
+	licManager   *license.Manager
+	logger       *zap.Logger
+}
+
+func NewLinkTemplateService(
+	templateRepo repository.LinkTemplateRepository,
+	domainRepo repository.DomainRepository,
+	licManager *license.Manager,
+	logger *zap.Logger,
+) LinkTemplateService {
+	return &linkTemplateService{
+		templateRepo: templateRepo,
+		domainRepo:   domainRepo,
+		licManager:   licManager,
+		logger:       logger,
+	}
+}
+
+func (s *linkTemplateService) CreateTemplate(ctx context.Context, workspaceID, userID uuid.UUID, input models.CreateLinkTemplateInput) (*models.LinkTemplate, error) {
+	count, err := s.templateRepo.Count(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxLinkTemplatesPerWorkspace {
+		return nil, httputil.Validation("templates", fmt.Sprintf("a workspace can have at most %d link templates", maxLinkTemplatesPerWorkspace))
+	}
+
+	name, err := templateName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.checkSettings(ctx, workspaceID, input.Settings)
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := hashTemplatePassword(input.Settings.Password, pgtype.Text{})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.templateRepo.Create(ctx, sqlc.CreateLinkTemplateParams{
+		WorkspaceID:  workspaceID,
+		CreatedBy:    pgtype.UUID{Bytes: userID, Valid: true},
+		Name:         name,
+		Description:  models.OptionalText(input.Description),
+		Settings:     settings,
+		PasswordHash: passwordHash,
+	})
+}
+
+func (s *linkTemplateService) ListTemplates(ctx context.Context, workspaceID uuid.UUID) ([]*models.LinkTemplate, error) {
+	return s.templateRepo.List(ctx, workspaceID)
+}
+
+func (s *linkTemplateService) GetTemplate(ctx context.Context, id, workspaceID uuid.UUID) (*models.LinkTemplate, error) {
+	return getWorkspaceTemplate(ctx, s.templateRepo, id, workspaceID)
+}
+
+func (s *linkTemplateService) UpdateTemplate(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateLinkTemplateInput) (*models.LinkTemplate, error) {
+	tmpl, err := s.GetTemplate(ctx, id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateLinkTemplateParams{
+		ID:           id,
+		Name:         tmpl.Name,
+		Description:  models.OptionalText(tmpl.Description),
+		PasswordHash: pgtype.Text{String: tmpl.PasswordHash, Valid: tmpl.PasswordHash != ""},
+	}
+	if input.Name != nil {
+		if params.Name, err = templateName(*input.Name); err != nil {
+			return nil, err
+		}
+	}
+	if input.Description != nil {
+		params.Description = models.OptionalText(input.Description)
+		if strings.TrimSpace(*input.Description) == "" {
+			params.Description = pgtype.Text{}
+		}
+	}
+
+	// Unchanged settings are checked again too, so that saving a template
+	// whose domain has since been removed reports it.
+	settings := tmpl.Settings
+	if input.Settings != nil {
+		settings = *input.Settings
+		if params.PasswordHash, err = hashTemplatePassword(settings.Password, params.PasswordHash); err != nil {
+			return nil, err
+		}
+	}
+	if params.Settings, err = s.checkSettings(ctx, workspaceID, settings); err != nil {
+		return nil, err
+	}
+
+	return s.templateRepo.Update(ctx, params)
+}
+
+func (s *linkTemplateService) DeleteTemplate(ctx context.Context, id, workspaceID uuid.UUID) error {
+	if _, err := s.GetTemplate(ctx, id, workspaceID); err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(ctx, id)
+}
+
+// checkSettings validates template settings as CreateLink would validate
+// the same fields, and encodes them for storage without the password.
+func (s *linkTemplateService) checkSettings(ctx context.Context, workspaceID uuid.UUID, settings models.LinkTemplateSettings) ([]byte, error) {
+	if settings.Cloak {
+		if err := requireCloakingLicense(s.licManager); err != nil {
+			return nil, err
+		}
+	}
+	if settings.ExpiresInDays != nil && (*settings.ExpiresInDays < 1 || *settings.ExpiresInDays > maxTemplateExpiryDays) {
+		return nil, httputil.Validation("expires_in_days", fmt.Sprintf("must be between 1 and %d", maxTemplateExpiryDays))
+	}
+	if _, err := resolveParamPrecedence(settings.ParamPrecedence); err != nil {
+		return nil, err
+	}
+	repeatVisitURL, err := resolveOptionalURL("repeat_visit_url", settings.RepeatVisitURL)
+	if err != nil {
+		return nil, err
+	}
+	if repeatVisitURL.Valid {
+		settings.RepeatVisitURL = &repeatVisitURL.String
+	}
+	pixels, err := resolvePixels(settings.FacebookPixelID, settings.GoogleTagID)
+	if err != nil {
+		return nil, err
+	}
+	if pixels.enabled() {
+		if err := requirePixelsLicense(s.licManager); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := resolveSchedule(settings.Schedule); err != nil {
+		return nil, err
+	}
+	if settings.Schedule != nil && len(settings.Schedule.Windows) == 0 {
+		settings.Schedule = nil
+	}
+	if _, err := resolveWorkspaceDomain(ctx, s.domainRepo, workspaceID, settings.DomainID); err != nil {
+		return nil, err
+	}
+
+	settings.Password = nil
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to encode link template settings")
+	}
+	return data, nil
+}
+
+func getWorkspaceTemplate(ctx context.Context, templateRepo repository.LinkTemplateRepository, id, workspaceID uuid.UUID) (*models.LinkTemplate, error) {
+	tmpl, err := templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("link template")
+	}
+	return tmpl, nil
+}
+
+func templateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", httputil.Validation("name", "must not be empty")
+	}
+	return name, nil
+}
+
+// hashTemplatePassword hashes a new template password. A nil password keeps
+// current and an empty one removes it.
+func hashTemplatePassword(password *string, current pgtype.Text) (pgtype.Text, error) {
+	if password == nil {
+		return current, nil
+	}
+	if *password == "" {
+		return pgtype.Text{}, nil
+	}
+	hash, err := crypto.HashPassword(*password)
+	if err != nil {
+		return pgtype.Text{}, httputil.Wrap(err, "failed to hash password")
+	}
+	return pgtype.Text{String: hash, Valid: true}, nil
+}
+
+// applyLinkTemplate fills the fields of input that the request didn't send
+// from tmpl's settings. It returns the template's password hash when the
+// link should be protected by it.
+func applyLinkTemplate(tmpl *models.LinkTemplate, input models.CreateLinkInput, now time.Time) (models.CreateLinkInput, pgtype.Text) {
+	t := tmpl.Settings
+
+	fillFromTemplate(&input.DomainID, t.DomainID, input.Sent("domain_id"))
+	fillFromTemplate(&input.MaxClicks, t.MaxClicks, input.Sent("max_clicks"))
+	fillFromTemplate(&input.UTMSource, t.UTMSource, input.Sent("utm_source"))
+	fillFromTemplate(&input.UTMMedium, t.UTMMedium, input.Sent("utm_medium"))
+	fillFromTemplate(&input.UTMCampaign, t.UTMCampaign, input.Sent("utm_campaign"))
+	fillFromTemplate(&input.UTMTerm, t.UTMTerm, input.Sent("utm_term"))
+	fillFromTemplate(&input.UTMContent, t.UTMContent, input.Sent("utm_content"))
+	fillFromTemplate(&input.ParamPrecedence, t.ParamPrecedence, input.Sent("param_precedence"))
+	fillFromTemplate(&input.RepeatVisitURL, t.RepeatVisitURL, input.Sent("repeat_visit_url"))
+	fillFromTemplate(&input.FacebookPixelID, t.FacebookPixelID, input.Sent("facebook_pixel_id"))
+	fillFromTemplate(&input.GoogleTagID, t.GoogleTagID, input.Sent("google_tag_id"))
+	fillFromTemplate(&input.Schedule, t.Schedule, input.Sent("schedule"))
+
+	fillFlagFromTemplate(&input.Cloak, t.Cloak, input.Sent("cloak"))
+	fillFlagFromTemplate(&input.ForwardParams, t.ForwardParams, input.Sent("forward_params"))
+	fillFlagFromTemplate(&input.ForceHTTPS, t.ForceHTTPS, input.Sent("force_https"))
+	fillFlagFromTemplate(&input.OncePerVisitor, t.OncePerVisitor, input.Sent("once_per_visitor"))
+	fillFlagFromTemplate(&input.Interstitial, t.Interstitial, input.Sent("interstitial"))
+	fillFlagFromTemplate(&input.GenerateQR, t.GenerateQR, input.Sent("generate_qr"))
+
+	if t.ExpiresInDays != nil && input.ExpiresAt == nil && !input.Sent("expires_at") {
+		expiresAt := now.AddDate(0, 0, int(*t.ExpiresInDays)).UTC().Format(time.RFC3339)
+		input.ExpiresAt = &expiresAt
+	}
+
+	var passwordHash pgtype.Text
+	if tmpl.PasswordHash != "" && input.Password == nil && !input.Sent("password") {
+		passwordHash = pgtype.Text{String: tmpl.PasswordHash, Valid: true}
+	}
+	return input, passwordHash
+}
+
+func fillFromTemplate[T any](field **T, value *T, sent bool) {
+	if *field == nil && !sent {
+		*field = value
+	}
+}
+
+func fillFlagFromTemplate(field *bool, value, sent bool) {
+	if !*field && !sent {
+		*field = value
+	}
+}
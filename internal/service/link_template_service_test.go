@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type mockLinkTemplateRepo struct {
+	templates map[uuid.UUID]*models.LinkTemplate
+	created   *sqlc.CreateLinkTemplateParams
+}
+
+func newMockLinkTemplateRepo(templates ...*models.LinkTemplate) *mockLinkTemplateRepo {
+	m := &mockLinkTemplateRepo{templates: make(map[uuid.UUID]*models.LinkTemplate)}
+	for _, t := range templates {
+		m.templates[t.ID] = t
+	}
+	return m
+}
+
+func (m *mockLinkTemplateRepo) Create(_ context.Context, params sqlc.CreateLinkTemplateParams) (*models.LinkTemplate, error) {
+	m.created = &params
+	tmpl := models.LinkTemplateFromSqlc(sqlc.LinkTemplate{
+		ID:           uuid.New(),
+		WorkspaceID:  params.WorkspaceID,
+		CreatedBy:    params.CreatedBy,
+		Name:         params.Name,
+		Description:  params.Description,
+		Settings:     params.Settings,
+		PasswordHash: params.PasswordHash,
+	})
+	m.templates[tmpl.ID] = tmpl
+	return tmpl, nil
+}
+
+func (m *mockLinkTemplateRepo) GetByID(_ context.Context, id uuid.UUID) (*models.LinkTemplate, error) {
+	if t, ok := m.templates[id]; ok {
+		return t, nil
+	}
+	return nil, httputil.NotFound("link template")
+}
+
+func (m *mockLinkTemplateRepo) List(_ context.Context, workspaceID uuid.UUID) ([]*models.LinkTemplate, error) {
+	var result []*models.LinkTemplate
+	for _, t := range m.templates {
+		if t.WorkspaceID == workspaceID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockLinkTemplateRepo) Count(ctx context.Context, workspaceID uuid.UUID) (int64, error) {
+	templates, _ := m.List(ctx, workspaceID)
+	return int64(len(templates)), nil
+}
+
+func (m *mockLinkTemplateRepo) Update(_ context.Context, params sqlc.UpdateLinkTemplateParams) (*models.LinkTemplate, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockLinkTemplateRepo) Delete(_ context.Context, id uuid.UUID) error {
+	delete(m.templates, id)
+	return nil
+}
+
+func validationField(err error) string {
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+		return ""
+	}
+	field, _ := appErr.Details["field"].(string)
+	return field
+}
+
+func TestApplyLinkTemplate(t *testing.T) {
+	tmpl := &models.LinkTemplate{
+		Settings: models.LinkTemplateSettings{
+			UTMSource:     strPtr("newsletter"),
+			UTMMedium:     strPtr("email"),
+			ExpiresInDays: int32Ptr(30),
+			ForwardParams: true,
+			Interstitial:  true,
+		},
+		PasswordHash: "template-hash",
+	}
+
+	var input models.CreateLinkInput
+	body := `{"url":"https://example.com","utm_medium":"social","interstitial":false,"password":null}`
+	if err := json.Unmarshal([]byte(body), &input); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	merged, passwordHash := applyLinkTemplate(tmpl, input, now)
+
+	if merged.UTMSource == nil || *merged.UTMSource != "newsletter" {
+		t.Errorf("utm_source = %v, want the template's", merged.UTMSource)
+	}
+	if merged.UTMMedium == nil || *merged.UTMMedium != "social" {
+		t.Errorf("utm_medium = %v, want the request's", merged.UTMMedium)
+	}
+	if !merged.ForwardParams {
+		t.Error("forward_params not taken from the template")
+	}
+	if merged.Interstitial {
+		t.Error("an explicit interstitial=false was overridden by the template")
+	}
+	if merged.ExpiresAt == nil || *merged.ExpiresAt != "2026-01-31T12:00:00Z" {
+		t.Errorf("expires_at = %v, want 30 days after now", merged.ExpiresAt)
+	}
+	if passwordHash.Valid {
+		t.Error("an explicit null password was overridden by the template")
+	}
+
+	// Inputs built in code have nothing marked as sent
+	_, passwordHash = applyLinkTemplate(tmpl, models.CreateLinkInput{URL: "https://example.com"}, now)
+	if !passwordHash.Valid || passwordHash.String != "template-hash" {
+		t.Errorf("password hash = %+v, want the template's", passwordHash)
+	}
+}
+
+func TestCreateTemplate_HashesPassword(t *testing.T) {
+	repo := newMockLinkTemplateRepo()
+	svc := NewLinkTemplateService(repo, newMockDomainRepo(), newTestLicenseManager(license.TierFree), zap.NewNop())
+
+	tmpl, err := svc.CreateTemplate(context.Background(), uuid.New(), uuid.New(), models.CreateLinkTemplateInput{
+		Name:     "  Newsletter  ",
+		Settings: models.LinkTemplateSettings{Password: strPtr("secret123"), UTMSource: strPtr("newsletter")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tmpl.Name != "Newsletter" {
+		t.Errorf("name = %q, want it trimmed", tmpl.Name)
+	}
+	if ok, _ := crypto.VerifyPassword("secret123", tmpl.PasswordHash); !tmpl.HasPassword || !ok {
+		t.Error("template password not hashed")
+	}
+	var stored map[string]any
+	if err := json.Unmarshal(repo.created.Settings, &stored); err != nil {
+		t.Fatalf("decode stored settings: %v", err)
+	}
+	if _, ok := stored["password"]; ok {
+		t.Error("plain-text password stored with the settings")
+	}
+}
+
+func TestCreateTemplate_Validation(t *testing.T) {
+	otherDomain := &models.Domain{ID: uuid.New(), WorkspaceID: uuid.New(), Domain: "go.example.com", IsVerified: true}
+	domainRepo := newMockDomainRepo()
+	domainRepo.domains[otherDomain.ID] = otherDomain
+
+	tests := []struct {
+		name     string
+		settings models.LinkTemplateSettings
+		field    string
+	}{
+		{"other workspace's domain", models.LinkTemplateSettings{DomainID: &otherDomain.ID}, "domain_id"},
+		{"bad precedence", models.LinkTemplateSettings{ParamPrecedence: strPtr("sideways")}, "param_precedence"},
+		{"bad pixel", models.LinkTemplateSettings{FacebookPixelID: strPtr("not-a-pixel")}, "facebook_pixel_id"},
+		{"expiry out of range", models.LinkTemplateSettings{ExpiresInDays: int32Ptr(0)}, "expires_in_days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewLinkTemplateService(newMockLinkTemplateRepo(), domainRepo, newTestLicenseManager(license.TierFree), zap.NewNop())
+			_, err := svc.CreateTemplate(context.Background(), uuid.New(), uuid.New(), models.CreateLinkTemplateInput{
+				Name:     "Template",
+				Settings: tt.settings,
+			})
+			if got := validationField(err); got != tt.field {
+				t.Errorf("error = %v, want a validation error on %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestCreateLink_FromTemplate(t *testing.T) {
+	workspaceID := uuid.New()
+	tmpl := &models.LinkTemplate{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		Settings: models.LinkTemplateSettings{
+			UTMCampaign:   strPtr("spring"),
+			ForwardParams: true,
+		},
+	}
+
+	repo := &mockLinkRepo{
+		shortCodeExistsFn: func(_ context.Context, _ string) (bool, error) { return false, nil },
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			if params.UtmCampaign.String != "spring" || !params.ForwardParams {
+				t.Errorf("template settings not applied: campaign=%+v forward=%v", params.UtmCampaign, params.ForwardParams)
+			}
+			return makeLink(uuid.New(), params.UserID, params.WorkspaceID, params.ShortCode), nil
+		},
+	}
+	svc := newTestService(repo, &mockClickRepo{}, &mockCodeGen{})
+	svc.templateRepo = newMockLinkTemplateRepo(tmpl)
+
+	_, err := svc.CreateLink(context.Background(), uuid.New(), workspaceID, models.CreateLinkInput{
+		URL:        "https://example.com",
+		TemplateID: &tmpl.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Another workspace's template is reported as missing
+	_, err = svc.CreateLink(context.Background(), uuid.New(), uuid.New(), models.CreateLinkInput{
+		URL:        "https://example.com",
+		TemplateID: &tmpl.ID,
+	})
+	if got := validationField(err); got != "template_id" {
+		t.Errorf("error = %v, want a validation error on template_id", err)
+	}
+}
+
+func TestCreateLink_TemplateDomainRemoved(t *testing.T) {
+	workspaceID := uuid.New()
+	tmpl := &models.LinkTemplate{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		Settings:    models.LinkTemplateSettings{DomainID: ptrUUID(uuid.New())},
+	}
+
+	svc := newTestService(&mockLinkRepo{}, &mockClickRepo{}, &mockCodeGen{})
+	svc.templateRepo = newMockLinkTemplateRepo(tmpl)
+	svc.domainRepo = newMockDomainRepo()
+
+	_, err := svc.CreateLink(context.Background(), uuid.New(), workspaceID, models.CreateLinkInput{
+		URL:        "https://example.com",
+		TemplateID: &tmpl.ID,
+	})
+	if got := validationField(err); got != "template_id" {
+		t.Errorf("error = %v, want a validation error on template_id", err)
+	}
+}
+
+func ptrUUID(id uuid.UUID) *uuid.UUID { return &id }
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -21,15 +22,20 @@ type QRCodeService interface {
 	CreateQRCode(ctx context.Context, linkID, workspaceID uuid.UUID, input models.CreateQRCodeInput) (*models.QRCode, error)
 	GetQRCode(ctx context.Context, id uuid.UUID) (*models.QRCode, error)
 	GetQRCodeForLink(ctx context.Context, linkID uuid.UUID) (*models.QRCode, error)
-	DownloadQRCode(ctx context.Context, linkID uuid.UUID, format string) ([]byte, string, error)
+	UpdateQRCode(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateQRCodeInput) (*models.QRCode, error)
+	DownloadQRCode(ctx context.Context, linkID uuid.UUID, format string, print *qrcode.PrintProfile) ([]byte, string, error)
 	DeleteQRCode(ctx context.Context, id uuid.UUID) error
-	BulkGenerateQRCodes(ctx context.Context, workspaceID uuid.UUID, input models.BulkQRCodeInput) (*qrcode.BatchResult, error)
+	BulkGenerateQRCodes(ctx context.Context, workspaceID uuid.UUID, input models.BulkQRCodeInput, progress qrcode.ProgressFunc) (*qrcode.BatchResult, error)
+	StoreBatchArchive(ctx context.Context, workspaceID uuid.UUID, zipData []byte) (string, error)
 	GetStyleTemplates() map[string]qrcode.StyleTemplate
+	InlineDataURI(url string) (string, error)
 }
 
 type qrCodeService struct {
 	qrRepo     repository.QRCodeRepository
 	linkRepo   repository.LinkRepository
+	wsRepo     repository.WorkspaceRepository
+	domainRepo repository.DomainRepository
 	generator  *qrcode.Generator
 	batchGen   *qrcode.BatchGenerator
 	store      storage.ObjectStorage
@@ -41,6 +47,8 @@ type qrCodeService struct {
 func NewQRCodeService(
 	qrRepo repository.QRCodeRepository,
 	linkRepo repository.LinkRepository,
+	wsRepo repository.WorkspaceRepository,
+	domainRepo repository.DomainRepository,
 	generator *qrcode.Generator,
 	batchGen *qrcode.BatchGenerator,
 	store storage.ObjectStorage,
@@ -51,6 +59,8 @@ func NewQRCodeService(
 	return &qrCodeService{
 		qrRepo:     qrRepo,
 		linkRepo:   linkRepo,
+		wsRepo:     wsRepo,
+		domainRepo: domainRepo,
 		generator:  generator,
 		batchGen:   batchGen,
 		store:      store,
@@ -81,9 +91,11 @@ func (s *qrCodeService) CreateQRCode(ctx context.Context, linkID, workspaceID uu
 	if input.QRType == "" {
 		input.QRType = "dynamic"
 	}
-	if input.ErrorCorrection == "" {
-		input.ErrorCorrection = "M"
+	ec, err := s.resolveErrorCorrection(ctx, workspaceID, input.ErrorCorrection)
+	if err != nil {
+		return nil, err
 	}
+	input.ErrorCorrection = ec
 	if input.ForegroundColor == "" {
 		input.ForegroundColor = "#000000"
 	}
@@ -108,7 +120,7 @@ func (s *qrCodeService) CreateQRCode(ctx context.Context, linkID, workspaceID uu
 	// Build URL for QR code
 	var targetURL string
 	if input.QRType == "dynamic" {
-		targetURL = s.cfg.App.RedirectURL + "/" + link.ShortCode
+		targetURL = s.dynamicTargetURL(ctx, link)
 	} else {
 		targetURL = link.URL
 	}
@@ -125,8 +137,7 @@ func (s *qrCodeService) CreateQRCode(ctx context.Context, linkID, workspaceID uu
 		Margin:          int(margin),
 	}
 
-	qrID := uuid.New()
-	storageKey := fmt.Sprintf("qr/%s/%s.png", linkID.String(), qrID.String())
+	storageKey := s.store.WorkspaceKey(link.WorkspaceID, qrImageKey(linkID, uuid.New()))
 
 	pngURL, err := s.generator.GenerateAndUpload(ctx, targetURL, storageKey, opts)
 	if err != nil {
@@ -164,7 +175,115 @@ func (s *qrCodeService) GetQRCodeForLink(ctx context.Context, linkID uuid.UUID)
 	return s.qrRepo.GetByLinkID(ctx, linkID)
 }
 
-func (s *qrCodeService) DownloadQRCode(ctx context.Context, linkID uuid.UUID, format string) ([]byte, string, error) {
+// UpdateQRCode re-renders a QR code with new style options and overwrites
+// its stored image. The row is updated in place, so its scan count and the
+// clicks recorded through it are kept.
+func (s *qrCodeService) UpdateQRCode(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateQRCodeInput) (*models.QRCode, error) {
+	qr, err := s.qrRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	link, err := s.linkRepo.GetByID(ctx, qr.LinkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("qr_code")
+	}
+
+	// Only the options being changed need the Pro tier, so a code
+	// customized under an earlier license can still be resized or reset.
+	// An empty logo_url removes the logo.
+	var newLogo *string
+	if stringFromPtr(input.LogoURL) != "" {
+		newLogo = input.LogoURL
+	}
+	if isCustomized(models.CreateQRCodeInput{
+		ForegroundColor: stringFromPtr(input.ForegroundColor),
+		BackgroundColor: stringFromPtr(input.BackgroundColor),
+		LogoURL:         newLogo,
+		DotStyle:        stringFromPtr(input.DotStyle),
+		CornerStyle:     stringFromPtr(input.CornerStyle),
+	}) {
+		if !s.licManager.HasFeature(license.FeatureQRCustomization) {
+			return nil, httputil.PaymentRequiredWithDetails("qr_customization", "pro")
+		}
+	}
+
+	params := sqlc.UpdateQRCodeParams{ID: qr.ID}
+	if input.ErrorCorrection != nil {
+		level, err := s.resolveErrorCorrection(ctx, workspaceID, *input.ErrorCorrection)
+		if err != nil {
+			return nil, err
+		}
+		qr.ErrorCorrection = level
+		params.ErrorCorrection = pgtype.Text{String: level, Valid: true}
+	}
+	if input.ForegroundColor != nil && *input.ForegroundColor != "" {
+		qr.ForegroundColor = *input.ForegroundColor
+		params.ForegroundColor = pgtype.Text{String: qr.ForegroundColor, Valid: true}
+	}
+	if input.BackgroundColor != nil && *input.BackgroundColor != "" {
+		qr.BackgroundColor = *input.BackgroundColor
+		params.BackgroundColor = pgtype.Text{String: qr.BackgroundColor, Valid: true}
+	}
+	if input.LogoURL != nil {
+		qr.LogoURL = newLogo
+		params.LogoUrl = pgtype.Text{String: *input.LogoURL, Valid: true}
+	}
+	if input.DotStyle != nil && *input.DotStyle != "" {
+		qr.DotStyle = *input.DotStyle
+		params.DotStyle = pgtype.Text{String: qr.DotStyle, Valid: true}
+	}
+	if input.CornerStyle != nil && *input.CornerStyle != "" {
+		qr.CornerStyle = *input.CornerStyle
+		params.CornerStyle = pgtype.Text{String: qr.CornerStyle, Valid: true}
+	}
+	if input.Size != nil {
+		qr.Size = *input.Size
+		params.Size = pgtype.Int4{Int32: qr.Size, Valid: true}
+	}
+	if input.Margin != nil {
+		qr.Margin = *input.Margin
+		params.Margin = pgtype.Int4{Int32: qr.Margin, Valid: true}
+	}
+
+	var targetURL string
+	if qr.QRType == "dynamic" {
+		targetURL = s.dynamicTargetURL(ctx, link)
+	} else {
+		targetURL = link.URL
+	}
+
+	opts := qrcode.Options{
+		Size:            int(qr.Size),
+		ErrorCorrection: qr.ErrorCorrection,
+		ForegroundColor: qr.ForegroundColor,
+		BackgroundColor: qr.BackgroundColor,
+		LogoURL:         stringFromPtr(qr.LogoURL),
+		DotStyle:        qr.DotStyle,
+		CornerStyle:     qr.CornerStyle,
+		Margin:          int(qr.Margin),
+	}
+
+	// Replace the image where it's served
+	storageKey := s.qrStorageKey(qr, link.WorkspaceID)
+	pngURL, err := s.generator.GenerateAndUpload(ctx, targetURL, storageKey, opts)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to generate QR code")
+	}
+	params.PngUrl = pgtype.Text{String: pngURL, Valid: true}
+
+	return s.qrRepo.Update(ctx, params)
+}
+
+func (s *qrCodeService) DownloadQRCode(ctx context.Context, linkID uuid.UUID, format string, print *qrcode.PrintProfile) ([]byte, string, error) {
+	if print != nil {
+		if err := print.Validate(); err != nil {
+			return nil, "", httputil.Validation("print", err.Error())
+		}
+	}
+
 	qr, err := s.qrRepo.GetByLinkID(ctx, linkID)
 	if err != nil {
 		return nil, "", err
@@ -178,7 +297,7 @@ func (s *qrCodeService) DownloadQRCode(ctx context.Context, linkID uuid.UUID, fo
 
 	var targetURL string
 	if qr.QRType == "dynamic" {
-		targetURL = s.cfg.App.RedirectURL + "/" + link.ShortCode
+		targetURL = s.dynamicTargetURL(ctx, link)
 	} else {
 		targetURL = link.URL
 	}
@@ -191,14 +310,28 @@ func (s *qrCodeService) DownloadQRCode(ctx context.Context, linkID uuid.UUID, fo
 		DotStyle:        qr.DotStyle,
 		CornerStyle:     qr.CornerStyle,
 		Margin:          int(qr.Margin),
+		Print:           print,
 	}
 
-	if format == "svg" {
+	switch format {
+	case "svg":
 		data, err := s.generator.GenerateSVG(targetURL, opts)
 		if err != nil {
 			return nil, "", httputil.Wrap(err, "failed to generate SVG")
 		}
 		return data, "image/svg+xml", nil
+	case "pdf":
+		data, err := s.generator.GeneratePDF(targetURL, opts)
+		if err != nil {
+			return nil, "", httputil.Wrap(err, "failed to generate PDF")
+		}
+		return data, "application/pdf", nil
+	case "eps":
+		data, err := s.generator.GenerateEPS(targetURL, opts)
+		if err != nil {
+			return nil, "", httputil.Wrap(err, "failed to generate EPS")
+		}
+		return data, "application/postscript", nil
 	}
 
 	// Default: PNG
@@ -217,47 +350,70 @@ func (s *qrCodeService) DeleteQRCode(ctx context.Context, id uuid.UUID) error {
 
 	// Delete from storage if we have a PNG URL
 	if qr.PngURL != nil {
-		storageKey := fmt.Sprintf("qr/%s/%s.png", qr.LinkID.String(), qr.ID.String())
-		if delErr := s.store.Delete(ctx, storageKey); delErr != nil {
-			s.logger.Warn("failed to delete QR code from storage", zap.Error(delErr))
+		if storageKey, ok := s.store.KeyForURL(*qr.PngURL); ok {
+			if delErr := s.store.Delete(ctx, storageKey); delErr != nil {
+				s.logger.Warn("failed to delete QR code from storage", zap.Error(delErr))
+			}
 		}
 	}
 
 	return s.qrRepo.Delete(ctx, id)
 }
 
-func (s *qrCodeService) BulkGenerateQRCodes(ctx context.Context, workspaceID uuid.UUID, input models.BulkQRCodeInput) (*qrcode.BatchResult, error) {
+// BulkGenerateQRCodes generates QR codes for up to QR.BatchMaxItems links.
+// Links that don't exist or belong to another workspace are reported as
+// failed items rather than failing the batch. progress may be nil.
+func (s *qrCodeService) BulkGenerateQRCodes(ctx context.Context, workspaceID uuid.UUID, input models.BulkQRCodeInput, progress qrcode.ProgressFunc) (*qrcode.BatchResult, error) {
+	if maxItems := s.cfg.QR.BatchMaxItems; maxItems > 0 && len(input.LinkIDs) > maxItems {
+		return nil, httputil.Validation("link_ids", fmt.Sprintf("at most %d links per batch", maxItems))
+	}
+
 	items := make([]qrcode.BatchItem, 0, len(input.LinkIDs))
+	valid := 0
 
 	for _, linkID := range input.LinkIDs {
 		link, err := s.linkRepo.GetByID(ctx, linkID)
 		if err != nil {
+			items = append(items, qrcode.BatchItem{LinkID: linkID, Err: err})
 			continue
 		}
 		if link.WorkspaceID != workspaceID {
+			items = append(items, qrcode.BatchItem{LinkID: linkID, Err: httputil.NotFound("link")})
 			continue
 		}
 
-		var targetURL string
-		if input.Options.QRType == "static" {
-			targetURL = link.URL
-		} else {
-			targetURL = s.cfg.App.RedirectURL + "/" + link.ShortCode
+		shortURL := s.shortLinkBaseURL(ctx, link) + "/" + link.ShortCode
+		targetURL := link.URL
+		if input.Options.QRType != "static" {
+			targetURL = qrTrackedURL(shortURL)
 		}
 
-		items = append(items, qrcode.BatchItem{
-			LinkID: linkID,
-			URL:    targetURL,
-		})
+		item := qrcode.BatchItem{
+			LinkID:         linkID,
+			URL:            targetURL,
+			ShortCode:      link.ShortCode,
+			ShortURL:       shortURL,
+			DestinationURL: link.URL,
+		}
+		if link.Title != nil {
+			item.Title = *link.Title
+		}
+		items = append(items, item)
+		valid++
 	}
 
-	if len(items) == 0 {
+	if valid == 0 {
 		return nil, httputil.Validation("link_ids", "no valid links found")
 	}
 
+	ec, err := s.resolveErrorCorrection(ctx, workspaceID, input.Options.ErrorCorrection)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := qrcode.Options{
 		Size:            512,
-		ErrorCorrection: input.Options.ErrorCorrection,
+		ErrorCorrection: ec,
 		ForegroundColor: input.Options.ForegroundColor,
 		BackgroundColor: input.Options.BackgroundColor,
 		DotStyle:        input.Options.DotStyle,
@@ -271,13 +427,116 @@ func (s *qrCodeService) BulkGenerateQRCodes(ctx context.Context, workspaceID uui
 		opts.Margin = int(*input.Options.Margin)
 	}
 
-	return s.batchGen.GenerateBatch(ctx, items, opts)
+	return s.batchGen.GenerateBatchWithProgress(ctx, items, opts, progress)
+}
+
+// dynamicTargetURL is what a dynamic QR code encodes: the short link, marked
+// so the redirect service records the click as a scan.
+func (s *qrCodeService) dynamicTargetURL(ctx context.Context, link *models.Link) string {
+	return qrTrackedURL(s.shortLinkBaseURL(ctx, link) + "/" + link.ShortCode)
+}
+
+// qrTrackedURL marks a short URL so its clicks are attributed to QR scans.
+func qrTrackedURL(shortURL string) string {
+	return shortURL + "?" + models.ClickSourceParam + "=" + models.ClickSourceQR
+}
+
+// shortLinkBaseURL returns where a link's short URL is served: its custom
+// domain when the domain is verified and belongs to the link's workspace,
+// and the default redirect host otherwise.
+func (s *qrCodeService) shortLinkBaseURL(ctx context.Context, link *models.Link) string {
+	if link.DomainID == nil || s.domainRepo == nil {
+		return s.cfg.App.RedirectURL
+	}
+
+	domain, err := s.domainRepo.GetByID(ctx, *link.DomainID)
+	if err != nil {
+		s.logger.Warn("failed to look up link domain for QR code, using default host",
+			zap.String("link_id", link.ID.String()),
+			zap.Error(err),
+		)
+		return s.cfg.App.RedirectURL
+	}
+	if domain == nil || !domain.IsVerified || domain.WorkspaceID != link.WorkspaceID {
+		return s.cfg.App.RedirectURL
+	}
+	return "https://" + domain.Domain
+}
+
+// qrImageKey is where a QR code's image is stored, before any workspace
+// prefix.
+func qrImageKey(linkID, qrID uuid.UUID) string {
+	return fmt.Sprintf("qr/%s/%s.png", linkID, qrID)
+}
+
+// qrBatchPrefix is where a workspace's bulk generation archives are stored,
+// before any workspace prefix.
+func qrBatchPrefix(workspaceID uuid.UUID) string {
+	return "qr/batches/" + workspaceID.String() + "/"
+}
+
+// qrStorageKey returns the key qr's image is stored under: the one its URL
+// points at, so images stored under an earlier key layout are replaced in
+// place, or a new key for codes without an image.
+func (s *qrCodeService) qrStorageKey(qr *models.QRCode, workspaceID uuid.UUID) string {
+	if qr.PngURL != nil {
+		if key, ok := s.store.KeyForURL(*qr.PngURL); ok {
+			return key
+		}
+	}
+	return s.store.WorkspaceKey(workspaceID, qrImageKey(qr.LinkID, qr.ID))
+}
+
+// StoreBatchArchive uploads a bulk generation ZIP and returns its URL, for
+// streamed requests that can't return the archive in the response body.
+func (s *qrCodeService) StoreBatchArchive(ctx context.Context, workspaceID uuid.UUID, zipData []byte) (string, error) {
+	key := s.store.WorkspaceKey(workspaceID, qrBatchPrefix(workspaceID)+uuid.New().String()+".zip")
+	url, err := s.store.Upload(ctx, key, zipData, "application/zip")
+	if err != nil {
+		return "", httputil.Wrap(err, "failed to store QR code archive")
+	}
+	return url, nil
 }
 
 func (s *qrCodeService) GetStyleTemplates() map[string]qrcode.StyleTemplate {
 	return qrcode.StyleTemplates
 }
 
+// inlineQRSize is the pixel size of QR codes embedded in link lists, small
+// enough to keep a page of results light.
+const inlineQRSize = 128
+
+// InlineDataURI renders a small QR code for url as a PNG data URI. Nothing
+// is stored.
+func (s *qrCodeService) InlineDataURI(url string) (string, error) {
+	opts := qrcode.DefaultOptions()
+	opts.Size = inlineQRSize
+	opts.Margin = 2
+	return s.generator.GenerateDataURI(url, opts)
+}
+
+// resolveErrorCorrection validates an explicit error-correction level, or
+// falls back to the workspace default and then "M" when none is given.
+func (s *qrCodeService) resolveErrorCorrection(ctx context.Context, workspaceID uuid.UUID, level string) (string, error) {
+	if level != "" {
+		level = strings.ToUpper(level)
+		if !models.IsValidErrorCorrection(level) {
+			return "", httputil.Validation("error_correction", "must be one of L, M, Q or H")
+		}
+		return level, nil
+	}
+
+	if s.wsRepo != nil {
+		ws, err := s.wsRepo.GetByID(ctx, workspaceID)
+		if err != nil {
+			s.logger.Warn("failed to load workspace QR defaults", zap.Error(err))
+		} else if defaults := ws.ParsedSettings().QRDefaults; defaults != nil && models.IsValidErrorCorrection(defaults.ErrorCorrection) {
+			return defaults.ErrorCorrection, nil
+		}
+	}
+	return "M", nil
+}
+
 // isCustomized returns true if any non-default customization is set.
 func isCustomized(input models.CreateQRCodeInput) bool {
 	if input.ForegroundColor != "" && input.ForegroundColor != "#000000" {
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/qrcode"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// --- Mock WorkspaceRepository ---
+
+type mockWorkspaceRepo struct {
+	repository.WorkspaceRepository
+	workspaces map[uuid.UUID]*models.Workspace
+}
+
+func (m *mockWorkspaceRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Workspace, error) {
+	ws, ok := m.workspaces[id]
+	if !ok {
+		return nil, httputil.NotFound("workspace")
+	}
+	return ws, nil
+}
+
+func TestResolveErrorCorrection(t *testing.T) {
+	wsWithDefault := uuid.New()
+	wsWithout := uuid.New()
+	svc := &qrCodeService{
+		wsRepo: &mockWorkspaceRepo{workspaces: map[uuid.UUID]*models.Workspace{
+			wsWithDefault: {ID: wsWithDefault, Settings: json.RawMessage(`{"qr_defaults":{"error_correction":"H"}}`)},
+			wsWithout:     {ID: wsWithout, Settings: json.RawMessage(`{}`)},
+		}},
+		logger: zap.NewNop(),
+	}
+
+	tests := []struct {
+		name  string
+		wsID  uuid.UUID
+		input string
+		want  string
+	}{
+		{"workspace default applies", wsWithDefault, "", "H"},
+		{"explicit value overrides", wsWithDefault, "l", "L"},
+		{"falls back to M", wsWithout, "", "M"},
+		{"unknown workspace falls back to M", uuid.New(), "", "M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.resolveErrorCorrection(context.Background(), tt.wsID, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveErrorCorrection_InvalidLevel(t *testing.T) {
+	svc := &qrCodeService{logger: zap.NewNop()}
+
+	_, err := svc.resolveErrorCorrection(context.Background(), uuid.New(), "X")
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+		t.Errorf("expected validation error, got %v", err)
+	}
+}
+
+type mockQRCodeRepo struct {
+	repository.QRCodeRepository
+	qr      *models.QRCode
+	updated *sqlc.UpdateQRCodeParams
+}
+
+func (m *mockQRCodeRepo) GetByID(_ context.Context, id uuid.UUID) (*models.QRCode, error) {
+	if m.qr == nil || m.qr.ID != id {
+		return nil, httputil.NotFound("qr_code")
+	}
+	return m.qr, nil
+}
+
+func (m *mockQRCodeRepo) Update(_ context.Context, params sqlc.UpdateQRCodeParams) (*models.QRCode, error) {
+	m.updated = &params
+	qr := *m.qr
+	return &qr, nil
+}
+
+type recordingStorage struct {
+	keys []string
+}
+
+func (s *recordingStorage) Upload(_ context.Context, key string, _ []byte, _ string) (string, error) {
+	s.keys = append(s.keys, key)
+	return "https://cdn.example.com/" + key, nil
+}
+func (s *recordingStorage) Get(_ context.Context, _ string) ([]byte, error) { return nil, nil }
+func (s *recordingStorage) Delete(_ context.Context, _ string) error        { return nil }
+func (s *recordingStorage) GetURL(key string) string                        { return key }
+func (s *recordingStorage) DeletePrefix(_ context.Context, _ string) error  { return nil }
+func (s *recordingStorage) KeyForURL(url string) (string, bool) {
+	return strings.CutPrefix(url, "https://cdn.example.com/")
+}
+func (s *recordingStorage) WorkspaceKey(_ uuid.UUID, key string) string { return key }
+
+func TestUpdateQRCode(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "qrupd12")
+	qr := &models.QRCode{
+		ID: uuid.New(), LinkID: link.ID, QRType: "dynamic", ErrorCorrection: "M",
+		ForegroundColor: "#000000", BackgroundColor: "#FFFFFF", DotStyle: "square", CornerStyle: "square",
+		Size: 512, Margin: 4, ScanCount: 42,
+	}
+	qrRepo := &mockQRCodeRepo{qr: qr}
+	store := &recordingStorage{}
+	svc := NewQRCodeService(qrRepo, &mockLinkRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*models.Link, error) { return link, nil },
+	}, nil, nil, qrcode.NewGenerator(store), nil, store, newTestLicenseManager(license.TierFree), &config.Config{}, zap.NewNop())
+
+	updated, err := svc.UpdateQRCode(context.Background(), qr.ID, link.WorkspaceID, models.UpdateQRCodeInput{
+		Size:            int32Ptr(256),
+		ErrorCorrection: strPtr("h"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantKey := "qr/" + link.ID.String() + "/" + qr.ID.String() + ".png"
+	if len(store.keys) != 1 || store.keys[0] != wantKey {
+		t.Errorf("expected the image to be re-uploaded to %s, got %v", wantKey, store.keys)
+	}
+	p := qrRepo.updated
+	if p == nil || p.Size.Int32 != 256 || p.ErrorCorrection.String != "H" || !p.PngUrl.Valid {
+		t.Errorf("unexpected update params: %+v", p)
+	}
+	if p.ForegroundColor.Valid || p.DotStyle.Valid {
+		t.Error("expected unchanged options to be left alone")
+	}
+	if updated.ScanCount != 42 {
+		t.Errorf("expected the scan count to be kept, got %d", updated.ScanCount)
+	}
+
+	// Colors are a Pro feature
+	_, err = svc.UpdateQRCode(context.Background(), qr.ID, link.WorkspaceID, models.UpdateQRCodeInput{ForegroundColor: strPtr("#FF0000")})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Errorf("expected payment required, got %v", err)
+	}
+
+	// QR codes of other workspaces can't be updated
+	if _, err := svc.UpdateQRCode(context.Background(), qr.ID, uuid.New(), models.UpdateQRCodeInput{Size: int32Ptr(300)}); err == nil {
+		t.Error("expected an error for another workspace's QR code")
+	}
+}
+
+func TestDynamicTargetURL_CustomDomain(t *testing.T) {
+	wsID := uuid.New()
+	domains := newMockDomainRepo()
+	verified := &models.Domain{ID: uuid.New(), WorkspaceID: wsID, Domain: "go.brand.example", IsVerified: true}
+	unverified := &models.Domain{ID: uuid.New(), WorkspaceID: wsID, Domain: "new.brand.example"}
+	foreign := &models.Domain{ID: uuid.New(), WorkspaceID: uuid.New(), Domain: "other.example", IsVerified: true}
+	for _, d := range []*models.Domain{verified, unverified, foreign} {
+		domains.domains[d.ID] = d
+	}
+
+	cfg := &config.Config{}
+	cfg.App.RedirectURL = "http://localhost:8081"
+	svc := &qrCodeService{domainRepo: domains, cfg: cfg, logger: zap.NewNop()}
+
+	missing := uuid.New()
+	tests := []struct {
+		name     string
+		domainID *uuid.UUID
+		want     string
+	}{
+		{"no domain", nil, "http://localhost:8081/brand1?lr_src=qr"},
+		{"verified domain", &verified.ID, "https://go.brand.example/brand1?lr_src=qr"},
+		{"unverified domain", &unverified.ID, "http://localhost:8081/brand1?lr_src=qr"},
+		{"other workspace's domain", &foreign.ID, "http://localhost:8081/brand1?lr_src=qr"},
+		{"deleted domain", &missing, "http://localhost:8081/brand1?lr_src=qr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := makeLink(uuid.New(), uuid.New(), wsID, "brand1")
+			link.DomainID = tt.domainID
+			if got := svc.dynamicTargetURL(context.Background(), link); got != tt.want {
+				t.Errorf("dynamicTargetURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineDataURI(t *testing.T) {
+	svc := NewQRCodeService(nil, nil, nil, nil, qrcode.NewGenerator(nil), nil, nil, newTestLicenseManager(license.TierFree), &config.Config{}, zap.NewNop())
+
+	uri, err := svc.InlineDataURI("http://localhost:8081/abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, ok := strings.CutPrefix(uri, "data:image/png;base64,")
+	if !ok {
+		t.Fatalf("expected a PNG data URI, got %.40q", uri)
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		t.Fatalf("invalid base64: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("invalid PNG: %v", err)
+	}
+	if w := img.Bounds().Dx(); w > inlineQRSize {
+		t.Errorf("expected at most %dpx, got %d", inlineQRSize, w)
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/redirect"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type RuleService interface {
+	CreateRule(ctx context.Context, linkID, workspaceID uuid.UUID, input models.CreateLinkRuleInput) (*models.LinkRule, error)
+	ListRules(ctx context.Context, linkID, workspaceID uuid.UUID) ([]*models.LinkRule, error)
+	UpdateRule(ctx context.Context, ruleID, linkID, workspaceID uuid.UUID, input models.UpdateLinkRuleInput) (*models.LinkRule, error)
+	DeleteRule(ctx context.Context, ruleID, linkID, workspaceID uuid.UUID) error
+	SimulateRedirect(ctx context.Context, linkID, workspaceID uuid.UUID, input models.SimulateRedirectInput) (*models.RedirectSimulation, error)
+}
+
+type ruleService struct {
+	ruleRepo   repository.LinkRuleRepository
+	linkRepo   repository.LinkRepository
+	licManager *license.Manager
+	logger     *zap.Logger
+}
+
+func NewRuleService(
+	ruleRepo repository.LinkRuleRepository,
+	linkRepo repository.LinkRepository,
+	licManager *license.Manager,
+	logger *zap.Logger,
+) RuleService {
+	return &ruleService{
+		ruleRepo:   ruleRepo,
+		linkRepo:   linkRepo,
+		licManager: licManager,
+		logger:     logger,
+	}
+}
+
+func (s *ruleService) CreateRule(ctx context.Context, linkID, workspaceID uuid.UUID, input models.CreateLinkRuleInput) (*models.LinkRule, error) {
+	if !s.licManager.HasFeature(license.FeatureConditionalRouting) {
+		return nil, httputil.PaymentRequiredWithDetails("conditional_routing", "business")
+	}
+
+	if err := s.checkLinkOwnership(ctx, linkID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	ruleType := strings.ToLower(strings.TrimSpace(input.RuleType))
+	value := strings.ToLower(strings.TrimSpace(input.Value))
+	if err := validateRuleCondition(ruleType, value); err != nil {
+		return nil, err
+	}
+
+	destination, err := normalizeURL(input.DestinationURL)
+	if err != nil {
+		return nil, httputil.Validation("destination_url", "invalid URL format")
+	}
+
+	conditions, err := json.Marshal(models.LinkRuleCondition{Value: value})
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to encode rule conditions")
+	}
+
+	isActive := true
+	if input.IsActive != nil {
+		isActive = *input.IsActive
+	}
+
+	return s.ruleRepo.Create(ctx, sqlc.CreateLinkRuleParams{
+		LinkID:         linkID,
+		RuleType:       ruleType,
+		Priority:       input.Priority,
+		IsActive:       isActive,
+		Conditions:     conditions,
+		DestinationUrl: destination,
+	})
+}
+
+func (s *ruleService) ListRules(ctx context.Context, linkID, workspaceID uuid.UUID) ([]*models.LinkRule, error) {
+	if err := s.checkLinkOwnership(ctx, linkID, workspaceID); err != nil {
+		return nil, err
+	}
+	return s.ruleRepo.ListForLink(ctx, linkID)
+}
+
+func (s *ruleService) UpdateRule(ctx context.Context, ruleID, linkID, workspaceID uuid.UUID, input models.UpdateLinkRuleInput) (*models.LinkRule, error) {
+	if !s.licManager.HasFeature(license.FeatureConditionalRouting) {
+		return nil, httputil.PaymentRequiredWithDetails("conditional_routing", "business")
+	}
+
+	existing, err := s.getOwnedRule(ctx, ruleID, linkID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateLinkRuleParams{ID: ruleID}
+
+	// Type and value are validated together, so changing one re-checks it
+	// against the other's current value.
+	ruleType, value := existing.RuleType, existing.Value
+	if input.RuleType != nil {
+		ruleType = strings.ToLower(strings.TrimSpace(*input.RuleType))
+		params.RuleType = pgtype.Text{String: ruleType, Valid: true}
+	}
+	if input.Value != nil {
+		value = strings.ToLower(strings.TrimSpace(*input.Value))
+	}
+	if input.RuleType != nil || input.Value != nil {
+		if err := validateRuleCondition(ruleType, value); err != nil {
+			return nil, err
+		}
+		conditions, err := json.Marshal(models.LinkRuleCondition{Value: value})
+		if err != nil {
+			return nil, httputil.Wrap(err, "failed to encode rule conditions")
+		}
+		params.Conditions = conditions
+	}
+
+	if input.DestinationURL != nil {
+		destination, err := normalizeURL(*input.DestinationURL)
+		if err != nil {
+			return nil, httputil.Validation("destination_url", "invalid URL format")
+		}
+		params.DestinationUrl = pgtype.Text{String: destination, Valid: true}
+	}
+	if input.Priority != nil {
+		params.Priority = pgtype.Int4{Int32: *input.Priority, Valid: true}
+	}
+	if input.IsActive != nil {
+		params.IsActive = pgtype.Bool{Bool: *input.IsActive, Valid: true}
+	}
+
+	return s.ruleRepo.Update(ctx, params)
+}
+
+func (s *ruleService) DeleteRule(ctx context.Context, ruleID, linkID, workspaceID uuid.UUID) error {
+	if _, err := s.getOwnedRule(ctx, ruleID, linkID, workspaceID); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(ctx, ruleID)
+}
+
+// SimulateRedirect returns where the redirect service would send the
+// simulated visitor after evaluating the link's rules and forwarding the
+// query, without recording a click.
+func (s *ruleService) SimulateRedirect(ctx context.Context, linkID, workspaceID uuid.UUID, input models.SimulateRedirectInput) (*models.RedirectSimulation, error) {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("link does not belong to this workspace")
+	}
+	if err := requireNotReserved(link); err != nil {
+		return nil, err
+	}
+
+	userAgent := strings.TrimSpace(input.UserAgent)
+	if userAgent == "" {
+		var ok bool
+		userAgent, ok = redirect.SimulatedUserAgent(input.Device, input.OS, input.Browser)
+		if !ok {
+			return nil, httputil.Validation("device", "unsupported combination of device, os and browser")
+		}
+	}
+	query, err := url.ParseQuery(strings.TrimPrefix(input.Query, "?"))
+	if err != nil {
+		return nil, httputil.Validation("query", "invalid query string")
+	}
+
+	rules, err := s.ruleRepo.ListForLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	return redirect.Simulate(link, rules, userAgent, query, time.Now()), nil
+}
+
+func (s *ruleService) checkLinkOwnership(ctx context.Context, linkID, workspaceID uuid.UUID) error {
+	link, err := s.linkRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link.WorkspaceID != workspaceID {
+		return httputil.Forbidden("link does not belong to this workspace")
+	}
+	return nil
+}
+
+// getOwnedRule loads a rule after checking that it belongs to the link and
+// the link to the workspace. A rule on another link reports as not found.
+func (s *ruleService) getOwnedRule(ctx context.Context, ruleID, linkID, workspaceID uuid.UUID) (*models.LinkRule, error) {
+	if err := s.checkLinkOwnership(ctx, linkID, workspaceID); err != nil {
+		return nil, err
+	}
+	rule, err := s.ruleRepo.GetByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.LinkID != linkID {
+		return nil, httputil.NotFound("link rule")
+	}
+	return rule, nil
+}
+
+func validateRuleCondition(ruleType, value string) error {
+	if _, ok := models.LinkRuleConditions[ruleType]; !ok {
+		return httputil.Validation("rule_type", "rule type must be one of: device, browser, os")
+	}
+	if !models.IsValidLinkRuleCondition(ruleType, value) {
+		return httputil.Validation("value", "unsupported value for "+ruleType+" rule: "+strings.Join(models.LinkRuleConditions[ruleType], ", "))
+	}
+	return nil
+}
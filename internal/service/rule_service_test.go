@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// --- Mock LinkRuleRepository ---
+
+type mockLinkRuleRepo struct {
+	repository.LinkRuleRepository
+	rules   map[uuid.UUID]*models.LinkRule
+	deleted []uuid.UUID
+}
+
+func (m *mockLinkRuleRepo) GetByID(_ context.Context, id uuid.UUID) (*models.LinkRule, error) {
+	rule, ok := m.rules[id]
+	if !ok {
+		return nil, httputil.NotFound("link rule")
+	}
+	return rule, nil
+}
+
+func (m *mockLinkRuleRepo) ListForLink(_ context.Context, linkID uuid.UUID) ([]*models.LinkRule, error) {
+	var rules []*models.LinkRule
+	for _, rule := range m.rules {
+		if rule.LinkID == linkID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *mockLinkRuleRepo) Delete(_ context.Context, id uuid.UUID) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func newTestRuleService(linkWorkspace uuid.UUID, rules *mockLinkRuleRepo) *ruleService {
+	return &ruleService{
+		ruleRepo: rules,
+		linkRepo: &mockLinkRepo{
+			getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+				return &models.Link{ID: id, WorkspaceID: linkWorkspace}, nil
+			},
+		},
+		licManager: newTestLicenseManager(license.TierFree),
+		logger:     zap.NewNop(),
+	}
+}
+
+func TestValidateRuleCondition(t *testing.T) {
+	tests := []struct {
+		ruleType, value string
+		wantErr         bool
+	}{
+		{"device", "mobile", false},
+		{"os", "macos", false},
+		{"browser", "opera", true},
+		{"country", "us", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ruleType+"/"+tt.value, func(t *testing.T) {
+			err := validateRuleCondition(tt.ruleType, tt.value)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var appErr *httputil.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateRule_RequiresConditionalRouting(t *testing.T) {
+	svc := newTestRuleService(uuid.New(), &mockLinkRuleRepo{})
+
+	_, err := svc.CreateRule(context.Background(), uuid.New(), uuid.New(), models.CreateLinkRuleInput{
+		RuleType: "device", Value: "mobile", DestinationURL: "https://example.com",
+	})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Errorf("expected payment required, got %v", err)
+	}
+}
+
+func TestDeleteRule_Ownership(t *testing.T) {
+	wsID := uuid.New()
+	linkID := uuid.New()
+	ruleID := uuid.New()
+
+	t.Run("link in another workspace", func(t *testing.T) {
+		repo := &mockLinkRuleRepo{rules: map[uuid.UUID]*models.LinkRule{ruleID: {ID: ruleID, LinkID: linkID}}}
+		svc := newTestRuleService(uuid.New(), repo)
+
+		err := svc.DeleteRule(context.Background(), ruleID, linkID, wsID)
+		var appErr *httputil.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "FORBIDDEN" {
+			t.Errorf("expected forbidden, got %v", err)
+		}
+		if len(repo.deleted) != 0 {
+			t.Error("rule should not be deleted")
+		}
+	})
+
+	t.Run("rule on another link", func(t *testing.T) {
+		repo := &mockLinkRuleRepo{rules: map[uuid.UUID]*models.LinkRule{ruleID: {ID: ruleID, LinkID: uuid.New()}}}
+		svc := newTestRuleService(wsID, repo)
+
+		err := svc.DeleteRule(context.Background(), ruleID, linkID, wsID)
+		var appErr *httputil.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+			t.Errorf("expected not found, got %v", err)
+		}
+	})
+
+	t.Run("owned rule", func(t *testing.T) {
+		repo := &mockLinkRuleRepo{rules: map[uuid.UUID]*models.LinkRule{ruleID: {ID: ruleID, LinkID: linkID}}}
+		svc := newTestRuleService(wsID, repo)
+
+		if err := svc.DeleteRule(context.Background(), ruleID, linkID, wsID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repo.deleted) != 1 || repo.deleted[0] != ruleID {
+			t.Errorf("deleted = %v, want [%s]", repo.deleted, ruleID)
+		}
+	})
+}
+
+func TestSimulateRedirect(t *testing.T) {
+	wsID := uuid.New()
+	linkID := uuid.New()
+	ruleID := uuid.New()
+	svc := newTestRuleService(wsID, &mockLinkRuleRepo{rules: map[uuid.UUID]*models.LinkRule{
+		ruleID: {ID: ruleID, LinkID: linkID, RuleType: "os", Value: "ios", DestinationURL: "https://apps.example.com", IsActive: true},
+	}})
+	svc.linkRepo = &mockLinkRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+			return &models.Link{ID: id, WorkspaceID: wsID, URL: "https://example.com", IsActive: true, ForwardParams: true}, nil
+		},
+	}
+
+	sim, err := svc.SimulateRedirect(context.Background(), linkID, wsID, models.SimulateRedirectInput{OS: "ios", Query: "?utm_source=qa"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sim.MatchedRuleID == nil || *sim.MatchedRuleID != ruleID {
+		t.Errorf("matched rule = %v, want %s", sim.MatchedRuleID, ruleID)
+	}
+	if want := "https://apps.example.com?utm_source=qa"; sim.DestinationURL != want {
+		t.Errorf("destination = %q, want %q", sim.DestinationURL, want)
+	}
+
+	_, err = svc.SimulateRedirect(context.Background(), linkID, wsID, models.SimulateRedirectInput{Device: "desktop", OS: "android"})
+	if got := validationField(err); got != "device" {
+		t.Errorf("error = %v, want a validation error on device", err)
+	}
+
+	_, err = svc.SimulateRedirect(context.Background(), linkID, uuid.New(), models.SimulateRedirectInput{})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "FORBIDDEN" {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/email"
+	"go.uber.org/zap"
+)
+
+const (
+	dueScheduledReportBatch = 50
+	scheduledReportTopN     = 10
+	// scheduledReportRetryDelay is how soon a report that failed to send is
+	// tried again, unless its next scheduled run comes first.
+	scheduledReportRetryDelay = 15 * time.Minute
+)
+
+// ScheduledReportSender emails scheduled reports that are due: a summary of
+// the workspace's analytics over the report's period as an HTML body, with
+// the top links attached as CSV. The worker calls SendDue periodically.
+type ScheduledReportSender struct {
+	reportRepo   repository.ScheduledReportRepository
+	wsRepo       repository.WorkspaceRepository
+	analytics    AnalyticsService
+	sender       email.Sender
+	licManager   *license.Manager
+	dashboardURL string
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+// NewScheduledReportSender creates the sender. dashboardURL is the web
+// app's base URL, linked from each report.
+func NewScheduledReportSender(
+	reportRepo repository.ScheduledReportRepository,
+	wsRepo repository.WorkspaceRepository,
+	analytics AnalyticsService,
+	sender email.Sender,
+	licManager *license.Manager,
+	dashboardURL string,
+	logger *zap.Logger,
+) *ScheduledReportSender {
+	return &ScheduledReportSender{
+		reportRepo:   reportRepo,
+		wsRepo:       wsRepo,
+		analytics:    analytics,
+		sender:       sender,
+		licManager:   licManager,
+		dashboardURL: strings.TrimRight(dashboardURL, "/"),
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// SendDue sends every report whose next run has passed and schedules its
+// next run. Runs missed while the worker was down are sent once, not once
+// per missed run.
+func (s *ScheduledReportSender) SendDue(ctx context.Context) error {
+	now := s.now()
+	reports, err := s.reportRepo.ListDue(ctx, now, dueScheduledReportBatch)
+	if err != nil {
+		return err
+	}
+	for _, report := range reports {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.run(ctx, report, now)
+	}
+	return nil
+}
+
+func (s *ScheduledReportSender) run(ctx context.Context, report *models.ScheduledReport, now time.Time) {
+	schedule, err := parseReportSchedule(report.Schedule, report.Timezone)
+	if err != nil {
+		// Schedules are validated when saved, but a time zone can stop
+		// loading if the host's tzdata changes.
+		s.logger.Error("scheduled report has an invalid schedule",
+			zap.String("report_id", report.ID.String()),
+			zap.Error(err),
+		)
+		s.recordRun(ctx, report, now, now.Add(24*time.Hour), "invalid schedule: "+err.Error())
+		return
+	}
+
+	next := schedule.Next(now)
+	runErr := ""
+	if err := s.send(ctx, report); err != nil {
+		runErr = err.Error()
+		if retry := now.Add(scheduledReportRetryDelay); next.IsZero() || retry.Before(next) {
+			next = retry
+		}
+		s.logger.Warn("failed to send scheduled report",
+			zap.String("report_id", report.ID.String()),
+			zap.String("workspace_id", report.WorkspaceID.String()),
+			zap.Time("retry_at", next),
+			zap.Error(err),
+		)
+	}
+	s.recordRun(ctx, report, now, next, runErr)
+}
+
+func (s *ScheduledReportSender) recordRun(ctx context.Context, report *models.ScheduledReport, now, next time.Time, runErr string) {
+	if err := s.reportRepo.RecordRun(ctx, report.ID, now, next, runErr); err != nil {
+		s.logger.Error("failed to record scheduled report run",
+			zap.String("report_id", report.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *ScheduledReportSender) send(ctx context.Context, report *models.ScheduledReport) error {
+	ws, err := s.wsRepo.GetByID(ctx, report.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	// The period ends at the scheduled time, so a late run still covers
+	// the intended window.
+	dr := report.Scope.DateRange(report.NextRunAt)
+	data := scheduledReportData{
+		ReportName:    report.Name,
+		WorkspaceName: ws.Name,
+		Start:         dr.Start,
+		End:           dr.End,
+		DashboardURL:  s.dashboardURL + "/analytics",
+	}
+	if data.Stats, err = s.analytics.GetWorkspaceStats(ctx, report.WorkspaceID, dr); err != nil {
+		return fmt.Errorf("workspace stats: %w", err)
+	}
+	// Breakdowns need advanced analytics; without it the report has the
+	// totals and top links only.
+	if s.licManager.HasFeature(license.FeatureAdvancedAnalytics) {
+		if data.Referrers, err = s.analytics.GetWorkspaceReferrers(ctx, report.WorkspaceID, nil, dr, scheduledReportTopN); err != nil {
+			return fmt.Errorf("workspace referrers: %w", err)
+		}
+		if data.Countries, err = s.analytics.GetWorkspaceCountries(ctx, report.WorkspaceID, nil, dr, scheduledReportTopN); err != nil {
+			return fmt.Errorf("workspace countries: %w", err)
+		}
+	}
+
+	msg, err := renderScheduledReport(data)
+	if err != nil {
+		return err
+	}
+	msg.To = report.Recipients
+	return s.sender.Send(ctx, msg)
+}
+
+type scheduledReportData struct {
+	ReportName    string
+	WorkspaceName string
+	Start, End    time.Time
+	DashboardURL  string
+	Stats         *models.WorkspaceAnalytics
+	Referrers     []models.ReferrerStats
+	Countries     []models.CountryStats
+}
+
+// Period formats the report's date range, such as "12 Oct – 19 Oct 2026".
+func (d scheduledReportData) Period() string {
+	return d.Start.Format("2 Jan") + " – " + d.End.Format("2 Jan 2006")
+}
+
+var scheduledReportHTML = htmltemplate.Must(htmltemplate.New("report").Funcs(htmltemplate.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, Segoe UI, Helvetica, Arial, sans-serif; color: #111827; max-width: 640px;">
+<h2 style="margin-bottom: 4px;">{{.ReportName}}</h2>
+<p style="color: #6b7280; margin-top: 0;">{{.WorkspaceName}} · {{.Period}}</p>
+<table cellpadding="8" style="border-collapse: collapse; margin-bottom: 24px;">
+<tr><td>Clicks</td><td><strong>{{.Stats.TotalClicks}}</strong></td></tr>
+<tr><td>Unique visitors</td><td><strong>{{.Stats.UniqueClicks}}</strong></td></tr>
+<tr><td>Links clicked</td><td><strong>{{.Stats.TotalLinks}}</strong></td></tr>
+</table>
+<h3>Top links</h3>
+{{if .Stats.TopLinks}}<table cellpadding="6" style="border-collapse: collapse; width: 100%;">
+<tr style="text-align: left; border-bottom: 1px solid #e5e7eb;"><th>#</th><th>Short code</th><th style="text-align: right;">Clicks</th></tr>
+{{range $i, $l := .Stats.TopLinks}}<tr style="border-bottom: 1px solid #f3f4f6;"><td>{{inc $i}}</td><td>{{$l.ShortCode}}</td><td style="text-align: right;">{{$l.TotalClicks}}</td></tr>
+{{end}}</table>{{else}}<p>No clicks in this period.</p>{{end}}
+{{if .Referrers}}<h3>Top referrers</h3>
+<table cellpadding="6" style="border-collapse: collapse; width: 100%;">
+{{range .Referrers}}<tr style="border-bottom: 1px solid #f3f4f6;"><td>{{.Referrer}}</td><td style="text-align: right;">{{.Clicks}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Countries}}<h3>Top countries</h3>
+<table cellpadding="6" style="border-collapse: collapse; width: 100%;">
+{{range .Countries}}<tr style="border-bottom: 1px solid #f3f4f6;"><td>{{.Country}}</td><td style="text-align: right;">{{.Clicks}}</td></tr>
+{{end}}</table>{{end}}
+<p style="margin-top: 24px;"><a href="{{.DashboardURL}}">View full analytics</a></p>
+<p style="color: #9ca3af; font-size: 12px;">The top links are attached as CSV. Workspace admins can change or stop this report.</p>
+</body>
+</html>
+`))
+
+var scheduledReportText = texttemplate.Must(texttemplate.New("report").Funcs(texttemplate.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`{{.ReportName}}
+{{.WorkspaceName}} · {{.Period}}
+
+Clicks: {{.Stats.TotalClicks}}
+Unique visitors: {{.Stats.UniqueClicks}}
+Links clicked: {{.Stats.TotalLinks}}
+
+Top links:
+{{range $i, $l := .Stats.TopLinks}}{{inc $i}}. {{$l.ShortCode}} — {{$l.TotalClicks}}
+{{else}}No clicks in this period.
+{{end}}
+View full analytics: {{.DashboardURL}}
+`))
+
+// renderScheduledReport renders a report's email, without recipients.
+func renderScheduledReport(data scheduledReportData) (*email.Message, error) {
+	var html, text bytes.Buffer
+	if err := scheduledReportHTML.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("render report html: %w", err)
+	}
+	if err := scheduledReportText.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("render report text: %w", err)
+	}
+
+	var attachment bytes.Buffer
+	w := csv.NewWriter(&attachment)
+	_ = w.Write([]string{"rank", "short_code", "link_id", "clicks"})
+	for i, l := range data.Stats.TopLinks {
+		_ = w.Write([]string{strconv.Itoa(i + 1), l.ShortCode, l.LinkID.String(), strconv.FormatInt(l.TotalClicks, 10)})
+	}
+	w.Flush()
+
+	return &email.Message{
+		Subject: fmt.Sprintf("%s — %s (%s)", data.ReportName, data.WorkspaceName, data.Period()),
+		HTML:    html.String(),
+		Text:    text.String(),
+		Attachments: []email.Attachment{{
+			Filename:    "top-links-" + data.End.Format("2006-01-02") + ".csv",
+			ContentType: "text/csv",
+			Data:        attachment.Bytes(),
+		}},
+	}, nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	maxScheduledReportsPerWorkspace = 25
+	maxScheduledReportRecipients    = 20
+	// minScheduledReportInterval is the shortest gap allowed between two
+	// runs of a report, so a schedule like "* * * * *" can't flood inboxes.
+	minScheduledReportInterval = time.Hour
+)
+
+// reportScheduleParser accepts standard five-field cron expressions and
+// descriptors such as @weekly.
+var reportScheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// reportSchedule is a parsed report schedule, evaluated in a time zone.
+type reportSchedule struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// parseReportSchedule parses a cron expression and an IANA time zone
+// (UTC when empty), returning validation errors for either.
+func parseReportSchedule(spec, timezone string) (*reportSchedule, error) {
+	if strings.Contains(spec, "TZ=") {
+		return nil, httputil.Validation("schedule", "set the time zone with the timezone field")
+	}
+	schedule, err := reportScheduleParser.Parse(spec)
+	if err != nil {
+		return nil, httputil.Validation("schedule", "must be a cron expression such as \"0 9 * * 1\": "+err.Error())
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, httputil.Validation("timezone", "must be an IANA time zone such as Europe/Berlin")
+	}
+	return &reportSchedule{schedule: schedule, location: location}, nil
+}
+
+// Next returns the first run after t, in UTC, or the zero time if the
+// schedule never runs.
+func (s *reportSchedule) Next(t time.Time) time.Time {
+	next := s.schedule.Next(t.In(s.location))
+	if next.IsZero() {
+		return next
+	}
+	return next.UTC()
+}
+
+// validate checks that the schedule runs at all and never more often than
+// minScheduledReportInterval, over its next several runs.
+func (s *reportSchedule) validate(now time.Time) error {
+	prev := s.Next(now)
+	if prev.IsZero() {
+		return httputil.Validation("schedule", "never runs")
+	}
+	for i := 0; i < 50; i++ {
+		next := s.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if next.Sub(prev) < minScheduledReportInterval {
+			return httputil.Validation("schedule", "must not run more than once an hour")
+		}
+		prev = next
+	}
+	return nil
+}
+
+// ScheduledReportService manages a workspace's scheduled analytics reports.
+// The worker sends them; see ScheduledReportSender.
+type ScheduledReportService interface {
+	CreateReport(ctx context.Context, workspaceID, userID uuid.UUID, input models.CreateScheduledReportInput) (*models.ScheduledReport, error)
+	ListReports(ctx context.Context, workspaceID uuid.UUID) ([]*models.ScheduledReport, error)
+	GetReport(ctx context.Context, id, workspaceID uuid.UUID) (*models.ScheduledReport, error)
+	UpdateReport(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateScheduledReportInput) (*models.ScheduledReport, error)
+	DeleteReport(ctx context.Context, id, workspaceID uuid.UUID) error
+}
+
+type scheduledReportService struct {
+	reportRepo repository.ScheduledReportRepository
+	licManager *license.Manager
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+func NewScheduledReportService(reportRepo repository.ScheduledReportRepository, licManager *license.Manager, logger *zap.Logger) ScheduledReportService {
+	return &scheduledReportService{
+		reportRepo: reportRepo,
+		licManager: licManager,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (s *scheduledReportService) CreateReport(ctx context.Context, workspaceID, userID uuid.UUID, input models.CreateScheduledReportInput) (*models.ScheduledReport, error) {
+	if !s.licManager.HasFeature(license.FeatureExportData) {
+		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureExportData), "pro")
+	}
+
+	count, err := s.reportRepo.Count(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxScheduledReportsPerWorkspace {
+		return nil, httputil.Validation("reports", fmt.Sprintf("a workspace can have at most %d scheduled reports", maxScheduledReportsPerWorkspace))
+	}
+
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	schedule, err := s.checkSchedule(input.Schedule, timezone)
+	if err != nil {
+		return nil, err
+	}
+	recipients, err := normalizeRecipients(input.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := scopeJSON(input.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.Create(ctx, sqlc.CreateScheduledReportParams{
+		WorkspaceID: workspaceID,
+		CreatedBy:   pgtype.UUID{Bytes: userID, Valid: true},
+		Name:        strings.TrimSpace(input.Name),
+		Schedule:    strings.TrimSpace(input.Schedule),
+		Timezone:    timezone,
+		Recipients:  recipients,
+		Scope:       scope,
+		IsActive:    true,
+		NextRunAt:   pgtype.Timestamptz{Time: schedule.Next(s.now()), Valid: true},
+	})
+}
+
+func (s *scheduledReportService) ListReports(ctx context.Context, workspaceID uuid.UUID) ([]*models.ScheduledReport, error) {
+	return s.reportRepo.List(ctx, workspaceID)
+}
+
+func (s *scheduledReportService) GetReport(ctx context.Context, id, workspaceID uuid.UUID) (*models.ScheduledReport, error) {
+	report, err := s.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if report.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("scheduled report")
+	}
+	return report, nil
+}
+
+func (s *scheduledReportService) UpdateReport(ctx context.Context, id, workspaceID uuid.UUID, input models.UpdateScheduledReportInput) (*models.ScheduledReport, error) {
+	report, err := s.GetReport(ctx, id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateScheduledReportParams{
+		ID:         id,
+		Name:       report.Name,
+		Schedule:   report.Schedule,
+		Timezone:   report.Timezone,
+		Recipients: report.Recipients,
+		IsActive:   report.IsActive,
+		NextRunAt:  pgtype.Timestamptz{Time: report.NextRunAt, Valid: true},
+	}
+	if input.Name != nil {
+		params.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Schedule != nil {
+		params.Schedule = strings.TrimSpace(*input.Schedule)
+	}
+	if input.Timezone != nil {
+		params.Timezone = *input.Timezone
+		if params.Timezone == "" {
+			params.Timezone = "UTC"
+		}
+	}
+	if input.Recipients != nil {
+		if params.Recipients, err = normalizeRecipients(input.Recipients); err != nil {
+			return nil, err
+		}
+	}
+	scope := &report.Scope
+	if input.Scope != nil {
+		scope = input.Scope
+	}
+	if params.Scope, err = scopeJSON(scope); err != nil {
+		return nil, err
+	}
+
+	// A new schedule, or resuming a paused report, restarts from now rather
+	// than sending runs missed while paused.
+	reschedule := input.Schedule != nil || input.Timezone != nil
+	if input.IsActive != nil {
+		reschedule = reschedule || (*input.IsActive && !report.IsActive)
+		params.IsActive = *input.IsActive
+	}
+	if reschedule {
+		schedule, err := s.checkSchedule(params.Schedule, params.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		params.NextRunAt = pgtype.Timestamptz{Time: schedule.Next(s.now()), Valid: true}
+	}
+
+	return s.reportRepo.Update(ctx, params)
+}
+
+func (s *scheduledReportService) DeleteReport(ctx context.Context, id, workspaceID uuid.UUID) error {
+	if _, err := s.GetReport(ctx, id, workspaceID); err != nil {
+		return err
+	}
+	return s.reportRepo.Delete(ctx, id)
+}
+
+func (s *scheduledReportService) checkSchedule(spec, timezone string) (*reportSchedule, error) {
+	schedule, err := parseReportSchedule(spec, timezone)
+	if err != nil {
+		return nil, err
+	}
+	if err := schedule.validate(s.now()); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// normalizeRecipients lowercases and de-duplicates recipient addresses.
+func normalizeRecipients(recipients []string) ([]string, error) {
+	seen := make(map[string]bool, len(recipients))
+	result := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if r == "" || seen[r] {
+			continue
+		}
+		seen[r] = true
+		result = append(result, r)
+	}
+	if len(result) == 0 {
+		return nil, httputil.Validation("recipients", "at least one recipient is required")
+	}
+	if len(result) > maxScheduledReportRecipients {
+		return nil, httputil.Validation("recipients", fmt.Sprintf("at most %d recipients are allowed", maxScheduledReportRecipients))
+	}
+	return result, nil
+}
+
+// scopeJSON validates a report scope, defaulting its period, and encodes it
+// for storage.
+func scopeJSON(scope *models.ScheduledReportScope) ([]byte, error) {
+	s := models.ScheduledReportScope{Period: models.DefaultScheduledReportPeriod}
+	if scope != nil && scope.Period != "" {
+		if !models.IsValidScheduledReportPeriod(scope.Period) {
+			return nil, httputil.Validation("scope.period", "must be one of 24h, 7d, 30d or 90d")
+		}
+		s.Period = scope.Period
+	}
+	return json.Marshal(s)
+}
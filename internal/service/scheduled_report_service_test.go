@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/email"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type reportRun struct {
+	ranAt, next time.Time
+	err         string
+}
+
+type mockScheduledReportRepo struct {
+	repository.ScheduledReportRepository
+	due  []*models.ScheduledReport
+	runs map[uuid.UUID]reportRun
+}
+
+func (m *mockScheduledReportRepo) ListDue(_ context.Context, _ time.Time, _ int32) ([]*models.ScheduledReport, error) {
+	return m.due, nil
+}
+
+func (m *mockScheduledReportRepo) RecordRun(_ context.Context, id uuid.UUID, ranAt, next time.Time, runErr string) error {
+	if m.runs == nil {
+		m.runs = map[uuid.UUID]reportRun{}
+	}
+	m.runs[id] = reportRun{ranAt, next, runErr}
+	return nil
+}
+
+type fakeReportAnalytics struct {
+	AnalyticsService
+	stats  *models.WorkspaceAnalytics
+	ranges []models.DateRange
+}
+
+func (f *fakeReportAnalytics) GetWorkspaceStats(_ context.Context, _ uuid.UUID, dr models.DateRange) (*models.WorkspaceAnalytics, error) {
+	f.ranges = append(f.ranges, dr)
+	return f.stats, nil
+}
+
+type fakeEmailSender struct {
+	sent []*email.Message
+	err  error
+}
+
+func (f *fakeEmailSender) Send(_ context.Context, msg *email.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestParseReportSchedule(t *testing.T) {
+	// 08:00 UTC on Friday 16 October 2026 is 10:00 in Berlin.
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	schedule, err := parseReportSchedule("0 9 * * 1", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC) // Monday 09:00 CEST
+	if next := schedule.Next(now); !next.Equal(want) {
+		t.Errorf("Next() = %v, want %v", next, want)
+	}
+	if err := schedule.validate(now); err != nil {
+		t.Errorf("weekly schedule should be valid: %v", err)
+	}
+
+	invalid := []struct{ spec, timezone string }{
+		{"every monday", ""},
+		{"0 9 * * 1", "Mars/Olympus"},
+		{"CRON_TZ=Europe/Berlin 0 9 * * 1", ""},
+	}
+	for _, tc := range invalid {
+		if _, err := parseReportSchedule(tc.spec, tc.timezone); !errors.Is(err, httputil.ErrValidation) {
+			t.Errorf("parseReportSchedule(%q, %q): expected validation error, got %v", tc.spec, tc.timezone, err)
+		}
+	}
+
+	for _, spec := range []string{"*/5 * * * *", "0,30 9 * * 1", "0 0 30 2 *"} {
+		schedule, err := parseReportSchedule(spec, "")
+		if err != nil {
+			t.Fatalf("parseReportSchedule(%q): %v", spec, err)
+		}
+		if err := schedule.validate(now); !errors.Is(err, httputil.ErrValidation) {
+			t.Errorf("validate(%q): expected validation error, got %v", spec, err)
+		}
+	}
+}
+
+func TestNormalizeRecipients(t *testing.T) {
+	got, err := normalizeRecipients([]string{"Ana@Example.com", "ana@example.com ", "bo@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(got, ",") != "ana@example.com,bo@example.com" {
+		t.Errorf("normalizeRecipients() = %v", got)
+	}
+
+	many := make([]string, maxScheduledReportRecipients+1)
+	for i := range many {
+		many[i] = uuid.NewString() + "@example.com"
+	}
+	if _, err := normalizeRecipients(many); !errors.Is(err, httputil.ErrValidation) {
+		t.Errorf("expected validation error for too many recipients, got %v", err)
+	}
+}
+
+func TestScheduledReportService_CreateRequiresExportFeature(t *testing.T) {
+	svc := NewScheduledReportService(&mockScheduledReportRepo{}, newTestLicenseManager(license.TierFree), zap.NewNop())
+	_, err := svc.CreateReport(context.Background(), uuid.New(), uuid.New(), models.CreateScheduledReportInput{
+		Name:       "Weekly",
+		Schedule:   "0 9 * * 1",
+		Recipients: []string{"ana@example.com"},
+	})
+	if !errors.Is(err, httputil.ErrPaymentRequired) {
+		t.Errorf("expected payment required, got %v", err)
+	}
+}
+
+func TestScheduledReportSender_SendDue(t *testing.T) {
+	wsID := uuid.New()
+	scheduledAt := time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)
+	report := &models.ScheduledReport{
+		ID:          uuid.New(),
+		WorkspaceID: wsID,
+		Name:        "Weekly top links",
+		Schedule:    "0 9 * * 1",
+		Timezone:    "Europe/Berlin",
+		Recipients:  []string{"ana@example.com"},
+		Scope:       models.ScheduledReportScope{Period: "7d"},
+		NextRunAt:   scheduledAt,
+	}
+	repo := &mockScheduledReportRepo{due: []*models.ScheduledReport{report}}
+	analytics := &fakeReportAnalytics{stats: &models.WorkspaceAnalytics{
+		TotalClicks: 120,
+		TopLinks:    []models.TopLink{{LinkID: uuid.New(), ShortCode: "launch", TotalClicks: 90}},
+	}}
+	sender := &fakeEmailSender{}
+	s := NewScheduledReportSender(repo,
+		&mockWorkspaceRepo{workspaces: map[uuid.UUID]*models.Workspace{wsID: {ID: wsID, Name: "Marketing"}}},
+		analytics, sender, newTestLicenseManager(license.TierFree), "https://app.example.com/", zap.NewNop())
+	now := scheduledAt.Add(2 * time.Minute)
+	s.now = func() time.Time { return now }
+
+	if err := s.SendDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sender.sent))
+	}
+	msg := sender.sent[0]
+	if len(msg.To) != 1 || msg.To[0] != "ana@example.com" {
+		t.Errorf("To = %v", msg.To)
+	}
+	if !strings.Contains(msg.Subject, "Marketing") || !strings.Contains(msg.HTML, "launch") {
+		t.Errorf("email is missing the workspace or top link: %q", msg.Subject)
+	}
+	if len(msg.Attachments) != 1 || !strings.Contains(string(msg.Attachments[0].Data), "1,launch,") {
+		t.Errorf("expected the top links as CSV, got %+v", msg.Attachments)
+	}
+	if dr := analytics.ranges[0]; !dr.End.Equal(scheduledAt) || !dr.Start.Equal(scheduledAt.AddDate(0, 0, -7)) {
+		t.Errorf("report covered %v to %v, want the 7 days before the scheduled run", dr.Start, dr.End)
+	}
+
+	// Berlin leaves summer time on 25 October, so 09:00 is an hour later
+	// in UTC the next Monday.
+	run := repo.runs[report.ID]
+	if want := time.Date(2026, 10, 26, 8, 0, 0, 0, time.UTC); run.err != "" || !run.next.Equal(want) {
+		t.Errorf("run = %+v, want the next run at %v with no error", run, want)
+	}
+}
+
+func TestScheduledReportSender_RetriesFailedSends(t *testing.T) {
+	wsID := uuid.New()
+	report := &models.ScheduledReport{
+		ID:          uuid.New(),
+		WorkspaceID: wsID,
+		Name:        "Weekly",
+		Schedule:    "0 9 * * 1",
+		Timezone:    "UTC",
+		Recipients:  []string{"ana@example.com"},
+		NextRunAt:   time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+	}
+	repo := &mockScheduledReportRepo{due: []*models.ScheduledReport{report}}
+	s := NewScheduledReportSender(repo,
+		&mockWorkspaceRepo{workspaces: map[uuid.UUID]*models.Workspace{wsID: {ID: wsID}}},
+		&fakeReportAnalytics{stats: &models.WorkspaceAnalytics{}},
+		&fakeEmailSender{err: errors.New("connection refused")},
+		newTestLicenseManager(license.TierFree), "", zap.NewNop())
+	now := report.NextRunAt
+	s.now = func() time.Time { return now }
+
+	if err := s.SendDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	run := repo.runs[report.ID]
+	if run.err == "" || !run.next.Equal(now.Add(scheduledReportRetryDelay)) {
+		t.Errorf("run = %+v, want a retry after %v with the error recorded", run, scheduledReportRetryDelay)
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	sessionCachePrefix = "session:active:"
+	sessionCacheTTL    = 30 * time.Second
+)
+
+// SessionValidator reports whether the session behind an access token is
+// still active, so revoked sessions are rejected before their tokens expire.
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+}
+
+type sessionValidator struct {
+	sessionRepo repository.SessionRepository
+	redis       *redis.Client
+	logger      *zap.Logger
+}
+
+// NewSessionValidator creates a SessionValidator that caches lookups in Redis
+// for a short time to avoid a database query on every request.
+func NewSessionValidator(sessionRepo repository.SessionRepository, redisClient *redis.Client, logger *zap.Logger) SessionValidator {
+	return &sessionValidator{
+		sessionRepo: sessionRepo,
+		redis:       redisClient,
+		logger:      logger,
+	}
+}
+
+func (v *sessionValidator) IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	key := sessionCachePrefix + sessionID.String()
+
+	if v.redis != nil {
+		cached, err := v.redis.Get(ctx, key).Result()
+		if err == nil {
+			return cached == "1", nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			v.logger.Warn("session cache lookup failed", zap.Error(err))
+		}
+	}
+
+	active := true
+	if _, err := v.sessionRepo.GetActiveByID(ctx, sessionID); err != nil {
+		if !errors.Is(err, httputil.ErrNotFound) {
+			return false, err
+		}
+		active = false
+	}
+
+	if v.redis != nil {
+		value := "0"
+		if active {
+			value = "1"
+		}
+		if err := v.redis.Set(ctx, key, value, sessionCacheTTL).Err(); err != nil {
+			v.logger.Warn("failed to cache session status", zap.Error(err))
+		}
+	}
+
+	return active, nil
+}
+
+// invalidateSessionCache marks sessions as revoked in the validator cache so
+// their access tokens are rejected immediately rather than after the cached
+// status expires.
+func invalidateSessionCache(ctx context.Context, redisClient *redis.Client, sessionIDs ...uuid.UUID) error {
+	if redisClient == nil || len(sessionIDs) == 0 {
+		return nil
+	}
+	pipe := redisClient.Pipeline()
+	for _, id := range sessionIDs {
+		pipe.Set(ctx, sessionCachePrefix+id.String(), "0", sessionCacheTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+// --- Mock SessionRepository ---
+
+type mockSessionRepo struct {
+	repository.SessionRepository
+	active map[uuid.UUID]bool
+	err    error
+}
+
+func (m *mockSessionRepo) GetActiveByID(_ context.Context, id uuid.UUID) (*models.Session, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if !m.active[id] {
+		return nil, httputil.NotFound("session")
+	}
+	return &models.Session{ID: id}, nil
+}
+
+func TestSessionValidator_IsSessionActive(t *testing.T) {
+	activeID := uuid.New()
+	revokedID := uuid.New()
+	v := NewSessionValidator(&mockSessionRepo{active: map[uuid.UUID]bool{activeID: true}}, nil, zap.NewNop())
+
+	active, err := v.IsSessionActive(context.Background(), activeID)
+	if err != nil || !active {
+		t.Errorf("active session: got (%v, %v), want (true, nil)", active, err)
+	}
+
+	active, err = v.IsSessionActive(context.Background(), revokedID)
+	if err != nil || active {
+		t.Errorf("revoked session: got (%v, %v), want (false, nil)", active, err)
+	}
+}
+
+func TestSessionValidator_RepoErrorPropagates(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	v := NewSessionValidator(&mockSessionRepo{err: repoErr}, nil, zap.NewNop())
+
+	if _, err := v.IsSessionActive(context.Background(), uuid.New()); !errors.Is(err, repoErr) {
+		t.Errorf("expected repo error, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUsageSnapshotLimit = 12
+	maxUsageSnapshotLimit     = 36
+)
+
+// UsageService meters per-workspace monthly usage for billing integrations.
+// It doesn't bill; it reports what a billing system would charge for,
+// alongside the limits of the current tier.
+type UsageService interface {
+	// GetUsage counts a workspace's usage for the month containing month,
+	// live from the database.
+	GetUsage(ctx context.Context, workspaceID uuid.UUID, month time.Time) (*models.WorkspaceUsage, error)
+	// ListSnapshots returns a workspace's recorded monthly usage, newest
+	// first.
+	ListSnapshots(ctx context.Context, workspaceID uuid.UUID, limit int) ([]*models.UsageSnapshot, error)
+	// SnapshotAll records the current month's usage of every workspace. On
+	// the first day of a month the previous month is recorded again, so its
+	// snapshot includes usage up to its very end.
+	SnapshotAll(ctx context.Context) error
+}
+
+type usageService struct {
+	usageRepo  repository.UsageRepository
+	licManager *license.Manager
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+func NewUsageService(usageRepo repository.UsageRepository, licManager *license.Manager, logger *zap.Logger) UsageService {
+	return &usageService{
+		usageRepo:  usageRepo,
+		licManager: licManager,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// UsagePeriod returns the UTC calendar month containing t, as [start, end).
+func UsagePeriod(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+func (s *usageService) GetUsage(ctx context.Context, workspaceID uuid.UUID, month time.Time) (*models.WorkspaceUsage, error) {
+	start, end := UsagePeriod(month)
+	if start.After(s.now()) {
+		return nil, httputil.Validation("month", "must not be in the future")
+	}
+
+	counts, err := s.usageRepo.CountForPeriod(ctx, workspaceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := s.licManager.GetLimits()
+	return &models.WorkspaceUsage{
+		WorkspaceID: workspaceID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Tier:        string(s.licManager.GetTier()),
+		Usage:       *counts,
+		Limits: models.UsageLimits{
+			LinksCreated:  limits.GetLimit(license.LimitMaxLinksPerMonth),
+			ClicksTracked: limits.GetLimit(license.LimitMaxClicksPerMonth),
+		},
+	}, nil
+}
+
+func (s *usageService) ListSnapshots(ctx context.Context, workspaceID uuid.UUID, limit int) ([]*models.UsageSnapshot, error) {
+	if limit <= 0 {
+		limit = defaultUsageSnapshotLimit
+	}
+	if limit > maxUsageSnapshotLimit {
+		limit = maxUsageSnapshotLimit
+	}
+	return s.usageRepo.ListSnapshots(ctx, workspaceID, int32(limit))
+}
+
+func (s *usageService) SnapshotAll(ctx context.Context) error {
+	now := s.now()
+	start, end := UsagePeriod(now)
+	periods := [][2]time.Time{{start, end}}
+	if now.Sub(start) < 24*time.Hour {
+		prevStart, _ := UsagePeriod(start.AddDate(0, 0, -1))
+		periods = append(periods, [2]time.Time{prevStart, start})
+	}
+
+	ids, err := s.usageRepo.ListWorkspaceIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	tier := string(s.licManager.GetTier())
+	failed := 0
+	for _, id := range ids {
+		for _, period := range periods {
+			if err := s.snapshot(ctx, id, period[0], period[1], tier); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				failed++
+				s.logger.Warn("failed to snapshot workspace usage",
+					zap.String("workspace_id", id.String()),
+					zap.Time("period_start", period[0]),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	s.logger.Info("usage snapshot completed",
+		zap.Int("workspaces", len(ids)),
+		zap.Int("failed", failed),
+	)
+	return nil
+}
+
+func (s *usageService) snapshot(ctx context.Context, workspaceID uuid.UUID, start, end time.Time, tier string) error {
+	counts, err := s.usageRepo.CountForPeriod(ctx, workspaceID, start, end)
+	if err != nil {
+		return err
+	}
+	return s.usageRepo.UpsertSnapshot(ctx, workspaceID, start, tier, *counts)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type usagePeriodCall struct {
+	workspaceID uuid.UUID
+	start, end  time.Time
+}
+
+type mockUsageRepo struct {
+	ids       []uuid.UUID
+	counts    models.UsageCounts
+	countErr  error
+	counted   []usagePeriodCall
+	snapshots []usagePeriodCall
+	tiers     []string
+}
+
+func (m *mockUsageRepo) CountForPeriod(_ context.Context, workspaceID uuid.UUID, start, end time.Time) (*models.UsageCounts, error) {
+	m.counted = append(m.counted, usagePeriodCall{workspaceID, start, end})
+	if m.countErr != nil {
+		return nil, m.countErr
+	}
+	counts := m.counts
+	return &counts, nil
+}
+
+func (m *mockUsageRepo) ListWorkspaceIDs(_ context.Context) ([]uuid.UUID, error) {
+	return m.ids, nil
+}
+
+func (m *mockUsageRepo) UpsertSnapshot(_ context.Context, workspaceID uuid.UUID, periodStart time.Time, tier string, _ models.UsageCounts) error {
+	m.snapshots = append(m.snapshots, usagePeriodCall{workspaceID: workspaceID, start: periodStart})
+	m.tiers = append(m.tiers, tier)
+	return nil
+}
+
+func (m *mockUsageRepo) ListSnapshots(_ context.Context, _ uuid.UUID, _ int32) ([]*models.UsageSnapshot, error) {
+	return nil, nil
+}
+
+func newTestUsageService(repo *mockUsageRepo, now time.Time) *usageService {
+	svc := NewUsageService(repo, newTestLicenseManager(license.TierFree), zap.NewNop()).(*usageService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestUsagePeriod(t *testing.T) {
+	// 01:30 on 1 March in UTC+2 is still February in UTC.
+	at := time.Date(2026, 3, 1, 1, 30, 0, 0, time.FixedZone("EET", 2*60*60))
+	start, end := UsagePeriod(at)
+	if !start.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("UsagePeriod() = %v, %v, want February 2026 in UTC", start, end)
+	}
+}
+
+func TestUsageService_GetUsage(t *testing.T) {
+	wsID := uuid.New()
+	repo := &mockUsageRepo{counts: models.UsageCounts{LinksCreated: 7, ClicksTracked: 120, QRCodesGenerated: 2, WebhookDeliveries: 30}}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	svc := newTestUsageService(repo, now)
+
+	usage, err := svc.GetUsage(context.Background(), wsID, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage.Usage != repo.counts {
+		t.Errorf("usage = %+v, want %+v", usage.Usage, repo.counts)
+	}
+	if usage.Tier != string(license.TierFree) {
+		t.Errorf("tier = %q, want free", usage.Tier)
+	}
+	limits := license.DefaultLimits(license.TierFree)
+	if usage.Limits.LinksCreated != limits.MaxLinksPerMonth || usage.Limits.ClicksTracked != limits.MaxClicksPerMonth {
+		t.Errorf("limits = %+v, want the free monthly limits", usage.Limits)
+	}
+	if !usage.PeriodStart.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("period start = %v, want 1 October", usage.PeriodStart)
+	}
+
+	if _, err := svc.GetUsage(context.Background(), wsID, now.AddDate(0, 1, 0)); !errors.Is(err, httputil.ErrValidation) {
+		t.Errorf("future month: expected validation error, got %v", err)
+	}
+}
+
+func TestUsageService_SnapshotAll(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	repo := &mockUsageRepo{ids: ids}
+	svc := newTestUsageService(repo, time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+
+	if err := svc.SnapshotAll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.snapshots) != len(ids) {
+		t.Fatalf("got %d snapshots, want one per workspace", len(repo.snapshots))
+	}
+	for i, s := range repo.snapshots {
+		if s.workspaceID != ids[i] || !s.start.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("snapshot %d = %+v, want October for %s", i, s, ids[i])
+		}
+		if repo.tiers[i] != string(license.TierFree) {
+			t.Errorf("snapshot %d tier = %q, want free", i, repo.tiers[i])
+		}
+	}
+}
+
+func TestUsageService_SnapshotAllClosesPreviousMonth(t *testing.T) {
+	repo := &mockUsageRepo{ids: []uuid.UUID{uuid.New()}}
+	svc := newTestUsageService(repo, time.Date(2026, 11, 1, 3, 0, 0, 0, time.UTC))
+
+	if err := svc.SnapshotAll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.counted) != 2 {
+		t.Fatalf("got %d periods counted, want November and October", len(repo.counted))
+	}
+	prev := repo.counted[1]
+	if !prev.start.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || !prev.end.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("previous period = %v to %v, want all of October", prev.start, prev.end)
+	}
+}
+
+func TestUsageService_SnapshotAllContinuesPastFailures(t *testing.T) {
+	repo := &mockUsageRepo{ids: []uuid.UUID{uuid.New(), uuid.New()}, countErr: errors.New("timeout")}
+	svc := newTestUsageService(repo, time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+
+	if err := svc.SnapshotAll(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.counted) != 2 {
+		t.Errorf("expected every workspace to be tried, got %d", len(repo.counted))
+	}
+}
@@ -4,41 +4,65 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/link-rift/link-rift/internal/license"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
 	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// webhookDLQPrefix prefixes each workspace's dead-letter queue, a Redis list
+// of permanently failed deliveries (newest first) filled by the worker.
+const webhookDLQPrefix = "webhook:delivery:dlq:"
+
 type WebhookService interface {
 	CreateWebhook(ctx context.Context, workspaceID uuid.UUID, input models.CreateWebhookInput) (*models.CreateWebhookResponse, error)
 	ListWebhooks(ctx context.Context, workspaceID uuid.UUID) ([]*models.Webhook, error)
 	GetWebhook(ctx context.Context, id, workspaceID uuid.UUID) (*models.Webhook, error)
 	DeleteWebhook(ctx context.Context, id, workspaceID uuid.UUID) error
+	// RotateSecret gives the webhook a new signing secret, returned only
+	// here. Deliveries are signed with both secrets until the rotation
+	// window ends so receivers can switch over without dropping any.
+	RotateSecret(ctx context.Context, webhookID, workspaceID uuid.UUID) (*models.RotateWebhookSecretResponse, error)
 	ListDeliveries(ctx context.Context, webhookID, workspaceID uuid.UUID, limit, offset int32) ([]*models.WebhookDelivery, int64, error)
+	ListDeadLetters(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*models.WebhookDeadLetter, int64, error)
+	ReprocessDeadLetters(ctx context.Context, workspaceID uuid.UUID) (*models.ReprocessDeadLettersResult, error)
 }
 
 type webhookService struct {
-	webhookRepo repository.WebhookRepository
-	licManager  *license.Manager
-	logger      *zap.Logger
+	webhookRepo    repository.WebhookRepository
+	redis          *redis.Client
+	licManager     *license.Manager
+	hostPolicy     *httputil.HostPolicy
+	rotationWindow time.Duration
+	logger         *zap.Logger
 }
 
 func NewWebhookService(
 	webhookRepo repository.WebhookRepository,
+	redisClient *redis.Client,
 	licManager *license.Manager,
+	hostPolicy *httputil.HostPolicy,
+	rotationWindow time.Duration,
 	logger *zap.Logger,
 ) WebhookService {
 	return &webhookService{
-		webhookRepo: webhookRepo,
-		licManager:  licManager,
-		logger:      logger,
+		webhookRepo:    webhookRepo,
+		redis:          redisClient,
+		licManager:     licManager,
+		hostPolicy:     hostPolicy,
+		rotationWindow: rotationWindow,
+		logger:         logger,
 	}
 }
 
@@ -47,9 +71,8 @@ func (s *webhookService) CreateWebhook(ctx context.Context, workspaceID uuid.UUI
 		return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureWebhooks), "business")
 	}
 
-	// Validate URL is HTTPS
-	if !strings.HasPrefix(input.URL, "https://") {
-		return nil, httputil.Validation("url", "webhook URL must use HTTPS")
+	if err := checkWebhookURL(ctx, s.hostPolicy, input.URL); err != nil {
+		return nil, err
 	}
 
 	// Validate events
@@ -59,19 +82,26 @@ func (s *webhookService) CreateWebhook(ctx context.Context, workspaceID uuid.UUI
 		}
 	}
 
-	// Generate secret: whsec_ + 32 random hex bytes
-	rawBytes := make([]byte, 32)
-	if _, err := rand.Read(rawBytes); err != nil {
-		return nil, httputil.Wrap(err, "failed to generate webhook secret")
+	var payloadVersion pgtype.Int4
+	if input.PayloadVersion != nil {
+		if !models.IsValidWebhookPayloadVersion(*input.PayloadVersion) {
+			return nil, httputil.Validation("payload_version", fmt.Sprintf("payload version must be between %d and %d", models.MinWebhookPayloadVersion, models.CurrentWebhookPayloadVersion))
+		}
+		payloadVersion = pgtype.Int4{Int32: *input.PayloadVersion, Valid: true}
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
 	}
-	secret := "whsec_" + hex.EncodeToString(rawBytes)
 
 	params := sqlc.CreateWebhookParams{
-		WorkspaceID: workspaceID,
-		Url:         input.URL,
-		Secret:      secret,
-		Events:      input.Events,
-		IsActive:    true,
+		WorkspaceID:    workspaceID,
+		Url:            input.URL,
+		Secret:         secret,
+		Events:         input.Events,
+		IsActive:       true,
+		PayloadVersion: payloadVersion,
 	}
 
 	webhook, err := s.webhookRepo.Create(ctx, params)
@@ -111,6 +141,38 @@ func (s *webhookService) DeleteWebhook(ctx context.Context, id, workspaceID uuid
 	return s.webhookRepo.Delete(ctx, id)
 }
 
+func (s *webhookService) RotateSecret(ctx context.Context, webhookID, workspaceID uuid.UUID) (*models.RotateWebhookSecretResponse, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.WorkspaceID != workspaceID {
+		return nil, httputil.Forbidden("webhook does not belong to this workspace")
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// A rotation during an open window retires the secret before the
+	// current one: only the two most recent secrets ever sign deliveries.
+	webhook, err = s.webhookRepo.RotateSecret(ctx, webhookID, secret, time.Now().Add(s.rotationWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("webhook secret rotated",
+		zap.String("webhook_id", webhookID.String()),
+		zap.Duration("dual_sign_window", s.rotationWindow),
+	)
+
+	return &models.RotateWebhookSecretResponse{
+		Webhook: webhook,
+		Secret:  secret,
+	}, nil
+}
+
 func (s *webhookService) ListDeliveries(ctx context.Context, webhookID, workspaceID uuid.UUID, limit, offset int32) ([]*models.WebhookDelivery, int64, error) {
 	// Verify webhook belongs to workspace
 	webhook, err := s.webhookRepo.GetByID(ctx, webhookID)
@@ -133,3 +195,111 @@ func (s *webhookService) ListDeliveries(ctx context.Context, webhookID, workspac
 
 	return deliveries, total, nil
 }
+
+func (s *webhookService) ListDeadLetters(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*models.WebhookDeadLetter, int64, error) {
+	key := webhookDLQPrefix + workspaceID.String()
+
+	total, err := s.redis.LLen(ctx, key).Result()
+	if err != nil {
+		return nil, 0, httputil.Wrap(err, "failed to count dead-lettered deliveries")
+	}
+	raw, err := s.redis.LRange(ctx, key, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, httputil.Wrap(err, "failed to list dead-lettered deliveries")
+	}
+
+	entries := make([]*models.WebhookDeadLetter, 0, len(raw))
+	for _, r := range raw {
+		var entry models.WebhookDeadLetter
+		if err := json.Unmarshal([]byte(r), &entry); err != nil {
+			s.logger.Warn("skipping malformed dead-letter entry", zap.Error(err))
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, total, nil
+}
+
+// ReprocessDeadLetters requeues every dead-lettered delivery of the
+// workspace's active webhooks; the worker then retries each one from
+// scratch under its original delivery ID. Requeued and dropped entries leave
+// the queue.
+func (s *webhookService) ReprocessDeadLetters(ctx context.Context, workspaceID uuid.UUID) (*models.ReprocessDeadLettersResult, error) {
+	key := webhookDLQPrefix + workspaceID.String()
+	raw, err := s.redis.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to list dead-lettered deliveries")
+	}
+
+	result := &models.ReprocessDeadLettersResult{}
+	webhooks := make(map[uuid.UUID]*models.Webhook)
+	for _, r := range raw {
+		var entry models.WebhookDeadLetter
+		if err := json.Unmarshal([]byte(r), &entry); err != nil {
+			s.removeDeadLetter(ctx, key, r)
+			result.Dropped++
+			continue
+		}
+
+		webhook, ok := webhooks[entry.WebhookID]
+		if !ok {
+			webhook, err = s.webhookRepo.GetByID(ctx, entry.WebhookID)
+			if err != nil && !errors.Is(err, httputil.ErrNotFound) {
+				return nil, err
+			}
+			webhooks[entry.WebhookID] = webhook
+		}
+		if webhook == nil || webhook.WorkspaceID != workspaceID {
+			s.removeDeadLetter(ctx, key, r)
+			result.Dropped++
+			continue
+		}
+		if !webhook.IsActive {
+			result.Skipped++
+			continue
+		}
+
+		if err := s.webhookRepo.RequeueDelivery(ctx, entry.DeliveryID); err != nil {
+			if !errors.Is(err, httputil.ErrNotFound) {
+				return nil, err
+			}
+			s.removeDeadLetter(ctx, key, r)
+			result.Dropped++
+			continue
+		}
+		s.removeDeadLetter(ctx, key, r)
+		result.Requeued++
+	}
+
+	return result, nil
+}
+
+func (s *webhookService) removeDeadLetter(ctx context.Context, key, raw string) {
+	if err := s.redis.LRem(ctx, key, 1, raw).Err(); err != nil {
+		s.logger.Warn("failed to remove dead-letter entry", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// checkWebhookURL requires an HTTPS URL that doesn't point at an internal
+// address (SSRF protection).
+func checkWebhookURL(ctx context.Context, hostPolicy *httputil.HostPolicy, rawURL string) error {
+	if !strings.HasPrefix(rawURL, "https://") {
+		return httputil.Validation("url", "webhook URL must use HTTPS")
+	}
+	if err := hostPolicy.CheckURL(ctx, rawURL); err != nil {
+		if errors.Is(err, httputil.ErrBlockedAddress) {
+			return httputil.Validation("url", "webhook URL must not point to a private or internal address")
+		}
+		return httputil.Validation("url", "webhook URL host could not be resolved")
+	}
+	return nil
+}
+
+// newWebhookSecret generates a signing secret: whsec_ + 32 random hex bytes.
+func newWebhookSecret() (string, error) {
+	rawBytes := make([]byte, 32)
+	if _, err := rand.Read(rawBytes); err != nil {
+		return "", httputil.Wrap(err, "failed to generate webhook secret")
+	}
+	return "whsec_" + hex.EncodeToString(rawBytes), nil
+}
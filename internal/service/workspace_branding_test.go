@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type settingsWorkspaceRepo struct {
+	repository.WorkspaceRepository
+	settings []byte
+	updated  *sqlc.UpdateWorkspaceParams
+}
+
+func (m *settingsWorkspaceRepo) GetByID(_ context.Context, id uuid.UUID) (*models.Workspace, error) {
+	return &models.Workspace{ID: id, Settings: m.settings}, nil
+}
+
+func (m *settingsWorkspaceRepo) Update(_ context.Context, params sqlc.UpdateWorkspaceParams) (*models.Workspace, error) {
+	m.updated = &params
+	return &models.Workspace{ID: params.ID, Settings: params.Settings}, nil
+}
+
+func TestUpdateWorkspace_BrandingRequiresLicense(t *testing.T) {
+	repo := &settingsWorkspaceRepo{}
+	svc := &workspaceService{wsRepo: repo, licManager: newTestLicenseManager(license.TierFree), logger: zap.NewNop()}
+
+	_, err := svc.UpdateWorkspace(context.Background(), uuid.New(), models.UpdateWorkspaceInput{
+		Branding: &models.WorkspaceBranding{AccentColor: "#0F766E"},
+	})
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "PAYMENT_REQUIRED" {
+		t.Fatalf("expected PAYMENT_REQUIRED, got %v", err)
+	}
+	if repo.updated != nil {
+		t.Error("expected no update without the white label feature")
+	}
+}
+
+func TestUpdateWorkspace_RemoveBrandingWithoutLicense(t *testing.T) {
+	repo := &settingsWorkspaceRepo{settings: []byte(`{"branding":{"accent_color":"#0f766e"},"timezone":"Asia/Tokyo"}`)}
+	svc := &workspaceService{wsRepo: repo, licManager: newTestLicenseManager(license.TierFree), logger: zap.NewNop()}
+
+	ws, err := svc.UpdateWorkspace(context.Background(), uuid.New(), models.UpdateWorkspaceInput{
+		Branding: &models.WorkspaceBranding{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	settings := ws.ParsedSettings()
+	if settings.Branding != nil || settings.Timezone != "Asia/Tokyo" {
+		t.Errorf("settings = %+v, want branding removed and the rest kept", settings)
+	}
+}
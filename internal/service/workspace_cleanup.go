@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// workspaceCleanupQueue holds the IDs of deleted workspaces waiting for the
+// worker to clean up their resources.
+const workspaceCleanupQueue = "workspace:cleanup:queue"
+
+// WorkspaceCleanupReport counts what one cleanup run changed.
+type WorkspaceCleanupReport struct {
+	Links         int64
+	BioPages      int
+	Domains       int
+	APIKeys       int
+	Webhooks      int
+	Objects       int
+	FailedObjects int
+}
+
+// WorkspaceCleaner retires everything a deleted workspace leaves behind:
+// links, bio pages and domains are soft-deleted, SSL certificates removed,
+// API keys revoked, webhooks disabled and stored files deleted. Every step
+// skips what an earlier run already did, so a cleanup can be repeated until
+// it completes.
+type WorkspaceCleaner struct {
+	wsRepo      repository.WorkspaceRepository
+	linkRepo    repository.LinkRepository
+	qrRepo      repository.QRCodeRepository
+	bioPageRepo repository.BioPageRepository
+	domainRepo  repository.DomainRepository
+	apiKeyRepo  repository.APIKeyRepository
+	webhookRepo repository.WebhookRepository
+	sslProvider SSLProvider
+	store       storage.ObjectStorage
+	logger      *zap.Logger
+}
+
+func NewWorkspaceCleaner(
+	wsRepo repository.WorkspaceRepository,
+	linkRepo repository.LinkRepository,
+	qrRepo repository.QRCodeRepository,
+	bioPageRepo repository.BioPageRepository,
+	domainRepo repository.DomainRepository,
+	apiKeyRepo repository.APIKeyRepository,
+	webhookRepo repository.WebhookRepository,
+	sslProvider SSLProvider,
+	store storage.ObjectStorage,
+	logger *zap.Logger,
+) *WorkspaceCleaner {
+	return &WorkspaceCleaner{
+		wsRepo:      wsRepo,
+		linkRepo:    linkRepo,
+		qrRepo:      qrRepo,
+		bioPageRepo: bioPageRepo,
+		domainRepo:  domainRepo,
+		apiKeyRepo:  apiKeyRepo,
+		webhookRepo: webhookRepo,
+		sslProvider: sslProvider,
+		store:       store,
+		logger:      logger,
+	}
+}
+
+// Run cleans up the deleted workspace with the given ID and marks it done.
+// Workspaces that are still live are left alone. Files that can't be
+// deleted are logged and skipped; any other failure leaves the workspace
+// pending so a later run retries it.
+func (c *WorkspaceCleaner) Run(ctx context.Context, workspaceID uuid.UUID) error {
+	if _, err := c.wsRepo.GetByID(ctx, workspaceID); err == nil {
+		c.logger.Warn("skipping cleanup of a workspace that isn't deleted",
+			zap.String("workspace_id", workspaceID.String()),
+		)
+		return nil
+	} else if !errors.Is(err, httputil.ErrNotFound) {
+		return err
+	}
+
+	var (
+		report WorkspaceCleanupReport
+		errs   []error
+	)
+	// QR images are found through the links, so remove them first; the
+	// listing includes deleted links, so a repeated run still finds them.
+	if err := c.deleteQRImages(ctx, workspaceID, &report); err != nil {
+		errs = append(errs, err)
+	}
+	if n, err := c.linkRepo.SoftDeleteForWorkspace(ctx, workspaceID); err != nil {
+		errs = append(errs, err)
+	} else {
+		report.Links = n
+	}
+	if err := c.deleteBioPages(ctx, workspaceID, &report); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.releaseDomains(ctx, workspaceID, &report); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.revokeAPIKeys(ctx, workspaceID, &report); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.disableWebhooks(ctx, workspaceID, &report); err != nil {
+		errs = append(errs, err)
+	}
+	c.purgePrefixes(ctx, workspaceID, &report)
+
+	fields := []zap.Field{
+		zap.String("workspace_id", workspaceID.String()),
+		zap.Int64("links", report.Links),
+		zap.Int("bio_pages", report.BioPages),
+		zap.Int("domains", report.Domains),
+		zap.Int("api_keys", report.APIKeys),
+		zap.Int("webhooks", report.Webhooks),
+		zap.Int("objects", report.Objects),
+		zap.Int("failed_objects", report.FailedObjects),
+	}
+	if err := errors.Join(errs...); err != nil {
+		c.logger.Warn("workspace cleanup incomplete, will retry", append(fields, zap.Error(err))...)
+		return err
+	}
+	if err := c.wsRepo.MarkAssetsCleaned(ctx, workspaceID); err != nil {
+		return err
+	}
+	c.logger.Info("cleaned up deleted workspace", fields...)
+	return nil
+}
+
+// RunPending cleans up to limit deleted workspaces that haven't been cleaned
+// up yet, such as those whose queued job failed or was lost.
+func (c *WorkspaceCleaner) RunPending(ctx context.Context, limit int32) error {
+	ids, err := c.wsRepo.ListPendingCleanup(ctx, limit)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Run logs its own failures; move on to the next workspace.
+		_ = c.Run(ctx, id)
+	}
+	return nil
+}
+
+func (c *WorkspaceCleaner) deleteQRImages(ctx context.Context, workspaceID uuid.UUID, report *WorkspaceCleanupReport) error {
+	if c.store == nil {
+		return nil
+	}
+	qrs, err := c.qrRepo.ListForWorkspace(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	for _, qr := range qrs {
+		for _, url := range []*string{qr.PngURL, qr.SvgURL} {
+			if url == nil {
+				continue
+			}
+			if key, ok := c.store.KeyForURL(*url); ok {
+				c.deleteObject(ctx, workspaceID, key, report)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *WorkspaceCleaner) deleteBioPages(ctx context.Context, workspaceID uuid.UUID, report *WorkspaceCleanupReport) error {
+	pages, err := c.bioPageRepo.List(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, page := range pages {
+		if c.store != nil {
+			// The generated OG card may predate per-workspace prefixes.
+			key := ogImageKey(page.ID)
+			prefixed := c.store.WorkspaceKey(workspaceID, key)
+			c.deleteObject(ctx, workspaceID, prefixed, report)
+			if prefixed != key {
+				c.deleteObject(ctx, workspaceID, key, report)
+			}
+		}
+		if err := c.bioPageRepo.SoftDelete(ctx, page.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		report.BioPages++
+	}
+	return errors.Join(errs...)
+}
+
+// releaseDomains removes each verified domain's certificate before deleting
+// it, so the host name can be added to another workspace. A domain whose
+// certificate can't be removed is kept for the next run.
+func (c *WorkspaceCleaner) releaseDomains(ctx context.Context, workspaceID uuid.UUID, report *WorkspaceCleanupReport) error {
+	domains, err := c.domainRepo.List(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, d := range domains {
+		if d.IsVerified && c.sslProvider != nil {
+			if err := c.sslProvider.RemoveSSL(ctx, d.Domain); err != nil {
+				errs = append(errs, fmt.Errorf("removing SSL certificate for %s: %w", d.Domain, err))
+				continue
+			}
+		}
+		if err := c.domainRepo.SoftDelete(ctx, d.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		report.Domains++
+	}
+	return errors.Join(errs...)
+}
+
+func (c *WorkspaceCleaner) revokeAPIKeys(ctx context.Context, workspaceID uuid.UUID, report *WorkspaceCleanupReport) error {
+	keys, err := c.apiKeyRepo.List(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, k := range keys {
+		if err := c.apiKeyRepo.Revoke(ctx, k.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		report.APIKeys++
+	}
+	return errors.Join(errs...)
+}
+
+func (c *WorkspaceCleaner) disableWebhooks(ctx context.Context, workspaceID uuid.UUID, report *WorkspaceCleanupReport) error {
+	webhooks, err := c.webhookRepo.List(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, w := range webhooks {
+		if !w.IsActive {
+			continue
+		}
+		if err := c.webhookRepo.Disable(ctx, w.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		report.Webhooks++
+	}
+	return errors.Join(errs...)
+}
+
+// purgePrefixes removes the stored objects under prefixes that belong only
+// to the workspace. With per-workspace prefixes that is everything under the
+// workspace's prefix; keys written before they were enabled are removed too.
+func (c *WorkspaceCleaner) purgePrefixes(ctx context.Context, workspaceID uuid.UUID, report *WorkspaceCleanupReport) {
+	if c.store == nil {
+		return
+	}
+	prefixes := []string{
+		models.WorkspaceExportPrefix(workspaceID),
+		qrBatchPrefix(workspaceID),
+	}
+	if wsPrefix := c.store.WorkspaceKey(workspaceID, ""); wsPrefix != "" {
+		prefixes = append(prefixes, wsPrefix)
+	}
+
+	for _, prefix := range prefixes {
+		if err := c.store.DeletePrefix(ctx, prefix); err != nil {
+			report.FailedObjects++
+			c.logger.Warn("failed to purge workspace assets",
+				zap.String("workspace_id", workspaceID.String()),
+				zap.String("prefix", prefix),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// deleteObject removes one stored file. Failures are logged rather than
+// retried; bucket lifecycle rules can catch what's left.
+func (c *WorkspaceCleaner) deleteObject(ctx context.Context, workspaceID uuid.UUID, key string, report *WorkspaceCleanupReport) {
+	if err := c.store.Delete(ctx, key); err != nil {
+		report.FailedObjects++
+		c.logger.Warn("failed to delete workspace asset",
+			zap.String("workspace_id", workspaceID.String()),
+			zap.String("key", key),
+			zap.Error(err),
+		)
+		return
+	}
+	report.Objects++
+}
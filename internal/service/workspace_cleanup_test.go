@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/storage"
+	"go.uber.org/zap"
+)
+
+type cleanupWorkspaceRepo struct {
+	mockWorkspaceRepo
+	cleaned []uuid.UUID
+}
+
+func (m *cleanupWorkspaceRepo) MarkAssetsCleaned(_ context.Context, id uuid.UUID) error {
+	m.cleaned = append(m.cleaned, id)
+	return nil
+}
+
+type cleanupQRRepo struct {
+	repository.QRCodeRepository
+	qrs []*models.QRCode
+}
+
+func (m *cleanupQRRepo) ListForWorkspace(_ context.Context, _ uuid.UUID) ([]*models.QRCode, error) {
+	return m.qrs, nil
+}
+
+type cleanupBioPageRepo struct {
+	repository.BioPageRepository
+	pages   []*models.BioPage
+	deleted []uuid.UUID
+}
+
+func (m *cleanupBioPageRepo) List(_ context.Context, _ uuid.UUID) ([]*models.BioPage, error) {
+	return m.pages, nil
+}
+
+func (m *cleanupBioPageRepo) SoftDelete(_ context.Context, id uuid.UUID) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+type cleanupAPIKeyRepo struct {
+	repository.APIKeyRepository
+	keys    []*models.APIKey
+	revoked []uuid.UUID
+}
+
+func (m *cleanupAPIKeyRepo) List(_ context.Context, _ uuid.UUID) ([]*models.APIKey, error) {
+	return m.keys, nil
+}
+
+func (m *cleanupAPIKeyRepo) Revoke(_ context.Context, id uuid.UUID) error {
+	m.revoked = append(m.revoked, id)
+	return nil
+}
+
+type cleanupWebhookRepo struct {
+	repository.WebhookRepository
+	webhooks []*models.Webhook
+	disabled []uuid.UUID
+}
+
+func (m *cleanupWebhookRepo) List(_ context.Context, _ uuid.UUID) ([]*models.Webhook, error) {
+	return m.webhooks, nil
+}
+
+func (m *cleanupWebhookRepo) Disable(_ context.Context, id uuid.UUID) error {
+	m.disabled = append(m.disabled, id)
+	return nil
+}
+
+type recordingSSLProvider struct {
+	MockSSLProvider
+	removed []string
+	err     error
+}
+
+func (p *recordingSSLProvider) RemoveSSL(_ context.Context, domain string) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.removed = append(p.removed, domain)
+	return nil
+}
+
+func TestWorkspaceCleaner_Run(t *testing.T) {
+	wsID, otherID := uuid.New(), uuid.New()
+	ctx := context.Background()
+	store := storage.NewLocalStorage(t.TempDir(), "http://localhost/uploads",
+		storage.Options{KeyPrefix: "tenant-a", WorkspacePrefixes: true})
+
+	upload := func(key string) string {
+		url, err := store.Upload(ctx, key, []byte("x"), "application/octet-stream")
+		if err != nil {
+			t.Fatalf("upload %s: %v", key, err)
+		}
+		return url
+	}
+	page := &models.BioPage{ID: uuid.New(), WorkspaceID: wsID}
+	pngURL := upload(qrImageKey(uuid.New(), uuid.New()))
+	removed := []string{
+		store.WorkspaceKey(wsID, models.WorkspaceExportPrefix(wsID)+"e.zip"),
+		store.WorkspaceKey(wsID, ogImageKey(page.ID)),
+		// Written before per-workspace prefixes were enabled.
+		models.WorkspaceExportPrefix(wsID) + "old.zip",
+		qrBatchPrefix(wsID) + "old.zip",
+		ogImageKey(page.ID),
+	}
+	kept := []string{
+		store.WorkspaceKey(otherID, "qr/abc/def.png"),
+		models.WorkspaceExportPrefix(otherID) + "old.zip",
+	}
+	for _, key := range append(append([]string{}, removed...), kept...) {
+		upload(key)
+	}
+
+	verified := &models.Domain{ID: uuid.New(), WorkspaceID: wsID, Domain: "go.example.com", IsVerified: true}
+	pending := &models.Domain{ID: uuid.New(), WorkspaceID: wsID, Domain: "pending.example.com"}
+	domainRepo := newMockDomainRepo()
+	for _, d := range []*models.Domain{verified, pending} {
+		domainRepo.domains[d.ID] = d
+		domainRepo.domainsByStr[d.Domain] = d
+	}
+
+	var linksDeletedFor uuid.UUID
+	wsRepo := &cleanupWorkspaceRepo{}
+	bioRepo := &cleanupBioPageRepo{pages: []*models.BioPage{page}}
+	keyRepo := &cleanupAPIKeyRepo{keys: []*models.APIKey{{ID: uuid.New()}, {ID: uuid.New()}}}
+	webhookRepo := &cleanupWebhookRepo{webhooks: []*models.Webhook{
+		{ID: uuid.New(), IsActive: true},
+		{ID: uuid.New(), IsActive: false},
+	}}
+	ssl := &recordingSSLProvider{}
+
+	cleaner := NewWorkspaceCleaner(
+		wsRepo,
+		&mockLinkRepo{softDeleteForWsFn: func(_ context.Context, id uuid.UUID) (int64, error) {
+			linksDeletedFor = id
+			return 3, nil
+		}},
+		&cleanupQRRepo{qrs: []*models.QRCode{{ID: uuid.New(), PngURL: &pngURL}}},
+		bioRepo,
+		domainRepo,
+		keyRepo,
+		webhookRepo,
+		ssl,
+		store,
+		zap.NewNop(),
+	)
+
+	if err := cleaner.Run(ctx, wsID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if linksDeletedFor != wsID {
+		t.Errorf("links deleted for %s, want %s", linksDeletedFor, wsID)
+	}
+	if len(bioRepo.deleted) != 1 || bioRepo.deleted[0] != page.ID {
+		t.Errorf("deleted bio pages = %v, want [%s]", bioRepo.deleted, page.ID)
+	}
+	if len(domainRepo.domains) != 0 {
+		t.Errorf("%d domains left, want 0", len(domainRepo.domains))
+	}
+	if len(ssl.removed) != 1 || ssl.removed[0] != verified.Domain {
+		t.Errorf("SSL removed for %v, want only the verified domain", ssl.removed)
+	}
+	if len(keyRepo.revoked) != 2 {
+		t.Errorf("revoked %d API keys, want 2", len(keyRepo.revoked))
+	}
+	if len(webhookRepo.disabled) != 1 || webhookRepo.disabled[0] != webhookRepo.webhooks[0].ID {
+		t.Errorf("disabled webhooks = %v, want only the active one", webhookRepo.disabled)
+	}
+
+	qrKey, ok := store.KeyForURL(pngURL)
+	if !ok {
+		t.Fatalf("KeyForURL(%q) not found", pngURL)
+	}
+	for _, key := range append(removed, qrKey) {
+		if _, err := store.Get(ctx, key); err == nil {
+			t.Errorf("%s survived the cleanup", key)
+		}
+	}
+	for _, key := range kept {
+		if _, err := store.Get(ctx, key); err != nil {
+			t.Errorf("%s was removed with another workspace: %v", key, err)
+		}
+	}
+
+	if len(wsRepo.cleaned) != 1 || wsRepo.cleaned[0] != wsID {
+		t.Errorf("marked cleaned = %v, want [%s]", wsRepo.cleaned, wsID)
+	}
+}
+
+func TestWorkspaceCleaner_PurgePrefixes(t *testing.T) {
+	for _, wsPrefixes := range []bool{true, false} {
+		wsID, otherID := uuid.New(), uuid.New()
+		store := storage.NewLocalStorage(t.TempDir(), "http://localhost/uploads",
+			storage.Options{KeyPrefix: "tenant-a", WorkspacePrefixes: wsPrefixes})
+		ctx := context.Background()
+
+		removed := []string{
+			models.WorkspaceExportPrefix(wsID) + "old.zip",
+			qrBatchPrefix(wsID) + "old.zip",
+		}
+		kept := []string{
+			models.WorkspaceExportPrefix(otherID) + "old.zip",
+			qrBatchPrefix(otherID) + "old.zip",
+		}
+		if wsPrefixes {
+			removed = append(removed,
+				store.WorkspaceKey(wsID, "qr/abc/def.png"),
+				store.WorkspaceKey(wsID, models.WorkspaceExportPrefix(wsID)+"e.zip"),
+			)
+			kept = append(kept, store.WorkspaceKey(otherID, "qr/abc/def.png"))
+		}
+		for _, key := range append(append([]string{}, removed...), kept...) {
+			if _, err := store.Upload(ctx, key, []byte("x"), "application/octet-stream"); err != nil {
+				t.Fatalf("upload %s: %v", key, err)
+			}
+		}
+
+		cleaner := &WorkspaceCleaner{store: store, logger: zap.NewNop()}
+		var report WorkspaceCleanupReport
+		cleaner.purgePrefixes(ctx, wsID, &report)
+
+		if report.FailedObjects != 0 {
+			t.Errorf("workspace prefixes %v: %d failed purges", wsPrefixes, report.FailedObjects)
+		}
+		for _, key := range removed {
+			if _, err := store.Get(ctx, key); err == nil {
+				t.Errorf("workspace prefixes %v: %s survived the purge", wsPrefixes, key)
+			}
+		}
+		for _, key := range kept {
+			if _, err := store.Get(ctx, key); err != nil {
+				t.Errorf("workspace prefixes %v: %s was purged with another workspace: %v", wsPrefixes, key, err)
+			}
+		}
+	}
+}
+
+func TestWorkspaceCleaner_RetriesAfterFailure(t *testing.T) {
+	wsID := uuid.New()
+	domain := &models.Domain{ID: uuid.New(), WorkspaceID: wsID, Domain: "go.example.com", IsVerified: true}
+	domainRepo := newMockDomainRepo()
+	domainRepo.domains[domain.ID] = domain
+	domainRepo.domainsByStr[domain.Domain] = domain
+
+	wsRepo := &cleanupWorkspaceRepo{}
+	ssl := &recordingSSLProvider{err: errors.New("provider unavailable")}
+	cleaner := NewWorkspaceCleaner(
+		wsRepo, &mockLinkRepo{}, &cleanupQRRepo{}, &cleanupBioPageRepo{}, domainRepo,
+		&cleanupAPIKeyRepo{}, &cleanupWebhookRepo{}, ssl, nil, zap.NewNop(),
+	)
+
+	if err := cleaner.Run(context.Background(), wsID); err == nil {
+		t.Fatal("expected an error while the certificate can't be removed")
+	}
+	if _, ok := domainRepo.domains[domain.ID]; !ok {
+		t.Error("domain was deleted while it still has a certificate")
+	}
+	if len(wsRepo.cleaned) != 0 {
+		t.Error("workspace marked cleaned after a failed run")
+	}
+
+	ssl.err = nil
+	if err := cleaner.Run(context.Background(), wsID); err != nil {
+		t.Fatalf("retry: unexpected error: %v", err)
+	}
+	if len(domainRepo.domains) != 0 || len(wsRepo.cleaned) != 1 {
+		t.Errorf("after retry: %d domains left, cleaned = %v", len(domainRepo.domains), wsRepo.cleaned)
+	}
+}
+
+func TestWorkspaceCleaner_SkipsLiveWorkspace(t *testing.T) {
+	wsID := uuid.New()
+	wsRepo := &cleanupWorkspaceRepo{mockWorkspaceRepo: mockWorkspaceRepo{
+		workspaces: map[uuid.UUID]*models.Workspace{wsID: {ID: wsID}},
+	}}
+	linkRepo := &mockLinkRepo{softDeleteForWsFn: func(_ context.Context, _ uuid.UUID) (int64, error) {
+		t.Error("links of a live workspace were deleted")
+		return 0, nil
+	}}
+	cleaner := NewWorkspaceCleaner(
+		wsRepo, linkRepo, &cleanupQRRepo{}, &cleanupBioPageRepo{}, newMockDomainRepo(),
+		&cleanupAPIKeyRepo{}, &cleanupWebhookRepo{}, &recordingSSLProvider{}, nil, zap.NewNop(),
+	)
+
+	if err := cleaner.Run(context.Background(), wsID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(wsRepo.cleaned) != 0 {
+		t.Error("live workspace marked cleaned")
+	}
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/storage"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	workspaceExportQueue     = "workspace:export:queue"
+	workspaceExportKeyPrefix = "workspace_export:"
+	// workspaceExportActivePrefix maps a workspace to its unfinished export
+	// so repeated requests don't queue duplicate jobs.
+	workspaceExportActivePrefix = "workspace_export:active:"
+
+	// workspaceExportRetention is how long a job's status and download stay
+	// available after it is requested.
+	workspaceExportRetention = 7 * 24 * time.Hour
+	// workspaceExportActiveTTL bounds how long a stuck job blocks new ones.
+	workspaceExportActiveTTL = time.Hour
+)
+
+func (s *workspaceService) ExportWorkspace(ctx context.Context, workspaceID, actorID uuid.UUID) (*models.WorkspaceExport, error) {
+	if err := s.requireOwner(ctx, workspaceID, actorID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	export := &models.WorkspaceExport{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		RequestedBy: actorID,
+		Status:      models.WorkspaceExportPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(workspaceExportRetention),
+	}
+
+	activeKey := workspaceExportActivePrefix + workspaceID.String()
+	acquired, err := s.redis.SetNX(ctx, activeKey, export.ID.String(), workspaceExportActiveTTL).Result()
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to queue workspace export")
+	}
+	if !acquired {
+		activeID, err := s.redis.Get(ctx, activeKey).Result()
+		if err == nil {
+			if id, err := uuid.Parse(activeID); err == nil {
+				if existing, err := loadWorkspaceExport(ctx, s.redis, id); err == nil && !existing.IsFinished() {
+					return existing, nil
+				}
+			}
+		}
+		// The marker points at a finished or expired job; take it over.
+		if err := s.redis.Set(ctx, activeKey, export.ID.String(), workspaceExportActiveTTL).Err(); err != nil {
+			return nil, httputil.Wrap(err, "failed to queue workspace export")
+		}
+	}
+
+	if err := saveWorkspaceExport(ctx, s.redis, export); err != nil {
+		return nil, err
+	}
+	if err := s.redis.RPush(ctx, workspaceExportQueue, export.ID.String()).Err(); err != nil {
+		return nil, httputil.Wrap(err, "failed to queue workspace export")
+	}
+
+	return export, nil
+}
+
+func (s *workspaceService) GetExport(ctx context.Context, workspaceID, exportID, actorID uuid.UUID) (*models.WorkspaceExport, error) {
+	if err := s.requireOwner(ctx, workspaceID, actorID); err != nil {
+		return nil, err
+	}
+
+	export, err := loadWorkspaceExport(ctx, s.redis, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.WorkspaceID != workspaceID {
+		return nil, httputil.NotFound("workspace export")
+	}
+	return export, nil
+}
+
+func (s *workspaceService) DownloadExport(ctx context.Context, workspaceID, exportID, actorID uuid.UUID) ([]byte, error) {
+	export, err := s.GetExport(ctx, workspaceID, exportID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != models.WorkspaceExportCompleted {
+		return nil, httputil.Validation("export_id", "export is not ready for download")
+	}
+
+	data, err := s.store.Get(ctx, s.store.WorkspaceKey(export.WorkspaceID, export.StorageKey()))
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to read workspace export")
+	}
+	return data, nil
+}
+
+func (s *workspaceService) requireOwner(ctx context.Context, workspaceID, actorID uuid.UUID) error {
+	ws, err := s.wsRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if ws.OwnerID != actorID {
+		return httputil.Forbidden("only the workspace owner can export or import workspace data")
+	}
+	return nil
+}
+
+func loadWorkspaceExport(ctx context.Context, rdb *redis.Client, id uuid.UUID) (*models.WorkspaceExport, error) {
+	data, err := rdb.Get(ctx, workspaceExportKeyPrefix+id.String()).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, httputil.NotFound("workspace export")
+		}
+		return nil, httputil.Wrap(err, "failed to load workspace export")
+	}
+
+	var export models.WorkspaceExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, httputil.Wrap(err, "failed to decode workspace export")
+	}
+	return &export, nil
+}
+
+func saveWorkspaceExport(ctx context.Context, rdb *redis.Client, export *models.WorkspaceExport) error {
+	data, err := json.Marshal(export)
+	if err != nil {
+		return httputil.Wrap(err, "failed to encode workspace export")
+	}
+
+	ttl := time.Until(export.ExpiresAt)
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	if err := rdb.Set(ctx, workspaceExportKeyPrefix+export.ID.String(), data, ttl).Err(); err != nil {
+		return httputil.Wrap(err, "failed to save workspace export")
+	}
+	return nil
+}
+
+// WorkspaceExporter builds the archives for queued workspace exports. It runs
+// in the worker process.
+type WorkspaceExporter struct {
+	redis         *redis.Client
+	wsRepo        repository.WorkspaceRepository
+	linkRepo      repository.LinkRepository
+	bioPageRepo   repository.BioPageRepository
+	domainRepo    repository.DomainRepository
+	webhookRepo   repository.WebhookRepository
+	analyticsRepo repository.AnalyticsRepository
+	store         storage.ObjectStorage
+	cfg           *config.Config
+	logger        *zap.Logger
+}
+
+func NewWorkspaceExporter(
+	redisClient *redis.Client,
+	wsRepo repository.WorkspaceRepository,
+	linkRepo repository.LinkRepository,
+	bioPageRepo repository.BioPageRepository,
+	domainRepo repository.DomainRepository,
+	webhookRepo repository.WebhookRepository,
+	analyticsRepo repository.AnalyticsRepository,
+	store storage.ObjectStorage,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *WorkspaceExporter {
+	return &WorkspaceExporter{
+		redis:         redisClient,
+		wsRepo:        wsRepo,
+		linkRepo:      linkRepo,
+		bioPageRepo:   bioPageRepo,
+		domainRepo:    domainRepo,
+		webhookRepo:   webhookRepo,
+		analyticsRepo: analyticsRepo,
+		store:         store,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// Run builds and stores the archive for the export job with the given ID,
+// recording the outcome on the job. Jobs that already finished or expired
+// are skipped.
+func (e *WorkspaceExporter) Run(ctx context.Context, exportID uuid.UUID) error {
+	export, err := loadWorkspaceExport(ctx, e.redis, exportID)
+	if err != nil {
+		return err
+	}
+	if export.IsFinished() {
+		return nil
+	}
+	defer e.redis.Del(context.WithoutCancel(ctx), workspaceExportActivePrefix+export.WorkspaceID.String())
+
+	export.Status = models.WorkspaceExportProcessing
+	if err := saveWorkspaceExport(ctx, e.redis, export); err != nil {
+		return err
+	}
+
+	size, buildErr := e.buildAndStore(ctx, export)
+
+	now := time.Now()
+	export.CompletedAt = &now
+	if buildErr != nil {
+		e.logger.Error("workspace export failed",
+			zap.String("export_id", export.ID.String()),
+			zap.String("workspace_id", export.WorkspaceID.String()),
+			zap.Error(buildErr),
+		)
+		export.Status = models.WorkspaceExportFailed
+		export.Error = "export could not be generated"
+	} else {
+		export.Status = models.WorkspaceExportCompleted
+		export.SizeBytes = size
+	}
+
+	// Record the outcome even if the worker is shutting down.
+	if err := saveWorkspaceExport(context.WithoutCancel(ctx), e.redis, export); err != nil {
+		return err
+	}
+	return buildErr
+}
+
+func (e *WorkspaceExporter) buildAndStore(ctx context.Context, export *models.WorkspaceExport) (int64, error) {
+	data, err := e.collect(ctx, export.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeExportArchive(&buf, data); err != nil {
+		return 0, err
+	}
+	key := e.store.WorkspaceKey(export.WorkspaceID, export.StorageKey())
+	if _, err := e.store.Upload(ctx, key, buf.Bytes(), "application/zip"); err != nil {
+		return 0, httputil.Wrap(err, "failed to upload workspace export")
+	}
+	return int64(buf.Len()), nil
+}
+
+// workspaceExportData is everything that goes into an export archive.
+type workspaceExportData struct {
+	Workspace *models.Workspace
+	Links     []*models.Link
+	LinkRows  []*models.LinkExportRow
+	BioPages  []*models.BioPage
+	Domains   []*models.Domain
+	Webhooks  []*models.Webhook
+	Analytics *models.WorkspaceAnalytics
+}
+
+func (e *WorkspaceExporter) collect(ctx context.Context, workspaceID uuid.UUID) (*workspaceExportData, error) {
+	ws, err := e.wsRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	data := &workspaceExportData{Workspace: ws}
+
+	// Analytics cover the workspace's whole lifetime.
+	dr := models.DateRange{Start: ws.CreatedAt, End: time.Now()}
+
+	for offset := 0; ; offset += exportPageSize {
+		links, _, err := e.linkRepo.List(ctx, sqlc.ListLinksForWorkspaceParams{
+			WorkspaceID: workspaceID,
+			Limit:       exportPageSize,
+			Offset:      int32(offset),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]uuid.UUID, len(links))
+		for i, link := range links {
+			ids[i] = link.ID
+		}
+		summaries, err := e.analyticsRepo.GetLinkClickSummaries(ctx, ids, dr)
+		if err != nil {
+			return nil, httputil.Wrap(err, "failed to get link click summaries")
+		}
+
+		for _, link := range links {
+			data.Links = append(data.Links, link)
+			data.LinkRows = append(data.LinkRows, models.NewLinkExportRow(link, summaries[link.ID], e.cfg.App.RedirectURL))
+		}
+		if len(links) < exportPageSize {
+			break
+		}
+	}
+
+	data.BioPages, err = e.bioPageRepo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range data.BioPages {
+		page.Links, err = e.bioPageRepo.ListLinks(ctx, page.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data.Domains, err = e.domainRepo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Webhooks, err = e.webhookRepo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Analytics, err = e.analyticsRepo.GetWorkspaceStats(ctx, workspaceID, dr)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to get workspace analytics")
+	}
+
+	return data, nil
+}
+
+// writeExportArchive writes the export as a ZIP: a manifest, one JSON file
+// per resource, and links.csv with per-link click totals.
+func writeExportArchive(w io.Writer, data *workspaceExportData) error {
+	zw := zip.NewWriter(w)
+
+	manifest := models.WorkspaceExportManifest{
+		FormatVersion: models.WorkspaceExportFormatVersion,
+		WorkspaceID:   data.Workspace.ID,
+		ExportedAt:    time.Now().UTC(),
+	}
+	jsonFiles := []struct {
+		name  string
+		value any
+	}{
+		{"manifest.json", manifest},
+		{"workspace.json", data.Workspace},
+		{"links.json", nonNil(data.Links)},
+		{"bio_pages.json", nonNil(data.BioPages)},
+		{"domains.json", nonNil(data.Domains)},
+		{"webhooks.json", nonNil(data.Webhooks)},
+		{"analytics.json", data.Analytics},
+	}
+	for _, f := range jsonFiles {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return httputil.Wrap(err, "failed to write export archive")
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.value); err != nil {
+			return httputil.Wrap(err, "failed to write "+f.name)
+		}
+	}
+
+	fw, err := zw.Create("links.csv")
+	if err != nil {
+		return httputil.Wrap(err, "failed to write export archive")
+	}
+	cw := csv.NewWriter(fw)
+	if err := cw.Write(models.LinkExportCSVHeader); err != nil {
+		return httputil.Wrap(err, "failed to write links.csv")
+	}
+	for _, row := range data.LinkRows {
+		if err := cw.Write(row.CSVRecord()); err != nil {
+			return httputil.Wrap(err, "failed to write links.csv")
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return httputil.Wrap(err, "failed to write links.csv")
+	}
+
+	if err := zw.Close(); err != nil {
+		return httputil.Wrap(err, "failed to write export archive")
+	}
+	return nil
+}
+
+// nonNil returns an empty slice for nil so empty resources encode as [].
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+func TestExportWorkspace_OwnerOnly(t *testing.T) {
+	wsID := uuid.New()
+	svc := &workspaceService{
+		wsRepo: &mockWorkspaceRepo{workspaces: map[uuid.UUID]*models.Workspace{
+			wsID: {ID: wsID, OwnerID: uuid.New()},
+		}},
+		logger: zap.NewNop(),
+	}
+
+	_, err := svc.ExportWorkspace(context.Background(), wsID, uuid.New())
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "FORBIDDEN" {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+}
+
+func TestWriteExportArchive(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "abc123")
+	title := `=HYPERLINK("https://evil.example")`
+	link.Title = &title
+	data := &workspaceExportData{
+		Workspace: &models.Workspace{ID: link.WorkspaceID, Name: "Acme", Slug: "acme"},
+		Links:     []*models.Link{link},
+		LinkRows: []*models.LinkExportRow{
+			models.NewLinkExportRow(link, models.LinkClickSummary{Clicks: 7}, "https://lrift.co"),
+		},
+		Analytics: &models.WorkspaceAnalytics{TotalLinks: 1, TotalClicks: 7},
+	}
+
+	var buf bytes.Buffer
+	if err := writeExportArchive(&buf, data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	for _, name := range []string{"manifest.json", "workspace.json", "links.json", "bio_pages.json", "domains.json", "webhooks.json", "analytics.json", "links.csv"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("archive is missing %s", name)
+		}
+	}
+
+	var domains []any
+	if err := json.Unmarshal(files["domains.json"], &domains); err != nil || domains == nil {
+		t.Errorf("domains.json = %q, want an empty array", files["domains.json"])
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(files["links.csv"])).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid links.csv: %v", err)
+	}
+	if len(records) != 2 || records[1][1] != "abc123" || records[1][7] != "7" {
+		t.Errorf("links.csv = %v", records)
+	}
+	if len(records) == 2 && records[1][4] != "'"+title {
+		t.Errorf("links.csv title = %q, want it escaped", records[1][4])
+	}
+
+	var links []*models.Link
+	if err := json.Unmarshal(files["links.json"], &links); err != nil || len(links) != 1 || links[0].Title == nil || *links[0].Title != title {
+		t.Errorf("links.json should keep the title as entered, got %s", files["links.json"])
+	}
+}
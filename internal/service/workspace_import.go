@@ -0,0 +1,654 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/shortcode"
+	"go.uber.org/zap"
+)
+
+const (
+	// workspaceImportMaxEntrySize bounds how much of a single archive entry is
+	// read, so a crafted archive can't decompress without limit.
+	workspaceImportMaxEntrySize = 100 << 20
+
+	// importSuffixMaxAttempts is how many numeric suffixes are tried for a
+	// taken short code or slug before the item is skipped.
+	importSuffixMaxAttempts = 100
+
+	maxShortCodeLength = 50
+	maxBioSlugLength   = 100
+)
+
+// workspaceImportArchive is the importable content of an export archive.
+type workspaceImportArchive struct {
+	Links    []*models.Link
+	BioPages []*models.BioPage
+	Webhooks []*models.Webhook
+}
+
+// ImportWorkspace recreates the links, bio pages and webhooks of an export
+// archive in the workspace. Everything runs in one transaction; a dry run
+// rolls it back so the result reports what an import would do.
+func (s *workspaceService) ImportWorkspace(ctx context.Context, workspaceID, actorID uuid.UUID, archive []byte, opts models.WorkspaceImportOptions) (*models.WorkspaceImportResult, error) {
+	if err := s.requireOwner(ctx, workspaceID, actorID); err != nil {
+		return nil, err
+	}
+
+	opts, err := normalizeImportOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readImportArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkLinkLimit(ctx, s.licManager, s.linkRepo, workspaceID, int64(len(data.Links))); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := sqlc.New(tx)
+	txLinkRepo := repository.NewLinkRepository(qtx, s.logger)
+	imp := &workspaceImporter{
+		workspaceID: workspaceID,
+		actorID:     actorID,
+		opts:        opts,
+		linkRepo:    txLinkRepo,
+		flagRepo:    repository.NewLinkFlagRepository(qtx, s.logger),
+		screener: &linkService{
+			linkRepo:   txLinkRepo,
+			domainRepo: s.domainRepo,
+			urlChecker: s.urlChecker,
+			cfg:        s.cfg,
+			logger:     s.logger,
+		},
+		bioPageRepo: repository.NewBioPageRepository(qtx, s.logger),
+		webhookRepo: repository.NewWebhookRepository(qtx, s.logger),
+		licManager:  s.licManager,
+		hostPolicy:  s.webhookHostPolicy,
+		codeGen:     s.codeGen,
+		cfg:         s.cfg.Links,
+		result: &models.WorkspaceImportResult{
+			DryRun:         opts.DryRun,
+			Conflicts:      []models.ImportConflict{},
+			Warnings:       []models.ImportWarning{},
+			ShortCodes:     map[string]string{},
+			WebhookSecrets: map[uuid.UUID]string{},
+		},
+	}
+
+	if err := enforceLinkLimit(ctx, s.licManager, imp.linkRepo, workspaceID, int64(len(data.Links))); err != nil {
+		return nil, err
+	}
+	if err := imp.importLinks(ctx, data.Links); err != nil {
+		return nil, err
+	}
+	if err := imp.importBioPages(ctx, data.BioPages); err != nil {
+		return nil, err
+	}
+	if err := imp.importWebhooks(ctx, data.Webhooks); err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		// Nothing was saved, so the generated secrets are meaningless.
+		imp.result.WebhookSecrets = nil
+		return imp.result, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, httputil.Wrap(err, "failed to commit transaction")
+	}
+
+	s.logger.Info("workspace imported",
+		zap.String("workspace_id", workspaceID.String()),
+		zap.Int("links", imp.result.Links.Created),
+		zap.Int("bio_pages", imp.result.BioPages.Created),
+		zap.Int("webhooks", imp.result.Webhooks.Created),
+	)
+
+	return imp.result, nil
+}
+
+func normalizeImportOptions(opts models.WorkspaceImportOptions) (models.WorkspaceImportOptions, error) {
+	switch opts.ShortCodes {
+	case "":
+		opts.ShortCodes = models.ImportShortCodesPreserve
+	case models.ImportShortCodesPreserve, models.ImportShortCodesRegenerate:
+	default:
+		return opts, httputil.Validation("short_codes", "must be \"preserve\" or \"regenerate\"")
+	}
+
+	switch opts.OnConflict {
+	case "":
+		opts.OnConflict = models.ImportConflictSkip
+	case models.ImportConflictSkip, models.ImportConflictSuffix:
+	default:
+		return opts, httputil.Validation("on_conflict", "must be \"skip\" or \"suffix\"")
+	}
+
+	return opts, nil
+}
+
+// readImportArchive unpacks an export archive, rejecting archives without a
+// manifest or with a format version this server doesn't understand.
+func readImportArchive(archive []byte) (*workspaceImportArchive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, httputil.Validation("archive", "archive is not a valid ZIP file")
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var manifest models.WorkspaceExportManifest
+	found, err := decodeArchiveEntry(files, "manifest.json", &manifest)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, httputil.Validation("archive", "archive is missing manifest.json")
+	}
+	if manifest.FormatVersion != models.WorkspaceExportFormatVersion {
+		return nil, httputil.Validation("archive", fmt.Sprintf("unsupported export format version %d", manifest.FormatVersion))
+	}
+
+	data := &workspaceImportArchive{}
+	if _, err := decodeArchiveEntry(files, "links.json", &data.Links); err != nil {
+		return nil, err
+	}
+	if _, err := decodeArchiveEntry(files, "bio_pages.json", &data.BioPages); err != nil {
+		return nil, err
+	}
+	if _, err := decodeArchiveEntry(files, "webhooks.json", &data.Webhooks); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decodeArchiveEntry decodes the named JSON entry into v. It reports false if
+// the archive has no such entry.
+func decodeArchiveEntry(files map[string]*zip.File, name string, v any) (bool, error) {
+	f, ok := files[name]
+	if !ok {
+		return false, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return false, httputil.Validation("archive", "failed to read "+name)
+	}
+	defer rc.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(rc, workspaceImportMaxEntrySize+1))
+	if err != nil {
+		return false, httputil.Validation("archive", "failed to read "+name)
+	}
+	if len(raw) > workspaceImportMaxEntrySize {
+		return false, httputil.Validation("archive", name+" is too large")
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, httputil.Validation("archive", name+" is not valid JSON")
+	}
+	return true, nil
+}
+
+// workspaceImporter applies one archive to a workspace through
+// transaction-scoped repositories.
+type workspaceImporter struct {
+	workspaceID uuid.UUID
+	actorID     uuid.UUID
+	opts        models.WorkspaceImportOptions
+	linkRepo    repository.LinkRepository
+	flagRepo    repository.LinkFlagRepository
+	// screener runs imported destinations through the checks a new link
+	// gets. Its link repository is the transaction's, so loops between
+	// links of the same archive are caught.
+	screener    *linkService
+	bioPageRepo repository.BioPageRepository
+	webhookRepo repository.WebhookRepository
+	licManager  *license.Manager
+	hostPolicy  *httputil.HostPolicy
+	codeGen     shortcode.Generator
+	cfg         config.LinksConfig
+	result      *models.WorkspaceImportResult
+}
+
+func (imp *workspaceImporter) warn(typ, key, message string) {
+	imp.result.Warnings = append(imp.result.Warnings, models.ImportWarning{Type: typ, Key: key, Message: message})
+}
+
+// resolveKey checks whether key is free. A taken key is recorded as a
+// conflict and either skipped or replaced by the first free suffixed variant,
+// depending on the import options. It returns the key to use, or "" to skip.
+func (imp *workspaceImporter) resolveKey(typ, key string, maxLen int, taken func(string) (bool, error)) (string, error) {
+	exists, err := taken(key)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return key, nil
+	}
+
+	conflict := models.ImportConflict{Type: typ, Key: key, Resolution: "skipped"}
+	if imp.opts.OnConflict == models.ImportConflictSuffix {
+		for n := 2; n < importSuffixMaxAttempts+2; n++ {
+			candidate := suffixedKey(key, n, maxLen)
+			exists, err := taken(candidate)
+			if err != nil {
+				return "", err
+			}
+			if !exists {
+				conflict.Resolution = "suffixed"
+				conflict.NewKey = candidate
+				imp.result.Conflicts = append(imp.result.Conflicts, conflict)
+				return candidate, nil
+			}
+		}
+	}
+
+	imp.result.Conflicts = append(imp.result.Conflicts, conflict)
+	return "", nil
+}
+
+// suffixedKey appends "-n" to key, trimming key so the result fits in maxLen.
+func suffixedKey(key string, n, maxLen int) string {
+	suffix := "-" + strconv.Itoa(n)
+	if len(key)+len(suffix) > maxLen {
+		key = key[:maxLen-len(suffix)]
+	}
+	return key + suffix
+}
+
+func (imp *workspaceImporter) importLinks(ctx context.Context, links []*models.Link) error {
+	canCloak := imp.licManager.HasFeature(license.FeatureLinkCloaking)
+	canPixel := imp.licManager.HasFeature(license.FeatureRetargetingPixels)
+
+	for _, link := range links {
+		if link == nil {
+			continue
+		}
+
+		normalizedURL, err := normalizeURL(link.URL)
+		if err != nil {
+			imp.warn("link", link.ShortCode, "invalid destination URL, link was not imported")
+			imp.result.Links.Skipped++
+			continue
+		}
+		verdict, err := imp.screener.screenDestination(ctx, normalizedURL)
+		if err != nil {
+			reason, ok := rejectionReason(err)
+			if !ok {
+				return err
+			}
+			imp.warn("link", link.ShortCode, reason+", link was not imported")
+			imp.result.Links.Skipped++
+			continue
+		}
+		flaggedURL := normalizedURL
+
+		code, err := imp.linkShortCode(ctx, link.ShortCode)
+		if err != nil {
+			return err
+		}
+		if code == "" {
+			imp.result.Links.Skipped++
+			continue
+		}
+		if err := imp.screener.checkRedirectLoop(ctx, code, normalizedURL); err != nil {
+			reason, ok := rejectionReason(err)
+			if !ok {
+				return err
+			}
+			imp.warn("link", link.ShortCode, reason+", link was not imported")
+			imp.result.Links.Skipped++
+			continue
+		}
+		if code != link.ShortCode {
+			imp.result.ShortCodes[link.ShortCode] = code
+		}
+
+		isActive := link.IsActive
+		if link.HasPassword {
+			// Password hashes are never exported.
+			isActive = false
+			imp.warn("link", link.ShortCode, "password protection is not included in exports, link was imported inactive")
+		}
+		cloak := link.Cloak
+		if cloak && !canCloak {
+			cloak = false
+			imp.warn("link", link.ShortCode, "link cloaking is not available on this plan and was turned off")
+		}
+		if link.DomainID != nil {
+			imp.warn("link", link.ShortCode, "custom domains are not imported, link uses the default domain")
+		}
+		precedence := link.ParamPrecedence
+		if !models.IsValidParamPrecedence(precedence) {
+			precedence = models.ParamPrecedenceDestination
+		}
+		deepLinks, err := resolveDeepLinks(link.IOSURL, link.AndroidURL, link.FallbackURL)
+		if err != nil {
+			deepLinks = deepLinkURLs{}
+			imp.warn("link", link.ShortCode, "app URIs or fallback URL are invalid and were not imported")
+		}
+		pixels, err := resolvePixels(link.FacebookPixelID, link.GoogleTagID)
+		if err != nil {
+			pixels = retargetingPixels{}
+			imp.warn("link", link.ShortCode, "retargeting pixel IDs are invalid and were not imported")
+		} else if pixels.enabled() && !canPixel {
+			pixels = retargetingPixels{}
+			imp.warn("link", link.ShortCode, "retargeting pixels are not available on this plan and were not imported")
+		}
+		schedule, err := resolveSchedule(link.Schedule)
+		if err != nil {
+			schedule = nil
+			imp.warn("link", link.ShortCode, "schedule is invalid and was not imported, link is always live")
+		}
+		repeatVisitURL, err := resolveOptionalURL("repeat_visit_url", link.RepeatVisitURL)
+		if err != nil {
+			repeatVisitURL = pgtype.Text{}
+			imp.warn("link", link.ShortCode, "repeat visit URL is invalid and was not imported")
+		}
+		failoverURLs, err := resolveFailoverURLs(link.FailoverURLs)
+		if err != nil {
+			failoverURLs = nil
+			imp.warn("link", link.ShortCode, "failover URLs are invalid and were not imported")
+		}
+		failoverFlagged, failoverVerdict, err := imp.screener.screenFailoverURLs(ctx, code, failoverURLs)
+		if err != nil {
+			reason, ok := rejectionReason(err)
+			if !ok {
+				return err
+			}
+			failoverURLs = nil
+			imp.warn("link", link.ShortCode, "failover URLs were not imported: "+reason)
+		} else if verdict == nil && failoverVerdict != nil {
+			flaggedURL, verdict = failoverFlagged, failoverVerdict
+		}
+		if verdict != nil {
+			isActive = false
+			imp.warn("link", link.ShortCode, flaggedURL+" is reported as malware or phishing, link was imported inactive and held for review")
+		}
+
+		created, err := imp.linkRepo.Create(ctx, sqlc.CreateLinkParams{
+			UserID:          imp.actorID,
+			WorkspaceID:     imp.workspaceID,
+			Url:             normalizedURL,
+			ShortCode:       code,
+			Title:           models.OptionalText(link.Title),
+			Description:     models.OptionalText(link.Description),
+			IsActive:        isActive,
+			ExpiresAt:       models.OptionalTimestamptz(link.ExpiresAt),
+			MaxClicks:       models.OptionalInt4(link.MaxClicks),
+			UtmSource:       models.OptionalText(link.UTMSource),
+			UtmMedium:       models.OptionalText(link.UTMMedium),
+			UtmCampaign:     models.OptionalText(link.UTMCampaign),
+			UtmTerm:         models.OptionalText(link.UTMTerm),
+			UtmContent:      models.OptionalText(link.UTMContent),
+			Cloak:           cloak,
+			ForwardParams:   link.ForwardParams,
+			ParamPrecedence: precedence,
+			InternalNote:    models.OptionalText(link.InternalNote),
+			ForceHttps:      link.ForceHTTPS,
+			OncePerVisitor:  link.OncePerVisitor,
+			RepeatVisitUrl:  repeatVisitURL,
+			IosUrl:          deepLinks.ios,
+			AndroidUrl:      deepLinks.android,
+			FallbackUrl:     deepLinks.fallback,
+			FacebookPixelID: pixels.facebook,
+			GoogleTagID:     pixels.google,
+			Interstitial:    link.Interstitial,
+			Schedule:        schedule,
+			FailoverUrls:    failoverURLs,
+		})
+		if err != nil {
+			return err
+		}
+		if verdict != nil {
+			if err := imp.screener.flagLink(ctx, imp.flagRepo, created, flaggedURL, verdict); err != nil {
+				return err
+			}
+		}
+
+		if link.IsArchived() {
+			if _, err := imp.linkRepo.Archive(ctx, created.ID, false); err != nil {
+				return err
+			}
+		}
+
+		if link.FaviconURL != nil || link.OgImageURL != nil {
+			if _, err := imp.linkRepo.UpdateMetadata(ctx, sqlc.UpdateLinkMetadataParams{
+				ID:         created.ID,
+				FaviconUrl: models.OptionalText(link.FaviconURL),
+				OgImageUrl: models.OptionalText(link.OgImageURL),
+			}); err != nil {
+				return err
+			}
+		}
+
+		imp.result.Links.Created++
+	}
+	return nil
+}
+
+// rejectionReason returns the message of a validation error from screening a
+// URL. It reports false for other errors, which fail the import.
+func rejectionReason(err error) (string, bool) {
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || !errors.Is(err, httputil.ErrValidation) {
+		return "", false
+	}
+	return appErr.Message, true
+}
+
+// linkShortCode picks the short code for an imported link, or "" to skip it.
+func (imp *workspaceImporter) linkShortCode(ctx context.Context, code string) (string, error) {
+	if imp.opts.ShortCodes == models.ImportShortCodesRegenerate {
+		return findUniqueShortCode(ctx, imp.linkRepo, imp.codeGen, imp.cfg)
+	}
+
+	if !isValidShortCode(code) {
+		imp.warn("link", code, "short code is not valid, a new one was generated")
+		return findUniqueShortCode(ctx, imp.linkRepo, imp.codeGen, imp.cfg)
+	}
+
+	return imp.resolveKey("link", imp.cfg.NormalizeShortCode(code), maxShortCodeLength, func(c string) (bool, error) {
+		return shortCodeExists(ctx, imp.linkRepo, imp.cfg, c)
+	})
+}
+
+func (imp *workspaceImporter) importBioPages(ctx context.Context, pages []*models.BioPage) error {
+	canCreate := imp.licManager.HasFeature(license.FeatureBioPages)
+	canCustomCSS := imp.licManager.HasFeature(license.FeatureCustomCSS)
+
+	for _, page := range pages {
+		if page == nil {
+			continue
+		}
+		if !canCreate {
+			imp.warn("bio_page", page.Slug, "bio pages are not available on this plan, page was not imported")
+			imp.result.BioPages.Skipped++
+			continue
+		}
+		if !isValidSlug(page.Slug) {
+			imp.warn("bio_page", page.Slug, "slug is not valid, page was not imported")
+			imp.result.BioPages.Skipped++
+			continue
+		}
+
+		slug, err := imp.resolveKey("bio_page", page.Slug, maxBioSlugLength, func(s string) (bool, error) {
+			_, err := imp.bioPageRepo.GetBySlug(ctx, s)
+			if errors.Is(err, httputil.ErrNotFound) {
+				return false, nil
+			}
+			return err == nil, err
+		})
+		if err != nil {
+			return err
+		}
+		if slug == "" {
+			imp.result.BioPages.Skipped++
+			continue
+		}
+
+		params := sqlc.CreateBioPageParams{
+			WorkspaceID:     imp.workspaceID,
+			Slug:            slug,
+			Title:           page.Title,
+			Bio:             models.OptionalText(page.Bio),
+			AvatarUrl:       models.OptionalText(page.AvatarURL),
+			MetaTitle:       models.OptionalText(page.MetaTitle),
+			MetaDescription: models.OptionalText(page.MetaDescription),
+			OgImageUrl:      models.OptionalText(page.OgImageURL),
+		}
+		if page.ThemeID != nil {
+			params.ThemeID = pgtype.UUID{Bytes: *page.ThemeID, Valid: true}
+		}
+		if page.CustomCSS != nil {
+			if !canCustomCSS {
+				imp.warn("bio_page", page.Slug, "custom CSS is not available on this plan and was dropped")
+			} else if sanitized, dropped, err := sanitizeCSS(*page.CustomCSS); err != nil {
+				imp.warn("bio_page", page.Slug, "custom CSS was rejected: "+err.Error())
+			} else {
+				if dropped > 0 {
+					imp.warn("bio_page", page.Slug, fmt.Sprintf("%d unsupported custom CSS rules or declarations were removed", dropped))
+				}
+				params.CustomCss = pgtype.Text{String: sanitized, Valid: true}
+			}
+		}
+
+		created, err := imp.bioPageRepo.Create(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		for _, l := range page.Links {
+			if l == nil {
+				continue
+			}
+			// Older archives have no link type; social links whose URL no
+			// longer matches a platform are kept as regular links.
+			linkType := l.LinkType
+			if !models.IsValidBioLinkType(linkType) ||
+				(linkType == models.BioLinkTypeSocial && models.DetectSocialPlatform(l.URL) == "") {
+				linkType = models.BioLinkTypeRegular
+			}
+			if _, err := imp.bioPageRepo.CreateLink(ctx, sqlc.CreateBioPageLinkParams{
+				BioPageID:    created.ID,
+				Title:        l.Title,
+				Url:          l.URL,
+				Icon:         models.OptionalText(l.Icon),
+				Position:     l.Position,
+				IsVisible:    l.IsVisible,
+				VisibleFrom:  models.OptionalTimestamptz(l.VisibleFrom),
+				VisibleUntil: models.OptionalTimestamptz(l.VisibleUntil),
+				LinkType:     linkType,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if page.IsPublished {
+			if _, err := imp.bioPageRepo.Update(ctx, sqlc.UpdateBioPageParams{
+				ID:          created.ID,
+				IsPublished: pgtype.Bool{Bool: true, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+
+		imp.result.BioPages.Created++
+	}
+	return nil
+}
+
+func (imp *workspaceImporter) importWebhooks(ctx context.Context, webhooks []*models.Webhook) error {
+	canCreate := imp.licManager.HasFeature(license.FeatureWebhooks)
+
+	for _, webhook := range webhooks {
+		if webhook == nil {
+			continue
+		}
+		if !canCreate {
+			imp.warn("webhook", webhook.URL, "webhooks are not available on this plan, webhook was not imported")
+			imp.result.Webhooks.Skipped++
+			continue
+		}
+
+		if err := checkWebhookURL(ctx, imp.hostPolicy, webhook.URL); err != nil {
+			var appErr *httputil.AppError
+			if !errors.As(err, &appErr) {
+				return err
+			}
+			imp.warn("webhook", webhook.URL, appErr.Message+", webhook was not imported")
+			imp.result.Webhooks.Skipped++
+			continue
+		}
+
+		events := make([]string, 0, len(webhook.Events))
+		for _, event := range webhook.Events {
+			if models.IsValidWebhookEvent(event) {
+				events = append(events, event)
+			} else {
+				imp.warn("webhook", webhook.URL, "unknown event "+event+" was dropped")
+			}
+		}
+		if len(events) == 0 {
+			imp.warn("webhook", webhook.URL, "webhook has no valid events, webhook was not imported")
+			imp.result.Webhooks.Skipped++
+			continue
+		}
+
+		var payloadVersion pgtype.Int4
+		if webhook.PayloadVersion != nil && models.IsValidWebhookPayloadVersion(*webhook.PayloadVersion) {
+			payloadVersion = pgtype.Int4{Int32: *webhook.PayloadVersion, Valid: true}
+		}
+
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return err
+		}
+
+		created, err := imp.webhookRepo.Create(ctx, sqlc.CreateWebhookParams{
+			WorkspaceID:    imp.workspaceID,
+			Url:            webhook.URL,
+			Secret:         secret,
+			Events:         events,
+			IsActive:       webhook.IsActive,
+			PayloadVersion: payloadVersion,
+		})
+		if err != nil {
+			return err
+		}
+
+		imp.result.WebhookSecrets[created.ID] = secret
+		imp.result.Webhooks.Created++
+	}
+	return nil
+}
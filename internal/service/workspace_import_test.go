@@ -0,0 +1,286 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/config"
+	"github.com/link-rift/link-rift/internal/license"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/safety"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+func TestReadImportArchive_RoundTrip(t *testing.T) {
+	link := makeLink(uuid.New(), uuid.New(), uuid.New(), "abc123")
+	var buf bytes.Buffer
+	err := writeExportArchive(&buf, &workspaceExportData{
+		Workspace: &models.Workspace{ID: link.WorkspaceID},
+		Links:     []*models.Link{link},
+		BioPages: []*models.BioPage{{
+			Slug:  "acme",
+			Links: []*models.BioPageLink{{Title: "Docs", URL: "https://example.com/docs"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := readImportArchive(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data.Links) != 1 || data.Links[0].ShortCode != "abc123" {
+		t.Errorf("links = %+v", data.Links)
+	}
+	if len(data.BioPages) != 1 || len(data.BioPages[0].Links) != 1 {
+		t.Errorf("bio pages = %+v", data.BioPages)
+	}
+	if len(data.Webhooks) != 0 {
+		t.Errorf("webhooks = %+v", data.Webhooks)
+	}
+}
+
+func TestReadImportArchive_Invalid(t *testing.T) {
+	zipWith := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range files {
+			fw, _ := zw.Create(name)
+			fw.Write([]byte(content))
+		}
+		zw.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		archive []byte
+	}{
+		{"not a zip", []byte("hello")},
+		{"missing manifest", zipWith(map[string]string{"links.json": "[]"})},
+		{"unsupported version", zipWith(map[string]string{"manifest.json": `{"format_version": 99}`})},
+		{"invalid json", zipWith(map[string]string{"manifest.json": `{"format_version": 1}`, "links.json": "{"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readImportArchive(tt.archive)
+			var appErr *httputil.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSuffixedKey(t *testing.T) {
+	if got := suffixedKey("promo", 2, 50); got != "promo-2" {
+		t.Errorf("suffixedKey = %q", got)
+	}
+	if got := suffixedKey("abcdef", 12, 6); got != "abc-12" {
+		t.Errorf("suffixedKey = %q, want it trimmed to fit", got)
+	}
+}
+
+func TestResolveKey(t *testing.T) {
+	taken := map[string]bool{"promo": true, "promo-2": true}
+	exists := func(k string) (bool, error) { return taken[k], nil }
+
+	skip := &workspaceImporter{
+		opts:   models.WorkspaceImportOptions{OnConflict: models.ImportConflictSkip},
+		result: &models.WorkspaceImportResult{},
+	}
+	if key, _ := skip.resolveKey("link", "fresh", 50, exists); key != "fresh" {
+		t.Errorf("free key resolved to %q", key)
+	}
+	if key, _ := skip.resolveKey("link", "promo", 50, exists); key != "" {
+		t.Errorf("taken key resolved to %q, want skipped", key)
+	}
+	if len(skip.result.Conflicts) != 1 || skip.result.Conflicts[0].Resolution != "skipped" {
+		t.Errorf("conflicts = %+v", skip.result.Conflicts)
+	}
+
+	suffix := &workspaceImporter{
+		opts:   models.WorkspaceImportOptions{OnConflict: models.ImportConflictSuffix},
+		result: &models.WorkspaceImportResult{},
+	}
+	if key, _ := suffix.resolveKey("link", "promo", 50, exists); key != "promo-3" {
+		t.Errorf("taken key resolved to %q, want promo-3", key)
+	}
+	if c := suffix.result.Conflicts; len(c) != 1 || c[0].NewKey != "promo-3" {
+		t.Errorf("conflicts = %+v", c)
+	}
+}
+
+func TestNormalizeImportOptions(t *testing.T) {
+	opts, err := normalizeImportOptions(models.WorkspaceImportOptions{})
+	if err != nil || opts.ShortCodes != models.ImportShortCodesPreserve || opts.OnConflict != models.ImportConflictSkip {
+		t.Errorf("defaults = %+v, %v", opts, err)
+	}
+	if _, err := normalizeImportOptions(models.WorkspaceImportOptions{OnConflict: "overwrite"}); err == nil {
+		t.Error("expected error for unknown conflict mode")
+	}
+}
+
+// newTestImporter returns an importer creating links through linkRepo, with
+// destination screening off.
+func newTestImporter(linkRepo *mockLinkRepo) *workspaceImporter {
+	return &workspaceImporter{
+		workspaceID: uuid.New(),
+		actorID:     uuid.New(),
+		opts:        models.WorkspaceImportOptions{ShortCodes: models.ImportShortCodesPreserve, OnConflict: models.ImportConflictSkip},
+		linkRepo:    linkRepo,
+		flagRepo:    newMockLinkFlagRepo(),
+		screener: &linkService{
+			linkRepo: linkRepo,
+			cfg:      &config.Config{App: config.AppConfig{RedirectURL: "http://localhost:8081"}},
+			logger:   zap.NewNop(),
+		},
+		licManager: newTestLicenseManager(license.TierFree),
+		result: &models.WorkspaceImportResult{
+			Warnings:   []models.ImportWarning{},
+			ShortCodes: map[string]string{},
+		},
+	}
+}
+
+func TestImportLinks_InvalidRepeatVisitURL(t *testing.T) {
+	var created []sqlc.CreateLinkParams
+	imp := newTestImporter(&mockLinkRepo{
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			created = append(created, params)
+			return &models.Link{ID: uuid.New()}, nil
+		},
+	})
+
+	bad := "javascript:alert(1)"
+	good := "https://example.com/welcome-back"
+	links := []*models.Link{
+		{ShortCode: "bad123", URL: "https://example.com", IsActive: true, OncePerVisitor: true, RepeatVisitURL: &bad},
+		{ShortCode: "good123", URL: "https://example.com", IsActive: true, OncePerVisitor: true, RepeatVisitURL: &good},
+	}
+	if err := imp.importLinks(context.Background(), links); err != nil {
+		t.Fatalf("importLinks() error = %v", err)
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("created %d links, want 2", len(created))
+	}
+	if created[0].RepeatVisitUrl.Valid {
+		t.Errorf("invalid repeat visit URL was imported as %q", created[0].RepeatVisitUrl.String)
+	}
+	if !created[1].RepeatVisitUrl.Valid || created[1].RepeatVisitUrl.String != good {
+		t.Errorf("repeat visit URL = %+v, want %q", created[1].RepeatVisitUrl, good)
+	}
+	if len(imp.result.Warnings) != 1 || imp.result.Warnings[0].Key != "bad123" {
+		t.Errorf("warnings = %+v, want one for bad123", imp.result.Warnings)
+	}
+}
+
+// createdLinks returns a link repository that records created links and
+// serves them by short code, like the import transaction would.
+func createdLinks() (*mockLinkRepo, map[string]*models.Link) {
+	byCode := map[string]*models.Link{}
+	repo := &mockLinkRepo{
+		createFn: func(_ context.Context, params sqlc.CreateLinkParams) (*models.Link, error) {
+			link := &models.Link{
+				ID:           uuid.New(),
+				WorkspaceID:  params.WorkspaceID,
+				ShortCode:    params.ShortCode,
+				URL:          params.Url,
+				IsActive:     params.IsActive,
+				FailoverURLs: params.FailoverUrls,
+			}
+			byCode[link.ShortCode] = link
+			return link, nil
+		},
+		getByShortCodeFn: func(_ context.Context, code string) (*models.Link, error) {
+			if link, ok := byCode[code]; ok {
+				return link, nil
+			}
+			return nil, httputil.NotFound("link")
+		},
+	}
+	return repo, byCode
+}
+
+func TestImportLinks_BlockedDestination(t *testing.T) {
+	repo, created := createdLinks()
+	imp := newTestImporter(repo)
+	imp.screener.urlChecker = &mockURLChecker{verdict: &safety.Verdict{Source: safety.SourceBlocklist, Reason: "listed"}}
+	imp.screener.cfg.Safety.Mode = config.SafetyModeBlock
+
+	links := []*models.Link{{ShortCode: "evil123", URL: "https://evil.example", IsActive: true}}
+	if err := imp.importLinks(context.Background(), links); err != nil {
+		t.Fatalf("importLinks() error = %v", err)
+	}
+
+	if len(created) != 0 {
+		t.Errorf("blocked link was imported: %+v", created)
+	}
+	if imp.result.Links.Skipped != 1 || len(imp.result.Warnings) != 1 {
+		t.Errorf("links = %+v, warnings = %+v; want one skipped with a warning", imp.result.Links, imp.result.Warnings)
+	}
+}
+
+func TestImportLinks_FlaggedDestination(t *testing.T) {
+	repo, created := createdLinks()
+	imp := newTestImporter(repo)
+	flags := imp.flagRepo.(*mockLinkFlagRepo)
+	imp.screener.urlChecker = &mockURLChecker{verdict: &safety.Verdict{Source: safety.SourceBlocklist, Reason: "listed"}}
+	imp.screener.cfg.Safety.Mode = config.SafetyModeFlag
+
+	links := []*models.Link{{ShortCode: "evil123", URL: "https://evil.example", IsActive: true}}
+	if err := imp.importLinks(context.Background(), links); err != nil {
+		t.Fatalf("importLinks() error = %v", err)
+	}
+
+	link := created["evil123"]
+	if link == nil || link.IsActive {
+		t.Fatalf("expected the link to be imported inactive, got %+v", link)
+	}
+	if flag := flags.flags[link.ID]; flag == nil || flag.URL != "https://evil.example" {
+		t.Errorf("flag = %+v, want one for the destination", flag)
+	}
+	if len(imp.result.Warnings) != 1 {
+		t.Errorf("warnings = %+v, want one", imp.result.Warnings)
+	}
+}
+
+func TestImportLinks_RejectsLoops(t *testing.T) {
+	repo, created := createdLinks()
+	imp := newTestImporter(repo)
+
+	links := []*models.Link{
+		{ShortCode: "self123", URL: "http://localhost:8081/self123", IsActive: true},
+		{ShortCode: "first1", URL: "http://localhost:8081/second", IsActive: true},
+		{ShortCode: "second", URL: "http://localhost:8081/first1", IsActive: true},
+		{ShortCode: "backup", URL: "https://example.com", IsActive: true, FailoverURLs: []string{"http://localhost:8081/backup"}},
+	}
+	if err := imp.importLinks(context.Background(), links); err != nil {
+		t.Fatalf("importLinks() error = %v", err)
+	}
+
+	if _, ok := created["self123"]; ok {
+		t.Error("link redirecting to itself was imported")
+	}
+	if _, ok := created["first1"]; !ok {
+		t.Error("expected first1 to be imported")
+	}
+	if _, ok := created["second"]; ok {
+		t.Error("link closing a loop with an imported link was imported")
+	}
+	if link := created["backup"]; link == nil || len(link.FailoverURLs) != 0 {
+		t.Errorf("expected backup to be imported without its looping failover URL, got %+v", link)
+	}
+	if imp.result.Links.Skipped != 2 || len(imp.result.Warnings) != 3 {
+		t.Errorf("links = %+v, warnings = %+v", imp.result.Links, imp.result.Warnings)
+	}
+}
@@ -3,19 +3,35 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/link-rift/link-rift/internal/config"
 	"github.com/link-rift/link-rift/internal/license"
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/internal/safety"
 	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/shortcode"
+	"github.com/link-rift/link-rift/pkg/storage"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// clickSampleRateKeyPrefix keys the redirect service's cache of each
+// workspace's click sample rate, cleared when the setting changes.
+const clickSampleRateKeyPrefix = "workspace:click_sample_rate:"
+
+// interstitialKeyPrefix keys the redirect service's cache of each
+// workspace's name, interstitial settings and branding, cleared when any of
+// them changes.
+const interstitialKeyPrefix = "workspace:interstitial:"
+
 type WorkspaceService interface {
 	CreateWorkspace(ctx context.Context, userID uuid.UUID, input models.CreateWorkspaceInput) (*models.Workspace, error)
 	GetWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
@@ -30,35 +46,63 @@ type WorkspaceService interface {
 	ListMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMemberResponse, error)
 	GetMember(ctx context.Context, workspaceID, userID uuid.UUID) (*models.WorkspaceMember, error)
 	GetMemberCount(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+
+	ExportWorkspace(ctx context.Context, workspaceID, actorID uuid.UUID) (*models.WorkspaceExport, error)
+	GetExport(ctx context.Context, workspaceID, exportID, actorID uuid.UUID) (*models.WorkspaceExport, error)
+	DownloadExport(ctx context.Context, workspaceID, exportID, actorID uuid.UUID) ([]byte, error)
+	ImportWorkspace(ctx context.Context, workspaceID, actorID uuid.UUID, archive []byte, opts models.WorkspaceImportOptions) (*models.WorkspaceImportResult, error)
 }
 
 type workspaceService struct {
-	wsRepo     repository.WorkspaceRepository
-	memberRepo repository.WorkspaceMemberRepository
-	userRepo   repository.UserRepository
-	licManager *license.Manager
-	events     EventPublisher
-	pool       *pgxpool.Pool
-	logger     *zap.Logger
+	wsRepo            repository.WorkspaceRepository
+	memberRepo        repository.WorkspaceMemberRepository
+	userRepo          repository.UserRepository
+	linkRepo          repository.LinkRepository
+	domainRepo        repository.DomainRepository
+	urlChecker        safety.Checker
+	licManager        *license.Manager
+	events            EventPublisher
+	pool              *pgxpool.Pool
+	redis             *redis.Client
+	store             storage.ObjectStorage
+	webhookHostPolicy *httputil.HostPolicy
+	codeGen           shortcode.Generator
+	cfg               *config.Config
+	logger            *zap.Logger
 }
 
 func NewWorkspaceService(
 	wsRepo repository.WorkspaceRepository,
 	memberRepo repository.WorkspaceMemberRepository,
 	userRepo repository.UserRepository,
+	linkRepo repository.LinkRepository,
+	domainRepo repository.DomainRepository,
+	urlChecker safety.Checker,
 	licManager *license.Manager,
 	events EventPublisher,
 	pool *pgxpool.Pool,
+	redisClient *redis.Client,
+	store storage.ObjectStorage,
+	webhookHostPolicy *httputil.HostPolicy,
+	cfg *config.Config,
 	logger *zap.Logger,
 ) WorkspaceService {
 	return &workspaceService{
-		wsRepo:     wsRepo,
-		memberRepo: memberRepo,
-		userRepo:   userRepo,
-		licManager: licManager,
-		events:     events,
-		pool:       pool,
-		logger:     logger,
+		wsRepo:            wsRepo,
+		memberRepo:        memberRepo,
+		userRepo:          userRepo,
+		linkRepo:          linkRepo,
+		domainRepo:        domainRepo,
+		urlChecker:        urlChecker,
+		licManager:        licManager,
+		events:            events,
+		pool:              pool,
+		redis:             redisClient,
+		store:             store,
+		webhookHostPolicy: webhookHostPolicy,
+		codeGen:           shortcode.NewGenerator(),
+		cfg:               cfg,
+		logger:            logger,
 	}
 }
 
@@ -72,7 +116,13 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, userID uuid.UUID
 		return nil, httputil.PaymentRequired("workspace limit reached, upgrade your plan")
 	}
 
-	slug := strings.ToLower(strings.TrimSpace(input.Slug))
+	slug, err := normalizeWorkspaceSlug(input.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSlugAvailable(ctx, slug, uuid.Nil); err != nil {
+		return nil, err
+	}
 
 	// Use a transaction: create workspace + add owner as member
 	tx, err := s.pool.Begin(ctx)
@@ -93,7 +143,7 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, userID uuid.UUID
 		Settings: json.RawMessage(`{}`),
 	})
 	if err != nil {
-		return nil, err
+		return nil, s.mapWorkspaceSlugError(ctx, err, slug)
 	}
 
 	_, err = txMemberRepo.Add(ctx, sqlc.AddWorkspaceMemberParams{
@@ -131,11 +181,174 @@ func (s *workspaceService) UpdateWorkspace(ctx context.Context, id uuid.UUID, in
 		params.Name = pgtype.Text{String: name, Valid: true}
 	}
 	if input.Slug != nil {
-		slug := strings.ToLower(strings.TrimSpace(*input.Slug))
+		slug, err := normalizeWorkspaceSlug(*input.Slug)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkSlugAvailable(ctx, slug, id); err != nil {
+			return nil, err
+		}
 		params.Slug = pgtype.Text{String: slug, Valid: true}
 	}
 
-	return s.wsRepo.Update(ctx, params)
+	settings := map[string]any{}
+	if input.SecurityPolicy != nil {
+		if !s.licManager.HasFeature(license.FeatureSessionPolicies) {
+			return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureSessionPolicies), "enterprise")
+		}
+		if field, err := input.SecurityPolicy.Validate(); err != nil {
+			return nil, httputil.Validation("security_policy."+field, "must be a positive duration such as 15m or 8h")
+		}
+		settings["security_policy"] = input.SecurityPolicy
+		if input.SecurityPolicy.IsEmpty() {
+			settings["security_policy"] = nil
+		}
+	}
+	if input.QRDefaults != nil {
+		level := strings.ToUpper(input.QRDefaults.ErrorCorrection)
+		if level != "" && !models.IsValidErrorCorrection(level) {
+			return nil, httputil.Validation("qr_defaults.error_correction", "must be one of L, M, Q or H")
+		}
+		settings["qr_defaults"] = &models.WorkspaceQRDefaults{ErrorCorrection: level}
+		if level == "" {
+			settings["qr_defaults"] = nil
+		}
+	}
+	if input.ClickSampling != nil {
+		rate := input.ClickSampling.Rate
+		if rate < 0 || rate > models.MaxClickSampleRate {
+			return nil, httputil.Validation("click_sampling.rate", fmt.Sprintf("must be between 1 and %d", models.MaxClickSampleRate))
+		}
+		settings["click_sampling"] = &models.WorkspaceClickSampling{Rate: rate}
+		if rate <= 1 {
+			settings["click_sampling"] = nil
+		}
+	}
+	if input.Interstitial != nil {
+		in := input.Interstitial
+		in.Title = strings.TrimSpace(in.Title)
+		in.Message = strings.TrimSpace(in.Message)
+		if len(in.Title) > 100 {
+			return nil, httputil.Validation("interstitial.title", "must be at most 100 characters")
+		}
+		if len(in.Message) > 1000 {
+			return nil, httputil.Validation("interstitial.message", "must be at most 1000 characters")
+		}
+		if in.DelaySeconds != nil && (*in.DelaySeconds < 0 || *in.DelaySeconds > models.MaxInterstitialDelaySeconds) {
+			return nil, httputil.Validation("interstitial.delay_seconds", fmt.Sprintf("must be between 0 and %d", models.MaxInterstitialDelaySeconds))
+		}
+		settings["interstitial"] = in
+		if in.IsEmpty() {
+			settings["interstitial"] = nil
+		}
+	}
+	if input.Branding != nil {
+		b := input.Branding
+		b.LogoURL = strings.TrimSpace(b.LogoURL)
+		b.AccentColor = strings.ToLower(strings.TrimSpace(b.AccentColor))
+		// Branding can be removed without the feature, e.g. after a downgrade.
+		if !b.IsEmpty() && !s.licManager.HasFeature(license.FeatureWhiteLabel) {
+			return nil, httputil.PaymentRequiredWithDetails(string(license.FeatureWhiteLabel), "enterprise")
+		}
+		if field, err := b.Validate(); err != nil {
+			return nil, httputil.Validation("branding."+field, err.Error())
+		}
+		settings["branding"] = b
+		if b.IsEmpty() {
+			settings["branding"] = nil
+		}
+	}
+	if input.ShortCodeLength != nil {
+		if field, err := input.ShortCodeLength.Validate(); err != nil {
+			return nil, httputil.Validation("short_code_length."+field, err.Error())
+		}
+		settings["short_code_length"] = input.ShortCodeLength
+		if input.ShortCodeLength.IsEmpty() {
+			settings["short_code_length"] = nil
+		}
+	}
+	if input.AnalyticsBackend != nil {
+		backend := strings.ToLower(strings.TrimSpace(*input.AnalyticsBackend))
+		if backend != "" && !models.IsValidAnalyticsBackend(backend) {
+			return nil, httputil.Validation("analytics_backend", "must be postgres or clickhouse")
+		}
+		settings["analytics_backend"] = backend
+		if backend == "" {
+			settings["analytics_backend"] = nil
+		}
+	}
+	if input.Timezone != nil {
+		tz := strings.TrimSpace(*input.Timezone)
+		if tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+				return nil, httputil.Validation("timezone", "must be an IANA time zone such as Asia/Tokyo")
+			}
+		}
+		settings["timezone"] = tz
+		if tz == "" || tz == "UTC" {
+			settings["timezone"] = nil
+		}
+	}
+	if len(settings) > 0 {
+		merged, err := s.mergeSettings(ctx, id, settings)
+		if err != nil {
+			return nil, err
+		}
+		params.Settings = merged
+	}
+
+	ws, err := s.wsRepo.Update(ctx, params)
+	if err != nil {
+		if params.Slug.Valid {
+			return nil, s.mapWorkspaceSlugError(ctx, err, params.Slug.String)
+		}
+		return nil, err
+	}
+	if input.ClickSampling != nil && s.redis != nil {
+		if err := s.redis.Del(ctx, clickSampleRateKeyPrefix+id.String()).Err(); err != nil {
+			s.logger.Warn("failed to clear cached click sample rate", zap.Error(err))
+		}
+	}
+	if (input.Interstitial != nil || input.Branding != nil || input.Name != nil) && s.redis != nil {
+		if err := s.redis.Del(ctx, interstitialKeyPrefix+id.String()).Err(); err != nil {
+			s.logger.Warn("failed to clear cached interstitial settings", zap.Error(err))
+		}
+	}
+	return ws, nil
+}
+
+// mergeSettings returns the workspace settings with updates applied. A nil
+// update removes the key; keys not in updates are preserved.
+func (s *workspaceService) mergeSettings(ctx context.Context, id uuid.UUID, updates map[string]any) ([]byte, error) {
+	ws, err := s.wsRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := map[string]json.RawMessage{}
+	if len(ws.Settings) > 0 {
+		if err := json.Unmarshal(ws.Settings, &settings); err != nil {
+			settings = map[string]json.RawMessage{}
+		}
+	}
+
+	for key, value := range updates {
+		if value == nil {
+			delete(settings, key)
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, httputil.Wrap(err, "failed to encode workspace settings")
+		}
+		settings[key] = raw
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, httputil.Wrap(err, "failed to encode workspace settings")
+	}
+	return data, nil
 }
 
 func (s *workspaceService) DeleteWorkspace(ctx context.Context, id uuid.UUID, actorID uuid.UUID) error {
@@ -148,7 +361,19 @@ func (s *workspaceService) DeleteWorkspace(ctx context.Context, id uuid.UUID, ac
 		return httputil.Forbidden("only the workspace owner can delete the workspace")
 	}
 
-	return s.wsRepo.SoftDelete(ctx, id)
+	if err := s.wsRepo.SoftDelete(ctx, id); err != nil {
+		return err
+	}
+
+	// The worker retires the workspace's links, domains, keys and files. If
+	// the job can't be queued, its periodic sweep picks the workspace up.
+	if err := s.redis.RPush(ctx, workspaceCleanupQueue, id.String()).Err(); err != nil {
+		s.logger.Warn("failed to queue workspace cleanup",
+			zap.String("workspace_id", id.String()),
+			zap.Error(err),
+		)
+	}
+	return nil
 }
 
 func (s *workspaceService) InviteMember(ctx context.Context, workspaceID, inviterID uuid.UUID, input models.InviteMemberInput) (*models.WorkspaceMember, error) {
@@ -162,6 +387,12 @@ func (s *workspaceService) InviteMember(ctx context.Context, workspaceID, invite
 		return nil, err
 	}
 	if !s.licManager.CheckLimit(license.LimitMaxUsers, memberCount) {
+		// A downgrade can leave a workspace with more members than allowed;
+		// they keep access, but nobody new can join until it's back under.
+		if limit := s.licManager.GetLimits().MaxUsers; memberCount > limit {
+			return nil, httputil.PaymentRequired(fmt.Sprintf(
+				"workspace has %d members but the current plan allows %d, remove members or upgrade your plan", memberCount, limit))
+		}
 		return nil, httputil.PaymentRequired("team member limit reached, upgrade your plan")
 	}
 
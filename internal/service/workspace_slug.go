@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+)
+
+// maxSlugSuggestions is how many free alternatives are offered for a
+// workspace slug that is taken; maxSlugCandidates bounds the lookups spent
+// finding them.
+const (
+	maxSlugSuggestions = 3
+	maxSlugCandidates  = 10
+)
+
+// normalizeWorkspaceSlug lowercases and trims a user-chosen slug and checks
+// its format.
+func normalizeWorkspaceSlug(raw string) (string, error) {
+	slug := strings.ToLower(strings.TrimSpace(raw))
+	if !models.IsValidWorkspaceSlug(slug) {
+		return "", httputil.Validation("slug", "must be lowercase letters, digits and single hyphens, and start and end with a letter or digit")
+	}
+	return slug, nil
+}
+
+// checkSlugAvailable rejects a slug used by a workspace other than self,
+// suggesting free alternatives. self is uuid.Nil for new workspaces.
+func (s *workspaceService) checkSlugAvailable(ctx context.Context, slug string, self uuid.UUID) error {
+	taken, err := s.slugTaken(ctx, slug, self)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return s.slugTakenError(ctx, slug)
+	}
+	return nil
+}
+
+// mapWorkspaceSlugError turns a unique violation on the slug, from a
+// workspace created between the check and the write, into the same error
+// checkSlugAvailable returns.
+func (s *workspaceService) mapWorkspaceSlugError(ctx context.Context, err error, slug string) error {
+	var appErr *httputil.AppError
+	if errors.As(err, &appErr) && appErr.Code == "ALREADY_EXISTS" {
+		return s.slugTakenError(ctx, slug)
+	}
+	return err
+}
+
+func (s *workspaceService) slugTakenError(ctx context.Context, slug string) error {
+	appErr := httputil.AlreadyExists("workspace slug")
+	appErr.Details = map[string]any{
+		"field":       "slug",
+		"suggestions": s.suggestSlugs(ctx, slug),
+	}
+	return appErr
+}
+
+func (s *workspaceService) slugTaken(ctx context.Context, slug string, self uuid.UUID) (bool, error) {
+	ws, err := s.wsRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		var appErr *httputil.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+			return false, nil
+		}
+		return false, err
+	}
+	return ws != nil && ws.ID != self, nil
+}
+
+// suggestSlugs returns up to maxSlugSuggestions free slugs based on slug:
+// numbered ones first, then ones with a random suffix. Lookup failures
+// only shorten the list.
+func (s *workspaceService) suggestSlugs(ctx context.Context, slug string) []string {
+	suggestions := make([]string, 0, maxSlugSuggestions)
+	for i := 0; i < maxSlugCandidates && len(suggestions) < maxSlugSuggestions; i++ {
+		var suffix string
+		if i < maxSlugCandidates/2 {
+			suffix = strconv.Itoa(i + 2)
+		} else {
+			b := make([]byte, 2)
+			if _, err := rand.Read(b); err != nil {
+				break
+			}
+			suffix = hex.EncodeToString(b)
+		}
+
+		candidate := withSlugSuffix(slug, suffix)
+		taken, err := s.slugTaken(ctx, candidate, uuid.Nil)
+		if err != nil {
+			break
+		}
+		if !taken {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// withSlugSuffix appends -suffix to slug, shortening slug to keep the result
+// within MaxWorkspaceSlugLength.
+func withSlugSuffix(slug, suffix string) string {
+	if max := models.MaxWorkspaceSlugLength - len(suffix) - 1; len(slug) > max {
+		slug = strings.TrimRight(slug[:max], "-")
+	}
+	return slug + "-" + suffix
+}
+
+// slugify turns a workspace name into slug form: runs of characters other
+// than ASCII letters and digits become single hyphens. Names with none of
+// those become "workspace".
+func slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+
+	slug := b.String()
+	if slug == "" {
+		return "workspace"
+	}
+	// Leave room for the suffix generateWorkspaceSlug adds
+	if len(slug) > models.MaxWorkspaceSlugLength-9 {
+		slug = strings.TrimRight(slug[:models.MaxWorkspaceSlugLength-9], "-")
+	}
+	return slug
+}
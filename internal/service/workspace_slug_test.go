@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type slugWorkspaceRepo struct {
+	repository.WorkspaceRepository
+	bySlug    map[string]*models.Workspace
+	updateErr error
+	updated   *sqlc.UpdateWorkspaceParams
+}
+
+func (m *slugWorkspaceRepo) GetBySlug(_ context.Context, slug string) (*models.Workspace, error) {
+	ws, ok := m.bySlug[slug]
+	if !ok {
+		return nil, httputil.NotFound("workspace")
+	}
+	return ws, nil
+}
+
+func (m *slugWorkspaceRepo) Update(_ context.Context, params sqlc.UpdateWorkspaceParams) (*models.Workspace, error) {
+	m.updated = &params
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	return &models.Workspace{ID: params.ID, Slug: params.Slug.String}, nil
+}
+
+func TestNormalizeWorkspaceSlug(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{" Acme-Marketing ", "acme-marketing", true},
+		{"team42", "team42", true},
+		{"acme--marketing", "", false},
+		{"-acme", "", false},
+		{"acme_marketing", "", false},
+		{"café", "", false},
+		{strings.Repeat("a", models.MaxWorkspaceSlugLength+1), "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeWorkspaceSlug(tt.in)
+		if (err == nil) != tt.wantOK || got != tt.want {
+			t.Errorf("normalizeWorkspaceSlug(%q) = %q, %v, want %q, ok=%v", tt.in, got, err, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Jane Doe":               "jane-doe",
+		"  O'Brien & Co.  ":      "o-brien-co",
+		"Zoë's Team":             "zo-s-team",
+		"日本":                     "workspace",
+		strings.Repeat("x", 200): strings.Repeat("x", models.MaxWorkspaceSlugLength-9),
+	}
+	for name, want := range tests {
+		if got := slugify(name); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	slug, err := generateWorkspaceSlug("Jane Doe")
+	if err != nil || !models.IsValidWorkspaceSlug(slug) {
+		t.Errorf("generateWorkspaceSlug() = %q, %v, want a valid slug", slug, err)
+	}
+}
+
+func TestUpdateWorkspace_SlugTaken(t *testing.T) {
+	other := &models.Workspace{ID: uuid.New(), Slug: "acme"}
+	repo := &slugWorkspaceRepo{bySlug: map[string]*models.Workspace{
+		"acme":   other,
+		"acme-2": {ID: uuid.New(), Slug: "acme-2"},
+	}}
+	svc := &workspaceService{wsRepo: repo, logger: zap.NewNop()}
+
+	slug := "ACME"
+	_, err := svc.UpdateWorkspace(context.Background(), uuid.New(), models.UpdateWorkspaceInput{Slug: &slug})
+
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "ALREADY_EXISTS" {
+		t.Fatalf("expected ALREADY_EXISTS, got %v", err)
+	}
+	suggestions, _ := appErr.Details["suggestions"].([]string)
+	if len(suggestions) != maxSlugSuggestions || suggestions[0] != "acme-3" || suggestions[1] != "acme-4" {
+		t.Errorf("suggestions = %v, want free numbered alternatives", suggestions)
+	}
+	if repo.updated != nil {
+		t.Error("expected no update for a taken slug")
+	}
+}
+
+func TestUpdateWorkspace_KeepsOwnSlug(t *testing.T) {
+	id := uuid.New()
+	repo := &slugWorkspaceRepo{bySlug: map[string]*models.Workspace{"acme": {ID: id, Slug: "acme"}}}
+	svc := &workspaceService{wsRepo: repo, logger: zap.NewNop()}
+
+	slug := "acme"
+	if _, err := svc.UpdateWorkspace(context.Background(), id, models.UpdateWorkspaceInput{Slug: &slug}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updated == nil || repo.updated.Slug.String != "acme" {
+		t.Errorf("expected the slug to be saved, got %+v", repo.updated)
+	}
+}
+
+func TestUpdateWorkspace_SlugConflictOnWrite(t *testing.T) {
+	repo := &slugWorkspaceRepo{
+		bySlug:    map[string]*models.Workspace{},
+		updateErr: httputil.AlreadyExists("workspace slug"),
+	}
+	svc := &workspaceService{wsRepo: repo, logger: zap.NewNop()}
+
+	slug := "acme"
+	_, err := svc.UpdateWorkspace(context.Background(), uuid.New(), models.UpdateWorkspaceInput{Slug: &slug})
+
+	var appErr *httputil.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "ALREADY_EXISTS" || appErr.Details["field"] != "slug" {
+		t.Fatalf("expected ALREADY_EXISTS on slug, got %v", err)
+	}
+	if _, ok := appErr.Details["suggestions"]; !ok {
+		t.Error("expected suggestions with a conflict found on write")
+	}
+}
+
+func TestWithSlugSuffix(t *testing.T) {
+	long := strings.Repeat("a", models.MaxWorkspaceSlugLength)
+	got := withSlugSuffix(long, "2")
+	if len(got) != models.MaxWorkspaceSlugLength || !strings.HasSuffix(got, "-2") || !models.IsValidWorkspaceSlug(got) {
+		t.Errorf("withSlugSuffix() = %q, want a valid slug within the length limit", got)
+	}
+}
@@ -0,0 +1,24 @@
+package worker
+
+import "net"
+
+// Prefix lengths kept when anonymizing client IPs.
+const (
+	anonIPv4PrefixBits = 24
+	anonIPv6PrefixBits = 48
+)
+
+// AnonymizeIP truncates an IPv4 address to its /24 network and an IPv6
+// address to its /48 network (e.g. 203.0.113.42 -> 203.0.113.0). Values that
+// don't parse as an IP are returned unchanged.
+func AnonymizeIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(anonIPv4PrefixBits, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(anonIPv6PrefixBits, 128)).String()
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const bulkLinkJobQueue = "bulk_link_job:queue"
+
+// BulkLinkJobProcessor creates the links of queued bulk link jobs.
+type BulkLinkJobProcessor struct {
+	redis  *redis.Client
+	runner *service.BulkLinkJobRunner
+	logger *zap.Logger
+	done   chan struct{}
+}
+
+func NewBulkLinkJobProcessor(
+	redisClient *redis.Client,
+	runner *service.BulkLinkJobRunner,
+	logger *zap.Logger,
+) *BulkLinkJobProcessor {
+	return &BulkLinkJobProcessor{
+		redis:  redisClient,
+		runner: runner,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+}
+
+// Start begins processing bulk link jobs, one at a time.
+func (p *BulkLinkJobProcessor) Start(ctx context.Context) {
+	p.logger.Info("bulk link job processor started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("bulk link job processor shutting down")
+			return
+		case <-p.done:
+			return
+		default:
+			p.processQueue(ctx)
+		}
+	}
+}
+
+// Stop signals the processor to stop.
+func (p *BulkLinkJobProcessor) Stop() {
+	close(p.done)
+}
+
+func (p *BulkLinkJobProcessor) processQueue(ctx context.Context) {
+	result, err := p.redis.BLPop(ctx, 2*time.Second, bulkLinkJobQueue).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		p.logger.Error("failed to pop from bulk link job queue", zap.Error(err))
+		time.Sleep(1 * time.Second)
+		return
+	}
+
+	jobID, err := uuid.Parse(result[1])
+	if err != nil {
+		p.logger.Warn("invalid bulk link job ID in queue", zap.String("value", result[1]))
+		return
+	}
+
+	if err := p.runner.Run(ctx, jobID); err != nil {
+		p.logger.Warn("bulk link job did not run",
+			zap.String("job_id", jobID.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -2,17 +2,23 @@ package worker
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"regexp"
-	"strings"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/internal/realtime"
 	"github.com/link-rift/link-rift/internal/redirect"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
 	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/lock"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -21,6 +27,29 @@ const (
 	clickQueueKey = "clicks:queue"
 	batchSize     = 100
 	batchWindow   = 1 * time.Second
+
+	// uniqueClickKeyPrefix keys the marker for a visitor's most recent click
+	// on a link: clicks:unique:<link_id>:<visitor_id>.
+	uniqueClickKeyPrefix     = "clicks:unique:"
+	defaultUniqueClickWindow = 24 * time.Hour
+	// duplicateClickKeyPrefix keys the time of a visitor's last click on a
+	// link, for telling double taps apart from new clicks:
+	// clicks:dedup:<link_id>:<visitor_id>.
+	duplicateClickKeyPrefix = "clicks:dedup:"
+	// uniqueClickReconcileInterval is how often unique click counters of
+	// recently clicked links are recomputed from the clicks table.
+	uniqueClickReconcileInterval = time.Hour
+
+	// unsampledClicksKey is the redirect service's hash of link ID to
+	// clicks skipped by sampling. Each drain renames it to
+	// unsampledClicksDrainKey and adds the counts to the links' totals.
+	unsampledClicksKey          = "clicks:unsampled"
+	unsampledClicksDrainKey     = "clicks:unsampled:draining"
+	unsampledClickDrainInterval = 5 * time.Second
+
+	// linkScopeTTL bounds how long a link's workspace is reused when
+	// enriching events; transfers can move a link to another workspace.
+	linkScopeTTL = 10 * time.Minute
 )
 
 // ClickProcessor reads click events from the Redis queue and processes them into the database.
@@ -30,10 +59,29 @@ type ClickProcessor struct {
 	linkRepo    repository.LinkRepository
 	botDetector *redirect.BotDetector
 	geoLookup   *GeoLookup
-	chForwarder *ClickHouseForwarder
+	sinks       []ClickSink
 	events      service.EventPublisher
-	logger      *zap.Logger
-	done        chan struct{}
+	locker      *lock.Locker
+	anonymizeIP atomic.Bool
+	// uniqueWindow is how long repeat clicks by the same visitor don't count
+	// as unique, as a time.Duration.
+	uniqueWindow atomic.Int64
+	// duplicateWindow is how close together a visitor's clicks on a link
+	// count as one, as a time.Duration; 0 counts every click.
+	duplicateWindow atomic.Int64
+	lastClicks      lastClickStore
+	// linkScopes caches the workspace and short code of links whose events
+	// arrive without them, keyed by link ID.
+	linkScopes sync.Map
+	logger     *zap.Logger
+	done       chan struct{}
+}
+
+// linkScope is a cached linkScopes entry.
+type linkScope struct {
+	workspaceID uuid.UUID
+	shortCode   string
+	expiresAt   time.Time
 }
 
 func NewClickProcessor(
@@ -43,7 +91,7 @@ func NewClickProcessor(
 	botDetector *redirect.BotDetector,
 	logger *zap.Logger,
 ) *ClickProcessor {
-	return &ClickProcessor{
+	cp := &ClickProcessor{
 		redis:       redisClient,
 		clickRepo:   clickRepo,
 		linkRepo:    linkRepo,
@@ -51,6 +99,11 @@ func NewClickProcessor(
 		logger:      logger,
 		done:        make(chan struct{}),
 	}
+	cp.uniqueWindow.Store(int64(defaultUniqueClickWindow))
+	if redisClient != nil {
+		cp.lastClicks = redisLastClickStore{redisClient}
+	}
+	return cp
 }
 
 // SetGeoLookup attaches an optional GeoIP2 lookup provider.
@@ -58,9 +111,10 @@ func (cp *ClickProcessor) SetGeoLookup(gl *GeoLookup) {
 	cp.geoLookup = gl
 }
 
-// SetClickHouseForwarder attaches an optional ClickHouse forwarder.
-func (cp *ClickProcessor) SetClickHouseForwarder(f *ClickHouseForwarder) {
-	cp.chForwarder = f
+// AddClickSink attaches a sink that receives every stored click, such as
+// the ClickHouse forwarder or an event bus publisher.
+func (cp *ClickProcessor) AddClickSink(sink ClickSink) {
+	cp.sinks = append(cp.sinks, sink)
 }
 
 // SetEventPublisher attaches an optional webhook event publisher.
@@ -68,10 +122,40 @@ func (cp *ClickProcessor) SetEventPublisher(ep service.EventPublisher) {
 	cp.events = ep
 }
 
+// SetLocker makes replicas take turns reconciling unique clicks, so each
+// interval is reconciled once.
+func (cp *ClickProcessor) SetLocker(l *lock.Locker) {
+	cp.locker = l
+}
+
+// SetIPAnonymization enables truncating client IPs (IPv4 /24, IPv6 /48)
+// before clicks are stored. GeoIP enrichment still uses the full address.
+func (cp *ClickProcessor) SetIPAnonymization(enabled bool) {
+	cp.anonymizeIP.Store(enabled)
+}
+
+// SetUniqueClickWindow sets how long repeat clicks by the same visitor on a
+// link don't count as unique. Non-positive values keep the default.
+func (cp *ClickProcessor) SetUniqueClickWindow(window time.Duration) {
+	if window > 0 {
+		cp.uniqueWindow.Store(int64(window))
+	}
+}
+
+// SetDuplicateClickWindow sets how close together a visitor's clicks on a
+// link are counted as one click. 0 counts every click.
+func (cp *ClickProcessor) SetDuplicateClickWindow(window time.Duration) {
+	cp.duplicateWindow.Store(int64(max(window, 0)))
+}
+
 // Start begins processing click events from the Redis queue.
 func (cp *ClickProcessor) Start(ctx context.Context) {
 	cp.logger.Info("click processor started")
 
+	// Start unique click reconciliation goroutine
+	go cp.reconcileLoop(ctx)
+	go cp.unsampledClicksLoop(ctx)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -133,13 +217,16 @@ func (cp *ClickProcessor) processBatch(ctx context.Context) {
 }
 
 func (cp *ClickProcessor) processEvents(ctx context.Context, events []*models.ClickEvent) {
+	stored := make([]*models.ClickEvent, 0, len(events))
+	enriched := make([]EnrichedClick, 0, len(events))
 	for _, event := range events {
+		cp.enrichEvent(ctx, event)
 		isBot := cp.botDetector.IsBot(event.UserAgent)
 
 		// Parse user agent
-		browser, browserVersion := parseBrowser(event.UserAgent)
-		osName, osVersion := parseOS(event.UserAgent)
-		deviceType := parseDeviceType(event.UserAgent)
+		browser, browserVersion := redirect.ParseBrowser(event.UserAgent)
+		osName, osVersion := redirect.ParseOS(event.UserAgent)
+		deviceType := redirect.ParseDeviceType(event.UserAgent)
 
 		// Geo enrichment (optional, nil-safe)
 		var countryCode, region, city string
@@ -147,9 +234,25 @@ func (cp *ClickProcessor) processEvents(ctx context.Context, events []*models.Cl
 			countryCode, region, city = cp.geoLookup.Lookup(event.IP)
 		}
 
+		// Anonymize after geo lookup so location accuracy is preserved. The
+		// stored form is also what unique-click counts key off.
+		if cp.anonymizeIP.Load() {
+			event.IP = AnonymizeIP(event.IP)
+		}
+		visitor := visitorID(event.IP, event.UserAgent)
+
+		// A double tap is one click
+		if cp.isDuplicateClick(ctx, event, visitor) {
+			cp.logger.Debug("dropping duplicate click",
+				zap.String("link_id", event.LinkID.String()),
+			)
+			continue
+		}
+
 		params := sqlc.InsertClickParams{
 			LinkID:         event.LinkID,
 			ClickedAt:      pgtype.Timestamptz{Time: event.Timestamp, Valid: true},
+			VisitorID:      pgtype.Text{String: visitor, Valid: true},
 			IpAddress:      event.IP,
 			UserAgent:      pgtype.Text{String: event.UserAgent, Valid: event.UserAgent != ""},
 			Referer:        pgtype.Text{String: event.Referer, Valid: event.Referer != ""},
@@ -162,6 +265,7 @@ func (cp *ClickProcessor) processEvents(ctx context.Context, events []*models.Cl
 			Os:             pgtype.Text{String: osName, Valid: osName != ""},
 			OsVersion:      pgtype.Text{String: osVersion, Valid: osVersion != ""},
 			DeviceType:     pgtype.Text{String: deviceType, Valid: deviceType != ""},
+			Source:         pgtype.Text{String: event.Source, Valid: event.Source != ""},
 		}
 
 		if err := cp.clickRepo.Insert(ctx, params); err != nil {
@@ -180,22 +284,22 @@ func (cp *ClickProcessor) processEvents(ctx context.Context, events []*models.Cl
 					zap.String("link_id", event.LinkID.String()),
 				)
 			}
+			cp.countUniqueClick(ctx, event.LinkID, visitor)
 		}
 
-		// Forward to ClickHouse (optional, nil-safe, async/best-effort)
-		if cp.chForwarder != nil {
-			cp.chForwarder.Forward(ctx, event, EnrichedClick{
-				CountryCode:    countryCode,
-				Region:         region,
-				City:           city,
-				Browser:        browser,
-				BrowserVersion: browserVersion,
-				OS:             osName,
-				OSVersion:      osVersion,
-				DeviceType:     deviceType,
-				IsBot:          isBot,
-			})
-		}
+		// Collected for the click sinks once the batch is done
+		stored = append(stored, event)
+		enriched = append(enriched, EnrichedClick{
+			CountryCode:    countryCode,
+			Region:         region,
+			City:           city,
+			Browser:        browser,
+			BrowserVersion: browserVersion,
+			OS:             osName,
+			OSVersion:      osVersion,
+			DeviceType:     deviceType,
+			IsBot:          isBot,
+		})
 
 		// Publish real-time click notification via Redis Pub/Sub
 		if !isBot && cp.redis != nil {
@@ -208,11 +312,21 @@ func (cp *ClickProcessor) processEvents(ctx context.Context, events []*models.Cl
 				DeviceType:  deviceType,
 				Browser:     browser,
 				Referer:     event.Referer,
+				Source:      event.Source,
 			}
 			notifData, err := json.Marshal(notification)
 			if err == nil {
 				cp.redis.Publish(ctx, "clicks:realtime", notifData)
 			}
+
+			// A sampled click stands for SampleRate clicks in the totals
+			clicks := int64(max(event.SampleRate, 1))
+			if err := realtime.RecordClick(ctx, cp.redis, &notification, clicks); err != nil {
+				cp.logger.Warn("failed to update realtime counters",
+					zap.Error(err),
+					zap.String("link_id", event.LinkID.String()),
+				)
+			}
 		}
 
 		// Publish webhook event for link.clicked (best-effort, non-bot only)
@@ -226,76 +340,278 @@ func (cp *ClickProcessor) processEvents(ctx context.Context, events []*models.Cl
 				"browser":      browser,
 				"referer":      event.Referer,
 			}
+			if event.Source != "" {
+				clickData["source"] = event.Source
+			}
 			if err := cp.events.Publish(ctx, "link.clicked", event.WorkspaceID, clickData); err != nil {
 				cp.logger.Warn("failed to publish link.clicked webhook event", zap.Error(err))
 			}
+			if event.Source == models.ClickSourceQR {
+				if err := cp.events.Publish(ctx, "qr.scanned", event.WorkspaceID, clickData); err != nil {
+					cp.logger.Warn("failed to publish qr.scanned webhook event", zap.Error(err))
+				}
+			}
+		}
+	}
+
+	// Fan out to ClickHouse and event buses (best-effort)
+	if len(stored) > 0 {
+		for _, sink := range cp.sinks {
+			sink.ForwardBatch(ctx, stored, enriched)
 		}
 	}
 
 	cp.logger.Debug("processed click batch", zap.Int("count", len(events)))
 }
 
-// Simple UA parsing functions
+// enrichEvent fills in the workspace and short code of events queued without
+// them, e.g. by older redirect instances, so ClickHouse rows, realtime
+// notifications and webhooks are scoped to the link's workspace. Events for
+// links that can't be looked up are processed as they are.
+func (cp *ClickProcessor) enrichEvent(ctx context.Context, event *models.ClickEvent) {
+	if event.WorkspaceID != uuid.Nil && event.ShortCode != "" {
+		return
+	}
 
-var (
-	chromeRe  = regexp.MustCompile(`Chrome/(\d+[\.\d]*)`)
-	firefoxRe = regexp.MustCompile(`Firefox/(\d+[\.\d]*)`)
-	safariRe  = regexp.MustCompile(`Version/(\d+[\.\d]*).*Safari`)
-	edgeRe    = regexp.MustCompile(`Edg/(\d+[\.\d]*)`)
-	operaRe   = regexp.MustCompile(`OPR/(\d+[\.\d]*)`)
+	scope, ok := cp.lookupLinkScope(ctx, event.LinkID)
+	if !ok {
+		return
+	}
+	if event.WorkspaceID == uuid.Nil {
+		event.WorkspaceID = scope.workspaceID
+	}
+	if event.ShortCode == "" {
+		event.ShortCode = scope.shortCode
+	}
+}
 
-	windowsRe = regexp.MustCompile(`Windows NT (\d+[\.\d]*)`)
-	macRe     = regexp.MustCompile(`Mac OS X (\d+[_\.\d]*)`)
-	linuxRe   = regexp.MustCompile(`Linux`)
-	androidRe = regexp.MustCompile(`Android (\d+[\.\d]*)`)
-	iosRe     = regexp.MustCompile(`(?:iPhone|iPad) OS (\d+[_\.\d]*)`)
-)
+func (cp *ClickProcessor) lookupLinkScope(ctx context.Context, linkID uuid.UUID) (linkScope, bool) {
+	if v, ok := cp.linkScopes.Load(linkID); ok {
+		if scope := v.(linkScope); time.Now().Before(scope.expiresAt) {
+			return scope, true
+		}
+	}
+
+	link, err := cp.linkRepo.GetByID(ctx, linkID)
+	if err != nil || link == nil {
+		cp.logger.Warn("failed to look up link for click event",
+			zap.Error(err),
+			zap.String("link_id", linkID.String()),
+		)
+		return linkScope{}, false
+	}
+
+	scope := linkScope{
+		workspaceID: link.WorkspaceID,
+		shortCode:   link.ShortCode,
+		expiresAt:   time.Now().Add(linkScopeTTL),
+	}
+	cp.linkScopes.Store(linkID, scope)
+	return scope, true
+}
 
-func parseBrowser(ua string) (name, version string) {
-	if m := edgeRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "Edge", m[1]
+// visitorID identifies a visitor for unique click counting by hashing the
+// stored IP with the user agent, so visitors behind one address are told apart
+// without keying Redis on raw addresses.
+func visitorID(ip, userAgent string) string {
+	sum := sha256.Sum256([]byte(ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// countUniqueClick increments the link's unique clicks unless the visitor
+// already clicked it within the unique click window. Every click restarts the
+// visitor's window, matching how ReconcileUniqueClicks recomputes the counter.
+func (cp *ClickProcessor) countUniqueClick(ctx context.Context, linkID uuid.UUID, visitor string) {
+	if cp.redis == nil {
+		return
+	}
+
+	key := uniqueClickKeyPrefix + linkID.String() + ":" + visitor
+	err := cp.redis.SetArgs(ctx, key, 1, redis.SetArgs{TTL: time.Duration(cp.uniqueWindow.Load()), Get: true}).Err()
+	if err == nil {
+		return // Seen within the window
+	}
+	if err != redis.Nil {
+		// Leave it to reconciliation rather than risk double counting.
+		cp.logger.Warn("failed to check unique click",
+			zap.Error(err),
+			zap.String("link_id", linkID.String()),
+		)
+		return
 	}
-	if m := operaRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "Opera", m[1]
+
+	if err := cp.linkRepo.IncrementUniqueClicks(ctx, linkID); err != nil {
+		cp.logger.Error("failed to increment unique click counter",
+			zap.Error(err),
+			zap.String("link_id", linkID.String()),
+		)
 	}
-	if m := chromeRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "Chrome", m[1]
+}
+
+// lastClickStore keeps the time of each visitor's last click on a link.
+type lastClickStore interface {
+	// SwapLastClick stores at under key for ttl and returns the time it
+	// replaced, if there was one.
+	SwapLastClick(ctx context.Context, key string, at time.Time, ttl time.Duration) (prev time.Time, found bool, err error)
+}
+
+type redisLastClickStore struct {
+	client *redis.Client
+}
+
+func (s redisLastClickStore) SwapLastClick(ctx context.Context, key string, at time.Time, ttl time.Duration) (time.Time, bool, error) {
+	prev, err := s.client.SetArgs(ctx, key, at.UnixMilli(), redis.SetArgs{TTL: ttl, Get: true}).Result()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
 	}
-	if m := firefoxRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "Firefox", m[1]
+	if err != nil {
+		return time.Time{}, false, err
 	}
-	if m := safariRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "Safari", m[1]
+	ms, err := strconv.ParseInt(prev, 10, 64)
+	if err != nil {
+		return time.Time{}, false, nil
 	}
-	return "", ""
+	return time.UnixMilli(ms), true, nil
 }
 
-func parseOS(ua string) (name, version string) {
-	if m := iosRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "iOS", strings.ReplaceAll(m[1], "_", ".")
+// isDuplicateClick reports whether the visitor clicked the link less than
+// the duplicate click window before event, going by the events' timestamps
+// so that a backlog in the queue doesn't merge clicks. Every click restarts
+// the window, so a burst of taps counts once. If Redis fails the click is
+// counted.
+func (cp *ClickProcessor) isDuplicateClick(ctx context.Context, event *models.ClickEvent, visitor string) bool {
+	window := time.Duration(cp.duplicateWindow.Load())
+	if window <= 0 || cp.lastClicks == nil {
+		return false
 	}
-	if m := androidRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "Android", m[1]
+
+	key := duplicateClickKeyPrefix + event.LinkID.String() + ":" + visitor
+	prev, found, err := cp.lastClicks.SwapLastClick(ctx, key, event.Timestamp, window)
+	if err != nil {
+		cp.logger.Warn("failed to check duplicate click",
+			zap.Error(err),
+			zap.String("link_id", event.LinkID.String()),
+		)
+		return false
 	}
-	if m := macRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "macOS", strings.ReplaceAll(m[1], "_", ".")
+	if !found {
+		return false
 	}
-	if m := windowsRe.FindStringSubmatch(ua); len(m) > 1 {
-		return "Windows", m[1]
+	gap := event.Timestamp.Sub(prev)
+	return gap > -window && gap < window
+}
+
+// reconcileLoop periodically recomputes unique click counters from the clicks
+// table, correcting drift from Redis failures or evicted window markers.
+func (cp *ClickProcessor) reconcileLoop(ctx context.Context) {
+	ticker := time.NewTicker(uniqueClickReconcileInterval)
+	defer ticker.Stop()
+
+	since := time.Now().Add(-uniqueClickReconcileInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cp.done:
+			return
+		case <-ticker.C:
+			runPeriodic(ctx, cp.locker, "worker:unique-click-reconcile", uniqueClickReconcileInterval, cp.logger, func(ctx context.Context) {
+				since = cp.reconcileUniqueClicks(ctx, since)
+			})
+		}
 	}
-	if linuxRe.MatchString(ua) {
-		return "Linux", ""
+}
+
+// reconcileUniqueClicks recomputes counters for links clicked since the given
+// time and returns the start of the next pass.
+func (cp *ClickProcessor) reconcileUniqueClicks(ctx context.Context, since time.Time) time.Time {
+	started := time.Now()
+	n, err := cp.linkRepo.ReconcileUniqueClicks(ctx, since, time.Duration(cp.uniqueWindow.Load()))
+	if err != nil {
+		cp.logger.Error("failed to reconcile unique clicks", zap.Error(err))
+		return since // Retry the same range next time
+	}
+	cp.logger.Debug("reconciled unique clicks", zap.Int64("links", n))
+	return started
+}
+
+// unsampledClicksLoop periodically adds clicks skipped by sampling to their
+// links' total clicks.
+func (cp *ClickProcessor) unsampledClicksLoop(ctx context.Context) {
+	ticker := time.NewTicker(unsampledClickDrainInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cp.done:
+			return
+		case <-ticker.C:
+			runPeriodic(ctx, cp.locker, "worker:unsampled-clicks", unsampledClickDrainInterval, cp.logger, cp.drainUnsampledClicks)
+		}
 	}
-	return "", ""
 }
 
-func parseDeviceType(ua string) string {
-	uaLower := strings.ToLower(ua)
-	if strings.Contains(uaLower, "tablet") || strings.Contains(uaLower, "ipad") {
-		return "tablet"
+// drainUnsampledClicks moves the unsampled click counts aside and applies
+// them. A drain key left by an interrupted run is finished first, so its
+// counts aren't overwritten.
+func (cp *ClickProcessor) drainUnsampledClicks(ctx context.Context) {
+	pending, err := cp.redis.Exists(ctx, unsampledClicksDrainKey).Result()
+	if err != nil {
+		cp.logger.Error("failed to check unsampled clicks", zap.Error(err))
+		return
 	}
-	if strings.Contains(uaLower, "mobile") || strings.Contains(uaLower, "iphone") || strings.Contains(uaLower, "android") {
-		return "mobile"
+	if pending == 0 {
+		queued, err := cp.redis.Exists(ctx, unsampledClicksKey).Result()
+		if err != nil || queued == 0 {
+			return
+		}
+		if err := cp.redis.Rename(ctx, unsampledClicksKey, unsampledClicksDrainKey).Err(); err != nil {
+			cp.logger.Error("failed to take unsampled clicks", zap.Error(err))
+			return
+		}
+	}
+
+	counts, err := cp.redis.HGetAll(ctx, unsampledClicksDrainKey).Result()
+	if err != nil {
+		cp.logger.Error("failed to read unsampled clicks", zap.Error(err))
+		return
+	}
+	if applied := cp.addUnsampledClicks(ctx, counts); len(applied) > 0 {
+		if err := cp.redis.HDel(ctx, unsampledClicksDrainKey, applied...).Err(); err != nil {
+			cp.logger.Error("failed to clear applied unsampled clicks", zap.Error(err))
+		}
+	}
+}
+
+// addUnsampledClicks adds each link's skipped clicks to its total and
+// returns the link IDs that are done with: applied, or unparseable. Links
+// that failed to update are left to retry on the next drain.
+func (cp *ClickProcessor) addUnsampledClicks(ctx context.Context, counts map[string]string) []string {
+	done := make([]string, 0, len(counts))
+	for field, value := range counts {
+		linkID, err := uuid.Parse(field)
+		n, nerr := strconv.ParseInt(value, 10, 64)
+		if err != nil || nerr != nil {
+			cp.logger.Warn("dropping malformed unsampled click count",
+				zap.String("link_id", field),
+				zap.String("count", value),
+			)
+			done = append(done, field)
+			continue
+		}
+		if n > 0 {
+			if err := cp.linkRepo.AddClicks(ctx, linkID, n); err != nil {
+				cp.logger.Error("failed to add unsampled clicks",
+					zap.String("link_id", field),
+					zap.Int64("count", n),
+					zap.Error(err),
+				)
+				continue
+			}
+		}
+		done = append(done, field)
 	}
-	return "desktop"
+	return done
 }
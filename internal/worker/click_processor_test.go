@@ -2,6 +2,9 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -34,13 +37,21 @@ func (m *mockClickRepo) GetByLinkID(ctx context.Context, params sqlc.GetClicksBy
 }
 
 type mockLinkRepo struct {
-	incrementFn func(ctx context.Context, id uuid.UUID) error
+	getByIDFn         func(ctx context.Context, id uuid.UUID) (*models.Link, error)
+	incrementFn       func(ctx context.Context, id uuid.UUID) error
+	addClicksFn       func(ctx context.Context, id uuid.UUID, count int64) error
+	incrementUniqueFn func(ctx context.Context, id uuid.UUID) error
+	reconcileFn       func(ctx context.Context, since time.Time, window time.Duration) (int64, error)
+	listFailoversFn   func(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Link, error)
 }
 
 func (m *mockLinkRepo) Create(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
 	return nil, nil
 }
-func (m *mockLinkRepo) GetByID(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+func (m *mockLinkRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Link, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
 	return nil, nil
 }
 func (m *mockLinkRepo) GetByShortCode(_ context.Context, _ string) (*models.Link, error) {
@@ -55,139 +66,99 @@ func (m *mockLinkRepo) List(_ context.Context, _ sqlc.ListLinksForWorkspaceParam
 func (m *mockLinkRepo) Update(_ context.Context, _ sqlc.UpdateLinkParams) (*models.Link, error) {
 	return nil, nil
 }
+func (m *mockLinkRepo) UpdateMetadata(_ context.Context, _ sqlc.UpdateLinkMetadataParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) Transfer(_ context.Context, _ sqlc.TransferLinkParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) Archive(_ context.Context, _ uuid.UUID, _ bool) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) Unarchive(_ context.Context, _ uuid.UUID) (*models.Link, error) {
+	return nil, nil
+}
 func (m *mockLinkRepo) SoftDelete(_ context.Context, _ uuid.UUID) error   { return nil }
+func (m *mockLinkRepo) SoftDeleteForWorkspace(_ context.Context, _ uuid.UUID) (int64, error) {
+	return 0, nil
+}
+func (m *mockLinkRepo) Reserve(_ context.Context, _ sqlc.ReserveShortCodeParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) GetReservation(_ context.Context, _ uuid.UUID, _ string) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) ClaimReservation(_ context.Context, _ sqlc.CreateLinkParams) (*models.Link, error) {
+	return nil, nil
+}
+func (m *mockLinkRepo) ReleaseExpiredReservation(_ context.Context, _ string) error { return nil }
+func (m *mockLinkRepo) ReleaseExpiredReservations(_ context.Context) (int64, error) {
+	return 0, nil
+}
 func (m *mockLinkRepo) ShortCodeExists(_ context.Context, _ string) (bool, error) {
 	return false, nil
 }
+func (m *mockLinkRepo) ShortCodeExistsIgnoreCase(_ context.Context, _ string) (bool, error) {
+	return false, nil
+}
 func (m *mockLinkRepo) IncrementClicks(ctx context.Context, id uuid.UUID) error {
 	if m.incrementFn != nil {
 		return m.incrementFn(ctx, id)
 	}
 	return nil
 }
-func (m *mockLinkRepo) IncrementUniqueClicks(_ context.Context, _ uuid.UUID) error { return nil }
+func (m *mockLinkRepo) AddClicks(ctx context.Context, id uuid.UUID, count int64) error {
+	if m.addClicksFn != nil {
+		return m.addClicksFn(ctx, id, count)
+	}
+	return nil
+}
+func (m *mockLinkRepo) IncrementUniqueClicks(ctx context.Context, id uuid.UUID) error {
+	if m.incrementUniqueFn != nil {
+		return m.incrementUniqueFn(ctx, id)
+	}
+	return nil
+}
+func (m *mockLinkRepo) ReconcileUniqueClicks(ctx context.Context, since time.Time, window time.Duration) (int64, error) {
+	if m.reconcileFn != nil {
+		return m.reconcileFn(ctx, since, window)
+	}
+	return 0, nil
+}
 func (m *mockLinkRepo) GetQuickStats(_ context.Context, _ uuid.UUID) (*models.LinkQuickStats, error) {
 	return nil, nil
 }
-func (m *mockLinkRepo) GetCountForWorkspace(_ context.Context, _ uuid.UUID) (int64, error) {
+func (m *mockLinkRepo) CountCreatedForWorkspace(_ context.Context, _ uuid.UUID, _, _ time.Time) (int64, error) {
 	return 0, nil
 }
-
-// --- UA Parsing Tests ---
-
-func TestParseBrowser(t *testing.T) {
-	tests := []struct {
-		ua          string
-		wantName    string
-		wantVersion string
-	}{
-		{
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
-			"Chrome", "91.0.4472.124",
-		},
-		{
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:89.0) Gecko/20100101 Firefox/89.0",
-			"Firefox", "89.0",
-		},
-		{
-			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1.1 Safari/605.1.15",
-			"Safari", "14.1.1",
-		},
-		{
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36 Edg/91.0.864.59",
-			"Edge", "91.0.864.59",
-		},
-		{
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0 Safari/537.36 OPR/77.0",
-			"Opera", "77.0",
-		},
-		{"", "", ""},
-		{"some random string", "", ""},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.wantName, func(t *testing.T) {
-			name, version := parseBrowser(tt.ua)
-			if name != tt.wantName {
-				t.Errorf("parseBrowser(%q) name = %q, want %q", tt.ua, name, tt.wantName)
-			}
-			if version != tt.wantVersion {
-				t.Errorf("parseBrowser(%q) version = %q, want %q", tt.ua, version, tt.wantVersion)
-			}
-		})
-	}
+func (m *mockLinkRepo) LockLinkLimit(_ context.Context, _ uuid.UUID) error {
+	return nil
+}
+func (m *mockLinkRepo) ListIDsByTag(_ context.Context, _, _ uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
 }
 
-func TestParseOS(t *testing.T) {
-	tests := []struct {
-		ua          string
-		wantName    string
-		wantVersion string
-	}{
-		{
-			"Mozilla/5.0 (Windows NT 10.0; Win64; x64)",
-			"Windows", "10.0",
-		},
-		{
-			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)",
-			"macOS", "10.15.7",
-		},
-		{
-			"Mozilla/5.0 (X11; Linux x86_64)",
-			"Linux", "",
-		},
-		{
-			"Mozilla/5.0 (Linux; Android 11; SM-G998B)",
-			"Android", "11",
-		},
-		{
-			"Mozilla/5.0 (iPhone; CPU iPhone OS 14_6 like Mac OS X)",
-			"iOS", "14.6",
-		},
-		{"", "", ""},
-	}
+func (m *mockLinkRepo) AddTag(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
 
-	for _, tt := range tests {
-		t.Run(tt.wantName, func(t *testing.T) {
-			name, version := parseOS(tt.ua)
-			if name != tt.wantName {
-				t.Errorf("parseOS(%q) name = %q, want %q", tt.ua, name, tt.wantName)
-			}
-			if version != tt.wantVersion {
-				t.Errorf("parseOS(%q) version = %q, want %q", tt.ua, version, tt.wantVersion)
-			}
-		})
-	}
+func (m *mockLinkRepo) ListMostClicked(_ context.Context, _ int) ([]*models.Link, error) {
+	return nil, nil
 }
 
-func TestParseDeviceType(t *testing.T) {
-	tests := []struct {
-		ua   string
-		want string
-	}{
-		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64)", "desktop"},
-		{"Mozilla/5.0 (Linux; Android 11) Mobile Safari", "mobile"},
-		{"Mozilla/5.0 (iPhone; CPU iPhone OS 14_6)", "mobile"},
-		{"Mozilla/5.0 (iPad; CPU OS 14_6 like Mac OS X)", "tablet"},
-		{"Mozilla/5.0 (Linux; Android 11; SM-T870) Tablet", "tablet"},
-		{"", "desktop"},
+func (m *mockLinkRepo) ListWithFailovers(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Link, error) {
+	if m.listFailoversFn != nil {
+		return m.listFailoversFn(ctx, afterID, limit)
 	}
+	return nil, nil
+}
 
-	for _, tt := range tests {
-		t.Run(tt.want+"_"+tt.ua[:min(20, len(tt.ua))], func(t *testing.T) {
-			got := parseDeviceType(tt.ua)
-			if got != tt.want {
-				t.Errorf("parseDeviceType(%q) = %q, want %q", tt.ua, got, tt.want)
-			}
-		})
-	}
+func (m *mockLinkRepo) EnsureTag(_ context.Context, _ uuid.UUID, _ string) (uuid.UUID, error) {
+	return uuid.New(), nil
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+func (m *mockLinkRepo) CountWorkspaceTags(_ context.Context, _ uuid.UUID, _ []uuid.UUID) (int64, error) {
+	return 0, nil
 }
 
 // --- processEvents Tests ---
@@ -347,15 +318,24 @@ func TestProcessEvents_WithGeoLookup(t *testing.T) {
 	}
 }
 
-func TestProcessEvents_WithClickHouseForwarder(t *testing.T) {
-	// When chForwarder is nil, processEvents should not panic
+type recordingSink struct {
+	events   []*models.ClickEvent
+	enriched []EnrichedClick
+}
+
+func (s *recordingSink) ForwardBatch(_ context.Context, events []*models.ClickEvent, enriched []EnrichedClick) {
+	s.events = append(s.events, events...)
+	s.enriched = append(s.enriched, enriched...)
+}
+
+func TestProcessEvents_WithoutClickSinks(t *testing.T) {
+	// With no sinks attached, processEvents should not panic
 	logger, _ := zap.NewDevelopment()
 	cp := &ClickProcessor{
 		clickRepo:   &mockClickRepo{},
 		linkRepo:    &mockLinkRepo{},
 		botDetector: redirect.NewBotDetector(),
 		logger:      logger,
-		chForwarder: nil, // explicitly nil
 	}
 
 	events := []*models.ClickEvent{
@@ -372,6 +352,39 @@ func TestProcessEvents_WithClickHouseForwarder(t *testing.T) {
 	cp.processEvents(context.Background(), events)
 }
 
+func TestProcessEvents_FansOutToClickSinks(t *testing.T) {
+	failing := uuid.New()
+	cp := &ClickProcessor{
+		clickRepo: &mockClickRepo{insertFn: func(_ context.Context, params sqlc.InsertClickParams) error {
+			if params.LinkID == failing {
+				return errors.New("insert failed")
+			}
+			return nil
+		}},
+		linkRepo:    &mockLinkRepo{},
+		botDetector: redirect.NewBotDetector(),
+		logger:      zap.NewNop(),
+	}
+	first, second := &recordingSink{}, &recordingSink{}
+	cp.AddClickSink(first)
+	cp.AddClickSink(second)
+
+	stored := &models.ClickEvent{LinkID: uuid.New(), IP: "1.2.3.4", UserAgent: "Googlebot/2.1", Timestamp: time.Now()}
+	cp.processEvents(context.Background(), []*models.ClickEvent{
+		stored,
+		{LinkID: failing, IP: "5.6.7.8", Timestamp: time.Now()},
+	})
+
+	for _, sink := range []*recordingSink{first, second} {
+		if len(sink.events) != 1 || sink.events[0] != stored {
+			t.Fatalf("expected only the stored click to be forwarded, got %d", len(sink.events))
+		}
+		if !sink.enriched[0].IsBot {
+			t.Error("expected enriched fields to be forwarded with the click")
+		}
+	}
+}
+
 func TestProcessEvents_InsertError(t *testing.T) {
 	var incrementCalled bool
 
@@ -465,6 +478,72 @@ func TestProcessEvents_BatchMultipleEvents(t *testing.T) {
 	}
 }
 
+// memoryLastClicks is a lastClickStore without expiry.
+type memoryLastClicks map[string]time.Time
+
+func (m memoryLastClicks) SwapLastClick(_ context.Context, key string, at time.Time, _ time.Duration) (time.Time, bool, error) {
+	prev, found := m[key]
+	m[key] = at
+	return prev, found, nil
+}
+
+func TestProcessEvents_DuplicateClicks(t *testing.T) {
+	inserted := map[string]int{}
+	clickRepo := &mockClickRepo{
+		insertFn: func(_ context.Context, params sqlc.InsertClickParams) error {
+			inserted[params.IpAddress]++
+			return nil
+		},
+	}
+	incrementCount := 0
+	linkRepo := &mockLinkRepo{
+		incrementFn: func(_ context.Context, _ uuid.UUID) error {
+			incrementCount++
+			return nil
+		},
+	}
+
+	logger, _ := zap.NewDevelopment()
+	cp := &ClickProcessor{
+		clickRepo:   clickRepo,
+		linkRepo:    linkRepo,
+		botDetector: redirect.NewBotDetector(),
+		lastClicks:  memoryLastClicks{},
+		logger:      logger,
+	}
+	cp.SetDuplicateClickWindow(2 * time.Second)
+
+	linkID := uuid.New()
+	ua := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+	tap := time.Now()
+	events := []*models.ClickEvent{
+		// A double tap, 80ms apart
+		{LinkID: linkID, ShortCode: "promo", IP: "1.1.1.1", UserAgent: ua, Timestamp: tap},
+		{LinkID: linkID, ShortCode: "promo", IP: "1.1.1.1", UserAgent: ua, Timestamp: tap.Add(80 * time.Millisecond)},
+		// Another visitor at the same moment
+		{LinkID: linkID, ShortCode: "promo", IP: "2.2.2.2", UserAgent: ua, Timestamp: tap.Add(80 * time.Millisecond)},
+		// The first visitor coming back later
+		{LinkID: linkID, ShortCode: "promo", IP: "1.1.1.1", UserAgent: ua, Timestamp: tap.Add(10 * time.Second)},
+	}
+
+	cp.processEvents(context.Background(), events)
+
+	if inserted["1.1.1.1"] != 2 || inserted["2.2.2.2"] != 1 {
+		t.Errorf("inserted clicks = %v, want 2 for the double tapper and 1 for the other visitor", inserted)
+	}
+	if incrementCount != 3 {
+		t.Errorf("expected 3 increments, got %d", incrementCount)
+	}
+
+	// With the window off every click counts
+	cp.SetDuplicateClickWindow(0)
+	incrementCount = 0
+	cp.processEvents(context.Background(), events[:2])
+	if incrementCount != 2 {
+		t.Errorf("expected 2 increments without deduplication, got %d", incrementCount)
+	}
+}
+
 // --- Helper ---
 
 type testError struct {
@@ -472,3 +551,227 @@ type testError struct {
 }
 
 func (e *testError) Error() string { return e.msg }
+
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"203.0.113.42", "203.0.113.0"},
+		{"10.1.2.255", "10.1.2.0"},
+		{"2001:db8:85a3:8d3:1319:8a2e:370:7348", "2001:db8:85a3::"},
+		{"::ffff:198.51.100.7", "198.51.100.0"},
+		{"not-an-ip", "not-an-ip"},
+	}
+
+	for _, tt := range tests {
+		if got := AnonymizeIP(tt.in); got != tt.want {
+			t.Errorf("AnonymizeIP(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestProcessEvents_AnonymizesIP(t *testing.T) {
+	var insertedIP string
+	clickRepo := &mockClickRepo{
+		insertFn: func(_ context.Context, params sqlc.InsertClickParams) error {
+			insertedIP = params.IpAddress
+			return nil
+		},
+	}
+
+	logger, _ := zap.NewDevelopment()
+	cp := &ClickProcessor{
+		clickRepo:   clickRepo,
+		linkRepo:    &mockLinkRepo{},
+		botDetector: redirect.NewBotDetector(),
+		logger:      logger,
+	}
+	cp.SetIPAnonymization(true)
+
+	cp.processEvents(context.Background(), []*models.ClickEvent{
+		{LinkID: uuid.New(), IP: "1.2.3.4", UserAgent: "Mozilla/5.0", Timestamp: time.Now()},
+	})
+
+	if insertedIP != "1.2.3.0" {
+		t.Errorf("expected anonymized IP 1.2.3.0, got %s", insertedIP)
+	}
+}
+
+func TestProcessEvents_SetsVisitorID(t *testing.T) {
+	var visitors []string
+	clickRepo := &mockClickRepo{
+		insertFn: func(_ context.Context, params sqlc.InsertClickParams) error {
+			visitors = append(visitors, params.VisitorID.String)
+			return nil
+		},
+	}
+
+	cp := &ClickProcessor{
+		clickRepo:   clickRepo,
+		linkRepo:    &mockLinkRepo{},
+		botDetector: redirect.NewBotDetector(),
+		logger:      zap.NewNop(),
+	}
+
+	linkID := uuid.New()
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/91.0.4472.124"
+	cp.processEvents(context.Background(), []*models.ClickEvent{
+		{LinkID: linkID, IP: "1.2.3.4", UserAgent: ua, Timestamp: time.Now()},
+		{LinkID: linkID, IP: "1.2.3.4", UserAgent: ua, Timestamp: time.Now()},
+		{LinkID: linkID, IP: "1.2.3.4", UserAgent: "curl/8.0", Timestamp: time.Now()},
+	})
+
+	if len(visitors) != 3 || len(visitors[0]) != 64 {
+		t.Fatalf("visitor IDs = %v", visitors)
+	}
+	if visitors[0] != visitors[1] {
+		t.Error("expected the same visitor ID for the same IP and user agent")
+	}
+	if visitors[0] == visitors[2] {
+		t.Error("expected a different visitor ID for a different user agent")
+	}
+}
+
+func TestReconcileUniqueClicks(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	var gotSince time.Time
+	var gotWindow time.Duration
+	fail := true
+	linkRepo := &mockLinkRepo{
+		reconcileFn: func(_ context.Context, s time.Time, w time.Duration) (int64, error) {
+			gotSince, gotWindow = s, w
+			if fail {
+				return 0, errors.New("db down")
+			}
+			return 3, nil
+		},
+	}
+
+	cp := &ClickProcessor{linkRepo: linkRepo, logger: zap.NewNop()}
+	cp.SetUniqueClickWindow(30 * time.Minute)
+
+	if next := cp.reconcileUniqueClicks(context.Background(), since); !next.Equal(since) {
+		t.Errorf("failed pass should retry from %v, got %v", since, next)
+	}
+	if !gotSince.Equal(since) || gotWindow != 30*time.Minute {
+		t.Errorf("reconciled since %v with window %v", gotSince, gotWindow)
+	}
+
+	fail = false
+	if next := cp.reconcileUniqueClicks(context.Background(), since); !next.After(since) {
+		t.Errorf("successful pass should advance past %v, got %v", since, next)
+	}
+}
+
+func TestAddUnsampledClicks(t *testing.T) {
+	applied, failing := uuid.New(), uuid.New()
+	added := map[uuid.UUID]int64{}
+	linkRepo := &mockLinkRepo{
+		addClicksFn: func(_ context.Context, id uuid.UUID, count int64) error {
+			if id == failing {
+				return errors.New("db down")
+			}
+			added[id] += count
+			return nil
+		},
+	}
+	cp := &ClickProcessor{linkRepo: linkRepo, logger: zap.NewNop()}
+
+	done := cp.addUnsampledClicks(context.Background(), map[string]string{
+		applied.String(): "42",
+		failing.String(): "7",
+		"not-a-uuid":     "3",
+	})
+
+	if added[applied] != 42 {
+		t.Errorf("added %d clicks, want 42", added[applied])
+	}
+	sort.Strings(done)
+	want := []string{applied.String(), "not-a-uuid"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(done, want) {
+		t.Errorf("done = %v, want %v (failed link kept for retry)", done, want)
+	}
+}
+
+func TestProcessEvents_EnrichesWorkspace(t *testing.T) {
+	linkID := uuid.New()
+	workspaceID := uuid.New()
+	lookups := 0
+	linkRepo := &mockLinkRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*models.Link, error) {
+			lookups++
+			return &models.Link{ID: id, WorkspaceID: workspaceID, ShortCode: "abc123"}, nil
+		},
+	}
+
+	cp := &ClickProcessor{
+		clickRepo:   &mockClickRepo{},
+		linkRepo:    linkRepo,
+		botDetector: redirect.NewBotDetector(),
+		logger:      zap.NewNop(),
+	}
+
+	events := []*models.ClickEvent{
+		{LinkID: linkID, IP: "1.2.3.4", Timestamp: time.Now()},
+		{LinkID: linkID, IP: "5.6.7.8", Timestamp: time.Now()},
+	}
+	cp.processEvents(context.Background(), events)
+
+	for i, e := range events {
+		if e.WorkspaceID != workspaceID || e.ShortCode != "abc123" {
+			t.Errorf("event %d = %s/%q, want %s/abc123", i, e.WorkspaceID, e.ShortCode, workspaceID)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("expected 1 lookup for repeated link, got %d", lookups)
+	}
+
+	// Events that already carry their scope are left alone.
+	scoped := &models.ClickEvent{LinkID: uuid.New(), WorkspaceID: uuid.New(), ShortCode: "xyz", Timestamp: time.Now()}
+	cp.processEvents(context.Background(), []*models.ClickEvent{scoped})
+	if lookups != 1 {
+		t.Errorf("expected no lookup for scoped event, got %d", lookups)
+	}
+}
+
+type recordingEvents struct {
+	events []string
+}
+
+func (r *recordingEvents) Publish(_ context.Context, event string, _ uuid.UUID, _ any) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestProcessEvents_QRScan(t *testing.T) {
+	var sources []string
+	clickRepo := &mockClickRepo{
+		insertFn: func(_ context.Context, params sqlc.InsertClickParams) error {
+			sources = append(sources, params.Source.String)
+			return nil
+		},
+	}
+	events := &recordingEvents{}
+	cp := &ClickProcessor{
+		clickRepo:   clickRepo,
+		linkRepo:    &mockLinkRepo{},
+		botDetector: redirect.NewBotDetector(),
+		events:      events,
+		logger:      zap.NewNop(),
+	}
+
+	ua := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+	cp.processEvents(context.Background(), []*models.ClickEvent{
+		{LinkID: uuid.New(), WorkspaceID: uuid.New(), ShortCode: "qr1", IP: "1.2.3.4", UserAgent: ua, Timestamp: time.Now(), Source: models.ClickSourceQR},
+		{LinkID: uuid.New(), WorkspaceID: uuid.New(), ShortCode: "web1", IP: "1.2.3.5", UserAgent: ua, Timestamp: time.Now()},
+	})
+
+	if !reflect.DeepEqual(sources, []string{"qr", ""}) {
+		t.Errorf("stored sources = %q, want [qr \"\"]", sources)
+	}
+	want := []string{"link.clicked", "qr.scanned", "link.clicked"}
+	if !reflect.DeepEqual(events.events, want) {
+		t.Errorf("events = %v, want %v", events.events, want)
+	}
+}
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+// ClickSink receives clicks after they are stored in PostgreSQL. Sinks are
+// best-effort: they log their own errors and must not block the processor
+// for long. enriched[i] holds the parsed fields of events[i].
+type ClickSink interface {
+	ForwardBatch(ctx context.Context, events []*models.ClickEvent, enriched []EnrichedClick)
+}
+
+// MessagePublisher delivers messages to the event bus topic it was created
+// for.
+type MessagePublisher interface {
+	Publish(ctx context.Context, messages []Message) error
+	Close() error
+}
+
+// Message is one event bus message. Key groups related messages, e.g. into
+// one Kafka partition; buses without keys ignore it.
+type Message struct {
+	Key   string
+	Value []byte
+}
+
+// EventBusSink publishes each click as a models.ClickStreamEvent JSON
+// message, keyed by link ID.
+type EventBusSink struct {
+	publisher MessagePublisher
+	logger    *zap.Logger
+}
+
+func NewEventBusSink(publisher MessagePublisher, logger *zap.Logger) *EventBusSink {
+	return &EventBusSink{publisher: publisher, logger: logger}
+}
+
+// ForwardBatch publishes the batch in one call. A failed batch is logged and
+// dropped; PostgreSQL keeps the clicks either way.
+func (s *EventBusSink) ForwardBatch(ctx context.Context, events []*models.ClickEvent, enriched []EnrichedClick) {
+	messages := make([]Message, 0, len(events))
+	for i, event := range events {
+		data, err := json.Marshal(NewClickStreamEvent(event, enriched[i]))
+		if err != nil {
+			s.logger.Warn("failed to encode click stream event", zap.Error(err))
+			continue
+		}
+		messages = append(messages, Message{Key: event.LinkID.String(), Value: data})
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	if err := s.publisher.Publish(ctx, messages); err != nil {
+		s.logger.Warn("failed to publish clicks to event bus",
+			zap.Error(err),
+			zap.Int("count", len(messages)),
+		)
+	}
+}
+
+// NewClickStreamEvent builds the event bus schema for a stored click.
+func NewClickStreamEvent(event *models.ClickEvent, enriched EnrichedClick) models.ClickStreamEvent {
+	return models.ClickStreamEvent{
+		Type:           "click",
+		Version:        models.ClickStreamVersion,
+		LinkID:         event.LinkID,
+		WorkspaceID:    event.WorkspaceID,
+		ShortCode:      event.ShortCode,
+		ClickedAt:      event.Timestamp,
+		IPAddress:      event.IP,
+		UserAgent:      event.UserAgent,
+		Referer:        event.Referer,
+		CountryCode:    enriched.CountryCode,
+		Region:         enriched.Region,
+		City:           enriched.City,
+		Browser:        enriched.Browser,
+		BrowserVersion: enriched.BrowserVersion,
+		OS:             enriched.OS,
+		OSVersion:      enriched.OSVersion,
+		DeviceType:     enriched.DeviceType,
+		IsBot:          enriched.IsBot,
+		Source:         event.Source,
+	}
+}
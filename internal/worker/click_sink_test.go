@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"go.uber.org/zap"
+)
+
+type fakePublisher struct {
+	messages []Message
+	err      error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, messages []Message) error {
+	p.messages = append(p.messages, messages...)
+	return p.err
+}
+
+func (p *fakePublisher) Close() error { return nil }
+
+func TestEventBusSink_ForwardBatch(t *testing.T) {
+	pub := &fakePublisher{}
+	sink := NewEventBusSink(pub, zap.NewNop())
+
+	event := &models.ClickEvent{
+		LinkID:      uuid.New(),
+		WorkspaceID: uuid.New(),
+		ShortCode:   "abc123",
+		IP:          "1.2.3.0",
+		UserAgent:   "Mozilla/5.0",
+		Timestamp:   time.Date(2024, 1, 24, 15, 30, 0, 0, time.UTC),
+		Source:      models.ClickSourceQR,
+	}
+	sink.ForwardBatch(context.Background(), []*models.ClickEvent{event}, []EnrichedClick{{CountryCode: "US", Browser: "Chrome"}})
+
+	if len(pub.messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(pub.messages))
+	}
+	if pub.messages[0].Key != event.LinkID.String() {
+		t.Errorf("Key = %q, want the link ID", pub.messages[0].Key)
+	}
+
+	var got models.ClickStreamEvent
+	if err := json.Unmarshal(pub.messages[0].Value, &got); err != nil {
+		t.Fatalf("message is not JSON: %v", err)
+	}
+	if got.Type != "click" || got.Version != models.ClickStreamVersion {
+		t.Errorf("Type, Version = %q, %d, want click, %d", got.Type, got.Version, models.ClickStreamVersion)
+	}
+	if got.WorkspaceID != event.WorkspaceID || got.ShortCode != "abc123" || !got.ClickedAt.Equal(event.Timestamp) {
+		t.Errorf("event fields not carried over: %+v", got)
+	}
+	if got.CountryCode != "US" || got.Browser != "Chrome" || got.Source != models.ClickSourceQR {
+		t.Errorf("enriched fields not carried over: %+v", got)
+	}
+}
+
+func TestEventBusSink_PublishErrorIsLogged(t *testing.T) {
+	pub := &fakePublisher{err: errors.New("bus unavailable")}
+	sink := NewEventBusSink(pub, zap.NewNop())
+
+	// Should not panic or block
+	sink.ForwardBatch(context.Background(), []*models.ClickEvent{{LinkID: uuid.New()}}, []EnrichedClick{{}})
+}
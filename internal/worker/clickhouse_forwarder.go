@@ -50,8 +50,8 @@ func (f *ClickHouseForwarder) Forward(ctx context.Context, event *models.ClickEv
 		`INSERT INTO clicks (
 			link_id, workspace_id, short_code, clicked_at, ip_address, user_agent, referer,
 			country_code, region, city, browser, browser_version,
-			os, os_version, device_type, is_bot
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			os, os_version, device_type, is_bot, source
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		false,
 		event.LinkID,
 		event.WorkspaceID,
@@ -69,6 +69,7 @@ func (f *ClickHouseForwarder) Forward(ctx context.Context, event *models.ClickEv
 		enriched.OSVersion,
 		enriched.DeviceType,
 		isBot,
+		event.Source,
 	)
 	if err != nil {
 		f.logger.Warn("failed to forward click to ClickHouse",
@@ -88,7 +89,7 @@ func (f *ClickHouseForwarder) ForwardBatch(ctx context.Context, events []*models
 		`INSERT INTO clicks (
 			link_id, workspace_id, short_code, clicked_at, ip_address, user_agent, referer,
 			country_code, region, city, browser, browser_version,
-			os, os_version, device_type, is_bot
+			os, os_version, device_type, is_bot, source
 		)`,
 	)
 	if err != nil {
@@ -120,6 +121,7 @@ func (f *ClickHouseForwarder) ForwardBatch(ctx context.Context, events []*models
 			e.OSVersion,
 			e.DeviceType,
 			isBot,
+			event.Source,
 		); err != nil {
 			f.logger.Warn("failed to append to ClickHouse batch",
 				zap.Error(err),
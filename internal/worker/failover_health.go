@@ -0,0 +1,189 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/redirect"
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/lock"
+	"go.uber.org/zap"
+)
+
+const (
+	failoverLinkBatchSize = 500
+	// failoverCheckWorkers bounds how many destinations are checked at once.
+	failoverCheckWorkers = 8
+	failoverCheckTimeout = 10 * time.Second
+	// failoverHealthIntervals is how many check intervals a result is kept
+	// for. A late run doesn't lose it, but once the checks stop, results
+	// expire and redirects go back to each link's destination.
+	failoverHealthIntervals = 3
+)
+
+// destinationHealthRecorder stores health check results for the redirect
+// service. *redirect.Failover satisfies it.
+type destinationHealthRecorder interface {
+	Record(ctx context.Context, destination string, up bool, ttl time.Duration) error
+}
+
+// FailoverHealthChecker periodically checks whether the destinations and
+// failover URLs of links with failover URLs are up, and records the results
+// for the redirect service, which serves the first one up.
+type FailoverHealthChecker struct {
+	linkRepo repository.LinkRepository
+	prober   redirect.DestinationProber
+	health   destinationHealthRecorder
+	interval time.Duration
+	locker   *lock.Locker
+	logger   *zap.Logger
+	done     chan struct{}
+}
+
+func NewFailoverHealthChecker(linkRepo repository.LinkRepository, prober redirect.DestinationProber, health destinationHealthRecorder, interval time.Duration, logger *zap.Logger) *FailoverHealthChecker {
+	return &FailoverHealthChecker{
+		linkRepo: linkRepo,
+		prober:   prober,
+		health:   health,
+		interval: interval,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+}
+
+// SetLocker makes replicas take turns checking destinations.
+func (c *FailoverHealthChecker) SetLocker(l *lock.Locker) {
+	c.locker = l
+}
+
+// Start checks destinations right away and then every interval.
+func (c *FailoverHealthChecker) Start(ctx context.Context) {
+	c.logger.Info("failover health checker started", zap.Duration("interval", c.interval))
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		runPeriodic(ctx, c.locker, "worker:failover-health", c.interval, c.logger, c.checkAll)
+
+		select {
+		case <-ctx.Done():
+			c.logger.Info("failover health checker shutting down")
+			return
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals the checker to stop.
+func (c *FailoverHealthChecker) Stop() {
+	close(c.done)
+}
+
+// checkAll checks every destination of links with failover URLs once,
+// however many links share it.
+func (c *FailoverHealthChecker) checkAll(ctx context.Context) {
+	destinations, err := c.destinations(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.logger.Error("failed to list links with failover URLs", zap.Error(err))
+		}
+		return
+	}
+
+	ttl := failoverHealthIntervals * c.interval
+	var down atomic.Int64
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, failoverCheckWorkers)
+	for _, destination := range destinations {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			up := c.check(ctx, destination)
+			if ctx.Err() != nil {
+				// A cancelled check says nothing about the destination
+				return
+			}
+			if !up {
+				down.Add(1)
+			}
+			if err := c.health.Record(ctx, destination, up, ttl); err != nil {
+				c.logger.Warn("failed to record destination health", zap.String("url", destination), zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(destinations) > 0 {
+		c.logger.Debug("checked failover destinations",
+			zap.Int("checked", len(destinations)),
+			zap.Int64("down", down.Load()),
+		)
+	}
+}
+
+// destinations returns the web destination and failover URLs of every
+// redirectable link that has failover URLs, without duplicates.
+func (c *FailoverHealthChecker) destinations(ctx context.Context) ([]string, error) {
+	var destinations []string
+	seen := make(map[string]bool)
+	add := func(u string) {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			destinations = append(destinations, u)
+		}
+	}
+
+	afterID := uuid.Nil
+	for {
+		links, err := c.linkRepo.ListWithFailovers(ctx, afterID, failoverLinkBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			// Redirects use the fallback URL over the destination, see
+			// ResolveResult.WebDestination
+			if link.FallbackURL != nil && *link.FallbackURL != "" {
+				add(*link.FallbackURL)
+			} else {
+				add(link.URL)
+			}
+			for _, u := range link.FailoverURLs {
+				add(u)
+			}
+		}
+		if len(links) < failoverLinkBatchSize {
+			return destinations, nil
+		}
+		afterID = links[len(links)-1].ID
+	}
+}
+
+// check reports whether destination is up: it answers, after redirects,
+// with a status below 400, as in the API's destination check.
+func (c *FailoverHealthChecker) check(ctx context.Context, destination string) bool {
+	ctx, cancel := context.WithTimeout(ctx, failoverCheckTimeout)
+	defer cancel()
+
+	res, err := c.prober.Check(ctx, destination)
+	if err != nil {
+		c.logger.Debug("failover destination check failed", zap.String("url", destination), zap.Error(err))
+		return false
+	}
+	return res.StatusCode < 400
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+type stubProber map[string]int
+
+func (p stubProber) Check(_ context.Context, rawURL string) (*httputil.CheckResult, error) {
+	status, ok := p[rawURL]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return &httputil.CheckResult{StatusCode: status, FinalURL: rawURL}, nil
+}
+
+type memoryHealth struct {
+	mu   sync.Mutex
+	up   map[string]bool
+	ttls map[string]time.Duration
+}
+
+func (h *memoryHealth) Record(_ context.Context, destination string, up bool, ttl time.Duration) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.up[destination] = up
+	h.ttls[destination] = ttl
+	return nil
+}
+
+func TestFailoverHealthChecker(t *testing.T) {
+	fallback := "https://example.com/web"
+	links := []*models.Link{
+		{ID: uuid.New(), URL: "https://example.com", FailoverURLs: []string{"https://backup.example.com", "https://gone.example.com"}},
+		{ID: uuid.New(), URL: "https://down.example.com", FailoverURLs: []string{"https://backup.example.com"}},
+		{ID: uuid.New(), URL: "https://app.example.com", FallbackURL: &fallback, FailoverURLs: []string{"https://error.example.com"}},
+	}
+	var calls int
+	repo := &mockLinkRepo{
+		listFailoversFn: func(_ context.Context, afterID uuid.UUID, limit int) ([]*models.Link, error) {
+			calls++
+			if afterID != uuid.Nil {
+				t.Errorf("afterID = %s, want the first page only", afterID)
+			}
+			return links, nil
+		},
+	}
+	prober := stubProber{
+		"https://example.com":        200,
+		"https://backup.example.com": 301,
+		"https://example.com/web":    200,
+		"https://error.example.com":  503,
+	}
+	health := &memoryHealth{up: map[string]bool{}, ttls: map[string]time.Duration{}}
+
+	c := NewFailoverHealthChecker(repo, prober, health, time.Minute, zap.NewNop())
+	c.checkAll(context.Background())
+
+	if calls != 1 {
+		t.Errorf("listed %d pages, want 1", calls)
+	}
+	want := map[string]bool{
+		"https://example.com":        true,
+		"https://backup.example.com": true,
+		"https://gone.example.com":   false,
+		"https://down.example.com":   false,
+		"https://example.com/web":    true,
+		"https://error.example.com":  false,
+	}
+	if len(health.up) != len(want) {
+		t.Errorf("recorded %v, want %v", health.up, want)
+	}
+	for destination, up := range want {
+		got, ok := health.up[destination]
+		if !ok || got != up {
+			t.Errorf("%s: recorded up=%v (%v), want %v", destination, got, ok, up)
+		}
+		if ttl := health.ttls[destination]; ttl != 3*time.Minute {
+			t.Errorf("%s: ttl = %s, want 3m", destination, ttl)
+		}
+	}
+	// The app link's destination is behind its fallback URL
+	if _, ok := health.up["https://app.example.com"]; ok {
+		t.Error("checked the destination of a link with a fallback URL")
+	}
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const kafkaRESTContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaRESTPublisher produces messages to a Kafka topic through a REST proxy
+// that speaks the Confluent REST Proxy v2 API, such as Confluent REST Proxy
+// or Redpanda's HTTP proxy. Message values must be JSON.
+type KafkaRESTPublisher struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewKafkaRESTPublisher(proxyURL, topic string, timeout time.Duration) (*KafkaRESTPublisher, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid Kafka REST proxy URL %q", proxyURL)
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("kafka topic is required")
+	}
+
+	return &KafkaRESTPublisher{
+		endpoint: strings.TrimSuffix(u.String(), "/") + "/topics/" + url.PathEscape(topic),
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+type kafkaRESTRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Publish produces all messages in one request.
+func (p *KafkaRESTPublisher) Publish(ctx context.Context, messages []Message) error {
+	records := make([]kafkaRESTRecord, len(messages))
+	for i, m := range messages {
+		records[i] = kafkaRESTRecord{Key: m.Key, Value: m.Value}
+	}
+	body, err := json.Marshal(map[string]any{"records": records})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaRESTContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("produce to Kafka REST proxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("kafka REST proxy returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return nil
+}
+
+// Close drops the HTTP client's idle connections to the proxy.
+func (p *KafkaRESTPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
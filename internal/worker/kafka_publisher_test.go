@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestKafkaRESTPublisher_Publish(t *testing.T) {
+	var (
+		path, contentType string
+		body              struct {
+			Records []struct {
+				Key   string          `json:"key"`
+				Value json.RawMessage `json:"value"`
+			} `json:"records"`
+		}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"offsets":[]}`))
+	}))
+	defer srv.Close()
+
+	pub, err := NewKafkaRESTPublisher(srv.URL+"/", "linkrift.clicks", time.Second)
+	if err != nil {
+		t.Fatalf("NewKafkaRESTPublisher: %v", err)
+	}
+	err = pub.Publish(context.Background(), []Message{
+		{Key: "a", Value: []byte(`{"n":1}`)},
+		{Key: "b", Value: []byte(`{"n":2}`)},
+	})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if path != "/topics/linkrift.clicks" {
+		t.Errorf("path = %q, want /topics/linkrift.clicks", path)
+	}
+	if contentType != kafkaRESTContentType {
+		t.Errorf("Content-Type = %q, want %q", contentType, kafkaRESTContentType)
+	}
+	if len(body.Records) != 2 || body.Records[1].Key != "b" || string(body.Records[1].Value) != `{"n":2}` {
+		t.Errorf("records = %+v, want both messages", body.Records)
+	}
+}
+
+func TestKafkaRESTPublisher_ProxyError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_code":40401,"message":"Topic not found."}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	pub, _ := NewKafkaRESTPublisher(srv.URL, "missing", time.Second)
+	if err := pub.Publish(context.Background(), []Message{{Value: []byte(`{}`)}}); err == nil {
+		t.Fatal("expected an error for a 404 from the proxy")
+	}
+}
+
+func TestNewKafkaRESTPublisher_InvalidURL(t *testing.T) {
+	if _, err := NewKafkaRESTPublisher("kafka://broker:9092", "clicks", time.Second); err == nil {
+		t.Error("expected an error for a non-HTTP proxy URL")
+	}
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/repository"
+	"github.com/link-rift/link-rift/pkg/lock"
+	"go.uber.org/zap"
+)
+
+// reservationReleaseInterval is how often expired short code reservations
+// are released. Creating a link also frees an expired reservation of its
+// code, so this only bounds how long they show up in link lists.
+const reservationReleaseInterval = 15 * time.Minute
+
+// ReservationReleaser periodically deletes short code reservations that
+// expired without being claimed, freeing their codes.
+type ReservationReleaser struct {
+	linkRepo repository.LinkRepository
+	locker   *lock.Locker
+	logger   *zap.Logger
+	done     chan struct{}
+}
+
+func NewReservationReleaser(linkRepo repository.LinkRepository, logger *zap.Logger) *ReservationReleaser {
+	return &ReservationReleaser{
+		linkRepo: linkRepo,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+}
+
+// SetLocker makes replicas take turns releasing reservations.
+func (r *ReservationReleaser) SetLocker(l *lock.Locker) {
+	r.locker = l
+}
+
+// Start releases expired reservations right away and then every
+// reservationReleaseInterval.
+func (r *ReservationReleaser) Start(ctx context.Context) {
+	r.logger.Info("reservation releaser started", zap.Duration("interval", reservationReleaseInterval))
+
+	ticker := time.NewTicker(reservationReleaseInterval)
+	defer ticker.Stop()
+
+	for {
+		runPeriodic(ctx, r.locker, "worker:link-reservations", reservationReleaseInterval, r.logger, func(ctx context.Context) {
+			n, err := r.linkRepo.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.logger.Error("failed to release expired reservations", zap.Error(err))
+				}
+				return
+			}
+			if n > 0 {
+				r.logger.Info("released expired short code reservations", zap.Int64("count", n))
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("reservation releaser shutting down")
+			return
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals the releaser to stop.
+func (r *ReservationReleaser) Stop() {
+	close(r.done)
+}
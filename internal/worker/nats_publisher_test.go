@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeNATSServer accepts one connection, completes the handshake and sends
+// what the client writes afterwards on received.
+func fakeNATSServer(t *testing.T, authErr string) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte(`INFO {"server_id":"test","max_payload":1048576}` + "\r\n"))
+
+		r := bufio.NewReader(conn)
+		connect, _ := r.ReadString('\n')
+		r.ReadString('\n') // PING
+		if authErr != "" {
+			conn.Write([]byte("-ERR '" + authErr + "'\r\n"))
+			return
+		}
+		conn.Write([]byte("PONG\r\n"))
+
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		rest, _ := io.ReadAll(r)
+		received <- connect + string(rest)
+	}()
+	return "nats://" + ln.Addr().String(), received
+}
+
+func TestNATSPublisher_Publish(t *testing.T) {
+	url, received := fakeNATSServer(t, "")
+	pub, err := NewNATSPublisher(strings.Replace(url, "nats://", "nats://svc:secret@", 1), "linkrift.clicks", time.Second, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewNATSPublisher: %v", err)
+	}
+
+	err = pub.Publish(context.Background(), []Message{{Value: []byte(`{"n":1}`)}, {Value: []byte(`{"n":22}`)}})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	pub.Close()
+
+	got := <-received
+	if !strings.Contains(got, `"user":"svc"`) || !strings.Contains(got, `"pass":"secret"`) {
+		t.Errorf("CONNECT did not carry the URL credentials: %q", got)
+	}
+	want := "PUB linkrift.clicks 7\r\n{\"n\":1}\r\nPUB linkrift.clicks 8\r\n{\"n\":22}\r\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("published %q, want %q", got, want)
+	}
+}
+
+func TestNATSPublisher_AuthorizationError(t *testing.T) {
+	url, _ := fakeNATSServer(t, "Authorization Violation")
+	pub, err := NewNATSPublisher(url, "linkrift.clicks", time.Second, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewNATSPublisher: %v", err)
+	}
+
+	err = pub.Publish(context.Background(), []Message{{Value: []byte(`{}`)}})
+	if err == nil || !strings.Contains(err.Error(), "Authorization Violation") {
+		t.Errorf("Publish() = %v, want the server's authorization error", err)
+	}
+}
+
+func TestNewNATSPublisher_Invalid(t *testing.T) {
+	if _, err := NewNATSPublisher("http://localhost:4222", "clicks", time.Second, zap.NewNop()); err == nil {
+		t.Error("expected an error for a non-NATS URL")
+	}
+	if _, err := NewNATSPublisher("nats://localhost", "link clicks", time.Second, zap.NewNop()); err == nil {
+		t.Error("expected an error for a subject with spaces")
+	}
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/link-rift/link-rift/pkg/lock"
+	"go.uber.org/zap"
+)
+
+// periodicJobLockTTL is how long a periodic job's lock outlives a worker
+// that dies mid-run; live runs keep renewing it.
+const periodicJobLockTTL = 30 * time.Second
+
+// runPeriodic runs one tick of a periodic job. With a locker, only one
+// worker replica runs each period: the first to claim it, and only once the
+// previous run, on whichever replica, has finished. fn's context is
+// cancelled if the lock is lost. Without a locker fn always runs.
+func runPeriodic(ctx context.Context, locker *lock.Locker, name string, period time.Duration, logger *zap.Logger, fn func(context.Context)) {
+	if locker == nil {
+		fn(ctx)
+		return
+	}
+
+	claimed, err := locker.Claim(ctx, name, period, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("failed to claim periodic job, skipping", zap.String("job", name), zap.Error(err))
+		}
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	m, err := locker.TryAcquire(ctx, name, periodicJobLockTTL)
+	if err != nil {
+		if errors.Is(err, lock.ErrNotAcquired) {
+			logger.Warn("previous run of periodic job still in progress, skipping", zap.String("job", name))
+		} else if ctx.Err() == nil {
+			logger.Warn("failed to lock periodic job, skipping", zap.String("job", name), zap.Error(err))
+		}
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-m.Lost():
+			logger.Warn("lost lock of periodic job, stopping it", zap.String("job", name))
+			cancel()
+		case <-jobCtx.Done():
+		}
+	}()
+
+	fn(jobCtx)
+
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer releaseCancel()
+	if err := m.Release(releaseCtx); err != nil && !errors.Is(err, lock.ErrNotHeld) {
+		logger.Warn("failed to release periodic job lock", zap.String("job", name), zap.Error(err))
+	}
+}
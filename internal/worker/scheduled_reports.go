@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/lock"
+	"go.uber.org/zap"
+)
+
+// scheduledReportInterval is how often due reports are looked for, so
+// reports go out within a minute of their scheduled time.
+const scheduledReportInterval = time.Minute
+
+// ScheduledReportRunner periodically sends scheduled analytics reports that
+// are due.
+type ScheduledReportRunner struct {
+	reports *service.ScheduledReportSender
+	locker  *lock.Locker
+	logger  *zap.Logger
+	done    chan struct{}
+}
+
+func NewScheduledReportRunner(reports *service.ScheduledReportSender, logger *zap.Logger) *ScheduledReportRunner {
+	return &ScheduledReportRunner{
+		reports: reports,
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+}
+
+// SetLocker makes replicas take turns, so each report is sent once.
+func (r *ScheduledReportRunner) SetLocker(l *lock.Locker) {
+	r.locker = l
+}
+
+// Start sends due reports right away and then every minute.
+func (r *ScheduledReportRunner) Start(ctx context.Context) {
+	r.logger.Info("scheduled report runner started")
+
+	ticker := time.NewTicker(scheduledReportInterval)
+	defer ticker.Stop()
+
+	for {
+		runPeriodic(ctx, r.locker, "worker:scheduled-reports", scheduledReportInterval, r.logger, func(ctx context.Context) {
+			if err := r.reports.SendDue(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Error("failed to send scheduled reports", zap.Error(err))
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("scheduled report runner shutting down")
+			return
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals the runner to stop.
+func (r *ScheduledReportRunner) Stop() {
+	close(r.done)
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/lock"
+	"go.uber.org/zap"
+)
+
+// UsageSnapshotter periodically records every workspace's monthly usage, so
+// billing integrations can read metered usage from usage_snapshots.
+type UsageSnapshotter struct {
+	usage    service.UsageService
+	interval time.Duration
+	locker   *lock.Locker
+	logger   *zap.Logger
+	done     chan struct{}
+}
+
+func NewUsageSnapshotter(usage service.UsageService, interval time.Duration, logger *zap.Logger) *UsageSnapshotter {
+	return &UsageSnapshotter{
+		usage:    usage,
+		interval: interval,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+}
+
+// SetLocker makes replicas take turns, so each interval is snapshotted once.
+func (s *UsageSnapshotter) SetLocker(l *lock.Locker) {
+	s.locker = l
+}
+
+// Start takes a snapshot right away and then every interval.
+func (s *UsageSnapshotter) Start(ctx context.Context) {
+	s.logger.Info("usage snapshotter started", zap.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		runPeriodic(ctx, s.locker, "worker:usage-snapshot", s.interval, s.logger, func(ctx context.Context) {
+			if err := s.usage.SnapshotAll(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("failed to snapshot usage", zap.Error(err))
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("usage snapshotter shutting down")
+			return
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals the snapshotter to stop.
+func (s *UsageSnapshotter) Stop() {
+	close(s.done)
+}
@@ -3,13 +3,13 @@ package worker
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,17 +17,25 @@ import (
 	"github.com/link-rift/link-rift/internal/models"
 	"github.com/link-rift/link-rift/internal/repository"
 	"github.com/link-rift/link-rift/internal/repository/sqlc"
+	"github.com/link-rift/link-rift/pkg/crypto"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"github.com/link-rift/link-rift/pkg/lock"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 const (
 	webhookDeliveryQueue  = "webhook:delivery:queue"
+	webhookDLQPrefix      = "webhook:delivery:dlq:"
+	maxDeadLetters        = 1000
 	maxWebhookAttempts    = 5
 	maxFailuresPerDay     = 10
 	retryPollInterval     = 30 * time.Second
 	webhookRequestTimeout = 10 * time.Second
 	maxResponseBodyLen    = 4096
+	// maxRetryAfter caps how long a receiver's Retry-After can hold back
+	// the next attempt.
+	maxRetryAfter = time.Hour
 )
 
 // WebhookDeliveryProcessor processes webhook events from the Redis queue.
@@ -35,6 +43,7 @@ type WebhookDeliveryProcessor struct {
 	redis       *redis.Client
 	webhookRepo repository.WebhookRepository
 	httpClient  *http.Client
+	locker      *lock.Locker
 	logger      *zap.Logger
 	done        chan struct{}
 }
@@ -42,19 +51,33 @@ type WebhookDeliveryProcessor struct {
 func NewWebhookDeliveryProcessor(
 	redisClient *redis.Client,
 	webhookRepo repository.WebhookRepository,
+	hostPolicy *httputil.HostPolicy,
 	logger *zap.Logger,
 ) *WebhookDeliveryProcessor {
+	// Targets are re-checked at dial time so redirects or DNS changes after
+	// registration can't reach internal addresses.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = hostPolicy.DialContext(&net.Dialer{Timeout: webhookRequestTimeout})
+	transport.Proxy = nil // a proxy would bypass the dial-time address check
+
 	return &WebhookDeliveryProcessor{
 		redis:       redisClient,
 		webhookRepo: webhookRepo,
 		httpClient: &http.Client{
-			Timeout: webhookRequestTimeout,
+			Timeout:   webhookRequestTimeout,
+			Transport: transport,
 		},
 		logger: logger,
 		done:   make(chan struct{}),
 	}
 }
 
+// SetLocker makes replicas take turns retrying pending deliveries, so a
+// delivery isn't retried by several replicas at once.
+func (p *WebhookDeliveryProcessor) SetLocker(l *lock.Locker) {
+	p.locker = l
+}
+
 // Start begins processing webhook delivery events.
 func (p *WebhookDeliveryProcessor) Start(ctx context.Context) {
 	p.logger.Info("webhook delivery processor started")
@@ -115,13 +138,8 @@ func (p *WebhookDeliveryProcessor) processEvent(ctx context.Context, event *mode
 	}
 
 	for _, webhook := range webhooks {
-		// Build delivery payload
-		payload, err := json.Marshal(map[string]any{
-			"event":       event.Event,
-			"workspace_id": event.WorkspaceID,
-			"timestamp":   event.Timestamp,
-			"data":        json.RawMessage(event.Data),
-		})
+		// Build delivery payload in the version the webhook expects
+		payload, err := json.Marshal(buildPayloadEnvelope(event, webhook.EffectivePayloadVersion()))
 		if err != nil {
 			p.logger.Error("failed to marshal delivery payload", zap.Error(err))
 			continue
@@ -146,23 +164,22 @@ func (p *WebhookDeliveryProcessor) processEvent(ctx context.Context, event *mode
 
 func (p *WebhookDeliveryProcessor) deliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery, payload []byte) {
 	deliveryID := delivery.ID
-	timestamp := fmt.Sprintf("%d", time.Now().Unix())
-
-	// HMAC-SHA256 signature
-	signature := signPayload(webhook.Secret, payload, timestamp)
+	now := time.Now()
+	timestamp := fmt.Sprintf("%d", now.Unix())
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
 	if err != nil {
 		p.logger.Error("failed to create webhook request", zap.Error(err))
-		p.recordFailure(ctx, webhook.ID, deliveryID, 1, 0, "failed to create request: "+err.Error())
+		p.recordFailure(ctx, webhook.ID, deliveryID, 1, 0, "failed to create request: "+err.Error(), pgtype.Timestamptz{})
 		return
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Linkrift-Signature", signature)
+	setSignatures(req, webhook, payload, timestamp, now)
 	req.Header.Set("X-Linkrift-Timestamp", timestamp)
 	req.Header.Set("X-Linkrift-Event", delivery.Event)
 	req.Header.Set("X-Linkrift-Delivery", deliveryID.String())
+	req.Header.Set("X-Linkrift-Version", strconv.Itoa(int(webhook.EffectivePayloadVersion())))
 	req.Header.Set("User-Agent", "Linkrift-Webhooks/1.0")
 
 	resp, err := p.httpClient.Do(req)
@@ -172,7 +189,7 @@ func (p *WebhookDeliveryProcessor) deliver(ctx context.Context, webhook *models.
 			zap.String("delivery_id", deliveryID.String()),
 			zap.Error(err),
 		)
-		p.recordFailure(ctx, webhook.ID, deliveryID, 1, 0, "request failed: "+err.Error())
+		p.recordFailure(ctx, webhook.ID, deliveryID, 1, 0, "request failed: "+err.Error(), pgtype.Timestamptz{})
 		return
 	}
 	defer resp.Body.Close()
@@ -190,7 +207,7 @@ func (p *WebhookDeliveryProcessor) deliver(ctx context.Context, webhook *models.
 			zap.String("webhook_id", webhook.ID.String()),
 			zap.Int("status", resp.StatusCode),
 		)
-		p.recordFailure(ctx, webhook.ID, deliveryID, 1, int32(resp.StatusCode), respBody)
+		p.recordFailure(ctx, webhook.ID, deliveryID, 1, int32(resp.StatusCode), respBody, nextRetryAt(resp, time.Now()))
 	}
 }
 
@@ -211,7 +228,9 @@ func (p *WebhookDeliveryProcessor) recordSuccess(ctx context.Context, webhookID,
 	}
 }
 
-func (p *WebhookDeliveryProcessor) recordFailure(ctx context.Context, webhookID, deliveryID uuid.UUID, attempts int32, statusCode int32, body string) {
+// recordFailure stores a failed attempt. nextRetry, when set, is when the
+// delivery may be retried; otherwise it waits for retryPollInterval.
+func (p *WebhookDeliveryProcessor) recordFailure(ctx context.Context, webhookID, deliveryID uuid.UUID, attempts int32, statusCode int32, body string, nextRetry pgtype.Timestamptz) {
 	respStatus := pgtype.Int4{}
 	if statusCode > 0 {
 		respStatus = pgtype.Int4{Int32: statusCode, Valid: true}
@@ -223,6 +242,7 @@ func (p *WebhookDeliveryProcessor) recordFailure(ctx context.Context, webhookID,
 		ResponseBody:   pgtype.Text{String: body, Valid: body != ""},
 		Attempts:       attempts,
 		CompletedAt:    pgtype.Timestamptz{}, // not completed yet if retries remain
+		NextRetryAt:    nextRetry,
 	}); err != nil {
 		p.logger.Error("failed to update webhook delivery", zap.Error(err))
 	}
@@ -255,7 +275,7 @@ func (p *WebhookDeliveryProcessor) retryLoop(ctx context.Context) {
 		case <-p.done:
 			return
 		case <-ticker.C:
-			p.retryPendingDeliveries(ctx)
+			runPeriodic(ctx, p.locker, "worker:webhook-retry", retryPollInterval, p.logger, p.retryPendingDeliveries)
 		}
 	}
 }
@@ -278,14 +298,8 @@ func (p *WebhookDeliveryProcessor) retryPendingDeliveries(ctx context.Context) {
 		}
 
 		if !webhook.IsActive {
-			// Mark as completed (failed) if webhook is disabled
-			now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
-			p.webhookRepo.UpdateDelivery(ctx, sqlc.UpdateWebhookDeliveryParams{
-				ID:          delivery.ID,
-				Attempts:    delivery.Attempts,
-				CompletedAt: now,
-				ResponseBody: pgtype.Text{String: "webhook disabled", Valid: true},
-			})
+			// Mark as completed (failed) and dead-letter it if webhook is disabled
+			p.deadLetter(ctx, webhook, delivery, delivery.Attempts, 0, "webhook disabled", models.WebhookDeadLetterWebhookDisabled)
 			continue
 		}
 
@@ -294,43 +308,33 @@ func (p *WebhookDeliveryProcessor) retryPendingDeliveries(ctx context.Context) {
 }
 
 func (p *WebhookDeliveryProcessor) retryDeliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) {
-	timestamp := fmt.Sprintf("%d", time.Now().Unix())
-	signature := signPayload(webhook.Secret, delivery.Payload, timestamp)
+	now := time.Now()
+	timestamp := fmt.Sprintf("%d", now.Unix())
 	attempts := delivery.Attempts + 1
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
 	if err != nil {
-		p.recordFailure(ctx, webhook.ID, delivery.ID, attempts, 0, "failed to create request: "+err.Error())
+		p.recordFailure(ctx, webhook.ID, delivery.ID, attempts, 0, "failed to create request: "+err.Error(), pgtype.Timestamptz{})
 		if attempts >= delivery.MaxAttempts {
-			now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
-			p.webhookRepo.UpdateDelivery(ctx, sqlc.UpdateWebhookDeliveryParams{
-				ID:          delivery.ID,
-				Attempts:    attempts,
-				CompletedAt: now,
-			})
+			p.deadLetter(ctx, webhook, delivery, attempts, 0, "failed to create request: "+err.Error(), models.WebhookDeadLetterAttemptsExhausted)
 		}
 		return
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Linkrift-Signature", signature)
+	setSignatures(req, webhook, delivery.Payload, timestamp, now)
 	req.Header.Set("X-Linkrift-Timestamp", timestamp)
 	req.Header.Set("X-Linkrift-Event", delivery.Event)
 	req.Header.Set("X-Linkrift-Delivery", delivery.ID.String())
+	req.Header.Set("X-Linkrift-Version", strconv.Itoa(int(webhook.EffectivePayloadVersion())))
 	req.Header.Set("User-Agent", "Linkrift-Webhooks/1.0")
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
 		if attempts >= delivery.MaxAttempts {
-			now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
-			p.webhookRepo.UpdateDelivery(ctx, sqlc.UpdateWebhookDeliveryParams{
-				ID:             delivery.ID,
-				ResponseBody:   pgtype.Text{String: "request failed: " + err.Error(), Valid: true},
-				Attempts:       attempts,
-				CompletedAt:    now,
-			})
+			p.deadLetter(ctx, webhook, delivery, attempts, 0, "request failed: "+err.Error(), models.WebhookDeadLetterAttemptsExhausted)
 		} else {
-			p.recordFailure(ctx, webhook.ID, delivery.ID, attempts, 0, "request failed: "+err.Error())
+			p.recordFailure(ctx, webhook.ID, delivery.ID, attempts, 0, "request failed: "+err.Error(), pgtype.Timestamptz{})
 		}
 		return
 	}
@@ -343,24 +347,132 @@ func (p *WebhookDeliveryProcessor) retryDeliver(ctx context.Context, webhook *mo
 		p.recordSuccess(ctx, webhook.ID, delivery.ID, attempts, int32(resp.StatusCode), respBody)
 	} else {
 		if attempts >= delivery.MaxAttempts {
-			now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
-			p.webhookRepo.UpdateDelivery(ctx, sqlc.UpdateWebhookDeliveryParams{
-				ID:             delivery.ID,
-				ResponseStatus: pgtype.Int4{Int32: int32(resp.StatusCode), Valid: true},
-				ResponseBody:   pgtype.Text{String: respBody, Valid: respBody != ""},
-				Attempts:       attempts,
-				CompletedAt:    now,
-			})
+			p.deadLetter(ctx, webhook, delivery, attempts, int32(resp.StatusCode), respBody, models.WebhookDeadLetterAttemptsExhausted)
 			p.webhookRepo.IncrementFailureCount(ctx, webhook.ID)
 		} else {
-			p.recordFailure(ctx, webhook.ID, delivery.ID, attempts, int32(resp.StatusCode), respBody)
+			p.recordFailure(ctx, webhook.ID, delivery.ID, attempts, int32(resp.StatusCode), respBody, nextRetryAt(resp, time.Now()))
 		}
 	}
 }
 
+// deadLetter marks a delivery as permanently failed and pushes it onto its
+// workspace's dead-letter queue, which keeps the newest maxDeadLetters.
+func (p *WebhookDeliveryProcessor) deadLetter(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery, attempts, statusCode int32, body, reason string) {
+	respStatus := pgtype.Int4{}
+	if statusCode > 0 {
+		respStatus = pgtype.Int4{Int32: statusCode, Valid: true}
+	}
+	now := time.Now()
+	if err := p.webhookRepo.UpdateDelivery(ctx, sqlc.UpdateWebhookDeliveryParams{
+		ID:             delivery.ID,
+		ResponseStatus: respStatus,
+		ResponseBody:   pgtype.Text{String: body, Valid: body != ""},
+		Attempts:       attempts,
+		CompletedAt:    pgtype.Timestamptz{Time: now, Valid: true},
+	}); err != nil {
+		p.logger.Error("failed to update webhook delivery", zap.Error(err))
+	}
+
+	entry := models.WebhookDeadLetter{
+		DeliveryID:  delivery.ID,
+		WebhookID:   webhook.ID,
+		WorkspaceID: webhook.WorkspaceID,
+		URL:         webhook.URL,
+		Event:       delivery.Event,
+		Payload:     delivery.Payload,
+		Attempts:    attempts,
+		LastError:   body,
+		Reason:      reason,
+		FailedAt:    now.UTC(),
+	}
+	if statusCode > 0 {
+		entry.ResponseStatus = &statusCode
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		p.logger.Error("failed to marshal dead-lettered webhook delivery", zap.Error(err))
+		return
+	}
+
+	key := webhookDLQPrefix + webhook.WorkspaceID.String()
+	pipe := p.redis.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, maxDeadLetters-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		p.logger.Error("failed to dead-letter webhook delivery",
+			zap.String("delivery_id", delivery.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	p.logger.Warn("webhook delivery dead-lettered",
+		zap.String("webhook_id", webhook.ID.String()),
+		zap.String("delivery_id", delivery.ID.String()),
+		zap.String("reason", reason),
+		zap.Int32("attempts", attempts),
+	)
+}
+
+// nextRetryAt is when a delivery answered with resp may be retried. Only
+// 429 and 503 responses are considered, and only with a usable Retry-After;
+// otherwise it is unset.
+func nextRetryAt(resp *http.Response, now time.Time) pgtype.Timestamptz {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return pgtype.Timestamptz{}
+	}
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	if !ok {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: now.Add(delay), Valid: true}
+}
+
+// parseRetryAfter reads a Retry-After value, either delay-seconds or an
+// HTTP-date, as a delay from now, capped at maxRetryAfter. A date in the
+// past means no delay.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		if secs > int64(maxRetryAfter/time.Second) {
+			return maxRetryAfter, true
+		}
+		delay = time.Duration(secs) * time.Second
+	} else {
+		t, err := http.ParseTime(value)
+		if err != nil {
+			return 0, false
+		}
+		delay = t.Sub(now)
+	}
+
+	if delay < 0 {
+		return 0, true
+	}
+	if delay > maxRetryAfter {
+		return maxRetryAfter, true
+	}
+	return delay, true
+}
+
+// setSignatures adds an HMAC-SHA256 X-Linkrift-Signature header for each of
+// the webhook's signing secrets, the current secret first. Receivers accept a
+// delivery if any of the values matches.
+func setSignatures(req *http.Request, webhook *models.Webhook, payload []byte, timestamp string, now time.Time) {
+	for _, secret := range webhook.SigningSecrets(now) {
+		req.Header.Add("X-Linkrift-Signature", signPayload(secret, payload, timestamp))
+	}
+}
+
 func signPayload(secret string, payload []byte, timestamp string) string {
 	message := fmt.Sprintf("%s.%s", timestamp, string(payload))
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write([]byte(message))
-	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+	return "v1=" + crypto.SignHMAC(secret, []byte(message))
 }
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/models"
+	"github.com/link-rift/link-rift/pkg/httputil"
+	"go.uber.org/zap"
+)
+
+func TestParseRetryAfter_Seconds(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"86400", maxRetryAfter, true},
+		{"99999999999999999", maxRetryAfter, true},
+		{"-5", 0, false},
+		{"1.5", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"Sun, 01 Mar 2026 12:05:00 GMT", 5 * time.Minute, true},
+		{"Sunday, 01-Mar-26 12:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 01 Mar 2026 11:00:00 GMT", 0, true},
+		{"Mon, 02 Mar 2026 12:00:00 GMT", maxRetryAfter, true},
+		{"tomorrow", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNextRetryAt(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	got := nextRetryAt(response(http.StatusTooManyRequests, "60"), now)
+	if !got.Valid || !got.Time.Equal(now.Add(time.Minute)) {
+		t.Errorf("429 with Retry-After: got %+v, want %v", got, now.Add(time.Minute))
+	}
+
+	got = nextRetryAt(response(http.StatusServiceUnavailable, "Sun, 01 Mar 2026 12:10:00 GMT"), now)
+	if !got.Valid || !got.Time.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("503 with Retry-After date: got %+v, want %v", got, now.Add(10*time.Minute))
+	}
+
+	if got := nextRetryAt(response(http.StatusInternalServerError, "60"), now); got.Valid {
+		t.Errorf("500 should use the fixed interval, got %v", got.Time)
+	}
+	if got := nextRetryAt(response(http.StatusTooManyRequests, ""), now); got.Valid {
+		t.Errorf("429 without Retry-After should use the fixed interval, got %v", got.Time)
+	}
+}
+
+func TestSetSignatures_DualSignWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"event":"link.created"}`)
+	expires := now.Add(time.Hour)
+	webhook := &models.Webhook{Secret: "whsec_new", PreviousSecret: "whsec_old", PreviousSecretExpiresAt: &expires}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/hook", nil)
+	setSignatures(req, webhook, payload, "1772366400", now)
+	got := req.Header.Values("X-Linkrift-Signature")
+	want := []string{signPayload("whsec_new", payload, "1772366400"), signPayload("whsec_old", payload, "1772366400")}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("signatures in window = %v, want %v", got, want)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "https://example.com/hook", nil)
+	setSignatures(req, webhook, payload, "1772366400", expires)
+	if got := req.Header.Values("X-Linkrift-Signature"); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("signatures after window = %v, want only the new secret's", got)
+	}
+}
+
+func TestWebhookDeliveryIgnoresProxy(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://proxy.internal:3128")
+	t.Setenv("HTTP_PROXY", "http://proxy.internal:3128")
+
+	policy, err := httputil.NewHostPolicy(false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewWebhookDeliveryProcessor(nil, nil, policy, zap.NewNop())
+
+	// Deliveries must dial targets directly for the address check to apply
+	transport := p.httpClient.Transport.(*http.Transport)
+	if transport.Proxy != nil {
+		t.Error("webhook transport uses a proxy")
+	}
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+// payloadEnvelope is the JSON object delivered to webhook receivers.
+type payloadEnvelope map[string]any
+
+// payloadDowngrades converts an envelope of version N+1 into version N, keyed
+// by the target version N. Add an entry here whenever the envelope or event
+// data shape changes, and bump models.CurrentWebhookPayloadVersion.
+var payloadDowngrades = map[int32]func(payloadEnvelope) payloadEnvelope{
+	// v1 is the original envelope, which carried no version field.
+	1: func(env payloadEnvelope) payloadEnvelope {
+		delete(env, "version")
+		return env
+	},
+}
+
+// buildPayloadEnvelope builds the current-version envelope for an event and
+// downgrades it step by step to the requested version.
+func buildPayloadEnvelope(event *models.WebhookEvent, version int32) payloadEnvelope {
+	env := payloadEnvelope{
+		"version":      models.CurrentWebhookPayloadVersion,
+		"event":        event.Event,
+		"workspace_id": event.WorkspaceID,
+		"timestamp":    event.Timestamp,
+		"data":         event.Data,
+	}
+
+	for v := int32(models.CurrentWebhookPayloadVersion) - 1; v >= version; v-- {
+		if downgrade, ok := payloadDowngrades[v]; ok {
+			env = downgrade(env)
+		}
+	}
+	return env
+}
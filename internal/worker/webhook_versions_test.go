@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/models"
+)
+
+func TestBuildPayloadEnvelope_CurrentVersion(t *testing.T) {
+	event := &models.WebhookEvent{
+		Event:       "link.created",
+		WorkspaceID: uuid.New(),
+		Timestamp:   time.Now(),
+		Data:        json.RawMessage(`{"id":"abc"}`),
+	}
+
+	env := buildPayloadEnvelope(event, models.CurrentWebhookPayloadVersion)
+	if env["version"] != models.CurrentWebhookPayloadVersion {
+		t.Errorf("expected version %d, got %v", models.CurrentWebhookPayloadVersion, env["version"])
+	}
+	if env["event"] != "link.created" {
+		t.Errorf("expected event link.created, got %v", env["event"])
+	}
+}
+
+func TestBuildPayloadEnvelope_PinnedV1(t *testing.T) {
+	event := &models.WebhookEvent{
+		Event:       "link.clicked",
+		WorkspaceID: uuid.New(),
+		Timestamp:   time.Now(),
+		Data:        json.RawMessage(`{"short_code":"abc"}`),
+	}
+
+	data, err := json.Marshal(buildPayloadEnvelope(event, 1))
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if _, ok := decoded["version"]; ok {
+		t.Error("v1 payload should not include a version field")
+	}
+	for _, key := range []string{"event", "workspace_id", "timestamp", "data"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("v1 payload missing %q", key)
+		}
+	}
+}
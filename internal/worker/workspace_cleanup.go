@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/link-rift/link-rift/pkg/lock"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	workspaceCleanupQueue = "workspace:cleanup:queue"
+
+	// workspaceCleanupSweepInterval is how often deleted workspaces whose
+	// cleanup didn't finish, or was never queued, are retried.
+	workspaceCleanupSweepInterval = 15 * time.Minute
+	// workspaceCleanupSweepBatch caps the workspaces retried per sweep.
+	workspaceCleanupSweepBatch = 20
+)
+
+// WorkspaceCleanupProcessor cleans up deleted workspaces as they are queued,
+// and periodically sweeps for any that are still pending.
+type WorkspaceCleanupProcessor struct {
+	redis   *redis.Client
+	cleaner *service.WorkspaceCleaner
+	locker  *lock.Locker
+	logger  *zap.Logger
+	done    chan struct{}
+}
+
+func NewWorkspaceCleanupProcessor(
+	redisClient *redis.Client,
+	cleaner *service.WorkspaceCleaner,
+	logger *zap.Logger,
+) *WorkspaceCleanupProcessor {
+	return &WorkspaceCleanupProcessor{
+		redis:   redisClient,
+		cleaner: cleaner,
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+}
+
+// SetLocker makes replicas take turns running the sweep.
+func (p *WorkspaceCleanupProcessor) SetLocker(l *lock.Locker) {
+	p.locker = l
+}
+
+// Start begins processing queued cleanups and sweeping for pending ones.
+func (p *WorkspaceCleanupProcessor) Start(ctx context.Context) {
+	p.logger.Info("workspace cleanup processor started")
+
+	go p.sweep(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("workspace cleanup processor shutting down")
+			return
+		case <-p.done:
+			return
+		default:
+			p.processQueue(ctx)
+		}
+	}
+}
+
+// Stop signals the processor to stop.
+func (p *WorkspaceCleanupProcessor) Stop() {
+	close(p.done)
+}
+
+func (p *WorkspaceCleanupProcessor) processQueue(ctx context.Context) {
+	result, err := p.redis.BLPop(ctx, 2*time.Second, workspaceCleanupQueue).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		p.logger.Error("failed to pop from workspace cleanup queue", zap.Error(err))
+		time.Sleep(1 * time.Second)
+		return
+	}
+
+	workspaceID, err := uuid.Parse(result[1])
+	if err != nil {
+		p.logger.Warn("invalid workspace ID in cleanup queue", zap.String("value", result[1]))
+		return
+	}
+
+	// Run logs what it did; a failed cleanup stays pending for the sweep.
+	_ = p.cleaner.Run(ctx, workspaceID)
+}
+
+func (p *WorkspaceCleanupProcessor) sweep(ctx context.Context) {
+	ticker := time.NewTicker(workspaceCleanupSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		runPeriodic(ctx, p.locker, "worker:workspace-cleanup", workspaceCleanupSweepInterval, p.logger, func(ctx context.Context) {
+			if err := p.cleaner.RunPending(ctx, workspaceCleanupSweepBatch); err != nil && ctx.Err() == nil {
+				p.logger.Error("failed to sweep deleted workspaces", zap.Error(err))
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/link-rift/link-rift/internal/service"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const workspaceExportQueue = "workspace:export:queue"
+
+// WorkspaceExportProcessor builds queued workspace export archives.
+type WorkspaceExportProcessor struct {
+	redis    *redis.Client
+	exporter *service.WorkspaceExporter
+	logger   *zap.Logger
+	done     chan struct{}
+}
+
+func NewWorkspaceExportProcessor(
+	redisClient *redis.Client,
+	exporter *service.WorkspaceExporter,
+	logger *zap.Logger,
+) *WorkspaceExportProcessor {
+	return &WorkspaceExportProcessor{
+		redis:    redisClient,
+		exporter: exporter,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start begins processing workspace export jobs. Exports are built one at a
+// time since each one reads the whole workspace.
+func (p *WorkspaceExportProcessor) Start(ctx context.Context) {
+	p.logger.Info("workspace export processor started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("workspace export processor shutting down")
+			return
+		case <-p.done:
+			return
+		default:
+			p.processQueue(ctx)
+		}
+	}
+}
+
+// Stop signals the processor to stop.
+func (p *WorkspaceExportProcessor) Stop() {
+	close(p.done)
+}
+
+func (p *WorkspaceExportProcessor) processQueue(ctx context.Context) {
+	result, err := p.redis.BLPop(ctx, 2*time.Second, workspaceExportQueue).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		p.logger.Error("failed to pop from workspace export queue", zap.Error(err))
+		time.Sleep(1 * time.Second)
+		return
+	}
+
+	exportID, err := uuid.Parse(result[1])
+	if err != nil {
+		p.logger.Warn("invalid workspace export ID in queue", zap.String("value", result[1]))
+		return
+	}
+
+	if err := p.exporter.Run(ctx, exportID); err != nil {
+		p.logger.Warn("workspace export did not complete",
+			zap.String("export_id", exportID.String()),
+			zap.Error(err),
+		)
+	}
+}
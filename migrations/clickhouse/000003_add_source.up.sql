@@ -0,0 +1 @@
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS source LowCardinality(String) DEFAULT '';
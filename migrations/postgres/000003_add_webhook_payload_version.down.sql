@@ -0,0 +1,2 @@
+ALTER TABLE webhooks
+    DROP COLUMN IF EXISTS payload_version;
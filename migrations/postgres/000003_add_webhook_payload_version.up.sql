@@ -0,0 +1,3 @@
+-- NULL means the webhook always receives the latest payload version.
+ALTER TABLE webhooks
+    ADD COLUMN payload_version INTEGER;
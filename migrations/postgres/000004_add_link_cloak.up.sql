@@ -0,0 +1,4 @@
+-- Cloaked links are served as a full-page iframe so the short URL stays in
+-- the address bar.
+ALTER TABLE links
+    ADD COLUMN cloak BOOLEAN NOT NULL DEFAULT FALSE;
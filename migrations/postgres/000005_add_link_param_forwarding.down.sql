@@ -0,0 +1,3 @@
+ALTER TABLE links
+    DROP COLUMN IF EXISTS param_precedence,
+    DROP COLUMN IF EXISTS forward_params;
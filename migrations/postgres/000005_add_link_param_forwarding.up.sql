@@ -0,0 +1,5 @@
+-- param_precedence decides which value wins when an incoming query parameter
+-- is already set on the destination: 'destination' or 'incoming'.
+ALTER TABLE links
+    ADD COLUMN forward_params BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN param_precedence VARCHAR(20) NOT NULL DEFAULT 'destination';
@@ -0,0 +1,4 @@
+-- internal_note is visible to workspace members only and is never served by
+-- the redirect service.
+ALTER TABLE links
+    ADD COLUMN internal_note TEXT;
@@ -0,0 +1,4 @@
+-- Archived links are hidden from the default link list but keep their
+-- history and, unless deactivated, keep redirecting.
+ALTER TABLE links
+    ADD COLUMN archived_at TIMESTAMPTZ;
@@ -0,0 +1,15 @@
+-- Public, read-only share links for a single link's analytics. The token
+-- itself is signed and never stored; this row scopes it and lets it be
+-- revoked before it expires.
+CREATE TABLE analytics_share_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    range_start TIMESTAMPTZ NOT NULL,
+    range_end TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_analytics_share_tokens_link ON analytics_share_tokens(link_id);
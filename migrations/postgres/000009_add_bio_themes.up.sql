@@ -0,0 +1,13 @@
+-- Workspace-defined bio page themes. Built-in themes stay in code; pages
+-- point at either kind through bio_pages.theme_id, so there is no FK.
+CREATE TABLE bio_themes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    styles JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_bio_themes_workspace ON bio_themes(workspace_id);
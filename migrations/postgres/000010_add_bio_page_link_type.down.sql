@@ -0,0 +1,2 @@
+ALTER TABLE bio_page_links
+    DROP COLUMN IF EXISTS link_type;
@@ -0,0 +1,3 @@
+-- Social links derive their platform (and icon) from the URL.
+ALTER TABLE bio_page_links
+    ADD COLUMN link_type VARCHAR(20) NOT NULL DEFAULT 'regular';
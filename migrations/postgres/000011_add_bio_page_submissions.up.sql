@@ -0,0 +1,14 @@
+-- Emails collected by email capture blocks on bio pages. One row per page
+-- and address; resubmitting is a no-op.
+CREATE TABLE bio_page_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bio_page_id UUID NOT NULL REFERENCES bio_pages(id) ON DELETE CASCADE,
+    block_id UUID REFERENCES bio_page_links(id) ON DELETE SET NULL,
+    email VARCHAR(254) NOT NULL,
+    name VARCHAR(100),
+    ip_hash VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_bio_page_submissions_email ON bio_page_submissions(bio_page_id, email);
+CREATE INDEX idx_bio_page_submissions_page ON bio_page_submissions(bio_page_id, created_at DESC);
@@ -0,0 +1,16 @@
+-- Links whose destination matched a malware or phishing blocklist. A flagged
+-- link is created inactive and stays that way until an instance admin
+-- approves it. One row per link; flagging again resets the review.
+CREATE TABLE link_flags (
+    link_id UUID PRIMARY KEY REFERENCES links(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_link_flags_status ON link_flags(status, created_at);
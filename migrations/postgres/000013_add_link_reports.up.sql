@@ -0,0 +1,16 @@
+-- Abuse reports filed against links from the redirect service. One row per
+-- link and reporter (a keyed hash of their IP); reporting again replaces the
+-- earlier report.
+CREATE TABLE link_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    reason VARCHAR(20) NOT NULL,
+    details TEXT,
+    reporter_email VARCHAR(254),
+    reporter_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_link_reports_reporter ON link_reports(link_id, reporter_hash);
+CREATE INDEX idx_link_reports_link ON link_reports(link_id, created_at DESC);
@@ -0,0 +1,4 @@
+-- Links with force_https redirect http:// destinations over https:// when the
+-- destination host supports it.
+ALTER TABLE links
+    ADD COLUMN force_https BOOLEAN NOT NULL DEFAULT FALSE;
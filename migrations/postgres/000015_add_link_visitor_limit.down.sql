@@ -0,0 +1,3 @@
+ALTER TABLE links
+    DROP COLUMN IF EXISTS repeat_visit_url,
+    DROP COLUMN IF EXISTS once_per_visitor;
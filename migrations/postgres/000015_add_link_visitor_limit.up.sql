@@ -0,0 +1,5 @@
+-- Links with once_per_visitor send visitors who already followed them to
+-- repeat_visit_url, or an "already used" page when it is empty.
+ALTER TABLE links
+    ADD COLUMN once_per_visitor BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN repeat_visit_url TEXT;
@@ -0,0 +1,4 @@
+ALTER TABLE links
+    DROP COLUMN IF EXISTS fallback_url,
+    DROP COLUMN IF EXISTS android_url,
+    DROP COLUMN IF EXISTS ios_url;
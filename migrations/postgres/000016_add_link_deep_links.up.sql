@@ -0,0 +1,6 @@
+-- App URIs that mobile visitors are sent to first, with fallback_url (or url
+-- when empty) for visitors without the app and on desktop.
+ALTER TABLE links
+    ADD COLUMN ios_url TEXT,
+    ADD COLUMN android_url TEXT,
+    ADD COLUMN fallback_url TEXT;
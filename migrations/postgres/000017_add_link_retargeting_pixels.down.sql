@@ -0,0 +1,3 @@
+ALTER TABLE links
+    DROP COLUMN IF EXISTS google_tag_id,
+    DROP COLUMN IF EXISTS facebook_pixel_id;
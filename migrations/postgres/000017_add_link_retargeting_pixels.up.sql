@@ -0,0 +1,4 @@
+-- Retargeting pixels fired on an interstitial page before redirecting.
+ALTER TABLE links
+    ADD COLUMN facebook_pixel_id TEXT,
+    ADD COLUMN google_tag_id TEXT;
@@ -0,0 +1,14 @@
+-- Monthly usage per workspace, refreshed periodically by the worker so a
+-- billing integration can read metered usage without recounting it.
+CREATE TABLE usage_snapshots (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    tier VARCHAR(50) NOT NULL,
+    links_created BIGINT NOT NULL DEFAULT 0,
+    clicks_tracked BIGINT NOT NULL DEFAULT 0,
+    qr_codes_generated BIGINT NOT NULL DEFAULT 0,
+    webhook_deliveries BIGINT NOT NULL DEFAULT 0,
+    captured_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (workspace_id, period_start)
+);
@@ -0,0 +1,22 @@
+-- Analytics reports emailed to a workspace's recipients on a cron schedule.
+-- The worker sends reports whose next_run_at has passed and schedules the
+-- next run.
+CREATE TABLE scheduled_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    schedule VARCHAR(100) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    recipients TEXT[] NOT NULL,
+    scope JSONB NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_scheduled_reports_workspace ON scheduled_reports(workspace_id);
+CREATE INDEX idx_scheduled_reports_due ON scheduled_reports(next_run_at) WHERE is_active;
@@ -0,0 +1,3 @@
+-- Links that show a "you are leaving" interstitial before redirecting.
+ALTER TABLE links
+    ADD COLUMN interstitial BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- Case-insensitive short code checks, used when
+-- LINKS_CASE_INSENSITIVE_SHORT_CODES is enabled.
+CREATE INDEX idx_links_short_code_lower ON links(lower(short_code)) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+-- Where a click came from when the short link was reached through a tracked
+-- entry point: 'qr' for dynamic QR codes, NULL otherwise.
+ALTER TABLE clicks
+    ADD COLUMN source VARCHAR(20);
@@ -0,0 +1,4 @@
+-- When a failed delivery may next be retried, set from the receiver's
+-- Retry-After header. NULL falls back to the fixed retry interval.
+ALTER TABLE webhook_deliveries
+    ADD COLUMN next_retry_at TIMESTAMPTZ;
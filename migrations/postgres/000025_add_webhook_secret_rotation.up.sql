@@ -0,0 +1,5 @@
+-- The secret a webhook was signed with before its last rotation. Deliveries
+-- are signed with both secrets until previous_secret_expires_at.
+ALTER TABLE webhooks
+    ADD COLUMN previous_secret VARCHAR(255),
+    ADD COLUMN previous_secret_expires_at TIMESTAMPTZ;
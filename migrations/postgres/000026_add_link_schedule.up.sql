@@ -0,0 +1,4 @@
+-- Recurring weekly windows a link is live in, with their time zone and an
+-- optional URL to send visitors to outside them. NULL means always live.
+ALTER TABLE links
+    ADD COLUMN schedule JSONB;
@@ -0,0 +1,3 @@
+ALTER TABLE domains
+    DROP COLUMN IF EXISTS not_found_status,
+    DROP COLUMN IF EXISTS not_found_mode;
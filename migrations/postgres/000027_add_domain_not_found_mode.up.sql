@@ -0,0 +1,6 @@
+-- How the redirect service answers unknown short codes on a domain: the
+-- branded page, a redirect to custom_404_url, or a bare status. NULL follows
+-- the instance default. not_found_status overrides the mode's status code.
+ALTER TABLE domains
+    ADD COLUMN not_found_mode VARCHAR(20),
+    ADD COLUMN not_found_status INTEGER;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_workspaces_pending_cleanup;
+
+ALTER TABLE workspaces
+    DROP COLUMN IF EXISTS assets_cleaned_at;
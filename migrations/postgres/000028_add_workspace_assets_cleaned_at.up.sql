@@ -0,0 +1,9 @@
+-- Deleted workspaces have their links, bio pages, domains, API keys,
+-- webhooks and stored files cleaned up by the worker. assets_cleaned_at
+-- records when that finished; workspaces deleted before this migration are
+-- picked up by the worker's sweep.
+ALTER TABLE workspaces
+    ADD COLUMN assets_cleaned_at TIMESTAMPTZ;
+
+CREATE INDEX idx_workspaces_pending_cleanup ON workspaces(deleted_at)
+    WHERE deleted_at IS NOT NULL AND assets_cleaned_at IS NULL;
@@ -0,0 +1,15 @@
+-- Reusable link settings. Creating a link with a template_id starts from the
+-- template's settings; fields sent with the request override them.
+CREATE TABLE link_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    settings JSONB NOT NULL DEFAULT '{}',
+    password_hash VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_link_templates_workspace_name ON link_templates(workspace_id, LOWER(name));
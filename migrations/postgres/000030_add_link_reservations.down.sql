@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_links_reserved_until;
+
+ALTER TABLE links
+    DROP COLUMN IF EXISTS reserved_until;
@@ -0,0 +1,9 @@
+-- A short code can be reserved before its link is ready: the reservation is
+-- an inactive link with no destination and reserved_until set. Creating a
+-- link with the code in the same workspace claims it; once reserved_until
+-- passes, the worker deletes the reservation and the code is free again.
+ALTER TABLE links
+    ADD COLUMN reserved_until TIMESTAMPTZ;
+
+CREATE INDEX idx_links_reserved_until ON links(reserved_until)
+    WHERE reserved_until IS NOT NULL AND deleted_at IS NULL;
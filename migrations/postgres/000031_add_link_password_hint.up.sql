@@ -0,0 +1,3 @@
+-- An optional hint shown on the password form of password-protected links
+ALTER TABLE links
+    ADD COLUMN password_hint TEXT;
@@ -0,0 +1,4 @@
+-- Backup destinations, in order, served instead of the link's destination
+-- while the worker's health checks find it down. NULL or empty means none.
+ALTER TABLE links
+    ADD COLUMN failover_urls TEXT[];
@@ -7,33 +7,74 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
 )
 
 var (
 	ErrInvalidHash         = errors.New("invalid password hash format")
 	ErrIncompatibleVersion = errors.New("incompatible argon2 version")
+	ErrInvalidParams       = errors.New("invalid argon2 parameters")
 )
 
-type argon2Params struct {
-	memory      uint32
-	iterations  uint32
-	parallelism uint8
-	saltLength  uint32
-	keyLength   uint32
+// Params are the argon2id cost parameters used when hashing new passwords.
+// Memory is expressed in KiB.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
 }
 
-var defaultParams = &argon2Params{
-	memory:      64 * 1024, // 64 MB
-	iterations:  3,
-	parallelism: 2,
-	saltLength:  16,
-	keyLength:   32,
+var defaultParams = Params{
+	Memory:      64 * 1024, // 64 MB
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+var currentParams atomic.Pointer[Params]
+
+func init() {
+	p := defaultParams
+	currentParams.Store(&p)
+}
+
+// DefaultParams returns the built-in argon2id parameters.
+func DefaultParams() Params {
+	return defaultParams
+}
+
+// CurrentParams returns the parameters used for new hashes.
+func CurrentParams() Params {
+	return *currentParams.Load()
+}
+
+// SetParams replaces the parameters used for new hashes. Zero salt and key
+// lengths fall back to the defaults. Existing hashes keep verifying with the
+// parameters encoded in them; use NeedsRehash to find outdated ones.
+func SetParams(p Params) error {
+	if p.SaltLength == 0 {
+		p.SaltLength = defaultParams.SaltLength
+	}
+	if p.KeyLength == 0 {
+		p.KeyLength = defaultParams.KeyLength
+	}
+	if p.Memory < 8*uint32(p.Parallelism) || p.Iterations < 1 || p.Parallelism < 1 {
+		return ErrInvalidParams
+	}
+	currentParams.Store(&p)
+	return nil
 }
 
 func HashPassword(password string) (string, error) {
-	salt := make([]byte, defaultParams.saltLength)
+	p := currentParams.Load()
+
+	salt := make([]byte, p.SaltLength)
 	if _, err := rand.Read(salt); err != nil {
 		return "", fmt.Errorf("generating salt: %w", err)
 	}
@@ -41,10 +82,10 @@ func HashPassword(password string) (string, error) {
 	hash := argon2.IDKey(
 		[]byte(password),
 		salt,
-		defaultParams.iterations,
-		defaultParams.memory,
-		defaultParams.parallelism,
-		defaultParams.keyLength,
+		p.Iterations,
+		p.Memory,
+		p.Parallelism,
+		p.KeyLength,
 	)
 
 	// PHC format: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
@@ -53,21 +94,34 @@ func HashPassword(password string) (string, error) {
 
 	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
 		argon2.Version,
-		defaultParams.memory,
-		defaultParams.iterations,
-		defaultParams.parallelism,
+		p.Memory,
+		p.Iterations,
+		p.Parallelism,
 		b64Salt,
 		b64Hash,
 	), nil
 }
 
+// VerifyPassword checks password against an encoded hash. Both argon2id (PHC
+// format) and legacy bcrypt hashes are accepted.
 func VerifyPassword(password, encodedHash string) (bool, error) {
+	if isBcryptHash(encodedHash) {
+		err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, ErrInvalidHash
+		}
+		return true, nil
+	}
+
 	p, salt, hash, err := decodeHash(encodedHash)
 	if err != nil {
 		return false, err
 	}
 
-	otherHash := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, p.keyLength)
+	otherHash := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
 
 	if subtle.ConstantTimeCompare(hash, otherHash) == 1 {
 		return true, nil
@@ -75,9 +129,36 @@ func VerifyPassword(password, encodedHash string) (bool, error) {
 	return false, nil
 }
 
-func decodeHash(encodedHash string) (*argon2Params, []byte, []byte, error) {
+// NeedsRehash reports whether encodedHash was produced by a legacy algorithm
+// or with parameters that differ from the current ones. Callers should rehash
+// the plaintext after a successful verification when this returns true.
+func NeedsRehash(encodedHash string) bool {
+	if isBcryptHash(encodedHash) {
+		return true
+	}
+
+	p, _, _, err := decodeHash(encodedHash)
+	if err != nil {
+		return true
+	}
+
+	cur := currentParams.Load()
+	return p.Memory != cur.Memory ||
+		p.Iterations != cur.Iterations ||
+		p.Parallelism != cur.Parallelism ||
+		p.SaltLength != cur.SaltLength ||
+		p.KeyLength != cur.KeyLength
+}
+
+func isBcryptHash(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") ||
+		strings.HasPrefix(encodedHash, "$2b$") ||
+		strings.HasPrefix(encodedHash, "$2y$")
+}
+
+func decodeHash(encodedHash string) (*Params, []byte, []byte, error) {
 	parts := strings.Split(encodedHash, "$")
-	if len(parts) != 6 {
+	if len(parts) != 6 || parts[1] != "argon2id" {
 		return nil, nil, nil, ErrInvalidHash
 	}
 
@@ -89,8 +170,8 @@ func decodeHash(encodedHash string) (*argon2Params, []byte, []byte, error) {
 		return nil, nil, nil, ErrIncompatibleVersion
 	}
 
-	p := &argon2Params{}
-	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+	p := &Params{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
 		return nil, nil, nil, ErrInvalidHash
 	}
 
@@ -98,13 +179,13 @@ func decodeHash(encodedHash string) (*argon2Params, []byte, []byte, error) {
 	if err != nil {
 		return nil, nil, nil, ErrInvalidHash
 	}
-	p.saltLength = uint32(len(salt))
+	p.SaltLength = uint32(len(salt))
 
 	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
 	if err != nil {
 		return nil, nil, nil, ErrInvalidHash
 	}
-	p.keyLength = uint32(len(hash))
+	p.KeyLength = uint32(len(hash))
 
 	return p, salt, hash, nil
 }
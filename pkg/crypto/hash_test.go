@@ -3,6 +3,8 @@ package crypto
 import (
 	"strings"
 	"testing"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestHashAndVerify(t *testing.T) {
@@ -91,3 +93,60 @@ func TestEmptyPassword(t *testing.T) {
 		t.Error("non-empty password should not match empty password hash")
 	}
 }
+
+func TestNeedsRehash(t *testing.T) {
+	hash, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("HashPassword() error: %v", err)
+	}
+	if NeedsRehash(hash) {
+		t.Error("hash with current params should not need rehash")
+	}
+
+	defer SetParams(DefaultParams())
+	if err := SetParams(Params{Memory: 32 * 1024, Iterations: 2, Parallelism: 1}); err != nil {
+		t.Fatalf("SetParams() error: %v", err)
+	}
+
+	if !NeedsRehash(hash) {
+		t.Error("hash with outdated params should need rehash")
+	}
+
+	// Old hashes still verify after params change
+	match, err := VerifyPassword("password", hash)
+	if err != nil || !match {
+		t.Errorf("expected outdated hash to verify, match=%v err=%v", match, err)
+	}
+
+	newHash, _ := HashPassword("password")
+	if !strings.Contains(newHash, "$m=32768,t=2,p=1$") {
+		t.Errorf("expected new params in hash, got %s", newHash)
+	}
+}
+
+func TestVerifyLegacyBcrypt(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("legacy-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt error: %v", err)
+	}
+
+	match, err := VerifyPassword("legacy-password", string(legacy))
+	if err != nil || !match {
+		t.Errorf("expected bcrypt hash to verify, match=%v err=%v", match, err)
+	}
+
+	match, _ = VerifyPassword("wrong", string(legacy))
+	if match {
+		t.Error("expected wrong password not to match bcrypt hash")
+	}
+
+	if !NeedsRehash(string(legacy)) {
+		t.Error("bcrypt hash should always need rehash")
+	}
+}
+
+func TestSetParamsRejectsInvalid(t *testing.T) {
+	if err := SetParams(Params{Memory: 1024, Iterations: 0, Parallelism: 1}); err == nil {
+		t.Error("expected error for zero iterations")
+	}
+}
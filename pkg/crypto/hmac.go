@@ -0,0 +1,20 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SignHMAC returns the hex-encoded HMAC-SHA256 of message under secret.
+func SignHMAC(secret string, message []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(message)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMAC reports whether signature is SignHMAC(secret, message), comparing
+// in constant time.
+func VerifyHMAC(secret string, message []byte, signature string) bool {
+	return hmac.Equal([]byte(SignHMAC(secret, message)), []byte(signature))
+}
@@ -0,0 +1,19 @@
+package crypto
+
+import "testing"
+
+func TestSignAndVerifyHMAC(t *testing.T) {
+	sig := SignHMAC("secret", []byte("message"))
+	if len(sig) != 64 {
+		t.Fatalf("signature length = %d, want 64 hex chars", len(sig))
+	}
+	if !VerifyHMAC("secret", []byte("message"), sig) {
+		t.Error("VerifyHMAC() rejected a valid signature")
+	}
+	if VerifyHMAC("other", []byte("message"), sig) {
+		t.Error("VerifyHMAC() accepted a signature under the wrong secret")
+	}
+	if VerifyHMAC("secret", []byte("tampered"), sig) {
+		t.Error("VerifyHMAC() accepted a signature for a different message")
+	}
+}
@@ -0,0 +1,147 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Sender delivers email messages (SMTP, or a fake in tests).
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Message is an email with an HTML body, an optional plain-text
+// alternative and optional attachments.
+type Message struct {
+	To          []string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a Message.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Build renders msg as a MIME message from the given sender address.
+// Recipients are validated, and header values with line breaks are
+// rejected so they can't inject headers.
+func Build(from string, msg *Message, date time.Time) ([]byte, error) {
+	if len(msg.To) == 0 {
+		return nil, errors.New("email: no recipients")
+	}
+	for _, addr := range append([]string{from}, msg.To...) {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return nil, fmt.Errorf("email: invalid address %q: %w", addr, err)
+		}
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return nil, errors.New("email: subject must not contain line breaks")
+	}
+
+	var buf bytes.Buffer
+	mixed := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	if err := writeBody(mixed, msg); err != nil {
+		return nil, err
+	}
+	for _, a := range msg.Attachments {
+		if err := writeAttachment(mixed, a); err != nil {
+			return nil, err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBody writes the HTML body, as a multipart/alternative with the text
+// version when there is one.
+func writeBody(mixed *multipart.Writer, msg *Message) error {
+	if msg.Text == "" {
+		return writeTextPart(mixed, "text/html; charset=utf-8", msg.HTML)
+	}
+
+	var alt bytes.Buffer
+	altWriter := multipart.NewWriter(&alt)
+	if err := writeTextPart(altWriter, "text/plain; charset=utf-8", msg.Text); err != nil {
+		return err
+	}
+	if err := writeTextPart(altWriter, "text/html; charset=utf-8", msg.HTML); err != nil {
+		return err
+	}
+	if err := altWriter.Close(); err != nil {
+		return err
+	}
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + altWriter.Boundary()},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(alt.Bytes())
+	return err
+}
+
+func writeTextPart(w *multipart.Writer, contentType, body string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+	return writeBase64(part, []byte(body))
+}
+
+func writeAttachment(w *multipart.Writer, a Attachment) error {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+	})
+	if err != nil {
+		return err
+	}
+	return writeBase64(part, a.Data)
+}
+
+// writeBase64 writes data base64-encoded in 76-character lines, as MIME
+// requires.
+func writeBase64(w interface{ Write([]byte) (int, error) }, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:n]); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
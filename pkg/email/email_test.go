@@ -0,0 +1,80 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuild(t *testing.T) {
+	csv := []byte("short_code,clicks\nabc,42\n")
+	raw, err := Build("reports@example.com", &Message{
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "Weekly report — Marketing",
+		HTML:    "<p>Hello</p>",
+		Text:    "Hello",
+		Attachments: []Attachment{
+			{Filename: "top-links.csv", ContentType: "text/csv", Data: csv},
+		},
+	}, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage() error: %v", err)
+	}
+	if got := msg.Header.Get("To"); got != "a@example.com, b@example.com" {
+		t.Errorf("To = %q", got)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Weekly report — Marketing" {
+		t.Errorf("Subject = %q", subject)
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Content-Type: %v", err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+
+	body, err := parts.NextPart()
+	if err != nil {
+		t.Fatalf("body part: %v", err)
+	}
+	if ct := body.Header.Get("Content-Type"); !strings.HasPrefix(ct, "multipart/alternative") {
+		t.Errorf("body Content-Type = %q, want multipart/alternative", ct)
+	}
+
+	attachment, err := parts.NextPart()
+	if err != nil {
+		t.Fatalf("attachment part: %v", err)
+	}
+	if attachment.FileName() != "top-links.csv" {
+		t.Errorf("attachment filename = %q", attachment.FileName())
+	}
+	data, _ := io.ReadAll(base64.NewDecoder(base64.StdEncoding, attachment))
+	if !bytes.Equal(data, csv) {
+		t.Errorf("attachment = %q, want %q", data, csv)
+	}
+}
+
+func TestBuild_RejectsHeaderInjection(t *testing.T) {
+	cases := map[string]*Message{
+		"subject":   {To: []string{"a@example.com"}, Subject: "Hi\r\nBcc: x@example.com"},
+		"recipient": {To: []string{"a@example.com\r\nBcc: x@example.com"}, Subject: "Hi"},
+		"none":      {Subject: "Hi"},
+	}
+	for name, msg := range cases {
+		if _, err := Build("reports@example.com", msg, time.Now()); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/link-rift/link-rift/internal/config"
+)
+
+// defaultSendTimeout bounds a send when the context has no deadline.
+const defaultSendTimeout = 30 * time.Second
+
+// SMTPSender implements Sender over SMTP, upgrading to TLS with STARTTLS
+// when the server offers it and authenticating when a user is configured.
+type SMTPSender struct {
+	cfg config.SMTPConfig
+}
+
+// Compile-time check that SMTPSender satisfies Sender.
+var _ Sender = (*SMTPSender)(nil)
+
+// NewSMTPSender creates an SMTPSender.
+// Returns an error if the host or sender address is missing.
+func NewSMTPSender(cfg config.SMTPConfig) (*SMTPSender, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("smtp: host is required")
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("smtp: from address is required")
+	}
+	return &SMTPSender{cfg: cfg}, nil
+}
+
+// Send delivers msg to all of its recipients in one SMTP transaction.
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	body, err := Build(s.cfg.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("smtp: dial %s: %w", addr, err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultSendTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp: starttls: %w", err)
+		}
+	}
+	if s.cfg.User != "" {
+		auth := smtp.PlainAuth("", s.cfg.User, s.cfg.Password, s.cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+
+	// SMTP_FROM may carry a display name; the envelope takes the address.
+	from, err := mail.ParseAddress(s.cfg.From)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid from address: %w", err)
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp: mail from: %w", err)
+	}
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp: rcpt to %s: %w", to, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("smtp: write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: send message: %w", err)
+	}
+	return c.Quit()
+}
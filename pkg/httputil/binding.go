@@ -0,0 +1,194 @@
+package httputil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is one request field that failed a validation rule. Field is
+// the field's path in the request, e.g. "links[2].url", and Rule the
+// binding tag that failed, e.g. "required".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func init() {
+	// Name fields in validation errors after their JSON or form keys rather
+	// than the Go struct fields, so clients can match them to their inputs.
+	// It has to happen before the first request is validated, because the
+	// validator caches the names per struct.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+func requestFieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// BindingError converts an error from gin's ShouldBind methods into a
+// validation error. Each failed rule becomes a FieldError in the "errors"
+// detail, and the "field" detail names the first of them as Validation
+// does. A body that isn't valid JSON, or has a value of the wrong type, is
+// reported the same way; anything else is reported on field.
+func BindingError(field string, err error) *AppError {
+	fieldErrs := bindingFieldErrors(field, err)
+	if len(fieldErrs) == 0 {
+		return Validation(field, err.Error())
+	}
+
+	first := fieldErrs[0]
+	return &AppError{
+		Err:     ErrValidation,
+		Message: first.Field + " " + first.Message,
+		Code:    "VALIDATION_ERROR",
+		Details: map[string]any{
+			"field":  first.Field,
+			"errors": fieldErrs,
+		},
+	}
+}
+
+func bindingFieldErrors(field string, err error) []FieldError {
+	var (
+		invalid   validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &invalid):
+		fieldErrs := make([]FieldError, 0, len(invalid))
+		for _, fe := range invalid {
+			fieldErrs = append(fieldErrs, FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Message: ruleMessage(fe),
+			})
+		}
+		return fieldErrs
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return []FieldError{{
+			Field:   jsonFieldPath(typeErr.Field),
+			Rule:    "type",
+			Message: "must be " + jsonTypeName(typeErr.Type),
+		}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return []FieldError{{Field: field, Rule: "json", Message: "must be valid JSON"}}
+	case errors.Is(err, io.EOF):
+		return []FieldError{{Field: field, Rule: "required", Message: "is required"}}
+	}
+	return nil
+}
+
+// fieldPath drops the struct name from a validator namespace such as
+// "CreateLinkInput.links[2].url".
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// jsonFieldPath writes the path of a JSON decoding error, such as
+// "links.2.url", the way validator namespaces are: "links[2].url".
+func jsonFieldPath(path string) string {
+	parts := strings.Split(path, ".")
+	var b strings.Builder
+	for i, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "min", "gte":
+		return "must be at least " + sizeLimit(fe.Kind(), param)
+	case "max", "lte":
+		return "must be at most " + sizeLimit(fe.Kind(), param)
+	case "gt":
+		return "must be more than " + sizeLimit(fe.Kind(), param)
+	case "lt":
+		return "must be less than " + sizeLimit(fe.Kind(), param)
+	case "len":
+		return "must be exactly " + sizeLimit(fe.Kind(), param)
+	}
+	if param != "" {
+		return fmt.Sprintf("failed the %s=%s rule", fe.Tag(), param)
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
+
+// sizeLimit describes a min or max limit for a field of the given kind:
+// a length for strings, a count for lists and a value for numbers.
+func sizeLimit(kind reflect.Kind, param string) string {
+	switch kind {
+	case reflect.String:
+		if param == "1" {
+			return "1 character long"
+		}
+		return param + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if param == "1" {
+			return "1 item"
+		}
+		return param + " items"
+	}
+	return param
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return "a " + t.String()
+}
@@ -0,0 +1,18 @@
+package httputil
+
+import "net/http"
+
+// NewCookie returns a site-wide cookie that scripts can't read and that
+// isn't sent on cross-site subrequests, marked Secure when secure is true.
+// maxAge is in seconds.
+func NewCookie(name, value string, maxAge int, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -94,6 +95,94 @@ func TestRespondErrorGeneric(t *testing.T) {
 	}
 }
 
+type bindingTestLink struct {
+	URL string `json:"url" binding:"required,url"`
+}
+
+type bindingTestInput struct {
+	Name  string            `json:"name" binding:"required,min=3"`
+	Role  string            `json:"role" binding:"omitempty,oneof=admin member"`
+	Links []bindingTestLink `json:"links" binding:"required,min=1,dive"`
+}
+
+func TestBindingError(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string
+		wantRules  []string
+		wantMsg    string
+	}{
+		{
+			name:       "rules",
+			body:       `{"name":"ab","role":"owner","links":[{"url":"https://example.com"},{"url":"nope"}]}`,
+			wantFields: []string{"name", "role", "links[1].url"},
+			wantRules:  []string{"min", "oneof", "url"},
+			wantMsg:    "name must be at least 3 characters long",
+		},
+		{
+			name:       "wrong type",
+			body:       `{"name":"acme","links":[{"url":42}]}`,
+			wantFields: []string{"links[0].url"},
+			wantRules:  []string{"type"},
+			wantMsg:    "links[0].url must be a string",
+		},
+		{
+			name:       "malformed",
+			body:       `{"name":`,
+			wantFields: []string{"body"},
+			wantRules:  []string{"json"},
+			wantMsg:    "body must be valid JSON",
+		},
+		{
+			name:       "empty",
+			body:       ``,
+			wantFields: []string{"body"},
+			wantRules:  []string{"required"},
+			wantMsg:    "body is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var input bindingTestInput
+			bindErr := c.ShouldBindJSON(&input)
+			if bindErr == nil {
+				t.Fatal("expected a binding error")
+			}
+
+			appErr := BindingError("body", bindErr)
+			if MapToHTTPStatus(appErr) != http.StatusBadRequest || appErr.Code != "VALIDATION_ERROR" {
+				t.Fatalf("got %s, want a validation error", appErr.Code)
+			}
+			if appErr.Message != tt.wantMsg {
+				t.Errorf("message = %q, want %q", appErr.Message, tt.wantMsg)
+			}
+			if appErr.Details["field"] != tt.wantFields[0] {
+				t.Errorf("field = %v, want %q", appErr.Details["field"], tt.wantFields[0])
+			}
+			fieldErrs, _ := appErr.Details["errors"].([]FieldError)
+			if len(fieldErrs) != len(tt.wantFields) {
+				t.Fatalf("errors = %+v, want %d", fieldErrs, len(tt.wantFields))
+			}
+			for i, fe := range fieldErrs {
+				if fe.Field != tt.wantFields[i] || fe.Rule != tt.wantRules[i] || fe.Message == "" {
+					t.Errorf("errors[%d] = %+v, want field %q and rule %q", i, fe, tt.wantFields[i], tt.wantRules[i])
+				}
+			}
+		})
+	}
+
+	// Errors that aren't about the request's fields keep the given field
+	appErr := BindingError("query", errors.New("strconv.ParseInt: parsing \"x\": invalid syntax"))
+	if appErr.Details["field"] != "query" || appErr.Details["errors"] != nil {
+		t.Errorf("details = %v, want only the query field", appErr.Details)
+	}
+}
+
 func TestRespondList(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
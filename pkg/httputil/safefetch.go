@@ -0,0 +1,256 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrResponseTooLarge = errors.New("response body exceeds size limit")
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// SafeClientConfig bounds outbound fetches of user-supplied URLs. HopTimeout
+// bounds each request Check makes while following redirects, so the whole
+// check takes at most MaxRedirects+1 times as long.
+type SafeClientConfig struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	MaxRedirects int
+	HopTimeout   time.Duration
+}
+
+// DefaultSafeClientConfig returns conservative limits suitable for fetching
+// page metadata and small images.
+func DefaultSafeClientConfig() SafeClientConfig {
+	return SafeClientConfig{
+		Timeout:      5 * time.Second,
+		MaxBodyBytes: 2 << 20, // 2 MB
+		MaxRedirects: 3,
+		HopTimeout:   5 * time.Second,
+	}
+}
+
+// FetchResult is the body and metadata of a successful safe fetch.
+type FetchResult struct {
+	Body        []byte
+	ContentType string
+	FinalURL    string
+}
+
+// SafeClient fetches user-supplied URLs server-side without exposing internal
+// services: every connection (including redirects) is checked against a
+// HostPolicy, and responses are bounded in time, size and redirect count.
+type SafeClient struct {
+	client  *http.Client
+	policy  *HostPolicy
+	maxBody int64
+	// noFollow shares client's transport but returns redirects to the
+	// caller, so Check can record each hop.
+	noFollow     *http.Client
+	maxRedirects int
+	hopTimeout   time.Duration
+}
+
+// NewSafeClient creates a SafeClient. Zero config values fall back to
+// DefaultSafeClientConfig.
+func NewSafeClient(policy *HostPolicy, cfg SafeClientConfig) *SafeClient {
+	def := DefaultSafeClientConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = def.Timeout
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = def.MaxBodyBytes
+	}
+	if cfg.MaxRedirects <= 0 {
+		cfg.MaxRedirects = def.MaxRedirects
+	}
+	if cfg.HopTimeout <= 0 {
+		cfg.HopTimeout = def.HopTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // a proxy would bypass the dial-time address check
+	transport.DialContext = policy.DialContext(&net.Dialer{Timeout: cfg.Timeout})
+
+	maxRedirects := cfg.MaxRedirects
+	return &SafeClient{
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return ErrTooManyRedirects
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("unsupported redirect scheme %q", req.URL.Scheme)
+				}
+				return nil
+			},
+		},
+		policy:  policy,
+		maxBody: cfg.MaxBodyBytes,
+		noFollow: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxRedirects: maxRedirects,
+		hopTimeout:   cfg.HopTimeout,
+	}
+}
+
+// Get fetches rawURL and returns its body. Non-2xx responses, blocked
+// destinations and bodies over the size limit are errors.
+func (c *SafeClient) Get(ctx context.Context, rawURL string) (*FetchResult, error) {
+	if err := c.policy.CheckURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Linkrift-Fetcher/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, rawURL)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxBody {
+		return nil, ErrResponseTooLarge
+	}
+
+	return &FetchResult{
+		Body:        body,
+		ContentType: resp.Header.Get("Content-Type"),
+		FinalURL:    resp.Request.URL.String(),
+	}, nil
+}
+
+// Headers fetches rawURL and returns the final response headers without
+// reading the body. Non-2xx responses and blocked destinations are errors.
+func (c *SafeClient) Headers(ctx context.Context, rawURL string) (http.Header, error) {
+	if err := c.policy.CheckURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Linkrift-Fetcher/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, rawURL)
+	}
+	return resp.Header, nil
+}
+
+// RedirectHop is one redirect followed by Check.
+type RedirectHop struct {
+	URL        string
+	StatusCode int
+}
+
+// CheckResult is the outcome of Check: the status of the final response and
+// the redirects that led to it.
+type CheckResult struct {
+	StatusCode int
+	FinalURL   string
+	Redirects  []RedirectHop
+}
+
+// Check requests rawURL with HEAD, or GET when the server doesn't allow HEAD,
+// following redirects itself so each hop can be reported. Unlike Get, any
+// final status is a result rather than an error; blocked destinations,
+// network failures and redirect loops are errors. A chain longer than
+// MaxRedirects returns ErrTooManyRedirects along with the chain up to the
+// redirect that wasn't followed. Bodies are never read.
+func (c *SafeClient) Check(ctx context.Context, rawURL string) (*CheckResult, error) {
+	result := &CheckResult{}
+	current := rawURL
+	for {
+		if err := c.policy.CheckURL(ctx, current); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.probeHop(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+
+		location := resp.Header.Get("Location")
+		if resp.StatusCode < 300 || resp.StatusCode >= 400 || location == "" {
+			result.StatusCode = resp.StatusCode
+			result.FinalURL = current
+			return result, nil
+		}
+
+		if len(result.Redirects) >= c.maxRedirects {
+			result.Redirects = append(result.Redirects, RedirectHop{URL: current, StatusCode: resp.StatusCode})
+			return result, ErrTooManyRedirects
+		}
+		next, err := resp.Request.URL.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redirect location %q: %w", location, err)
+		}
+		if next.Scheme != "http" && next.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported redirect scheme %q", next.Scheme)
+		}
+		result.Redirects = append(result.Redirects, RedirectHop{URL: current, StatusCode: resp.StatusCode})
+		current = next.String()
+	}
+}
+
+// probeHop requests one URL of a redirect chain within the hop timeout.
+func (c *SafeClient) probeHop(ctx context.Context, rawURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.hopTimeout)
+	defer cancel()
+
+	resp, err := c.probe(ctx, http.MethodHead, rawURL)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = c.probe(ctx, http.MethodGet, rawURL)
+	}
+	return resp, err
+}
+
+// probe sends a single request without following redirects and closes the
+// body unread.
+func (c *SafeClient) probe(ctx context.Context, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Linkrift-Fetcher/1.0")
+
+	resp, err := c.noFollow.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
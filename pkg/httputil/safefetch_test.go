@@ -0,0 +1,192 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loopbackClient returns a SafeClient that may reach httptest servers.
+func loopbackClient(t *testing.T, cfg SafeClientConfig) *SafeClient {
+	t.Helper()
+	p, err := NewHostPolicy(false, []string{"127.0.0.1"})
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error: %v", err)
+	}
+	return NewSafeClient(p, cfg)
+}
+
+func TestSafeClient_BlocksPrivateByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer srv.Close()
+
+	p, _ := NewHostPolicy(false, nil)
+	c := NewSafeClient(p, DefaultSafeClientConfig())
+
+	if _, err := c.Get(context.Background(), srv.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected ErrBlockedAddress, got %v", err)
+	}
+	if _, err := c.Get(context.Background(), "http://169.254.169.254/latest/meta-data/"); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected metadata endpoint to be blocked, got %v", err)
+	}
+}
+
+func TestSafeClient_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer srv.Close()
+
+	res, err := loopbackClient(t, DefaultSafeClientConfig()).Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if string(res.Body) != "<html></html>" {
+		t.Errorf("unexpected body %q", res.Body)
+	}
+	if res.ContentType != "text/html" {
+		t.Errorf("expected content type text/html, got %q", res.ContentType)
+	}
+}
+
+func TestSafeClient_Headers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Write([]byte(strings.Repeat("x", 4096)))
+	}))
+	defer srv.Close()
+
+	// The body is never read, so the size limit doesn't apply.
+	h, err := loopbackClient(t, SafeClientConfig{MaxBodyBytes: 1024}).Headers(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Headers() error: %v", err)
+	}
+	if got := h.Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("X-Frame-Options = %q, want DENY", got)
+	}
+}
+
+func TestSafeClient_BodyTooLarge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+	defer srv.Close()
+
+	c := loopbackClient(t, SafeClientConfig{MaxBodyBytes: 1024})
+	if _, err := c.Get(context.Background(), srv.URL); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestSafeClient_RedirectLimit(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, srv.URL+r.URL.Path+"x", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	c := loopbackClient(t, SafeClientConfig{MaxRedirects: 2})
+	if _, err := c.Get(context.Background(), srv.URL+"/"); !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("expected ErrTooManyRedirects, got %v", err)
+	}
+}
+
+func TestSafeClient_RedirectToMetadataBlocked(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	if _, err := loopbackClient(t, DefaultSafeClientConfig()).Get(context.Background(), srv.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected redirect to metadata endpoint to be blocked, got %v", err)
+	}
+}
+
+func TestSafeClient_Check(t *testing.T) {
+	var methods []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := loopbackClient(t, DefaultSafeClientConfig())
+	res, err := c.Check(context.Background(), srv.URL+"/old")
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if res.StatusCode != http.StatusNotFound || res.FinalURL != srv.URL+"/new" {
+		t.Errorf("got status %d at %q, want 404 at /new", res.StatusCode, res.FinalURL)
+	}
+	if len(res.Redirects) != 1 || res.Redirects[0] != (RedirectHop{URL: srv.URL + "/old", StatusCode: http.StatusMovedPermanently}) {
+		t.Errorf("unexpected redirects %+v", res.Redirects)
+	}
+	if strings.Join(methods, ",") != "HEAD,GET" {
+		t.Errorf("expected GET fallback after 405, got %v", methods)
+	}
+
+	res, err = c.Check(context.Background(), srv.URL+"/loop")
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("expected ErrTooManyRedirects, got %v", err)
+	}
+	if res == nil || len(res.Redirects) != DefaultSafeClientConfig().MaxRedirects+1 {
+		t.Errorf("expected the chain up to the limit, got %+v", res)
+	}
+}
+
+func TestSafeClient_CheckHopTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/slow", http.StatusFound)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := DefaultSafeClientConfig()
+	cfg.HopTimeout = 50 * time.Millisecond
+	c := loopbackClient(t, cfg)
+
+	start := time.Now()
+	if _, err := c.Check(context.Background(), srv.URL+"/start"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the slow hop to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the hop timeout to apply, took %v", elapsed)
+	}
+}
+
+func TestSafeClient_CheckBlocksRedirectToPrivate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	if _, err := loopbackClient(t, DefaultSafeClientConfig()).Check(context.Background(), srv.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected ErrBlockedAddress, got %v", err)
+	}
+}
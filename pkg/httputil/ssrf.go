@@ -0,0 +1,199 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrBlockedAddress is returned when a destination resolves to a private,
+// loopback, link-local or otherwise internal address that isn't allowlisted.
+var ErrBlockedAddress = errors.New("destination address is not allowed")
+
+// Ranges that are never reachable as public destinations, beyond what the
+// net.IP helpers already cover.
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved
+	"64:ff9b::/96",  // NAT64, may embed private IPv4
+	"2001:db8::/32", // documentation
+)
+
+// HostPolicy decides which outbound destinations server-side requests may
+// reach. By default only public addresses are allowed; allowPrivate lifts the
+// restriction entirely, and the allowlist admits specific hosts or networks.
+type HostPolicy struct {
+	allowPrivate bool
+	hosts        map[string]bool
+	networks     []*net.IPNet
+	resolver     *net.Resolver
+}
+
+// NewHostPolicy builds a policy from an allowlist of hostnames, IPs and CIDRs.
+func NewHostPolicy(allowPrivate bool, allowlist []string) (*HostPolicy, error) {
+	p := &HostPolicy{
+		allowPrivate: allowPrivate,
+		hosts:        make(map[string]bool),
+		resolver:     net.DefaultResolver,
+	}
+
+	for _, entry := range allowlist {
+		entry = strings.TrimSpace(strings.ToLower(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowlist CIDR %q: %w", entry, err)
+			}
+			p.networks = append(p.networks, network)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			p.networks = append(p.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		p.hosts[entry] = true
+	}
+
+	return p, nil
+}
+
+// CheckURL validates that rawURL uses http(s) and that its host resolves only
+// to permitted addresses.
+func (p *HostPolicy) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("invalid URL: %q", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+
+	_, err = p.resolve(ctx, u.Hostname())
+	return err
+}
+
+// CheckIP returns ErrBlockedAddress if ip is internal and not allowlisted.
+func (p *HostPolicy) CheckIP(ip net.IP) error {
+	if p == nil || p.allowPrivate {
+		return nil
+	}
+	for _, network := range p.networks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if IsInternalIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// DialContext returns a dial function for http.Transport that resolves the
+// host itself and connects only to permitted addresses. Checking at dial time
+// covers redirects and DNS rebinding between validation and delivery.
+func (p *HostPolicy) DialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		ips, err := p.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if ips == nil {
+			// Allowlisted by name or unrestricted: dial as requested
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		var lastErr error
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// resolve looks up host and checks every address it resolves to. It returns
+// nil addresses when the host needs no checking.
+func (p *HostPolicy) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if p == nil || p.allowPrivate || p.hosts[strings.ToLower(host)] {
+		return nil, nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if err := p.CheckIP(ip); err != nil {
+			return nil, err
+		}
+		return []net.IP{ip}, nil
+	}
+
+	addrs, err := p.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("resolving %s: no addresses", host)
+	}
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		// Reject the host if any address is internal, so a mixed record
+		// can't be used to reach an internal service.
+		if err := p.CheckIP(addr.IP); err != nil {
+			return nil, err
+		}
+		ips = append(ips, addr.IP)
+	}
+	return ips, nil
+}
+
+// IsInternalIP reports whether ip is loopback, private, link-local (including
+// cloud metadata endpoints such as 169.254.169.254), multicast, unspecified
+// or in another reserved range.
+func IsInternalIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, network, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
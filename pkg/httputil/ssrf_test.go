@@ -0,0 +1,115 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostPolicy_BlocksInternalURLs(t *testing.T) {
+	p, err := NewHostPolicy(false, nil)
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error: %v", err)
+	}
+
+	urls := []string{
+		"http://169.254.169.254/latest/meta-data/iam/security-credentials/",
+		"https://169.254.169.254/computeMetadata/v1/",
+		"http://[fd00:ec2::254]/latest/meta-data/",
+		"http://127.0.0.1:8080/admin",
+		"https://[::1]/",
+		"https://10.0.0.5/hook",
+		"https://192.168.1.1/hook",
+		"https://172.16.0.10/hook",
+		"https://100.64.0.1/hook",
+		"https://0.0.0.0/hook",
+		"https://[::ffff:127.0.0.1]/hook",
+		"https://localhost/hook",
+	}
+
+	for _, u := range urls {
+		if err := p.CheckURL(context.Background(), u); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("CheckURL(%q) = %v, want ErrBlockedAddress", u, err)
+		}
+	}
+}
+
+func TestHostPolicy_AllowsPublicIP(t *testing.T) {
+	p, _ := NewHostPolicy(false, nil)
+	if err := p.CheckURL(context.Background(), "https://93.184.216.34/hook"); err != nil {
+		t.Errorf("expected public IP to be allowed, got %v", err)
+	}
+}
+
+func TestHostPolicy_RejectsNonHTTPScheme(t *testing.T) {
+	p, _ := NewHostPolicy(false, nil)
+	if err := p.CheckURL(context.Background(), "file:///etc/passwd"); err == nil {
+		t.Error("expected file:// URL to be rejected")
+	}
+}
+
+func TestHostPolicy_Allowlist(t *testing.T) {
+	p, err := NewHostPolicy(false, []string{"10.1.0.0/16", "hooks.internal", "192.168.5.5"})
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error: %v", err)
+	}
+
+	allowed := []string{
+		"https://10.1.2.3/hook",
+		"https://hooks.internal/hook",
+		"https://192.168.5.5/hook",
+	}
+	for _, u := range allowed {
+		if err := p.CheckURL(context.Background(), u); err != nil {
+			t.Errorf("CheckURL(%q) = %v, want allowed", u, err)
+		}
+	}
+
+	if err := p.CheckURL(context.Background(), "https://10.2.0.1/hook"); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected address outside allowlisted CIDR to be blocked, got %v", err)
+	}
+}
+
+func TestHostPolicy_AllowPrivate(t *testing.T) {
+	p, _ := NewHostPolicy(true, nil)
+	if err := p.CheckURL(context.Background(), "http://169.254.169.254/"); err != nil {
+		t.Errorf("expected private targets to be allowed, got %v", err)
+	}
+}
+
+func TestHostPolicy_InvalidAllowlistCIDR(t *testing.T) {
+	if _, err := NewHostPolicy(false, []string{"10.0.0.0/99"}); err == nil {
+		t.Error("expected error for invalid CIDR")
+	}
+}
+
+func TestHostPolicy_DialContextBlocksLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p, _ := NewHostPolicy(false, nil)
+	client := &http.Client{Transport: &http.Transport{DialContext: p.DialContext(&net.Dialer{})}}
+
+	resp, err := client.Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected dial to loopback test server to be blocked")
+	}
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected ErrBlockedAddress, got %v", err)
+	}
+
+	// Allowlisting the loopback address lets the request through
+	p, _ = NewHostPolicy(false, []string{"127.0.0.1"})
+	client = &http.Client{Transport: &http.Transport{DialContext: p.DialContext(&net.Dialer{})}}
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected allowlisted loopback to be reachable, got %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,168 @@
+// Package lock provides Redis-backed locks for coordinating work across
+// instances: a mutex that is renewed while held, and per-period claims for
+// periodic jobs.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "lock:"
+
+var (
+	// ErrNotAcquired is returned when a lock is held by someone else.
+	ErrNotAcquired = errors.New("lock: not acquired")
+	// ErrNotHeld is returned when releasing a lock that has expired or been
+	// taken over.
+	ErrNotHeld = errors.New("lock: not held")
+)
+
+// releaseScript deletes the key only if it still holds our token.
+const releaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`
+
+// renewScript extends the key's TTL only if it still holds our token.
+const renewScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`
+
+// Client is the subset of the Redis client locks need. *redis.Client
+// implements it.
+type Client interface {
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd
+	Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd
+}
+
+// Locker creates locks in one Redis.
+type Locker struct {
+	client Client
+}
+
+func New(client Client) *Locker {
+	return &Locker{client: client}
+}
+
+// Mutex is a held lock. It is renewed in the background until released; if
+// a renewal finds the lock gone (for example after a long Redis outage), the
+// Lost channel is closed.
+type Mutex struct {
+	client Client
+	key    string
+	token  string
+	ttl    time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	lost     chan struct{}
+	renewed  chan struct{}
+}
+
+// TryAcquire takes the named lock for ttl (SET NX PX) without waiting. It
+// returns ErrNotAcquired if another holder has it. The lock is renewed every
+// ttl/3 until Release.
+func (l *Locker) TryAcquire(ctx context.Context, name string, ttl time.Duration) (*Mutex, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := keyPrefix + name
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotAcquired
+	}
+
+	m := &Mutex{
+		client:  l.client,
+		key:     key,
+		token:   token,
+		ttl:     ttl,
+		stop:    make(chan struct{}),
+		lost:    make(chan struct{}),
+		renewed: make(chan struct{}),
+	}
+	go m.renew()
+	return m, nil
+}
+
+// Claim claims the current period for name (the period's start is
+// now truncated to period) and reports whether this caller got it. Every
+// instance of a job that runs each period can call Claim on each tick and
+// only run when it returns true, so the job runs once per period however
+// many instances there are.
+func (l *Locker) Claim(ctx context.Context, name string, period time.Duration, now time.Time) (bool, error) {
+	slot := now.Truncate(period).Unix()
+	key := keyPrefix + name + ":" + strconv.FormatInt(slot, 10)
+	return l.client.SetNX(ctx, key, "1", period).Result()
+}
+
+// Lost is closed when the lock was found to have been lost while held.
+func (m *Mutex) Lost() <-chan struct{} {
+	return m.lost
+}
+
+// Release stops renewing the lock and deletes it. It returns ErrNotHeld if
+// the lock had already expired or been taken by someone else.
+func (m *Mutex) Release(ctx context.Context) error {
+	m.stopOnce.Do(func() { close(m.stop) })
+	<-m.renewed
+
+	n, err := m.client.Eval(ctx, releaseScript, []string{m.key}, m.token).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+func (m *Mutex) renew() {
+	defer close(m.renewed)
+
+	ticker := time.NewTicker(m.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), m.ttl/3)
+		n, err := m.client.Eval(ctx, renewScript, []string{m.key}, m.token, m.ttl.Milliseconds()).Int64()
+		cancel()
+		if err != nil {
+			// Transient errors are retried on the next tick; the lock is
+			// only lost once the key is gone or someone else holds it.
+			continue
+		}
+		if n == 0 {
+			close(m.lost)
+			return
+		}
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
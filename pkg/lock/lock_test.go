@@ -0,0 +1,160 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis implements Client in memory, running the lock scripts natively.
+type fakeRedis struct {
+	mu   sync.Mutex
+	keys map[string]fakeKey
+}
+
+type fakeKey struct {
+	value   string
+	expires time.Time
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{keys: make(map[string]fakeKey)}
+}
+
+func (f *fakeRedis) get(key string) (string, bool) {
+	k, ok := f.keys[key]
+	if !ok || time.Now().After(k.expires) {
+		return "", false
+	}
+	return k.value, true
+}
+
+func (f *fakeRedis) set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[key] = fakeKey{value: value, expires: time.Now().Add(time.Hour)}
+}
+
+func (f *fakeRedis) SetNX(_ context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.get(key); ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	f.keys[key] = fakeKey{value: value.(string), expires: time.Now().Add(expiration)}
+	return redis.NewBoolResult(true, nil)
+}
+
+func (f *fakeRedis) Eval(_ context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if v, ok := f.get(keys[0]); !ok || v != args[0].(string) {
+		return redis.NewCmdResult(int64(0), nil)
+	}
+	switch script {
+	case releaseScript:
+		delete(f.keys, keys[0])
+	case renewScript:
+		f.keys[keys[0]] = fakeKey{value: args[0].(string), expires: time.Now().Add(time.Duration(args[1].(int64)) * time.Millisecond)}
+	default:
+		return redis.NewCmdResult(nil, errors.New("unknown script"))
+	}
+	return redis.NewCmdResult(int64(1), nil)
+}
+
+func TestTryAcquire(t *testing.T) {
+	ctx := context.Background()
+	l := New(newFakeRedis())
+
+	m, err := l.TryAcquire(ctx, "job", time.Second)
+	if err != nil {
+		t.Fatalf("TryAcquire() error = %v", err)
+	}
+	if _, err := l.TryAcquire(ctx, "job", time.Second); !errors.Is(err, ErrNotAcquired) {
+		t.Errorf("second TryAcquire() error = %v, want ErrNotAcquired", err)
+	}
+	if _, err := l.TryAcquire(ctx, "other", time.Second); err != nil {
+		t.Errorf("TryAcquire() of another name error = %v", err)
+	}
+
+	if err := m.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	m, err = l.TryAcquire(ctx, "job", time.Second)
+	if err != nil {
+		t.Fatalf("TryAcquire() after release error = %v", err)
+	}
+	_ = m.Release(ctx)
+}
+
+func TestMutexIsRenewed(t *testing.T) {
+	ctx := context.Background()
+	l := New(newFakeRedis())
+
+	m, err := l.TryAcquire(ctx, "job", 60*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TryAcquire() error = %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	if _, err := l.TryAcquire(ctx, "job", time.Second); !errors.Is(err, ErrNotAcquired) {
+		t.Errorf("TryAcquire() past the TTL error = %v, want ErrNotAcquired while renewed", err)
+	}
+	if err := m.Release(ctx); err != nil {
+		t.Errorf("Release() error = %v", err)
+	}
+}
+
+func TestMutexLost(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	l := New(fake)
+
+	m, err := l.TryAcquire(ctx, "job", 60*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TryAcquire() error = %v", err)
+	}
+	fake.set(keyPrefix+"job", "someone-else")
+
+	select {
+	case <-m.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() not closed after the lock was taken over")
+	}
+	if err := m.Release(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Release() error = %v, want ErrNotHeld", err)
+	}
+	if v, _ := fake.get(keyPrefix + "job"); v != "someone-else" {
+		t.Error("Release() deleted a lock it no longer held")
+	}
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	l := New(newFakeRedis())
+	period := time.Minute
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"first in period", start.Add(5 * time.Second), true},
+		{"same period", start.Add(50 * time.Second), false},
+		{"next period", start.Add(65 * time.Second), true},
+	}
+	for _, tt := range tests {
+		got, err := l.Claim(ctx, "job", period, tt.at)
+		if err != nil {
+			t.Fatalf("%s: Claim() error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: Claim() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package paseto
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -23,19 +24,45 @@ type Maker interface {
 
 type pasetoMaker struct {
 	symmetricKey paseto.V4SymmetricKey
+	// previousKeys still verify tokens issued before the last key rotation.
+	previousKeys []paseto.V4SymmetricKey
 }
 
 func NewPasetoMaker(secret string) (Maker, error) {
+	return NewPasetoMakerWithKeys(secret, nil)
+}
+
+// NewPasetoMakerWithKeys creates a maker that signs new tokens with current
+// and also accepts tokens signed with any of the previous secrets, so the
+// signing key can be rotated without logging everyone out. Previous secrets
+// can be dropped once the tokens they signed have expired.
+func NewPasetoMakerWithKeys(current string, previous []string) (Maker, error) {
+	key, err := symmetricKey(current)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &pasetoMaker{symmetricKey: key}
+	for i, secret := range previous {
+		key, err := symmetricKey(secret)
+		if err != nil {
+			return nil, fmt.Errorf("previous key %d: %w", i+1, err)
+		}
+		m.previousKeys = append(m.previousKeys, key)
+	}
+	return m, nil
+}
+
+func symmetricKey(secret string) (paseto.V4SymmetricKey, error) {
 	if len(secret) < 32 {
-		return nil, fmt.Errorf("token secret must be at least 32 characters")
+		return paseto.V4SymmetricKey{}, fmt.Errorf("token secret must be at least 32 characters")
 	}
 
 	key, err := paseto.V4SymmetricKeyFromBytes([]byte(secret)[:32])
 	if err != nil {
-		return nil, fmt.Errorf("failed to create symmetric key: %w", err)
+		return paseto.V4SymmetricKey{}, fmt.Errorf("failed to create symmetric key: %w", err)
 	}
-
-	return &pasetoMaker{symmetricKey: key}, nil
+	return key, nil
 }
 
 func (m *pasetoMaker) CreateToken(userID uuid.UUID, email string, sessionID uuid.UUID, duration time.Duration) (string, *Claims, error) {
@@ -65,7 +92,7 @@ func (m *pasetoMaker) VerifyToken(tokenString string) (*Claims, error) {
 	parser.AddRule(paseto.NotExpired())
 	parser.AddRule(paseto.ValidAt(time.Now()))
 
-	token, err := parser.ParseV4Local(m.symmetricKey, tokenString, nil)
+	token, err := m.parse(parser, tokenString)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
@@ -111,3 +138,19 @@ func (m *pasetoMaker) VerifyToken(tokenString string) (*Claims, error) {
 		ExpiresAt: expiresAt,
 	}, nil
 }
+
+// parse decrypts the token with the current key and then each previous key.
+// A token that decrypts but fails a rule, such as being expired, is rejected
+// without trying the remaining keys.
+func (m *pasetoMaker) parse(parser paseto.Parser, tokenString string) (*paseto.Token, error) {
+	token, err := parser.ParseV4Local(m.symmetricKey, tokenString, nil)
+	if err == nil || errors.Is(err, paseto.RuleError{}) {
+		return token, err
+	}
+	for _, key := range m.previousKeys {
+		if token, prevErr := parser.ParseV4Local(key, tokenString, nil); prevErr == nil || errors.Is(prevErr, paseto.RuleError{}) {
+			return token, prevErr
+		}
+	}
+	return nil, err
+}
@@ -1,6 +1,7 @@
 package paseto
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -102,3 +103,68 @@ func TestShortSecret(t *testing.T) {
 		t.Fatal("expected error for short secret, got nil")
 	}
 }
+
+func TestPreviousKeyCanVerify(t *testing.T) {
+	const oldSecret = "first-secret-key-that-is-at-least-32-characters"
+	const newSecret = "second-secret-key-that-is-at-least-32-chars"
+
+	oldMaker, _ := NewPasetoMaker(oldSecret)
+	rotated, err := NewPasetoMakerWithKeys(newSecret, []string{oldSecret})
+	if err != nil {
+		t.Fatalf("failed to create maker: %v", err)
+	}
+
+	userID := uuid.New()
+	oldToken, _, err := oldMaker.CreateToken(userID, "test@example.com", uuid.New(), 15*time.Minute)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	verified, err := rotated.VerifyToken(oldToken)
+	if err != nil {
+		t.Fatalf("failed to verify token signed with previous key: %v", err)
+	}
+	if verified.UserID != userID {
+		t.Errorf("verified userID mismatch: got %v, want %v", verified.UserID, userID)
+	}
+
+	// New tokens are signed with the current key only.
+	newToken, _, err := rotated.CreateToken(userID, "test@example.com", uuid.New(), 15*time.Minute)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	if _, err := oldMaker.VerifyToken(newToken); err == nil {
+		t.Fatal("expected error when verifying a new token with the previous key, got nil")
+	}
+	current, _ := NewPasetoMaker(newSecret)
+	if _, err := current.VerifyToken(newToken); err != nil {
+		t.Fatalf("failed to verify new token with current key: %v", err)
+	}
+}
+
+func TestExpiredTokenWithPreviousKey(t *testing.T) {
+	const oldSecret = "first-secret-key-that-is-at-least-32-characters"
+
+	oldMaker, _ := NewPasetoMaker(oldSecret)
+	rotated, _ := NewPasetoMakerWithKeys("second-secret-key-that-is-at-least-32-chars", []string{oldSecret})
+
+	tokenStr, _, err := oldMaker.CreateToken(uuid.New(), "test@example.com", uuid.New(), -time.Minute)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	_, err = rotated.VerifyToken(tokenStr)
+	if err == nil {
+		t.Fatal("expected error for expired token, got nil")
+	}
+	if !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expected expiry error, got %v", err)
+	}
+}
+
+func TestShortPreviousSecret(t *testing.T) {
+	_, err := NewPasetoMakerWithKeys("test-secret-key-that-is-at-least-32-characters-long", []string{"short"})
+	if err == nil {
+		t.Fatal("expected error for short previous secret, got nil")
+	}
+}
@@ -0,0 +1,471 @@
+// Package sanitize cleans user-supplied markup before it is served to
+// visitors.
+package sanitize
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CSS parses a style sheet and rebuilds it from the rules and declarations
+// that pass an allowlist, dropping everything else. It returns the cleaned
+// style sheet and how many rules and declarations were dropped.
+//
+// Allowed are plain style rules and @media blocks of them, with properties
+// from a fixed list of visual properties. Values may use colors, lengths,
+// keywords, strings and a set of harmless functions (colors, gradients, math,
+// transforms, filters). url() is only accepted in background properties and
+// only for https URLs. The output is safe to place inside a <style> element.
+func CSS(input string) (string, int) {
+	s := &cssSanitizer{}
+	nodes := parseComponents(tokenize(input))
+
+	var b strings.Builder
+	for _, rule := range splitRules(nodes) {
+		s.writeRule(&b, rule, "")
+	}
+	return strings.TrimSuffix(b.String(), "\n"), s.dropped
+}
+
+// node is a component value: a single token, or a block or function with its
+// contents.
+type node struct {
+	tok      token
+	children []node
+}
+
+func (n node) isBlock(open tokenType) bool { return n.tok.typ == open && n.children != nil }
+
+// parseComponents groups tokens into component values, matching brackets.
+// Unclosed blocks are closed at the end of input, as browsers do.
+func parseComponents(tokens []token) []node {
+	nodes, _ := parseUntil(tokens, 0, tokEOF)
+	return nodes
+}
+
+func parseUntil(tokens []token, i int, closing tokenType) ([]node, int) {
+	var nodes []node
+	for i < len(tokens) {
+		tok := tokens[i]
+		i++
+		if tok.typ == closing {
+			return nodes, i
+		}
+
+		var close tokenType
+		switch tok.typ {
+		case tokOpenCurly:
+			close = tokCloseCurly
+		case tokOpenSquare:
+			close = tokCloseSquare
+		case tokOpenParen, tokFunction:
+			close = tokCloseParen
+		default:
+			nodes = append(nodes, node{tok: tok})
+			continue
+		}
+
+		var children []node
+		children, i = parseUntil(tokens, i, close)
+		if children == nil {
+			children = []node{}
+		}
+		nodes = append(nodes, node{tok: tok, children: children})
+	}
+	return nodes, i
+}
+
+// rule is a qualified rule or at-rule. body is nil for at-rules ending in a
+// semicolon.
+type rule struct {
+	atKeyword string
+	prelude   []node
+	body      []node
+}
+
+func splitRules(nodes []node) []rule {
+	var rules []rule
+	for i := 0; i < len(nodes); i++ {
+		n := nodes[i]
+		switch n.tok.typ {
+		case tokWhitespace, tokCDO, tokCDC, tokSemicolon:
+			continue
+		}
+
+		r := rule{}
+		if n.tok.typ == tokAtKeyword {
+			r.atKeyword = strings.ToLower(n.tok.value)
+			i++
+		}
+		for ; i < len(nodes); i++ {
+			if nodes[i].isBlock(tokOpenCurly) {
+				r.body = nodes[i].children
+				break
+			}
+			if r.atKeyword != "" && nodes[i].tok.typ == tokSemicolon {
+				break
+			}
+			r.prelude = append(r.prelude, nodes[i])
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+type cssSanitizer struct {
+	dropped int
+}
+
+func (s *cssSanitizer) writeRule(b *strings.Builder, r rule, indent string) {
+	switch {
+	case r.atKeyword == "media" && indent == "" && r.body != nil:
+		s.writeMedia(b, r)
+	case r.atKeyword == "" && r.body != nil:
+		s.writeStyleRule(b, r, indent)
+	default:
+		s.dropped++
+	}
+}
+
+func (s *cssSanitizer) writeMedia(b *strings.Builder, r rule) {
+	query, ok := serializeChecked(trimWhitespace(r.prelude), isMediaQueryNode)
+	if !ok || query == "" {
+		s.dropped++
+		return
+	}
+
+	var inner strings.Builder
+	for _, nested := range splitRules(r.body) {
+		s.writeRule(&inner, nested, "  ")
+	}
+	if inner.Len() == 0 {
+		return
+	}
+	b.WriteString("@media " + query + " {\n")
+	b.WriteString(inner.String())
+	b.WriteString("}\n")
+}
+
+func (s *cssSanitizer) writeStyleRule(b *strings.Builder, r rule, indent string) {
+	selector, ok := serializeChecked(trimWhitespace(r.prelude), isSelectorNode)
+	if !ok || selector == "" {
+		s.dropped++
+		return
+	}
+
+	var decls []string
+	for _, group := range splitOn(r.body, tokSemicolon) {
+		group = trimWhitespace(group)
+		if len(group) == 0 {
+			continue
+		}
+		decl, ok := sanitizeDeclaration(group)
+		if !ok {
+			s.dropped++
+			continue
+		}
+		decls = append(decls, decl)
+	}
+	if len(decls) == 0 {
+		return
+	}
+
+	b.WriteString(indent + selector + " {\n")
+	for _, d := range decls {
+		b.WriteString(indent + "  " + d + ";\n")
+	}
+	b.WriteString(indent + "}\n")
+}
+
+// sanitizeDeclaration checks a single "property: value" declaration and
+// returns its serialized form.
+func sanitizeDeclaration(nodes []node) (string, bool) {
+	if nodes[0].tok.typ != tokIdent {
+		return "", false
+	}
+	prop := strings.ToLower(nodes[0].tok.value)
+	if !allowedProperties[prop] {
+		return "", false
+	}
+
+	rest := trimWhitespace(nodes[1:])
+	if len(rest) == 0 || rest[0].tok.typ != tokColon {
+		return "", false
+	}
+	value := trimWhitespace(rest[1:])
+
+	important := false
+	if n := len(value); n >= 2 && value[n-1].tok.typ == tokIdent &&
+		strings.EqualFold(value[n-1].tok.value, "important") {
+		bang := trimWhitespace(value[:n-1])
+		if m := len(bang); m > 0 && bang[m-1].tok.typ == tokDelim && bang[m-1].tok.value == "!" {
+			important = true
+			value = trimWhitespace(bang[:m-1])
+		}
+	}
+	if len(value) == 0 {
+		return "", false
+	}
+
+	check := isValueNode
+	if urlProperties[prop] {
+		check = isValueOrImageURLNode
+	}
+	serialized, ok := serializeChecked(value, check)
+	if !ok {
+		return "", false
+	}
+	if important {
+		serialized += " !important"
+	}
+	return serializeIdent(prop) + ": " + serialized, true
+}
+
+// serializeChecked serializes nodes if check accepts every one of them.
+func serializeChecked(nodes []node, check func(node) bool) (string, bool) {
+	var b strings.Builder
+	for _, n := range nodes {
+		if !check(n) {
+			return "", false
+		}
+		writeNode(&b, n)
+	}
+	return b.String(), true
+}
+
+func isSelectorNode(n node) bool {
+	switch n.tok.typ {
+	case tokIdent, tokHash, tokWhitespace, tokComma, tokColon:
+		return true
+	case tokDelim:
+		return strings.Contains(".*>+~", n.tok.value)
+	case tokOpenSquare:
+		return n.isBlock(tokOpenSquare) && allChildren(n, isAttributeSelectorNode)
+	case tokFunction:
+		name := strings.ToLower(n.tok.value)
+		if strings.HasPrefix(name, "nth-") {
+			return selectorFunctions[name] && allChildren(n, isNthNode)
+		}
+		return selectorFunctions[name] && allChildren(n, isSelectorNode)
+	}
+	return false
+}
+
+func isAttributeSelectorNode(n node) bool {
+	switch n.tok.typ {
+	case tokIdent, tokString, tokWhitespace:
+		return true
+	case tokDelim:
+		return strings.Contains("=~|^$*", n.tok.value)
+	}
+	return false
+}
+
+func isNthNode(n node) bool {
+	switch n.tok.typ {
+	case tokIdent, tokNumber, tokDimension, tokWhitespace:
+		return true
+	case tokDelim:
+		return n.tok.value == "+" || n.tok.value == "-"
+	}
+	return false
+}
+
+func isMediaQueryNode(n node) bool {
+	switch n.tok.typ {
+	case tokIdent, tokNumber, tokDimension, tokWhitespace, tokComma, tokColon:
+		return true
+	case tokDelim:
+		// No "<": range syntax isn't worth risking "</style" in the output.
+		return n.tok.value == "/" || n.tok.value == "=" || n.tok.value == ">"
+	case tokOpenParen:
+		return n.isBlock(tokOpenParen) && allChildren(n, isMediaQueryNode)
+	}
+	return false
+}
+
+func isValueNode(n node) bool {
+	switch n.tok.typ {
+	case tokIdent, tokNumber, tokPercentage, tokDimension, tokHash, tokString, tokWhitespace, tokComma:
+		return true
+	case tokDelim:
+		return strings.Contains("/+-*", n.tok.value)
+	case tokOpenParen:
+		return n.isBlock(tokOpenParen) && allChildren(n, isValueNode)
+	case tokFunction:
+		return valueFunctions[strings.ToLower(n.tok.value)] && allChildren(n, isValueNode)
+	}
+	return false
+}
+
+func isValueOrImageURLNode(n node) bool {
+	switch {
+	case n.tok.typ == tokURL:
+		return isSafeImageURL(n.tok.value)
+	case n.tok.typ == tokFunction && strings.EqualFold(n.tok.value, "url"):
+		args := trimWhitespace(n.children)
+		return len(args) == 1 && args[0].tok.typ == tokString && isSafeImageURL(args[0].tok.value)
+	case n.tok.typ == tokFunction:
+		return valueFunctions[strings.ToLower(n.tok.value)] && allChildren(n, isValueOrImageURLNode)
+	}
+	return isValueNode(n)
+}
+
+// isSafeImageURL accepts absolute https URLs without credentials.
+func isSafeImageURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && u.Host != "" && u.User == nil
+}
+
+func allChildren(n node, check func(node) bool) bool {
+	for _, c := range n.children {
+		if !check(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitOn(nodes []node, sep tokenType) [][]node {
+	var groups [][]node
+	start := 0
+	for i, n := range nodes {
+		if n.tok.typ == sep {
+			groups = append(groups, nodes[start:i])
+			start = i + 1
+		}
+	}
+	return append(groups, nodes[start:])
+}
+
+func trimWhitespace(nodes []node) []node {
+	for len(nodes) > 0 && nodes[0].tok.typ == tokWhitespace {
+		nodes = nodes[1:]
+	}
+	for len(nodes) > 0 && nodes[len(nodes)-1].tok.typ == tokWhitespace {
+		nodes = nodes[:len(nodes)-1]
+	}
+	return nodes
+}
+
+// writeNode serializes a node so that it tokenizes back to the same thing.
+// Strings and URLs are always re-quoted with "<", ">" and "&" escaped, so
+// nothing written can close the surrounding <style> element.
+func writeNode(b *strings.Builder, n node) {
+	t := n.tok
+	switch t.typ {
+	case tokIdent:
+		b.WriteString(serializeIdent(t.value))
+	case tokFunction:
+		b.WriteString(serializeIdent(t.value) + "(")
+	case tokHash:
+		b.WriteString("#" + serializeName(t.value))
+	case tokString:
+		b.WriteString(serializeString(t.value))
+	case tokURL:
+		b.WriteString("url(" + serializeString(t.value) + ")")
+	case tokDelim:
+		b.WriteString(t.value)
+	case tokNumber:
+		b.WriteString(t.num)
+	case tokPercentage:
+		b.WriteString(t.num + "%")
+	case tokDimension:
+		unit := serializeIdent(t.value)
+		if u := []rune(t.value); len(u) > 1 && (u[0] == 'e' || u[0] == 'E') &&
+			(isDigit(u[1]) || u[1] == '+' || u[1] == '-') {
+			// Escape the "e" so "1e3x" doesn't read back as an exponent.
+			unit = fmt.Sprintf("\\%x ", u[0]) + serializeName(string(u[1:]))
+		}
+		b.WriteString(t.num + unit)
+	case tokWhitespace:
+		b.WriteString(" ")
+	case tokColon:
+		b.WriteString(":")
+	case tokComma:
+		b.WriteString(",")
+	case tokSemicolon:
+		b.WriteString(";")
+	case tokOpenSquare:
+		b.WriteString("[")
+	case tokOpenParen:
+		b.WriteString("(")
+	case tokOpenCurly:
+		b.WriteString("{")
+	}
+
+	if n.children == nil {
+		return
+	}
+	for _, c := range n.children {
+		writeNode(b, c)
+	}
+	switch t.typ {
+	case tokOpenSquare:
+		b.WriteString("]")
+	case tokOpenCurly:
+		b.WriteString("}")
+	default:
+		b.WriteString(")")
+	}
+}
+
+// serializeIdent escapes an identifier so it reads back as the same ident.
+func serializeIdent(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i, c := range r {
+		switch {
+		case i == 0 && isDigit(c),
+			i == 1 && r[0] == '-' && isDigit(c),
+			i == 0 && c == '-' && len(r) == 1:
+			b.WriteString(escapeCodePoint(c))
+		case isNameChar(c):
+			b.WriteRune(c)
+		default:
+			b.WriteString(escapeCodePoint(c))
+		}
+	}
+	return b.String()
+}
+
+// serializeName escapes a name, which unlike an ident may start with a digit.
+func serializeName(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if isNameChar(c) {
+			b.WriteRune(c)
+		} else {
+			b.WriteString(escapeCodePoint(c))
+		}
+	}
+	return b.String()
+}
+
+func serializeString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' || c < 0x20 || c == 0x7F:
+			b.WriteString(escapeCodePoint(c))
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// escapeCodePoint writes c as a hex escape. The trailing space ends the
+// escape and is consumed by the tokenizer.
+func escapeCodePoint(c rune) string {
+	return fmt.Sprintf("\\%x ", c)
+}
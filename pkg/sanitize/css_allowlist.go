@@ -0,0 +1,71 @@
+package sanitize
+
+func setOf(items ...string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, it := range items {
+		m[it] = true
+	}
+	return m
+}
+
+// allowedProperties are the properties custom CSS may set. They change how
+// the page looks but not what it says or where it sends visitors, so
+// content, positioning and anything that loads resources other than
+// background images are left out.
+var allowedProperties = setOf(
+	"color", "opacity", "visibility", "cursor",
+
+	"background", "background-color", "background-image", "background-position",
+	"background-size", "background-repeat", "background-clip", "background-origin",
+	"background-blend-mode",
+
+	"border", "border-color", "border-style", "border-width", "border-radius",
+	"border-top", "border-right", "border-bottom", "border-left",
+	"border-top-color", "border-right-color", "border-bottom-color", "border-left-color",
+	"border-top-width", "border-right-width", "border-bottom-width", "border-left-width",
+	"border-top-left-radius", "border-top-right-radius",
+	"border-bottom-left-radius", "border-bottom-right-radius",
+	"outline", "outline-color", "outline-style", "outline-width", "outline-offset",
+	"box-shadow", "text-shadow",
+
+	"margin", "margin-top", "margin-right", "margin-bottom", "margin-left",
+	"padding", "padding-top", "padding-right", "padding-bottom", "padding-left",
+	"width", "height", "min-width", "max-width", "min-height", "max-height",
+	"box-sizing", "aspect-ratio", "object-fit", "overflow", "overflow-x", "overflow-y",
+
+	"display", "flex", "flex-direction", "flex-wrap", "flex-grow", "flex-shrink",
+	"flex-basis", "justify-content", "align-items", "align-content", "align-self",
+	"gap", "row-gap", "column-gap", "order",
+
+	"font", "font-family", "font-size", "font-weight", "font-style", "font-variant",
+	"line-height", "letter-spacing", "word-spacing", "text-align", "text-decoration",
+	"text-decoration-color", "text-decoration-style", "text-transform", "text-indent",
+	"text-overflow", "white-space", "word-break", "overflow-wrap", "vertical-align",
+	"list-style-type",
+
+	"transform", "transform-origin", "transition", "transition-property",
+	"transition-duration", "transition-timing-function", "transition-delay",
+	"filter", "backdrop-filter", "mix-blend-mode",
+)
+
+// urlProperties may contain url() values, restricted to https images.
+var urlProperties = setOf("background", "background-image")
+
+// valueFunctions are the functions allowed in property values.
+var valueFunctions = setOf(
+	"rgb", "rgba", "hsl", "hsla", "hwb", "lab", "lch", "oklab", "oklch",
+	"linear-gradient", "radial-gradient", "conic-gradient",
+	"repeating-linear-gradient", "repeating-radial-gradient", "repeating-conic-gradient",
+	"calc", "min", "max", "clamp",
+	"translate", "translatex", "translatey", "rotate", "scale", "scalex", "scaley",
+	"skew", "skewx", "skewy", "matrix",
+	"cubic-bezier", "steps",
+	"blur", "brightness", "contrast", "drop-shadow", "grayscale", "hue-rotate",
+	"invert", "saturate", "sepia",
+)
+
+// selectorFunctions are the functional pseudo-classes allowed in selectors.
+var selectorFunctions = setOf(
+	"not", "is", "where",
+	"nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type",
+)
@@ -0,0 +1,431 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// This is a tokenizer for the subset of CSS Syntax Level 3 the sanitizer
+// needs. It follows the spec's tokenization rules closely, in particular for
+// escapes and comments, because that's where filters that match on raw text
+// get bypassed: "\75 rl(" is a url( token and "ur/**/l(" is not.
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokIdent
+	tokFunction
+	tokAtKeyword
+	tokHash
+	tokString
+	tokBadString
+	tokURL
+	tokBadURL
+	tokDelim
+	tokNumber
+	tokPercentage
+	tokDimension
+	tokWhitespace
+	tokCDO
+	tokCDC
+	tokColon
+	tokSemicolon
+	tokComma
+	tokOpenSquare
+	tokCloseSquare
+	tokOpenParen
+	tokCloseParen
+	tokOpenCurly
+	tokCloseCurly
+)
+
+type token struct {
+	typ tokenType
+	// value is the unescaped name for idents, functions, at-keywords and
+	// hashes, the contents of strings and URLs, and the character of a delim.
+	value string
+	// num is the numeric part of numbers, percentages and dimensions; value
+	// holds a dimension's unit.
+	num string
+}
+
+const replacementChar = '�'
+
+type tokenizer struct {
+	src []rune
+	pos int
+}
+
+func tokenize(input string) []token {
+	t := &tokenizer{src: preprocess(input)}
+	var tokens []token
+	for {
+		tok := t.next()
+		if tok.typ == tokEOF {
+			return tokens
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+// preprocess normalizes newlines and replaces NULs and invalid UTF-8.
+func preprocess(input string) []rune {
+	input = strings.ReplaceAll(input, "\r\n", "\n")
+	input = strings.NewReplacer("\r", "\n", "\f", "\n", "\x00", string(replacementChar)).Replace(input)
+	if !utf8.ValidString(input) {
+		input = strings.ToValidUTF8(input, string(replacementChar))
+	}
+	return []rune(input)
+}
+
+func (t *tokenizer) peek(n int) rune {
+	if t.pos+n < len(t.src) {
+		return t.src[t.pos+n]
+	}
+	return -1
+}
+
+func (t *tokenizer) next() token {
+	t.skipComments()
+	c := t.peek(0)
+	switch {
+	case c == -1:
+		return token{typ: tokEOF}
+	case isWhitespace(c):
+		for isWhitespace(t.peek(0)) {
+			t.pos++
+		}
+		return token{typ: tokWhitespace}
+	case c == '"' || c == '\'':
+		t.pos++
+		return t.consumeString(c)
+	case c == '#':
+		if isNameChar(t.peek(1)) || isValidEscape(t.peek(1), t.peek(2)) {
+			t.pos++
+			return token{typ: tokHash, value: t.consumeName()}
+		}
+	case c == '(':
+		t.pos++
+		return token{typ: tokOpenParen}
+	case c == ')':
+		t.pos++
+		return token{typ: tokCloseParen}
+	case c == '[':
+		t.pos++
+		return token{typ: tokOpenSquare}
+	case c == ']':
+		t.pos++
+		return token{typ: tokCloseSquare}
+	case c == '{':
+		t.pos++
+		return token{typ: tokOpenCurly}
+	case c == '}':
+		t.pos++
+		return token{typ: tokCloseCurly}
+	case c == ',':
+		t.pos++
+		return token{typ: tokComma}
+	case c == ':':
+		t.pos++
+		return token{typ: tokColon}
+	case c == ';':
+		t.pos++
+		return token{typ: tokSemicolon}
+	case c == '+' || c == '.':
+		if startsNumber(c, t.peek(1), t.peek(2)) {
+			return t.consumeNumeric()
+		}
+	case c == '-':
+		if startsNumber(c, t.peek(1), t.peek(2)) {
+			return t.consumeNumeric()
+		}
+		if t.peek(1) == '-' && t.peek(2) == '>' {
+			t.pos += 3
+			return token{typ: tokCDC}
+		}
+		if startsIdent(c, t.peek(1), t.peek(2)) {
+			return t.consumeIdentLike()
+		}
+	case c == '<':
+		if t.peek(1) == '!' && t.peek(2) == '-' && t.peek(3) == '-' {
+			t.pos += 4
+			return token{typ: tokCDO}
+		}
+	case c == '@':
+		if startsIdent(t.peek(1), t.peek(2), t.peek(3)) {
+			t.pos++
+			return token{typ: tokAtKeyword, value: t.consumeName()}
+		}
+	case c == '\\':
+		if isValidEscape(c, t.peek(1)) {
+			return t.consumeIdentLike()
+		}
+	case isDigit(c):
+		return t.consumeNumeric()
+	case isNameStart(c):
+		return t.consumeIdentLike()
+	}
+
+	t.pos++
+	return token{typ: tokDelim, value: string(c)}
+}
+
+func (t *tokenizer) skipComments() {
+	for t.peek(0) == '/' && t.peek(1) == '*' {
+		end := -1
+		for i := t.pos + 2; i+1 < len(t.src); i++ {
+			if t.src[i] == '*' && t.src[i+1] == '/' {
+				end = i + 2
+				break
+			}
+		}
+		if end == -1 {
+			t.pos = len(t.src)
+			return
+		}
+		t.pos = end
+	}
+}
+
+func (t *tokenizer) consumeString(quote rune) token {
+	var b strings.Builder
+	for {
+		c := t.peek(0)
+		switch {
+		case c == -1:
+			return token{typ: tokString, value: b.String()}
+		case c == quote:
+			t.pos++
+			return token{typ: tokString, value: b.String()}
+		case c == '\n':
+			return token{typ: tokBadString}
+		case c == '\\':
+			switch t.peek(1) {
+			case -1:
+				t.pos++
+			case '\n':
+				t.pos += 2
+			default:
+				t.pos++
+				b.WriteRune(t.consumeEscape())
+			}
+		default:
+			t.pos++
+			b.WriteRune(c)
+		}
+	}
+}
+
+// consumeEscape consumes an escape after its backslash.
+func (t *tokenizer) consumeEscape() rune {
+	c := t.peek(0)
+	if c == -1 {
+		return replacementChar
+	}
+	t.pos++
+	if !isHexDigit(c) {
+		return c
+	}
+
+	v := hexValue(c)
+	for i := 0; i < 5 && isHexDigit(t.peek(0)); i++ {
+		v = v*16 + hexValue(t.peek(0))
+		t.pos++
+	}
+	if isWhitespace(t.peek(0)) {
+		t.pos++
+	}
+	if v == 0 || (v >= 0xD800 && v <= 0xDFFF) || v > utf8.MaxRune {
+		return replacementChar
+	}
+	return rune(v)
+}
+
+func (t *tokenizer) consumeName() string {
+	var b strings.Builder
+	for {
+		c := t.peek(0)
+		switch {
+		case isNameChar(c):
+			t.pos++
+			b.WriteRune(c)
+		case isValidEscape(c, t.peek(1)):
+			t.pos++
+			b.WriteRune(t.consumeEscape())
+		default:
+			return b.String()
+		}
+	}
+}
+
+func (t *tokenizer) consumeNumber() string {
+	start := t.pos
+	if c := t.peek(0); c == '+' || c == '-' {
+		t.pos++
+	}
+	for isDigit(t.peek(0)) {
+		t.pos++
+	}
+	if t.peek(0) == '.' && isDigit(t.peek(1)) {
+		t.pos += 2
+		for isDigit(t.peek(0)) {
+			t.pos++
+		}
+	}
+	if c := t.peek(0); c == 'e' || c == 'E' {
+		n := t.peek(1)
+		if isDigit(n) || ((n == '+' || n == '-') && isDigit(t.peek(2))) {
+			t.pos += 2
+			for isDigit(t.peek(0)) {
+				t.pos++
+			}
+		}
+	}
+	return string(t.src[start:t.pos])
+}
+
+func (t *tokenizer) consumeNumeric() token {
+	num := t.consumeNumber()
+	if startsIdent(t.peek(0), t.peek(1), t.peek(2)) {
+		return token{typ: tokDimension, num: num, value: t.consumeName()}
+	}
+	if t.peek(0) == '%' {
+		t.pos++
+		return token{typ: tokPercentage, num: num}
+	}
+	return token{typ: tokNumber, num: num}
+}
+
+func (t *tokenizer) consumeIdentLike() token {
+	name := t.consumeName()
+	if t.peek(0) != '(' {
+		return token{typ: tokIdent, value: name}
+	}
+	t.pos++
+
+	if strings.EqualFold(name, "url") {
+		for isWhitespace(t.peek(0)) && isWhitespace(t.peek(1)) {
+			t.pos++
+		}
+		c := t.peek(0)
+		if isWhitespace(c) {
+			c = t.peek(1)
+		}
+		// url("...") is a function holding a string; only the unquoted
+		// form is a url token.
+		if c != '"' && c != '\'' {
+			return t.consumeURL()
+		}
+	}
+	return token{typ: tokFunction, value: name}
+}
+
+func (t *tokenizer) consumeURL() token {
+	var b strings.Builder
+	for isWhitespace(t.peek(0)) {
+		t.pos++
+	}
+	for {
+		c := t.peek(0)
+		switch {
+		case c == ')':
+			t.pos++
+			return token{typ: tokURL, value: b.String()}
+		case c == -1:
+			return token{typ: tokURL, value: b.String()}
+		case isWhitespace(c):
+			for isWhitespace(t.peek(0)) {
+				t.pos++
+			}
+			if n := t.peek(0); n == ')' || n == -1 {
+				continue
+			}
+			t.consumeBadURLRemnants()
+			return token{typ: tokBadURL}
+		case c == '"' || c == '\'' || c == '(' || isNonPrintable(c):
+			t.consumeBadURLRemnants()
+			return token{typ: tokBadURL}
+		case c == '\\':
+			if !isValidEscape(c, t.peek(1)) {
+				t.consumeBadURLRemnants()
+				return token{typ: tokBadURL}
+			}
+			t.pos++
+			b.WriteRune(t.consumeEscape())
+		default:
+			t.pos++
+			b.WriteRune(c)
+		}
+	}
+}
+
+func (t *tokenizer) consumeBadURLRemnants() {
+	for {
+		c := t.peek(0)
+		switch {
+		case c == -1:
+			return
+		case c == ')':
+			t.pos++
+			return
+		case isValidEscape(c, t.peek(1)):
+			t.pos++
+			t.consumeEscape()
+		default:
+			t.pos++
+		}
+	}
+}
+
+func isWhitespace(c rune) bool { return c == ' ' || c == '\t' || c == '\n' }
+func isDigit(c rune) bool      { return c >= '0' && c <= '9' }
+
+func isHexDigit(c rune) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c rune) int {
+	switch {
+	case isDigit(c):
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	default:
+		return int(c-'A') + 10
+	}
+}
+
+func isNameStart(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c >= 0x80
+}
+
+func isNameChar(c rune) bool { return isNameStart(c) || isDigit(c) || c == '-' }
+
+func isNonPrintable(c rune) bool {
+	return (c >= 0 && c <= 0x08) || c == 0x0B || (c >= 0x0E && c <= 0x1F) || c == 0x7F
+}
+
+func isValidEscape(a, b rune) bool { return a == '\\' && b != '\n' && b != -1 }
+
+func startsIdent(a, b, c rune) bool {
+	switch {
+	case a == '-':
+		return isNameStart(b) || b == '-' || isValidEscape(b, c)
+	case a == '\\':
+		return isValidEscape(a, b)
+	default:
+		return isNameStart(a)
+	}
+}
+
+func startsNumber(a, b, c rune) bool {
+	switch {
+	case a == '+' || a == '-':
+		return isDigit(b) || (b == '.' && isDigit(c))
+	case a == '.':
+		return isDigit(b)
+	default:
+		return isDigit(a)
+	}
+}
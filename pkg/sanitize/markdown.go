@@ -0,0 +1,22 @@
+package sanitize
+
+import "strings"
+
+// markdownSpecial holds the ASCII punctuation that can start Markdown or
+// inline HTML syntax. CommonMark lets any of it be escaped with a backslash.
+const markdownSpecial = "\\`*_{}[]()#+-.!|<>~=&"
+
+// Markdown escapes text so that a Markdown renderer shows it literally:
+// no emphasis, links, images, headings, lists or inline HTML. Line breaks
+// are kept.
+func Markdown(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		if r < 0x80 && strings.ContainsRune(markdownSpecial, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,21 @@
+package sanitize
+
+import "testing"
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"plain words", "plain words"},
+		{"**bold** and _em_", `\*\*bold\*\* and \_em\_`},
+		{"[click](javascript:alert(1))", `\[click\]\(javascript:alert\(1\)\)`},
+		{"![img](x.png)", `\!\[img\]\(x\.png\)`},
+		{"# heading\n- item", "\\# heading\n\\- item"},
+		{"<script>&amp;", `\<script\>\&amp;`},
+		{`back\slash`, `back\\slash`},
+		{"naïve café", "naïve café"},
+	}
+	for _, tt := range tests {
+		if got := Markdown(tt.in); got != tt.want {
+			t.Errorf("Markdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
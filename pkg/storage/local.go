@@ -11,6 +11,7 @@ import (
 // LocalStorage implements ObjectStorage using the local filesystem.
 // This is intended for development and testing environments.
 type LocalStorage struct {
+	keyspace
 	basePath string
 	baseURL  string
 }
@@ -21,8 +22,9 @@ var _ ObjectStorage = (*LocalStorage)(nil)
 // NewLocalStorage creates a new LocalStorage instance.
 // basePath is the root directory for stored files (defaults to "./data/uploads/"
 // when empty). baseURL is the public URL prefix used to construct download URLs
-// (e.g. "http://localhost:8080/uploads/").
-func NewLocalStorage(basePath string, baseURL string) *LocalStorage {
+// (e.g. "http://localhost:8080/uploads/"). Keys are stored under
+// opts.KeyPrefix, as files below basePath.
+func NewLocalStorage(basePath string, baseURL string, opts Options) *LocalStorage {
 	if basePath == "" {
 		basePath = "./data/uploads/"
 	}
@@ -34,6 +36,7 @@ func NewLocalStorage(basePath string, baseURL string) *LocalStorage {
 		baseURL += "/"
 	}
 	return &LocalStorage{
+		keyspace: newKeyspace(opts),
 		basePath: basePath,
 		baseURL:  baseURL,
 	}
@@ -42,7 +45,7 @@ func NewLocalStorage(basePath string, baseURL string) *LocalStorage {
 // Upload writes data to basePath/key, creating intermediate directories as
 // needed. It returns the public URL for the newly stored file.
 func (l *LocalStorage) Upload(_ context.Context, key string, data []byte, _ string) (string, error) {
-	fullPath := filepath.Join(l.basePath, key)
+	fullPath := filepath.Join(l.basePath, l.full(key))
 
 	dir := filepath.Dir(fullPath)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -58,7 +61,7 @@ func (l *LocalStorage) Upload(_ context.Context, key string, data []byte, _ stri
 
 // Get reads the file at basePath/key and returns its contents.
 func (l *LocalStorage) Get(_ context.Context, key string) ([]byte, error) {
-	fullPath := filepath.Join(l.basePath, key)
+	fullPath := filepath.Join(l.basePath, l.full(key))
 
 	data, err := os.ReadFile(fullPath)
 	if err != nil {
@@ -73,7 +76,7 @@ func (l *LocalStorage) Get(_ context.Context, key string) ([]byte, error) {
 
 // Delete removes the file at basePath/key.
 func (l *LocalStorage) Delete(_ context.Context, key string) error {
-	fullPath := filepath.Join(l.basePath, key)
+	fullPath := filepath.Join(l.basePath, l.full(key))
 
 	if err := os.Remove(fullPath); err != nil {
 		if os.IsNotExist(err) {
@@ -85,7 +88,25 @@ func (l *LocalStorage) Delete(_ context.Context, key string) error {
 	return nil
 }
 
+// DeletePrefix removes the directory basePath/prefix and everything in it.
+func (l *LocalStorage) DeletePrefix(_ context.Context, prefix string) error {
+	if !strings.HasSuffix(prefix, "/") || strings.Trim(prefix, "/") == "" {
+		return fmt.Errorf("local storage: invalid prefix %q", prefix)
+	}
+	dir := filepath.Join(l.basePath, l.full(prefix))
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("local storage: deleting %s: %w", dir, err)
+	}
+	return nil
+}
+
 // GetURL returns the public URL for the given key by joining baseURL and key.
 func (l *LocalStorage) GetURL(key string) string {
-	return l.baseURL + key
+	return l.baseURL + l.full(key)
+}
+
+// KeyForURL strips GetURL's prefix from url.
+func (l *LocalStorage) KeyForURL(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, l.GetURL(""))
+	return key, ok && key != ""
 }
@@ -13,22 +13,24 @@ import (
 // available, replace the method bodies with real PutObject / GetObject /
 // DeleteObject calls.
 type S3Storage struct {
+	keyspace
 	cfg config.S3Config
 }
 
 // Compile-time check that S3Storage satisfies ObjectStorage.
 var _ ObjectStorage = (*S3Storage)(nil)
 
-// NewS3Storage creates a new S3Storage instance.
+// NewS3Storage creates a new S3Storage instance storing keys under
+// opts.KeyPrefix in the configured bucket.
 // Returns an error if required configuration fields are missing.
-func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
+func NewS3Storage(cfg config.S3Config, opts Options) (*S3Storage, error) {
 	if cfg.Bucket == "" {
 		return nil, fmt.Errorf("s3: bucket name is required")
 	}
 	if cfg.Region == "" {
 		return nil, fmt.Errorf("s3: region is required")
 	}
-	return &S3Storage{cfg: cfg}, nil
+	return &S3Storage{keyspace: newKeyspace(opts), cfg: cfg}, nil
 }
 
 // Upload stores data under the given key in S3.
@@ -49,6 +51,12 @@ func (s *S3Storage) Delete(_ context.Context, _ string) error {
 	return fmt.Errorf("s3: delete not implemented — AWS SDK is not yet integrated")
 }
 
+// DeletePrefix removes every object under prefix from S3.
+// Stub: returns an error until the AWS SDK is integrated.
+func (s *S3Storage) DeletePrefix(_ context.Context, _ string) error {
+	return fmt.Errorf("s3: delete prefix not implemented — AWS SDK is not yet integrated")
+}
+
 // GetURL returns the public URL for the given key.
 // If a custom endpoint is configured (e.g. MinIO), the URL uses
 // {endpoint}/{bucket}/{key}. Otherwise it uses the standard S3 virtual-hosted
@@ -56,7 +64,13 @@ func (s *S3Storage) Delete(_ context.Context, _ string) error {
 func (s *S3Storage) GetURL(key string) string {
 	if s.cfg.Endpoint != "" {
 		endpoint := strings.TrimRight(s.cfg.Endpoint, "/")
-		return fmt.Sprintf("%s/%s/%s", endpoint, s.cfg.Bucket, key)
+		return fmt.Sprintf("%s/%s/%s", endpoint, s.cfg.Bucket, s.full(key))
 	}
-	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.cfg.Bucket, s.cfg.Region, key)
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.cfg.Bucket, s.cfg.Region, s.full(key))
+}
+
+// KeyForURL strips GetURL's prefix from url.
+func (s *S3Storage) KeyForURL(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.GetURL(""))
+	return key, ok && key != ""
 }
@@ -1,6 +1,11 @@
 package storage
 
-import "context"
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
 
 // ObjectStorage abstracts file storage operations (S3, local filesystem, etc.).
 type ObjectStorage interface {
@@ -8,4 +13,56 @@ type ObjectStorage interface {
 	Get(ctx context.Context, key string) ([]byte, error)
 	Delete(ctx context.Context, key string) error
 	GetURL(key string) string
+	// DeletePrefix removes every object under prefix, a key ending in "/".
+	DeletePrefix(ctx context.Context, prefix string) error
+	// KeyForURL returns the key of the object served at url, or false when
+	// url isn't one of this storage's.
+	KeyForURL(url string) (string, bool)
+	// WorkspaceKey returns the key a workspace's asset is stored under:
+	// key itself, or key under the workspace's own prefix when workspace
+	// prefixes are on.
+	WorkspaceKey(workspaceID uuid.UUID, key string) string
+}
+
+// Options shape the keys objects are stored under.
+type Options struct {
+	// KeyPrefix is put in front of every key, e.g. "prod/" to share a
+	// bucket between deployments.
+	KeyPrefix string
+	// WorkspacePrefixes stores each workspace's assets under
+	// "workspaces/<id>/", so they can be lifecycle-managed and deleted
+	// together.
+	WorkspacePrefixes bool
+}
+
+// WorkspacePrefix is the prefix a workspace's assets are stored under when
+// workspace prefixes are on.
+func WorkspacePrefix(workspaceID uuid.UUID) string {
+	return "workspaces/" + workspaceID.String() + "/"
+}
+
+// keyspace maps the keys callers use to the keys objects are stored under.
+type keyspace struct {
+	prefix            string
+	workspacePrefixes bool
+}
+
+func newKeyspace(opts Options) keyspace {
+	prefix := strings.Trim(opts.KeyPrefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return keyspace{prefix: prefix, workspacePrefixes: opts.WorkspacePrefixes}
+}
+
+// full returns the stored key for key.
+func (k keyspace) full(key string) string {
+	return k.prefix + key
+}
+
+func (k keyspace) WorkspaceKey(workspaceID uuid.UUID, key string) string {
+	if !k.workspacePrefixes {
+		return key
+	}
+	return WorkspacePrefix(workspaceID) + key
 }
@@ -0,0 +1,18 @@
+-- name: CreateAnalyticsShareToken :one
+INSERT INTO analytics_share_tokens (link_id, workspace_id, range_start, range_end, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetAnalyticsShareToken :one
+SELECT * FROM analytics_share_tokens
+WHERE id = $1;
+
+-- name: ListAnalyticsShareTokensForLink :many
+SELECT * FROM analytics_share_tokens
+WHERE link_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+ORDER BY created_at DESC;
+
+-- name: RevokeAnalyticsShareToken :exec
+UPDATE analytics_share_tokens
+SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL;
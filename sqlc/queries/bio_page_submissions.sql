@@ -0,0 +1,19 @@
+-- name: CreateBioPageSubmission :exec
+INSERT INTO bio_page_submissions (bio_page_id, block_id, email, name, ip_hash)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (bio_page_id, email) DO NOTHING;
+
+-- name: ListBioPageSubmissions :many
+SELECT * FROM bio_page_submissions
+WHERE bio_page_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountBioPageSubmissions :one
+SELECT COUNT(*) FROM bio_page_submissions
+WHERE bio_page_id = $1;
+
+-- name: ListBioPageSubmissionsForExport :many
+SELECT * FROM bio_page_submissions
+WHERE bio_page_id = $1
+ORDER BY created_at ASC;
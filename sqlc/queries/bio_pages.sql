@@ -51,8 +51,8 @@ WHERE workspace_id = $1 AND deleted_at IS NULL;
 -- ============================================================================
 
 -- name: CreateBioPageLink :one
-INSERT INTO bio_page_links (bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO bio_page_links (bio_page_id, title, url, icon, position, is_visible, visible_from, visible_until, link_type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: GetBioPageLinkByID :one
@@ -73,6 +73,7 @@ SET
     is_visible = COALESCE(sqlc.narg('is_visible'), is_visible),
     visible_from = COALESCE(sqlc.narg('visible_from'), visible_from),
     visible_until = COALESCE(sqlc.narg('visible_until'), visible_until),
+    link_type = COALESCE(sqlc.narg('link_type'), link_type),
     updated_at = NOW()
 WHERE id = $1
 RETURNING *;
@@ -94,3 +95,7 @@ WHERE id = $1;
 -- name: GetMaxBioPageLinkPosition :one
 SELECT COALESCE(MAX(position), -1)::integer AS max_position FROM bio_page_links
 WHERE bio_page_id = $1;
+
+-- name: GetBioPageLinkCount :one
+SELECT COUNT(*) AS count FROM bio_page_links
+WHERE bio_page_id = $1;
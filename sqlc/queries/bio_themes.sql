@@ -0,0 +1,32 @@
+-- name: CreateBioTheme :one
+INSERT INTO bio_themes (workspace_id, name, description, styles)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetBioThemeByID :one
+SELECT * FROM bio_themes
+WHERE id = $1;
+
+-- name: ListBioThemesForWorkspace :many
+SELECT * FROM bio_themes
+WHERE workspace_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateBioTheme :one
+UPDATE bio_themes
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    description = COALESCE(sqlc.narg('description'), description),
+    styles = COALESCE(sqlc.narg('styles'), styles),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBioTheme :exec
+DELETE FROM bio_themes
+WHERE id = $1;
+
+-- name: ClearBioPageTheme :exec
+UPDATE bio_pages
+SET theme_id = NULL, updated_at = NOW()
+WHERE theme_id = $1;
@@ -27,6 +27,8 @@ SET
     last_dns_check_at = COALESCE(sqlc.narg('last_dns_check_at'), last_dns_check_at),
     default_redirect_url = COALESCE(sqlc.narg('default_redirect_url'), default_redirect_url),
     custom_404_url = COALESCE(sqlc.narg('custom_404_url'), custom_404_url),
+    not_found_mode = COALESCE(sqlc.narg('not_found_mode'), not_found_mode),
+    not_found_status = COALESCE(sqlc.narg('not_found_status'), not_found_status),
     updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
@@ -39,3 +41,12 @@ WHERE id = $1 AND deleted_at IS NULL;
 -- name: GetDomainCountForWorkspace :one
 SELECT COUNT(*) AS count FROM domains
 WHERE workspace_id = $1 AND deleted_at IS NULL;
+
+-- name: ListWorkspacesOverDomainLimit :many
+SELECT d.workspace_id, COUNT(*) AS domain_count
+FROM domains d
+JOIN workspaces w ON w.id = d.workspace_id AND w.deleted_at IS NULL
+WHERE d.deleted_at IS NULL
+GROUP BY d.workspace_id
+HAVING COUNT(*) > sqlc.arg('max_domains')::bigint
+ORDER BY d.workspace_id;
@@ -0,0 +1,29 @@
+-- name: CreateLinkComment :one
+WITH c AS (
+    INSERT INTO link_comments (link_id, workspace_id, user_id, body)
+    VALUES ($1, $2, $3, $4)
+    RETURNING *
+)
+SELECT c.*, u.name AS author_name, u.avatar_url AS author_avatar_url
+FROM c
+LEFT JOIN users u ON u.id = c.user_id;
+
+-- name: GetLinkCommentByID :one
+SELECT * FROM link_comments
+WHERE id = $1;
+
+-- name: ListLinkComments :many
+SELECT c.*, u.name AS author_name, u.avatar_url AS author_avatar_url
+FROM link_comments c
+LEFT JOIN users u ON u.id = c.user_id
+WHERE c.link_id = $1
+ORDER BY c.created_at, c.id
+LIMIT $2 OFFSET $3;
+
+-- name: CountLinkComments :one
+SELECT COUNT(*) FROM link_comments
+WHERE link_id = $1;
+
+-- name: DeleteLinkComment :exec
+DELETE FROM link_comments
+WHERE id = $1;
@@ -0,0 +1,32 @@
+-- name: UpsertLinkFlag :one
+INSERT INTO link_flags (link_id, workspace_id, url, source, reason)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (link_id) DO UPDATE
+SET url = EXCLUDED.url,
+    source = EXCLUDED.source,
+    reason = EXCLUDED.reason,
+    status = 'pending',
+    reviewed_by = NULL,
+    reviewed_at = NULL,
+    created_at = NOW()
+RETURNING *;
+
+-- name: GetLinkFlag :one
+SELECT * FROM link_flags
+WHERE link_id = $1;
+
+-- name: ListLinkFlagsByStatus :many
+SELECT * FROM link_flags
+WHERE status = $1
+ORDER BY created_at ASC
+LIMIT $2 OFFSET $3;
+
+-- name: CountLinkFlagsByStatus :one
+SELECT COUNT(*) FROM link_flags
+WHERE status = $1;
+
+-- name: ReviewLinkFlag :one
+UPDATE link_flags
+SET status = $2, reviewed_by = $3, reviewed_at = NOW()
+WHERE link_id = $1 AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,24 @@
+-- name: UpsertLinkReport :one
+INSERT INTO link_reports (link_id, workspace_id, reason, details, reporter_email, reporter_hash)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (link_id, reporter_hash) DO UPDATE
+SET reason = EXCLUDED.reason,
+    details = EXCLUDED.details,
+    reporter_email = EXCLUDED.reporter_email,
+    created_at = NOW()
+RETURNING *;
+
+-- name: CountLinkReportsSinceReview :one
+-- Reports filed since the link's flag was last approved, or all of them if
+-- it never was.
+SELECT COUNT(*) FROM link_reports r
+WHERE r.link_id = $1
+  AND r.created_at > COALESCE(
+    (SELECT f.reviewed_at FROM link_flags f WHERE f.link_id = r.link_id AND f.status = 'approved'),
+    '-infinity'::timestamptz
+  );
+
+-- name: ListLinkReportsForLink :many
+SELECT * FROM link_reports
+WHERE link_id = $1
+ORDER BY created_at DESC;
@@ -28,3 +28,8 @@ DELETE FROM link_rules WHERE id = $1;
 
 -- name: GetLinkRuleByID :one
 SELECT * FROM link_rules WHERE id = $1;
+
+-- name: ListRulesForLink :many
+SELECT * FROM link_rules
+WHERE link_id = $1
+ORDER BY priority ASC, created_at ASC;
@@ -0,0 +1,31 @@
+-- name: CreateLinkTemplate :one
+INSERT INTO link_templates (workspace_id, created_by, name, description, settings, password_hash)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetLinkTemplateByID :one
+SELECT * FROM link_templates
+WHERE id = $1;
+
+-- name: ListLinkTemplatesForWorkspace :many
+SELECT * FROM link_templates
+WHERE workspace_id = $1
+ORDER BY name;
+
+-- name: CountLinkTemplatesForWorkspace :one
+SELECT COUNT(*) FROM link_templates
+WHERE workspace_id = $1;
+
+-- name: UpdateLinkTemplate :one
+UPDATE link_templates
+SET name = $2,
+    description = $3,
+    settings = $4,
+    password_hash = $5,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteLinkTemplate :exec
+DELETE FROM link_templates
+WHERE id = $1;
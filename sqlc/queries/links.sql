@@ -3,9 +3,13 @@ INSERT INTO links (
     user_id, workspace_id, domain_id, url, short_code,
     title, description, is_active, password_hash,
     expires_at, max_clicks,
-    utm_source, utm_medium, utm_campaign, utm_term, utm_content
+    utm_source, utm_medium, utm_campaign, utm_term, utm_content, cloak,
+    forward_params, param_precedence, internal_note, force_https,
+    once_per_visitor, repeat_visit_url, ios_url, android_url, fallback_url,
+    facebook_pixel_id, google_tag_id, interstitial, schedule, password_hint,
+    failover_urls
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)
 RETURNING *;
 
 -- name: GetLinkByID :one
@@ -26,6 +30,7 @@ WHERE l.workspace_id = $1
     AND (sqlc.narg('search')::text IS NULL OR
          to_tsvector('english', COALESCE(l.title, '') || ' ' || COALESCE(l.description, '')) @@
          plainto_tsquery('english', sqlc.narg('search')::text))
+    AND (sqlc.narg('archived')::boolean IS NULL OR (l.archived_at IS NOT NULL) = sqlc.narg('archived')::boolean)
 ORDER BY l.created_at DESC
 LIMIT $2 OFFSET $3;
 
@@ -39,6 +44,33 @@ SET
     password_hash = COALESCE(sqlc.narg('password_hash'), password_hash),
     expires_at = COALESCE(sqlc.narg('expires_at'), expires_at),
     max_clicks = COALESCE(sqlc.narg('max_clicks'), max_clicks),
+    cloak = COALESCE(sqlc.narg('cloak'), cloak),
+    forward_params = COALESCE(sqlc.narg('forward_params'), forward_params),
+    param_precedence = COALESCE(sqlc.narg('param_precedence'), param_precedence),
+    internal_note = COALESCE(sqlc.narg('internal_note'), internal_note),
+    force_https = COALESCE(sqlc.narg('force_https'), force_https),
+    once_per_visitor = COALESCE(sqlc.narg('once_per_visitor'), once_per_visitor),
+    repeat_visit_url = COALESCE(sqlc.narg('repeat_visit_url'), repeat_visit_url),
+    ios_url = COALESCE(sqlc.narg('ios_url'), ios_url),
+    android_url = COALESCE(sqlc.narg('android_url'), android_url),
+    fallback_url = COALESCE(sqlc.narg('fallback_url'), fallback_url),
+    facebook_pixel_id = COALESCE(sqlc.narg('facebook_pixel_id'), facebook_pixel_id),
+    google_tag_id = COALESCE(sqlc.narg('google_tag_id'), google_tag_id),
+    interstitial = COALESCE(sqlc.narg('interstitial'), interstitial),
+    schedule = COALESCE(sqlc.narg('schedule'), schedule),
+    password_hint = COALESCE(sqlc.narg('password_hint'), password_hint),
+    failover_urls = COALESCE(sqlc.narg('failover_urls'), failover_urls),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateLinkMetadata :one
+UPDATE links
+SET
+    title = COALESCE(sqlc.narg('title'), title),
+    description = COALESCE(sqlc.narg('description'), description),
+    favicon_url = COALESCE(sqlc.narg('favicon_url'), favicon_url),
+    og_image_url = COALESCE(sqlc.narg('og_image_url'), og_image_url),
     updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
 RETURNING *;
@@ -48,11 +80,21 @@ UPDATE links
 SET deleted_at = NOW(), updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL;
 
+-- name: SoftDeleteWorkspaceLinks :execrows
+UPDATE links
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE workspace_id = $1 AND deleted_at IS NULL;
+
 -- name: IncrementLinkClicks :exec
 UPDATE links
 SET total_clicks = total_clicks + 1, updated_at = NOW()
 WHERE id = $1;
 
+-- name: IncrementLinkClicksBy :exec
+UPDATE links
+SET total_clicks = total_clicks + sqlc.arg('count')::bigint, updated_at = NOW()
+WHERE id = sqlc.arg('id');
+
 -- name: GetLinkByURL :one
 SELECT * FROM links
 WHERE url = $1 AND workspace_id = $2 AND deleted_at IS NULL;
@@ -63,9 +105,25 @@ SELECT EXISTS(
     WHERE short_code = $1 AND deleted_at IS NULL
 ) AS exists;
 
--- name: GetLinkCountForWorkspace :one
+-- name: ShortCodeExistsIgnoreCase :one
+SELECT EXISTS(
+    SELECT 1 FROM links
+    WHERE lower(short_code) = lower($1) AND deleted_at IS NULL
+) AS exists;
+
+-- name: CountLinksCreatedForWorkspace :one
+-- Links count even when deleted since, except reservations released
+-- unclaimed, as in GetWorkspaceUsage.
 SELECT COUNT(*) AS count FROM links
-WHERE workspace_id = $1 AND deleted_at IS NULL;
+WHERE workspace_id = sqlc.arg('workspace_id')
+    AND created_at >= sqlc.arg('period_start')::timestamptz
+    AND created_at < sqlc.arg('period_end')::timestamptz
+    AND NOT (reserved_until IS NOT NULL AND deleted_at IS NOT NULL);
+
+-- name: LockWorkspaceLinkLimit :exec
+-- Held until the transaction ends, so that link limit checks and the
+-- inserts they allow don't interleave.
+SELECT pg_advisory_xact_lock(hashtextextended('link_limit:' || sqlc.arg('workspace_id')::text, 0));
 
 -- name: GetLinkQuickStats :one
 SELECT
@@ -81,3 +139,122 @@ WHERE l.id = $1 AND l.deleted_at IS NULL;
 UPDATE links
 SET unique_clicks = unique_clicks + 1, updated_at = NOW()
 WHERE id = $1;
+
+-- name: ReconcileLinkUniqueClicks :execrows
+UPDATE links l
+SET unique_clicks = (
+    SELECT COUNT(*) FROM clicks c
+    WHERE c.link_id = l.id
+        AND NOT c.is_bot
+        AND NOT EXISTS (
+            SELECT 1 FROM clicks p
+            WHERE p.link_id = c.link_id
+                AND NOT p.is_bot
+                AND COALESCE(p.visitor_id, host(p.ip_address)) = COALESCE(c.visitor_id, host(c.ip_address))
+                AND p.clicked_at < c.clicked_at
+                AND p.clicked_at >= c.clicked_at - make_interval(secs => sqlc.arg('window_seconds')::float8)
+        )
+), updated_at = NOW()
+WHERE l.deleted_at IS NULL
+    AND l.id IN (SELECT DISTINCT link_id FROM clicks WHERE clicked_at >= sqlc.arg('since'));
+
+-- name: ArchiveLink :one
+UPDATE links
+SET
+    archived_at = NOW(),
+    is_active = CASE WHEN sqlc.arg('deactivate')::boolean THEN FALSE ELSE is_active END,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UnarchiveLink :one
+UPDATE links
+SET archived_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: TransferLink :one
+UPDATE links
+SET
+    workspace_id = $2,
+    domain_id = $3,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListLinkIDsByTag :many
+SELECT lt.link_id FROM link_tags lt
+JOIN links l ON l.id = lt.link_id
+WHERE lt.tag_id = $1 AND l.workspace_id = $2 AND l.deleted_at IS NULL;
+
+-- name: AddLinkTag :exec
+INSERT INTO link_tags (link_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: CountWorkspaceTags :one
+SELECT COUNT(*) FROM tags
+WHERE workspace_id = $1 AND id = ANY(sqlc.arg('tag_ids')::uuid[]);
+
+-- name: UpsertTag :one
+INSERT INTO tags (workspace_id, name)
+VALUES ($1, $2)
+ON CONFLICT (workspace_id, name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id;
+
+-- name: ListMostClickedLinks :many
+SELECT * FROM links
+WHERE deleted_at IS NULL
+    AND is_active = true
+    AND archived_at IS NULL
+    AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY total_clicks DESC
+LIMIT $1;
+
+-- name: ListLinksWithFailovers :many
+SELECT * FROM links
+WHERE deleted_at IS NULL
+    AND is_active = true
+    AND archived_at IS NULL
+    AND cardinality(failover_urls) > 0
+    AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: ReserveShortCode :one
+INSERT INTO links (user_id, workspace_id, url, short_code, is_active, reserved_until)
+VALUES ($1, $2, '', $3, FALSE, $4)
+RETURNING *;
+
+-- name: GetShortCodeReservation :one
+SELECT * FROM links
+WHERE workspace_id = $1 AND short_code = $2
+    AND reserved_until > NOW() AND deleted_at IS NULL;
+
+-- name: ClaimReservedLink :one
+UPDATE links
+SET
+    user_id = $1, domain_id = $3, url = $4,
+    title = $6, description = $7, is_active = $8, password_hash = $9,
+    expires_at = $10, max_clicks = $11,
+    utm_source = $12, utm_medium = $13, utm_campaign = $14, utm_term = $15, utm_content = $16, cloak = $17,
+    forward_params = $18, param_precedence = $19, internal_note = $20, force_https = $21,
+    once_per_visitor = $22, repeat_visit_url = $23, ios_url = $24, android_url = $25, fallback_url = $26,
+    facebook_pixel_id = $27, google_tag_id = $28, interstitial = $29, schedule = $30,
+    password_hint = $31, failover_urls = $32,
+    reserved_until = NULL,
+    created_at = NOW(),
+    updated_at = NOW()
+WHERE workspace_id = $2 AND short_code = $5
+    AND reserved_until > NOW() AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ReleaseExpiredReservation :exec
+UPDATE links
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE short_code = $1 AND reserved_until <= NOW() AND deleted_at IS NULL;
+
+-- name: ReleaseExpiredReservations :execrows
+UPDATE links
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE reserved_until <= NOW() AND deleted_at IS NULL;
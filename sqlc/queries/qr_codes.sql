@@ -36,6 +36,12 @@ SELECT * FROM qr_codes
 WHERE link_id = $1
 ORDER BY created_at DESC;
 
+-- name: ListQRCodesForWorkspace :many
+SELECT q.* FROM qr_codes q
+JOIN links l ON l.id = q.link_id
+WHERE l.workspace_id = $1
+ORDER BY q.created_at;
+
 -- name: UpdateQRCode :one
 UPDATE qr_codes SET
     qr_type = COALESCE(sqlc.narg('qr_type'), qr_type),
@@ -0,0 +1,53 @@
+-- name: CreateScheduledReport :one
+INSERT INTO scheduled_reports (
+    workspace_id, created_by, name, schedule, timezone, recipients, scope, is_active, next_run_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetScheduledReportByID :one
+SELECT * FROM scheduled_reports
+WHERE id = $1;
+
+-- name: ListScheduledReportsForWorkspace :many
+SELECT * FROM scheduled_reports
+WHERE workspace_id = $1
+ORDER BY created_at DESC;
+
+-- name: CountScheduledReportsForWorkspace :one
+SELECT COUNT(*) FROM scheduled_reports
+WHERE workspace_id = $1;
+
+-- name: UpdateScheduledReport :one
+UPDATE scheduled_reports
+SET name = $2,
+    schedule = $3,
+    timezone = $4,
+    recipients = $5,
+    scope = $6,
+    is_active = $7,
+    next_run_at = $8,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteScheduledReport :exec
+DELETE FROM scheduled_reports
+WHERE id = $1;
+
+-- name: ListDueScheduledReports :many
+-- Reports of deleted workspaces are skipped.
+SELECT r.* FROM scheduled_reports r
+JOIN workspaces w ON w.id = r.workspace_id
+WHERE r.is_active = TRUE
+  AND r.next_run_at <= sqlc.arg('now')::timestamptz
+  AND w.deleted_at IS NULL
+ORDER BY r.next_run_at
+LIMIT sqlc.arg('limit');
+
+-- name: RecordScheduledReportRun :exec
+UPDATE scheduled_reports
+SET last_run_at = $2,
+    next_run_at = $3,
+    last_error = $4
+WHERE id = $1;
@@ -9,6 +9,12 @@ WHERE refresh_token_hash = $1
     AND is_revoked = FALSE
     AND expires_at > NOW();
 
+-- name: GetActiveSessionByID :one
+SELECT * FROM sessions
+WHERE id = $1
+    AND is_revoked = FALSE
+    AND expires_at > NOW();
+
 -- name: RevokeSession :exec
 UPDATE sessions
 SET is_revoked = TRUE
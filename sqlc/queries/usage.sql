@@ -0,0 +1,50 @@
+-- name: GetWorkspaceUsage :one
+-- Links count even when deleted since, except reservations released
+-- unclaimed, and clicks exclude bots.
+SELECT
+    (SELECT COUNT(*) FROM links l
+     WHERE l.workspace_id = sqlc.arg('workspace_id')
+       AND l.created_at >= sqlc.arg('period_start')::timestamptz
+       AND l.created_at < sqlc.arg('period_end')::timestamptz
+       AND NOT (l.reserved_until IS NOT NULL AND l.deleted_at IS NOT NULL))::bigint AS links_created,
+    (SELECT COUNT(*) FROM clicks c
+     JOIN links l ON l.id = c.link_id
+     WHERE l.workspace_id = sqlc.arg('workspace_id')
+       AND c.clicked_at >= sqlc.arg('period_start')::timestamptz
+       AND c.clicked_at < sqlc.arg('period_end')::timestamptz
+       AND NOT c.is_bot)::bigint AS clicks_tracked,
+    (SELECT COUNT(*) FROM qr_codes q
+     JOIN links l ON l.id = q.link_id
+     WHERE l.workspace_id = sqlc.arg('workspace_id')
+       AND q.created_at >= sqlc.arg('period_start')::timestamptz
+       AND q.created_at < sqlc.arg('period_end')::timestamptz)::bigint AS qr_codes_generated,
+    (SELECT COUNT(*) FROM webhook_deliveries d
+     JOIN webhooks w ON w.id = d.webhook_id
+     WHERE w.workspace_id = sqlc.arg('workspace_id')
+       AND d.created_at >= sqlc.arg('period_start')::timestamptz
+       AND d.created_at < sqlc.arg('period_end')::timestamptz)::bigint AS webhook_deliveries;
+
+-- name: ListActiveWorkspaceIDs :many
+SELECT id FROM workspaces
+WHERE deleted_at IS NULL
+ORDER BY created_at;
+
+-- name: UpsertUsageSnapshot :exec
+INSERT INTO usage_snapshots (
+    workspace_id, period_start, tier,
+    links_created, clicks_tracked, qr_codes_generated, webhook_deliveries
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (workspace_id, period_start) DO UPDATE
+SET tier = EXCLUDED.tier,
+    links_created = EXCLUDED.links_created,
+    clicks_tracked = EXCLUDED.clicks_tracked,
+    qr_codes_generated = EXCLUDED.qr_codes_generated,
+    webhook_deliveries = EXCLUDED.webhook_deliveries,
+    captured_at = NOW();
+
+-- name: ListUsageSnapshots :many
+SELECT * FROM usage_snapshots
+WHERE workspace_id = $1
+ORDER BY period_start DESC
+LIMIT $2;
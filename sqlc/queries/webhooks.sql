@@ -1,6 +1,6 @@
 -- name: CreateWebhook :one
-INSERT INTO webhooks (workspace_id, url, secret, events, is_active)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO webhooks (workspace_id, url, secret, events, is_active, payload_version)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetWebhookByID :one
@@ -21,6 +21,15 @@ SET url = COALESCE($2, url),
 WHERE id = $1
 RETURNING *;
 
+-- name: RotateWebhookSecret :one
+UPDATE webhooks
+SET previous_secret = secret,
+    previous_secret_expires_at = $3,
+    secret = $2,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
 -- name: DeleteWebhook :exec
 DELETE FROM webhooks
 WHERE id = $1;
@@ -76,14 +85,23 @@ SET response_status = $2,
     response_body = $3,
     attempts = $4,
     last_attempt_at = NOW(),
-    completed_at = $5
+    completed_at = $5,
+    next_retry_at = $6
+WHERE id = $1;
+
+-- name: RequeueWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET attempts = 0,
+    last_attempt_at = NULL,
+    completed_at = NULL,
+    next_retry_at = NULL
 WHERE id = $1;
 
 -- name: GetPendingWebhookDeliveries :many
 SELECT * FROM webhook_deliveries
 WHERE completed_at IS NULL
   AND attempts < max_attempts
-  AND (last_attempt_at IS NULL OR last_attempt_at < NOW() - INTERVAL '30 seconds')
+  AND COALESCE(next_retry_at, last_attempt_at + INTERVAL '30 seconds', NOW()) <= NOW()
 ORDER BY created_at ASC
 LIMIT 50;
 
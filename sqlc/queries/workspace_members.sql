@@ -26,3 +26,11 @@ WHERE workspace_id = $1 AND user_id = $2;
 
 -- name: GetMemberCountForWorkspace :one
 SELECT COUNT(*) FROM workspace_members WHERE workspace_id = $1;
+
+-- name: ListWorkspacesOverMemberLimit :many
+SELECT wm.workspace_id, COUNT(*) AS member_count
+FROM workspace_members wm
+JOIN workspaces w ON w.id = wm.workspace_id AND w.deleted_at IS NULL
+GROUP BY wm.workspace_id
+HAVING COUNT(*) > sqlc.arg('max_members')::bigint
+ORDER BY wm.workspace_id;
@@ -38,6 +38,17 @@ SELECT COUNT(*) FROM workspaces w
 JOIN workspace_members wm ON wm.workspace_id = w.id
 WHERE wm.user_id = $1 AND wm.role = 'owner' AND w.deleted_at IS NULL;
 
+-- name: ListWorkspacesPendingCleanup :many
+SELECT id FROM workspaces
+WHERE deleted_at IS NOT NULL AND assets_cleaned_at IS NULL
+ORDER BY deleted_at
+LIMIT $1;
+
+-- name: MarkWorkspaceAssetsCleaned :exec
+UPDATE workspaces
+SET assets_cleaned_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
 -- name: UpdateWorkspaceOwner :one
 UPDATE workspaces
 SET owner_id = $2, updated_at = NOW()
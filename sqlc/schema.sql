@@ -35,11 +35,15 @@ CREATE TABLE workspaces (
     settings JSONB NOT NULL DEFAULT '{}',
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    deleted_at TIMESTAMPTZ
+    deleted_at TIMESTAMPTZ,
+    -- set once the cleanup of a deleted workspace's resources has finished
+    assets_cleaned_at TIMESTAMPTZ
 );
 
 CREATE UNIQUE INDEX idx_workspaces_slug ON workspaces(slug) WHERE deleted_at IS NULL;
 CREATE INDEX idx_workspaces_owner ON workspaces(owner_id);
+CREATE INDEX idx_workspaces_pending_cleanup ON workspaces(deleted_at)
+    WHERE deleted_at IS NOT NULL AND assets_cleaned_at IS NULL;
 
 -- ============================================================================
 -- 3. workspace_members
@@ -76,7 +80,10 @@ CREATE TABLE domains (
     custom_404_url TEXT,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    deleted_at TIMESTAMPTZ
+    deleted_at TIMESTAMPTZ,
+    -- page, redirect or status; NULL follows the instance default
+    not_found_mode VARCHAR(20),
+    not_found_status INTEGER
 );
 
 CREATE UNIQUE INDEX idx_domains_domain ON domains(domain) WHERE deleted_at IS NULL;
@@ -119,14 +126,66 @@ CREATE TABLE links (
     -- Timestamps
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    deleted_at TIMESTAMPTZ
+    deleted_at TIMESTAMPTZ,
+
+    -- Serve the destination in a full-page iframe instead of redirecting
+    cloak BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Forward incoming query parameters onto the destination
+    forward_params BOOLEAN NOT NULL DEFAULT FALSE,
+    param_precedence VARCHAR(20) NOT NULL DEFAULT 'destination',
+
+    -- Note for workspace members; never served by the redirect service
+    internal_note TEXT,
+
+    -- Archived links are hidden from the default list but keep redirecting
+    -- unless deactivated
+    archived_at TIMESTAMPTZ,
+
+    -- Upgrade http:// destinations to https:// when redirecting
+    force_https BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Each visitor may follow the link once; repeat visits go to
+    -- repeat_visit_url, or an "already used" page when it is empty
+    once_per_visitor BOOLEAN NOT NULL DEFAULT FALSE,
+    repeat_visit_url TEXT,
+
+    -- App URIs tried on iOS and Android before falling back to fallback_url,
+    -- or url when it is empty; desktop visitors go straight to the fallback
+    ios_url TEXT,
+    android_url TEXT,
+    fallback_url TEXT,
+
+    -- Retargeting pixels fired on an interstitial page before redirecting
+    facebook_pixel_id TEXT,
+    google_tag_id TEXT,
+
+    -- Show a "you are leaving" interstitial before redirecting
+    interstitial BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Recurring weekly windows the link is live in; see models.LinkSchedule
+    schedule JSONB,
+
+    -- Set while the link only reserves its short code; the code is freed
+    -- once it passes unless a create in the workspace claims it first
+    reserved_until TIMESTAMPTZ,
+
+    -- Shown on the password form of password-protected links
+    password_hint TEXT,
+
+    -- Backup destinations, in order, for when health checks find the
+    -- destination down; NULL or empty means none
+    failover_urls TEXT[]
 );
 
 CREATE UNIQUE INDEX idx_links_short_code ON links(short_code) WHERE deleted_at IS NULL;
+CREATE INDEX idx_links_short_code_lower ON links(lower(short_code)) WHERE deleted_at IS NULL;
 CREATE INDEX idx_links_user ON links(user_id) WHERE deleted_at IS NULL;
 CREATE INDEX idx_links_workspace ON links(workspace_id) WHERE deleted_at IS NULL;
 CREATE INDEX idx_links_domain ON links(domain_id) WHERE deleted_at IS NULL;
 CREATE INDEX idx_links_created_at ON links(created_at DESC);
+CREATE INDEX idx_links_reserved_until ON links(reserved_until)
+    WHERE reserved_until IS NOT NULL AND deleted_at IS NULL;
 CREATE INDEX idx_links_search ON links USING GIN (to_tsvector('english', COALESCE(title, '') || ' ' || COALESCE(description, '')));
 
 -- ============================================================================
@@ -199,6 +258,9 @@ CREATE TABLE clicks (
     utm_medium VARCHAR(255),
     utm_campaign VARCHAR(255),
 
+    -- 'qr' for clicks through a dynamic QR code, NULL otherwise
+    source VARCHAR(20),
+
     PRIMARY KEY (id, clicked_at)
 ) PARTITION BY RANGE (clicked_at);
 
@@ -259,7 +321,7 @@ CREATE TABLE bio_pages (
     title VARCHAR(255) NOT NULL,
     bio TEXT,
     avatar_url VARCHAR(500),
-    theme_id UUID, -- FK to bio_page_themes added in future migration
+    theme_id UUID, -- built-in theme or bio_themes(id); no FK
     custom_css TEXT,
     meta_title VARCHAR(100),
     meta_description VARCHAR(300),
@@ -288,7 +350,10 @@ CREATE TABLE bio_page_links (
     visible_until TIMESTAMPTZ,
     click_count BIGINT NOT NULL DEFAULT 0,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- 'regular', 'social' (platform and icon derived from the URL) or
+    -- 'email_capture' (a signup form; url is empty)
+    link_type VARCHAR(20) NOT NULL DEFAULT 'regular'
 );
 
 CREATE INDEX idx_bio_page_links_page ON bio_page_links(bio_page_id, position);
@@ -330,7 +395,10 @@ CREATE TABLE webhooks (
     last_triggered_at TIMESTAMPTZ,
     last_success_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    payload_version INTEGER,
+    previous_secret VARCHAR(255),
+    previous_secret_expires_at TIMESTAMPTZ
 );
 
 CREATE INDEX idx_webhooks_workspace ON webhooks(workspace_id);
@@ -433,3 +501,120 @@ CREATE TABLE subscriptions (
 
 CREATE INDEX idx_subscriptions_workspace ON subscriptions(workspace_id);
 CREATE INDEX idx_subscriptions_stripe ON subscriptions(stripe_subscription_id);
+
+-- ============================================================================
+-- 20. analytics_share_tokens
+-- ============================================================================
+CREATE TABLE analytics_share_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    range_start TIMESTAMPTZ NOT NULL,
+    range_end TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_analytics_share_tokens_link ON analytics_share_tokens(link_id);
+
+-- ============================================================================
+-- 21. bio_themes
+-- ============================================================================
+CREATE TABLE bio_themes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    styles JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_bio_themes_workspace ON bio_themes(workspace_id);
+
+-- ============================================================================
+-- 22. bio_page_submissions
+-- ============================================================================
+CREATE TABLE bio_page_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bio_page_id UUID NOT NULL REFERENCES bio_pages(id) ON DELETE CASCADE,
+    block_id UUID REFERENCES bio_page_links(id) ON DELETE SET NULL,
+    email VARCHAR(254) NOT NULL,
+    name VARCHAR(100),
+    ip_hash VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_bio_page_submissions_email ON bio_page_submissions(bio_page_id, email);
+CREATE INDEX idx_bio_page_submissions_page ON bio_page_submissions(bio_page_id, created_at DESC);
+
+-- ============================================================================
+-- 23. usage_snapshots
+-- ============================================================================
+CREATE TABLE usage_snapshots (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    tier VARCHAR(50) NOT NULL,
+    links_created BIGINT NOT NULL DEFAULT 0,
+    clicks_tracked BIGINT NOT NULL DEFAULT 0,
+    qr_codes_generated BIGINT NOT NULL DEFAULT 0,
+    webhook_deliveries BIGINT NOT NULL DEFAULT 0,
+    captured_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (workspace_id, period_start)
+);
+
+-- ============================================================================
+-- 24. scheduled_reports
+-- ============================================================================
+CREATE TABLE scheduled_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    schedule VARCHAR(100) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    recipients TEXT[] NOT NULL,
+    scope JSONB NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_scheduled_reports_workspace ON scheduled_reports(workspace_id);
+CREATE INDEX idx_scheduled_reports_due ON scheduled_reports(next_run_at) WHERE is_active;
+
+-- ============================================================================
+-- 25. link_comments
+-- ============================================================================
+CREATE TABLE link_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL REFERENCES links(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_link_comments_link ON link_comments(link_id, created_at);
+
+-- ============================================================================
+-- 26. link_templates
+-- ============================================================================
+CREATE TABLE link_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    settings JSONB NOT NULL DEFAULT '{}',
+    password_hash VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_link_templates_workspace_name ON link_templates(workspace_id, LOWER(name));
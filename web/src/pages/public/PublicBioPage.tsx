@@ -1,7 +1,8 @@
+import { useState } from "react"
 import { useParams } from "react-router-dom"
 import { usePublicBioPage } from "@/hooks/useBioPages"
-import { trackBioLinkClick } from "@/services/biopages"
-import type { PublicBioPage as PublicBioPageType, ThemeStyles } from "@/types/biopage"
+import { submitBioEmail, trackBioLinkClick } from "@/services/biopages"
+import type { PublicBioLink, PublicBioPage as PublicBioPageType, ThemeStyles } from "@/types/biopage"
 
 export default function PublicBioPage() {
   const { slug } = useParams<{ slug: string }>()
@@ -67,22 +68,26 @@ function BioPageRenderer({ page }: { page: PublicBioPageType }) {
           </p>
         )}
         <div className="space-y-3">
-          {page.links.map((link) => (
-            <button
-              key={link.id}
-              onClick={() => handleLinkClick(link.id, link.url)}
-              className="w-full px-4 py-3 text-center font-medium transition-transform hover:scale-[1.02] active:scale-[0.98]"
-              style={{
-                backgroundColor: styles?.button_color || "#1a1a1a",
-                color: styles?.button_text_color || "#ffffff",
-                borderRadius: styles?.button_style === "pill" ? "9999px" : "0.5rem",
-                fontFamily: styles?.font_family,
-              }}
-            >
-              {link.icon && <span className="mr-2">{link.icon}</span>}
-              {link.title}
-            </button>
-          ))}
+          {page.links.map((link) =>
+            link.link_type === "email_capture" ? (
+              <EmailCaptureBlock key={link.id} slug={page.slug} block={link} styles={styles} />
+            ) : (
+              <button
+                key={link.id}
+                onClick={() => handleLinkClick(link.id, link.url)}
+                className="w-full px-4 py-3 text-center font-medium transition-transform hover:scale-[1.02] active:scale-[0.98]"
+                style={{
+                  backgroundColor: styles?.button_color || "#1a1a1a",
+                  color: styles?.button_text_color || "#ffffff",
+                  borderRadius: styles?.button_style === "pill" ? "9999px" : "0.5rem",
+                  fontFamily: styles?.font_family,
+                }}
+              >
+                {link.icon && <span className="mr-2">{link.icon}</span>}
+                {link.title}
+              </button>
+            )
+          )}
         </div>
         <div className="mt-12 text-center">
           <span className="text-xs opacity-50" style={{ color: styles?.text_color }}>
@@ -94,6 +99,88 @@ function BioPageRenderer({ page }: { page: PublicBioPageType }) {
   )
 }
 
+function EmailCaptureBlock({
+  slug,
+  block,
+  styles,
+}: {
+  slug: string
+  block: PublicBioLink
+  styles?: ThemeStyles | null
+}) {
+  const [email, setEmail] = useState("")
+  const [website, setWebsite] = useState("")
+  const [status, setStatus] = useState<"idle" | "sending" | "done">("idle")
+  const [error, setError] = useState<string | null>(null)
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setStatus("sending")
+    setError(null)
+    try {
+      await submitBioEmail(slug, { block_id: block.id, email, website })
+      setStatus("done")
+    } catch (err) {
+      setError(err instanceof Error ? err.message : "Failed to subscribe")
+      setStatus("idle")
+    }
+  }
+
+  const radius = styles?.button_style === "pill" ? "9999px" : "0.5rem"
+
+  if (status === "done") {
+    return (
+      <p className="py-3 text-center text-sm" style={{ color: styles?.text_color }}>
+        Thanks for subscribing!
+      </p>
+    )
+  }
+
+  return (
+    <form onSubmit={handleSubmit} className="space-y-2">
+      <p className="text-center text-sm font-medium" style={{ color: styles?.text_color }}>
+        {block.title}
+      </p>
+      {/* Honeypot: hidden from people, filled in by bots */}
+      <input
+        type="text"
+        name="website"
+        value={website}
+        onChange={(e) => setWebsite(e.target.value)}
+        tabIndex={-1}
+        autoComplete="off"
+        aria-hidden="true"
+        className="hidden"
+      />
+      <div className="flex gap-2">
+        <input
+          type="email"
+          required
+          value={email}
+          onChange={(e) => setEmail(e.target.value)}
+          placeholder="you@example.com"
+          className="min-w-0 flex-1 border px-4 py-3 text-sm text-black"
+          style={{ borderRadius: radius }}
+        />
+        <button
+          type="submit"
+          disabled={status === "sending"}
+          className="px-4 py-3 font-medium disabled:opacity-60"
+          style={{
+            backgroundColor: styles?.button_color || "#1a1a1a",
+            color: styles?.button_text_color || "#ffffff",
+            borderRadius: radius,
+            fontFamily: styles?.font_family,
+          }}
+        >
+          Subscribe
+        </button>
+      </div>
+      {error && <p className="text-center text-xs text-red-500">{error}</p>}
+    </form>
+  )
+}
+
 function getBackgroundStyle(styles?: ThemeStyles | null): React.CSSProperties {
   if (!styles) return { backgroundColor: "#ffffff" }
   if (styles.gradient) {
@@ -10,6 +10,8 @@ import type {
   UpdateBioPageLinkRequest,
   ReorderBioLinksRequest,
   PublicBioPage,
+  BioPageSubmission,
+  BioEmailSubmissionRequest,
 } from "@/types/biopage"
 
 function getWorkspaceId(): string {
@@ -166,6 +168,34 @@ export async function getBioPageTheme(themeId: string): Promise<BioPageTheme> {
   return res.data
 }
 
+// Email capture
+
+export async function getBioPageSubmissions(
+  pageId: string,
+  limit = 20,
+  offset = 0
+): Promise<{ data: BioPageSubmission[]; total: number }> {
+  const res = await apiRequest<BioPageSubmission[]>(
+    `${wsBase()}/${pageId}/submissions?limit=${limit}&offset=${offset}`
+  )
+  if (!res.success || !res.data) {
+    throw new Error(res.error?.message || "Failed to fetch submissions")
+  }
+  return { data: res.data, total: res.meta?.total ?? res.data.length }
+}
+
+export async function exportBioPageSubmissions(pageId: string): Promise<Blob> {
+  const token = localStorage.getItem("access_token")
+  const headers: Record<string, string> = {}
+  if (token) headers["Authorization"] = `Bearer ${token}`
+
+  const response = await fetch(`/api/v1${wsBase()}/${pageId}/submissions/export`, { headers })
+  if (!response.ok) {
+    throw new Error("Failed to export submissions")
+  }
+  return response.blob()
+}
+
 // Public (no auth)
 
 export async function getPublicBioPage(slug: string): Promise<PublicBioPage> {
@@ -184,3 +214,15 @@ export async function trackBioLinkClick(slug: string, linkId: string): Promise<v
     // Best-effort tracking
   }
 }
+
+export async function submitBioEmail(slug: string, data: BioEmailSubmissionRequest): Promise<void> {
+  const response = await fetch(`/b/${slug}/subscribe`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(data),
+  })
+  const res = await response.json()
+  if (!res.success) {
+    throw new Error(res.error?.message || "Failed to subscribe")
+  }
+}
@@ -1,5 +1,5 @@
 import { apiRequest } from "./api"
-import type { LicenseInfo, ActivateLicenseRequest } from "@/types/license"
+import type { LicenseInfo, LicenseFeatures, ActivateLicenseRequest } from "@/types/license"
 
 export async function getLicense(): Promise<LicenseInfo> {
   const res = await apiRequest<LicenseInfo>("/license")
@@ -9,6 +9,15 @@ export async function getLicense(): Promise<LicenseInfo> {
   return res.data
 }
 
+export async function getLicenseFeatures(workspaceId?: string): Promise<LicenseFeatures> {
+  const query = workspaceId ? `?workspace_id=${workspaceId}` : ""
+  const res = await apiRequest<LicenseFeatures>(`/license/features${query}`)
+  if (!res.success || !res.data) {
+    throw new Error(res.error?.message || "Failed to get license features")
+  }
+  return res.data
+}
+
 export async function activateLicense(
   data: ActivateLicenseRequest
 ): Promise<LicenseInfo> {
@@ -6,6 +6,7 @@ import type {
   UpdateLinkRequest,
   BulkCreateRequest,
   LinkQuickStats,
+  LinkDestinationCheck,
 } from "@/types/link"
 
 function getWorkspaceId(): string {
@@ -99,3 +100,14 @@ export async function getLinkStats(id: string): Promise<LinkQuickStats> {
   }
   return res.data
 }
+
+export async function validateLinkDestination(url: string): Promise<LinkDestinationCheck> {
+  const res = await apiRequest<LinkDestinationCheck>(`${wsBase()}/validate`, {
+    method: "POST",
+    body: JSON.stringify({ url }),
+  })
+  if (!res.success || !res.data) {
+    throw new Error(res.error?.message || "Failed to check destination")
+  }
+  return res.data
+}
@@ -25,6 +25,7 @@ const defaultCELicense: LicenseInfo = {
     max_clicks_per_month: 10000,
     max_workspaces: 1,
     max_api_requests_per_min: 10,
+    max_bio_links: 10,
   },
   is_community: true,
 }
@@ -17,11 +17,15 @@ export interface BioPage {
   link_count?: number
 }
 
+export type BioLinkType = "regular" | "social" | "email_capture"
+
 export interface BioPageLink {
   id: string
   bio_page_id: string
   title: string
   url: string
+  link_type: BioLinkType
+  platform?: string
   icon?: string | null
   position: number
   is_visible: boolean
@@ -37,6 +41,7 @@ export interface BioPageTheme {
   name: string
   description: string
   is_premium: boolean
+  is_custom: boolean
   styles: ThemeStyles
 }
 
@@ -82,6 +87,7 @@ export interface UpdateBioPageRequest {
 export interface CreateBioPageLinkRequest {
   title: string
   url: string
+  link_type?: BioLinkType
   icon?: string
   is_visible?: boolean
   visible_from?: string
@@ -91,6 +97,7 @@ export interface CreateBioPageLinkRequest {
 export interface UpdateBioPageLinkRequest {
   title?: string
   url?: string
+  link_type?: BioLinkType
   icon?: string
   is_visible?: boolean
   visible_from?: string
@@ -119,4 +126,22 @@ export interface PublicBioLink {
   title: string
   url: string
   icon?: string | null
+  link_type: BioLinkType
+  platform?: string
+}
+
+export interface BioPageSubmission {
+  id: string
+  bio_page_id: string
+  block_id?: string
+  email: string
+  name?: string
+  created_at: string
+}
+
+export interface BioEmailSubmissionRequest {
+  block_id: string
+  email: string
+  name?: string
+  website?: string
 }
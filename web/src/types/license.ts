@@ -30,6 +30,7 @@ export interface LicenseLimits {
   max_clicks_per_month: number
   max_workspaces: number
   max_api_requests_per_min: number
+  max_bio_links: number
 }
 
 export interface Plan {
@@ -49,6 +50,38 @@ export interface LicenseInfo {
   features: Feature[]
   limits: LicenseLimits
   is_community: boolean
+  warnings?: LimitWarning[]
+}
+
+export interface LimitWarning {
+  limit: keyof LicenseLimits
+  workspace_id?: string
+  current: number
+  max: number
+}
+
+export interface FeatureStatus {
+  feature: string
+  name: string
+  description: string
+  min_tier: Tier
+  category: string
+  available: boolean
+}
+
+export interface LicenseUsage {
+  workspace_id: string
+  links: number
+  domains: number
+  members: number
+  workspaces: number
+}
+
+export interface LicenseFeatures {
+  tier: Tier
+  features: FeatureStatus[]
+  limits: LicenseLimits
+  usage?: LicenseUsage
 }
 
 export interface ActivateLicenseRequest {
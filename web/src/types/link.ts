@@ -23,6 +23,36 @@ export interface Link {
   unique_clicks: number
   created_at: string
   updated_at: string
+  destination_check?: LinkDestinationCheck
+  flag?: LinkFlag
+}
+
+export type LinkFlagStatus = 'pending' | 'approved' | 'rejected'
+
+export interface LinkFlag {
+  link_id: string
+  workspace_id: string
+  url: string
+  source: string
+  reason: string
+  status: LinkFlagStatus
+  reviewed_by?: string
+  reviewed_at?: string
+  created_at: string
+}
+
+export interface LinkRedirectHop {
+  url: string
+  status_code: number
+}
+
+export interface LinkDestinationCheck {
+  url: string
+  reachable: boolean
+  status_code?: number
+  final_url?: string
+  redirects: LinkRedirectHop[]
+  error?: string
 }
 
 export interface CreateLinkRequest {
@@ -38,6 +68,7 @@ export interface CreateLinkRequest {
   utm_campaign?: string
   utm_term?: string
   utm_content?: string
+  check_destination?: boolean
 }
 
 export interface UpdateLinkRequest {
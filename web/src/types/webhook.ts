@@ -41,6 +41,7 @@ export const WEBHOOK_EVENTS = [
   { value: "link.deleted", label: "Link Deleted", category: "Links" },
   { value: "link.clicked", label: "Link Clicked", category: "Links" },
   { value: "link.expired", label: "Link Expired", category: "Links" },
+  { value: "link.reported", label: "Link Reported", category: "Links" },
   { value: "qr.created", label: "QR Code Created", category: "QR Codes" },
   { value: "qr.scanned", label: "QR Code Scanned", category: "QR Codes" },
   { value: "biopage.created", label: "Bio Page Created", category: "Bio Pages" },
@@ -51,4 +52,5 @@ export const WEBHOOK_EVENTS = [
   { value: "team.member_invited", label: "Member Invited", category: "Team" },
   { value: "team.member_joined", label: "Member Joined", category: "Team" },
   { value: "team.member_removed", label: "Member Removed", category: "Team" },
+  { value: "license.limit_exceeded", label: "License Limit Exceeded", category: "License" },
 ] as const